@@ -7,7 +7,10 @@ if [ ! -f "build/env.sh" ]; then
     exit 2
 fi
 
-# set gitCommit when running from a Git checkout.
+# set build metadata when running from a Git checkout. Build time is the time of commit
+# and paths are trimmed, so that builds of the same commit are reproducible.
 if [ -f ".git/HEAD" ]; then
-    echo "-ldflags '-X github.com/status-im/status-go/geth/params.UseMainnetFlag=false -X main.buildStamp=`date -u '+%Y-%m-%d.%H:%M:%S'` -X main.gitCommit=$(git rev-parse HEAD)'";
+    PKG="github.com/status-im/status-go/geth/params"
+    TRIMPATH="$PWD/build/_workspace"
+    echo "-gcflags '-trimpath=$TRIMPATH' -asmflags '-trimpath=$TRIMPATH' -ldflags '-X $PKG.UseMainnetFlag=false -X $PKG.GitCommit=$(git rev-parse HEAD) -X $PKG.BuildTime=$(git log -1 --format=%cI) -X $PKG.BuildFeatures=$BUILD_FEATURES'";
 fi
@@ -0,0 +1,221 @@
+// genflags generates CLI flags (and environment variables) for every field
+// of params.NodeConfig, along with the code applying them to the config.
+//
+// Usage:
+//
+//	genflags -out config_flags.go -skip DataDir,NetworkID path/to/geth/params/config.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	rootType     = "NodeConfig"
+	flagPrefix   = "config."
+	envVarPrefix = "STATUSD_"
+)
+
+// cliTypes maps Go types of config fields to types of CLI flags and getters.
+// Types, which CLI has no flags of, are converted from values of wider flags.
+var cliTypes = map[string]struct {
+	Flag    string
+	Getter  string
+	Convert bool // value of getter is converted to type of field
+}{
+	"bool":     {"BoolFlag", "GlobalBool", false},
+	"string":   {"StringFlag", "GlobalString", false},
+	"int":      {"IntFlag", "GlobalInt", false},
+	"uint8":    {"UintFlag", "GlobalUint", true},
+	"uint32":   {"UintFlag", "GlobalUint", true},
+	"uint64":   {"Uint64Flag", "GlobalUint64", false},
+	"float64":  {"Float64Flag", "GlobalFloat64", false},
+	"[]string": {"StringSliceFlag", "GlobalStringSlice", false},
+}
+
+// configField describes a single (possibly nested) field of the config.
+type configField struct {
+	Path  []string // field names, starting from the root config type
+	Type  string
+	Usage string
+}
+
+func (f configField) flagName() string {
+	return flagPrefix + strings.ToLower(strings.Join(f.Path, "."))
+}
+
+func (f configField) envVar() string {
+	return envVarPrefix + strings.ToUpper(strings.Join(f.Path, "_"))
+}
+
+func main() {
+	out := flag.String("out", "", "output file (stdout, if empty)")
+	skip := flag.String("skip", "", "comma-separated list of top-level fields to skip")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: genflags [-out file] [-skip fields] config.go")
+		os.Exit(2)
+	}
+
+	fields, err := parseConfigFields(flag.Arg(0), strings.Split(*skip, ","))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code, err := generate(fields)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(code) // nolint: errcheck
+		return
+	}
+	if err := ioutil.WriteFile(*out, code, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// parseConfigFields collects fields of the root config type, descending into nested config structs.
+func parseConfigFields(path string, skip []string) ([]configField, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	structs := make(map[string]*ast.StructType)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				structs[spec.Name.Name] = st
+			}
+		}
+		return true
+	})
+
+	root, ok := structs[rootType]
+	if !ok {
+		return nil, fmt.Errorf("type %s is not found in %s", rootType, path)
+	}
+
+	skipped := make(map[string]bool)
+	for _, name := range skip {
+		skipped[strings.TrimSpace(name)] = true
+	}
+
+	var fields []configField
+	var walk func(st *ast.StructType, prefix []string) error
+	walk = func(st *ast.StructType, prefix []string) error {
+		for _, field := range st.Fields.List {
+			for _, name := range field.Names {
+				if !name.IsExported() || (len(prefix) == 0 && skipped[name.Name]) {
+					continue
+				}
+				fieldPath := append(append([]string{}, prefix...), name.Name)
+
+				typeName := typeString(field.Type)
+				if nested, ok := structs[strings.TrimPrefix(typeName, "*")]; ok {
+					if err := walk(nested, fieldPath); err != nil {
+						return err
+					}
+					continue
+				}
+
+				if _, ok := cliTypes[typeName]; !ok {
+					return fmt.Errorf("field %s has unsupported type %s", strings.Join(fieldPath, "."), typeName)
+				}
+
+				fields = append(fields, configField{
+					Path:  fieldPath,
+					Type:  typeName,
+					Usage: usageString(field.Doc, name.Name),
+				})
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, nil); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// typeString returns textual representation of a field type.
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
+
+// usageString makes flag usage out of the first sentence of field's doc comment.
+func usageString(doc *ast.CommentGroup, name string) string {
+	text := strings.Join(strings.Fields(doc.Text()), " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i]
+	}
+	text = strings.TrimSuffix(text, ".")
+	text = strings.TrimPrefix(text, name+" ")
+	text = strings.TrimPrefix(text, "is ")
+	text = strings.TrimPrefix(text, "Is ")
+
+	if text == "" {
+		return name
+	}
+
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// generate produces formatted Go code of flags and the function applying them.
+func generate(fields []configField) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by genflags. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package main\n\n")
+	fmt.Fprintf(&buf, "import (\n\t\"github.com/status-im/status-go/geth/params\"\n\t\"gopkg.in/urfave/cli.v1\"\n)\n\n")
+
+	fmt.Fprintf(&buf, "// configFlags are flags for every field of params.%s\n", rootType)
+	fmt.Fprintf(&buf, "var configFlags = []cli.Flag{\n")
+	for _, f := range fields {
+		fmt.Fprintf(&buf, "\tcli.%s{\n\t\tName: %q,\n\t\tUsage: %q,\n\t\tEnvVar: %q,\n\t},\n",
+			cliTypes[f.Type].Flag, f.flagName(), f.Usage, f.envVar())
+	}
+	fmt.Fprintf(&buf, "}\n\n")
+
+	fmt.Fprintf(&buf, "// applyConfigFlags overrides config fields with values of flags (or environment variables) set\n")
+	fmt.Fprintf(&buf, "func applyConfigFlags(ctx *cli.Context, config *params.%s) {\n", rootType)
+	for _, f := range fields {
+		value := fmt.Sprintf("ctx.%s(%q)", cliTypes[f.Type].Getter, f.flagName())
+		if cliTypes[f.Type].Convert {
+			value = fmt.Sprintf("%s(%s)", f.Type, value)
+		}
+		fmt.Fprintf(&buf, "\tif isConfigFlagSet(ctx, %q, %q) {\n\t\tconfig.%s = %s\n\t}\n",
+			f.flagName(), f.envVar(), strings.Join(f.Path, "."), value)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/status-im/status-go/geth/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	// SeedFlag is a seed, mnemonics of generated accounts are derived from
+	SeedFlag = cli.StringFlag{
+		Name:   "seed",
+		Usage:  "Seed, mnemonics of generated accounts are derived from (the same seed yields the same accounts)",
+		EnvVar: "STATUSD_ACCOUNTS_SEED",
+	}
+
+	// CountFlag is a number of accounts to generate
+	CountFlag = cli.IntFlag{
+		Name:  "count",
+		Usage: "Number of accounts to generate",
+		Value: 1,
+	}
+
+	// PasswordFlag is a password generated accounts are protected with
+	PasswordFlag = cli.StringFlag{
+		Name:   "password",
+		Usage:  "Password generated accounts are protected with",
+		EnvVar: "STATUSD_ACCOUNTS_PASSWORD",
+	}
+
+	// FaucetURLFlag is URL of faucet, generated accounts are funded from
+	FaucetURLFlag = cli.StringFlag{
+		Name:   "faucet",
+		Usage:  `URL of faucet to fund generated accounts from, "{address}" is replaced with address (or address is appended)`,
+		EnvVar: "STATUSD_ACCOUNTS_FAUCET",
+	}
+
+	accountsCommand = cli.Command{
+		Name:  "accounts",
+		Usage: "Account utilities",
+		Subcommands: []cli.Command{
+			{
+				Action: accountsGenerateCommandHandler,
+				Name:   "generate",
+				Usage:  "Generate throwaway accounts with deterministic seeds (for load tests and QA environments)",
+				Flags:  []cli.Flag{SeedFlag, CountFlag, PasswordFlag, FaucetURLFlag},
+			},
+		},
+	}
+)
+
+// errors
+var (
+	ErrSeedMissing = errors.New("seed of generated accounts is expected")
+)
+
+// accountsGenerateCommandHandler handles `statusd accounts generate` command,
+// generated accounts are printed as JSON
+func accountsGenerateCommandHandler(ctx *cli.Context) error {
+	if ctx.String(SeedFlag.Name) == "" {
+		return ErrSeedMissing
+	}
+
+	config, err := parseAccountsCommandConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("can not parse config: %v", err)
+	}
+
+	// node is started to access its keystore only
+	if err := statusAPI.StartNode(config); err != nil {
+		return err
+	}
+	defer statusAPI.StopNode() // nolint: errcheck
+
+	generated, err := statusAPI.GenerateAccounts(
+		ctx.String(SeedFlag.Name),
+		ctx.Int(CountFlag.Name),
+		ctx.String(PasswordFlag.Name),
+		ctx.String(FaucetURLFlag.Name),
+	)
+	if len(generated) > 0 {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(generated); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// parseAccountsCommandConfig returns configuration of node without sub-protocols and peers
+func parseAccountsCommandConfig(ctx *cli.Context) (*params.NodeConfig, error) {
+	nodeConfig, err := makeNodeConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeConfig.LightEthConfig.Enabled = false
+	nodeConfig.WhisperConfig.Enabled = false
+	nodeConfig.SwarmConfig.Enabled = false
+	nodeConfig.BootClusterConfig.Enabled = false
+	nodeConfig.HTTPHost = ""
+	nodeConfig.IPCEnabled = false
+	nodeConfig.MaxPeers = 0
+
+	return nodeConfig, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+//go:generate go run ../genflags/main.go -out config_flags.go -skip DevMode,NetworkID,DataDir,NodeKeyFile,LogLevel,LogFile ../../geth/params/config.go
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/status-im/status-go/geth/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	configCommand = cli.Command{
+		Name:  "config",
+		Usage: "Node configuration utilities",
+		Subcommands: []cli.Command{
+			{
+				Action: configDumpCommandHandler,
+				Name:   "dump",
+				Usage:  "Print effective node configuration (as JSON)",
+			},
+		},
+	}
+)
+
+// configDumpCommandHandler handles `statusd config dump` command
+func configDumpCommandHandler(ctx *cli.Context) error {
+	nodeConfig, err := makeNodeConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("can not parse config: %v", err)
+	}
+	applyConfigFlags(ctx, nodeConfig)
+
+	// genesis is derived from network ID on config load, no need to dump it
+	if nodeConfig.NetworkID == params.MainNetworkID ||
+		nodeConfig.NetworkID == params.RopstenNetworkID ||
+		nodeConfig.NetworkID == params.RinkebyNetworkID {
+		nodeConfig.LightEthConfig.Genesis = ""
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, nodeConfig)
+	return err
+}
+
+// isConfigFlagSet checks whether config flag is set either explicitly
+// or via corresponding environment variable.
+func isConfigFlagSet(ctx *cli.Context, name, envVar string) bool {
+	if ctx.GlobalIsSet(name) {
+		return true
+	}
+
+	return os.Getenv(envVar) != ""
+}
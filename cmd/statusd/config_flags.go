@@ -0,0 +1,888 @@
+// Code generated by genflags. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/status-im/status-go/geth/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// configFlags are flags for every field of params.NodeConfig
+var configFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:   "config.keystoredir",
+		Usage:  "The file system folder that contains private keys",
+		EnvVar: "STATUSD_KEYSTOREDIR",
+	},
+	cli.StringFlag{
+		Name:   "config.name",
+		Usage:  "Sets the instance name of the node",
+		EnvVar: "STATUSD_NAME",
+	},
+	cli.StringFlag{
+		Name:   "config.version",
+		Usage:  "Exposes program's version",
+		EnvVar: "STATUSD_VERSION",
+	},
+	cli.StringFlag{
+		Name:   "config.apimodules",
+		Usage:  "A comma-separated list of API modules exposed via *any* (HTTP/WS/IPC) RPC interface",
+		EnvVar: "STATUSD_APIMODULES",
+	},
+	cli.StringFlag{
+		Name:   "config.httphost",
+		Usage:  "The host interface on which to start the HTTP RPC server",
+		EnvVar: "STATUSD_HTTPHOST",
+	},
+	cli.BoolFlag{
+		Name:   "config.rpcenabled",
+		Usage:  "Specifies whether the http RPC server is to be enabled by default",
+		EnvVar: "STATUSD_RPCENABLED",
+	},
+	cli.IntFlag{
+		Name:   "config.httpport",
+		Usage:  "The TCP port number on which to start the Geth's HTTP RPC server",
+		EnvVar: "STATUSD_HTTPPORT",
+	},
+	cli.StringFlag{
+		Name:   "config.wshost",
+		Usage:  "A host interface for the WebSocket RPC server",
+		EnvVar: "STATUSD_WSHOST",
+	},
+	cli.IntFlag{
+		Name:   "config.wsport",
+		Usage:  "The TCP port number on which to start the Geth's WebSocket RPC server",
+		EnvVar: "STATUSD_WSPORT",
+	},
+	cli.BoolFlag{
+		Name:   "config.wsenabled",
+		Usage:  "Specifies whether WS-RPC Server is enabled or not",
+		EnvVar: "STATUSD_WSENABLED",
+	},
+	cli.StringFlag{
+		Name:   "config.ipcfile",
+		Usage:  "Filename of exposed IPC RPC Server",
+		EnvVar: "STATUSD_IPCFILE",
+	},
+	cli.BoolFlag{
+		Name:   "config.ipcenabled",
+		Usage:  "Specifies whether IPC-RPC Server is enabled or not",
+		EnvVar: "STATUSD_IPCENABLED",
+	},
+	cli.BoolFlag{
+		Name:   "config.tlsenabled",
+		Usage:  "Specifies whether TLS support should be enabled on node or not TLS support is only planned in go-ethereum, so we are using our own patch",
+		EnvVar: "STATUSD_TLSENABLED",
+	},
+	cli.IntFlag{
+		Name:   "config.maxpeers",
+		Usage:  "The maximum number of (global) peers that can be connected",
+		EnvVar: "STATUSD_MAXPEERS",
+	},
+	cli.IntFlag{
+		Name:   "config.maxpendingpeers",
+		Usage:  "The maximum number of peers that can be pending in the handshake phase, counted separately for inbound and outbound connections",
+		EnvVar: "STATUSD_MAXPENDINGPEERS",
+	},
+	cli.BoolFlag{
+		Name:   "config.logtostderr",
+		Usage:  "Defines whether logged info should also be output to os.Stderr",
+		EnvVar: "STATUSD_LOGTOSTDERR",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.secretfields",
+		Usage:  "Lists paths of string fields (such as \"UpstreamConfig.URL\"), which are encrypted at rest with a key of the device, kept in DataDir (see SaveEncrypted, LoadNodeConfig); DefaultSecretFields, if empty",
+		EnvVar: "STATUSD_SECRETFIELDS",
+	},
+	cli.BoolFlag{
+		Name:   "config.upstreamconfig.enabled",
+		Usage:  "Flag specifies whether feature is enabled",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_ENABLED",
+	},
+	cli.StringFlag{
+		Name:   "config.upstreamconfig.url",
+		Usage:  "Sets the rpc upstream host address for communication with a non-local infura endpoint",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_URL",
+	},
+	cli.StringFlag{
+		Name:   "config.upstreamconfig.websocketurl",
+		Usage:  "An upstream WebSocket endpoint, used for eth_subscribe subscriptions",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_WEBSOCKETURL",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.upstreamconfig.fallbackurls",
+		Usage:  "Lists upstream providers to switch to, when the one at URL is unavailable",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_FALLBACKURLS",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.upstreamconfig.headers",
+		Usage:  "Lists HTTP headers \"Name: value\" (e.g",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_HEADERS",
+	},
+	cli.StringFlag{
+		Name:   "config.upstreamconfig.basicauth",
+		Usage:  "\"user:password\" pair, sent with every request to upstream providers",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_BASICAUTH",
+	},
+	cli.StringFlag{
+		Name:   "config.upstreamconfig.strategy",
+		Usage:  "Defines how provider is selected among healthy ones: \"priority\" (default) or \"round-robin\"",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_STRATEGY",
+	},
+	cli.IntFlag{
+		Name:   "config.upstreamconfig.healthcheckinterval",
+		Usage:  "Interval (in seconds) between health checks of providers (default is 30)",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_HEALTHCHECKINTERVAL",
+	},
+	cli.IntFlag{
+		Name:   "config.upstreamconfig.calltimeout",
+		Usage:  "A timeout (in seconds) of a single attempt of RPC call (default is 60)",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_CALLTIMEOUT",
+	},
+	cli.IntFlag{
+		Name:   "config.upstreamconfig.retries",
+		Usage:  "A number of times an upstream call is retried (with jittered backoff) on connectivity errors",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_RETRIES",
+	},
+	cli.IntFlag{
+		Name:   "config.upstreamconfig.breakerthreshold",
+		Usage:  "A number of consecutive failed upstream calls, after which circuit breaker opens, failing further calls immediately (0 disables breaker)",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_BREAKERTHRESHOLD",
+	},
+	cli.IntFlag{
+		Name:   "config.upstreamconfig.breakercooldown",
+		Usage:  "Interval (in seconds), after which open circuit breaker lets a probe call through (default is 30)",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_BREAKERCOOLDOWN",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.upstreamconfig.methodrouting",
+		Usage:  "Lists rules \"method=local\" or \"method=upstream\" (method may end with \"*\" to match a prefix), overriding the default routing of RPC calls; the first matching rule wins",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_METHODROUTING",
+	},
+	cli.BoolFlag{
+		Name:   "config.upstreamconfig.cacheenabled",
+		Usage:  "Flag specifies whether results of idempotent calls are cached in memory",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_CACHEENABLED",
+	},
+	cli.BoolFlag{
+		Name:   "config.upstreamconfig.offlinestateenabled",
+		Usage:  "Flag specifies whether the last known balances, nonces, gas price and block number are kept, in order to serve them (labeled as stale), when upstream is unreachable",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_OFFLINESTATEENABLED",
+	},
+	cli.BoolFlag{
+		Name:   "config.upstreamconfig.offlinequeueenabled",
+		Usage:  "Flag specifies whether calls fail fast, while upstream is unreachable, except of signed raw transactions and Whisper posts, which are queued (and persisted) and replayed, once connectivity is restored",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_OFFLINEQUEUEENABLED",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.upstreamconfig.cachettls",
+		Usage:  "Lists rules \"method=seconds\", overriding how long results of method are cached (0 disables caching)",
+		EnvVar: "STATUSD_UPSTREAMCONFIG_CACHETTLS",
+	},
+	cli.BoolFlag{
+		Name:   "config.bootclusterconfig.enabled",
+		Usage:  "Flag specifies whether feature is enabled",
+		EnvVar: "STATUSD_BOOTCLUSTERCONFIG_ENABLED",
+	},
+	cli.IntFlag{
+		Name:   "config.bootclusterconfig.rootnumber",
+		Usage:  "CHT root number",
+		EnvVar: "STATUSD_BOOTCLUSTERCONFIG_ROOTNUMBER",
+	},
+	cli.StringFlag{
+		Name:   "config.bootclusterconfig.roothash",
+		Usage:  "Hash of CHT root for a given root number",
+		EnvVar: "STATUSD_BOOTCLUSTERCONFIG_ROOTHASH",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.bootclusterconfig.bootnodes",
+		Usage:  "List of bootstrap nodes for a given network (Ropsten, Rinkeby, Homestead), for a given mode (production vs development)",
+		EnvVar: "STATUSD_BOOTCLUSTERCONFIG_BOOTNODES",
+	},
+	cli.StringFlag{
+		Name:   "config.bootclusterconfig.registryurl",
+		Usage:  "URL of signed fleet registry, boot nodes are fetched from",
+		EnvVar: "STATUSD_BOOTCLUSTERCONFIG_REGISTRYURL",
+	},
+	cli.StringFlag{
+		Name:   "config.bootclusterconfig.registrykey",
+		Usage:  "Hex-encoded public key fleet registry is expected to be signed with",
+		EnvVar: "STATUSD_BOOTCLUSTERCONFIG_REGISTRYKEY",
+	},
+	cli.BoolFlag{
+		Name:   "config.lightethconfig.enabled",
+		Usage:  "Flag specifies whether protocol is enabled",
+		EnvVar: "STATUSD_LIGHTETHCONFIG_ENABLED",
+	},
+	cli.StringFlag{
+		Name:   "config.lightethconfig.genesis",
+		Usage:  "JSON to seed the chain database with",
+		EnvVar: "STATUSD_LIGHTETHCONFIG_GENESIS",
+	},
+	cli.IntFlag{
+		Name:   "config.lightethconfig.databasecache",
+		Usage:  "Memory (in MBs) allocated to internal caching (min 16MB / database forced)",
+		EnvVar: "STATUSD_LIGHTETHCONFIG_DATABASECACHE",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.enabled",
+		Usage:  "Flag specifies whether protocol is enabled",
+		EnvVar: "STATUSD_WHISPERCONFIG_ENABLED",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.identityfile",
+		Usage:  "Path to private key, that will be loaded as identity into Whisper",
+		EnvVar: "STATUSD_WHISPERCONFIG_IDENTITYFILE",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.passwordfile",
+		Usage:  "Path to password file, for non-interactive password entry (if no account file selected, then this password is used for symmetric encryption)",
+		EnvVar: "STATUSD_WHISPERCONFIG_PASSWORDFILE",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.echomode",
+		Usage:  "If mode is on, prints some arguments for diagnostics",
+		EnvVar: "STATUSD_WHISPERCONFIG_ECHOMODE",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.bootstrapnode",
+		Usage:  "Whether node doesn't actively connect to peers, and waits for incoming connections",
+		EnvVar: "STATUSD_WHISPERCONFIG_BOOTSTRAPNODE",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.forwardernode",
+		Usage:  "Mode when node only forwards messages, neither sends nor decrypts messages",
+		EnvVar: "STATUSD_WHISPERCONFIG_FORWARDERNODE",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.lightclient",
+		Usage:  "Mode when node only sends and receives its own messages, not relaying messages of others",
+		EnvVar: "STATUSD_WHISPERCONFIG_LIGHTCLIENT",
+	},
+	cli.Float64Flag{
+		Name:   "config.whisperconfig.lightclientpow",
+		Usage:  "PoW of messages sent by light client (MinimumPoW, if zero)",
+		EnvVar: "STATUSD_WHISPERCONFIG_LIGHTCLIENTPOW",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.mailservernode",
+		Usage:  "Mode when node is capable of delivering expired messages on demand",
+		EnvVar: "STATUSD_WHISPERCONFIG_MAILSERVERNODE",
+	},
+	cli.IntFlag{
+		Name:   "config.whisperconfig.mailserverretention",
+		Usage:  "Time (in seconds), envelopes are archived by mail server for (forever, if zero)",
+		EnvVar: "STATUSD_WHISPERCONFIG_MAILSERVERRETENTION",
+	},
+	cli.IntFlag{
+		Name:   "config.whisperconfig.mailservermaxenvelopes",
+		Usage:  "A maximum number of envelopes archived by mail server (unlimited, if zero)",
+		EnvVar: "STATUSD_WHISPERCONFIG_MAILSERVERMAXENVELOPES",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.whisperconfig.mailservernodes",
+		Usage:  "Lists enode URLs of known mail servers, connections with which are preferred",
+		EnvVar: "STATUSD_WHISPERCONFIG_MAILSERVERNODES",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.mailserverpassword",
+		Usage:  "A password of mail servers, symmetric key of requests of historic messages is derived from",
+		EnvVar: "STATUSD_WHISPERCONFIG_MAILSERVERPASSWORD",
+	},
+	cli.BoolFlag{
+		Name:   "config.whisperconfig.notificationservernode",
+		Usage:  "Mode when node is capable of sending Push (and probably other kinds) Notifications",
+		EnvVar: "STATUSD_WHISPERCONFIG_NOTIFICATIONSERVERNODE",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.datadir",
+		Usage:  "The file system folder Whisper should use for any data storage needs",
+		EnvVar: "STATUSD_WHISPERCONFIG_DATADIR",
+	},
+	cli.IntFlag{
+		Name:   "config.whisperconfig.port",
+		Usage:  "Whisper node's listening port",
+		EnvVar: "STATUSD_WHISPERCONFIG_PORT",
+	},
+	cli.Float64Flag{
+		Name:   "config.whisperconfig.minimumpow",
+		Usage:  "Minimum PoW for Whisper messages",
+		EnvVar: "STATUSD_WHISPERCONFIG_MINIMUMPOW",
+	},
+	cli.IntFlag{
+		Name:   "config.whisperconfig.ttl",
+		Usage:  "Time to live for messages, in seconds",
+		EnvVar: "STATUSD_WHISPERCONFIG_TTL",
+	},
+	cli.IntFlag{
+		Name:   "config.whisperconfig.outboxmaxretries",
+		Usage:  "A maximum number of attempts to send message of outbox (unlimited, if zero)",
+		EnvVar: "STATUSD_WHISPERCONFIG_OUTBOXMAXRETRIES",
+	},
+	cli.IntFlag{
+		Name:   "config.whisperconfig.outboxttl",
+		Usage:  "Time (in seconds), message is kept in outbox for, until it's sent (forever, if zero)",
+		EnvVar: "STATUSD_WHISPERCONFIG_OUTBOXTTL",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.firebaseconfig.authorizationkey",
+		Usage:  "FCM authorization (server) key, it takes precedence over AuthorizationKeyFile",
+		EnvVar: "STATUSD_WHISPERCONFIG_FIREBASECONFIG_AUTHORIZATIONKEY",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.firebaseconfig.authorizationkeyfile",
+		Usage:  "File path that contains FCM authorization key",
+		EnvVar: "STATUSD_WHISPERCONFIG_FIREBASECONFIG_AUTHORIZATIONKEYFILE",
+	},
+	cli.StringFlag{
+		Name:   "config.whisperconfig.firebaseconfig.notificationtriggerurl",
+		Usage:  "URL used to send push notification requests to",
+		EnvVar: "STATUSD_WHISPERCONFIG_FIREBASECONFIG_NOTIFICATIONTRIGGERURL",
+	},
+	cli.BoolFlag{
+		Name:   "config.swarmconfig.enabled",
+		Usage:  "Flag specifies whether protocol is enabled",
+		EnvVar: "STATUSD_SWARMCONFIG_ENABLED",
+	},
+	cli.StringFlag{
+		Name:   "config.jailconfig.engine",
+		Usage:  "JavaScript engine cells are run with, \"otto\" (ES5, default) or \"goja\" (ES6 and later)",
+		EnvVar: "STATUSD_JAILCONFIG_ENGINE",
+	},
+	cli.Float64Flag{
+		Name:   "config.jailconfig.ratelimit",
+		Usage:  "A number of RPC requests per second, each jail cell may send (0 disables rate limiting)",
+		EnvVar: "STATUSD_JAILCONFIG_RATELIMIT",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.rateburst",
+		Usage:  "A number of RPC requests, cell may send at once, before rate limit applies (default is 1)",
+		EnvVar: "STATUSD_JAILCONFIG_RATEBURST",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.maxcells",
+		Usage:  "A number of live jail cells, above which least recently used cells are evicted (and restored from snapshots on next access); 0 means no limit",
+		EnvVar: "STATUSD_JAILCONFIG_MAXCELLS",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.maxheapsize",
+		Usage:  "A size of heap (in megabytes), above which the cell, that has handled the most calls, is recycled (re-created from its snapshot), releasing memory accumulated by its VM; 0 disables recycling",
+		EnvVar: "STATUSD_JAILCONFIG_MAXHEAPSIZE",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.exectimeout",
+		Usage:  "A duration (in milliseconds), execution of JavaScript code of cell (parsing of bundle, calls and callbacks) is interrupted after, so that infinite loops don't hang; 0 means no limit",
+		EnvVar: "STATUSD_JAILCONFIG_EXECTIMEOUT",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.cellcpubudget",
+		Usage:  "A total duration (in milliseconds) of execution of JavaScript code of cell, above which cell is terminated; 0 means no limit",
+		EnvVar: "STATUSD_JAILCONFIG_CELLCPUBUDGET",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.cellheapbudget",
+		Usage:  "A growth of heap (in megabytes) during execution of JavaScript code of cell, above which cell is terminated; 0 means no limit",
+		EnvVar: "STATUSD_JAILCONFIG_CELLHEAPBUDGET",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.storagequota",
+		Usage:  "A size (in bytes) of keys and values, each cell may keep in its localStorage (persisted in data dir); 0 means default quota of 5 MB",
+		EnvVar: "STATUSD_JAILCONFIG_STORAGEQUOTA",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.jailconfig.fetchwhitelist",
+		Usage:  "Lists origins, cells may send requests to with fetch(), like \"https://api.example.com\" or \"https://*.example.com\" (any subdomain); any origin is allowed, if empty",
+		EnvVar: "STATUSD_JAILCONFIG_FETCHWHITELIST",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.fetchmaxresponsesize",
+		Usage:  "A size (in bytes) of response body, above which fetch() fails; 0 means default limit of 1 MB",
+		EnvVar: "STATUSD_JAILCONFIG_FETCHMAXRESPONSESIZE",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.fetchtimeout",
+		Usage:  "A timeout (in milliseconds) of requests cells send with fetch(); 0 means 30 seconds",
+		EnvVar: "STATUSD_JAILCONFIG_FETCHTIMEOUT",
+	},
+	cli.BoolFlag{
+		Name:   "config.jailconfig.eip1193provider",
+		Usage:  "Exposes EIP-1193 provider as ethereum object in cells, so that web3 1.x and newer libraries can be used along with the legacy web3",
+		EnvVar: "STATUSD_JAILCONFIG_EIP1193PROVIDER",
+	},
+	cli.IntFlag{
+		Name:   "config.jailconfig.logbuffersize",
+		Usage:  "A number of the last entries, each cell has logged with console, kept for inspection; 0 means 100 entries",
+		EnvVar: "STATUSD_JAILCONFIG_LOGBUFFERSIZE",
+	},
+	cli.BoolFlag{
+		Name:   "config.jailconfig.streamlogs",
+		Usage:  "Sends every entry cells log with console as a signal",
+		EnvVar: "STATUSD_JAILCONFIG_STREAMLOGS",
+	},
+	cli.BoolFlag{
+		Name:   "config.jailconfig.orderedcalls",
+		Usage:  "Executes calls of each cell in order they have been made, tagging responses with sequence numbers, so that clients can rely on order of responses to chains of commands",
+		EnvVar: "STATUSD_JAILCONFIG_ORDEREDCALLS",
+	},
+	cli.StringSliceFlag{
+		Name:   "config.signingconfig.allowedaccounts",
+		Usage:  "Lists addresses of accounts permitted to sign transactions (any account, if empty)",
+		EnvVar: "STATUSD_SIGNINGCONFIG_ALLOWEDACCOUNTS",
+	},
+	cli.BoolFlag{
+		Name:   "config.signingconfig.autoapprove",
+		Usage:  "Flag specifies whether queued transactions are approved or rejected without user interaction: transactions of value up to AutoApproveMaxValue are completed, others are rejected",
+		EnvVar: "STATUSD_SIGNINGCONFIG_AUTOAPPROVE",
+	},
+	cli.StringFlag{
+		Name:   "config.signingconfig.autoapprovemaxvalue",
+		Usage:  "Maximum value (decimal, in wei) of automatically approved transaction",
+		EnvVar: "STATUSD_SIGNINGCONFIG_AUTOAPPROVEMAXVALUE",
+	},
+	cli.StringFlag{
+		Name:   "config.signingconfig.passwordfile",
+		Usage:  "Path to the file with password of selected account, approved transactions are signed with",
+		EnvVar: "STATUSD_SIGNINGCONFIG_PASSWORDFILE",
+	},
+	cli.BoolFlag{
+		Name:   "config.signingconfig.typedtransactions",
+		Usage:  "Flag specifies whether typed transaction envelopes (EIP-2718) may be signed, provided that signer of account supports them",
+		EnvVar: "STATUSD_SIGNINGCONFIG_TYPEDTRANSACTIONS",
+	},
+	cli.StringFlag{
+		Name:   "config.explorerconfig.kind",
+		Usage:  "A kind of explorer (\"etherscan\" or \"blockscout\"), it defines paths of pages of transactions and addresses",
+		EnvVar: "STATUSD_EXPLORERCONFIG_KIND",
+	},
+	cli.StringFlag{
+		Name:   "config.explorerconfig.url",
+		Usage:  "A base URL of explorer, if empty, explorer registered for the network is used (Etherscan on public networks)",
+		EnvVar: "STATUSD_EXPLORERCONFIG_URL",
+	},
+	cli.StringFlag{
+		Name:   "config.keystoreconfig.kdf",
+		Usage:  "Key derivation function of new keys, \"scrypt\" or \"argon2id\"",
+		EnvVar: "STATUSD_KEYSTORECONFIG_KDF",
+	},
+	cli.BoolFlag{
+		Name:   "config.keystoreconfig.lightkdf",
+		Usage:  "Selects light scrypt parameters (4MB of memory), for low-end devices",
+		EnvVar: "STATUSD_KEYSTORECONFIG_LIGHTKDF",
+	},
+	cli.IntFlag{
+		Name:   "config.keystoreconfig.scryptn",
+		Usage:  "CPU/memory cost parameter of scrypt, power of 2 (standard parameters are used, if zero)",
+		EnvVar: "STATUSD_KEYSTORECONFIG_SCRYPTN",
+	},
+	cli.IntFlag{
+		Name:   "config.keystoreconfig.scryptp",
+		Usage:  "Parallelization parameter of scrypt (standard parameters are used, if zero)",
+		EnvVar: "STATUSD_KEYSTORECONFIG_SCRYPTP",
+	},
+	cli.UintFlag{
+		Name:   "config.keystoreconfig.argon2time",
+		Usage:  "The number of passes of Argon2id over the memory",
+		EnvVar: "STATUSD_KEYSTORECONFIG_ARGON2TIME",
+	},
+	cli.UintFlag{
+		Name:   "config.keystoreconfig.argon2memory",
+		Usage:  "The memory (in KiB) used by Argon2id",
+		EnvVar: "STATUSD_KEYSTORECONFIG_ARGON2MEMORY",
+	},
+	cli.UintFlag{
+		Name:   "config.keystoreconfig.argon2threads",
+		Usage:  "The number of lanes of Argon2id",
+		EnvVar: "STATUSD_KEYSTORECONFIG_ARGON2THREADS",
+	},
+	cli.StringFlag{
+		Name:   "config.feeconfig.gaspriceoracleurl",
+		Usage:  "URL of gas price oracle, which is asked for gas price before the node (if empty, gas price is suggested by the node)",
+		EnvVar: "STATUSD_FEECONFIG_GASPRICEORACLEURL",
+	},
+	cli.BoolFlag{
+		Name:   "config.relayconfig.enabled",
+		Usage:  "Flag specifies whether transactions are signed as relayed calls, and submitted to relay, instead of being sent as raw transactions",
+		EnvVar: "STATUSD_RELAYCONFIG_ENABLED",
+	},
+	cli.StringFlag{
+		Name:   "config.relayconfig.url",
+		Usage:  "An endpoint of relay, signed relayed calls are posted to",
+		EnvVar: "STATUSD_RELAYCONFIG_URL",
+	},
+	cli.StringFlag{
+		Name:   "config.relayconfig.hubaddress",
+		Usage:  "An address of relay hub contract, which nonces of senders are kept by, and which relayed calls are signed for",
+		EnvVar: "STATUSD_RELAYCONFIG_HUBADDRESS",
+	},
+	cli.Uint64Flag{
+		Name:   "config.relayconfig.fee",
+		Usage:  "A fee of relay, percents of gas cost of relayed call",
+		EnvVar: "STATUSD_RELAYCONFIG_FEE",
+	},
+	cli.IntFlag{
+		Name:   "config.txqueueconfig.size",
+		Usage:  "A maximum number of queued transactions",
+		EnvVar: "STATUSD_TXQUEUECONFIG_SIZE",
+	},
+	cli.IntFlag{
+		Name:   "config.txqueueconfig.ttl",
+		Usage:  "A time (in seconds), within which queued transaction must be completed, otherwise it expires",
+		EnvVar: "STATUSD_TXQUEUECONFIG_TTL",
+	},
+	cli.StringFlag{
+		Name:   "config.txqueueconfig.eviction",
+		Usage:  "A policy applied to new transactions, once queue is full: either the oldest transaction is evicted to make room for a new one (\"evict-oldest\"), or a new one is rejected (\"reject-new\")",
+		EnvVar: "STATUSD_TXQUEUECONFIG_EVICTION",
+	},
+	cli.BoolFlag{
+		Name:   "config.txmonitorconfig.enabled",
+		Usage:  "Flag specifies whether sent transactions are monitored, so that signals are sent once they are confirmed, or fail",
+		EnvVar: "STATUSD_TXMONITORCONFIG_ENABLED",
+	},
+	cli.Uint64Flag{
+		Name:   "config.txmonitorconfig.confirmations",
+		Usage:  "A number of blocks (including the one transaction is mined in), transaction is considered confirmed after",
+		EnvVar: "STATUSD_TXMONITORCONFIG_CONFIRMATIONS",
+	},
+	cli.IntFlag{
+		Name:   "config.txmonitorconfig.timeout",
+		Usage:  "A time (in seconds), within which transaction must be mined, otherwise it's considered failed",
+		EnvVar: "STATUSD_TXMONITORCONFIG_TIMEOUT",
+	},
+	cli.BoolFlag{
+		Name:   "config.txhistoryconfig.enabled",
+		Usage:  "Flag specifies whether history of transactions (sent ones, and ones found in chain) is kept",
+		EnvVar: "STATUSD_TXHISTORYCONFIG_ENABLED",
+	},
+}
+
+// applyConfigFlags overrides config fields with values of flags (or environment variables) set
+func applyConfigFlags(ctx *cli.Context, config *params.NodeConfig) {
+	if isConfigFlagSet(ctx, "config.keystoredir", "STATUSD_KEYSTOREDIR") {
+		config.KeyStoreDir = ctx.GlobalString("config.keystoredir")
+	}
+	if isConfigFlagSet(ctx, "config.name", "STATUSD_NAME") {
+		config.Name = ctx.GlobalString("config.name")
+	}
+	if isConfigFlagSet(ctx, "config.version", "STATUSD_VERSION") {
+		config.Version = ctx.GlobalString("config.version")
+	}
+	if isConfigFlagSet(ctx, "config.apimodules", "STATUSD_APIMODULES") {
+		config.APIModules = ctx.GlobalString("config.apimodules")
+	}
+	if isConfigFlagSet(ctx, "config.httphost", "STATUSD_HTTPHOST") {
+		config.HTTPHost = ctx.GlobalString("config.httphost")
+	}
+	if isConfigFlagSet(ctx, "config.rpcenabled", "STATUSD_RPCENABLED") {
+		config.RPCEnabled = ctx.GlobalBool("config.rpcenabled")
+	}
+	if isConfigFlagSet(ctx, "config.httpport", "STATUSD_HTTPPORT") {
+		config.HTTPPort = ctx.GlobalInt("config.httpport")
+	}
+	if isConfigFlagSet(ctx, "config.wshost", "STATUSD_WSHOST") {
+		config.WSHost = ctx.GlobalString("config.wshost")
+	}
+	if isConfigFlagSet(ctx, "config.wsport", "STATUSD_WSPORT") {
+		config.WSPort = ctx.GlobalInt("config.wsport")
+	}
+	if isConfigFlagSet(ctx, "config.wsenabled", "STATUSD_WSENABLED") {
+		config.WSEnabled = ctx.GlobalBool("config.wsenabled")
+	}
+	if isConfigFlagSet(ctx, "config.ipcfile", "STATUSD_IPCFILE") {
+		config.IPCFile = ctx.GlobalString("config.ipcfile")
+	}
+	if isConfigFlagSet(ctx, "config.ipcenabled", "STATUSD_IPCENABLED") {
+		config.IPCEnabled = ctx.GlobalBool("config.ipcenabled")
+	}
+	if isConfigFlagSet(ctx, "config.tlsenabled", "STATUSD_TLSENABLED") {
+		config.TLSEnabled = ctx.GlobalBool("config.tlsenabled")
+	}
+	if isConfigFlagSet(ctx, "config.maxpeers", "STATUSD_MAXPEERS") {
+		config.MaxPeers = ctx.GlobalInt("config.maxpeers")
+	}
+	if isConfigFlagSet(ctx, "config.maxpendingpeers", "STATUSD_MAXPENDINGPEERS") {
+		config.MaxPendingPeers = ctx.GlobalInt("config.maxpendingpeers")
+	}
+	if isConfigFlagSet(ctx, "config.logtostderr", "STATUSD_LOGTOSTDERR") {
+		config.LogToStderr = ctx.GlobalBool("config.logtostderr")
+	}
+	if isConfigFlagSet(ctx, "config.secretfields", "STATUSD_SECRETFIELDS") {
+		config.SecretFields = ctx.GlobalStringSlice("config.secretfields")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.enabled", "STATUSD_UPSTREAMCONFIG_ENABLED") {
+		config.UpstreamConfig.Enabled = ctx.GlobalBool("config.upstreamconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.url", "STATUSD_UPSTREAMCONFIG_URL") {
+		config.UpstreamConfig.URL = ctx.GlobalString("config.upstreamconfig.url")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.websocketurl", "STATUSD_UPSTREAMCONFIG_WEBSOCKETURL") {
+		config.UpstreamConfig.WebSocketURL = ctx.GlobalString("config.upstreamconfig.websocketurl")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.fallbackurls", "STATUSD_UPSTREAMCONFIG_FALLBACKURLS") {
+		config.UpstreamConfig.FallbackURLs = ctx.GlobalStringSlice("config.upstreamconfig.fallbackurls")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.headers", "STATUSD_UPSTREAMCONFIG_HEADERS") {
+		config.UpstreamConfig.Headers = ctx.GlobalStringSlice("config.upstreamconfig.headers")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.basicauth", "STATUSD_UPSTREAMCONFIG_BASICAUTH") {
+		config.UpstreamConfig.BasicAuth = ctx.GlobalString("config.upstreamconfig.basicauth")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.strategy", "STATUSD_UPSTREAMCONFIG_STRATEGY") {
+		config.UpstreamConfig.Strategy = ctx.GlobalString("config.upstreamconfig.strategy")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.healthcheckinterval", "STATUSD_UPSTREAMCONFIG_HEALTHCHECKINTERVAL") {
+		config.UpstreamConfig.HealthCheckInterval = ctx.GlobalInt("config.upstreamconfig.healthcheckinterval")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.calltimeout", "STATUSD_UPSTREAMCONFIG_CALLTIMEOUT") {
+		config.UpstreamConfig.CallTimeout = ctx.GlobalInt("config.upstreamconfig.calltimeout")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.retries", "STATUSD_UPSTREAMCONFIG_RETRIES") {
+		config.UpstreamConfig.Retries = ctx.GlobalInt("config.upstreamconfig.retries")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.breakerthreshold", "STATUSD_UPSTREAMCONFIG_BREAKERTHRESHOLD") {
+		config.UpstreamConfig.BreakerThreshold = ctx.GlobalInt("config.upstreamconfig.breakerthreshold")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.breakercooldown", "STATUSD_UPSTREAMCONFIG_BREAKERCOOLDOWN") {
+		config.UpstreamConfig.BreakerCooldown = ctx.GlobalInt("config.upstreamconfig.breakercooldown")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.methodrouting", "STATUSD_UPSTREAMCONFIG_METHODROUTING") {
+		config.UpstreamConfig.MethodRouting = ctx.GlobalStringSlice("config.upstreamconfig.methodrouting")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.cacheenabled", "STATUSD_UPSTREAMCONFIG_CACHEENABLED") {
+		config.UpstreamConfig.CacheEnabled = ctx.GlobalBool("config.upstreamconfig.cacheenabled")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.offlinestateenabled", "STATUSD_UPSTREAMCONFIG_OFFLINESTATEENABLED") {
+		config.UpstreamConfig.OfflineStateEnabled = ctx.GlobalBool("config.upstreamconfig.offlinestateenabled")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.offlinequeueenabled", "STATUSD_UPSTREAMCONFIG_OFFLINEQUEUEENABLED") {
+		config.UpstreamConfig.OfflineQueueEnabled = ctx.GlobalBool("config.upstreamconfig.offlinequeueenabled")
+	}
+	if isConfigFlagSet(ctx, "config.upstreamconfig.cachettls", "STATUSD_UPSTREAMCONFIG_CACHETTLS") {
+		config.UpstreamConfig.CacheTTLs = ctx.GlobalStringSlice("config.upstreamconfig.cachettls")
+	}
+	if isConfigFlagSet(ctx, "config.bootclusterconfig.enabled", "STATUSD_BOOTCLUSTERCONFIG_ENABLED") {
+		config.BootClusterConfig.Enabled = ctx.GlobalBool("config.bootclusterconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.bootclusterconfig.rootnumber", "STATUSD_BOOTCLUSTERCONFIG_ROOTNUMBER") {
+		config.BootClusterConfig.RootNumber = ctx.GlobalInt("config.bootclusterconfig.rootnumber")
+	}
+	if isConfigFlagSet(ctx, "config.bootclusterconfig.roothash", "STATUSD_BOOTCLUSTERCONFIG_ROOTHASH") {
+		config.BootClusterConfig.RootHash = ctx.GlobalString("config.bootclusterconfig.roothash")
+	}
+	if isConfigFlagSet(ctx, "config.bootclusterconfig.bootnodes", "STATUSD_BOOTCLUSTERCONFIG_BOOTNODES") {
+		config.BootClusterConfig.BootNodes = ctx.GlobalStringSlice("config.bootclusterconfig.bootnodes")
+	}
+	if isConfigFlagSet(ctx, "config.bootclusterconfig.registryurl", "STATUSD_BOOTCLUSTERCONFIG_REGISTRYURL") {
+		config.BootClusterConfig.RegistryURL = ctx.GlobalString("config.bootclusterconfig.registryurl")
+	}
+	if isConfigFlagSet(ctx, "config.bootclusterconfig.registrykey", "STATUSD_BOOTCLUSTERCONFIG_REGISTRYKEY") {
+		config.BootClusterConfig.RegistryKey = ctx.GlobalString("config.bootclusterconfig.registrykey")
+	}
+	if isConfigFlagSet(ctx, "config.lightethconfig.enabled", "STATUSD_LIGHTETHCONFIG_ENABLED") {
+		config.LightEthConfig.Enabled = ctx.GlobalBool("config.lightethconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.lightethconfig.genesis", "STATUSD_LIGHTETHCONFIG_GENESIS") {
+		config.LightEthConfig.Genesis = ctx.GlobalString("config.lightethconfig.genesis")
+	}
+	if isConfigFlagSet(ctx, "config.lightethconfig.databasecache", "STATUSD_LIGHTETHCONFIG_DATABASECACHE") {
+		config.LightEthConfig.DatabaseCache = ctx.GlobalInt("config.lightethconfig.databasecache")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.enabled", "STATUSD_WHISPERCONFIG_ENABLED") {
+		config.WhisperConfig.Enabled = ctx.GlobalBool("config.whisperconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.identityfile", "STATUSD_WHISPERCONFIG_IDENTITYFILE") {
+		config.WhisperConfig.IdentityFile = ctx.GlobalString("config.whisperconfig.identityfile")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.passwordfile", "STATUSD_WHISPERCONFIG_PASSWORDFILE") {
+		config.WhisperConfig.PasswordFile = ctx.GlobalString("config.whisperconfig.passwordfile")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.echomode", "STATUSD_WHISPERCONFIG_ECHOMODE") {
+		config.WhisperConfig.EchoMode = ctx.GlobalBool("config.whisperconfig.echomode")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.bootstrapnode", "STATUSD_WHISPERCONFIG_BOOTSTRAPNODE") {
+		config.WhisperConfig.BootstrapNode = ctx.GlobalBool("config.whisperconfig.bootstrapnode")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.forwardernode", "STATUSD_WHISPERCONFIG_FORWARDERNODE") {
+		config.WhisperConfig.ForwarderNode = ctx.GlobalBool("config.whisperconfig.forwardernode")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.lightclient", "STATUSD_WHISPERCONFIG_LIGHTCLIENT") {
+		config.WhisperConfig.LightClient = ctx.GlobalBool("config.whisperconfig.lightclient")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.lightclientpow", "STATUSD_WHISPERCONFIG_LIGHTCLIENTPOW") {
+		config.WhisperConfig.LightClientPoW = ctx.GlobalFloat64("config.whisperconfig.lightclientpow")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.mailservernode", "STATUSD_WHISPERCONFIG_MAILSERVERNODE") {
+		config.WhisperConfig.MailServerNode = ctx.GlobalBool("config.whisperconfig.mailservernode")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.mailserverretention", "STATUSD_WHISPERCONFIG_MAILSERVERRETENTION") {
+		config.WhisperConfig.MailServerRetention = ctx.GlobalInt("config.whisperconfig.mailserverretention")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.mailservermaxenvelopes", "STATUSD_WHISPERCONFIG_MAILSERVERMAXENVELOPES") {
+		config.WhisperConfig.MailServerMaxEnvelopes = ctx.GlobalInt("config.whisperconfig.mailservermaxenvelopes")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.mailservernodes", "STATUSD_WHISPERCONFIG_MAILSERVERNODES") {
+		config.WhisperConfig.MailServerNodes = ctx.GlobalStringSlice("config.whisperconfig.mailservernodes")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.mailserverpassword", "STATUSD_WHISPERCONFIG_MAILSERVERPASSWORD") {
+		config.WhisperConfig.MailServerPassword = ctx.GlobalString("config.whisperconfig.mailserverpassword")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.notificationservernode", "STATUSD_WHISPERCONFIG_NOTIFICATIONSERVERNODE") {
+		config.WhisperConfig.NotificationServerNode = ctx.GlobalBool("config.whisperconfig.notificationservernode")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.datadir", "STATUSD_WHISPERCONFIG_DATADIR") {
+		config.WhisperConfig.DataDir = ctx.GlobalString("config.whisperconfig.datadir")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.port", "STATUSD_WHISPERCONFIG_PORT") {
+		config.WhisperConfig.Port = ctx.GlobalInt("config.whisperconfig.port")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.minimumpow", "STATUSD_WHISPERCONFIG_MINIMUMPOW") {
+		config.WhisperConfig.MinimumPoW = ctx.GlobalFloat64("config.whisperconfig.minimumpow")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.ttl", "STATUSD_WHISPERCONFIG_TTL") {
+		config.WhisperConfig.TTL = ctx.GlobalInt("config.whisperconfig.ttl")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.outboxmaxretries", "STATUSD_WHISPERCONFIG_OUTBOXMAXRETRIES") {
+		config.WhisperConfig.OutboxMaxRetries = ctx.GlobalInt("config.whisperconfig.outboxmaxretries")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.outboxttl", "STATUSD_WHISPERCONFIG_OUTBOXTTL") {
+		config.WhisperConfig.OutboxTTL = ctx.GlobalInt("config.whisperconfig.outboxttl")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.firebaseconfig.authorizationkey", "STATUSD_WHISPERCONFIG_FIREBASECONFIG_AUTHORIZATIONKEY") {
+		config.WhisperConfig.FirebaseConfig.AuthorizationKey = ctx.GlobalString("config.whisperconfig.firebaseconfig.authorizationkey")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.firebaseconfig.authorizationkeyfile", "STATUSD_WHISPERCONFIG_FIREBASECONFIG_AUTHORIZATIONKEYFILE") {
+		config.WhisperConfig.FirebaseConfig.AuthorizationKeyFile = ctx.GlobalString("config.whisperconfig.firebaseconfig.authorizationkeyfile")
+	}
+	if isConfigFlagSet(ctx, "config.whisperconfig.firebaseconfig.notificationtriggerurl", "STATUSD_WHISPERCONFIG_FIREBASECONFIG_NOTIFICATIONTRIGGERURL") {
+		config.WhisperConfig.FirebaseConfig.NotificationTriggerURL = ctx.GlobalString("config.whisperconfig.firebaseconfig.notificationtriggerurl")
+	}
+	if isConfigFlagSet(ctx, "config.swarmconfig.enabled", "STATUSD_SWARMCONFIG_ENABLED") {
+		config.SwarmConfig.Enabled = ctx.GlobalBool("config.swarmconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.engine", "STATUSD_JAILCONFIG_ENGINE") {
+		config.JailConfig.Engine = ctx.GlobalString("config.jailconfig.engine")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.ratelimit", "STATUSD_JAILCONFIG_RATELIMIT") {
+		config.JailConfig.RateLimit = ctx.GlobalFloat64("config.jailconfig.ratelimit")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.rateburst", "STATUSD_JAILCONFIG_RATEBURST") {
+		config.JailConfig.RateBurst = ctx.GlobalInt("config.jailconfig.rateburst")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.maxcells", "STATUSD_JAILCONFIG_MAXCELLS") {
+		config.JailConfig.MaxCells = ctx.GlobalInt("config.jailconfig.maxcells")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.maxheapsize", "STATUSD_JAILCONFIG_MAXHEAPSIZE") {
+		config.JailConfig.MaxHeapSize = ctx.GlobalInt("config.jailconfig.maxheapsize")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.exectimeout", "STATUSD_JAILCONFIG_EXECTIMEOUT") {
+		config.JailConfig.ExecTimeout = ctx.GlobalInt("config.jailconfig.exectimeout")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.cellcpubudget", "STATUSD_JAILCONFIG_CELLCPUBUDGET") {
+		config.JailConfig.CellCPUBudget = ctx.GlobalInt("config.jailconfig.cellcpubudget")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.cellheapbudget", "STATUSD_JAILCONFIG_CELLHEAPBUDGET") {
+		config.JailConfig.CellHeapBudget = ctx.GlobalInt("config.jailconfig.cellheapbudget")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.storagequota", "STATUSD_JAILCONFIG_STORAGEQUOTA") {
+		config.JailConfig.StorageQuota = ctx.GlobalInt("config.jailconfig.storagequota")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.fetchwhitelist", "STATUSD_JAILCONFIG_FETCHWHITELIST") {
+		config.JailConfig.FetchWhitelist = ctx.GlobalStringSlice("config.jailconfig.fetchwhitelist")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.fetchmaxresponsesize", "STATUSD_JAILCONFIG_FETCHMAXRESPONSESIZE") {
+		config.JailConfig.FetchMaxResponseSize = ctx.GlobalInt("config.jailconfig.fetchmaxresponsesize")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.fetchtimeout", "STATUSD_JAILCONFIG_FETCHTIMEOUT") {
+		config.JailConfig.FetchTimeout = ctx.GlobalInt("config.jailconfig.fetchtimeout")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.eip1193provider", "STATUSD_JAILCONFIG_EIP1193PROVIDER") {
+		config.JailConfig.EIP1193Provider = ctx.GlobalBool("config.jailconfig.eip1193provider")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.logbuffersize", "STATUSD_JAILCONFIG_LOGBUFFERSIZE") {
+		config.JailConfig.LogBufferSize = ctx.GlobalInt("config.jailconfig.logbuffersize")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.streamlogs", "STATUSD_JAILCONFIG_STREAMLOGS") {
+		config.JailConfig.StreamLogs = ctx.GlobalBool("config.jailconfig.streamlogs")
+	}
+	if isConfigFlagSet(ctx, "config.jailconfig.orderedcalls", "STATUSD_JAILCONFIG_ORDEREDCALLS") {
+		config.JailConfig.OrderedCalls = ctx.GlobalBool("config.jailconfig.orderedcalls")
+	}
+	if isConfigFlagSet(ctx, "config.signingconfig.allowedaccounts", "STATUSD_SIGNINGCONFIG_ALLOWEDACCOUNTS") {
+		config.SigningConfig.AllowedAccounts = ctx.GlobalStringSlice("config.signingconfig.allowedaccounts")
+	}
+	if isConfigFlagSet(ctx, "config.signingconfig.autoapprove", "STATUSD_SIGNINGCONFIG_AUTOAPPROVE") {
+		config.SigningConfig.AutoApprove = ctx.GlobalBool("config.signingconfig.autoapprove")
+	}
+	if isConfigFlagSet(ctx, "config.signingconfig.autoapprovemaxvalue", "STATUSD_SIGNINGCONFIG_AUTOAPPROVEMAXVALUE") {
+		config.SigningConfig.AutoApproveMaxValue = ctx.GlobalString("config.signingconfig.autoapprovemaxvalue")
+	}
+	if isConfigFlagSet(ctx, "config.signingconfig.passwordfile", "STATUSD_SIGNINGCONFIG_PASSWORDFILE") {
+		config.SigningConfig.PasswordFile = ctx.GlobalString("config.signingconfig.passwordfile")
+	}
+	if isConfigFlagSet(ctx, "config.signingconfig.typedtransactions", "STATUSD_SIGNINGCONFIG_TYPEDTRANSACTIONS") {
+		config.SigningConfig.TypedTransactions = ctx.GlobalBool("config.signingconfig.typedtransactions")
+	}
+	if isConfigFlagSet(ctx, "config.explorerconfig.kind", "STATUSD_EXPLORERCONFIG_KIND") {
+		config.ExplorerConfig.Kind = ctx.GlobalString("config.explorerconfig.kind")
+	}
+	if isConfigFlagSet(ctx, "config.explorerconfig.url", "STATUSD_EXPLORERCONFIG_URL") {
+		config.ExplorerConfig.URL = ctx.GlobalString("config.explorerconfig.url")
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.kdf", "STATUSD_KEYSTORECONFIG_KDF") {
+		config.KeyStoreConfig.KDF = ctx.GlobalString("config.keystoreconfig.kdf")
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.lightkdf", "STATUSD_KEYSTORECONFIG_LIGHTKDF") {
+		config.KeyStoreConfig.LightKDF = ctx.GlobalBool("config.keystoreconfig.lightkdf")
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.scryptn", "STATUSD_KEYSTORECONFIG_SCRYPTN") {
+		config.KeyStoreConfig.ScryptN = ctx.GlobalInt("config.keystoreconfig.scryptn")
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.scryptp", "STATUSD_KEYSTORECONFIG_SCRYPTP") {
+		config.KeyStoreConfig.ScryptP = ctx.GlobalInt("config.keystoreconfig.scryptp")
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.argon2time", "STATUSD_KEYSTORECONFIG_ARGON2TIME") {
+		config.KeyStoreConfig.Argon2Time = uint32(ctx.GlobalUint("config.keystoreconfig.argon2time"))
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.argon2memory", "STATUSD_KEYSTORECONFIG_ARGON2MEMORY") {
+		config.KeyStoreConfig.Argon2Memory = uint32(ctx.GlobalUint("config.keystoreconfig.argon2memory"))
+	}
+	if isConfigFlagSet(ctx, "config.keystoreconfig.argon2threads", "STATUSD_KEYSTORECONFIG_ARGON2THREADS") {
+		config.KeyStoreConfig.Argon2Threads = uint8(ctx.GlobalUint("config.keystoreconfig.argon2threads"))
+	}
+	if isConfigFlagSet(ctx, "config.feeconfig.gaspriceoracleurl", "STATUSD_FEECONFIG_GASPRICEORACLEURL") {
+		config.FeeConfig.GasPriceOracleURL = ctx.GlobalString("config.feeconfig.gaspriceoracleurl")
+	}
+	if isConfigFlagSet(ctx, "config.relayconfig.enabled", "STATUSD_RELAYCONFIG_ENABLED") {
+		config.RelayConfig.Enabled = ctx.GlobalBool("config.relayconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.relayconfig.url", "STATUSD_RELAYCONFIG_URL") {
+		config.RelayConfig.URL = ctx.GlobalString("config.relayconfig.url")
+	}
+	if isConfigFlagSet(ctx, "config.relayconfig.hubaddress", "STATUSD_RELAYCONFIG_HUBADDRESS") {
+		config.RelayConfig.HubAddress = ctx.GlobalString("config.relayconfig.hubaddress")
+	}
+	if isConfigFlagSet(ctx, "config.relayconfig.fee", "STATUSD_RELAYCONFIG_FEE") {
+		config.RelayConfig.Fee = ctx.GlobalUint64("config.relayconfig.fee")
+	}
+	if isConfigFlagSet(ctx, "config.txqueueconfig.size", "STATUSD_TXQUEUECONFIG_SIZE") {
+		config.TxQueueConfig.Size = ctx.GlobalInt("config.txqueueconfig.size")
+	}
+	if isConfigFlagSet(ctx, "config.txqueueconfig.ttl", "STATUSD_TXQUEUECONFIG_TTL") {
+		config.TxQueueConfig.TTL = ctx.GlobalInt("config.txqueueconfig.ttl")
+	}
+	if isConfigFlagSet(ctx, "config.txqueueconfig.eviction", "STATUSD_TXQUEUECONFIG_EVICTION") {
+		config.TxQueueConfig.Eviction = ctx.GlobalString("config.txqueueconfig.eviction")
+	}
+	if isConfigFlagSet(ctx, "config.txmonitorconfig.enabled", "STATUSD_TXMONITORCONFIG_ENABLED") {
+		config.TxMonitorConfig.Enabled = ctx.GlobalBool("config.txmonitorconfig.enabled")
+	}
+	if isConfigFlagSet(ctx, "config.txmonitorconfig.confirmations", "STATUSD_TXMONITORCONFIG_CONFIRMATIONS") {
+		config.TxMonitorConfig.Confirmations = ctx.GlobalUint64("config.txmonitorconfig.confirmations")
+	}
+	if isConfigFlagSet(ctx, "config.txmonitorconfig.timeout", "STATUSD_TXMONITORCONFIG_TIMEOUT") {
+		config.TxMonitorConfig.Timeout = ctx.GlobalInt("config.txmonitorconfig.timeout")
+	}
+	if isConfigFlagSet(ctx, "config.txhistoryconfig.enabled", "STATUSD_TXHISTORYCONFIG_ENABLED") {
+		config.TxHistoryConfig.Enabled = ctx.GlobalBool("config.txhistoryconfig.enabled")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestApplyConfigFlags(t *testing.T) {
+	require.NoError(t, os.Setenv("STATUSD_WHISPERCONFIG_MINIMUMPOW", "0.5"))
+	defer os.Unsetenv("STATUSD_WHISPERCONFIG_MINIMUMPOW") // nolint: errcheck
+
+	config := &params.NodeConfig{
+		HTTPPort:       8545,
+		WhisperConfig:  &params.WhisperConfig{},
+		KeyStoreConfig: &params.KeyStoreConfig{},
+	}
+
+	app := cli.NewApp()
+	app.Flags = configFlags
+	app.Action = func(ctx *cli.Context) error {
+		applyConfigFlags(ctx, config)
+		return nil
+	}
+	require.NoError(t, app.Run([]string{"statusd", "--config.httpport", "9000", "--config.apimodules", "eth,shh",
+		"--config.keystoreconfig.argon2memory", "65536", "--config.keystoreconfig.argon2threads", "4"}))
+
+	require.Equal(t, 9000, config.HTTPPort)
+	require.Equal(t, "eth,shh", config.APIModules)
+	require.Equal(t, 0.5, config.WhisperConfig.MinimumPoW)
+	require.Equal(t, uint32(65536), config.KeyStoreConfig.Argon2Memory)
+	require.Equal(t, uint8(4), config.KeyStoreConfig.Argon2Threads)
+	require.False(t, config.IPCEnabled)
+}
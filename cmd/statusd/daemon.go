@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	osSignal "os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/status-im/status-go/geth/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	// PIDFileFlag defines a file to write process ID to
+	PIDFileFlag = cli.StringFlag{
+		Name:  "pidfile",
+		Usage: "Path to the file process ID is written to",
+	}
+
+	// HealthAddrFlag defines address of the health endpoint
+	HealthAddrFlag = cli.StringFlag{
+		Name:  "healthaddr",
+		Usage: `Listening address of HTTP health endpoint suitable for liveness probes, e.g. ":8080" (disabled, if empty)`,
+	}
+
+	// DrainPeriodFlag defines how long node keeps running after SIGTERM is received
+	DrainPeriodFlag = cli.DurationFlag{
+		Name:  "drain",
+		Usage: "Period to wait after SIGTERM is received, before node is stopped",
+		Value: 5 * time.Second,
+	}
+)
+
+// daemon states
+const (
+	daemonRunning int32 = iota
+	daemonDraining
+)
+
+// healthStatus is a response of the health endpoint
+type healthStatus struct {
+	Status string `json:"status"`
+	Peers  int    `json:"peers"`
+}
+
+// serveNode blocks until the running node is stopped.
+// Meanwhile, it takes care of duties of the daemon running under systemd or Kubernetes:
+// PID file, readiness notification, health endpoint and graceful termination on SIGTERM.
+func serveNode(ctx *cli.Context) error {
+	node, err := statusAPI.NodeManager().Node()
+	if err != nil {
+		return err
+	}
+
+	if pidFile := ctx.GlobalString(PIDFileFlag.Name); pidFile != "" {
+		if err := writePIDFile(pidFile); err != nil {
+			return err
+		}
+		defer os.Remove(pidFile) // nolint: errcheck
+	}
+
+	state := daemonRunning
+
+	if healthAddr := ctx.GlobalString(HealthAddrFlag.Name); healthAddr != "" {
+		listener, err := net.Listen("tcp", healthAddr)
+		if err != nil {
+			return fmt.Errorf("can not open health endpoint: %v", err)
+		}
+		defer listener.Close() // nolint: errcheck
+
+		go http.Serve(listener, healthHandler(&state)) // nolint: errcheck
+		log.Info("Health endpoint opened", "addr", listener.Addr())
+	}
+
+	if _, err := sdNotify("READY=1"); err != nil {
+		log.Warn("Failed to notify systemd", "error", err)
+	}
+
+	sigc := make(chan os.Signal, 1)
+	osSignal.Notify(sigc, syscall.SIGTERM)
+	defer osSignal.Stop(sigc)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-sigc:
+		case <-done:
+			return
+		}
+
+		atomic.StoreInt32(&state, daemonDraining)
+		drainPeriod := ctx.GlobalDuration(DrainPeriodFlag.Name)
+		log.Info("Got SIGTERM, draining...", "period", drainPeriod)
+		if _, err := sdNotify("STOPPING=1"); err != nil {
+			log.Warn("Failed to notify systemd", "error", err)
+		}
+
+		time.Sleep(drainPeriod)
+
+		if err := statusAPI.StopNode(); err != nil {
+			log.Error("Failed to stop node", "error", err)
+		}
+	}()
+
+	// wait till node has been stopped
+	node.Wait()
+
+	return nil
+}
+
+// healthHandler reports whether node is running and not being drained.
+func healthHandler(state *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := healthStatus{Status: "ok"}
+		code := http.StatusOK
+
+		node, err := statusAPI.NodeManager().Node()
+		switch {
+		case err != nil:
+			status.Status = "stopped"
+			code = http.StatusServiceUnavailable
+		case atomic.LoadInt32(state) == daemonDraining:
+			status.Status = "draining"
+			code = http.StatusServiceUnavailable
+		}
+		if err == nil && node.Server() != nil {
+			status.Peers = node.Server().PeerCount()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(status) // nolint: errcheck
+	})
+}
+
+// writePIDFile writes ID of the current process to a given file.
+func writePIDFile(path string) error {
+	return ioutil.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+}
+
+// sdNotify sends state notification to systemd (see sd_notify(3)).
+// It returns false, if notification socket is not available,
+// i.e. process is not run by systemd with Type=notify.
+func sdNotify(state string) (bool, error) {
+	socketAddr := &net.UnixAddr{
+		Name: os.Getenv("NOTIFY_SOCKET"),
+		Net:  "unixgram",
+	}
+
+	if socketAddr.Name == "" {
+		return false, nil
+	}
+
+	// abstract namespace socket
+	if socketAddr.Name[0] == '@' {
+		socketAddr.Name = "\x00" + socketAddr.Name[1:]
+	}
+
+	conn, err := net.DialUnix(socketAddr.Net, nil, socketAddr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close() // nolint: errcheck
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDNotify(t *testing.T) {
+	os.Unsetenv("NOTIFY_SOCKET") // nolint: errcheck
+	sent, err := sdNotify("READY=1")
+	require.NoError(t, err)
+	require.False(t, sent)
+
+	dir, err := ioutil.TempDir("", "sdnotify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	socketPath := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close() // nolint: errcheck
+
+	os.Setenv("NOTIFY_SOCKET", socketPath) // nolint: errcheck
+	defer os.Unsetenv("NOTIFY_SOCKET")     // nolint: errcheck
+
+	sent, err = sdNotify("READY=1")
+	require.NoError(t, err)
+	require.True(t, sent)
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, "READY=1", string(buf[:n]))
+}
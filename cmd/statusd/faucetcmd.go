@@ -31,14 +31,7 @@ func faucetCommandHandler(ctx *cli.Context) error {
 		return err
 	}
 
-	// wait till node has been stopped
-	node, err := statusAPI.NodeManager().Node()
-	if err != nil {
-		return nil
-	}
-	node.Wait()
-
-	return nil
+	return serveNode(ctx)
 }
 
 // parseFaucetCommandConfig parses incoming CLI options and returns node configuration object
@@ -62,5 +55,8 @@ func parseFaucetCommandConfig(ctx *cli.Context) (*params.NodeConfig, error) {
 	// extra options
 	nodeConfig.BootClusterConfig.Enabled = true
 
+	// explicitly set config flags (or environment variables) take precedence
+	applyConfigFlags(ctx, nodeConfig)
+
 	return nodeConfig, nil
 }
@@ -34,14 +34,7 @@ func lesCommandHandler(ctx *cli.Context) error {
 		return err
 	}
 
-	// wait till node has been stopped
-	node, err := statusAPI.NodeManager().Node()
-	if err != nil {
-		return nil
-	}
-	node.Wait()
-
-	return nil
+	return serveNode(ctx)
 }
 
 // parseLESCommandConfig parses incoming CLI options and returns node configuration object
@@ -64,5 +57,8 @@ func parseLESCommandConfig(ctx *cli.Context) (*params.NodeConfig, error) {
 	nodeConfig.HTTPPort = ctx.Int(HTTPPortFlag.Name)
 	nodeConfig.IPCEnabled = ctx.Bool(IPCEnabledFlag.Name)
 
+	// explicitly set config flags (or environment variables) take precedence
+	applyConfigFlags(ctx, nodeConfig)
+
 	return nodeConfig, nil
 }
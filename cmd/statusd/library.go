@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"os"
 
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"gopkg.in/go-playground/validator.v9"
 
 	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/deeplink"
 	"github.com/status-im/status-go/geth/log"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/tokens"
+	"github.com/status-im/status-go/geth/txhistory"
 	"github.com/status-im/status-go/helpers/profiling"
 )
 
@@ -29,6 +35,27 @@ func GenerateConfig(datadir *C.char, networkID C.int, devMode C.int) *C.char {
 	return C.CString(string(outBytes))
 }
 
+//export EncryptConfig
+func EncryptConfig(configJSON *C.char) *C.char {
+	config, err := params.LoadNodeConfig(C.GoString(configJSON))
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+
+	// secrets are decrypted by StartNode with key of the device, kept in data dir
+	encrypted, err := config.Encrypted()
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+
+	outBytes, err := json.Marshal(encrypted)
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+
+	return C.CString(string(outBytes))
+}
+
 //export StartNode
 func StartNode(configJSON *C.char) *C.char {
 	config, err := params.LoadNodeConfig(C.GoString(configJSON))
@@ -191,6 +218,185 @@ func RecoverAccount(password, mnemonic *C.char) *C.char {
 	return C.CString(string(outBytes))
 }
 
+//export CreateAccountFromMnemonic
+func CreateAccountFromMnemonic(mnemonic, password *C.char) *C.char {
+	address, pubKey, err := statusAPI.CreateAccountFromMnemonic(C.GoString(mnemonic), C.GoString(password))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := common.AccountInfo{
+		Address:  address,
+		PubKey:   pubKey,
+		Mnemonic: C.GoString(mnemonic),
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export DeriveChildAccounts
+func DeriveChildAccounts(path *C.char, count C.int, password *C.char) *C.char {
+	derived, err := statusAPI.DeriveChildAccounts(C.GoString(path), int(count), C.GoString(password))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Accounts []common.DerivedAccount `json:"accounts"`
+		Error    string                  `json:"error"`
+	}{
+		Accounts: derived,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export LoadAccountAtPath
+func LoadAccountAtPath(path, password *C.char) *C.char {
+	derived, err := statusAPI.LoadAccountAtPath(C.GoString(path), C.GoString(password))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := common.AccountInfo{
+		Address: derived.Address,
+		PubKey:  derived.PubKey,
+		Error:   errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export ChangePassword
+func ChangePassword(address, oldPassword, newPassword *C.char) *C.char {
+	err := statusAPI.ChangePassword(C.GoString(address), C.GoString(oldPassword), C.GoString(newPassword))
+	return makeJSONResponse(err)
+}
+
+//export VerifyMnemonic
+func VerifyMnemonic(address, mnemonic, password *C.char) *C.char {
+	verified, err := statusAPI.VerifyMnemonic(C.GoString(address), C.GoString(mnemonic), C.GoString(password))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Verified bool   `json:"verified"`
+		Error    string `json:"error"`
+	}{
+		Verified: verified,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export ExportAccount
+func ExportAccount(address, password, exportPassword *C.char) *C.char {
+	keyJSON, err := statusAPI.ExportAccount(C.GoString(address), C.GoString(password), C.GoString(exportPassword))
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+
+	return C.CString(keyJSON)
+}
+
+//export ImportAccount
+func ImportAccount(keyJSON, password, newPassword *C.char) *C.char {
+	address, err := statusAPI.ImportAccount(C.GoString(keyJSON), C.GoString(password), C.GoString(newPassword))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := common.AccountInfo{
+		Address: address,
+		Error:   errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export ExportKeystoreBundle
+func ExportKeystoreBundle(exportPassword *C.char) *C.char {
+	bundle, err := statusAPI.ExportKeystoreBundle(C.GoString(exportPassword))
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+
+	return C.CString(bundle)
+}
+
+//export ImportKeystoreBundle
+func ImportKeystoreBundle(bundle, exportPassword *C.char) *C.char {
+	imported, err := statusAPI.ImportKeystoreBundle(C.GoString(bundle), C.GoString(exportPassword))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Addresses []string `json:"addresses"`
+		Error     string   `json:"error"`
+	}{
+		Addresses: imported,
+		Error:     errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export AddWatchOnlyAccount
+func AddWatchOnlyAccount(address, name *C.char) *C.char {
+	err := statusAPI.AddWatchOnlyAccount(C.GoString(address), C.GoString(name))
+	return makeJSONResponse(err)
+}
+
+//export RemoveWatchOnlyAccount
+func RemoveWatchOnlyAccount(address *C.char) *C.char {
+	err := statusAPI.RemoveWatchOnlyAccount(C.GoString(address))
+	return makeJSONResponse(err)
+}
+
+//export WatchOnlyAccounts
+func WatchOnlyAccounts() *C.char {
+	accounts, err := statusAPI.WatchOnlyAccounts()
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Accounts []common.WatchOnlyAccount `json:"accounts"`
+		Error    string                    `json:"error"`
+	}{
+		Accounts: accounts,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
 //export VerifyAccountPassword
 func VerifyAccountPassword(keyStoreDir, address, password *C.char) *C.char {
 	_, err := statusAPI.VerifyAccountPassword(C.GoString(keyStoreDir), C.GoString(address), C.GoString(password))
@@ -205,6 +411,145 @@ func Login(address, password *C.char) *C.char {
 	return makeJSONResponse(err)
 }
 
+//export AddSelectedAccount
+func AddSelectedAccount(address, password *C.char) *C.char {
+	err := statusAPI.AddSelectedAccount(C.GoString(address), C.GoString(password))
+	return makeJSONResponse(err)
+}
+
+//export RemoveSelectedAccount
+func RemoveSelectedAccount(address *C.char) *C.char {
+	err := statusAPI.RemoveSelectedAccount(C.GoString(address))
+	return makeJSONResponse(err)
+}
+
+//export InjectWhisperIdentity
+func InjectWhisperIdentity(address *C.char) *C.char {
+	err := statusAPI.InjectWhisperIdentity(C.GoString(address))
+	return makeJSONResponse(err)
+}
+
+//export RemoveWhisperIdentity
+func RemoveWhisperIdentity(address *C.char) *C.char {
+	err := statusAPI.RemoveWhisperIdentity(C.GoString(address))
+	return makeJSONResponse(err)
+}
+
+//export WhisperIdentities
+func WhisperIdentities() *C.char {
+	identities, err := statusAPI.WhisperIdentities()
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Identities []common.WhisperIdentity `json:"identities"`
+		Error      string                   `json:"error"`
+	}{
+		Identities: identities,
+		Error:      errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export DeriveContactKey
+func DeriveContactKey(contactPubKey *C.char) *C.char {
+	key, err := statusAPI.DeriveContactKey(C.GoString(contactPubKey))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		ContactPubKey string `json:"contactPubKey"`
+		PubKey        string `json:"pubkey"`
+		KeyID         string `json:"keyId"`
+		Error         string `json:"error"`
+	}{
+		ContactPubKey: key.ContactPubKey,
+		PubKey:        key.PubKey,
+		KeyID:         key.KeyID,
+		Error:         errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export SetAccountMetadata
+func SetAccountMetadata(metadataJSON *C.char) *C.char {
+	var metadata common.AccountMetadata
+	if err := json.Unmarshal([]byte(C.GoString(metadataJSON)), &metadata); err != nil {
+		return makeJSONResponse(err)
+	}
+	err := statusAPI.AccountsMetadata().Set(metadata)
+	return makeJSONResponse(err)
+}
+
+//export AccountMetadata
+func AccountMetadata(address *C.char) *C.char {
+	metadata, err := statusAPI.AccountsMetadata().Get(C.GoString(address))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Metadata common.AccountMetadata `json:"metadata"`
+		Error    string                 `json:"error"`
+	}{
+		Metadata: metadata,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export AccountsMetadata
+func AccountsMetadata() *C.char {
+	list, err := statusAPI.AccountsMetadata().List()
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Metadata []common.AccountMetadata `json:"metadata"`
+		Error    string                   `json:"error"`
+	}{
+		Metadata: list,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export DeleteAccountMetadata
+func DeleteAccountMetadata(address *C.char) *C.char {
+	err := statusAPI.AccountsMetadata().Delete(C.GoString(address))
+	return makeJSONResponse(err)
+}
+
+//export BindAccount
+func BindAccount(origin, address *C.char) *C.char {
+	err := statusAPI.BindAccount(C.GoString(origin), C.GoString(address))
+	return makeJSONResponse(err)
+}
+
+//export UnbindAccount
+func UnbindAccount(origin *C.char) {
+	statusAPI.UnbindAccount(C.GoString(origin))
+}
+
 //export Logout
 func Logout() *C.char {
 	// This is equivalent to clearing whisper identities
@@ -274,6 +619,155 @@ func CompleteTransactions(ids, password *C.char) *C.char {
 	return C.CString(string(outBytes))
 }
 
+//export TransactionHistory
+func TransactionHistory(address, pageJSON, filterJSON *C.char) *C.char {
+	var page txhistory.Page
+	if err := json.Unmarshal([]byte(C.GoString(pageJSON)), &page); err != nil {
+		return makeJSONResponse(err)
+	}
+	var filter txhistory.Filter
+	if err := json.Unmarshal([]byte(C.GoString(filterJSON)), &filter); err != nil {
+		return makeJSONResponse(err)
+	}
+	result, err := statusAPI.TransactionHistory(C.GoString(address), page, filter)
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		History txhistory.Result `json:"history"`
+		Error   string           `json:"error"`
+	}{
+		History: result,
+		Error:   errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export Tokens
+func Tokens() *C.char {
+	registry, err := statusAPI.Tokens()
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Tokens []tokens.Token `json:"tokens"`
+		Error  string         `json:"error"`
+	}{
+		Tokens: registry,
+		Error:  errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export UpdateTokens
+func UpdateTokens(tokensJSON *C.char) *C.char {
+	var registry []tokens.Token
+	if err := json.Unmarshal([]byte(C.GoString(tokensJSON)), &registry); err != nil {
+		return makeJSONResponse(err)
+	}
+
+	return makeJSONResponse(statusAPI.UpdateTokens(registry))
+}
+
+//export TokenBalances
+func TokenBalances(address, tokensJSON *C.char) *C.char {
+	var tokenAddresses []string
+	if err := json.Unmarshal([]byte(C.GoString(tokensJSON)), &tokenAddresses); err != nil {
+		return makeJSONResponse(err)
+	}
+	balances, err := statusAPI.TokenBalances(C.GoString(address), tokenAddresses)
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Balances map[gethcommon.Address]*hexutil.Big `json:"balances"`
+		Error    string                              `json:"error"`
+	}{
+		Balances: balances,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export QueueTokenTransfer
+func QueueTokenTransfer(token, to, amount *C.char) *C.char {
+	value, ok := math.ParseBig256(C.GoString(amount))
+	if !ok {
+		return makeJSONResponse(fmt.Errorf("invalid amount: %s", C.GoString(amount)))
+	}
+	id, err := statusAPI.QueueTokenTransfer(C.GoString(token), C.GoString(to), value)
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	}{
+		ID:    string(id),
+		Error: errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
+//export SpeedUpTransaction
+func SpeedUpTransaction(hash, gasPrice, password *C.char) *C.char {
+	price, ok := math.ParseBig256(C.GoString(gasPrice))
+	if !ok {
+		return makeJSONResponse(fmt.Errorf("invalid gas price: %s", C.GoString(gasPrice)))
+	}
+	newHash, err := statusAPI.SpeedUpTransaction(gethcommon.HexToHash(C.GoString(hash)), price, C.GoString(password))
+
+	return makeReplaceTransactionResponse(newHash, err)
+}
+
+//export CancelTransaction
+func CancelTransaction(hash, password *C.char) *C.char {
+	newHash, err := statusAPI.CancelTransaction(gethcommon.HexToHash(C.GoString(hash)), C.GoString(password))
+
+	return makeReplaceTransactionResponse(newHash, err)
+}
+
+// makeReplaceTransactionResponse returns hash of transaction, pending transaction has been replaced with.
+func makeReplaceTransactionResponse(hash gethcommon.Hash, err error) *C.char {
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Hash  string `json:"hash"`
+		Error string `json:"error"`
+	}{
+		Error: errString,
+	}
+	if err == nil {
+		out.Hash = hash.Hex()
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
 //export DiscardTransaction
 func DiscardTransaction(id *C.char) *C.char {
 	err := statusAPI.DiscardTransaction(common.QueuedTxID(C.GoString(id)))
@@ -297,6 +791,34 @@ func DiscardTransaction(id *C.char) *C.char {
 	return C.CString(string(outBytes))
 }
 
+//export ApproveSignRequest
+func ApproveSignRequest(id, password *C.char) *C.char {
+	signature, err := statusAPI.ApproveSignRequest(common.SignRequestID(C.GoString(id)), C.GoString(password))
+
+	out := common.ApproveSignRequestResult{
+		ID: C.GoString(id),
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		out.Error = err.Error()
+	} else {
+		out.Signature = signature.String()
+	}
+	outBytes, err := json.Marshal(&out)
+	if err != nil {
+		log.Error("failed to marshal ApproveSignRequest output", "error", err.Error())
+		return makeJSONResponse(err)
+	}
+
+	return C.CString(string(outBytes))
+}
+
+//export DiscardSignRequest
+func DiscardSignRequest(id *C.char) *C.char {
+	err := statusAPI.DiscardSignRequest(common.SignRequestID(C.GoString(id)))
+	return makeJSONResponse(err)
+}
+
 //export DiscardTransactions
 func DiscardTransactions(ids *C.char) *C.char {
 	out := common.DiscardTransactionsResult{}
@@ -334,11 +856,59 @@ func DiscardTransactions(ids *C.char) *C.char {
 	return C.CString(string(outBytes))
 }
 
+//export PendingTransactions
+func PendingTransactions() *C.char {
+	outBytes, _ := json.Marshal(statusAPI.PendingTransactions())
+	return C.CString(string(outBytes))
+}
+
+//export EstimateTransaction
+func EstimateTransaction(argsJSON *C.char) *C.char {
+	var args common.SendTxArgs
+	if err := json.Unmarshal([]byte(C.GoString(argsJSON)), &args); err != nil {
+		return makeJSONResponse(err)
+	}
+	estimate, err := statusAPI.EstimateTransaction(args)
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		Estimate common.TxEstimate `json:"estimate"`
+		Error    string            `json:"error"`
+	}{
+		Estimate: estimate,
+		Error:    errString,
+	}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
 //export InitJail
 func InitJail(js *C.char) {
 	statusAPI.JailBaseJS(C.GoString(js))
 }
 
+//export ReloadJail
+func ReloadJail(js *C.char) *C.char {
+	err := statusAPI.JailReloadBaseJS(C.GoString(js))
+	return makeJSONResponse(err)
+}
+
+//export JailParseVersion
+func JailParseVersion() C.ulonglong {
+	return C.ulonglong(statusAPI.JailParseVersion())
+}
+
+//export RegisterJailModule
+func RegisterJailModule(name, js *C.char) *C.char {
+	err := statusAPI.JailRegisterModule(C.GoString(name), C.GoString(js))
+	return makeJSONResponse(err)
+}
+
 //export Parse
 func Parse(chatID *C.char, js *C.char) *C.char {
 	res := statusAPI.JailParse(C.GoString(chatID), C.GoString(js))
@@ -351,6 +921,56 @@ func Call(chatID *C.char, path *C.char, params *C.char) *C.char {
 	return C.CString(res)
 }
 
+//export CallAsync
+func CallAsync(chatID *C.char, path *C.char, params *C.char) *C.char {
+	callID := statusAPI.JailCallAsync(C.GoString(chatID), C.GoString(path), C.GoString(params))
+	return C.CString(callID)
+}
+
+//export RemoveCell
+func RemoveCell(chatID *C.char) *C.char {
+	err := statusAPI.JailRemoveCell(C.GoString(chatID))
+	return makeJSONResponse(err)
+}
+
+//export Cells
+func Cells() *C.char {
+	outBytes, _ := json.Marshal(statusAPI.JailCells())
+	return C.CString(string(outBytes))
+}
+
+//export CellLogs
+func CellLogs(chatID *C.char, n C.int) *C.char {
+	outBytes, _ := json.Marshal(statusAPI.JailCellLogs(C.GoString(chatID), int(n)))
+	return C.CString(string(outBytes))
+}
+
+//export CellStorage
+func CellStorage(chatID *C.char) *C.char {
+	items, err := statusAPI.JailStorage(C.GoString(chatID))
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	outBytes, _ := json.Marshal(items)
+	return C.CString(string(outBytes))
+}
+
+//export ClearCellStorage
+func ClearCellStorage(chatID *C.char) *C.char {
+	err := statusAPI.JailClearStorage(C.GoString(chatID))
+	return makeJSONResponse(err)
+}
+
+//export SetCellPermissions
+func SetCellPermissions(chatID, permissionsJSON *C.char) *C.char {
+	var permissions *common.JailPermissions
+	if err := json.Unmarshal([]byte(C.GoString(permissionsJSON)), &permissions); err != nil {
+		return makeJSONResponse(err)
+	}
+	statusAPI.JailSetPermissions(C.GoString(chatID), permissions)
+	return makeJSONResponse(nil)
+}
+
 //export StartCPUProfile
 func StartCPUProfile(dataDir *C.char) *C.char {
 	err := profiling.StartCPUProfile(C.GoString(dataDir))
@@ -369,6 +989,12 @@ func WriteHeapProfile(dataDir *C.char) *C.char {
 	return makeJSONResponse(err)
 }
 
+//export BuildInfo
+func BuildInfo() *C.char {
+	outBytes, _ := json.Marshal(statusAPI.BuildInfo())
+	return C.CString(string(outBytes))
+}
+
 func makeJSONResponse(err error) *C.char {
 	errString := ""
 	if err != nil {
@@ -384,6 +1010,24 @@ func makeJSONResponse(err error) *C.char {
 	return C.CString(string(outBytes))
 }
 
+//export ParseDeepLink
+func ParseDeepLink(link *C.char) *C.char {
+	action, err := statusAPI.ParseDeepLink(C.GoString(link))
+
+	errString := ""
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		errString = err.Error()
+	}
+
+	out := struct {
+		*deeplink.Action
+		Error string `json:"error"`
+	}{action, errString}
+	outBytes, _ := json.Marshal(&out)
+	return C.CString(string(outBytes))
+}
+
 //export Notify
 func Notify(token *C.char) *C.char {
 	res := statusAPI.Notify(C.GoString(token))
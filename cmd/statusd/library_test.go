@@ -70,3 +70,7 @@ func TestValidateNodeConfig(t *testing.T) {
 		testValidateNodeConfig(t, tc.Config, tc.Callback)
 	}
 }
+
+func TestEncryptConfig(t *testing.T) {
+	testEncryptConfig(t)
+}
@@ -7,42 +7,48 @@ import (
 	"runtime"
 
 	"github.com/status-im/status-go/geth/api"
+	"github.com/status-im/status-go/geth/node"
 	"github.com/status-im/status-go/geth/params"
 	"gopkg.in/urfave/cli.v1"
 )
 
 var (
-	gitCommit  = "rely on linker: -ldflags -X main.GitCommit"
-	buildStamp = "rely on linker: -ldflags -X main.buildStamp"
-	app        = makeApp(gitCommit)
-	statusAPI  = api.NewStatusAPI()
+	app = makeApp()
+
+	// statusAPI leaves interrupt signals to host application, when status-go is built as a library,
+	// statusd replaces it with the one handling them (see main)
+	statusAPI = api.NewStatusAPI(node.WithoutSignalHandler())
 )
 
 var (
 	// ProdModeFlag is whether we need dev or production settings
 	ProdModeFlag = cli.BoolFlag{
-		Name:  "production",
-		Usage: "Whether production settings should be loaded",
+		Name:   "production",
+		Usage:  "Whether production settings should be loaded",
+		EnvVar: "STATUSD_PRODUCTION",
 	}
 
 	// NodeKeyFileFlag is a node key file to be used as node's private key
 	NodeKeyFileFlag = cli.StringFlag{
-		Name:  "nodekey",
-		Usage: "P2P node key file (private key)",
+		Name:   "nodekey",
+		Usage:  "P2P node key file (private key)",
+		EnvVar: "STATUSD_NODEKEYFILE",
 	}
 
 	// DataDirFlag defines data directory for the node
 	DataDirFlag = cli.StringFlag{
-		Name:  "datadir",
-		Usage: "Data directory for the databases and keystore",
-		Value: params.DataDir,
+		Name:   "datadir",
+		Usage:  "Data directory for the databases and keystore",
+		EnvVar: "STATUSD_DATADIR",
+		Value:  params.DataDir,
 	}
 
 	// NetworkIDFlag defines network ID
 	NetworkIDFlag = cli.IntFlag{
-		Name:  "networkid",
-		Usage: "Network identifier (integer, 1=Homestead, 3=Ropsten, 4=Rinkeby)",
-		Value: params.RopstenNetworkID,
+		Name:   "networkid",
+		Usage:  "Network identifier (integer, 1=Homestead, 3=Ropsten, 4=Rinkeby)",
+		EnvVar: "STATUSD_NETWORKID",
+		Value:  params.RopstenNetworkID,
 	}
 
 	// LightEthEnabledFlag flags whether LES is enabled or not
@@ -84,16 +90,18 @@ var (
 
 	// LogLevelFlag defines a log reporting level
 	LogLevelFlag = cli.StringFlag{
-		Name:  "log",
-		Usage: `Log level, one of: "ERROR", "WARN", "INFO", "DEBUG", and "TRACE"`,
-		Value: "",
+		Name:   "log",
+		Usage:  `Log level, one of: "ERROR", "WARN", "INFO", "DEBUG", and "TRACE"`,
+		EnvVar: "STATUSD_LOGLEVEL",
+		Value:  "",
 	}
 
 	// LogFileFlag defines a log filename
 	LogFileFlag = cli.StringFlag{
-		Name:  "logfile",
-		Usage: `Path to the log file`,
-		Value: "",
+		Name:   "logfile",
+		Usage:  `Path to the log file`,
+		EnvVar: "STATUSD_LOGFILE",
+		Value:  "",
 	}
 )
 
@@ -106,6 +114,9 @@ func init() {
 		faucetCommand,
 		lesCommand,
 		wnodeCommand,
+		configCommand,
+		txCommand,
+		accountsCommand,
 	}
 	app.Flags = []cli.Flag{
 		ProdModeFlag,
@@ -114,7 +125,11 @@ func init() {
 		NetworkIDFlag,
 		LogLevelFlag,
 		LogFileFlag,
+		PIDFileFlag,
+		HealthAddrFlag,
+		DrainPeriodFlag,
 	}
+	app.Flags = append(app.Flags, configFlags...)
 	app.Before = func(ctx *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
 		return nil
@@ -125,6 +140,8 @@ func init() {
 }
 
 func main() {
+	statusAPI = api.NewStatusAPI()
+
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -132,16 +149,13 @@ func main() {
 }
 
 // makeApp creates an app with sane defaults.
-func makeApp(gitCommit string) *cli.App {
+func makeApp() *cli.App {
 	app := cli.NewApp()
 	app.Name = filepath.Base(os.Args[0])
 	app.Author = ""
 	//app.Authors = nil
 	app.Email = ""
-	app.Version = params.Version
-	if gitCommit != "" {
-		app.Version += "-" + gitCommit[:8]
-	}
+	app.Version = params.VersionWithCommit()
 	app.Usage = "CLI for Status nodes management"
 	return app
 }
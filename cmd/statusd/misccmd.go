@@ -22,12 +22,17 @@ var (
 func versionCommandHandler(ctx *cli.Context) error {
 	fmt.Println(strings.Title(params.ClientIdentifier))
 	fmt.Println("Version:", params.Version)
-	if gitCommit != "" {
-		fmt.Println("Git Commit:", gitCommit)
+	build := params.Build()
+	if build.GitCommit != "" {
+		fmt.Println("Git Commit:", build.GitCommit)
 	}
-	if buildStamp != "" {
-		fmt.Println("Build Stamp:", buildStamp)
+	if build.BuildTime != "" {
+		fmt.Println("Build Time:", build.BuildTime)
 	}
+	if len(build.Features) > 0 {
+		fmt.Println("Features:", strings.Join(build.Features, ", "))
+	}
+	fmt.Println("Geth Version:", build.GethVersion)
 
 	fmt.Println("Network Id:", ctx.GlobalInt(NetworkIDFlag.Name))
 	fmt.Println("Go Version:", runtime.Version())
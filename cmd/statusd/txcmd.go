@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	// ABIFlag is a path to contract ABI (JSON), calldata is decoded against
+	ABIFlag = cli.StringFlag{
+		Name:  "abi",
+		Usage: "Path to contract ABI (JSON), used to decode method and arguments of calldata",
+	}
+
+	txCommand = cli.Command{
+		Name:  "tx",
+		Usage: "Transaction utilities",
+		Subcommands: []cli.Command{
+			{
+				Action:    txDecodeCommandHandler,
+				Name:      "decode",
+				Usage:     "Decode raw (RLP encoded) transaction or calldata",
+				ArgsUsage: "<hex>",
+				Flags:     []cli.Flag{ABIFlag},
+			},
+		},
+	}
+)
+
+// errors
+var (
+	ErrTxBlobMissing   = errors.New("hex encoded transaction or calldata is expected")
+	ErrUnknownSelector = errors.New("method selector is not found in ABI")
+)
+
+// txDecodeCommandHandler handles `statusd tx decode` command
+func txDecodeCommandHandler(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return ErrTxBlobMissing
+	}
+	blob, err := hexutil.Decode(strings.TrimSpace(ctx.Args().First()))
+	if err != nil {
+		return fmt.Errorf("can not parse hex: %v", err)
+	}
+
+	var contractABI *abi.ABI
+	if path := ctx.String(ABIFlag.Name); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+
+		parsed, err := abi.JSON(f)
+		if err != nil {
+			return fmt.Errorf("can not parse ABI: %v", err)
+		}
+		contractABI = &parsed
+	}
+
+	return decodeTx(os.Stdout, blob, contractABI)
+}
+
+// decodeTx prints fields of RLP encoded transaction, and its calldata decoded against ABI.
+// Blob, which is not a transaction, is decoded as calldata.
+func decodeTx(w io.Writer, blob []byte, contractABI *abi.ABI) error {
+	data := blob
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(blob, tx); err == nil {
+		if err := printTx(w, tx); err != nil {
+			return err
+		}
+		data = tx.Data()
+	}
+
+	if contractABI == nil || len(data) == 0 {
+		return nil
+	}
+
+	return printCalldata(w, data, contractABI)
+}
+
+// printTx prints fields of transaction, including EIP-155 ones.
+func printTx(w io.Writer, tx *types.Transaction) error {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+	}
+
+	to := "contract creation"
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	from := "unknown"
+	if sender, err := types.Sender(signer, tx); err == nil {
+		from = sender.Hex()
+	}
+	v, r, s := tx.RawSignatureValues()
+
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{"Hash", tx.Hash().Hex()},
+		{"From", from},
+		{"To", to},
+		{"Nonce", tx.Nonce()},
+		{"Value", tx.Value()},
+		{"Gas", tx.Gas()},
+		{"GasPrice", tx.GasPrice()},
+		{"EIP155", tx.Protected()},
+		{"ChainID", tx.ChainId()},
+		{"V", v},
+		{"R", hexutil.EncodeBig(r)},
+		{"S", hexutil.EncodeBig(s)},
+		{"Data", hexutil.Encode(tx.Data())},
+	}
+	for _, field := range fields {
+		if _, err := fmt.Fprintf(w, "%-10s %v\n", field.name+":", field.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printCalldata prints method, which calldata selector belongs to, and its arguments.
+func printCalldata(w io.Writer, data []byte, contractABI *abi.ABI) error {
+	if len(data) < 4 {
+		return ErrUnknownSelector
+	}
+
+	var method *abi.Method
+	for _, m := range contractABI.Methods {
+		if bytes.Equal(m.Id(), data[:4]) {
+			m := m
+			method = &m
+			break
+		}
+	}
+	if method == nil {
+		return fmt.Errorf("%v: %s", ErrUnknownSelector, hexutil.Encode(data[:4]))
+	}
+
+	if _, err := fmt.Fprintf(w, "%-10s %s\n", "Method:", method.Sig()); err != nil {
+		return err
+	}
+
+	args, err := unpackInputs(method, data[4:])
+	if err != nil {
+		return fmt.Errorf("can not decode arguments of %s: %v", method.Name, err)
+	}
+	for i, arg := range args {
+		name := method.Inputs[i].Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		if _, err := fmt.Fprintf(w, "  %s %s: %v\n", method.Inputs[i].Type, name, formatArg(arg)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unpackInputs decodes arguments of method. ABI package only unpacks outputs,
+// so a method with inputs as its outputs is unpacked instead.
+func unpackInputs(method *abi.Method, data []byte) ([]interface{}, error) {
+	switch len(method.Inputs) {
+	case 0:
+		return nil, nil
+	case 1:
+		var arg interface{}
+		if err := inputsABI(method).Unpack(&arg, method.Name, data); err != nil {
+			return nil, err
+		}
+		return []interface{}{arg}, nil
+	default:
+		var args []interface{}
+		if err := inputsABI(method).Unpack(&args, method.Name, data); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+}
+
+func inputsABI(method *abi.Method) abi.ABI {
+	return abi.ABI{
+		Methods: map[string]abi.Method{
+			method.Name: {Name: method.Name, Outputs: method.Inputs},
+		},
+	}
+}
+
+// formatArg prints addresses, byte arrays and slices as hex, other values as is.
+func formatArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	}
+
+	// fixed size byte arrays (addresses are decoded as [20]byte)
+	value := reflect.ValueOf(arg)
+	if value.Kind() == reflect.Array && value.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(b), value)
+		return hexutil.Encode(b)
+	}
+
+	return fmt.Sprintf("%v", arg)
+}
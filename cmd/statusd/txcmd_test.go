@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenABI = `[{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"type":"function"},
+{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"}]`
+
+func TestTxDecode(t *testing.T) {
+	tokenABI, err := abi.JSON(strings.NewReader(testTokenABI))
+	require.NoError(t, err)
+
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	calldata, err := tokenABI.Pack("transfer", recipient, big.NewInt(1000))
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	token := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	tx := types.NewTransaction(7, token, big.NewInt(0), big.NewInt(90000), big.NewInt(20), calldata)
+	tx, err = types.SignTx(tx, types.NewEIP155Signer(big.NewInt(3)), key)
+	require.NoError(t, err)
+	raw, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, decodeTx(&out, raw, &tokenABI))
+	require.Contains(t, out.String(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+	require.Contains(t, out.String(), "EIP155:    true")
+	require.Contains(t, out.String(), "ChainID:   3")
+	require.Contains(t, out.String(), "Method:    transfer(address,uint256)")
+	require.Contains(t, out.String(), "address to: "+strings.ToLower(recipient.Hex()))
+	require.Contains(t, out.String(), "uint256 value: 1000")
+
+	// calldata alone is decoded too
+	out.Reset()
+	calldata, err = tokenABI.Pack("balanceOf", recipient)
+	require.NoError(t, err)
+	require.NoError(t, decodeTx(&out, calldata, &tokenABI))
+	require.Equal(t, "Method:    balanceOf(address)\n  address owner: "+strings.ToLower(recipient.Hex())+"\n", out.String())
+
+	// unknown selector
+	require.Error(t, decodeTx(&out, []byte{1, 2, 3, 4}, &tokenABI))
+}
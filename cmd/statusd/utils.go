@@ -1399,3 +1399,29 @@ func testValidateNodeConfig(t *testing.T, config string, fn func(common.APIDetai
 
 	fn(resp)
 }
+
+func testEncryptConfig(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "encrypt-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	config := `{
+		"NetworkId": 3,
+		"DataDir": "` + tmpDir + `",
+		"UpstreamConfig": {"URL": "https://ropsten.example/secret"}
+	}`
+	encrypted := C.GoString(EncryptConfig(C.CString(config)))
+	require.NotContains(t, encrypted, "secret")
+
+	var nodeConfig params.NodeConfig
+	require.NoError(t, json.Unmarshal([]byte(encrypted), &nodeConfig))
+	require.True(t, nodeConfig.HasEncryptedSecrets())
+
+	// encrypted config is accepted by StartNode, which decrypts it with key of the device
+	testValidateNodeConfig(t, encrypted, func(resp common.APIDetailedResponse) {
+		require.True(t, resp.Status, resp.Message)
+	})
+	decrypted, err := params.LoadNodeConfig(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "https://ropsten.example/secret", decrypted.UpstreamConfig.URL)
+}
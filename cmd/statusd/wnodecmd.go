@@ -148,14 +148,7 @@ func wnode(ctx *cli.Context) error {
 		}
 	}
 
-	// wait till node has been stopped
-	node, err := statusAPI.NodeManager().Node()
-	if err != nil {
-		return nil
-	}
-	node.Wait()
-
-	return nil
+	return serveNode(ctx)
 }
 
 // wnodePrintHeader prints command header
@@ -235,6 +228,9 @@ func makeWhisperNodeConfig(ctx *cli.Context) (*params.NodeConfig, error) {
 	}
 	nodeConfig.HTTPPort = ctx.Int(HTTPPortFlag.Name)
 
+	// explicitly set config flags (or environment variables) take precedence
+	applyConfigFlags(ctx, nodeConfig)
+
 	return nodeConfig, nil
 }
 
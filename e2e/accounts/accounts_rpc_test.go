@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -30,12 +31,13 @@ func (s *AccountsTestSuite) TestRPCEthAccounts() {
 	s.NotNil(rpcClient)
 
 	expectedResponse := `{"jsonrpc":"2.0","id":1,"result":["` + strings.ToLower(TestConfig.Account1.Address) + `"]}`
-	resp := rpcClient.CallRaw(`{
+	resp, err := rpcClient.CallRaw(context.Background(), `{
 		"jsonrpc": "2.0",
 		"id": 1,
 		"method": "eth_accounts",
 		"params": []
     }`)
+	s.NoError(err)
 	s.Equal(expectedResponse, resp)
 }
 
@@ -54,11 +56,12 @@ func (s *AccountsTestSuite) TestRPCEthAccountsWithUpstream() {
 	s.NotNil(rpcClient)
 
 	expectedResponse := `{"jsonrpc":"2.0","id":1,"result":["` + strings.ToLower(TestConfig.Account1.Address) + `"]}`
-	resp := rpcClient.CallRaw(`{
+	resp, err := rpcClient.CallRaw(context.Background(), `{
     	"jsonrpc": "2.0",
     	"id": 1,
     	"method": "eth_accounts",
     	"params": []
     }`)
+	s.NoError(err)
 	s.Equal(expectedResponse, resp)
 }
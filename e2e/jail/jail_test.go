@@ -49,7 +49,8 @@ func (s *JailTestSuite) TestInit() {
 
 	// create VM (w/o properly initializing base JS script)
 	err = errors.New("ReferenceError: '_status_catalog' is not defined")
-	s.Equal(errorWrapper(err), s.jail.Parse(testChatID, ``))
+	s.Equal(`{"error":"`+err.Error()+`","stack":["catalog.js:1:30"],"file":"catalog.js","line":1,"column":30}`,
+		s.jail.Parse(testChatID, ``))
 	err = errors.New("ReferenceError: 'call' is not defined")
 	s.Equal(errorWrapper(err), s.jail.Call(testChatID, `["commands", "testCommand"]`, `{"val": 12}`))
 
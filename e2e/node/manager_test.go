@@ -328,6 +328,37 @@ func (s *ManagerTestSuite) TestRestartNode() {
 	s.Equal("0x6341fd3daf94b748c72ced5a5b26028f2474f5f00d824504e4fa37a75767e177", firstHash)
 }
 
+func (s *ManagerTestSuite) TestNodeKeyImportExport() {
+	s.StartTestNode(params.RinkebyNetworkID)
+	defer s.StopTestNode()
+
+	nodeID, err := s.NodeManager.NodeID()
+	s.NoError(err)
+	exportedKey, err := s.NodeManager.ExportNodeKey()
+	s.NoError(err)
+
+	// invalid key is rejected, and node keeps running
+	_, err = s.NodeManager.ImportNodeKey("0xdeadbeef")
+	s.Equal(node.ErrInvalidNodeKey, err)
+	s.True(s.NodeManager.IsNodeRunning())
+
+	// rotated key results in a new identity
+	nodeReady, err := s.NodeManager.RotateNodeKey()
+	s.NoError(err)
+	<-nodeReady
+	rotatedID, err := s.NodeManager.NodeID()
+	s.NoError(err)
+	s.NotEqual(nodeID, rotatedID)
+
+	// previous identity can be restored from exported key
+	nodeReady, err = s.NodeManager.ImportNodeKey(exportedKey)
+	s.NoError(err)
+	<-nodeReady
+	restoredID, err := s.NodeManager.NodeID()
+	s.NoError(err)
+	s.Equal(nodeID, restoredID)
+}
+
 // TODO(adam): race conditions should be tested with -race flag and unit tests, if possible.
 // Research if it's possible to do the same with unit tests.
 func (s *ManagerTestSuite) TestRaceConditions() {
@@ -111,7 +111,7 @@ func (s *RPCTestSuite) TestCallRPC() {
 			wg.Add(1)
 			go func(r rpcCall) {
 				defer wg.Done()
-				resultJSON := rpcClient.CallRaw(r.inputJSON)
+				resultJSON, _ := rpcClient.CallRaw(context.Background(), r.inputJSON)
 				r.validator(resultJSON)
 			}(r)
 		}
@@ -146,7 +146,8 @@ func (s *RPCTestSuite) TestCallRawResult() {
 	client := s.NodeManager.RPCClient()
 	s.NotNil(client)
 
-	jsonResult := client.CallRaw(`{"jsonrpc":"2.0","method":"shh_version","params":[],"id":67}`)
+	jsonResult, err := client.CallRaw(context.Background(), `{"jsonrpc":"2.0","method":"shh_version","params":[],"id":67}`)
+	s.NoError(err)
 	s.Equal(`{"jsonrpc":"2.0","id":67,"result":"5.0"}`, jsonResult)
 
 	s.NodeManager.StopNode()
@@ -351,7 +351,7 @@ func (s *WhisperJailTestSuite) TestJailWhisper() {
 
 			filter, err := cell.Get("filter")
 			s.NoError(err, "cannot get filter")
-			filterID, err := filter.Object().Get("filterId")
+			filterID, err := filter.Get("filterId")
 			s.NoError(err, "cannot get filterId")
 
 			// FilterID is not assigned yet.
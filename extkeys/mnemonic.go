@@ -140,6 +140,27 @@ func (m *Mnemonic) MnemonicPhrase(strength, language Language) (string, error) {
 		return "", err
 	}
 
+	return m.entropyPhrase(entropy, wordList, language), nil
+}
+
+// EntropyPhrase returns a human readable seed encoding a given entropy, which must be
+// a multiple of 32 bits, 128-256 bits long. The same entropy always yields the same phrase,
+// which is meant for deterministic accounts (e.g. in tests), rather than for real ones.
+func (m *Mnemonic) EntropyPhrase(entropy []byte, language Language) (string, error) {
+	wordList, err := m.WordList(language)
+	if err != nil {
+		return "", err
+	}
+
+	if len(entropy)%4 > 0 || len(entropy) < 16 || len(entropy) > 32 {
+		return "", errors.New("The mnemonic must encode entropy in a multiple of 32 bits, The recommended size of ENT is 128-256 bits")
+	}
+
+	return m.entropyPhrase(entropy, wordList, language), nil
+}
+
+func (m *Mnemonic) entropyPhrase(entropy []byte, wordList *WordList, language Language) string {
+	strength := len(entropy) * 8
 	entropyBigInt := new(big.Int).SetBytes(entropy)
 
 	// A checksum is generated by taking the first bits of its SHA256 hash ( ENT / 32 )
@@ -187,7 +208,7 @@ func (m *Mnemonic) MnemonicPhrase(strength, language Language) (string, error) {
 		words[i] = wordList[binary.BigEndian.Uint16(wordBytes)]
 	}
 
-	return strings.Join(words, wordSeperator), nil
+	return strings.Join(words, wordSeperator)
 }
 
 // ValidMnemonic validates mnemonic string
@@ -1,6 +1,7 @@
 package extkeys_test
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -86,3 +87,34 @@ func (v *Vector) String() string {
 	return fmt.Sprintf("{salt: %s, password: %s, input: %s, mnemonic: %s, seed: %s, xprv: %s}",
 		v.salt, v.password, v.input, v.mnemonic, v.seed, v.xprv)
 }
+
+// TestEntropyPhrase
+func TestEntropyPhrase(t *testing.T) {
+	vectorsFile, err := LoadVectorsFile("mnemonic_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mnemonic := extkeys.NewMnemonic(extkeys.Salt)
+	for _, vector := range vectorsFile.vectors {
+		if vector.language != "english" {
+			continue
+		}
+
+		entropy, err := hex.DecodeString(vector.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		phrase, err := mnemonic.EntropyPhrase(entropy, extkeys.EnglishLanguage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if phrase != vector.mnemonic {
+			t.Errorf("Test failed: incorrect phrase (%s) encoded (expected: %s)", phrase, vector.mnemonic)
+		}
+	}
+
+	if _, err := mnemonic.EntropyPhrase(make([]byte, 15), extkeys.EnglishLanguage); err == nil {
+		t.Error("Test failed: entropy of invalid length is accepted")
+	}
+}
@@ -16,8 +16,12 @@ import (
 	"github.com/status-im/status-go/extkeys"
 	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/signal"
 )
 
+// EventAccountLoggedOut is triggered when selected account is logged out, and its keys are scrubbed from memory
+const EventAccountLoggedOut = "account.logged_out"
+
 // errors
 var (
 	ErrAddressToAccountMappingFailure  = errors.New("cannot retrieve a valid account for a given address")
@@ -28,16 +32,25 @@ var (
 	ErrInvalidMasterKeyCreated         = errors.New("can not create master extended key")
 )
 
+// LoggedOutEvent is a signal sent on logout
+type LoggedOutEvent struct {
+	Address string `json:"address"` // address of the main selected account, "0x0" if none has been selected
+}
+
 // Manager represents account manager interface
 type Manager struct {
 	nodeManager     common.NodeManager
 	selectedAccount *common.SelectedExtKey // account that was processed during the last call to SelectAccount()
+	selection       *selection             // accounts selected along with the main one, and their bindings
+	metadata        *MetadataStore         // labels and avatars of accounts, and address book
 }
 
 // NewManager returns new node account manager
 func NewManager(nodeManager common.NodeManager) *Manager {
 	return &Manager{
 		nodeManager: nodeManager,
+		selection:   newSelection(),
+		metadata:    NewMetadataStore(nodeManager),
 	}
 }
 
@@ -59,8 +72,8 @@ func (m *Manager) CreateAccount(password string) (address, pubKey, mnemonic stri
 		return "", "", "", fmt.Errorf("can not create master extended key: %v", err)
 	}
 
-	// import created key into account keystore
-	address, pubKey, err = m.importExtendedKey(extKey, password)
+	// import created key into account keystore, master key is kept for further derivations
+	address, pubKey, err = m.importMasterKey(extKey, password)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -135,8 +148,8 @@ func (m *Manager) RecoverAccount(password, mnemonic string) (address, pubKey str
 		return "", "", ErrInvalidMasterKeyCreated
 	}
 
-	// import re-created key into account keystore
-	address, pubKey, err = m.importExtendedKey(extKey, password)
+	// import re-created key into account keystore, master key is kept for further derivations
+	address, pubKey, err = m.importMasterKey(extKey, password)
 	if err != nil {
 		return
 	}
@@ -204,7 +217,8 @@ func (m *Manager) VerifyAccountPassword(keyStoreDir, address, password string) (
 
 // SelectAccount selects current account, by verifying that address has corresponding account which can be decrypted
 // using provided password. Once verification is done, decrypted key is injected into Whisper (as a single identity,
-// all previous identities are removed).
+// all previous identities are removed). Identities can be managed independently afterwards,
+// see InjectWhisperIdentity and RemoveWhisperIdentity.
 func (m *Manager) SelectAccount(address, password string) error {
 	keyStore, err := m.nodeManager.AccountKeyStore()
 	if err != nil {
@@ -218,6 +232,9 @@ func (m *Manager) SelectAccount(address, password string) error {
 
 	account, accountKey, err := keyStore.AccountDecryptedKey(account, password)
 	if err != nil {
+		if m.IsWatchOnlyAccount(address) {
+			return ErrWatchOnlyAccount
+		}
 		return fmt.Errorf("%s: %v", ErrAccountToKeyMappingFailure.Error(), err)
 	}
 
@@ -235,11 +252,16 @@ func (m *Manager) SelectAccount(address, password string) error {
 	if err != nil {
 		return err
 	}
+	subAccounts = append(subAccounts, m.derivedAccounts(account.Address)...)
 	m.selectedAccount = &common.SelectedExtKey{
 		Address:     account.Address,
 		AccountKey:  accountKey,
 		SubAccounts: subAccounts,
 	}
+	m.selection.reset() // accounts selected along with the previous one are forgotten
+	m.selection.mu.Lock()
+	m.selection.identities[account.Address] = true
+	m.selection.mu.Unlock()
 
 	return nil
 }
@@ -253,26 +275,27 @@ func (m *Manager) SelectedAccount() (*common.SelectedExtKey, error) {
 }
 
 // ReSelectAccount selects previously selected account, often, after node restart.
+// Whisper identities, injected before, are injected again.
 func (m *Manager) ReSelectAccount() error {
-	selectedAccount := m.selectedAccount
-	if selectedAccount == nil {
+	if m.selectedAccount == nil {
 		return nil
 	}
 
-	whisperService, err := m.nodeManager.WhisperService()
-	if err != nil {
-		return err
-	}
-
-	if err := whisperService.SelectKeyPair(selectedAccount.AccountKey.PrivateKey); err != nil {
-		return ErrWhisperIdentityInjectionFailure
-	}
-
-	return nil
+	return m.reinjectWhisperIdentities()
 }
 
-// Logout clears whisper identities
+// Logout locks the wallet: decrypted keys of selected accounts are scrubbed from memory, selected account
+// is reset, and whisper identities are cleared. EventAccountLoggedOut is sent, once it's done.
 func (m *Manager) Logout() error {
+	address := m.selectedAccount.Hex()
+
+	// keys are scrubbed first, so that wallet is locked even if whisper is not available
+	if m.selectedAccount != nil {
+		scrubKey(m.selectedAccount.AccountKey)
+	}
+	m.selection.scrub()
+	m.selectedAccount = nil
+
 	whisperService, err := m.nodeManager.WhisperService()
 	if err != nil {
 		return err
@@ -283,7 +306,10 @@ func (m *Manager) Logout() error {
 		return fmt.Errorf("%s: %v", ErrWhisperClearIdentitiesFailure, err)
 	}
 
-	m.selectedAccount = nil
+	signal.Send(signal.Envelope{
+		Type:  EventAccountLoggedOut,
+		Event: LoggedOutEvent{Address: address},
+	})
 
 	return nil
 }
@@ -313,8 +339,27 @@ func (m *Manager) importExtendedKey(extKey *extkeys.ExtendedKey, password string
 	return
 }
 
+// encryptKey encrypts key with password, using KDF of keystore (see params.KeyStoreConfig).
+// Light scrypt parameters are used, if node has no keystore configuration.
+func (m *Manager) encryptKey(key *keystore.Key, password string) ([]byte, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.KeyStoreConfig == nil {
+		return keystore.EncryptKey(key, password, keystore.LightScryptN, keystore.LightScryptP)
+	}
+
+	if argon2Params := config.KeyStoreConfig.Argon2Params(); argon2Params != nil {
+		return keystore.EncryptKeyArgon2(key, password, *argon2Params)
+	}
+	scryptN, scryptP := config.KeyStoreConfig.ScryptParams()
+
+	return keystore.EncryptKey(key, password, scryptN, scryptP)
+}
+
 // Accounts returns list of addresses for selected account, including
-// subaccounts.
+// subaccounts and watch-only accounts.
 func (m *Manager) Accounts() ([]gethcommon.Address, error) {
 	am, err := m.nodeManager.AccountManager()
 	if err != nil {
@@ -349,13 +394,30 @@ func (m *Manager) Accounts() ([]gethcommon.Address, error) {
 		}
 	}
 
+	// accounts selected along with the main one
+	for _, account := range m.addedAccounts() {
+		if !containsAddress(filtered, account) {
+			filtered = append(filtered, account)
+		}
+	}
+
+	// watch-only accounts can be picked for queries (e.g. of balance), but they can't sign anything
+	watchOnly, err := m.WatchOnlyAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range watchOnly {
+		filtered = append(filtered, gethcommon.HexToAddress(account.Address))
+	}
+
 	return filtered, nil
 }
 
 // AccountsRPCHandler returns RPC Handler for the Accounts() method.
+// If account is bound to origin of request (see BindAccount), only that account is returned.
 func (m *Manager) AccountsRPCHandler() rpc.Handler {
-	return func(context.Context, ...interface{}) (interface{}, error) {
-		return m.Accounts()
+	return func(ctx context.Context, _ ...interface{}) (interface{}, error) {
+		return m.accountsOf(ctx)
 	}
 }
 
@@ -375,6 +437,7 @@ func (m *Manager) refreshSelectedAccount() {
 	if err != nil {
 		return
 	}
+	subAccounts = append(subAccounts, m.derivedAccounts(m.selectedAccount.Address)...)
 	m.selectedAccount = &common.SelectedExtKey{
 		Address:     m.selectedAccount.Address,
 		AccountKey:  m.selectedAccount.AccountKey,
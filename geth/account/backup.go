@@ -0,0 +1,318 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/progress"
+	"golang.org/x/crypto/scrypt"
+)
+
+// keystoreBundleVersion is a version of format of keystore bundles
+const keystoreBundleVersion = 1
+
+// scrypt parameters of keystore bundles, except N and P (the same as of account keys)
+const (
+	bundleScryptR     = 8
+	bundleScryptDKLen = 32
+)
+
+// errors
+var (
+	ErrAccountExists         = errors.New("account already exists")
+	ErrInvalidKeystoreBundle = errors.New("invalid keystore bundle")
+)
+
+// keystoreBundle is an encrypted backup of the whole key store directory.
+type keystoreBundle struct {
+	Version    int           `json:"version"`
+	KDFParams  bundleKDF     `json:"kdfparams"`
+	Nonce      hexutil.Bytes `json:"nonce"`
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+}
+
+type bundleKDF struct {
+	N    int           `json:"n"`
+	R    int           `json:"r"`
+	P    int           `json:"p"`
+	Salt hexutil.Bytes `json:"salt"`
+}
+
+// bundleFile is a file of key store directory (key file or HD wallet metadata), as is.
+// Key files stay encrypted with passwords of their accounts.
+type bundleFile struct {
+	Path string          `json:"path"` // relative to key store directory
+	Data json.RawMessage `json:"data"`
+}
+
+// ExportAccount returns key of account identified by address in standard V3 JSON format,
+// encrypted with exportPassword instead of password of account.
+func (m *Manager) ExportAccount(address, password, exportPassword string) (string, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	key, err := m.VerifyAccountPassword(config.KeyStoreDir, address, password)
+	if err != nil {
+		return "", err
+	}
+
+	keyJSON, err := m.encryptKey(key, exportPassword)
+	if err != nil {
+		return "", err
+	}
+
+	return string(keyJSON), nil
+}
+
+// ImportAccount imports key in V3 JSON format, encrypted with password (see ExportAccount),
+// into keystore. Imported key is encrypted with newPassword.
+func (m *Manager) ImportAccount(keyJSON, password, newPassword string) (address string, err error) {
+	keyStore, err := m.nodeManager.AccountKeyStore()
+	if err != nil {
+		return "", err
+	}
+
+	key, err := keystore.DecryptKey([]byte(keyJSON), password)
+	if err != nil {
+		return "", err
+	}
+	if keyStore.HasAddress(key.Address) {
+		return "", ErrAccountExists
+	}
+
+	account, err := keyStore.Import([]byte(keyJSON), password, newPassword)
+	if err != nil {
+		return "", err
+	}
+
+	return account.Address.Hex(), nil
+}
+
+// ExportKeystoreBundle returns backup of all accounts of keystore (along with their HD wallets),
+// encrypted with exportPassword. Keys in bundle stay encrypted with passwords of their accounts.
+// Progress is reported with signals (see progress.OperationKeystoreExport).
+func (m *Manager) ExportKeystoreBundle(exportPassword string) (string, error) {
+	tracker := progress.NewTracker(progress.OperationKeystoreExport)
+
+	bundle, err := m.exportKeystoreBundle(exportPassword, tracker)
+	if err != nil {
+		tracker.Fail(err)
+		return "", err
+	}
+	tracker.Done()
+
+	return bundle, nil
+}
+
+// ImportKeystoreBundle restores accounts from bundle (see ExportKeystoreBundle), encrypted with exportPassword.
+// Accounts, which are already in keystore, are left as is. Addresses of imported accounts are returned.
+// Progress is reported with signals (see progress.OperationKeystoreImport).
+func (m *Manager) ImportKeystoreBundle(bundle, exportPassword string) ([]string, error) {
+	tracker := progress.NewTracker(progress.OperationKeystoreImport)
+
+	imported, err := m.importKeystoreBundle(bundle, exportPassword, tracker)
+	if err != nil {
+		tracker.Fail(err)
+		return imported, err
+	}
+	tracker.Done()
+
+	return imported, nil
+}
+
+func (m *Manager) exportKeystoreBundle(exportPassword string, tracker *progress.Tracker) (string, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	var paths []string
+	err = filepath.Walk(config.KeyStoreDir, func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fileInfo.IsDir() && !skipKeystoreFile(fileInfo.Name()) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot traverse key store folder: %v", err)
+	}
+
+	files := make([]bundleFile, 0, len(paths))
+	for i, path := range paths {
+		tracker.Update("files", uint64(i), uint64(len(paths)))
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		var raw json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			log.Warn("non-JSON file is skipped in keystore bundle", "path", path)
+			continue
+		}
+		relPath, err := filepath.Rel(config.KeyStoreDir, path)
+		if err != nil {
+			return "", err
+		}
+		files = append(files, bundleFile{Path: filepath.ToSlash(relPath), Data: data})
+	}
+
+	plaintext, err := json.Marshal(files)
+	if err != nil {
+		return "", err
+	}
+	bundle, err := encryptBundle(plaintext, exportPassword)
+	if err != nil {
+		return "", err
+	}
+
+	return string(bundle), nil
+}
+
+func (m *Manager) importKeystoreBundle(bundle, exportPassword string, tracker *progress.Tracker) ([]string, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	keyStore, err := m.nodeManager.AccountKeyStore()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := decryptBundle([]byte(bundle), exportPassword)
+	if err != nil {
+		return nil, err
+	}
+	var files []bundleFile
+	if err := json.Unmarshal(plaintext, &files); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidKeystoreBundle, err)
+	}
+
+	imported := make([]string, 0)
+	for i, file := range files {
+		tracker.Update("files", uint64(i), uint64(len(files)))
+
+		relPath := filepath.Clean(filepath.FromSlash(file.Path))
+		if filepath.IsAbs(relPath) || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return imported, fmt.Errorf("%v: invalid path %s", ErrInvalidKeystoreBundle, file.Path)
+		}
+		path := filepath.Join(config.KeyStoreDir, relPath)
+
+		var keyFile struct {
+			Address string `json:"address"`
+		}
+		if err := json.Unmarshal(file.Data, &keyFile); err != nil {
+			return imported, fmt.Errorf("%v: %v", ErrInvalidKeystoreBundle, err)
+		}
+		address := gethcommon.HexToAddress(keyFile.Address)
+		if keyFile.Address != "" && keyStore.HasAddress(address) {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return imported, err
+		}
+		if err := ioutil.WriteFile(path, file.Data, 0600); err != nil {
+			return imported, err
+		}
+		if keyFile.Address != "" {
+			imported = append(imported, address.Hex())
+		}
+	}
+
+	return imported, nil
+}
+
+// skipKeystoreFile returns true for editor backups and hidden files, which are ignored by keystore.
+func skipKeystoreFile(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~")
+}
+
+// encryptBundle encrypts plaintext with AES-GCM, key is derived from password with scrypt.
+func encryptBundle(plaintext []byte, password string) ([]byte, error) {
+	kdf := bundleKDF{
+		N:    keystore.LightScryptN,
+		R:    bundleScryptR,
+		P:    keystore.LightScryptP,
+		Salt: make([]byte, 32),
+	}
+	if _, err := rand.Read(kdf.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newBundleGCM(kdf, password)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(keystoreBundle{
+		Version:    keystoreBundleVersion,
+		KDFParams:  kdf,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+}
+
+// decryptBundle returns plaintext of bundle, keystore.ErrDecrypt is returned if password is wrong.
+func decryptBundle(data []byte, password string) ([]byte, error) {
+	var bundle keystoreBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidKeystoreBundle, err)
+	}
+	if bundle.Version != keystoreBundleVersion {
+		return nil, fmt.Errorf("%v: unsupported version %d", ErrInvalidKeystoreBundle, bundle.Version)
+	}
+
+	gcm, err := newBundleGCM(bundle.KDFParams, password)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%v: invalid nonce", ErrInvalidKeystoreBundle)
+	}
+
+	plaintext, err := gcm.Open(nil, bundle.Nonce, bundle.Ciphertext, nil)
+	if err != nil {
+		return nil, keystore.ErrDecrypt
+	}
+
+	return plaintext, nil
+}
+
+func newBundleGCM(kdf bundleKDF, password string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), kdf.Salt, kdf.N, kdf.R, kdf.P, bundleScryptDKLen)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidKeystoreBundle, err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,178 @@
+package account_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/progress"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestManager returns account manager of node with key store in dir.
+func newTestManager(t *testing.T, ctrl *gomock.Controller, dir string) *account.Manager {
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP), nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	return account.NewManager(nodeManager)
+}
+
+func TestExportImportAccount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	otherDir, err := ioutil.TempDir("", "import")
+	require.NoError(t, err)
+	defer os.RemoveAll(otherDir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	acctManager := newTestManager(t, ctrl, dir)
+	address, _, _, err := acctManager.CreateAccount("password")
+	require.NoError(t, err)
+
+	_, err = acctManager.ExportAccount(address, "wrong password", "export")
+	require.Equal(t, keystore.ErrDecrypt, err)
+
+	keyJSON, err := acctManager.ExportAccount(address, "password", "export")
+	require.NoError(t, err)
+	require.Contains(t, keyJSON, `"version":3`)
+	_, err = keystore.DecryptKey([]byte(keyJSON), "password")
+	require.Equal(t, keystore.ErrDecrypt, err)
+
+	_, err = acctManager.ImportAccount(keyJSON, "export", "password")
+	require.Equal(t, account.ErrAccountExists, err)
+
+	otherManager := newTestManager(t, ctrl, otherDir)
+	_, err = otherManager.ImportAccount(keyJSON, "wrong password", "new password")
+	require.Equal(t, keystore.ErrDecrypt, err)
+	imported, err := otherManager.ImportAccount(keyJSON, "export", "new password")
+	require.NoError(t, err)
+	require.Equal(t, address, imported)
+
+	key, err := otherManager.VerifyAccountPassword(otherDir, imported, "new password")
+	require.NoError(t, err)
+	require.NotNil(t, key.ExtendedKey) // sub-accounts can still be derived
+}
+
+func TestArgon2Keystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "argon2")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	otherDir, err := ioutil.TempDir("", "scrypt")
+	require.NoError(t, err)
+	defer os.RemoveAll(otherDir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	argon2Params := keystore.Argon2Params{Time: 1, Memory: 1024, Threads: 1}
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keystore.NewKeyStoreWithArgon2(dir, argon2Params), nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		KeyStoreDir: dir,
+		KeyStoreConfig: &params.KeyStoreConfig{
+			KDF:           params.KeyStoreKDFArgon2id,
+			Argon2Time:    argon2Params.Time,
+			Argon2Memory:  argon2Params.Memory,
+			Argon2Threads: argon2Params.Threads,
+		},
+	}, nil).AnyTimes()
+	acctManager := account.NewManager(nodeManager)
+
+	address, _, _, err := acctManager.CreateAccount("password")
+	require.NoError(t, err)
+	key, err := acctManager.VerifyAccountPassword(dir, address, "password")
+	require.NoError(t, err)
+	require.Equal(t, address, key.Address.Hex())
+
+	keyJSON, err := acctManager.ExportAccount(address, "password", "export")
+	require.NoError(t, err)
+	require.Contains(t, keyJSON, `"kdf":"argon2id"`)
+
+	// keys encrypted with Argon2id are imported into scrypt keystore
+	otherManager := newTestManager(t, ctrl, otherDir)
+	imported, err := otherManager.ImportAccount(keyJSON, "export", "password")
+	require.NoError(t, err)
+	require.Equal(t, address, imported)
+	_, err = otherManager.VerifyAccountPassword(otherDir, imported, "password")
+	require.NoError(t, err)
+}
+
+func TestKeystoreBundle(t *testing.T) {
+	var mu sync.Mutex
+	events := map[string][]progress.Event{}
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event progress.Event
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		mu.Lock()
+		events[envelope.Event.Operation] = append(events[envelope.Event.Operation], envelope.Event)
+		mu.Unlock()
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	dir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	otherDir, err := ioutil.TempDir("", "import")
+	require.NoError(t, err)
+	defer os.RemoveAll(otherDir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	acctManager := newTestManager(t, ctrl, dir)
+	address1, _, _, err := acctManager.CreateAccount("password1")
+	require.NoError(t, err)
+	address2, _, _, err := acctManager.CreateAccount("password2")
+	require.NoError(t, err)
+
+	bundle, err := acctManager.ExportKeystoreBundle("export")
+	require.NoError(t, err)
+	require.False(t, strings.Contains(bundle, strings.ToLower(address1[2:])), "bundle must be encrypted")
+
+	otherManager := newTestManager(t, ctrl, otherDir)
+	_, err = otherManager.ImportKeystoreBundle(bundle, "wrong password")
+	require.Equal(t, keystore.ErrDecrypt, err)
+	_, err = otherManager.ImportKeystoreBundle(`{"version": 2}`, "export")
+	require.Error(t, err)
+
+	imported, err := otherManager.ImportKeystoreBundle(bundle, "export")
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	require.Contains(t, imported, address1)
+	require.Contains(t, imported, address2)
+
+	// keys stay encrypted with passwords of their accounts
+	_, err = otherManager.VerifyAccountPassword(otherDir, address1, "password1")
+	require.NoError(t, err)
+	_, err = otherManager.VerifyAccountPassword(otherDir, address2, "password2")
+	require.NoError(t, err)
+
+	// existing files are not overwritten
+	imported, err = otherManager.ImportKeystoreBundle(bundle, "export")
+	require.NoError(t, err)
+	require.Empty(t, imported)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, operation := range []string{progress.OperationKeystoreExport, progress.OperationKeystoreImport} {
+		require.NotEmpty(t, events[operation], operation)
+	}
+	exported := events[progress.OperationKeystoreExport]
+	require.Equal(t, progress.PhaseDone, exported[len(exported)-1].Phase)
+	require.Equal(t, progress.PhaseFailed, events[progress.OperationKeystoreImport][0].Phase) // wrong password
+}
@@ -0,0 +1,76 @@
+package account
+
+import (
+	"encoding/binary"
+	"errors"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/extkeys"
+	"github.com/status-im/status-go/geth/common"
+)
+
+// contactKeysRoot is a hardened child of extended key of account, contact keys are derived under.
+// Sub-accounts (see CreateChildAccount) are non-hardened children of the same key, so they never collide.
+const contactKeysRoot = extkeys.HardenedKeyStart + 0x636b // "ck"
+
+// errors
+var (
+	ErrInvalidContactPubKey = errors.New("invalid public key of contact")
+	ErrNoExtendedKey        = errors.New("selected account has no extended key, contact keys can not be derived")
+)
+
+// DeriveContactKey derives key of selected account, dedicated to a contact identified by its public key
+// (hex-encoded, uncompressed), and adds it into Whisper, so that pairwise encrypted sessions with the contact
+// can be built on top of it. Key is deterministic: the same key is derived for the same account and contact,
+// so it can be re-derived at any time (e.g. after account is re-selected, and Whisper keys are reset).
+func (m *Manager) DeriveContactKey(contactPubKey string) (common.ContactKey, error) {
+	if m.selectedAccount == nil {
+		return common.ContactKey{}, ErrNoAccountSelected
+	}
+
+	contactKey := crypto.ToECDSAPub(gethcommon.FromHex(contactPubKey))
+	if contactKey == nil || contactKey.X == nil {
+		return common.ContactKey{}, ErrInvalidContactPubKey
+	}
+
+	extKey := m.selectedAccount.AccountKey.ExtendedKey
+	if extKey == nil || extKey.String() == extkeys.EmptyExtendedKeyString {
+		return common.ContactKey{}, ErrNoExtendedKey
+	}
+
+	childKey, err := extKey.Derive(contactKeyPath(crypto.FromECDSAPub(contactKey)))
+	if err != nil {
+		return common.ContactKey{}, err
+	}
+	defer scrubExtendedKey(childKey)
+
+	whisperService, err := m.nodeManager.WhisperService()
+	if err != nil {
+		return common.ContactKey{}, err
+	}
+	// key is kept by Whisper, so it's not scrubbed
+	privateKey := childKey.ToECDSA()
+	keyID, err := whisperService.AddKeyPair(privateKey)
+	if err != nil {
+		return common.ContactKey{}, ErrWhisperIdentityInjectionFailure
+	}
+
+	return common.ContactKey{
+		ContactPubKey: gethcommon.ToHex(crypto.FromECDSAPub(contactKey)),
+		PubKey:        gethcommon.ToHex(crypto.FromECDSAPub(&privateKey.PublicKey)),
+		KeyID:         keyID,
+	}, nil
+}
+
+// contactKeyPath returns derivation path of contact key, relative to extended key of account:
+// two hardened indexes, taken from hash of contact's public key, are appended to contactKeysRoot.
+func contactKeyPath(contactPubKey []byte) []uint32 {
+	hash := crypto.Keccak256(contactPubKey)
+
+	return []uint32{
+		contactKeysRoot,
+		extkeys.HardenedKeyStart | binary.BigEndian.Uint32(hash[0:4]),
+		extkeys.HardenedKeyStart | binary.BigEndian.Uint32(hash[4:8]),
+	}
+}
@@ -0,0 +1,64 @@
+package account
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveContactKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "contactkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	whisperService := whisper.New(nil)
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	main, mainPubKey, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+	_, contactPubKey, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+	_, otherContactPubKey, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+
+	_, err = manager.DeriveContactKey(contactPubKey)
+	require.Equal(t, ErrNoAccountSelected, err)
+	require.NoError(t, manager.SelectAccount(main, "password"))
+	_, err = manager.DeriveContactKey("0x1234")
+	require.Equal(t, ErrInvalidContactPubKey, err)
+
+	key, err := manager.DeriveContactKey(contactPubKey)
+	require.NoError(t, err)
+	require.Equal(t, contactPubKey, key.ContactPubKey)
+	require.NotEqual(t, mainPubKey, key.PubKey)
+	require.True(t, whisperService.HasKeyPair(key.KeyID))
+
+	// keys of different contacts differ
+	otherKey, err := manager.DeriveContactKey(otherContactPubKey)
+	require.NoError(t, err)
+	require.NotEqual(t, key.PubKey, otherKey.PubKey)
+
+	// the same key is derived again, once keys of Whisper are reset
+	require.NoError(t, whisperService.DeleteKeyPairs())
+	require.NoError(t, manager.SelectAccount(main, "password"))
+	require.False(t, whisperService.HasKeyPair(key.KeyID))
+	derivedAgain, err := manager.DeriveContactKey(contactPubKey)
+	require.NoError(t, err)
+	require.Equal(t, key, derivedAgain)
+	require.True(t, whisperService.HasKeyPair(key.KeyID))
+}
@@ -0,0 +1,100 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/extkeys"
+	"github.com/status-im/status-go/geth/common"
+)
+
+const (
+	// MaxGeneratedAccounts limits number of accounts generated at once
+	MaxGeneratedAccounts = 1000
+
+	// faucetTimeout is a timeout of a single faucet request
+	faucetTimeout = 30 * time.Second
+)
+
+// errors
+var (
+	ErrInvalidAccountsCount = fmt.Errorf("number of accounts must be between 1 and %d", MaxGeneratedAccounts)
+	ErrEmptySeed            = errors.New("seed of generated accounts is empty")
+)
+
+// GenerateAccounts creates count throwaway accounts, protected with a given password, for load tests and
+// QA environments. Accounts are deterministic: mnemonic of every account is derived from seed and index
+// of account, so that the same accounts are generated (or recovered) for the same seed.
+func (m *Manager) GenerateAccounts(seed string, count int, password string) ([]common.GeneratedAccount, error) {
+	if count < 1 || count > MaxGeneratedAccounts {
+		return nil, ErrInvalidAccountsCount
+	}
+	if seed == "" {
+		return nil, ErrEmptySeed
+	}
+
+	mn := extkeys.NewMnemonic(extkeys.Salt)
+	generated := make([]common.GeneratedAccount, 0, count)
+	for i := 0; i < count; i++ {
+		mnemonic, err := mn.EntropyPhrase(generatedEntropy(seed, i), extkeys.EnglishLanguage)
+		if err != nil {
+			return generated, err
+		}
+
+		address, pubKey, err := m.RecoverAccount(password, mnemonic)
+		if err != nil {
+			return generated, err
+		}
+
+		generated = append(generated, common.GeneratedAccount{
+			Index:    i,
+			Address:  address,
+			PubKey:   pubKey,
+			Mnemonic: mnemonic,
+		})
+	}
+
+	return generated, nil
+}
+
+// generatedEntropy returns 128 bits of entropy of mnemonic of account with a given index.
+func generatedEntropy(seed string, index int) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("%s/%d", seed, index)))[:16]
+}
+
+// FundAccount requests Ether for a given address from faucet at URL, in which "{address}" is replaced
+// with hex-encoded address (or which address is appended to, e.g. "http://faucet.example/donate/").
+func FundAccount(ctx context.Context, faucetURL, address string) error {
+	url := faucetURL
+	if strings.Contains(url, "{address}") {
+		url = strings.Replace(url, "{address}", address, -1)
+	} else {
+		url += address
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, faucetTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("faucet responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
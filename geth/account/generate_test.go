@@ -0,0 +1,73 @@
+package account_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAccounts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "generate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP), nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	acctManager := account.NewManager(nodeManager)
+
+	_, err = acctManager.GenerateAccounts("seed", 0, "password")
+	require.Equal(t, account.ErrInvalidAccountsCount, err)
+	_, err = acctManager.GenerateAccounts("", 1, "password")
+	require.Equal(t, account.ErrEmptySeed, err)
+
+	generated, err := acctManager.GenerateAccounts("seed", 2, "password")
+	require.NoError(t, err)
+	require.Len(t, generated, 2)
+	require.NotEqual(t, generated[0].Address, generated[1].Address)
+
+	// the same seed yields the same accounts, which can be recovered
+	again, err := acctManager.GenerateAccounts("seed", 2, "password")
+	require.NoError(t, err)
+	require.Equal(t, generated, again)
+
+	address, _, err := acctManager.RecoverAccount("password", generated[1].Mnemonic)
+	require.NoError(t, err)
+	require.Equal(t, generated[1].Address, address)
+
+	other, err := acctManager.GenerateAccounts("other", 1, "password")
+	require.NoError(t, err)
+	require.NotEqual(t, generated[0].Address, other[0].Address)
+}
+
+func TestFundAccount(t *testing.T) {
+	var funded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/donate/0xbad" {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		funded = append(funded, r.URL.Path)
+	}))
+	defer server.Close()
+
+	require.NoError(t, account.FundAccount(context.Background(), server.URL+"/donate/", "0x01"))
+	require.NoError(t, account.FundAccount(context.Background(), server.URL+"/donate/{address}", "0x02"))
+	require.Equal(t, []string{"/donate/0x01", "/donate/0x02"}, funded)
+
+	err := account.FundAccount(context.Background(), server.URL+"/donate/", "0xbad")
+	require.EqualError(t, err, "faucet responded with 429 Too Many Requests: rate limited")
+}
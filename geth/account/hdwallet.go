@@ -0,0 +1,383 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/extkeys"
+	"github.com/status-im/status-go/geth/common"
+)
+
+const (
+	// DefaultDerivationPath is BIP44 path of Ethereum accounts, index of account is appended to it
+	DefaultDerivationPath = "m/44'/60'/0'/0"
+
+	// MaxDerivedAccounts limits number of accounts derived at once
+	MaxDerivedAccounts = 100
+
+	// hdWalletsDir is a sub-directory of key store directory, with derivation metadata of accounts
+	hdWalletsDir = "hdwallets"
+)
+
+// errors
+var (
+	ErrInvalidMnemonic       = errors.New("mnemonic phrase is invalid")
+	ErrInvalidDerivationPath = errors.New("invalid derivation path")
+	ErrInvalidDerivedCount   = fmt.Errorf("number of derived accounts must be between 1 and %d", MaxDerivedAccounts)
+	ErrNoHDWallet            = errors.New("account has not been created from mnemonic, no HD wallet found")
+)
+
+// hdWallet is derivation metadata of account, created from mnemonic. It is stored alongside key files,
+// so that more accounts could be derived from the same seed later on.
+type hdWallet struct {
+	Account  gethcommon.Address `json:"account"`  // main account, m/44'/60'/0'/0/0
+	Master   json.RawMessage    `json:"master"`   // master key, encrypted with password of main account
+	Accounts []hdWalletAccount  `json:"accounts"` // accounts derived (and imported) so far
+}
+
+// hdWalletAccount is an account derived from master key of HD wallet.
+type hdWalletAccount struct {
+	Path    string             `json:"path"`
+	Address gethcommon.Address `json:"address"`
+}
+
+// ParseDerivationPath parses BIP32 derivation path, e.g. "m/44'/60'/0'/0/0".
+// Hardened indexes are marked with either ' or h.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) < 2 || components[0] != "m" {
+		return nil, fmt.Errorf("%v: %s", ErrInvalidDerivationPath, path)
+	}
+
+	indexes := make([]uint32, 0, len(components)-1)
+	for _, component := range components[1:] {
+		hardened := strings.HasSuffix(component, "'") || strings.HasSuffix(component, "h")
+		if hardened {
+			component = component[:len(component)-1]
+		}
+
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || index >= extkeys.HardenedKeyStart {
+			return nil, fmt.Errorf("%v: %s", ErrInvalidDerivationPath, path)
+		}
+		if hardened {
+			index += extkeys.HardenedKeyStart
+		}
+		indexes = append(indexes, uint32(index))
+	}
+
+	return indexes, nil
+}
+
+// CreateAccountFromMnemonic creates HD wallet from a given BIP39 mnemonic phrase, protected with password.
+// Main account (m/44'/60'/0'/0/0) is imported into keystore, and other accounts can be derived
+// with DeriveChildAccounts and LoadAccountAtPath, once main account is selected.
+func (m *Manager) CreateAccountFromMnemonic(mnemonic, password string) (address, pubKey string, err error) {
+	mn := extkeys.NewMnemonic(extkeys.Salt)
+	if !mn.ValidMnemonic(mnemonic, extkeys.EnglishLanguage) {
+		return "", "", ErrInvalidMnemonic
+	}
+
+	extKey, err := extkeys.NewMaster(mn.MnemonicSeed(mnemonic, password), []byte(extkeys.Salt))
+	if err != nil {
+		return "", "", ErrInvalidMasterKeyCreated
+	}
+
+	return m.importMasterKey(extKey, password)
+}
+
+// VerifyMnemonic returns true, if mnemonic phrase derives key of account identified by address: either the main
+// account of HD wallet, or one derived from it (see DeriveChildAccounts). Password is required, as it is
+// a passphrase of BIP39 seed. Nothing is imported into keystore.
+func (m *Manager) VerifyMnemonic(address, mnemonic, password string) (bool, error) {
+	keyStore, err := m.nodeManager.AccountKeyStore()
+	if err != nil {
+		return false, err
+	}
+
+	account, err := common.ParseAccountString(address)
+	if err != nil || !keyStore.HasAddress(account.Address) {
+		return false, ErrAddressToAccountMappingFailure
+	}
+
+	mn := extkeys.NewMnemonic(extkeys.Salt)
+	if !mn.ValidMnemonic(mnemonic, extkeys.EnglishLanguage) {
+		return false, ErrInvalidMnemonic
+	}
+
+	masterKey, err := extkeys.NewMaster(mn.MnemonicSeed(mnemonic, password), []byte(extkeys.Salt))
+	if err != nil {
+		return false, ErrInvalidMasterKeyCreated
+	}
+	defer scrubExtendedKey(masterKey)
+
+	// main account is derived the same way, as by keystore.ImportExtendedKey
+	mainKey, err := masterKey.BIP44Child(extkeys.CoinTypeETH, 0)
+	if err != nil {
+		return false, err
+	}
+	defer scrubExtendedKey(mainKey)
+
+	mainAccount := crypto.PubkeyToAddress(mainKey.ToECDSA().PublicKey)
+	if mainAccount == account.Address {
+		return true, nil
+	}
+
+	// account may have been derived from HD wallet of main account
+	wallet, err := m.loadHDWallet(mainAccount)
+	if err == ErrNoHDWallet {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, derived := range wallet.Accounts {
+		if derived.Address != account.Address {
+			continue
+		}
+
+		indexes, err := ParseDerivationPath(derived.Path)
+		if err != nil {
+			return false, err
+		}
+		childKey, err := masterKey.Derive(indexes)
+		if err != nil {
+			return false, err
+		}
+		defer scrubExtendedKey(childKey)
+
+		return crypto.PubkeyToAddress(childKey.ToECDSA().PublicKey) == account.Address, nil
+	}
+
+	return false, nil
+}
+
+// DeriveChildAccounts derives count accounts of HD wallet of selected account, by appending indexes
+// 0..count-1 to path (DefaultDerivationPath, if path is empty). Derived accounts are imported into
+// keystore, protected with password of selected account.
+func (m *Manager) DeriveChildAccounts(path string, count int, password string) ([]common.DerivedAccount, error) {
+	if count < 1 || count > MaxDerivedAccounts {
+		return nil, ErrInvalidDerivedCount
+	}
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+
+	paths := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		paths = append(paths, fmt.Sprintf("%s/%d", strings.TrimSuffix(path, "/"), i))
+	}
+
+	return m.deriveAccounts(paths, password)
+}
+
+// LoadAccountAtPath derives account of HD wallet of selected account at a given path, e.g. "m/44'/60'/0'/0/1".
+// Derived account is imported into keystore, protected with password of selected account.
+func (m *Manager) LoadAccountAtPath(path, password string) (common.DerivedAccount, error) {
+	derived, err := m.deriveAccounts([]string{path}, password)
+	if err != nil {
+		return common.DerivedAccount{}, err
+	}
+
+	return derived[0], nil
+}
+
+// deriveAccounts derives accounts of selected account at given paths, imports them into keystore,
+// and records them in HD wallet metadata.
+func (m *Manager) deriveAccounts(paths []string, password string) ([]common.DerivedAccount, error) {
+	indexes := make([][]uint32, 0, len(paths))
+	for _, path := range paths {
+		path, err := ParseDerivationPath(path)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, path)
+	}
+
+	if m.selectedAccount == nil {
+		return nil, ErrNoAccountSelected
+	}
+
+	wallet, err := m.loadHDWallet(m.selectedAccount.Address)
+	if err != nil {
+		return nil, err
+	}
+	masterKey, err := keystore.DecryptKey(wallet.Master, password)
+	if err != nil {
+		return nil, err
+	}
+	defer scrubKey(masterKey)
+
+	derived := make([]common.DerivedAccount, 0, len(paths))
+	for i, path := range paths {
+		childKey, err := masterKey.ExtendedKey.Derive(indexes[i])
+		if err != nil {
+			return derived, err
+		}
+
+		address, pubKey, err := m.importExtendedKey(childKey, password)
+		if err != nil {
+			return derived, err
+		}
+		wallet.add(path, gethcommon.HexToAddress(address))
+
+		derived = append(derived, common.DerivedAccount{
+			Path:    path,
+			Address: address,
+			PubKey:  pubKey,
+		})
+	}
+
+	if err := m.storeHDWallet(wallet); err != nil {
+		return derived, err
+	}
+	m.refreshSelectedAccount()
+
+	return derived, nil
+}
+
+// importMasterKey imports main account of master key into keystore (see importExtendedKey), and stores
+// master key as HD wallet metadata of that account.
+func (m *Manager) importMasterKey(extKey *extkeys.ExtendedKey, password string) (address, pubKey string, err error) {
+	address, pubKey, err = m.importExtendedKey(extKey, password)
+	if err != nil {
+		return "", "", err
+	}
+
+	key := &keystore.Key{
+		Id:          uuid.NewRandom(),
+		Address:     crypto.PubkeyToAddress(extKey.ToECDSA().PublicKey),
+		PrivateKey:  extKey.ToECDSA(),
+		ExtendedKey: extKey,
+	}
+	master, err := m.encryptKey(key, password)
+	if err != nil {
+		return "", "", err
+	}
+
+	// accounts derived so far are kept, when account is recovered
+	account := gethcommon.HexToAddress(address)
+	wallet, err := m.loadHDWallet(account)
+	if err == ErrNoHDWallet {
+		wallet, err = &hdWallet{Account: account}, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	wallet.Master = master
+
+	if err := m.storeHDWallet(wallet); err != nil {
+		return "", "", err
+	}
+
+	return address, pubKey, nil
+}
+
+// derivedAccounts returns accounts derived from HD wallet of a given account, except the account itself.
+func (m *Manager) derivedAccounts(address gethcommon.Address) []accounts.Account {
+	wallet, err := m.loadHDWallet(address)
+	if err != nil {
+		return nil
+	}
+
+	derived := make([]accounts.Account, 0, len(wallet.Accounts))
+	for _, account := range wallet.Accounts {
+		if account.Address != address {
+			derived = append(derived, accounts.Account{Address: account.Address})
+		}
+	}
+
+	return derived
+}
+
+// hdWalletPath returns path of HD wallet metadata file of a given account.
+func (m *Manager) hdWalletPath(address gethcommon.Address) (string, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(config.KeyStoreDir, hdWalletsDir, strings.ToLower(address.Hex()[2:])+".json"), nil
+}
+
+func (m *Manager) loadHDWallet(address gethcommon.Address) (*hdWallet, error) {
+	path, err := m.hdWalletPath(address)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNoHDWallet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet hdWallet
+	if err := json.Unmarshal(data, &wallet); err != nil {
+		return nil, fmt.Errorf("invalid HD wallet file: %v", err)
+	}
+
+	return &wallet, nil
+}
+
+func (m *Manager) storeHDWallet(wallet *hdWallet) error {
+	path, err := m.hdWalletPath(wallet.Account)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(wallet)
+	if err != nil {
+		return err
+	}
+
+	// written atomically, as master key is replaced when password is changed
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes data to a temporary file, which is renamed to path then, creating its directory
+// if needed. Temporary file is hidden, so it's never picked up as a key file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()           // nolint: errcheck
+		os.Remove(f.Name()) // nolint: errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name()) // nolint: errcheck
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// add records derived account, unless it has been derived already.
+func (w *hdWallet) add(path string, address gethcommon.Address) {
+	for _, account := range w.Accounts {
+		if account.Address == address {
+			return
+		}
+	}
+	w.Accounts = append(w.Accounts, hdWalletAccount{Path: path, Address: address})
+}
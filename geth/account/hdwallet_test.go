@@ -0,0 +1,142 @@
+package account
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/extkeys"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/44'/60'/0'/0/1")
+	require.NoError(t, err)
+	require.Equal(t, []uint32{
+		extkeys.HardenedKeyStart + 44, extkeys.HardenedKeyStart + 60, extkeys.HardenedKeyStart, 0, 1,
+	}, path)
+
+	path, err = ParseDerivationPath("m/44h/0")
+	require.NoError(t, err)
+	require.Equal(t, []uint32{extkeys.HardenedKeyStart + 44, 0}, path)
+
+	for _, invalid := range []string{"", "m", "44'/60'", "m/", "m/-1", "m/x'", "m/2147483648"} {
+		_, err := ParseDerivationPath(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestHDWallet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hdwallet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	_, _, err = manager.CreateAccountFromMnemonic("abandon abandon", "password")
+	require.Equal(t, ErrInvalidMnemonic, err)
+
+	address, _, err := manager.CreateAccountFromMnemonic(testMnemonic, "password")
+	require.NoError(t, err)
+
+	_, err = manager.LoadAccountAtPath("m/44'/60'/0'/0/1", "password")
+	require.Equal(t, ErrNoAccountSelected, err)
+
+	account, accountKey, err := keyStore.AccountDecryptedKey(accounts.Account{Address: gethcommon.HexToAddress(address)}, "password")
+	require.NoError(t, err)
+	manager.selectedAccount = &common.SelectedExtKey{Address: account.Address, AccountKey: accountKey}
+
+	_, err = manager.DeriveChildAccounts("", 0, "password")
+	require.Equal(t, ErrInvalidDerivedCount, err)
+	_, err = manager.DeriveChildAccounts("", 2, "wrong password")
+	require.Equal(t, keystore.ErrDecrypt, err)
+
+	derived, err := manager.DeriveChildAccounts("", 3, "password")
+	require.NoError(t, err)
+	require.Len(t, derived, 3)
+	require.Equal(t, "m/44'/60'/0'/0/0", derived[0].Path)
+	require.Equal(t, address, derived[0].Address) // main account is the first one
+
+	// accounts are derived deterministically, and become accounts of selected account
+	loaded, err := manager.LoadAccountAtPath("m/44'/60'/0'/0/2", "password")
+	require.NoError(t, err)
+	require.Equal(t, derived[2], loaded)
+	require.Len(t, manager.selectedAccount.SubAccounts, 2)
+
+	_, _, err = keyStore.AccountDecryptedKey(accounts.Account{Address: gethcommon.HexToAddress(derived[1].Address)}, "password")
+	require.NoError(t, err)
+
+	// derived accounts are kept, once account is recovered
+	recovered, _, err := manager.RecoverAccount("password", testMnemonic)
+	require.NoError(t, err)
+	require.Equal(t, address, recovered)
+	require.Len(t, manager.derivedAccounts(account.Address), 2)
+
+	// metadata doesn't confuse look up of key files
+	_, err = manager.VerifyAccountPassword(dir, derived[1].Address, "password")
+	require.NoError(t, err)
+}
+
+func TestVerifyMnemonic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mnemonic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	address, _, mnemonic, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+
+	verified, err := manager.VerifyMnemonic(address, mnemonic, "password")
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	// password is BIP39 passphrase, so seed of other password derives other keys
+	verified, err = manager.VerifyMnemonic(address, mnemonic, "wrong password")
+	require.NoError(t, err)
+	require.False(t, verified)
+	verified, err = manager.VerifyMnemonic(address, testMnemonic, "password")
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	_, err = manager.VerifyMnemonic(address, "abandon abandon", "password")
+	require.Equal(t, ErrInvalidMnemonic, err)
+	_, err = manager.VerifyMnemonic("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", mnemonic, "password")
+	require.Equal(t, ErrAddressToAccountMappingFailure, err)
+
+	// accounts derived from HD wallet are verified too
+	account, accountKey, err := keyStore.AccountDecryptedKey(accounts.Account{Address: gethcommon.HexToAddress(address)}, "password")
+	require.NoError(t, err)
+	manager.selectedAccount = &common.SelectedExtKey{Address: account.Address, AccountKey: accountKey}
+	derived, err := manager.LoadAccountAtPath("m/44'/60'/0'/0/5", "password")
+	require.NoError(t, err)
+
+	verified, err = manager.VerifyMnemonic(derived.Address, mnemonic, "password")
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	// nothing is imported into keystore
+	require.Len(t, keyStore.Accounts(), 2)
+}
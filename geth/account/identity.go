@@ -0,0 +1,127 @@
+package account
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/common"
+)
+
+// errors
+var (
+	ErrWhisperIdentityNotInjected = errors.New("whisper identity of account is not injected")
+)
+
+// InjectWhisperIdentity adds key of selected account (the main one, or one added with AddSelectedAccount)
+// into Whisper as chat identity, along with identities injected so far. Wallet key selection is not changed,
+// so chat identity and key, transactions are signed with, can be controlled independently.
+func (m *Manager) InjectWhisperIdentity(address string) error {
+	selected, err := m.SelectedAccountByAddress(address)
+	if err != nil {
+		return err
+	}
+
+	whisperService, err := m.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	if _, err := whisperService.AddKeyPair(selected.AccountKey.PrivateKey); err != nil {
+		return ErrWhisperIdentityInjectionFailure
+	}
+
+	m.selection.mu.Lock()
+	m.selection.identities[selected.Address] = true
+	m.selection.mu.Unlock()
+
+	return nil
+}
+
+// RemoveWhisperIdentity removes chat identity of account from Whisper, account stays selected.
+func (m *Manager) RemoveWhisperIdentity(address string) error {
+	selected, err := m.SelectedAccountByAddress(address)
+	if err != nil {
+		return err
+	}
+
+	m.selection.mu.Lock()
+	injected := m.selection.identities[selected.Address]
+	delete(m.selection.identities, selected.Address)
+	m.selection.mu.Unlock()
+	if !injected {
+		return ErrWhisperIdentityNotInjected
+	}
+
+	whisperService, err := m.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+	whisperService.DeleteKeyPair(whisperIdentity(selected))
+
+	return nil
+}
+
+// WhisperIdentities returns chat identities of selected accounts, injected into Whisper, sorted by address.
+func (m *Manager) WhisperIdentities() ([]common.WhisperIdentity, error) {
+	identities := make([]common.WhisperIdentity, 0)
+	for _, address := range m.injectedIdentities() {
+		selected, err := m.SelectedAccountByAddress(address.Hex())
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, common.WhisperIdentity{
+			Address: address.Hex(),
+			PubKey:  whisperIdentity(selected),
+		})
+	}
+
+	return identities, nil
+}
+
+// reinjectWhisperIdentities injects identities of selected accounts into Whisper (e.g. after node restart),
+// other identities are removed.
+func (m *Manager) reinjectWhisperIdentities() error {
+	whisperService, err := m.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+	if err := whisperService.DeleteKeyPairs(); err != nil {
+		return ErrWhisperClearIdentitiesFailure
+	}
+
+	for _, address := range m.injectedIdentities() {
+		selected, err := m.SelectedAccountByAddress(address.Hex())
+		if err != nil {
+			return err
+		}
+		if _, err := whisperService.AddKeyPair(selected.AccountKey.PrivateKey); err != nil {
+			return ErrWhisperIdentityInjectionFailure
+		}
+	}
+
+	return nil
+}
+
+// injectedIdentities returns addresses of accounts, identities of which are injected into Whisper, sorted.
+func (m *Manager) injectedIdentities() []gethcommon.Address {
+	m.selection.mu.RLock()
+	defer m.selection.mu.RUnlock()
+
+	addresses := make([]gethcommon.Address, 0, len(m.selection.identities))
+	for address := range m.selection.identities {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+
+	return addresses
+}
+
+// whisperIdentity returns public key of account, which identifies it in Whisper.
+func whisperIdentity(account *common.SelectedExtKey) string {
+	return gethcommon.ToHex(crypto.FromECDSAPub(&account.AccountKey.PrivateKey.PublicKey))
+}
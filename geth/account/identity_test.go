@@ -0,0 +1,75 @@
+package account
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhisperIdentities(t *testing.T) {
+	dir, err := ioutil.TempDir("", "identities")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	whisperService := whisper.New(nil)
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	main, mainPubKey, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+	other, otherPubKey, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+
+	// identity of account can't be injected, until account is selected
+	require.Equal(t, ErrNoAccountSelected, manager.InjectWhisperIdentity(main))
+	require.NoError(t, manager.SelectAccount(main, "password"))
+	require.Equal(t, ErrAccountNotSelected, manager.InjectWhisperIdentity(other))
+	require.NoError(t, manager.AddSelectedAccount(other, "password"))
+
+	// main account is injected by SelectAccount, other one is added independently
+	require.False(t, whisperService.HasKeyPair(otherPubKey))
+	require.NoError(t, manager.InjectWhisperIdentity(other))
+	identities, err := manager.WhisperIdentities()
+	require.NoError(t, err)
+	require.Len(t, identities, 2)
+	require.True(t, whisperService.HasKeyPair(mainPubKey))
+	require.True(t, whisperService.HasKeyPair(otherPubKey))
+
+	// chat identity is switched to other account, while main one still signs transactions
+	require.NoError(t, manager.RemoveWhisperIdentity(main))
+	require.Equal(t, ErrWhisperIdentityNotInjected, manager.RemoveWhisperIdentity(main))
+	identities, err = manager.WhisperIdentities()
+	require.NoError(t, err)
+	require.Equal(t, []common.WhisperIdentity{{Address: other, PubKey: otherPubKey}}, identities)
+	require.False(t, whisperService.HasKeyPair(mainPubKey))
+	selected, err := manager.SelectedAccount()
+	require.NoError(t, err)
+	require.Equal(t, main, selected.Address.Hex())
+
+	// identities are injected again after node restart
+	require.NoError(t, whisperService.DeleteKeyPairs())
+	require.NoError(t, manager.ReSelectAccount())
+	require.False(t, whisperService.HasKeyPair(mainPubKey))
+	require.True(t, whisperService.HasKeyPair(otherPubKey))
+
+	// identity is removed along with deselected account
+	require.NoError(t, manager.RemoveSelectedAccount(other))
+	require.False(t, whisperService.HasKeyPair(otherPubKey))
+	identities, err = manager.WhisperIdentities()
+	require.NoError(t, err)
+	require.Empty(t, identities)
+}
@@ -0,0 +1,183 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/common"
+)
+
+// accountsMetadataFile is a file (in data dir) with labels and avatars of accounts, and address book
+const accountsMetadataFile = "accounts_metadata.json"
+
+// errors
+var (
+	ErrNoAccountMetadata = errors.New("no metadata found for a given address")
+)
+
+// MetadataStore keeps labels and avatars of accounts, and address book contacts, in a file in data dir
+// of the node, so that the data is shared by all clients of the node, and survives reinstall of UI layer.
+type MetadataStore struct {
+	nodeManager common.NodeManager
+	mu          sync.Mutex
+}
+
+// NewMetadataStore returns new metadata store, kept in data dir of node of nodeManager.
+func NewMetadataStore(nodeManager common.NodeManager) *MetadataStore {
+	return &MetadataStore{
+		nodeManager: nodeManager,
+	}
+}
+
+// AccountsMetadata returns persistent store of labels and avatars of accounts, and of address book contacts.
+func (m *Manager) AccountsMetadata() common.AccountsMetadata {
+	return m.metadata
+}
+
+// Set adds metadata of address, or replaces existing one.
+func (s *MetadataStore) Set(metadata common.AccountMetadata) error {
+	if !gethcommon.IsHexAddress(metadata.Address) {
+		return ErrAddressToAccountMappingFailure
+	}
+	metadata.Address = gethcommon.HexToAddress(metadata.Address).Hex()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[metadata.Address] = metadata
+
+	return s.store(entries)
+}
+
+// Get returns metadata of address.
+func (s *MetadataStore) Get(address string) (common.AccountMetadata, error) {
+	if !gethcommon.IsHexAddress(address) {
+		return common.AccountMetadata{}, ErrAddressToAccountMappingFailure
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return common.AccountMetadata{}, err
+	}
+	metadata, ok := entries[gethcommon.HexToAddress(address).Hex()]
+	if !ok {
+		return common.AccountMetadata{}, ErrNoAccountMetadata
+	}
+
+	return metadata, nil
+}
+
+// List returns metadata of all addresses, sorted by address.
+func (s *MetadataStore) List() ([]common.AccountMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedMetadata(entries), nil
+}
+
+// Delete removes metadata of address.
+func (s *MetadataStore) Delete(address string) error {
+	if !gethcommon.IsHexAddress(address) {
+		return ErrAddressToAccountMappingFailure
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	address = gethcommon.HexToAddress(address).Hex()
+	if _, ok := entries[address]; !ok {
+		return ErrNoAccountMetadata
+	}
+	delete(entries, address)
+
+	return s.store(entries)
+}
+
+// path returns path of metadata file, which is known once node is configured.
+func (s *MetadataStore) path() (string, error) {
+	config, err := s.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(config.DataDir, accountsMetadataFile), nil
+}
+
+// load reads metadata file, entries are keyed by checksummed address.
+func (s *MetadataStore) load() (map[string]common.AccountMetadata, error) {
+	path, err := s.path()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]common.AccountMetadata)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []common.AccountMetadata
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid accounts metadata file: %v", err)
+	}
+	for _, metadata := range list {
+		entries[metadata.Address] = metadata
+	}
+
+	return entries, nil
+}
+
+// store replaces metadata file atomically, so that it's never left half-written.
+func (s *MetadataStore) store(entries map[string]common.AccountMetadata) error {
+	path, err := s.path()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(sortedMetadata(entries), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// sortedMetadata returns metadata entries, sorted by address.
+func sortedMetadata(entries map[string]common.AccountMetadata) []common.AccountMetadata {
+	list := make([]common.AccountMetadata, 0, len(entries))
+	for _, metadata := range entries {
+		list = append(list, metadata)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Address) < strings.ToLower(list[j].Address)
+	})
+
+	return list
+}
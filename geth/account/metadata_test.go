@@ -0,0 +1,63 @@
+package account
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountsMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metadata")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{DataDir: dir}, nil).AnyTimes()
+	store := NewManager(nodeManager).AccountsMetadata()
+
+	const (
+		account = "0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"
+		contact = "0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2"
+	)
+
+	list, err := store.List()
+	require.NoError(t, err)
+	require.Empty(t, list)
+	_, err = store.Get(account)
+	require.Equal(t, ErrNoAccountMetadata, err)
+	require.Equal(t, ErrAddressToAccountMappingFailure, store.Set(common.AccountMetadata{Address: "0x1"}))
+
+	// addresses are normalized, so metadata is found regardless of case
+	require.NoError(t, store.Set(common.AccountMetadata{Address: strings.ToLower(account), Name: "Main", Avatar: "data:image/png;base64,AA=="}))
+	require.NoError(t, store.Set(common.AccountMetadata{Address: contact, Name: "Alice", Contact: true}))
+	metadata, err := store.Get(strings.ToUpper(account[2:]))
+	require.NoError(t, err)
+	require.Equal(t, common.AccountMetadata{Address: account, Name: "Main", Avatar: "data:image/png;base64,AA=="}, metadata)
+
+	// existing metadata is replaced
+	require.NoError(t, store.Set(common.AccountMetadata{Address: contact, Name: "Bob", Contact: true}))
+
+	// metadata survives re-creation of account manager
+	store = NewManager(nodeManager).AccountsMetadata()
+	list, err = store.List()
+	require.NoError(t, err)
+	require.Equal(t, []common.AccountMetadata{
+		{Address: account, Name: "Main", Avatar: "data:image/png;base64,AA=="},
+		{Address: contact, Name: "Bob", Contact: true},
+	}, list)
+
+	require.NoError(t, store.Delete(contact))
+	require.Equal(t, ErrNoAccountMetadata, store.Delete(contact))
+	list, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
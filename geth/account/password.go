@@ -0,0 +1,72 @@
+package account
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// EventAccountPasswordChanged is triggered when key of account is re-encrypted with a new password
+const EventAccountPasswordChanged = "account.password_changed"
+
+// PasswordChangedEvent is a signal sent once password of account is changed
+type PasswordChangedEvent struct {
+	Address string `json:"address"`
+}
+
+// ChangePassword re-encrypts key file of account identified by address with newPassword, along with master
+// key of its HD wallet (if any). Files are replaced atomically (written to temporary files, then renamed),
+// selected account stays selected. Accounts derived from HD wallet keep their passwords.
+func (m *Manager) ChangePassword(address, oldPassword, newPassword string) error {
+	keyStore, err := m.nodeManager.AccountKeyStore()
+	if err != nil {
+		return err
+	}
+
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return ErrAddressToAccountMappingFailure
+	}
+	account, accountKey, err := keyStore.AccountDecryptedKey(account, oldPassword)
+	if err != nil {
+		return err
+	}
+	scrubKey(accountKey)
+
+	// master key is decrypted before anything is written, so that wrong password changes nothing
+	wallet, err := m.loadHDWallet(account.Address)
+	if err != nil && err != ErrNoHDWallet {
+		return err
+	}
+	var masterKey *keystore.Key
+	if wallet != nil {
+		if masterKey, err = keystore.DecryptKey(wallet.Master, oldPassword); err != nil {
+			return err
+		}
+		defer scrubKey(masterKey)
+		if wallet.Master, err = m.encryptKey(masterKey, newPassword); err != nil {
+			return err
+		}
+	}
+
+	if err := keyStore.Update(account, oldPassword, newPassword); err != nil {
+		return err
+	}
+	if wallet != nil {
+		if err := m.storeHDWallet(wallet); err != nil {
+			// key file is rolled back, so that key and master key stay encrypted with the same password
+			if rollbackErr := keyStore.Update(account, newPassword, oldPassword); rollbackErr != nil {
+				log.Error("failed to roll back password of account", "account", log.Address(account.Address.Hex()), "err", rollbackErr)
+			}
+			return err
+		}
+	}
+
+	signal.Send(signal.Envelope{
+		Type:  EventAccountPasswordChanged,
+		Event: PasswordChangedEvent{Address: account.Address.Hex()},
+	})
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package account
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangePassword(t *testing.T) {
+	dir, err := ioutil.TempDir("", "password")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	address, _, _, err := manager.CreateAccount("old")
+	require.NoError(t, err)
+	account, accountKey, err := keyStore.AccountDecryptedKey(accounts.Account{Address: gethcommon.HexToAddress(address)}, "old")
+	require.NoError(t, err)
+	manager.selectedAccount = &common.SelectedExtKey{Address: account.Address, AccountKey: accountKey}
+
+	changed := make(chan PasswordChangedEvent, 1)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event PasswordChangedEvent
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		if envelope.Type == EventAccountPasswordChanged {
+			changed <- envelope.Event
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	// nothing is changed, if old password is wrong
+	require.Equal(t, keystore.ErrDecrypt, manager.ChangePassword(address, "wrong", "new"))
+	_, err = manager.VerifyAccountPassword(dir, address, "old")
+	require.NoError(t, err)
+
+	require.NoError(t, manager.ChangePassword(address, "old", "new"))
+	require.Equal(t, PasswordChangedEvent{Address: address}, <-changed)
+
+	_, err = manager.VerifyAccountPassword(dir, address, "old")
+	require.Equal(t, keystore.ErrDecrypt, err)
+	_, err = manager.VerifyAccountPassword(dir, address, "new")
+	require.NoError(t, err)
+
+	// session stays valid, and master key of HD wallet is re-encrypted along with account key
+	selected, err := manager.SelectedAccount()
+	require.NoError(t, err)
+	require.Equal(t, address, selected.Address.Hex())
+	_, err = manager.LoadAccountAtPath("m/44'/60'/0'/0/1", "old")
+	require.Equal(t, keystore.ErrDecrypt, err)
+	_, err = manager.LoadAccountAtPath("m/44'/60'/0'/0/1", "new")
+	require.NoError(t, err)
+
+	// no temporary files are left
+	files, err := ioutil.ReadDir(filepath.Join(dir, hdWalletsDir))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
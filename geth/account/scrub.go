@@ -0,0 +1,44 @@
+package account
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/status-im/status-go/extkeys"
+)
+
+// scrubKey zeroes private key and extended key of decrypted account key in place,
+// so that they don't stay in memory once key is not used anymore.
+func scrubKey(key *keystore.Key) {
+	if key == nil {
+		return
+	}
+
+	scrubPrivateKey(key.PrivateKey)
+	scrubExtendedKey(key.ExtendedKey)
+}
+
+func scrubPrivateKey(k *ecdsa.PrivateKey) {
+	if k == nil || k.D == nil {
+		return
+	}
+
+	b := k.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+	k.D.SetInt64(0)
+}
+
+func scrubExtendedKey(k *extkeys.ExtendedKey) {
+	if k == nil {
+		return
+	}
+
+	for i := range k.KeyData {
+		k.KeyData[i] = 0
+	}
+	for i := range k.ChainCode {
+		k.ChainCode[i] = 0
+	}
+}
@@ -0,0 +1,227 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/common"
+)
+
+// errors
+var (
+	ErrAccountNotSelected = errors.New("account is not selected")
+)
+
+// selection keeps accounts, selected along with the main one (see AddSelectedAccount), accounts
+// bound to origins of requests (jail cells, dapps), and accounts with identities injected into Whisper.
+type selection struct {
+	mu         sync.RWMutex
+	accounts   map[gethcommon.Address]*common.SelectedExtKey
+	bindings   map[string]gethcommon.Address // origin -> account
+	identities map[gethcommon.Address]bool   // see InjectWhisperIdentity
+}
+
+func newSelection() *selection {
+	return &selection{
+		accounts:   make(map[gethcommon.Address]*common.SelectedExtKey),
+		bindings:   make(map[string]gethcommon.Address),
+		identities: make(map[gethcommon.Address]bool),
+	}
+}
+
+// reset forgets all selected accounts, bindings and identities.
+func (s *selection) reset() {
+	s.mu.Lock()
+	s.accounts = make(map[gethcommon.Address]*common.SelectedExtKey)
+	s.bindings = make(map[string]gethcommon.Address)
+	s.identities = make(map[gethcommon.Address]bool)
+	s.mu.Unlock()
+}
+
+// scrub zeroes keys of selected accounts, and forgets them (see reset).
+func (s *selection) scrub() {
+	s.mu.Lock()
+	for _, account := range s.accounts {
+		scrubKey(account.AccountKey)
+	}
+	s.mu.Unlock()
+
+	s.reset()
+}
+
+// AddSelectedAccount selects account in addition to the main one (see SelectAccount), so that
+// it is listed by Accounts, can sign transactions, and can be bound to origins with BindAccount.
+// Whisper identity is not changed.
+func (m *Manager) AddSelectedAccount(address, password string) error {
+	if m.selectedAccount == nil {
+		return ErrNoAccountSelected
+	}
+
+	keyStore, err := m.nodeManager.AccountKeyStore()
+	if err != nil {
+		return err
+	}
+
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return ErrAddressToAccountMappingFailure
+	}
+
+	account, accountKey, err := keyStore.AccountDecryptedKey(account, password)
+	if err != nil {
+		if m.IsWatchOnlyAccount(address) {
+			return ErrWatchOnlyAccount
+		}
+		return fmt.Errorf("%s: %v", ErrAccountToKeyMappingFailure.Error(), err)
+	}
+
+	m.selection.mu.Lock()
+	m.selection.accounts[account.Address] = &common.SelectedExtKey{
+		Address:    account.Address,
+		AccountKey: accountKey,
+	}
+	m.selection.mu.Unlock()
+
+	return nil
+}
+
+// RemoveSelectedAccount deselects account added with AddSelectedAccount. Origins bound to it are unbound,
+// and its Whisper identity is removed.
+func (m *Manager) RemoveSelectedAccount(address string) error {
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return ErrAddressToAccountMappingFailure
+	}
+
+	m.selection.mu.Lock()
+	selected, ok := m.selection.accounts[account.Address]
+	if !ok {
+		m.selection.mu.Unlock()
+		return ErrAccountNotSelected
+	}
+	injected := m.selection.identities[account.Address]
+	delete(m.selection.accounts, account.Address)
+	delete(m.selection.identities, account.Address)
+	for origin, bound := range m.selection.bindings {
+		if bound == account.Address {
+			delete(m.selection.bindings, origin)
+		}
+	}
+	m.selection.mu.Unlock()
+
+	if injected {
+		whisperService, err := m.nodeManager.WhisperService()
+		if err != nil {
+			return err
+		}
+		whisperService.DeleteKeyPair(whisperIdentity(selected))
+	}
+
+	return nil
+}
+
+// SelectedAccountByAddress returns selected account with a given address, either the main one,
+// or one added with AddSelectedAccount.
+func (m *Manager) SelectedAccountByAddress(address string) (*common.SelectedExtKey, error) {
+	selectedAccount, err := m.SelectedAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return nil, ErrAddressToAccountMappingFailure
+	}
+	if account.Address == selectedAccount.Address {
+		return selectedAccount, nil
+	}
+
+	m.selection.mu.RLock()
+	defer m.selection.mu.RUnlock()
+
+	if added, ok := m.selection.accounts[account.Address]; ok {
+		return added, nil
+	}
+
+	return nil, ErrAccountNotSelected
+}
+
+// BindAccount binds account to origin of requests (chatID of jail cell, or origin of dapp),
+// so that eth_accounts sent from it returns that account only, and transactions sent from it
+// are sent from that account by default. Account must be listed by Accounts.
+func (m *Manager) BindAccount(origin, address string) error {
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return ErrAddressToAccountMappingFailure
+	}
+
+	addresses, err := m.Accounts()
+	if err != nil {
+		return err
+	}
+	for _, selected := range addresses {
+		if selected == account.Address {
+			m.selection.mu.Lock()
+			m.selection.bindings[origin] = account.Address
+			m.selection.mu.Unlock()
+			return nil
+		}
+	}
+
+	return ErrAccountNotSelected
+}
+
+// UnbindAccount removes binding of origin, added with BindAccount.
+func (m *Manager) UnbindAccount(origin string) {
+	m.selection.mu.Lock()
+	delete(m.selection.bindings, origin)
+	m.selection.mu.Unlock()
+}
+
+// BoundAccount returns account bound to origin with BindAccount.
+func (m *Manager) BoundAccount(origin string) (gethcommon.Address, bool) {
+	m.selection.mu.RLock()
+	defer m.selection.mu.RUnlock()
+
+	address, ok := m.selection.bindings[origin]
+	return address, ok
+}
+
+// accountsOf returns accounts of origin of request: either the bound one, or all accounts.
+func (m *Manager) accountsOf(ctx context.Context) ([]gethcommon.Address, error) {
+	if address, ok := m.BoundAccount(common.OriginFromContext(ctx)); ok {
+		return []gethcommon.Address{address}, nil
+	}
+
+	return m.Accounts()
+}
+
+// addedAccounts returns addresses of accounts added with AddSelectedAccount, sorted.
+func (m *Manager) addedAccounts() []gethcommon.Address {
+	m.selection.mu.RLock()
+	defer m.selection.mu.RUnlock()
+
+	addresses := make([]gethcommon.Address, 0, len(m.selection.accounts))
+	for address := range m.selection.accounts {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+
+	return addresses
+}
+
+func containsAddress(addresses []gethcommon.Address, address gethcommon.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
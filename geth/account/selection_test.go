@@ -0,0 +1,138 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipleSelectedAccounts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "selection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().AccountManager().Return(accounts.NewManager(keyStore), nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	mainAccount, _, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+	other, _, _, err := manager.CreateAccount("other password")
+	require.NoError(t, err)
+
+	require.Equal(t, ErrNoAccountSelected, manager.AddSelectedAccount(other, "other password"))
+
+	account, accountKey, err := keyStore.AccountDecryptedKey(accounts.Account{Address: gethcommon.HexToAddress(mainAccount)}, "password")
+	require.NoError(t, err)
+	manager.selectedAccount = &common.SelectedExtKey{Address: account.Address, AccountKey: accountKey}
+
+	require.Equal(t, ErrAccountNotSelected, manager.BindAccount("chat", other))
+	require.Error(t, manager.AddSelectedAccount(other, "password"))
+	require.NoError(t, manager.AddSelectedAccount(other, "other password"))
+
+	addresses, err := manager.Accounts()
+	require.NoError(t, err)
+	require.Equal(t, []gethcommon.Address{gethcommon.HexToAddress(mainAccount), gethcommon.HexToAddress(other)}, addresses)
+
+	// both accounts can sign
+	for _, address := range []string{mainAccount, other} {
+		signature, err := manager.SignMessage(address, []byte("hello"))
+		require.NoError(t, err)
+		pubKey, err := crypto.SigToPub(MessageHash([]byte("hello")), append(signature[:64], signature[64]-27))
+		require.NoError(t, err)
+		require.Equal(t, gethcommon.HexToAddress(address), crypto.PubkeyToAddress(*pubKey))
+	}
+
+	// bound origin sees its account only
+	require.NoError(t, manager.BindAccount("chat", other))
+	bound, ok := manager.BoundAccount("chat")
+	require.True(t, ok)
+	require.Equal(t, gethcommon.HexToAddress(other), bound)
+
+	handler := manager.AccountsRPCHandler()
+	result, err := handler(context.WithValue(context.Background(), common.OriginKey, "chat"))
+	require.NoError(t, err)
+	require.Equal(t, []gethcommon.Address{bound}, result)
+	result, err = handler(context.WithValue(context.Background(), common.OriginKey, "another chat"))
+	require.NoError(t, err)
+	require.Equal(t, addresses, result)
+
+	// deselected account is unbound
+	require.NoError(t, manager.RemoveSelectedAccount(other))
+	require.Equal(t, ErrAccountNotSelected, manager.RemoveSelectedAccount(other))
+	_, ok = manager.BoundAccount("chat")
+	require.False(t, ok)
+	_, err = manager.SignMessage(other, []byte("hello"))
+	require.Equal(t, ErrInvalidSigner, err)
+}
+
+func TestLogoutScrubsKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logout")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	whisperService := whisper.New(nil)
+	keyStore := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().AccountKeyStore().Return(keyStore, nil).AnyTimes()
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{KeyStoreDir: dir}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	manager := NewManager(nodeManager)
+
+	address, _, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+	other, _, _, err := manager.CreateAccount("password")
+	require.NoError(t, err)
+
+	require.NoError(t, manager.SelectAccount(address, "password"))
+	require.NoError(t, manager.AddSelectedAccount(other, "password"))
+	selected, err := manager.SelectedAccount()
+	require.NoError(t, err)
+	added, err := manager.SelectedAccountByAddress(other)
+	require.NoError(t, err)
+
+	loggedOut := make(chan LoggedOutEvent, 1)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event LoggedOutEvent
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		if envelope.Type == EventAccountLoggedOut {
+			loggedOut <- envelope.Event
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	require.NoError(t, manager.Logout())
+	require.Equal(t, LoggedOutEvent{Address: address}, <-loggedOut)
+
+	_, err = manager.SelectedAccount()
+	require.Equal(t, ErrNoAccountSelected, err)
+	for _, key := range []*keystore.Key{selected.AccountKey, added.AccountKey} {
+		require.Zero(t, key.PrivateKey.D.Sign())
+		require.Equal(t, make([]byte, len(key.ExtendedKey.KeyData)), key.ExtendedKey.KeyData)
+	}
+}
@@ -0,0 +1,49 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// errors
+var (
+	ErrInvalidSigner = errors.New("data can only be signed by the selected account")
+)
+
+// SignMessage signs message the way personal_sign and eth_sign do, i.e. hash of
+// "\x19Ethereum Signed Message:\n" + len(message) + message, with the key of selected account,
+// which must be the one identified by address. Signature is returned in [R || S || V] format,
+// where V is 27 or 28.
+func (m *Manager) SignMessage(address string, message []byte) ([]byte, error) {
+	return m.signHash(address, MessageHash(message))
+}
+
+// MessageHash returns hash of message, which is signed by personal_sign and eth_sign.
+func MessageHash(message []byte) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+}
+
+// signHash signs hash with the key of selected account, which must be the one identified by address.
+func (m *Manager) signHash(address string, hash []byte) ([]byte, error) {
+	if _, err := m.SelectedAccount(); err != nil {
+		return nil, err
+	}
+	if !gethcommon.IsHexAddress(address) {
+		return nil, ErrInvalidSigner
+	}
+	selectedAccount, err := m.SelectedAccountByAddress(address)
+	if err != nil {
+		return nil, ErrInvalidSigner
+	}
+
+	signature, err := crypto.Sign(hash, selectedAccount.AccountKey.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+
+	return signature, nil
+}
@@ -0,0 +1,33 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignMessage(t *testing.T) {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	manager := NewManager(nil)
+	manager.selectedAccount = &common.SelectedExtKey{
+		Address:    address,
+		AccountKey: &keystore.Key{Address: address, PrivateKey: key},
+	}
+
+	message := []byte("hello")
+	signature, err := manager.SignMessage(address.Hex(), message)
+	require.NoError(t, err)
+	require.Len(t, signature, 65)
+
+	// signature is recovered the way ecrecover does it, with V of 27 or 28
+	signature[64] -= 27
+	publicKey, err := crypto.SigToPub(MessageHash(message), signature)
+	require.NoError(t, err)
+	require.Equal(t, address, crypto.PubkeyToAddress(*publicKey))
+}
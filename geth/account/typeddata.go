@@ -0,0 +1,317 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// errors
+var (
+	ErrInvalidTypedData = errors.New("invalid typed data")
+)
+
+// typedDataDomainType is a type of domain of typed data, which is a part of every typed data hash.
+const typedDataDomainType = "EIP712Domain"
+
+var (
+	typedDataArrayType   = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+	typedDataIntegerType = regexp.MustCompile(`^(u?)int(\d*)$`)
+	typedDataBytesType   = regexp.MustCompile(`^bytes(\d+)$`)
+)
+
+// TypedDataField is a member of structured type of typed data.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is structured data, which is signed with eth_signTypedData (see EIP-712).
+// Arrays are supported as in eth_signTypedData_v4.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// ParseTypedData decodes typed data from JSON. Numbers are kept as json.Number,
+// so that integers don't lose precision.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var typed TypedData
+	if err := decoder.Decode(&typed); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidTypedData, err)
+	}
+	if _, ok := typed.Types[typedDataDomainType]; !ok {
+		return nil, fmt.Errorf("%v: %s type is not defined", ErrInvalidTypedData, typedDataDomainType)
+	}
+	if _, ok := typed.Types[typed.PrimaryType]; !ok {
+		return nil, fmt.Errorf("%v: primary type %s is not defined", ErrInvalidTypedData, typed.PrimaryType)
+	}
+
+	return &typed, nil
+}
+
+// Hash returns hash of typed data, which is signed:
+// keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message)).
+func (t *TypedData) Hash() ([]byte, error) {
+	domain, err := t.hashStruct(typedDataDomainType, t.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append([]byte{0x19, 0x01}, domain...)
+	if t.PrimaryType != typedDataDomainType {
+		message, err := t.hashStruct(t.PrimaryType, t.Message)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, message...)
+	}
+
+	return crypto.Keccak256(data), nil
+}
+
+// SignTypedData signs EIP-712 typed data (JSON encoded) with the key of selected account,
+// which must be the one identified by address. Signature is returned in [R || S || V] format,
+// where V is 27 or 28.
+func (m *Manager) SignTypedData(address, typedData string) ([]byte, error) {
+	typed, err := ParseTypedData([]byte(typedData))
+	if err != nil {
+		return nil, err
+	}
+	hash, err := typed.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.signHash(address, hash)
+}
+
+// hashStruct returns keccak256(typeHash ‖ encodeData(value)).
+func (t *TypedData) hashStruct(typ string, value map[string]interface{}) ([]byte, error) {
+	data, err := t.encodeData(typ, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Keccak256(data), nil
+}
+
+// encodeType returns encoding of struct type along with types it references, e.g.
+// "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+func (t *TypedData) encodeType(primary string) string {
+	deps := t.dependencies(primary, map[string]bool{})
+	sort.Strings(deps)
+
+	var buf bytes.Buffer
+	for _, typ := range append([]string{primary}, deps...) {
+		fields := make([]string, 0, len(t.Types[typ]))
+		for _, field := range t.Types[typ] {
+			fields = append(fields, field.Type+" "+field.Name)
+		}
+		buf.WriteString(typ + "(" + strings.Join(fields, ",") + ")")
+	}
+
+	return buf.String()
+}
+
+// dependencies returns struct types referenced by typ (directly or not), except typ itself.
+func (t *TypedData) dependencies(typ string, found map[string]bool) []string {
+	found[typ] = true
+
+	var deps []string
+	for _, field := range t.Types[typ] {
+		dep := baseType(field.Type)
+		if _, ok := t.Types[dep]; !ok || found[dep] {
+			continue
+		}
+		deps = append(deps, dep)
+		deps = append(deps, t.dependencies(dep, found)...)
+	}
+
+	return deps
+}
+
+// encodeData returns typeHash of struct type followed by encoded values of its fields.
+func (t *TypedData) encodeData(typ string, value map[string]interface{}) ([]byte, error) {
+	data := crypto.Keccak256([]byte(t.encodeType(typ)))
+	for _, field := range t.Types[typ] {
+		fieldValue, ok := value[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%v: %s.%s is missing", ErrInvalidTypedData, typ, field.Name)
+		}
+		encoded, err := t.encodeValue(field.Type, fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %s.%s: %v", ErrInvalidTypedData, typ, field.Name, err)
+		}
+		data = append(data, encoded...)
+	}
+
+	return data, nil
+}
+
+// encodeValue returns 32 bytes encoding of value of a given type.
+func (t *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if match := typedDataArrayType.FindStringSubmatch(typ); match != nil {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s expected", typ)
+		}
+		if match[2] != "" && match[2] != strconv.Itoa(len(items)) {
+			return nil, fmt.Errorf("%s of %d items", typ, len(items))
+		}
+		var data []byte
+		for _, item := range items {
+			encoded, err := t.encodeValue(match[1], item)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, encoded...)
+		}
+		return crypto.Keccak256(data), nil
+	}
+
+	if _, ok := t.Types[typ]; ok {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s expected", typ)
+		}
+		return t.hashStruct(typ, fields)
+	}
+
+	return encodeAtomicValue(typ, value)
+}
+
+func encodeAtomicValue(typ string, value interface{}) ([]byte, error) {
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("string expected")
+		}
+		return crypto.Keccak256([]byte(s)), nil
+	case "bytes":
+		b, err := decodeBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("bool expected")
+		}
+		if b {
+			return math.PaddedBigBytes(big.NewInt(1), 32), nil
+		}
+		return make([]byte, 32), nil
+	case "address":
+		s, ok := value.(string)
+		if !ok || !gethcommon.IsHexAddress(s) {
+			return nil, errors.New("address expected")
+		}
+		return gethcommon.LeftPadBytes(gethcommon.HexToAddress(s).Bytes(), 32), nil
+	}
+
+	if match := typedDataBytesType.FindStringSubmatch(typ); match != nil {
+		size, _ := strconv.Atoi(match[1])
+		b, err := decodeBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if size < 1 || size > 32 || len(b) > size {
+			return nil, fmt.Errorf("%s expected", typ)
+		}
+		return gethcommon.RightPadBytes(b, 32), nil
+	}
+
+	if match := typedDataIntegerType.FindStringSubmatch(typ); match != nil {
+		bits := 256
+		if match[2] != "" {
+			bits, _ = strconv.Atoi(match[2])
+		}
+		if bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("unsupported type %s", typ)
+		}
+		n, err := decodeInteger(value)
+		if err != nil {
+			return nil, err
+		}
+		if !integerFits(n, bits, match[1] == "u") {
+			return nil, fmt.Errorf("%s overflows %s", n, typ)
+		}
+		return math.PaddedBigBytes(math.U256(n), 32), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// decodeBytes decodes hex encoded bytes.
+func decodeBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, errors.New("hex encoded bytes expected")
+	}
+	return hexutil.Decode(s)
+}
+
+// decodeInteger decodes integer given as a number, decimal or hex string.
+func decodeInteger(value interface{}) (*big.Int, error) {
+	var s string
+	switch value := value.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = value
+	case float64:
+		s = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return nil, errors.New("integer expected")
+	}
+
+	n, ok := new(big.Int), false
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, ok = n.SetString(s[2:], 16)
+	} else {
+		n, ok = n.SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", s)
+	}
+
+	return n, nil
+}
+
+func integerFits(n *big.Int, bits int, unsigned bool) bool {
+	if unsigned {
+		return n.Sign() >= 0 && n.BitLen() <= bits
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	return n.Cmp(limit) < 0 && n.Cmp(new(big.Int).Neg(limit)) >= 0
+}
+
+// baseType strips array dimensions of type, "Person[][2]" becomes "Person".
+func baseType(typ string) string {
+	for {
+		match := typedDataArrayType.FindStringSubmatch(typ)
+		if match == nil {
+			return typ
+		}
+		typ = match[1]
+	}
+}
@@ -0,0 +1,127 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// mailTypedData is the example of EIP-712
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataHash(t *testing.T) {
+	typed, err := ParseTypedData([]byte(mailTypedData))
+	require.NoError(t, err)
+	require.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", typed.encodeType("Mail"))
+
+	domain, err := typed.hashStruct(typedDataDomainType, typed.Domain)
+	require.NoError(t, err)
+	require.Equal(t, "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", hexutil.Encode(domain))
+
+	hash, err := typed.Hash()
+	require.NoError(t, err)
+	require.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hexutil.Encode(hash))
+}
+
+func TestTypedDataArrays(t *testing.T) {
+	typed := &TypedData{
+		Types: map[string][]TypedDataField{
+			"Batch": {{Name: "amounts", Type: "uint256[]"}, {Name: "pair", Type: "bool[2]"}},
+		},
+	}
+
+	// arrays are encoded as hash of concatenated encodings of their items
+	encoded, err := typed.encodeValue("uint256[]", []interface{}{"1", "0x02"})
+	require.NoError(t, err)
+	one, _ := encodeAtomicValue("uint256", "1")
+	two, _ := encodeAtomicValue("uint256", "2")
+	require.Equal(t, crypto.Keccak256(append(one, two...)), encoded)
+
+	_, err = typed.hashStruct("Batch", map[string]interface{}{
+		"amounts": []interface{}{"1"},
+		"pair":    []interface{}{true},
+	})
+	require.Error(t, err) // fixed size array of wrong length
+}
+
+func TestTypedDataInvalidValues(t *testing.T) {
+	testCases := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint8", "256"},
+		{"uint256", "-1"},
+		{"int8", "-129"},
+		{"int7", "1"},
+		{"bytes4", "0x0102030405"},
+		{"address", "0x01"},
+		{"bool", "true"},
+		{"string", 1.0},
+		{"fixed128x18", "1"},
+	}
+	for _, tc := range testCases {
+		_, err := encodeAtomicValue(tc.typ, tc.value)
+		require.Error(t, err, "%s %v", tc.typ, tc.value)
+	}
+
+	_, err := encodeAtomicValue("int8", "-128")
+	require.NoError(t, err)
+}
+
+func TestSignTypedData(t *testing.T) {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	manager := NewManager(nil)
+	_, err = manager.SignTypedData(address.Hex(), mailTypedData)
+	require.Equal(t, ErrNoAccountSelected, err)
+
+	manager.selectedAccount = &common.SelectedExtKey{
+		Address:    address,
+		AccountKey: &keystore.Key{Address: address, PrivateKey: key},
+	}
+	_, err = manager.SignTypedData("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", mailTypedData)
+	require.Equal(t, ErrInvalidSigner, err)
+
+	signature, err := manager.SignTypedData(address.Hex(), mailTypedData)
+	require.NoError(t, err)
+	require.Equal(t, "0x"+
+		"4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"+
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"+
+		"1c", hexutil.Encode(signature))
+}
@@ -0,0 +1,148 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/common"
+)
+
+// watchOnlyDir is a sub-directory of key store directory, with watch-only accounts
+const watchOnlyDir = "watchonly"
+
+// errors
+var (
+	ErrWatchOnlyAccount = errors.New("account is watch-only, it has no private key to sign with")
+)
+
+// watchOnlyAccount is a file of watch-only account. Address is not stored as "address" field,
+// so that it is never confused with key files.
+type watchOnlyAccount struct {
+	Account gethcommon.Address `json:"account"`
+	Name    string             `json:"name"`
+}
+
+// AddWatchOnlyAccount adds address, private key of which is not known (e.g. of cold wallet).
+// Watch-only accounts are listed along with accounts of selected account, but can't sign anything.
+// Name of already added account is updated.
+func (m *Manager) AddWatchOnlyAccount(address, name string) error {
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return ErrAddressToAccountMappingFailure
+	}
+
+	keyStore, err := m.nodeManager.AccountKeyStore()
+	if err != nil {
+		return err
+	}
+	if keyStore.HasAddress(account.Address) {
+		return ErrAccountExists
+	}
+
+	path, err := m.watchOnlyPath(account.Address)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(watchOnlyAccount{Account: account.Address, Name: name})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// RemoveWatchOnlyAccount removes watch-only account, added with AddWatchOnlyAccount.
+func (m *Manager) RemoveWatchOnlyAccount(address string) error {
+	account, err := common.ParseAccountString(address)
+	if err != nil {
+		return ErrAddressToAccountMappingFailure
+	}
+
+	path, err := m.watchOnlyPath(account.Address)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return ErrAddressToAccountMappingFailure
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WatchOnlyAccounts returns watch-only accounts, sorted by address.
+func (m *Manager) WatchOnlyAccounts() ([]common.WatchOnlyAccount, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(config.KeyStoreDir, watchOnlyDir))
+	if os.IsNotExist(err) {
+		return []common.WatchOnlyAccount{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]common.WatchOnlyAccount, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(config.KeyStoreDir, watchOnlyDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var account watchOnlyAccount
+		if err := json.Unmarshal(data, &account); err != nil {
+			return nil, fmt.Errorf("invalid watch-only account file %s: %v", file.Name(), err)
+		}
+		accounts = append(accounts, common.WatchOnlyAccount{
+			Address: account.Account.Hex(),
+			Name:    account.Name,
+		})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return strings.ToLower(accounts[i].Address) < strings.ToLower(accounts[j].Address)
+	})
+
+	return accounts, nil
+}
+
+// IsWatchOnlyAccount returns true, if address has been added with AddWatchOnlyAccount.
+func (m *Manager) IsWatchOnlyAccount(address string) bool {
+	if !gethcommon.IsHexAddress(address) {
+		return false
+	}
+
+	path, err := m.watchOnlyPath(gethcommon.HexToAddress(address))
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+
+	return err == nil
+}
+
+// watchOnlyPath returns path of file of watch-only account with a given address.
+func (m *Manager) watchOnlyPath(address gethcommon.Address) (string, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(config.KeyStoreDir, watchOnlyDir, strings.ToLower(address.Hex()[2:])+".json"), nil
+}
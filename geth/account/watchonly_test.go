@@ -0,0 +1,52 @@
+package account_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchOnlyAccounts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchonly")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	acctManager := newTestManager(t, ctrl, dir)
+	address, _, _, err := acctManager.CreateAccount("password")
+	require.NoError(t, err)
+
+	const coldWallet = "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"
+	require.Equal(t, account.ErrAddressToAccountMappingFailure, acctManager.AddWatchOnlyAccount("0x01", "invalid"))
+	require.Equal(t, account.ErrAccountExists, acctManager.AddWatchOnlyAccount(address, "has key"))
+
+	accounts, err := acctManager.WatchOnlyAccounts()
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+
+	require.NoError(t, acctManager.AddWatchOnlyAccount(coldWallet, "cold"))
+	require.NoError(t, acctManager.AddWatchOnlyAccount(coldWallet, "cold wallet")) // name is updated
+	accounts, err = acctManager.WatchOnlyAccounts()
+	require.NoError(t, err)
+	require.Equal(t, []common.WatchOnlyAccount{{Address: coldWallet, Name: "cold wallet"}}, accounts)
+	require.True(t, acctManager.IsWatchOnlyAccount(coldWallet))
+	require.False(t, acctManager.IsWatchOnlyAccount(address))
+
+	// there is no key to log in with
+	require.Equal(t, account.ErrWatchOnlyAccount, acctManager.SelectAccount(coldWallet, "password"))
+
+	// key files are still looked up correctly
+	_, err = acctManager.VerifyAccountPassword(dir, address, "password")
+	require.NoError(t, err)
+
+	require.NoError(t, acctManager.RemoveWatchOnlyAccount(coldWallet))
+	require.Equal(t, account.ErrAddressToAccountMappingFailure, acctManager.RemoveWatchOnlyAccount(coldWallet))
+	require.False(t, acctManager.IsWatchOnlyAccount(coldWallet))
+}
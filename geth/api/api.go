@@ -2,13 +2,30 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"math/big"
 
 	"github.com/NaySoftware/go-fcm"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/account"
 	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/common/message"
+	"github.com/status-im/status-go/geth/deeplink"
+	"github.com/status-im/status-go/geth/encryption"
+	"github.com/status-im/status-go/geth/ens"
+	"github.com/status-im/status-go/geth/explorer"
 	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/node"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/peers"
+	"github.com/status-im/status-go/geth/shhext"
+	"github.com/status-im/status-go/geth/tokens"
+	"github.com/status-im/status-go/geth/txhistory"
 )
 
 const (
@@ -20,10 +37,11 @@ type StatusAPI struct {
 	b *StatusBackend
 }
 
-// NewStatusAPI create a new StatusAPI instance
-func NewStatusAPI() *StatusAPI {
+// NewStatusAPI create a new StatusAPI instance.
+// Options are passed to the underlying node manager.
+func NewStatusAPI(opts ...node.Option) *StatusAPI {
 	return &StatusAPI{
-		b: NewStatusBackend(),
+		b: NewStatusBackend(opts...),
 	}
 }
 
@@ -47,6 +65,46 @@ func (api *StatusAPI) TxQueueManager() common.TxQueueManager {
 	return api.b.TxQueueManager()
 }
 
+// SymKeyVault returns reference to symmetric key vault
+func (api *StatusAPI) SymKeyVault() *shhext.SymKeyVault {
+	return api.b.SymKeyVault()
+}
+
+// AddSymKey adds symmetric key under a given alias, associating it with chat topics. Key is generated,
+// unless its material is given. Keys are stored in the vault of the selected account, and are re-installed
+// into Whisper on every start of node.
+func (api *StatusAPI) AddSymKey(alias string, key hexutil.Bytes, topics []whisper.TopicType) (shhext.SymKeyInfo, error) {
+	if len(key) == 0 {
+		return api.b.SymKeyVault().Create(alias, topics)
+	}
+	return api.b.SymKeyVault().Add(alias, key, topics)
+}
+
+// AddSymKeyFromPassword adds symmetric key, derived from password, under a given alias
+func (api *StatusAPI) AddSymKeyFromPassword(alias, password string, topics []whisper.TopicType) (shhext.SymKeyInfo, error) {
+	return api.b.SymKeyVault().AddFromPassword(alias, password, topics)
+}
+
+// GetSymKey exports symmetric key with a given alias, including its material
+func (api *StatusAPI) GetSymKey(alias string) (shhext.SymKey, error) {
+	return api.b.SymKeyVault().Export(alias)
+}
+
+// SetSymKeyTopics associates symmetric key with a given alias with chat topics
+func (api *StatusAPI) SetSymKeyTopics(alias string, topics []whisper.TopicType) (shhext.SymKeyInfo, error) {
+	return api.b.SymKeyVault().SetTopics(alias, topics)
+}
+
+// DeleteSymKey removes symmetric key with a given alias from Whisper and from the vault
+func (api *StatusAPI) DeleteSymKey(alias string) error {
+	return api.b.SymKeyVault().Remove(alias)
+}
+
+// BuildInfo returns metadata of the running binary (also available as status_version RPC method).
+func (api *StatusAPI) BuildInfo() params.BuildInfo {
+	return params.Build()
+}
+
 // StartNode start Status node, fails if node is already started
 func (api *StatusAPI) StartNode(config *params.NodeConfig) error {
 	nodeStarted, err := api.b.StartNode(config)
@@ -94,6 +152,21 @@ func (api *StatusAPI) RestartNodeAsync() (<-chan struct{}, error) {
 	return api.b.RestartNode()
 }
 
+// UpdateConfig restarts running Status node with a given configuration
+func (api *StatusAPI) UpdateConfig(config *params.NodeConfig) error {
+	nodeStarted, err := api.b.UpdateConfig(config)
+	if err != nil {
+		return err
+	}
+	<-nodeStarted // do not return up until backend is ready
+	return nil
+}
+
+// UpdateConfigAsync restarts running Status node with a given configuration, in async manner
+func (api *StatusAPI) UpdateConfigAsync(config *params.NodeConfig) (<-chan struct{}, error) {
+	return api.b.UpdateConfig(config)
+}
+
 // ResetChainData remove chain data from data directory.
 // Node is stopped, and new node is started, with clean data directory.
 func (api *StatusAPI) ResetChainData() error {
@@ -110,6 +183,225 @@ func (api *StatusAPI) ResetChainDataAsync() (<-chan struct{}, error) {
 	return api.b.ResetChainData()
 }
 
+// StartRPCEndpoint opens HTTP, WS and/or IPC JSON-RPC servers of a running node
+func (api *StatusAPI) StartRPCEndpoint(config params.RPCEndpointConfig) error {
+	return api.b.NodeManager().StartRPCEndpoint(config)
+}
+
+// RPCEndpointToken returns token of session, HTTP and WS requests to endpoint opened with
+// StartRPCEndpoint must carry, unless params.RPCEndpointConfig.AuthDisabled is set
+func (api *StatusAPI) RPCEndpointToken() (string, error) {
+	return api.b.NodeManager().RPCEndpointToken()
+}
+
+// StopRPCEndpoint closes JSON-RPC servers opened with StartRPCEndpoint
+func (api *StatusAPI) StopRPCEndpoint() error {
+	return api.b.NodeManager().StopRPCEndpoint()
+}
+
+// StartRPCProxy starts local HTTP JSON-RPC proxy for dApps, opened in WebView, on a given
+// loopback address (e.g. "127.0.0.1:0"), returning its URL
+func (api *StatusAPI) StartRPCProxy(addr string) (string, error) {
+	if err := api.b.RPCProxy().Start(addr); err != nil {
+		return "", err
+	}
+	return api.b.RPCProxy().URL(), nil
+}
+
+// StopRPCProxy stops proxy started with StartRPCProxy
+func (api *StatusAPI) StopRPCProxy() error {
+	return api.b.RPCProxy().Stop()
+}
+
+// Peers returns info of connected peers, along with their latency and quality measurements
+func (api *StatusAPI) Peers() ([]peers.Info, error) {
+	return api.b.NodeManager().Peers()
+}
+
+// NodeID returns ID of the running node (public part of node's devp2p key)
+func (api *StatusAPI) NodeID() (discover.NodeID, error) {
+	return api.b.NodeManager().NodeID()
+}
+
+// ExportNodeKey returns hex-encoded private devp2p key of the running node
+func (api *StatusAPI) ExportNodeKey() (string, error) {
+	return api.b.NodeManager().ExportNodeKey()
+}
+
+// ImportNodeKey replaces devp2p key of the running node with a given hex-encoded one.
+// Node is restarted in order to start using a new identity.
+func (api *StatusAPI) ImportNodeKey(hexKey string) (<-chan struct{}, error) {
+	return api.b.NodeManager().ImportNodeKey(hexKey)
+}
+
+// RotateNodeKey replaces devp2p key of the running node with a freshly generated one.
+// Node is restarted in order to start using a new identity.
+func (api *StatusAPI) RotateNodeKey() (<-chan struct{}, error) {
+	return api.b.NodeManager().RotateNodeKey()
+}
+
+// ExportWhisperFilters dumps message filters installed into Whisper (topics, key ids and PoW), for debugging
+func (api *StatusAPI) ExportWhisperFilters() ([]shhext.FilterDump, error) {
+	whisperService, err := api.b.NodeManager().WhisperService()
+	if err != nil {
+		return nil, err
+	}
+	return shhext.ExportFilters(whisperService), nil
+}
+
+// WhisperRelayStats returns numbers of envelopes of other nodes, relayed to peers, and dropped in light client mode
+func (api *StatusAPI) WhisperRelayStats() (shhext.RelayStats, error) {
+	whisperService, err := api.b.NodeManager().WhisperService()
+	if err != nil {
+		return shhext.RelayStats{}, err
+	}
+	return shhext.NewRelayStats(whisperService), nil
+}
+
+// WhisperStats returns per-topic metrics of envelopes sent, received and dropped by Whisper since start of node
+func (api *StatusAPI) WhisperStats() shhext.WhisperStats {
+	return api.b.EnvelopeMetrics().WhisperStats()
+}
+
+// ImportWhisperFilters installs dumped message filters into Whisper, in order to reproduce subscription state
+// of another node. Ids of installed filters are returned, keyed by ids of dumped ones.
+func (api *StatusAPI) ImportWhisperFilters(filters []shhext.FilterDump) (map[string]string, error) {
+	whisperService, err := api.b.NodeManager().WhisperService()
+	if err != nil {
+		return nil, err
+	}
+	return shhext.ImportFilters(whisperService, filters)
+}
+
+// RequestWhisperHistory requests historic messages of topic from mail server. Unless bounds of requested range
+// are set, messages since the last received envelope of topic (or for the last day) are requested.
+func (api *StatusAPI) RequestWhisperHistory(req shhext.MessagesRequest) (shhext.MessagesRequest, error) {
+	return api.b.MailHistory().RequestMessages(req)
+}
+
+// RequestHistoricMessages requests messages of topics (all topics, if none is given), sent within range
+// [from, to] (unix time), from mail server (the first configured one, if peer is empty), limiting their
+// number, unless limit is zero. Completion of returned request is reported with signals.
+func (api *StatusAPI) RequestHistoricMessages(peer string, topics []whisper.TopicType, from, to, limit uint32) (gethcommon.Hash, error) {
+	return api.b.MailService().RequestHistoricMessages(peer, topics, from, to, limit)
+}
+
+// SendChatMessage sends chat message directly, without round-tripping through JS in the jail.
+// ChatID is either hex-encoded public key of recipient, or name of symmetric key of public/group chat.
+func (api *StatusAPI) SendChatMessage(chatID, content, replyTo string) (shhext.ChatMessage, error) {
+	return api.b.Messenger().SendChatMessage(chatID, content, replyTo)
+}
+
+// ChatMessages returns messages sent to a given chat with SendChatMessage
+func (api *StatusAPI) ChatMessages(chatID string) ([]shhext.ChatMessage, error) {
+	return api.b.Messenger().Messages(chatID)
+}
+
+// MessageStatus returns status of delivery of message sent with SendChatMessage, given its ID.
+// Changes of status are reported with signals.
+func (api *StatusAPI) MessageStatus(id string) (message.Status, error) {
+	return api.b.DeliveryService().MessageStatus(id)
+}
+
+// PostWhisperMessage posts message to Whisper through outbox: if it can't be sent now (no peers
+// are connected, or PoW fails), it is queued and resent later. Changes of status are reported with signals.
+func (api *StatusAPI) PostWhisperMessage(msg shhext.OutboxMessage) (shhext.OutboxMessage, error) {
+	return api.b.Outbox().Post(msg)
+}
+
+// OutboxMessages returns messages posted with PostWhisperMessage, with their current status
+func (api *StatusAPI) OutboxMessages() ([]shhext.OutboxMessage, error) {
+	return api.b.Outbox().Messages()
+}
+
+// DiscardOutboxMessage removes sent or failed message from outbox
+func (api *StatusAPI) DiscardOutboxMessage(id string) error {
+	return api.b.Outbox().Discard(id)
+}
+
+// GroupChats returns group chats of the selected account
+func (api *StatusAPI) GroupChats() ([]shhext.Group, error) {
+	return api.b.GroupChatManager().Groups()
+}
+
+// CreateGroupChat creates group chat, administered by the selected account, with members identified by
+// their public keys. Chat id of the group is a name of its key in the vault, so messages are sent to it
+// as to any other named key.
+func (api *StatusAPI) CreateGroupChat(name string, members []hexutil.Bytes) (shhext.Group, error) {
+	return api.b.GroupChatManager().CreateGroup(name, members)
+}
+
+// AddGroupChatMembers adds members to group chat, administered by the selected account
+func (api *StatusAPI) AddGroupChatMembers(chatID string, members []hexutil.Bytes) (shhext.Group, error) {
+	return api.b.GroupChatManager().AddMembers(chatID, members)
+}
+
+// RemoveGroupChatMembers removes members from group chat, administered by the selected account, rotating its key
+func (api *StatusAPI) RemoveGroupChatMembers(chatID string, members []hexutil.Bytes) (shhext.Group, error) {
+	return api.b.GroupChatManager().RemoveMembers(chatID, members)
+}
+
+// EncryptionBundle returns bundle of the selected account, which is to be published to contacts,
+// so that they could start encrypted sessions with it
+func (api *StatusAPI) EncryptionBundle() (encryption.Bundle, error) {
+	return api.b.Encryption().Bundle()
+}
+
+// ProcessEncryptionBundle stores bundle of contact, so that encrypted session could be started with it
+func (api *StatusAPI) ProcessEncryptionBundle(bundle encryption.Bundle) error {
+	return api.b.Encryption().ProcessBundle(bundle)
+}
+
+// EncryptPayload encrypts payload within session with contact, identified by its public key
+func (api *StatusAPI) EncryptPayload(publicKey hexutil.Bytes, payload []byte) (*encryption.EncryptedMessage, error) {
+	key := crypto.ToECDSAPub(publicKey)
+	if !whisper.ValidatePublicKey(key) {
+		return nil, encryption.ErrInvalidPublicKey
+	}
+	return api.b.Encryption().Encrypt(key, payload)
+}
+
+// DecryptPayload decrypts payload received within session with contact, identified by its public key
+func (api *StatusAPI) DecryptPayload(publicKey hexutil.Bytes, msg *encryption.EncryptedMessage) ([]byte, error) {
+	key := crypto.ToECDSAPub(publicKey)
+	if !whisper.ValidatePublicKey(key) {
+		return nil, encryption.ErrInvalidPublicKey
+	}
+	return api.b.Encryption().Decrypt(key, msg)
+}
+
+// SubscribeWhisper subscribes to Whisper messages matching filter, which are delivered as signals.
+// Subscription is kept across node restarts, until it's removed with UnsubscribeWhisper.
+func (api *StatusAPI) SubscribeWhisper(filter shhext.SubscriptionFilter) (string, error) {
+	return api.b.WhisperSubscriber().Subscribe(filter, nil)
+}
+
+// UnsubscribeWhisper removes subscription to Whisper messages
+func (api *StatusAPI) UnsubscribeWhisper(subID string) error {
+	return api.b.WhisperSubscriber().Unsubscribe(subID)
+}
+
+// AddBlockedKey blocks Whisper messages signed with public key, they are dropped before delivery to subscriptions
+func (api *StatusAPI) AddBlockedKey(key hexutil.Bytes) error {
+	return api.b.IngressFilterChain().AddBlockedKey(key)
+}
+
+// RemoveBlockedKey unblocks Whisper messages signed with public key
+func (api *StatusAPI) RemoveBlockedKey(key hexutil.Bytes) {
+	api.b.IngressFilterChain().RemoveBlockedKey(key)
+}
+
+// BlockedKeys returns public keys, Whisper messages of which are blocked
+func (api *StatusAPI) BlockedKeys() []hexutil.Bytes {
+	return api.b.IngressFilterChain().BlockedKeys()
+}
+
+// SetIngressPolicy sets limits of payload size and rate of messages per sender, Whisper messages
+// exceeding them are dropped before delivery to subscriptions
+func (api *StatusAPI) SetIngressPolicy(policy shhext.IngressPolicy) error {
+	return api.b.IngressFilterChain().SetIngressPolicy(policy)
+}
+
 // CallRPC executes RPC request on node's in-proc RPC server
 func (api *StatusAPI) CallRPC(inputJSON string) string {
 	return api.b.CallRPC(inputJSON)
@@ -136,6 +428,92 @@ func (api *StatusAPI) RecoverAccount(password, mnemonic string) (address, pubKey
 	return api.b.AccountManager().RecoverAccount(password, mnemonic)
 }
 
+// CreateAccountFromMnemonic creates HD wallet from a given BIP39 mnemonic phrase, protected with password.
+// Main account (m/44'/60'/0'/0/0) is imported into keystore.
+func (api *StatusAPI) CreateAccountFromMnemonic(mnemonic, password string) (address, pubKey string, err error) {
+	return api.b.AccountManager().CreateAccountFromMnemonic(mnemonic, password)
+}
+
+// DeriveChildAccounts derives count accounts of HD wallet of selected account, by appending indexes
+// 0..count-1 to path (m/44'/60'/0'/0, if path is empty).
+func (api *StatusAPI) DeriveChildAccounts(path string, count int, password string) ([]common.DerivedAccount, error) {
+	return api.b.AccountManager().DeriveChildAccounts(path, count, password)
+}
+
+// LoadAccountAtPath derives account of HD wallet of selected account at a given path.
+func (api *StatusAPI) LoadAccountAtPath(path, password string) (common.DerivedAccount, error) {
+	return api.b.AccountManager().LoadAccountAtPath(path, password)
+}
+
+// ChangePassword re-encrypts key of account (and master key of its HD wallet) with newPassword.
+// Selected account stays selected.
+func (api *StatusAPI) ChangePassword(address, oldPassword, newPassword string) error {
+	return api.b.AccountManager().ChangePassword(address, oldPassword, newPassword)
+}
+
+// VerifyMnemonic returns true, if mnemonic phrase derives key of account identified by address,
+// e.g. to check that user has written recovery phrase down correctly.
+func (api *StatusAPI) VerifyMnemonic(address, mnemonic, password string) (bool, error) {
+	return api.b.AccountManager().VerifyMnemonic(address, mnemonic, password)
+}
+
+// ExportAccount returns key of account identified by address in standard V3 JSON format,
+// encrypted with exportPassword instead of password of account.
+func (api *StatusAPI) ExportAccount(address, password, exportPassword string) (string, error) {
+	return api.b.AccountManager().ExportAccount(address, password, exportPassword)
+}
+
+// ImportAccount imports key in V3 JSON format, encrypted with password, into keystore.
+// Imported key is encrypted with newPassword.
+func (api *StatusAPI) ImportAccount(keyJSON, password, newPassword string) (address string, err error) {
+	return api.b.AccountManager().ImportAccount(keyJSON, password, newPassword)
+}
+
+// ExportKeystoreBundle returns backup of all accounts of keystore, encrypted with exportPassword.
+func (api *StatusAPI) ExportKeystoreBundle(exportPassword string) (string, error) {
+	return api.b.AccountManager().ExportKeystoreBundle(exportPassword)
+}
+
+// ImportKeystoreBundle restores accounts from bundle, encrypted with exportPassword.
+// Addresses of imported accounts are returned.
+func (api *StatusAPI) ImportKeystoreBundle(bundle, exportPassword string) ([]string, error) {
+	return api.b.AccountManager().ImportKeystoreBundle(bundle, exportPassword)
+}
+
+// AddWatchOnlyAccount adds address, private key of which is not known (e.g. of cold wallet).
+// Watch-only accounts are listed along with accounts of selected account, but can't sign anything.
+func (api *StatusAPI) AddWatchOnlyAccount(address, name string) error {
+	return api.b.AccountManager().AddWatchOnlyAccount(address, name)
+}
+
+// RemoveWatchOnlyAccount removes watch-only account, added with AddWatchOnlyAccount.
+func (api *StatusAPI) RemoveWatchOnlyAccount(address string) error {
+	return api.b.AccountManager().RemoveWatchOnlyAccount(address)
+}
+
+// WatchOnlyAccounts returns watch-only accounts, sorted by address.
+func (api *StatusAPI) WatchOnlyAccounts() ([]common.WatchOnlyAccount, error) {
+	return api.b.AccountManager().WatchOnlyAccounts()
+}
+
+// GenerateAccounts creates count throwaway accounts with mnemonics derived deterministically from seed,
+// for load tests and QA environments. If faucetURL is not empty, every account is funded from faucet.
+func (api *StatusAPI) GenerateAccounts(seed string, count int, password, faucetURL string) ([]common.GeneratedAccount, error) {
+	generated, err := api.b.AccountManager().GenerateAccounts(seed, count, password)
+	if err != nil || faucetURL == "" {
+		return generated, err
+	}
+
+	for _, acc := range generated {
+		if err := account.FundAccount(context.Background(), faucetURL, acc.Address); err != nil {
+			return generated, fmt.Errorf("funding of %s failed: %v", acc.Address, err)
+		}
+		log.Info("Generated account is funded", "address", log.Address(acc.Address))
+	}
+
+	return generated, nil
+}
+
 // VerifyAccountPassword tries to decrypt a given account key file, with a provided password.
 // If no error is returned, then account is considered verified.
 func (api *StatusAPI) VerifyAccountPassword(keyStoreDir, address, password string) (*keystore.Key, error) {
@@ -146,12 +524,62 @@ func (api *StatusAPI) VerifyAccountPassword(keyStoreDir, address, password strin
 // using provided password. Once verification is done, decrypted key is injected into Whisper (as a single identity,
 // all previous identities are removed).
 func (api *StatusAPI) SelectAccount(address, password string) error {
-	return api.b.AccountManager().SelectAccount(address, password)
+	return api.b.SelectAccount(address, password)
+}
+
+// AddSelectedAccount selects account in addition to the main one, so that it is listed by eth_accounts,
+// can sign transactions, and can be bound to jail cells and dapps with BindAccount.
+func (api *StatusAPI) AddSelectedAccount(address, password string) error {
+	return api.b.AccountManager().AddSelectedAccount(address, password)
+}
+
+// RemoveSelectedAccount deselects account added with AddSelectedAccount.
+func (api *StatusAPI) RemoveSelectedAccount(address string) error {
+	return api.b.AccountManager().RemoveSelectedAccount(address)
+}
+
+// InjectWhisperIdentity adds key of selected account (the main one, or one added with AddSelectedAccount)
+// into Whisper as chat identity, without changing wallet key selection.
+func (api *StatusAPI) InjectWhisperIdentity(address string) error {
+	return api.b.AccountManager().InjectWhisperIdentity(address)
+}
+
+// RemoveWhisperIdentity removes chat identity of account from Whisper, account stays selected.
+func (api *StatusAPI) RemoveWhisperIdentity(address string) error {
+	return api.b.AccountManager().RemoveWhisperIdentity(address)
+}
+
+// WhisperIdentities returns chat identities of selected accounts, injected into Whisper.
+func (api *StatusAPI) WhisperIdentities() ([]common.WhisperIdentity, error) {
+	return api.b.AccountManager().WhisperIdentities()
+}
+
+// DeriveContactKey derives deterministic key of selected account, dedicated to a contact identified
+// by its public key, and adds it into Whisper, so that pairwise encrypted sessions can be built on top of it.
+func (api *StatusAPI) DeriveContactKey(contactPubKey string) (common.ContactKey, error) {
+	return api.b.AccountManager().DeriveContactKey(contactPubKey)
 }
 
-// Logout clears whisper identities
+// AccountsMetadata returns persistent store of labels and avatars of accounts, and of address book contacts.
+func (api *StatusAPI) AccountsMetadata() common.AccountsMetadata {
+	return api.b.AccountManager().AccountsMetadata()
+}
+
+// BindAccount binds account to origin of requests (chatID of jail cell, or origin of dapp),
+// so that eth_accounts sent from it returns that account only, and transactions are sent from it by default.
+func (api *StatusAPI) BindAccount(origin, address string) error {
+	return api.b.AccountManager().BindAccount(origin, address)
+}
+
+// UnbindAccount removes binding of origin, added with BindAccount.
+func (api *StatusAPI) UnbindAccount(origin string) {
+	api.b.AccountManager().UnbindAccount(origin)
+}
+
+// Logout locks the wallet without stopping the node: decrypted keys are scrubbed from memory,
+// selected account is reset, symmetric keys are locked, and whisper identities are cleared.
 func (api *StatusAPI) Logout() error {
-	return api.b.AccountManager().Logout()
+	return api.b.Logout()
 }
 
 // SendTransaction creates a new transaction and waits until it's complete.
@@ -169,16 +597,64 @@ func (api *StatusAPI) CompleteTransactions(ids []common.QueuedTxID, password str
 	return api.b.txQueueManager.CompleteTransactions(ids, password)
 }
 
+// SpeedUpTransaction sends pending transaction again, with the same nonce and higher gas price
+func (api *StatusAPI) SpeedUpTransaction(hash gethcommon.Hash, gasPrice *big.Int, password string) (gethcommon.Hash, error) {
+	return api.b.txQueueManager.SpeedUpTransaction(hash, gasPrice, password)
+}
+
+// CancelTransaction replaces pending transaction with transfer of zero value to sender, with the same nonce
+func (api *StatusAPI) CancelTransaction(hash gethcommon.Hash, password string) (gethcommon.Hash, error) {
+	return api.b.txQueueManager.CancelTransaction(hash, password)
+}
+
 // DiscardTransaction discards a given transaction from transaction queue
 func (api *StatusAPI) DiscardTransaction(id common.QueuedTxID) error {
 	return api.b.txQueueManager.DiscardTransaction(id)
 }
 
+// ApproveSignRequest signs message of a given request (personal_sign, eth_sign) with selected account
+func (api *StatusAPI) ApproveSignRequest(id common.SignRequestID, password string) (hexutil.Bytes, error) {
+	return api.b.txQueueManager.ApproveSignRequest(id, password)
+}
+
+// DiscardSignRequest discards a given request to sign a message
+func (api *StatusAPI) DiscardSignRequest(id common.SignRequestID) error {
+	return api.b.txQueueManager.DiscardSignRequest(id)
+}
+
 // DiscardTransactions discards given multiple transactions from transaction queue
 func (api *StatusAPI) DiscardTransactions(ids []common.QueuedTxID) map[common.QueuedTxID]common.RawDiscardTransactionResult {
 	return api.b.txQueueManager.DiscardTransactions(ids)
 }
 
+// QueuedTransactionsByOrigin returns queued transactions, requested by a given origin (chatID of jail cell)
+func (api *StatusAPI) QueuedTransactionsByOrigin(origin string) []*common.QueuedTx {
+	return api.b.txQueueManager.TransactionsByOrigin(origin)
+}
+
+// PendingTransactions returns details of queued transactions (and requests to sign typed data),
+// in order they have been queued.
+func (api *StatusAPI) PendingTransactions() []common.PendingTransaction {
+	return api.b.txQueueManager.PendingTransactions()
+}
+
+// EstimateTransaction estimates gas of transaction and suggests gas price (with gas price oracle, if configured),
+// unless they are set in args, so that fee of transaction could be shown before it is sent.
+func (api *StatusAPI) EstimateTransaction(args common.SendTxArgs) (common.TxEstimate, error) {
+	return api.b.txQueueManager.EstimateTransaction(args)
+}
+
+// DiscardTransactionsByOrigin discards all queued transactions, requested by a given origin (chatID of jail cell)
+func (api *StatusAPI) DiscardTransactionsByOrigin(origin string) map[common.QueuedTxID]common.RawDiscardTransactionResult {
+	return api.b.txQueueManager.DiscardTransactionsByOrigin(origin)
+}
+
+// SetHardwareWallet sets hardware wallet (Ledger, Trezor), transactions of its accounts are signed with.
+// Transport to the device is provided by host app, nil wallet disconnects it.
+func (api *StatusAPI) SetHardwareWallet(wallet common.HardwareWallet) {
+	api.b.txQueueManager.SetHardwareWallet(wallet)
+}
+
 // JailParse creates a new jail cell context, with the given chatID as identifier.
 // New context executes provided JavaScript code, right after the initialization.
 func (api *StatusAPI) JailParse(chatID string, js string) string {
@@ -190,11 +666,150 @@ func (api *StatusAPI) JailCall(chatID, this, args string) string {
 	return api.b.jailManager.Call(chatID, this, args)
 }
 
+// JailCallAsync queues call of JavaScript function w/i a jail cell context identified by the chatID,
+// and returns ID of call immediately. Response is sent as "jail.call.result" signal.
+func (api *StatusAPI) JailCallAsync(chatID, this, args string) string {
+	return api.b.jailManager.CallAsync(chatID, this, args)
+}
+
+// JailRemoveCell stops jail cell, identified by chatID, and removes it
+func (api *StatusAPI) JailRemoveCell(chatID string) error {
+	return api.b.jailManager.RemoveCell(chatID)
+}
+
+// JailCells returns stats of live jail cells
+func (api *StatusAPI) JailCells() []common.JailCellInfo {
+	return api.b.jailManager.Cells()
+}
+
+// JailStorage returns items, jail cell identified by chatID keeps in its localStorage
+func (api *StatusAPI) JailStorage(chatID string) (map[string]string, error) {
+	return api.b.jailManager.Storage(chatID)
+}
+
+// JailClearStorage removes all items, jail cell identified by chatID keeps in its localStorage
+func (api *StatusAPI) JailClearStorage(chatID string) error {
+	return api.b.jailManager.ClearStorage(chatID)
+}
+
+// JailSetFetchWhitelist changes origins, jail cell identified by chatID may send requests to with fetch(),
+// overriding whitelist of params.JailConfig (nil restores it)
+func (api *StatusAPI) JailSetFetchWhitelist(chatID string, origins []string) {
+	api.b.jailManager.SetFetchWhitelist(chatID, origins)
+}
+
+// JailSetPermissions restricts RPC requests of jail cell identified by chatID to a given permission set
+// (nil lifts restrictions)
+func (api *StatusAPI) JailSetPermissions(chatID string, permissions *common.JailPermissions) {
+	api.b.jailManager.SetPermissions(chatID, permissions)
+}
+
+// JailCellLogs returns the last n entries, jail cell identified by chatID has logged with console
+func (api *StatusAPI) JailCellLogs(chatID string, n int) []common.JailLogEntry {
+	return api.b.jailManager.CellLogs(chatID, n)
+}
+
+// JailCallQueueDepth returns number of calls of jail cell identified by chatID, which are executed
+// or wait for execution
+func (api *StatusAPI) JailCallQueueDepth(chatID string) int {
+	return api.b.jailManager.CallQueueDepth(chatID)
+}
+
 // JailBaseJS allows to setup initial JavaScript to be loaded on each jail.Parse()
 func (api *StatusAPI) JailBaseJS(js string) {
 	api.b.jailManager.BaseJS(js)
 }
 
+// JailReloadBaseJS changes base JavaScript and re-parses live cells with it, keeping their state
+func (api *StatusAPI) JailReloadBaseJS(js string) error {
+	return api.b.jailManager.ReloadBaseJS(js)
+}
+
+// JailParseVersion returns version of base JavaScript, cells are parsed with
+func (api *StatusAPI) JailParseVersion() uint64 {
+	return api.b.jailManager.ParseVersion()
+}
+
+// JailRegisterModule compiles JavaScript module once, so that all jail cells can load it with require(name)
+func (api *StatusAPI) JailRegisterModule(name, js string) error {
+	return api.b.jailManager.RegisterModule(name, js)
+}
+
+// ParseDeepLink validates ethereum: or status-im: link, and returns action described by it
+func (api *StatusAPI) ParseDeepLink(link string) (*deeplink.Action, error) {
+	return deeplink.Parse(link)
+}
+
+// ExplorerTxURL returns URL of page of transaction on block explorer of the active network
+func (api *StatusAPI) ExplorerTxURL(hash string) (string, error) {
+	return explorer.NewResolver(api.b.nodeManager).TxURL(hash)
+}
+
+// ExplorerAddressURL returns URL of page of address on block explorer of the active network
+func (api *StatusAPI) ExplorerAddressURL(address string) (string, error) {
+	return explorer.NewResolver(api.b.nodeManager).AddressURL(address)
+}
+
+// TransactionHistory returns page of history of transactions of account, selected by filter
+func (api *StatusAPI) TransactionHistory(address string, page txhistory.Page, filter txhistory.Filter) (txhistory.Result, error) {
+	if !gethcommon.IsHexAddress(address) {
+		return txhistory.Result{}, account.ErrAddressToAccountMappingFailure
+	}
+	return api.b.TxHistory().TransactionHistory(gethcommon.HexToAddress(address), page, filter)
+}
+
+// Tokens returns registry of ERC20 tokens of the network
+func (api *StatusAPI) Tokens() ([]tokens.Token, error) {
+	return api.b.Tokens().Tokens()
+}
+
+// UpdateTokens replaces registry of ERC20 tokens of the network, nil tokens restore the bundled one
+func (api *StatusAPI) UpdateTokens(registry []tokens.Token) error {
+	return api.b.Tokens().UpdateTokens(registry)
+}
+
+// TokenBalances returns balances of account in tokens (all tokens of registry, if tokens are not given)
+func (api *StatusAPI) TokenBalances(address string, tokenAddresses []string) (map[gethcommon.Address]*hexutil.Big, error) {
+	if !gethcommon.IsHexAddress(address) {
+		return nil, account.ErrAddressToAccountMappingFailure
+	}
+
+	var addresses []gethcommon.Address
+	for _, token := range tokenAddresses {
+		if !gethcommon.IsHexAddress(token) {
+			return nil, tokens.ErrInvalidTokenAddress
+		}
+		addresses = append(addresses, gethcommon.HexToAddress(token))
+	}
+
+	return api.b.Tokens().TokenBalances(context.Background(), gethcommon.HexToAddress(address), addresses)
+}
+
+// QueueTokenTransfer queues transfer of amount of token (given by its address or symbol) from selected account
+// to recipient, and returns ID of queued transaction
+func (api *StatusAPI) QueueTokenTransfer(token, to string, amount *big.Int) (common.QueuedTxID, error) {
+	if !gethcommon.IsHexAddress(to) {
+		return "", account.ErrAddressToAccountMappingFailure
+	}
+	return api.b.QueueTokenTransfer(context.Background(), token, gethcommon.HexToAddress(to), amount)
+}
+
+// ResolveENSName resolves ENS name (bare usernames are subdomains of Status domain) to address and public key
+func (api *StatusAPI) ResolveENSName(name string) (ens.Resolution, error) {
+	return api.b.ENS().ResolveName(context.Background(), name)
+}
+
+// ReverseResolveENS resolves address or public key of contact to its ENS name
+func (api *StatusAPI) ReverseResolveENS(key hexutil.Bytes) (ens.Resolution, error) {
+	return api.b.ENS().ReverseResolve(context.Background(), key)
+}
+
+// QueueENSRegistration queues registration of username, pointing to the selected account,
+// and returns ID of queued transaction
+func (api *StatusAPI) QueueENSRegistration(username string) (common.QueuedTxID, error) {
+	return api.b.QueueENSRegistration(context.Background(), username)
+}
+
 // TODO(oskarth): API package this stuff
 func (api *StatusAPI) Notify(token string) string {
 	log.Debug("Notify", "token", token)
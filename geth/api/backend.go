@@ -2,16 +2,25 @@ package api
 
 import (
 	"context"
+	"math/big"
 	"sync"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/status-im/status-go/geth/account"
 	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/encryption"
+	"github.com/status-im/status-go/geth/ens"
 	"github.com/status-im/status-go/geth/jail"
 	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/mailservice"
 	"github.com/status-im/status-go/geth/node"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc/proxy"
+	"github.com/status-im/status-go/geth/shhext"
 	"github.com/status-im/status-go/geth/signal"
+	"github.com/status-im/status-go/geth/tokens"
+	"github.com/status-im/status-go/geth/txhistory"
 	"github.com/status-im/status-go/geth/txqueue"
 )
 
@@ -22,24 +31,76 @@ type StatusBackend struct {
 	nodeManager    common.NodeManager
 	accountManager common.AccountManager
 	txQueueManager common.TxQueueManager
+	txHistory      *txhistory.History
+	tokens         *tokens.Manager
+	ens            *ens.Service
 	jailManager    common.JailManager
+	symKeyVault    *shhext.SymKeyVault
+	mailHistory    *shhext.MailHistory
+	mailService    *mailservice.Service
+	delivery       *shhext.DeliveryService
+	encryption     *encryption.Service
+	messenger      *shhext.Messenger
+	outbox         *shhext.Outbox
+	subscriber     *shhext.WhisperSubscriber
+	ingress        *shhext.IngressFilterChain
+	groups         *shhext.GroupChatManager
+	metrics        *shhext.EnvelopeMetrics
+	rpcProxy       *proxy.Server
 	// TODO(oskarth): notifer here
 }
 
-// NewStatusBackend create a new NewStatusBackend instance
-func NewStatusBackend() *StatusBackend {
+// NewStatusBackend create a new NewStatusBackend instance.
+// Options are passed to the underlying node manager.
+func NewStatusBackend(opts ...node.Option) *StatusBackend {
 	defer log.Info("Status backend initialized")
 
-	nodeManager := node.NewNodeManager()
+	nodeManager := node.NewNodeManager(opts...)
 	accountManager := account.NewManager(nodeManager)
 	txQueueManager := txqueue.NewManager(nodeManager, accountManager)
+	txHistory := txhistory.New(nodeManager, accountManager)
+	tokenManager := tokens.New(nodeManager)
+	ensService := ens.New(nodeManager)
 	jailManager := jail.New(nodeManager)
+	symKeyVault := shhext.NewSymKeyVault(nodeManager)
+	mailHistory := shhext.NewMailHistory(nodeManager)
+	mailService := mailservice.New(nodeManager)
+	delivery := shhext.NewDeliveryService(nodeManager)
+	encryptionService := encryption.New(nodeManager)
+	outbox := shhext.NewOutbox(nodeManager, accountManager, symKeyVault, delivery)
+	messenger := shhext.NewMessenger(nodeManager, accountManager, symKeyVault, delivery, encryptionService, outbox)
+	ingress := shhext.NewIngressFilterChain()
+	subscriber := shhext.NewWhisperSubscriber(nodeManager, accountManager, symKeyVault, ingress)
+	groups := shhext.NewGroupChatManager(nodeManager, accountManager, symKeyVault, subscriber)
+	metrics := shhext.NewEnvelopeMetrics(nodeManager)
+	rpcProxy := proxy.New(func() proxy.Caller {
+		// avoid non-nil interface holding nil client
+		if client := nodeManager.RPCClient(); client != nil {
+			return client
+		}
+		return nil
+	})
 
 	return &StatusBackend{
 		nodeManager:    nodeManager,
 		accountManager: accountManager,
 		jailManager:    jailManager,
 		txQueueManager: txQueueManager,
+		txHistory:      txHistory,
+		tokens:         tokenManager,
+		ens:            ensService,
+		symKeyVault:    symKeyVault,
+		mailHistory:    mailHistory,
+		mailService:    mailService,
+		delivery:       delivery,
+		encryption:     encryptionService,
+		messenger:      messenger,
+		outbox:         outbox,
+		subscriber:     subscriber,
+		ingress:        ingress,
+		groups:         groups,
+		metrics:        metrics,
+		rpcProxy:       rpcProxy,
 	}
 }
 
@@ -63,6 +124,81 @@ func (m *StatusBackend) TxQueueManager() common.TxQueueManager {
 	return m.txQueueManager
 }
 
+// TxHistory returns reference to history of transactions of accounts
+func (m *StatusBackend) TxHistory() *txhistory.History {
+	return m.txHistory
+}
+
+// Tokens returns reference to registry of tokens
+func (m *StatusBackend) Tokens() *tokens.Manager {
+	return m.tokens
+}
+
+// ENS returns reference to resolver of ENS names
+func (m *StatusBackend) ENS() *ens.Service {
+	return m.ens
+}
+
+// SymKeyVault returns reference to symmetric key vault
+func (m *StatusBackend) SymKeyVault() *shhext.SymKeyVault {
+	return m.symKeyVault
+}
+
+// MailHistory returns reference to mail server history tracker
+func (m *StatusBackend) MailHistory() *shhext.MailHistory {
+	return m.mailHistory
+}
+
+// MailService returns reference to sender of requests of historic messages to mail servers
+func (m *StatusBackend) MailService() *mailservice.Service {
+	return m.mailService
+}
+
+// DeliveryService returns reference to tracker of delivery of sent messages
+func (m *StatusBackend) DeliveryService() *shhext.DeliveryService {
+	return m.delivery
+}
+
+// Encryption returns reference to manager of encrypted one-to-one sessions
+func (m *StatusBackend) Encryption() *encryption.Service {
+	return m.encryption
+}
+
+// Messenger returns reference to chat messages sender
+func (m *StatusBackend) Messenger() *shhext.Messenger {
+	return m.messenger
+}
+
+// Outbox returns reference to outbox of Whisper messages
+func (m *StatusBackend) Outbox() *shhext.Outbox {
+	return m.outbox
+}
+
+// WhisperSubscriber returns reference to subscriber of Whisper messages
+func (m *StatusBackend) WhisperSubscriber() *shhext.WhisperSubscriber {
+	return m.subscriber
+}
+
+// IngressFilterChain returns reference to filters of received Whisper messages
+func (m *StatusBackend) IngressFilterChain() *shhext.IngressFilterChain {
+	return m.ingress
+}
+
+// GroupChatManager returns reference to manager of group chats
+func (m *StatusBackend) GroupChatManager() *shhext.GroupChatManager {
+	return m.groups
+}
+
+// EnvelopeMetrics returns reference to collector of Whisper envelope metrics
+func (m *StatusBackend) EnvelopeMetrics() *shhext.EnvelopeMetrics {
+	return m.metrics
+}
+
+// RPCProxy returns reference to local JSON-RPC proxy for dApps
+func (m *StatusBackend) RPCProxy() *proxy.Server {
+	return m.rpcProxy
+}
+
 // IsNodeRunning confirm that node is running
 func (m *StatusBackend) IsNodeRunning() bool {
 	return m.nodeManager.IsNodeRunning()
@@ -101,6 +237,50 @@ func (m *StatusBackend) onNodeStart(nodeStarted <-chan struct{}, backendReady ch
 	m.accountManager.ReSelectAccount()
 	log.Info("Account reselected")
 
+	if err := m.txQueueManager.RestoreTransactions(); err != nil {
+		log.Error("Queued transactions restoration failed", "err", err)
+	}
+
+	if err := m.txHistory.Start(); err != nil {
+		log.Error("Transaction history failed", "err", err)
+	}
+
+	if err := m.symKeyVault.Reinstall(); err != nil {
+		log.Error("Symmetric keys re-installation failed", "err", err)
+	}
+
+	if err := m.mailHistory.Start(); err != nil {
+		log.Error("Mail server history tracking failed", "err", err)
+	}
+
+	if err := m.mailService.Start(); err != nil {
+		log.Error("Mail server requests tracking failed", "err", err)
+	}
+
+	if err := m.delivery.Start(); err != nil {
+		log.Error("Messages delivery tracking failed", "err", err)
+	}
+
+	if err := m.messenger.Start(); err != nil {
+		log.Error("Loading of sent chat messages failed", "err", err)
+	}
+
+	if err := m.outbox.Start(); err != nil {
+		log.Error("Resending of queued Whisper messages failed", "err", err)
+	}
+
+	if err := m.subscriber.Start(); err != nil {
+		log.Error("Re-installation of Whisper subscriptions failed", "err", err)
+	}
+
+	if err := m.groups.Start(); err != nil {
+		log.Error("Subscription to group chat membership updates failed", "err", err)
+	}
+
+	if err := m.metrics.Start(); err != nil {
+		log.Error("Whisper metrics collection failed", "err", err)
+	}
+
 	close(backendReady)
 	signal.Send(signal.Envelope{
 		Type:  signal.EventNodeReady,
@@ -119,7 +299,14 @@ func (m *StatusBackend) StopNode() (<-chan struct{}, error) {
 	<-m.nodeReady
 
 	m.txQueueManager.Stop()
+	m.txHistory.Stop()
 	m.jailManager.Stop()
+	m.mailHistory.Stop()
+	m.mailService.Stop()
+	m.delivery.Stop()
+	m.outbox.Stop()
+	m.subscriber.Stop()
+	m.metrics.Stop()
 
 	nodeStopped, err := m.nodeManager.StopNode()
 	if err != nil {
@@ -159,6 +346,27 @@ func (m *StatusBackend) RestartNode() (<-chan struct{}, error) {
 	return m.nodeReady, err
 }
 
+// UpdateConfig restarts running Status node with a given configuration, fails if node is not running
+func (m *StatusBackend) UpdateConfig(config *params.NodeConfig) (<-chan struct{}, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.nodeReady == nil {
+		return nil, node.ErrNoRunningNode
+	}
+	<-m.nodeReady
+
+	nodeRestarted, err := m.nodeManager.UpdateConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	m.nodeReady = make(chan struct{}, 1)
+	go m.onNodeStart(nodeRestarted, m.nodeReady) // waits on nodeRestarted, writes to backendReady
+
+	return m.nodeReady, err
+}
+
 // ResetChainData remove chain data from data directory.
 // Node is stopped, and new node is started, with clean data directory.
 func (m *StatusBackend) ResetChainData() (<-chan struct{}, error) {
@@ -184,7 +392,9 @@ func (m *StatusBackend) ResetChainData() (<-chan struct{}, error) {
 // CallRPC executes RPC request on node's in-proc RPC server
 func (m *StatusBackend) CallRPC(inputJSON string) string {
 	client := m.nodeManager.RPCClient()
-	return client.CallRaw(inputJSON)
+	// failures are reported in response
+	response, _ := client.CallRaw(context.Background(), inputJSON)
+	return response
 }
 
 // SendTransaction creates a new transaction and waits until it's complete.
@@ -206,6 +416,60 @@ func (m *StatusBackend) SendTransaction(ctx context.Context, args common.SendTxA
 	return tx.Hash, nil
 }
 
+// QueueTokenTransfer queues transaction, which transfers amount of token (given by its address or symbol)
+// from selected account to recipient, and returns its ID right away. Transaction is completed, discarded,
+// or expires the same way as other queued transactions.
+func (m *StatusBackend) QueueTokenTransfer(ctx context.Context, token string, to gethcommon.Address, amount *big.Int) (common.QueuedTxID, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	selectedAccount, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		return "", err
+	}
+	args, err := m.tokens.TransferArgs(selectedAccount.Address, token, to, amount)
+	if err != nil {
+		return "", err
+	}
+
+	tx := m.txQueueManager.CreateTransaction(ctx, args)
+	if err := m.txQueueManager.QueueTransaction(tx); err != nil {
+		return "", err
+	}
+	// requester doesn't wait for transaction, but it must still be removed from the queue, once it's done
+	go m.txQueueManager.WaitForTransaction(tx) // nolint: errcheck
+
+	return tx.ID, nil
+}
+
+// QueueENSRegistration queues registration of username as subdomain of Status domain, pointing to
+// address and public key of the selected account, and returns ID of queued transaction
+func (m *StatusBackend) QueueENSRegistration(ctx context.Context, username string) (common.QueuedTxID, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	selectedAccount, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		return "", err
+	}
+	publicKey := crypto.FromECDSAPub(&selectedAccount.AccountKey.PrivateKey.PublicKey)
+	args, err := m.ens.RegisterSubdomain(ctx, selectedAccount.Address, username, publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	tx := m.txQueueManager.CreateTransaction(ctx, args)
+	if err := m.txQueueManager.QueueTransaction(tx); err != nil {
+		return "", err
+	}
+	// requester doesn't wait for transaction, but it must still be removed from the queue, once it's done
+	go m.txQueueManager.WaitForTransaction(tx) // nolint: errcheck
+
+	return tx.ID, nil
+}
+
 // CompleteTransaction instructs backend to complete sending of a given transaction
 func (m *StatusBackend) CompleteTransaction(id common.QueuedTxID, password string) (gethcommon.Hash, error) {
 	return m.txQueueManager.CompleteTransaction(id, password)
@@ -226,11 +490,45 @@ func (m *StatusBackend) DiscardTransactions(ids []common.QueuedTxID) map[common.
 	return m.txQueueManager.DiscardTransactions(ids)
 }
 
+// SelectAccount selects current account (see AccountManager.SelectAccount), and
+// unlocks symmetric keys of that account, installing them into Whisper, along with its encrypted sessions.
+func (m *StatusBackend) SelectAccount(address, password string) error {
+	if err := m.accountManager.SelectAccount(address, password); err != nil {
+		return err
+	}
+
+	selectedAccount, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		return err
+	}
+
+	if err := m.symKeyVault.Unlock(selectedAccount.Address, selectedAccount.AccountKey.PrivateKey); err != nil {
+		return err
+	}
+
+	return m.encryption.Unlock(selectedAccount.Address, selectedAccount.AccountKey.PrivateKey)
+}
+
+// Logout locks symmetric keys and encrypted sessions of the selected account and clears whisper identities
+func (m *StatusBackend) Logout() error {
+	m.symKeyVault.Lock()
+	m.encryption.Lock()
+
+	return m.accountManager.Logout()
+}
+
 // registerHandlers attaches Status callback handlers to running node
 func (m *StatusBackend) registerHandlers() error {
 	rpcClient := m.NodeManager().RPCClient()
 	rpcClient.RegisterHandler("eth_accounts", m.accountManager.AccountsRPCHandler())
 	rpcClient.RegisterHandler("eth_sendTransaction", m.txQueueManager.SendTransactionRPCHandler)
+	rpcClient.RegisterHandler("eth_signTypedData", m.txQueueManager.SignTypedDataRPCHandler)
+	rpcClient.RegisterHandler("eth_signTypedData_v4", m.txQueueManager.SignTypedDataRPCHandler)
+	rpcClient.RegisterHandler("personal_sign", m.txQueueManager.SignRPCHandler("personal_sign"))
+	rpcClient.RegisterHandler("eth_sign", m.txQueueManager.SignRPCHandler("eth_sign"))
+	rpcClient.RegisterHandler("status_version", func(context.Context, ...interface{}) (interface{}, error) {
+		return params.Build(), nil
+	})
 
 	m.txQueueManager.SetTransactionQueueHandler(m.txQueueManager.TransactionQueueHandler())
 	log.Info("Registered handler", "fn", "TransactionQueueHandler")
@@ -238,5 +536,17 @@ func (m *StatusBackend) registerHandlers() error {
 	m.txQueueManager.SetTransactionReturnHandler(m.txQueueManager.TransactionReturnHandler())
 	log.Info("Registered handler", "fn", "TransactionReturnHandler")
 
+	m.txQueueManager.SetTransactionSentHandler(m.txHistory.RecordSent)
+	log.Info("Registered handler", "fn", "TransactionSentHandler")
+
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+	if err := m.txQueueManager.SetSigningPolicy(config.SigningConfig); err != nil {
+		return err
+	}
+	m.txQueueManager.SetQueueConfig(config.TxQueueConfig)
+
 	return nil
 }
@@ -0,0 +1,112 @@
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes exponentially growing intervals between retries.
+type Backoff struct {
+	// Base is an interval before the first retry, doubled with every next retry
+	Base time.Duration
+
+	// Max limits interval between retries (0 means no limit)
+	Max time.Duration
+
+	// Jitter is a fraction (0..1) of interval, which is random, so that clients failed
+	// at the same time don't retry at once
+	Jitter float64
+}
+
+// Duration returns interval to wait before a given retry (starting from 1).
+func (b Backoff) Duration(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	d := b.Base
+	for i := 1; i < retry && (b.Max == 0 || d < b.Max); i++ {
+		if d > d<<1 {
+			break // overflow
+		}
+		d <<= 1
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+
+	jitter := time.Duration(float64(d) * b.Jitter)
+	if jitter <= 0 {
+		return d
+	}
+	if jitter > d {
+		jitter = d
+	}
+
+	return d - jitter + time.Duration(rand.Int63n(int64(jitter)+1))
+}
+
+// Policy describes retries of an operation.
+type Policy struct {
+	Backoff
+
+	// MaxAttempts limits number of attempts, including the first one (0 means no limit,
+	// operation is retried until context is done)
+	MaxAttempts int
+}
+
+// permanentError stops retries.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Permanent wraps err, so that Retry returns it without further attempts.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return permanentError{err}
+}
+
+// Retry calls fn (with number of attempt, starting from 0), until it succeeds or fails with permanent
+// error, waiting between attempts according to policy. The last error of fn is returned, once attempts
+// are exhausted, or ctx.Err(), if ctx is done while waiting for the next attempt.
+func Retry(ctx context.Context, policy Policy, fn func(attempt int) error) error {
+	var err error
+	for attempt := 0; policy.MaxAttempts == 0 || attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if err := Sleep(ctx, policy.Duration(attempt)); err != nil {
+				return err
+			}
+		}
+
+		err = fn(attempt)
+		if err == nil {
+			return nil
+		}
+		if permanent, ok := err.(permanentError); ok {
+			return permanent.err
+		}
+	}
+
+	return err
+}
+
+// Sleep waits for d, returning ctx.Err(), if ctx is done earlier.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
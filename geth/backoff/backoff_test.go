@@ -0,0 +1,76 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	b := Backoff{Base: 500 * time.Millisecond, Max: 10 * time.Second}
+	require.Equal(t, 500*time.Millisecond, b.Duration(1))
+	require.Equal(t, time.Second, b.Duration(2))
+	require.Equal(t, 8*time.Second, b.Duration(5))
+	require.Equal(t, 10*time.Second, b.Duration(6))
+	require.Equal(t, 10*time.Second, b.Duration(1000))
+
+	b.Max = 0
+	require.True(t, b.Duration(1000) > 0)
+
+	b = Backoff{Base: 500 * time.Millisecond, Max: 10 * time.Second, Jitter: 0.5}
+	for retry := 1; retry < 100; retry++ {
+		d := b.Duration(retry)
+		require.True(t, d >= b.Base/2, "retry %d: %s", retry, d)
+		require.True(t, d <= b.Max, "retry %d: %s", retry, d)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := Policy{Backoff: Backoff{Base: time.Millisecond}, MaxAttempts: 3}
+	errFailed := errors.New("failed")
+
+	// attempts are exhausted
+	var attempts []int
+	err := Retry(context.Background(), policy, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		return errFailed
+	})
+	require.Equal(t, errFailed, err)
+	require.Equal(t, []int{0, 1, 2}, attempts)
+
+	// operation succeeds
+	attempts = nil
+	err = Retry(context.Background(), policy, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		if attempt < 1 {
+			return errFailed
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, attempts)
+
+	// permanent error stops retries
+	attempts = nil
+	err = Retry(context.Background(), policy, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		return Permanent(errFailed)
+	})
+	require.Equal(t, errFailed, err)
+	require.Equal(t, []int{0}, attempts)
+
+	// retries are stopped, once context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	policy = Policy{Backoff: Backoff{Base: time.Hour}}
+	attempts = nil
+	err = Retry(ctx, policy, func(attempt int) error {
+		attempts = append(attempts, attempt)
+		cancel()
+		return errFailed
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, []int{0}, attempts)
+}
@@ -0,0 +1,20 @@
+/*
+Package backoff - retries of failed operations with jittered exponential backoff.
+
+Subsystems, which retry failed calls (upstream RPC calls, mail server requests), describe
+retries with Policy and run them with Retry, so that they wait between attempts the same way
+and stop once attempts are exhausted, error is permanent, or context is done:
+
+	policy := backoff.Policy{
+		Backoff:     backoff.Backoff{Base: time.Second, Max: 10 * time.Second, Jitter: 0.5},
+		MaxAttempts: 3,
+	}
+	err := backoff.Retry(ctx, policy, func(attempt int) error {
+		err := call()
+		if isFatal(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	})
+*/
+package backoff
@@ -0,0 +1,19 @@
+// Package message defines states of delivery of outgoing Whisper messages.
+package message
+
+// Status is a state of delivery of outgoing message.
+type Status string
+
+// Statuses of delivery, in order messages go through them
+const (
+	Pending   Status = "pending"   // not posted yet, e.g. Whisper was not available
+	Queued    Status = "queued"    // accepted by Whisper, not sent to any peer yet
+	Sent      Status = "sent"      // sent to at least one peer
+	Delivered Status = "delivered" // received back from another peer, i.e. it's propagated through network
+	Expired   Status = "expired"   // TTL passed, before message was delivered
+)
+
+// Final checks whether status is not changed anymore.
+func (s Status) Final() bool {
+	return s == Delivered || s == Expired
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
@@ -13,11 +14,13 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/les"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/discover"
 	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
-	"github.com/robertkrimen/otto"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/peers"
 	"github.com/status-im/status-go/geth/rpc"
 	"github.com/status-im/status-go/static"
 )
@@ -27,6 +30,59 @@ var (
 	ErrDeprecatedMethod = errors.New("Method is depricated and will be removed in future release")
 )
 
+// GeneratedAccount describes an account generated with AccountManager.GenerateAccounts
+type GeneratedAccount struct {
+	Index    int    `json:"index"`
+	Address  string `json:"address"`
+	PubKey   string `json:"pubkey"`
+	Mnemonic string `json:"mnemonic"`
+}
+
+// DerivedAccount describes an account derived from HD wallet with AccountManager.DeriveChildAccounts
+type DerivedAccount struct {
+	Path    string `json:"path"`
+	Address string `json:"address"`
+	PubKey  string `json:"pubkey"`
+}
+
+// WatchOnlyAccount describes an account added with AccountManager.AddWatchOnlyAccount
+type WatchOnlyAccount struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// WhisperIdentity is a chat identity of selected account, injected into Whisper
+type WhisperIdentity struct {
+	Address string `json:"address"`
+	PubKey  string `json:"pubkey"`
+}
+
+// ContactKey is a key of selected account, derived for a contact with AccountManager.DeriveContactKey
+type ContactKey struct {
+	ContactPubKey string `json:"contactPubKey"`
+	PubKey        string `json:"pubkey"`
+	KeyID         string `json:"keyId"` // ID of key pair in Whisper
+}
+
+// AccountMetadata is a label and avatar of account, or of address book contact, kept in AccountsMetadata
+type AccountMetadata struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+	Avatar  string `json:"avatar,omitempty"` // e.g. data URI of image
+	Contact bool   `json:"contact"`          // true for address book contacts, false for own accounts
+}
+
+// HardwareWallet is a connection to hardware wallet (Ledger, Trezor), provided by host app.
+// Transactions of its accounts are signed on the device, once user confirms them there.
+// Wallets of go-ethereum's accounts/usbwallet satisfy it.
+type HardwareWallet interface {
+	// Contains returns true, if account belongs to the wallet.
+	Contains(account accounts.Account) bool
+
+	// SignTx requests the device to sign transaction, blocking until user confirms or rejects it.
+	SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
 // SelectedExtKey is a container for currently selected (logged in) account
 type SelectedExtKey struct {
 	Address     common.Address
@@ -55,6 +111,9 @@ type NodeManager interface {
 	// RestartNode restart running Status node, fails if node is not running
 	RestartNode() (<-chan struct{}, error)
 
+	// UpdateConfig restarts running Status node with a given configuration, fails if node is not running
+	UpdateConfig(config *params.NodeConfig) (<-chan struct{}, error)
+
 	// ResetChainData remove chain data from data directory.
 	// Node is stopped, and new node is started, with clean data directory.
 	ResetChainData() (<-chan struct{}, error)
@@ -74,6 +133,9 @@ type NodeManager interface {
 	// AddPeer adds URL of static peer
 	AddPeer(url string) error
 
+	// Peers returns info of connected peers, along with their latency and quality measurements
+	Peers() ([]peers.Info, error)
+
 	// LightEthereumService exposes reference to LES service running on top of the node
 	LightEthereumService() (*les.LightEthereum, error)
 
@@ -88,6 +150,27 @@ type NodeManager interface {
 
 	// RPCClient exposes reference to RPC client connected to the running node
 	RPCClient() *rpc.Client
+
+	// StartRPCEndpoint opens HTTP, WS and/or IPC JSON-RPC servers of a running node
+	StartRPCEndpoint(config params.RPCEndpointConfig) error
+
+	// RPCEndpointToken returns token of session, requests to endpoint opened with StartRPCEndpoint must carry
+	RPCEndpointToken() (string, error)
+
+	// StopRPCEndpoint closes JSON-RPC servers opened with StartRPCEndpoint
+	StopRPCEndpoint() error
+
+	// NodeID returns ID of the running node (public part of node's devp2p key)
+	NodeID() (discover.NodeID, error)
+
+	// ExportNodeKey returns hex-encoded private devp2p key of the running node
+	ExportNodeKey() (string, error)
+
+	// ImportNodeKey replaces devp2p key of the running node with a given hex-encoded one (node is restarted)
+	ImportNodeKey(hexKey string) (<-chan struct{}, error)
+
+	// RotateNodeKey replaces devp2p key of the running node with a freshly generated one (node is restarted)
+	RotateNodeKey() (<-chan struct{}, error)
 }
 
 // AccountManager defines expected methods for managing Status accounts
@@ -107,6 +190,57 @@ type AccountManager interface {
 	// Once master key is re-generated, it is inserted into keystore (if not already there).
 	RecoverAccount(password, mnemonic string) (address, pubKey string, err error)
 
+	// CreateAccountFromMnemonic creates HD wallet from a given BIP39 mnemonic phrase, protected with password.
+	// Main account (m/44'/60'/0'/0/0) is imported into keystore.
+	CreateAccountFromMnemonic(mnemonic, password string) (address, pubKey string, err error)
+
+	// DeriveChildAccounts derives count accounts of HD wallet of selected account, by appending indexes
+	// 0..count-1 to path (m/44'/60'/0'/0, if path is empty).
+	DeriveChildAccounts(path string, count int, password string) ([]DerivedAccount, error)
+
+	// LoadAccountAtPath derives account of HD wallet of selected account at a given path.
+	LoadAccountAtPath(path, password string) (DerivedAccount, error)
+
+	// ChangePassword re-encrypts key of account (and master key of its HD wallet) with newPassword.
+	// Files are replaced atomically, selected account stays selected.
+	ChangePassword(address, oldPassword, newPassword string) error
+
+	// VerifyMnemonic returns true, if mnemonic phrase (with password as BIP39 passphrase) derives key
+	// of account identified by address. Nothing is imported into keystore.
+	VerifyMnemonic(address, mnemonic, password string) (bool, error)
+
+	// ExportAccount returns key of account identified by address in standard V3 JSON format,
+	// encrypted with exportPassword instead of password of account.
+	ExportAccount(address, password, exportPassword string) (string, error)
+
+	// ImportAccount imports key in V3 JSON format, encrypted with password, into keystore.
+	// Imported key is encrypted with newPassword.
+	ImportAccount(keyJSON, password, newPassword string) (address string, err error)
+
+	// ExportKeystoreBundle returns backup of all accounts of keystore, encrypted with exportPassword.
+	ExportKeystoreBundle(exportPassword string) (string, error)
+
+	// ImportKeystoreBundle restores accounts from bundle, encrypted with exportPassword.
+	// Addresses of imported accounts are returned.
+	ImportKeystoreBundle(bundle, exportPassword string) ([]string, error)
+
+	// AddWatchOnlyAccount adds address, private key of which is not known (e.g. of cold wallet).
+	// Watch-only accounts are listed along with accounts of selected account, but can't sign anything.
+	AddWatchOnlyAccount(address, name string) error
+
+	// RemoveWatchOnlyAccount removes watch-only account, added with AddWatchOnlyAccount.
+	RemoveWatchOnlyAccount(address string) error
+
+	// WatchOnlyAccounts returns watch-only accounts, sorted by address.
+	WatchOnlyAccounts() ([]WatchOnlyAccount, error)
+
+	// IsWatchOnlyAccount returns true, if address has been added with AddWatchOnlyAccount.
+	IsWatchOnlyAccount(address string) bool
+
+	// GenerateAccounts creates count throwaway accounts, with mnemonics derived deterministically from seed
+	// (for load tests and QA environments).
+	GenerateAccounts(seed string, count int, password string) ([]GeneratedAccount, error)
+
 	// VerifyAccountPassword tries to decrypt a given account key file, with a provided password.
 	// If no error is returned, then account is considered verified.
 	VerifyAccountPassword(keyStoreDir, address, password string) (*keystore.Key, error)
@@ -122,19 +256,83 @@ type AccountManager interface {
 	// SelectedAccount returns currently selected account
 	SelectedAccount() (*SelectedExtKey, error)
 
-	// Logout clears whisper identities
+	// AddSelectedAccount selects account in addition to the main one, so that it is listed by Accounts,
+	// can sign transactions, and can be bound to origins with BindAccount.
+	AddSelectedAccount(address, password string) error
+
+	// RemoveSelectedAccount deselects account added with AddSelectedAccount. Origins bound to it are unbound.
+	RemoveSelectedAccount(address string) error
+
+	// SelectedAccountByAddress returns selected account with a given address, either the main one,
+	// or one added with AddSelectedAccount.
+	SelectedAccountByAddress(address string) (*SelectedExtKey, error)
+
+	// BindAccount binds account to origin of requests (chatID of jail cell, or origin of dapp),
+	// so that eth_accounts sent from it returns that account only, and transactions sent from it
+	// are sent from that account by default.
+	BindAccount(origin, address string) error
+
+	// UnbindAccount removes binding of origin, added with BindAccount.
+	UnbindAccount(origin string)
+
+	// InjectWhisperIdentity adds key of selected account into Whisper as chat identity, along with identities
+	// injected so far. Wallet key selection is not changed.
+	InjectWhisperIdentity(address string) error
+
+	// RemoveWhisperIdentity removes chat identity of account from Whisper, account stays selected.
+	RemoveWhisperIdentity(address string) error
+
+	// WhisperIdentities returns chat identities of selected accounts, injected into Whisper, sorted by address.
+	WhisperIdentities() ([]WhisperIdentity, error)
+
+	// DeriveContactKey derives deterministic key of selected account, dedicated to a contact identified
+	// by its public key, and adds it into Whisper.
+	DeriveContactKey(contactPubKey string) (ContactKey, error)
+
+	// BoundAccount returns account bound to origin with BindAccount.
+	BoundAccount(origin string) (common.Address, bool)
+
+	// Logout locks the wallet: decrypted keys of selected accounts are scrubbed from memory, selected account
+	// is reset, and whisper identities are cleared.
 	Logout() error
 
 	// Accounts returns handler to process account list request
 	Accounts() ([]common.Address, error)
 
-	// AccountsRPCHandler returns RPC wrapper for Accounts()
+	// AccountsRPCHandler returns RPC wrapper for Accounts(), which respects accounts bound to origins
 	AccountsRPCHandler() rpc.Handler
 
+	// SignTypedData signs EIP-712 typed data (JSON encoded) with the key of selected account,
+	// which must be the one identified by address.
+	SignTypedData(address, typedData string) ([]byte, error)
+
+	// SignMessage signs message the way personal_sign and eth_sign do, with the key of selected account,
+	// which must be the one identified by address.
+	SignMessage(address string, message []byte) ([]byte, error)
+
 	// AddressToDecryptedAccount tries to load decrypted key for a given account.
 	// The running node, has a keystore directory which is loaded on start. Key file
 	// for a given address is expected to be in that directory prior to node start.
 	AddressToDecryptedAccount(address, password string) (accounts.Account, *keystore.Key, error)
+
+	// AccountsMetadata returns persistent store of labels and avatars of accounts, and of address book contacts.
+	AccountsMetadata() AccountsMetadata
+}
+
+// AccountsMetadata is a persistent store of labels and avatars of accounts, and of address book contacts.
+// It is kept in data dir, so that it is shared by all clients of the node.
+type AccountsMetadata interface {
+	// Set adds metadata of address, or replaces existing one.
+	Set(metadata AccountMetadata) error
+
+	// Get returns metadata of address.
+	Get(address string) (AccountMetadata, error)
+
+	// List returns metadata of all addresses, sorted by address.
+	List() ([]AccountMetadata, error)
+
+	// Delete removes metadata of address.
+	Delete(address string) error
 }
 
 // RawCompleteTransactionResult is a JSON returned from transaction complete function (used internally)
@@ -151,16 +349,43 @@ type RawDiscardTransactionResult struct {
 // QueuedTxID queued transaction identifier
 type QueuedTxID string
 
+// SignRequestID identifies request to sign a message (see TxQueueManager.SignRPCHandler)
+type SignRequestID string
+
 // QueuedTx holds enough information to complete the queued transaction.
 type QueuedTx struct {
 	ID         QueuedTxID
 	Hash       common.Hash
 	Context    context.Context
 	Args       SendTxArgs
-	InProgress bool // true if transaction is being sent
+	Origin     string // chatID (or dapp origin), transaction has been requested by
+	InProgress bool   // true if transaction is being sent
 	Done       chan struct{}
 	Discard    chan struct{}
 	Err        error
+
+	TypedData string        // EIP-712 typed data (JSON) to be signed instead of sending transaction
+	Signature hexutil.Bytes // signature of TypedData, once it is signed
+
+	Queued time.Time // when transaction has been put into the queue
+}
+
+// TxEstimate is an estimation of gas and fee of transaction, see TxQueueManager.EstimateTransaction
+type TxEstimate struct {
+	Gas      *hexutil.Big `json:"gas"`
+	GasPrice *hexutil.Big `json:"gasPrice"`
+	Fee      *hexutil.Big `json:"fee"` // gas * gasPrice, in wei
+}
+
+// PendingTransaction describes queued transaction, returned by TxQueueManager.PendingTransactions
+type PendingTransaction struct {
+	ID        string          `json:"id"`
+	Args      SendTxArgs      `json:"args"`
+	Origin    string          `json:"origin"` // chatID of jail cell, or origin of dapp
+	MessageID string          `json:"message_id"`
+	TypedData json.RawMessage `json:"typed_data,omitempty"` // set for requests to sign typed data
+	QueuedAt  int64           `json:"queued_at"`            // unix time, in milliseconds
+	ExpiresAt int64           `json:"expires_at"`           // unix time, in milliseconds
 }
 
 // SendTxArgs represents the arguments to submit a new transaction into the transaction pool.
@@ -172,6 +397,8 @@ type SendTxArgs struct {
 	Value    *hexutil.Big    `json:"value"`
 	Data     hexutil.Bytes   `json:"data"`
 	Nonce    *hexutil.Uint64 `json:"nonce"`
+	ChainID  *hexutil.Big    `json:"chainId,omitempty"` // network (EIP-155) transaction is meant for, if set
+	Type     *hexutil.Uint64 `json:"type,omitempty"`    // type of transaction envelope (EIP-2718), legacy if unset
 }
 
 // EnqueuedTxHandler is a function that receives queued/pending transactions, when they get queued
@@ -180,6 +407,9 @@ type EnqueuedTxHandler func(*QueuedTx)
 // EnqueuedTxReturnHandler is a function that receives response when tx is complete (both on success and error)
 type EnqueuedTxReturnHandler func(*QueuedTx, error)
 
+// SentTxHandler is a function that receives completed transactions, along with hashes they have been sent with
+type SentTxHandler func(*QueuedTx, common.Hash)
+
 // TxQueue is a queue of transactions.
 type TxQueue interface {
 	// Remove removes a transaction from the queue.
@@ -203,6 +433,10 @@ type TxQueueManager interface {
 	// Stop stops accepting new transactions in the queue.
 	Stop()
 
+	// RestoreTransactions makes transaction queue persistent, and queues transactions persisted
+	// before restart (or crash) of the app again.
+	RestoreTransactions() error
+
 	// TransactionQueue returns a transaction queue.
 	TransactionQueue() TxQueue
 
@@ -227,8 +461,23 @@ type TxQueueManager interface {
 	// TODO(adam): might be not needed
 	SetTransactionReturnHandler(fn EnqueuedTxReturnHandler)
 
+	// SetTransactionSentHandler sets a handler, which is called once transaction is sent.
+	SetTransactionSentHandler(fn SentTxHandler)
+
 	SendTransactionRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error)
 
+	// SignTypedDataRPCHandler is a handler for eth_signTypedData methods, queueing requests to sign typed data
+	SignTypedDataRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error)
+
+	// SignRPCHandler returns handler for personal_sign or eth_sign method, queueing requests to sign messages
+	SignRPCHandler(method string) rpc.Handler
+
+	// ApproveSignRequest signs message of a given request with selected account, once password is verified
+	ApproveSignRequest(id SignRequestID, password string) (hexutil.Bytes, error)
+
+	// DiscardSignRequest discards a given request to sign a message
+	DiscardSignRequest(id SignRequestID) error
+
 	// TransactionReturnHandler returns handler that processes responses from internal tx manager
 	TransactionReturnHandler() func(queuedTx *QueuedTx, err error)
 
@@ -238,28 +487,124 @@ type TxQueueManager interface {
 	// CompleteTransactions instructs backend to complete sending of multiple transactions
 	CompleteTransactions(ids []QueuedTxID, password string) map[QueuedTxID]RawCompleteTransactionResult
 
+	// SpeedUpTransaction sends pending transaction again, with the same nonce and higher gas price.
+	SpeedUpTransaction(hash common.Hash, gasPrice *big.Int, password string) (common.Hash, error)
+
+	// CancelTransaction replaces pending transaction with transfer of zero value to sender, with the same nonce.
+	CancelTransaction(hash common.Hash, password string) (common.Hash, error)
+
 	// DiscardTransaction discards a given transaction from transaction queue
 	DiscardTransaction(id QueuedTxID) error
 
 	// DiscardTransactions discards given multiple transactions from transaction queue
 	DiscardTransactions(ids []QueuedTxID) map[QueuedTxID]RawDiscardTransactionResult
+
+	// SetSigningPolicy sets policy, queued transactions are checked against (nil config disables it)
+	SetSigningPolicy(config *params.SigningConfig) error
+
+	// SetQueueConfig sets limits of transaction queue (defaults are used for nil config)
+	SetQueueConfig(config *params.TxQueueConfig)
+
+	// TransactionsByOrigin returns queued transactions, requested by a given origin
+	TransactionsByOrigin(origin string) []*QueuedTx
+
+	// PendingTransactions returns details of queued transactions, in order they have been queued.
+	PendingTransactions() []PendingTransaction
+
+	// EstimateTransaction estimates gas of transaction and suggests gas price, unless they are set in args.
+	EstimateTransaction(args SendTxArgs) (TxEstimate, error)
+
+	// DiscardTransactionsByOrigin discards all queued transactions, requested by a given origin
+	DiscardTransactionsByOrigin(origin string) map[QueuedTxID]RawDiscardTransactionResult
+
+	// SetHardwareWallet sets hardware wallet, transactions of its accounts are signed with (nil disconnects it)
+	SetHardwareWallet(wallet HardwareWallet)
+}
+
+// JailValue is a JavaScript value of jail cell, independent of JavaScript engine of cell.
+type JailValue interface {
+	fmt.Stringer
+
+	// Export converts value to Go value (e.g. object to map[string]interface{}).
+	Export() (interface{}, error)
+
+	IsDefined() bool
+	IsUndefined() bool
+	IsNull() bool
+	IsBoolean() bool
+	IsNumber() bool
+	IsString() bool
+	IsObject() bool
+	IsFunction() bool
+
+	// Class returns class of object (e.g. "Object", "Array", "Error"), empty for primitive values.
+	Class() string
+
+	ToBoolean() (bool, error)
+	ToInteger() (int64, error)
+	ToFloat() (float64, error)
+	ToString() (string, error)
+
+	// Get returns property of object, undefined for primitive values.
+	Get(name string) (JailValue, error)
+	// Set sets property of object.
+	Set(name string, value interface{}) error
+	// Keys returns names of enumerable own properties of object.
+	Keys() []string
+	// Call calls function value with given this and arguments.
+	Call(this interface{}, args ...interface{}) (JailValue, error)
+}
+
+// JailScript is JavaScript code compiled by jail cell, to be run later by cells of the same engine.
+type JailScript interface {
+	// Filename returns name, script has been compiled with.
+	Filename() string
 }
 
 // JailCell represents single jail cell, which is basically a JavaScript VM.
-// It's designed to be a transparent wrapper around otto.VM's methods.
+// It's designed to be a transparent wrapper around methods of JavaScript engine of cell.
 type JailCell interface {
 	// Set a value inside VM.
 	Set(string, interface{}) error
 	// Get a value from VM.
-	Get(string) (otto.Value, error)
-	// Run an arbitrary JS code. Input maybe string or otto.Script.
-	Run(interface{}) (otto.Value, error)
+	Get(string) (JailValue, error)
+	// Run an arbitrary JS code. Input maybe string or JailScript.
+	Run(interface{}) (JailValue, error)
+	// Compile parses JS code as a named script, to be run later.
+	Compile(filename string, src interface{}) (JailScript, error)
 	// Call an arbitrary JS function by name and args.
-	Call(item string, this interface{}, args ...interface{}) (otto.Value, error)
+	Call(item string, this interface{}, args ...interface{}) (JailValue, error)
 	// Stop stops background execution of cell.
 	Stop()
 }
 
+// JailCellInfo describes live jail cell.
+type JailCellInfo struct {
+	ChatID       string `json:"chatID"`
+	StartedAt    int64  `json:"startedAt"`    // unix time, when cell has been created (or restored after eviction)
+	Uptime       int64  `json:"uptime"`       // seconds
+	Calls        uint64 `json:"calls"`        // number of calls handled since cell has been started
+	BundleSize   int    `json:"bundleSize"`   // size (in bytes) of JavaScript code, cell has been parsed with
+	CPUTime      int64  `json:"cpuTime"`      // milliseconds spent executing JavaScript code of cell since it has been started
+	ParseVersion uint64 `json:"parseVersion"` // version of base JavaScript, cell has been parsed with (see JailManager.ParseVersion)
+}
+
+// JailLogEntry is an entry, jail cell has logged with console.
+type JailLogEntry struct {
+	Level     string `json:"level"` // "log", "info", "debug", "warn" or "error"
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"` // unix time (in milliseconds)
+}
+
+// JailPermissions restrict RPC requests of jail cell (see JailManager.SetPermissions),
+// everything not granted is denied.
+type JailPermissions struct {
+	SendTransactions bool     `json:"sendTransactions"` // send transactions and sign data
+	ReadAccounts     bool     `json:"readAccounts"`     // list accounts (eth_accounts, eth_coinbase)
+	RPCMethods       []string `json:"rpcMethods"`       // other allowed methods, "eth_*" allows all methods with prefix
+	WhisperTopics    []string `json:"whisperTopics"`    // topics, cell may post to and receive messages of
+}
+
 // JailManager defines methods for managing jailed environments
 type JailManager interface {
 	// Parse creates a new jail cell context, with the given chatID as identifier.
@@ -269,15 +614,52 @@ type JailManager interface {
 	// Call executes given JavaScript function w/i a jail cell context identified by the chatID.
 	Call(chatID, this, args string) string
 
+	// CallAsync queues call of JavaScript function w/i a jail cell and returns ID of call,
+	// response is sent as a signal.
+	CallAsync(chatID, this, args string) string
+
 	// NewCell initializes and returns a new jail cell.
 	NewCell(chatID string) (JailCell, error)
 
 	// Cell returns an existing instance of JailCell.
 	Cell(chatID string) (JailCell, error)
 
+	// RemoveCell stops jail cell, halting its pending executions, and removes it.
+	RemoveCell(chatID string) error
+
+	// Cells returns stats of live jail cells.
+	Cells() []JailCellInfo
+
+	// Storage returns items, jail cell keeps in its localStorage.
+	Storage(chatID string) (map[string]string, error)
+
+	// ClearStorage removes all items, jail cell keeps in its localStorage.
+	ClearStorage(chatID string) error
+
+	// SetFetchWhitelist changes origins, jail cell may send requests to with fetch().
+	SetFetchWhitelist(chatID string, origins []string)
+
+	// SetPermissions restricts RPC requests of jail cell (nil lifts restrictions).
+	SetPermissions(chatID string, permissions *JailPermissions)
+
+	// CellLogs returns the last n entries, jail cell has logged with console.
+	CellLogs(chatID string, n int) []JailLogEntry
+
+	// CallQueueDepth returns number of calls of jail cell, which are executed or wait for execution.
+	CallQueueDepth(chatID string) int
+
 	// BaseJS allows to setup initial JavaScript to be loaded on each jail.Parse()
 	BaseJS(js string)
 
+	// ReloadBaseJS changes base JavaScript and re-parses live cells with it, keeping their state.
+	ReloadBaseJS(js string) error
+
+	// ParseVersion returns version of base JavaScript, cells are parsed with.
+	ParseVersion() uint64
+
+	// RegisterModule compiles JavaScript module once, so that all cells can load it with require(name).
+	RegisterModule(name, js string) error
+
 	// Stop stops all background activity of jail
 	Stop()
 }
@@ -367,6 +749,13 @@ type CompleteTransactionsResult struct {
 	Results map[string]CompleteTransactionResult `json:"results"`
 }
 
+// ApproveSignRequestResult is a JSON returned from ApproveSignRequest (used in exposed method)
+type ApproveSignRequestResult struct {
+	ID        string `json:"id"`
+	Signature string `json:"signature"`
+	Error     string `json:"error"`
+}
+
 // DiscardTransactionResult is a JSON returned from transaction discard function
 type DiscardTransactionResult struct {
 	ID    string `json:"id"`
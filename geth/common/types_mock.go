@@ -9,13 +9,16 @@ import (
 	accounts "github.com/ethereum/go-ethereum/accounts"
 	keystore "github.com/ethereum/go-ethereum/accounts/keystore"
 	common "github.com/ethereum/go-ethereum/common"
+	hexutil "github.com/ethereum/go-ethereum/common/hexutil"
 	les "github.com/ethereum/go-ethereum/les"
 	node "github.com/ethereum/go-ethereum/node"
+	discover "github.com/ethereum/go-ethereum/p2p/discover"
 	whisperv5 "github.com/ethereum/go-ethereum/whisper/whisperv5"
 	gomock "github.com/golang/mock/gomock"
-	otto "github.com/robertkrimen/otto"
 	params "github.com/status-im/status-go/geth/params"
+	peers "github.com/status-im/status-go/geth/peers"
 	rpc "github.com/status-im/status-go/geth/rpc"
+	big "math/big"
 	reflect "reflect"
 )
 
@@ -220,6 +223,121 @@ func (mr *MockNodeManagerMockRecorder) RPCClient() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPCClient", reflect.TypeOf((*MockNodeManager)(nil).RPCClient))
 }
 
+// StartRPCEndpoint mocks base method
+func (m *MockNodeManager) StartRPCEndpoint(config params.RPCEndpointConfig) error {
+	ret := m.ctrl.Call(m, "StartRPCEndpoint", config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartRPCEndpoint indicates an expected call of StartRPCEndpoint
+func (mr *MockNodeManagerMockRecorder) StartRPCEndpoint(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRPCEndpoint", reflect.TypeOf((*MockNodeManager)(nil).StartRPCEndpoint), config)
+}
+
+// StopRPCEndpoint mocks base method
+func (m *MockNodeManager) StopRPCEndpoint() error {
+	ret := m.ctrl.Call(m, "StopRPCEndpoint")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopRPCEndpoint indicates an expected call of StopRPCEndpoint
+func (mr *MockNodeManagerMockRecorder) StopRPCEndpoint() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopRPCEndpoint", reflect.TypeOf((*MockNodeManager)(nil).StopRPCEndpoint))
+}
+
+// NodeID mocks base method
+func (m *MockNodeManager) NodeID() (discover.NodeID, error) {
+	ret := m.ctrl.Call(m, "NodeID")
+	ret0, _ := ret[0].(discover.NodeID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NodeID indicates an expected call of NodeID
+func (mr *MockNodeManagerMockRecorder) NodeID() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeID", reflect.TypeOf((*MockNodeManager)(nil).NodeID))
+}
+
+// ExportNodeKey mocks base method
+func (m *MockNodeManager) ExportNodeKey() (string, error) {
+	ret := m.ctrl.Call(m, "ExportNodeKey")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportNodeKey indicates an expected call of ExportNodeKey
+func (mr *MockNodeManagerMockRecorder) ExportNodeKey() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportNodeKey", reflect.TypeOf((*MockNodeManager)(nil).ExportNodeKey))
+}
+
+// ImportNodeKey mocks base method
+func (m *MockNodeManager) ImportNodeKey(hexKey string) (<-chan struct{}, error) {
+	ret := m.ctrl.Call(m, "ImportNodeKey", hexKey)
+	ret0, _ := ret[0].(<-chan struct{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportNodeKey indicates an expected call of ImportNodeKey
+func (mr *MockNodeManagerMockRecorder) ImportNodeKey(hexKey interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportNodeKey", reflect.TypeOf((*MockNodeManager)(nil).ImportNodeKey), hexKey)
+}
+
+// RotateNodeKey mocks base method
+func (m *MockNodeManager) RotateNodeKey() (<-chan struct{}, error) {
+	ret := m.ctrl.Call(m, "RotateNodeKey")
+	ret0, _ := ret[0].(<-chan struct{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateNodeKey indicates an expected call of RotateNodeKey
+func (mr *MockNodeManagerMockRecorder) RotateNodeKey() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateNodeKey", reflect.TypeOf((*MockNodeManager)(nil).RotateNodeKey))
+}
+
+// Peers mocks base method
+func (m *MockNodeManager) Peers() ([]peers.Info, error) {
+	ret := m.ctrl.Call(m, "Peers")
+	ret0, _ := ret[0].([]peers.Info)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Peers indicates an expected call of Peers
+func (mr *MockNodeManagerMockRecorder) Peers() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Peers", reflect.TypeOf((*MockNodeManager)(nil).Peers))
+}
+
+// UpdateConfig mocks base method
+func (m *MockNodeManager) UpdateConfig(config *params.NodeConfig) (<-chan struct{}, error) {
+	ret := m.ctrl.Call(m, "UpdateConfig", config)
+	ret0, _ := ret[0].(<-chan struct{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateConfig indicates an expected call of UpdateConfig
+func (mr *MockNodeManagerMockRecorder) UpdateConfig(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockNodeManager)(nil).UpdateConfig), config)
+}
+
+// RPCEndpointToken mocks base method
+func (m *MockNodeManager) RPCEndpointToken() (string, error) {
+	ret := m.ctrl.Call(m, "RPCEndpointToken")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RPCEndpointToken indicates an expected call of RPCEndpointToken
+func (mr *MockNodeManagerMockRecorder) RPCEndpointToken() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RPCEndpointToken", reflect.TypeOf((*MockNodeManager)(nil).RPCEndpointToken))
+}
+
 // MockAccountManager is a mock of AccountManager interface
 type MockAccountManager struct {
 	ctrl     *gomock.Controller
@@ -387,283 +505,1177 @@ func (mr *MockAccountManagerMockRecorder) AddressToDecryptedAccount(address, pas
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddressToDecryptedAccount", reflect.TypeOf((*MockAccountManager)(nil).AddressToDecryptedAccount), address, password)
 }
 
-// MockTxQueue is a mock of TxQueue interface
-type MockTxQueue struct {
+// GenerateAccounts mocks base method
+func (m *MockAccountManager) GenerateAccounts(seed string, count int, password string) ([]GeneratedAccount, error) {
+	ret := m.ctrl.Call(m, "GenerateAccounts", seed, count, password)
+	ret0, _ := ret[0].([]GeneratedAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateAccounts indicates an expected call of GenerateAccounts
+func (mr *MockAccountManagerMockRecorder) GenerateAccounts(seed, count, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAccounts", reflect.TypeOf((*MockAccountManager)(nil).GenerateAccounts), seed, count, password)
+}
+
+// SignTypedData mocks base method
+func (m *MockAccountManager) SignTypedData(address, typedData string) ([]byte, error) {
+	ret := m.ctrl.Call(m, "SignTypedData", address, typedData)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignTypedData indicates an expected call of SignTypedData
+func (mr *MockAccountManagerMockRecorder) SignTypedData(address, typedData interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTypedData", reflect.TypeOf((*MockAccountManager)(nil).SignTypedData), address, typedData)
+}
+
+// SignMessage mocks base method
+func (m *MockAccountManager) SignMessage(address string, message []byte) ([]byte, error) {
+	ret := m.ctrl.Call(m, "SignMessage", address, message)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignMessage indicates an expected call of SignMessage
+func (mr *MockAccountManagerMockRecorder) SignMessage(address, message interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignMessage", reflect.TypeOf((*MockAccountManager)(nil).SignMessage), address, message)
+}
+
+// CreateAccountFromMnemonic mocks base method
+func (m *MockAccountManager) CreateAccountFromMnemonic(mnemonic, password string) (string, string, error) {
+	ret := m.ctrl.Call(m, "CreateAccountFromMnemonic", mnemonic, password)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAccountFromMnemonic indicates an expected call of CreateAccountFromMnemonic
+func (mr *MockAccountManagerMockRecorder) CreateAccountFromMnemonic(mnemonic, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountFromMnemonic", reflect.TypeOf((*MockAccountManager)(nil).CreateAccountFromMnemonic), mnemonic, password)
+}
+
+// DeriveChildAccounts mocks base method
+func (m *MockAccountManager) DeriveChildAccounts(path string, count int, password string) ([]DerivedAccount, error) {
+	ret := m.ctrl.Call(m, "DeriveChildAccounts", path, count, password)
+	ret0, _ := ret[0].([]DerivedAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeriveChildAccounts indicates an expected call of DeriveChildAccounts
+func (mr *MockAccountManagerMockRecorder) DeriveChildAccounts(path, count, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeriveChildAccounts", reflect.TypeOf((*MockAccountManager)(nil).DeriveChildAccounts), path, count, password)
+}
+
+// LoadAccountAtPath mocks base method
+func (m *MockAccountManager) LoadAccountAtPath(path, password string) (DerivedAccount, error) {
+	ret := m.ctrl.Call(m, "LoadAccountAtPath", path, password)
+	ret0, _ := ret[0].(DerivedAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAccountAtPath indicates an expected call of LoadAccountAtPath
+func (mr *MockAccountManagerMockRecorder) LoadAccountAtPath(path, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAccountAtPath", reflect.TypeOf((*MockAccountManager)(nil).LoadAccountAtPath), path, password)
+}
+
+// ExportAccount mocks base method
+func (m *MockAccountManager) ExportAccount(address, password, exportPassword string) (string, error) {
+	ret := m.ctrl.Call(m, "ExportAccount", address, password, exportPassword)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportAccount indicates an expected call of ExportAccount
+func (mr *MockAccountManagerMockRecorder) ExportAccount(address, password, exportPassword interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAccount", reflect.TypeOf((*MockAccountManager)(nil).ExportAccount), address, password, exportPassword)
+}
+
+// ImportAccount mocks base method
+func (m *MockAccountManager) ImportAccount(keyJSON, password, newPassword string) (string, error) {
+	ret := m.ctrl.Call(m, "ImportAccount", keyJSON, password, newPassword)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportAccount indicates an expected call of ImportAccount
+func (mr *MockAccountManagerMockRecorder) ImportAccount(keyJSON, password, newPassword interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAccount", reflect.TypeOf((*MockAccountManager)(nil).ImportAccount), keyJSON, password, newPassword)
+}
+
+// ExportKeystoreBundle mocks base method
+func (m *MockAccountManager) ExportKeystoreBundle(exportPassword string) (string, error) {
+	ret := m.ctrl.Call(m, "ExportKeystoreBundle", exportPassword)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportKeystoreBundle indicates an expected call of ExportKeystoreBundle
+func (mr *MockAccountManagerMockRecorder) ExportKeystoreBundle(exportPassword interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportKeystoreBundle", reflect.TypeOf((*MockAccountManager)(nil).ExportKeystoreBundle), exportPassword)
+}
+
+// ImportKeystoreBundle mocks base method
+func (m *MockAccountManager) ImportKeystoreBundle(bundle, exportPassword string) ([]string, error) {
+	ret := m.ctrl.Call(m, "ImportKeystoreBundle", bundle, exportPassword)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportKeystoreBundle indicates an expected call of ImportKeystoreBundle
+func (mr *MockAccountManagerMockRecorder) ImportKeystoreBundle(bundle, exportPassword interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportKeystoreBundle", reflect.TypeOf((*MockAccountManager)(nil).ImportKeystoreBundle), bundle, exportPassword)
+}
+
+// AddWatchOnlyAccount mocks base method
+func (m *MockAccountManager) AddWatchOnlyAccount(address, name string) error {
+	ret := m.ctrl.Call(m, "AddWatchOnlyAccount", address, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWatchOnlyAccount indicates an expected call of AddWatchOnlyAccount
+func (mr *MockAccountManagerMockRecorder) AddWatchOnlyAccount(address, name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWatchOnlyAccount", reflect.TypeOf((*MockAccountManager)(nil).AddWatchOnlyAccount), address, name)
+}
+
+// RemoveWatchOnlyAccount mocks base method
+func (m *MockAccountManager) RemoveWatchOnlyAccount(address string) error {
+	ret := m.ctrl.Call(m, "RemoveWatchOnlyAccount", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWatchOnlyAccount indicates an expected call of RemoveWatchOnlyAccount
+func (mr *MockAccountManagerMockRecorder) RemoveWatchOnlyAccount(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWatchOnlyAccount", reflect.TypeOf((*MockAccountManager)(nil).RemoveWatchOnlyAccount), address)
+}
+
+// WatchOnlyAccounts mocks base method
+func (m *MockAccountManager) WatchOnlyAccounts() ([]WatchOnlyAccount, error) {
+	ret := m.ctrl.Call(m, "WatchOnlyAccounts")
+	ret0, _ := ret[0].([]WatchOnlyAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchOnlyAccounts indicates an expected call of WatchOnlyAccounts
+func (mr *MockAccountManagerMockRecorder) WatchOnlyAccounts() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchOnlyAccounts", reflect.TypeOf((*MockAccountManager)(nil).WatchOnlyAccounts))
+}
+
+// IsWatchOnlyAccount mocks base method
+func (m *MockAccountManager) IsWatchOnlyAccount(address string) bool {
+	ret := m.ctrl.Call(m, "IsWatchOnlyAccount", address)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsWatchOnlyAccount indicates an expected call of IsWatchOnlyAccount
+func (mr *MockAccountManagerMockRecorder) IsWatchOnlyAccount(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsWatchOnlyAccount", reflect.TypeOf((*MockAccountManager)(nil).IsWatchOnlyAccount), address)
+}
+
+// AddSelectedAccount mocks base method
+func (m *MockAccountManager) AddSelectedAccount(address, password string) error {
+	ret := m.ctrl.Call(m, "AddSelectedAccount", address, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSelectedAccount indicates an expected call of AddSelectedAccount
+func (mr *MockAccountManagerMockRecorder) AddSelectedAccount(address, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSelectedAccount", reflect.TypeOf((*MockAccountManager)(nil).AddSelectedAccount), address, password)
+}
+
+// RemoveSelectedAccount mocks base method
+func (m *MockAccountManager) RemoveSelectedAccount(address string) error {
+	ret := m.ctrl.Call(m, "RemoveSelectedAccount", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSelectedAccount indicates an expected call of RemoveSelectedAccount
+func (mr *MockAccountManagerMockRecorder) RemoveSelectedAccount(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSelectedAccount", reflect.TypeOf((*MockAccountManager)(nil).RemoveSelectedAccount), address)
+}
+
+// SelectedAccountByAddress mocks base method
+func (m *MockAccountManager) SelectedAccountByAddress(address string) (*SelectedExtKey, error) {
+	ret := m.ctrl.Call(m, "SelectedAccountByAddress", address)
+	ret0, _ := ret[0].(*SelectedExtKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectedAccountByAddress indicates an expected call of SelectedAccountByAddress
+func (mr *MockAccountManagerMockRecorder) SelectedAccountByAddress(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectedAccountByAddress", reflect.TypeOf((*MockAccountManager)(nil).SelectedAccountByAddress), address)
+}
+
+// BindAccount mocks base method
+func (m *MockAccountManager) BindAccount(origin, address string) error {
+	ret := m.ctrl.Call(m, "BindAccount", origin, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindAccount indicates an expected call of BindAccount
+func (mr *MockAccountManagerMockRecorder) BindAccount(origin, address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindAccount", reflect.TypeOf((*MockAccountManager)(nil).BindAccount), origin, address)
+}
+
+// UnbindAccount mocks base method
+func (m *MockAccountManager) UnbindAccount(origin string) {
+	m.ctrl.Call(m, "UnbindAccount", origin)
+}
+
+// UnbindAccount indicates an expected call of UnbindAccount
+func (mr *MockAccountManagerMockRecorder) UnbindAccount(origin interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbindAccount", reflect.TypeOf((*MockAccountManager)(nil).UnbindAccount), origin)
+}
+
+// BoundAccount mocks base method
+func (m *MockAccountManager) BoundAccount(origin string) (common.Address, bool) {
+	ret := m.ctrl.Call(m, "BoundAccount", origin)
+	ret0, _ := ret[0].(common.Address)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// BoundAccount indicates an expected call of BoundAccount
+func (mr *MockAccountManagerMockRecorder) BoundAccount(origin interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BoundAccount", reflect.TypeOf((*MockAccountManager)(nil).BoundAccount), origin)
+}
+
+// InjectWhisperIdentity mocks base method
+func (m *MockAccountManager) InjectWhisperIdentity(address string) error {
+	ret := m.ctrl.Call(m, "InjectWhisperIdentity", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InjectWhisperIdentity indicates an expected call of InjectWhisperIdentity
+func (mr *MockAccountManagerMockRecorder) InjectWhisperIdentity(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InjectWhisperIdentity", reflect.TypeOf((*MockAccountManager)(nil).InjectWhisperIdentity), address)
+}
+
+// RemoveWhisperIdentity mocks base method
+func (m *MockAccountManager) RemoveWhisperIdentity(address string) error {
+	ret := m.ctrl.Call(m, "RemoveWhisperIdentity", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWhisperIdentity indicates an expected call of RemoveWhisperIdentity
+func (mr *MockAccountManagerMockRecorder) RemoveWhisperIdentity(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWhisperIdentity", reflect.TypeOf((*MockAccountManager)(nil).RemoveWhisperIdentity), address)
+}
+
+// WhisperIdentities mocks base method
+func (m *MockAccountManager) WhisperIdentities() ([]WhisperIdentity, error) {
+	ret := m.ctrl.Call(m, "WhisperIdentities")
+	ret0, _ := ret[0].([]WhisperIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WhisperIdentities indicates an expected call of WhisperIdentities
+func (mr *MockAccountManagerMockRecorder) WhisperIdentities() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WhisperIdentities", reflect.TypeOf((*MockAccountManager)(nil).WhisperIdentities))
+}
+
+// VerifyMnemonic mocks base method
+func (m *MockAccountManager) VerifyMnemonic(address, mnemonic, password string) (bool, error) {
+	ret := m.ctrl.Call(m, "VerifyMnemonic", address, mnemonic, password)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMnemonic indicates an expected call of VerifyMnemonic
+func (mr *MockAccountManagerMockRecorder) VerifyMnemonic(address, mnemonic, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMnemonic", reflect.TypeOf((*MockAccountManager)(nil).VerifyMnemonic), address, mnemonic, password)
+}
+
+// ChangePassword mocks base method
+func (m *MockAccountManager) ChangePassword(address, oldPassword, newPassword string) error {
+	ret := m.ctrl.Call(m, "ChangePassword", address, oldPassword, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword
+func (mr *MockAccountManagerMockRecorder) ChangePassword(address, oldPassword, newPassword interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAccountManager)(nil).ChangePassword), address, oldPassword, newPassword)
+}
+
+// DeriveContactKey mocks base method
+func (m *MockAccountManager) DeriveContactKey(contactPubKey string) (ContactKey, error) {
+	ret := m.ctrl.Call(m, "DeriveContactKey", contactPubKey)
+	ret0, _ := ret[0].(ContactKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeriveContactKey indicates an expected call of DeriveContactKey
+func (mr *MockAccountManagerMockRecorder) DeriveContactKey(contactPubKey interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeriveContactKey", reflect.TypeOf((*MockAccountManager)(nil).DeriveContactKey), contactPubKey)
+}
+
+// AccountsMetadata mocks base method
+func (m *MockAccountManager) AccountsMetadata() AccountsMetadata {
+	ret := m.ctrl.Call(m, "AccountsMetadata")
+	ret0, _ := ret[0].(AccountsMetadata)
+	return ret0
+}
+
+// AccountsMetadata indicates an expected call of AccountsMetadata
+func (mr *MockAccountManagerMockRecorder) AccountsMetadata() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountsMetadata", reflect.TypeOf((*MockAccountManager)(nil).AccountsMetadata))
+}
+
+// MockAccountsMetadata is a mock of AccountsMetadata interface
+type MockAccountsMetadata struct {
 	ctrl     *gomock.Controller
-	recorder *MockTxQueueMockRecorder
+	recorder *MockAccountsMetadataMockRecorder
 }
 
-// MockTxQueueMockRecorder is the mock recorder for MockTxQueue
-type MockTxQueueMockRecorder struct {
-	mock *MockTxQueue
+// MockAccountsMetadataMockRecorder is the mock recorder for MockAccountsMetadata
+type MockAccountsMetadataMockRecorder struct {
+	mock *MockAccountsMetadata
 }
 
-// NewMockTxQueue creates a new mock instance
-func NewMockTxQueue(ctrl *gomock.Controller) *MockTxQueue {
-	mock := &MockTxQueue{ctrl: ctrl}
-	mock.recorder = &MockTxQueueMockRecorder{mock}
+// NewMockAccountsMetadata creates a new mock instance
+func NewMockAccountsMetadata(ctrl *gomock.Controller) *MockAccountsMetadata {
+	mock := &MockAccountsMetadata{ctrl: ctrl}
+	mock.recorder = &MockAccountsMetadataMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockTxQueue) EXPECT() *MockTxQueueMockRecorder {
+func (m *MockAccountsMetadata) EXPECT() *MockAccountsMetadataMockRecorder {
 	return m.recorder
 }
 
-// Remove mocks base method
-func (m *MockTxQueue) Remove(id QueuedTxID) {
-	m.ctrl.Call(m, "Remove", id)
+// Set mocks base method
+func (m *MockAccountsMetadata) Set(metadata AccountMetadata) error {
+	ret := m.ctrl.Call(m, "Set", metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// Remove indicates an expected call of Remove
-func (mr *MockTxQueueMockRecorder) Remove(id interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTxQueue)(nil).Remove), id)
+// Set indicates an expected call of Set
+func (mr *MockAccountsMetadataMockRecorder) Set(metadata interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockAccountsMetadata)(nil).Set), metadata)
 }
 
-// Reset mocks base method
-func (m *MockTxQueue) Reset() {
-	m.ctrl.Call(m, "Reset")
+// Get mocks base method
+func (m *MockAccountsMetadata) Get(address string) (AccountMetadata, error) {
+	ret := m.ctrl.Call(m, "Get", address)
+	ret0, _ := ret[0].(AccountMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Reset indicates an expected call of Reset
-func (mr *MockTxQueueMockRecorder) Reset() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTxQueue)(nil).Reset))
+// Get indicates an expected call of Get
+func (mr *MockAccountsMetadataMockRecorder) Get(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAccountsMetadata)(nil).Get), address)
 }
 
-// Count mocks base method
+// List mocks base method
+func (m *MockAccountsMetadata) List() ([]AccountMetadata, error) {
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]AccountMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockAccountsMetadataMockRecorder) List() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccountsMetadata)(nil).List))
+}
+
+// Delete mocks base method
+func (m *MockAccountsMetadata) Delete(address string) error {
+	ret := m.ctrl.Call(m, "Delete", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockAccountsMetadataMockRecorder) Delete(address interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccountsMetadata)(nil).Delete), address)
+}
+
+// MockTxQueue is a mock of TxQueue interface
+type MockTxQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxQueueMockRecorder
+}
+
+// MockTxQueueMockRecorder is the mock recorder for MockTxQueue
+type MockTxQueueMockRecorder struct {
+	mock *MockTxQueue
+}
+
+// NewMockTxQueue creates a new mock instance
+func NewMockTxQueue(ctrl *gomock.Controller) *MockTxQueue {
+	mock := &MockTxQueue{ctrl: ctrl}
+	mock.recorder = &MockTxQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTxQueue) EXPECT() *MockTxQueueMockRecorder {
+	return m.recorder
+}
+
+// Remove mocks base method
+func (m *MockTxQueue) Remove(id QueuedTxID) {
+	m.ctrl.Call(m, "Remove", id)
+}
+
+// Remove indicates an expected call of Remove
+func (mr *MockTxQueueMockRecorder) Remove(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTxQueue)(nil).Remove), id)
+}
+
+// Reset mocks base method
+func (m *MockTxQueue) Reset() {
+	m.ctrl.Call(m, "Reset")
+}
+
+// Reset indicates an expected call of Reset
+func (mr *MockTxQueueMockRecorder) Reset() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockTxQueue)(nil).Reset))
+}
+
+// Count mocks base method
 func (m *MockTxQueue) Count() int {
 	ret := m.ctrl.Call(m, "Count")
 	ret0, _ := ret[0].(int)
 	return ret0
 }
 
-// Count indicates an expected call of Count
-func (mr *MockTxQueueMockRecorder) Count() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockTxQueue)(nil).Count))
+// Count indicates an expected call of Count
+func (mr *MockTxQueueMockRecorder) Count() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockTxQueue)(nil).Count))
+}
+
+// Has mocks base method
+func (m *MockTxQueue) Has(id QueuedTxID) bool {
+	ret := m.ctrl.Call(m, "Has", id)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Has indicates an expected call of Has
+func (mr *MockTxQueueMockRecorder) Has(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockTxQueue)(nil).Has), id)
+}
+
+// MockTxQueueManager is a mock of TxQueueManager interface
+type MockTxQueueManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxQueueManagerMockRecorder
+}
+
+// MockTxQueueManagerMockRecorder is the mock recorder for MockTxQueueManager
+type MockTxQueueManagerMockRecorder struct {
+	mock *MockTxQueueManager
+}
+
+// NewMockTxQueueManager creates a new mock instance
+func NewMockTxQueueManager(ctrl *gomock.Controller) *MockTxQueueManager {
+	mock := &MockTxQueueManager{ctrl: ctrl}
+	mock.recorder = &MockTxQueueManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTxQueueManager) EXPECT() *MockTxQueueManagerMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method
+func (m *MockTxQueueManager) Start() {
+	m.ctrl.Call(m, "Start")
+}
+
+// Start indicates an expected call of Start
+func (mr *MockTxQueueManagerMockRecorder) Start() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockTxQueueManager)(nil).Start))
+}
+
+// Stop mocks base method
+func (m *MockTxQueueManager) Stop() {
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockTxQueueManagerMockRecorder) Stop() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTxQueueManager)(nil).Stop))
+}
+
+// TransactionQueue mocks base method
+func (m *MockTxQueueManager) TransactionQueue() TxQueue {
+	ret := m.ctrl.Call(m, "TransactionQueue")
+	ret0, _ := ret[0].(TxQueue)
+	return ret0
+}
+
+// TransactionQueue indicates an expected call of TransactionQueue
+func (mr *MockTxQueueManagerMockRecorder) TransactionQueue() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionQueue", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionQueue))
+}
+
+// CreateTransaction mocks base method
+func (m *MockTxQueueManager) CreateTransaction(ctx context.Context, args SendTxArgs) *QueuedTx {
+	ret := m.ctrl.Call(m, "CreateTransaction", ctx, args)
+	ret0, _ := ret[0].(*QueuedTx)
+	return ret0
+}
+
+// CreateTransaction indicates an expected call of CreateTransaction
+func (mr *MockTxQueueManagerMockRecorder) CreateTransaction(ctx, args interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).CreateTransaction), ctx, args)
+}
+
+// QueueTransaction mocks base method
+func (m *MockTxQueueManager) QueueTransaction(tx *QueuedTx) error {
+	ret := m.ctrl.Call(m, "QueueTransaction", tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueTransaction indicates an expected call of QueueTransaction
+func (mr *MockTxQueueManagerMockRecorder) QueueTransaction(tx interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).QueueTransaction), tx)
+}
+
+// WaitForTransaction mocks base method
+func (m *MockTxQueueManager) WaitForTransaction(tx *QueuedTx) error {
+	ret := m.ctrl.Call(m, "WaitForTransaction", tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForTransaction indicates an expected call of WaitForTransaction
+func (mr *MockTxQueueManagerMockRecorder) WaitForTransaction(tx interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).WaitForTransaction), tx)
+}
+
+// NotifyOnQueuedTxReturn mocks base method
+func (m *MockTxQueueManager) NotifyOnQueuedTxReturn(queuedTx *QueuedTx, err error) {
+	m.ctrl.Call(m, "NotifyOnQueuedTxReturn", queuedTx, err)
+}
+
+// NotifyOnQueuedTxReturn indicates an expected call of NotifyOnQueuedTxReturn
+func (mr *MockTxQueueManagerMockRecorder) NotifyOnQueuedTxReturn(queuedTx, err interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyOnQueuedTxReturn", reflect.TypeOf((*MockTxQueueManager)(nil).NotifyOnQueuedTxReturn), queuedTx, err)
+}
+
+// TransactionQueueHandler mocks base method
+func (m *MockTxQueueManager) TransactionQueueHandler() func(*QueuedTx) {
+	ret := m.ctrl.Call(m, "TransactionQueueHandler")
+	ret0, _ := ret[0].(func(*QueuedTx))
+	return ret0
+}
+
+// TransactionQueueHandler indicates an expected call of TransactionQueueHandler
+func (mr *MockTxQueueManagerMockRecorder) TransactionQueueHandler() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionQueueHandler", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionQueueHandler))
+}
+
+// SetTransactionQueueHandler mocks base method
+func (m *MockTxQueueManager) SetTransactionQueueHandler(fn EnqueuedTxHandler) {
+	m.ctrl.Call(m, "SetTransactionQueueHandler", fn)
+}
+
+// SetTransactionQueueHandler indicates an expected call of SetTransactionQueueHandler
+func (mr *MockTxQueueManagerMockRecorder) SetTransactionQueueHandler(fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransactionQueueHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SetTransactionQueueHandler), fn)
+}
+
+// SetTransactionReturnHandler mocks base method
+func (m *MockTxQueueManager) SetTransactionReturnHandler(fn EnqueuedTxReturnHandler) {
+	m.ctrl.Call(m, "SetTransactionReturnHandler", fn)
+}
+
+// SetTransactionReturnHandler indicates an expected call of SetTransactionReturnHandler
+func (mr *MockTxQueueManagerMockRecorder) SetTransactionReturnHandler(fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransactionReturnHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SetTransactionReturnHandler), fn)
+}
+
+// SendTransactionRPCHandler mocks base method
+func (m *MockTxQueueManager) SendTransactionRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
+	varargs := []interface{}{ctx}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendTransactionRPCHandler", varargs...)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendTransactionRPCHandler indicates an expected call of SendTransactionRPCHandler
+func (mr *MockTxQueueManagerMockRecorder) SendTransactionRPCHandler(ctx interface{}, args ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransactionRPCHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SendTransactionRPCHandler), varargs...)
+}
+
+// TransactionReturnHandler mocks base method
+func (m *MockTxQueueManager) TransactionReturnHandler() func(*QueuedTx, error) {
+	ret := m.ctrl.Call(m, "TransactionReturnHandler")
+	ret0, _ := ret[0].(func(*QueuedTx, error))
+	return ret0
+}
+
+// TransactionReturnHandler indicates an expected call of TransactionReturnHandler
+func (mr *MockTxQueueManagerMockRecorder) TransactionReturnHandler() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionReturnHandler", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionReturnHandler))
+}
+
+// CompleteTransaction mocks base method
+func (m *MockTxQueueManager) CompleteTransaction(id QueuedTxID, password string) (common.Hash, error) {
+	ret := m.ctrl.Call(m, "CompleteTransaction", id, password)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteTransaction indicates an expected call of CompleteTransaction
+func (mr *MockTxQueueManagerMockRecorder) CompleteTransaction(id, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).CompleteTransaction), id, password)
+}
+
+// CompleteTransactions mocks base method
+func (m *MockTxQueueManager) CompleteTransactions(ids []QueuedTxID, password string) map[QueuedTxID]RawCompleteTransactionResult {
+	ret := m.ctrl.Call(m, "CompleteTransactions", ids, password)
+	ret0, _ := ret[0].(map[QueuedTxID]RawCompleteTransactionResult)
+	return ret0
+}
+
+// CompleteTransactions indicates an expected call of CompleteTransactions
+func (mr *MockTxQueueManagerMockRecorder) CompleteTransactions(ids, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransactions", reflect.TypeOf((*MockTxQueueManager)(nil).CompleteTransactions), ids, password)
+}
+
+// DiscardTransaction mocks base method
+func (m *MockTxQueueManager) DiscardTransaction(id QueuedTxID) error {
+	ret := m.ctrl.Call(m, "DiscardTransaction", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DiscardTransaction indicates an expected call of DiscardTransaction
+func (mr *MockTxQueueManagerMockRecorder) DiscardTransaction(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).DiscardTransaction), id)
+}
+
+// DiscardTransactions mocks base method
+func (m *MockTxQueueManager) DiscardTransactions(ids []QueuedTxID) map[QueuedTxID]RawDiscardTransactionResult {
+	ret := m.ctrl.Call(m, "DiscardTransactions", ids)
+	ret0, _ := ret[0].(map[QueuedTxID]RawDiscardTransactionResult)
+	return ret0
+}
+
+// DiscardTransactions indicates an expected call of DiscardTransactions
+func (mr *MockTxQueueManagerMockRecorder) DiscardTransactions(ids interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardTransactions", reflect.TypeOf((*MockTxQueueManager)(nil).DiscardTransactions), ids)
+}
+
+// TransactionsByOrigin mocks base method
+func (m *MockTxQueueManager) TransactionsByOrigin(origin string) []*QueuedTx {
+	ret := m.ctrl.Call(m, "TransactionsByOrigin", origin)
+	ret0, _ := ret[0].([]*QueuedTx)
+	return ret0
+}
+
+// TransactionsByOrigin indicates an expected call of TransactionsByOrigin
+func (mr *MockTxQueueManagerMockRecorder) TransactionsByOrigin(origin interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionsByOrigin", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionsByOrigin), origin)
+}
+
+// DiscardTransactionsByOrigin mocks base method
+func (m *MockTxQueueManager) DiscardTransactionsByOrigin(origin string) map[QueuedTxID]RawDiscardTransactionResult {
+	ret := m.ctrl.Call(m, "DiscardTransactionsByOrigin", origin)
+	ret0, _ := ret[0].(map[QueuedTxID]RawDiscardTransactionResult)
+	return ret0
+}
+
+// DiscardTransactionsByOrigin indicates an expected call of DiscardTransactionsByOrigin
+func (mr *MockTxQueueManagerMockRecorder) DiscardTransactionsByOrigin(origin interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardTransactionsByOrigin", reflect.TypeOf((*MockTxQueueManager)(nil).DiscardTransactionsByOrigin), origin)
+}
+
+// SetSigningPolicy mocks base method
+func (m *MockTxQueueManager) SetSigningPolicy(config *params.SigningConfig) error {
+	ret := m.ctrl.Call(m, "SetSigningPolicy", config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSigningPolicy indicates an expected call of SetSigningPolicy
+func (mr *MockTxQueueManagerMockRecorder) SetSigningPolicy(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSigningPolicy", reflect.TypeOf((*MockTxQueueManager)(nil).SetSigningPolicy), config)
+}
+
+// SignTypedDataRPCHandler mocks base method
+func (m *MockTxQueueManager) SignTypedDataRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
+	varargs := []interface{}{ctx}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SignTypedDataRPCHandler", varargs...)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignTypedDataRPCHandler indicates an expected call of SignTypedDataRPCHandler
+func (mr *MockTxQueueManagerMockRecorder) SignTypedDataRPCHandler(ctx interface{}, args ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{ctx}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTypedDataRPCHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SignTypedDataRPCHandler), varargs...)
+}
+
+// SignRPCHandler mocks base method
+func (m *MockTxQueueManager) SignRPCHandler(method string) rpc.Handler {
+	ret := m.ctrl.Call(m, "SignRPCHandler", method)
+	ret0, _ := ret[0].(rpc.Handler)
+	return ret0
+}
+
+// SignRPCHandler indicates an expected call of SignRPCHandler
+func (mr *MockTxQueueManagerMockRecorder) SignRPCHandler(method interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignRPCHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SignRPCHandler), method)
+}
+
+// ApproveSignRequest mocks base method
+func (m *MockTxQueueManager) ApproveSignRequest(id SignRequestID, password string) (hexutil.Bytes, error) {
+	ret := m.ctrl.Call(m, "ApproveSignRequest", id, password)
+	ret0, _ := ret[0].(hexutil.Bytes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveSignRequest indicates an expected call of ApproveSignRequest
+func (mr *MockTxQueueManagerMockRecorder) ApproveSignRequest(id, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveSignRequest", reflect.TypeOf((*MockTxQueueManager)(nil).ApproveSignRequest), id, password)
+}
+
+// DiscardSignRequest mocks base method
+func (m *MockTxQueueManager) DiscardSignRequest(id SignRequestID) error {
+	ret := m.ctrl.Call(m, "DiscardSignRequest", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DiscardSignRequest indicates an expected call of DiscardSignRequest
+func (mr *MockTxQueueManagerMockRecorder) DiscardSignRequest(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardSignRequest", reflect.TypeOf((*MockTxQueueManager)(nil).DiscardSignRequest), id)
+}
+
+// SetHardwareWallet mocks base method
+func (m *MockTxQueueManager) SetHardwareWallet(wallet HardwareWallet) {
+	m.ctrl.Call(m, "SetHardwareWallet", wallet)
+}
+
+// SetHardwareWallet indicates an expected call of SetHardwareWallet
+func (mr *MockTxQueueManagerMockRecorder) SetHardwareWallet(wallet interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHardwareWallet", reflect.TypeOf((*MockTxQueueManager)(nil).SetHardwareWallet), wallet)
+}
+
+// RestoreTransactions mocks base method
+func (m *MockTxQueueManager) RestoreTransactions() error {
+	ret := m.ctrl.Call(m, "RestoreTransactions")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTransactions indicates an expected call of RestoreTransactions
+func (mr *MockTxQueueManagerMockRecorder) RestoreTransactions() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTransactions", reflect.TypeOf((*MockTxQueueManager)(nil).RestoreTransactions))
+}
+
+// PendingTransactions mocks base method
+func (m *MockTxQueueManager) PendingTransactions() []PendingTransaction {
+	ret := m.ctrl.Call(m, "PendingTransactions")
+	ret0, _ := ret[0].([]PendingTransaction)
+	return ret0
+}
+
+// PendingTransactions indicates an expected call of PendingTransactions
+func (mr *MockTxQueueManagerMockRecorder) PendingTransactions() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingTransactions", reflect.TypeOf((*MockTxQueueManager)(nil).PendingTransactions))
+}
+
+// EstimateTransaction mocks base method
+func (m *MockTxQueueManager) EstimateTransaction(args SendTxArgs) (TxEstimate, error) {
+	ret := m.ctrl.Call(m, "EstimateTransaction", args)
+	ret0, _ := ret[0].(TxEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateTransaction indicates an expected call of EstimateTransaction
+func (mr *MockTxQueueManagerMockRecorder) EstimateTransaction(args interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).EstimateTransaction), args)
+}
+
+// SpeedUpTransaction mocks base method
+func (m *MockTxQueueManager) SpeedUpTransaction(hash common.Hash, gasPrice *big.Int, password string) (common.Hash, error) {
+	ret := m.ctrl.Call(m, "SpeedUpTransaction", hash, gasPrice, password)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SpeedUpTransaction indicates an expected call of SpeedUpTransaction
+func (mr *MockTxQueueManagerMockRecorder) SpeedUpTransaction(hash, gasPrice, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SpeedUpTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).SpeedUpTransaction), hash, gasPrice, password)
+}
+
+// CancelTransaction mocks base method
+func (m *MockTxQueueManager) CancelTransaction(hash common.Hash, password string) (common.Hash, error) {
+	ret := m.ctrl.Call(m, "CancelTransaction", hash, password)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTransaction indicates an expected call of CancelTransaction
+func (mr *MockTxQueueManagerMockRecorder) CancelTransaction(hash, password interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).CancelTransaction), hash, password)
+}
+
+// SetTransactionSentHandler mocks base method
+func (m *MockTxQueueManager) SetTransactionSentHandler(fn SentTxHandler) {
+	m.ctrl.Call(m, "SetTransactionSentHandler", fn)
+}
+
+// SetTransactionSentHandler indicates an expected call of SetTransactionSentHandler
+func (mr *MockTxQueueManagerMockRecorder) SetTransactionSentHandler(fn interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransactionSentHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SetTransactionSentHandler), fn)
+}
+
+// SetQueueConfig mocks base method
+func (m *MockTxQueueManager) SetQueueConfig(config *params.TxQueueConfig) {
+	m.ctrl.Call(m, "SetQueueConfig", config)
+}
+
+// SetQueueConfig indicates an expected call of SetQueueConfig
+func (mr *MockTxQueueManagerMockRecorder) SetQueueConfig(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQueueConfig", reflect.TypeOf((*MockTxQueueManager)(nil).SetQueueConfig), config)
+}
+
+// MockJailValue is a mock of JailValue interface
+type MockJailValue struct {
+	ctrl     *gomock.Controller
+	recorder *MockJailValueMockRecorder
+}
+
+// MockJailValueMockRecorder is the mock recorder for MockJailValue
+type MockJailValueMockRecorder struct {
+	mock *MockJailValue
+}
+
+// NewMockJailValue creates a new mock instance
+func NewMockJailValue(ctrl *gomock.Controller) *MockJailValue {
+	mock := &MockJailValue{ctrl: ctrl}
+	mock.recorder = &MockJailValueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockJailValue) EXPECT() *MockJailValueMockRecorder {
+	return m.recorder
+}
+
+// String mocks base method
+func (m *MockJailValue) String() string {
+	ret := m.ctrl.Call(m, "String")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// String indicates an expected call of String
+func (mr *MockJailValueMockRecorder) String() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockJailValue)(nil).String))
+}
+
+// Export mocks base method
+func (m *MockJailValue) Export() (interface{}, error) {
+	ret := m.ctrl.Call(m, "Export")
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export
+func (mr *MockJailValueMockRecorder) Export() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockJailValue)(nil).Export))
 }
 
-// Has mocks base method
-func (m *MockTxQueue) Has(id QueuedTxID) bool {
-	ret := m.ctrl.Call(m, "Has", id)
+// IsDefined mocks base method
+func (m *MockJailValue) IsDefined() bool {
+	ret := m.ctrl.Call(m, "IsDefined")
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// Has indicates an expected call of Has
-func (mr *MockTxQueueMockRecorder) Has(id interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockTxQueue)(nil).Has), id)
+// IsDefined indicates an expected call of IsDefined
+func (mr *MockJailValueMockRecorder) IsDefined() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDefined", reflect.TypeOf((*MockJailValue)(nil).IsDefined))
 }
 
-// MockTxQueueManager is a mock of TxQueueManager interface
-type MockTxQueueManager struct {
-	ctrl     *gomock.Controller
-	recorder *MockTxQueueManagerMockRecorder
+// IsUndefined mocks base method
+func (m *MockJailValue) IsUndefined() bool {
+	ret := m.ctrl.Call(m, "IsUndefined")
+	ret0, _ := ret[0].(bool)
+	return ret0
 }
 
-// MockTxQueueManagerMockRecorder is the mock recorder for MockTxQueueManager
-type MockTxQueueManagerMockRecorder struct {
-	mock *MockTxQueueManager
+// IsUndefined indicates an expected call of IsUndefined
+func (mr *MockJailValueMockRecorder) IsUndefined() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUndefined", reflect.TypeOf((*MockJailValue)(nil).IsUndefined))
 }
 
-// NewMockTxQueueManager creates a new mock instance
-func NewMockTxQueueManager(ctrl *gomock.Controller) *MockTxQueueManager {
-	mock := &MockTxQueueManager{ctrl: ctrl}
-	mock.recorder = &MockTxQueueManagerMockRecorder{mock}
-	return mock
+// IsNull mocks base method
+func (m *MockJailValue) IsNull() bool {
+	ret := m.ctrl.Call(m, "IsNull")
+	ret0, _ := ret[0].(bool)
+	return ret0
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockTxQueueManager) EXPECT() *MockTxQueueManagerMockRecorder {
-	return m.recorder
+// IsNull indicates an expected call of IsNull
+func (mr *MockJailValueMockRecorder) IsNull() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNull", reflect.TypeOf((*MockJailValue)(nil).IsNull))
 }
 
-// Start mocks base method
-func (m *MockTxQueueManager) Start() {
-	m.ctrl.Call(m, "Start")
+// IsBoolean mocks base method
+func (m *MockJailValue) IsBoolean() bool {
+	ret := m.ctrl.Call(m, "IsBoolean")
+	ret0, _ := ret[0].(bool)
+	return ret0
 }
 
-// Start indicates an expected call of Start
-func (mr *MockTxQueueManagerMockRecorder) Start() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockTxQueueManager)(nil).Start))
+// IsBoolean indicates an expected call of IsBoolean
+func (mr *MockJailValueMockRecorder) IsBoolean() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBoolean", reflect.TypeOf((*MockJailValue)(nil).IsBoolean))
 }
 
-// Stop mocks base method
-func (m *MockTxQueueManager) Stop() {
-	m.ctrl.Call(m, "Stop")
+// IsNumber mocks base method
+func (m *MockJailValue) IsNumber() bool {
+	ret := m.ctrl.Call(m, "IsNumber")
+	ret0, _ := ret[0].(bool)
+	return ret0
 }
 
-// Stop indicates an expected call of Stop
-func (mr *MockTxQueueManagerMockRecorder) Stop() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTxQueueManager)(nil).Stop))
+// IsNumber indicates an expected call of IsNumber
+func (mr *MockJailValueMockRecorder) IsNumber() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNumber", reflect.TypeOf((*MockJailValue)(nil).IsNumber))
 }
 
-// TransactionQueue mocks base method
-func (m *MockTxQueueManager) TransactionQueue() TxQueue {
-	ret := m.ctrl.Call(m, "TransactionQueue")
-	ret0, _ := ret[0].(TxQueue)
+// IsString mocks base method
+func (m *MockJailValue) IsString() bool {
+	ret := m.ctrl.Call(m, "IsString")
+	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// TransactionQueue indicates an expected call of TransactionQueue
-func (mr *MockTxQueueManagerMockRecorder) TransactionQueue() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionQueue", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionQueue))
+// IsString indicates an expected call of IsString
+func (mr *MockJailValueMockRecorder) IsString() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsString", reflect.TypeOf((*MockJailValue)(nil).IsString))
 }
 
-// CreateTransaction mocks base method
-func (m *MockTxQueueManager) CreateTransaction(ctx context.Context, args SendTxArgs) *QueuedTx {
-	ret := m.ctrl.Call(m, "CreateTransaction", ctx, args)
-	ret0, _ := ret[0].(*QueuedTx)
+// IsObject mocks base method
+func (m *MockJailValue) IsObject() bool {
+	ret := m.ctrl.Call(m, "IsObject")
+	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// CreateTransaction indicates an expected call of CreateTransaction
-func (mr *MockTxQueueManagerMockRecorder) CreateTransaction(ctx, args interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).CreateTransaction), ctx, args)
+// IsObject indicates an expected call of IsObject
+func (mr *MockJailValueMockRecorder) IsObject() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsObject", reflect.TypeOf((*MockJailValue)(nil).IsObject))
 }
 
-// QueueTransaction mocks base method
-func (m *MockTxQueueManager) QueueTransaction(tx *QueuedTx) error {
-	ret := m.ctrl.Call(m, "QueueTransaction", tx)
-	ret0, _ := ret[0].(error)
+// IsFunction mocks base method
+func (m *MockJailValue) IsFunction() bool {
+	ret := m.ctrl.Call(m, "IsFunction")
+	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// QueueTransaction indicates an expected call of QueueTransaction
-func (mr *MockTxQueueManagerMockRecorder) QueueTransaction(tx interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).QueueTransaction), tx)
+// IsFunction indicates an expected call of IsFunction
+func (mr *MockJailValueMockRecorder) IsFunction() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFunction", reflect.TypeOf((*MockJailValue)(nil).IsFunction))
 }
 
-// WaitForTransaction mocks base method
-func (m *MockTxQueueManager) WaitForTransaction(tx *QueuedTx) error {
-	ret := m.ctrl.Call(m, "WaitForTransaction", tx)
-	ret0, _ := ret[0].(error)
+// Class mocks base method
+func (m *MockJailValue) Class() string {
+	ret := m.ctrl.Call(m, "Class")
+	ret0, _ := ret[0].(string)
 	return ret0
 }
 
-// WaitForTransaction indicates an expected call of WaitForTransaction
-func (mr *MockTxQueueManagerMockRecorder) WaitForTransaction(tx interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).WaitForTransaction), tx)
+// Class indicates an expected call of Class
+func (mr *MockJailValueMockRecorder) Class() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Class", reflect.TypeOf((*MockJailValue)(nil).Class))
 }
 
-// NotifyOnQueuedTxReturn mocks base method
-func (m *MockTxQueueManager) NotifyOnQueuedTxReturn(queuedTx *QueuedTx, err error) {
-	m.ctrl.Call(m, "NotifyOnQueuedTxReturn", queuedTx, err)
+// ToBoolean mocks base method
+func (m *MockJailValue) ToBoolean() (bool, error) {
+	ret := m.ctrl.Call(m, "ToBoolean")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// NotifyOnQueuedTxReturn indicates an expected call of NotifyOnQueuedTxReturn
-func (mr *MockTxQueueManagerMockRecorder) NotifyOnQueuedTxReturn(queuedTx, err interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyOnQueuedTxReturn", reflect.TypeOf((*MockTxQueueManager)(nil).NotifyOnQueuedTxReturn), queuedTx, err)
+// ToBoolean indicates an expected call of ToBoolean
+func (mr *MockJailValueMockRecorder) ToBoolean() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToBoolean", reflect.TypeOf((*MockJailValue)(nil).ToBoolean))
 }
 
-// TransactionQueueHandler mocks base method
-func (m *MockTxQueueManager) TransactionQueueHandler() func(*QueuedTx) {
-	ret := m.ctrl.Call(m, "TransactionQueueHandler")
-	ret0, _ := ret[0].(func(*QueuedTx))
-	return ret0
+// ToInteger mocks base method
+func (m *MockJailValue) ToInteger() (int64, error) {
+	ret := m.ctrl.Call(m, "ToInteger")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// TransactionQueueHandler indicates an expected call of TransactionQueueHandler
-func (mr *MockTxQueueManagerMockRecorder) TransactionQueueHandler() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionQueueHandler", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionQueueHandler))
+// ToInteger indicates an expected call of ToInteger
+func (mr *MockJailValueMockRecorder) ToInteger() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToInteger", reflect.TypeOf((*MockJailValue)(nil).ToInteger))
 }
 
-// SetTransactionQueueHandler mocks base method
-func (m *MockTxQueueManager) SetTransactionQueueHandler(fn EnqueuedTxHandler) {
-	m.ctrl.Call(m, "SetTransactionQueueHandler", fn)
+// ToFloat mocks base method
+func (m *MockJailValue) ToFloat() (float64, error) {
+	ret := m.ctrl.Call(m, "ToFloat")
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SetTransactionQueueHandler indicates an expected call of SetTransactionQueueHandler
-func (mr *MockTxQueueManagerMockRecorder) SetTransactionQueueHandler(fn interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransactionQueueHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SetTransactionQueueHandler), fn)
+// ToFloat indicates an expected call of ToFloat
+func (mr *MockJailValueMockRecorder) ToFloat() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToFloat", reflect.TypeOf((*MockJailValue)(nil).ToFloat))
 }
 
-// SetTransactionReturnHandler mocks base method
-func (m *MockTxQueueManager) SetTransactionReturnHandler(fn EnqueuedTxReturnHandler) {
-	m.ctrl.Call(m, "SetTransactionReturnHandler", fn)
+// ToString mocks base method
+func (m *MockJailValue) ToString() (string, error) {
+	ret := m.ctrl.Call(m, "ToString")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SetTransactionReturnHandler indicates an expected call of SetTransactionReturnHandler
-func (mr *MockTxQueueManagerMockRecorder) SetTransactionReturnHandler(fn interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransactionReturnHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SetTransactionReturnHandler), fn)
+// ToString indicates an expected call of ToString
+func (mr *MockJailValueMockRecorder) ToString() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToString", reflect.TypeOf((*MockJailValue)(nil).ToString))
 }
 
-// SendTransactionRPCHandler mocks base method
-func (m *MockTxQueueManager) SendTransactionRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
-	varargs := []interface{}{ctx}
-	for _, a := range args {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "SendTransactionRPCHandler", varargs...)
-	ret0, _ := ret[0].(interface{})
+// Get mocks base method
+func (m *MockJailValue) Get(name string) (JailValue, error) {
+	ret := m.ctrl.Call(m, "Get", name)
+	ret0, _ := ret[0].(JailValue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SendTransactionRPCHandler indicates an expected call of SendTransactionRPCHandler
-func (mr *MockTxQueueManagerMockRecorder) SendTransactionRPCHandler(ctx interface{}, args ...interface{}) *gomock.Call {
-	varargs := append([]interface{}{ctx}, args...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransactionRPCHandler", reflect.TypeOf((*MockTxQueueManager)(nil).SendTransactionRPCHandler), varargs...)
+// Get indicates an expected call of Get
+func (mr *MockJailValueMockRecorder) Get(name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockJailValue)(nil).Get), name)
 }
 
-// TransactionReturnHandler mocks base method
-func (m *MockTxQueueManager) TransactionReturnHandler() func(*QueuedTx, error) {
-	ret := m.ctrl.Call(m, "TransactionReturnHandler")
-	ret0, _ := ret[0].(func(*QueuedTx, error))
+// Set mocks base method
+func (m *MockJailValue) Set(name string, value interface{}) error {
+	ret := m.ctrl.Call(m, "Set", name, value)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// TransactionReturnHandler indicates an expected call of TransactionReturnHandler
-func (mr *MockTxQueueManagerMockRecorder) TransactionReturnHandler() *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionReturnHandler", reflect.TypeOf((*MockTxQueueManager)(nil).TransactionReturnHandler))
+// Set indicates an expected call of Set
+func (mr *MockJailValueMockRecorder) Set(name, value interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockJailValue)(nil).Set), name, value)
 }
 
-// CompleteTransaction mocks base method
-func (m *MockTxQueueManager) CompleteTransaction(id QueuedTxID, password string) (common.Hash, error) {
-	ret := m.ctrl.Call(m, "CompleteTransaction", id, password)
-	ret0, _ := ret[0].(common.Hash)
+// Keys mocks base method
+func (m *MockJailValue) Keys() []string {
+	ret := m.ctrl.Call(m, "Keys")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys
+func (mr *MockJailValueMockRecorder) Keys() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockJailValue)(nil).Keys))
+}
+
+// Call mocks base method
+func (m *MockJailValue) Call(this interface{}, args ...interface{}) (JailValue, error) {
+	varargs := []interface{}{this}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Call", varargs...)
+	ret0, _ := ret[0].(JailValue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CompleteTransaction indicates an expected call of CompleteTransaction
-func (mr *MockTxQueueManagerMockRecorder) CompleteTransaction(id, password interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).CompleteTransaction), id, password)
+// Call indicates an expected call of Call
+func (mr *MockJailValueMockRecorder) Call(this interface{}, args ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{this}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockJailValue)(nil).Call), varargs...)
 }
 
-// CompleteTransactions mocks base method
-func (m *MockTxQueueManager) CompleteTransactions(ids []QueuedTxID, password string) map[QueuedTxID]RawCompleteTransactionResult {
-	ret := m.ctrl.Call(m, "CompleteTransactions", ids, password)
-	ret0, _ := ret[0].(map[QueuedTxID]RawCompleteTransactionResult)
-	return ret0
+// MockJailScript is a mock of JailScript interface
+type MockJailScript struct {
+	ctrl     *gomock.Controller
+	recorder *MockJailScriptMockRecorder
 }
 
-// CompleteTransactions indicates an expected call of CompleteTransactions
-func (mr *MockTxQueueManagerMockRecorder) CompleteTransactions(ids, password interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTransactions", reflect.TypeOf((*MockTxQueueManager)(nil).CompleteTransactions), ids, password)
+// MockJailScriptMockRecorder is the mock recorder for MockJailScript
+type MockJailScriptMockRecorder struct {
+	mock *MockJailScript
 }
 
-// DiscardTransaction mocks base method
-func (m *MockTxQueueManager) DiscardTransaction(id QueuedTxID) error {
-	ret := m.ctrl.Call(m, "DiscardTransaction", id)
-	ret0, _ := ret[0].(error)
-	return ret0
+// NewMockJailScript creates a new mock instance
+func NewMockJailScript(ctrl *gomock.Controller) *MockJailScript {
+	mock := &MockJailScript{ctrl: ctrl}
+	mock.recorder = &MockJailScriptMockRecorder{mock}
+	return mock
 }
 
-// DiscardTransaction indicates an expected call of DiscardTransaction
-func (mr *MockTxQueueManagerMockRecorder) DiscardTransaction(id interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardTransaction", reflect.TypeOf((*MockTxQueueManager)(nil).DiscardTransaction), id)
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockJailScript) EXPECT() *MockJailScriptMockRecorder {
+	return m.recorder
 }
 
-// DiscardTransactions mocks base method
-func (m *MockTxQueueManager) DiscardTransactions(ids []QueuedTxID) map[QueuedTxID]RawDiscardTransactionResult {
-	ret := m.ctrl.Call(m, "DiscardTransactions", ids)
-	ret0, _ := ret[0].(map[QueuedTxID]RawDiscardTransactionResult)
+// Filename mocks base method
+func (m *MockJailScript) Filename() string {
+	ret := m.ctrl.Call(m, "Filename")
+	ret0, _ := ret[0].(string)
 	return ret0
 }
 
-// DiscardTransactions indicates an expected call of DiscardTransactions
-func (mr *MockTxQueueManagerMockRecorder) DiscardTransactions(ids interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardTransactions", reflect.TypeOf((*MockTxQueueManager)(nil).DiscardTransactions), ids)
+// Filename indicates an expected call of Filename
+func (mr *MockJailScriptMockRecorder) Filename() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filename", reflect.TypeOf((*MockJailScript)(nil).Filename))
 }
 
 // MockJailCell is a mock of JailCell interface
@@ -702,9 +1714,9 @@ func (mr *MockJailCellMockRecorder) Set(arg0, arg1 interface{}) *gomock.Call {
 }
 
 // Get mocks base method
-func (m *MockJailCell) Get(arg0 string) (otto.Value, error) {
+func (m *MockJailCell) Get(arg0 string) (JailValue, error) {
 	ret := m.ctrl.Call(m, "Get", arg0)
-	ret0, _ := ret[0].(otto.Value)
+	ret0, _ := ret[0].(JailValue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -715,9 +1727,9 @@ func (mr *MockJailCellMockRecorder) Get(arg0 interface{}) *gomock.Call {
 }
 
 // Run mocks base method
-func (m *MockJailCell) Run(arg0 interface{}) (otto.Value, error) {
+func (m *MockJailCell) Run(arg0 interface{}) (JailValue, error) {
 	ret := m.ctrl.Call(m, "Run", arg0)
-	ret0, _ := ret[0].(otto.Value)
+	ret0, _ := ret[0].(JailValue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -728,13 +1740,13 @@ func (mr *MockJailCellMockRecorder) Run(arg0 interface{}) *gomock.Call {
 }
 
 // Call mocks base method
-func (m *MockJailCell) Call(item string, this interface{}, args ...interface{}) (otto.Value, error) {
+func (m *MockJailCell) Call(item string, this interface{}, args ...interface{}) (JailValue, error) {
 	varargs := []interface{}{item, this}
 	for _, a := range args {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Call", varargs...)
-	ret0, _ := ret[0].(otto.Value)
+	ret0, _ := ret[0].(JailValue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -755,6 +1767,19 @@ func (mr *MockJailCellMockRecorder) Stop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockJailCell)(nil).Stop))
 }
 
+// Compile mocks base method
+func (m *MockJailCell) Compile(filename string, src interface{}) (JailScript, error) {
+	ret := m.ctrl.Call(m, "Compile", filename, src)
+	ret0, _ := ret[0].(JailScript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compile indicates an expected call of Compile
+func (mr *MockJailCellMockRecorder) Compile(filename, src interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compile", reflect.TypeOf((*MockJailCell)(nil).Compile), filename, src)
+}
+
 // MockJailManager is a mock of JailManager interface
 type MockJailManager struct {
 	ctrl     *gomock.Controller
@@ -847,3 +1872,144 @@ func (m *MockJailManager) Stop() {
 func (mr *MockJailManagerMockRecorder) Stop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockJailManager)(nil).Stop))
 }
+
+// RemoveCell mocks base method
+func (m *MockJailManager) RemoveCell(chatID string) error {
+	ret := m.ctrl.Call(m, "RemoveCell", chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCell indicates an expected call of RemoveCell
+func (mr *MockJailManagerMockRecorder) RemoveCell(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCell", reflect.TypeOf((*MockJailManager)(nil).RemoveCell), chatID)
+}
+
+// Cells mocks base method
+func (m *MockJailManager) Cells() []JailCellInfo {
+	ret := m.ctrl.Call(m, "Cells")
+	ret0, _ := ret[0].([]JailCellInfo)
+	return ret0
+}
+
+// Cells indicates an expected call of Cells
+func (mr *MockJailManagerMockRecorder) Cells() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cells", reflect.TypeOf((*MockJailManager)(nil).Cells))
+}
+
+// Storage mocks base method
+func (m *MockJailManager) Storage(chatID string) (map[string]string, error) {
+	ret := m.ctrl.Call(m, "Storage", chatID)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Storage indicates an expected call of Storage
+func (mr *MockJailManagerMockRecorder) Storage(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Storage", reflect.TypeOf((*MockJailManager)(nil).Storage), chatID)
+}
+
+// ClearStorage mocks base method
+func (m *MockJailManager) ClearStorage(chatID string) error {
+	ret := m.ctrl.Call(m, "ClearStorage", chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearStorage indicates an expected call of ClearStorage
+func (mr *MockJailManagerMockRecorder) ClearStorage(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearStorage", reflect.TypeOf((*MockJailManager)(nil).ClearStorage), chatID)
+}
+
+// SetFetchWhitelist mocks base method
+func (m *MockJailManager) SetFetchWhitelist(chatID string, origins []string) {
+	m.ctrl.Call(m, "SetFetchWhitelist", chatID, origins)
+}
+
+// SetFetchWhitelist indicates an expected call of SetFetchWhitelist
+func (mr *MockJailManagerMockRecorder) SetFetchWhitelist(chatID, origins interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFetchWhitelist", reflect.TypeOf((*MockJailManager)(nil).SetFetchWhitelist), chatID, origins)
+}
+
+// CellLogs mocks base method
+func (m *MockJailManager) CellLogs(chatID string, n int) []JailLogEntry {
+	ret := m.ctrl.Call(m, "CellLogs", chatID, n)
+	ret0, _ := ret[0].([]JailLogEntry)
+	return ret0
+}
+
+// CellLogs indicates an expected call of CellLogs
+func (mr *MockJailManagerMockRecorder) CellLogs(chatID, n interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CellLogs", reflect.TypeOf((*MockJailManager)(nil).CellLogs), chatID, n)
+}
+
+// CallQueueDepth mocks base method
+func (m *MockJailManager) CallQueueDepth(chatID string) int {
+	ret := m.ctrl.Call(m, "CallQueueDepth", chatID)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// CallQueueDepth indicates an expected call of CallQueueDepth
+func (mr *MockJailManagerMockRecorder) CallQueueDepth(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallQueueDepth", reflect.TypeOf((*MockJailManager)(nil).CallQueueDepth), chatID)
+}
+
+// ReloadBaseJS mocks base method
+func (m *MockJailManager) ReloadBaseJS(js string) error {
+	ret := m.ctrl.Call(m, "ReloadBaseJS", js)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReloadBaseJS indicates an expected call of ReloadBaseJS
+func (mr *MockJailManagerMockRecorder) ReloadBaseJS(js interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadBaseJS", reflect.TypeOf((*MockJailManager)(nil).ReloadBaseJS), js)
+}
+
+// ParseVersion mocks base method
+func (m *MockJailManager) ParseVersion() uint64 {
+	ret := m.ctrl.Call(m, "ParseVersion")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// ParseVersion indicates an expected call of ParseVersion
+func (mr *MockJailManagerMockRecorder) ParseVersion() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseVersion", reflect.TypeOf((*MockJailManager)(nil).ParseVersion))
+}
+
+// CallAsync mocks base method
+func (m *MockJailManager) CallAsync(chatID, this, args string) string {
+	ret := m.ctrl.Call(m, "CallAsync", chatID, this, args)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CallAsync indicates an expected call of CallAsync
+func (mr *MockJailManagerMockRecorder) CallAsync(chatID, this, args interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallAsync", reflect.TypeOf((*MockJailManager)(nil).CallAsync), chatID, this, args)
+}
+
+// SetPermissions mocks base method
+func (m *MockJailManager) SetPermissions(chatID string, permissions *JailPermissions) {
+	m.ctrl.Call(m, "SetPermissions", chatID, permissions)
+}
+
+// SetPermissions indicates an expected call of SetPermissions
+func (mr *MockJailManagerMockRecorder) SetPermissions(chatID, permissions interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPermissions", reflect.TypeOf((*MockJailManager)(nil).SetPermissions), chatID, permissions)
+}
+
+// RegisterModule mocks base method
+func (m *MockJailManager) RegisterModule(name, js string) error {
+	ret := m.ctrl.Call(m, "RegisterModule", name, js)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterModule indicates an expected call of RegisterModule
+func (mr *MockJailManagerMockRecorder) RegisterModule(name, js interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterModule", reflect.TypeOf((*MockJailManager)(nil).RegisterModule), name, js)
+}
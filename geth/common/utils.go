@@ -24,6 +24,10 @@ const (
 	// MessageIDKey is a key for message ID
 	// This ID is required to track from which chat a given send transaction request is coming.
 	MessageIDKey = contextKey("message_id")
+
+	// OriginKey is a key for origin of request, such as chatID of jail cell, it is sent from.
+	// Transactions are tagged with it, so that they could be queried and discarded by origin.
+	OriginKey = contextKey("origin")
 )
 
 type contextKey string // in order to make sure that our context key does not collide with keys from other packages
@@ -120,6 +124,18 @@ func MessageIDFromContext(ctx context.Context) string {
 	return ""
 }
 
+// OriginFromContext returns origin of request from context (if exists)
+func OriginFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if origin, ok := ctx.Value(OriginKey).(string); ok {
+		return origin
+	}
+
+	return ""
+}
+
 // ParseJSONArray parses JSON array into Go array of string
 func ParseJSONArray(items string) ([]string, error) {
 	var parsedItems []string
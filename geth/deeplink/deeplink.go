@@ -0,0 +1,82 @@
+package deeplink
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// MaxLinkLength is a maximum length of a link accepted by Parse.
+const MaxLinkLength = 2048
+
+// Action types
+const (
+	ActionPaymentRequest = "payment-request"
+	ActionOpenChat       = "open-chat"
+	ActionAddContact     = "add-contact"
+	ActionOpenDApp       = "open-dapp"
+)
+
+// URI schemes
+const (
+	SchemeEthereum = "ethereum"
+	SchemeStatus   = "status-im"
+)
+
+// errors
+var (
+	ErrLinkTooLong       = errors.New("link is too long")
+	ErrInvalidCharacters = errors.New("link contains invalid characters")
+	ErrUnknownScheme     = errors.New("unknown link scheme")
+	ErrUnknownPrefix     = errors.New("unknown ethereum link prefix")
+	ErrUnknownAction     = errors.New("unknown status link action")
+	ErrInvalidAddress    = errors.New("invalid target address")
+	ErrInvalidChainID    = errors.New("invalid chain id")
+	ErrInvalidFunction   = errors.New("invalid function name")
+	ErrInvalidParameter  = errors.New("invalid link parameter")
+	ErrDuplicateParam    = errors.New("duplicate link parameter")
+	ErrInvalidNumber     = errors.New("invalid number")
+	ErrInvalidPublicKey  = errors.New("invalid public key")
+	ErrInvalidChatName   = errors.New("invalid public chat name")
+	ErrInvalidDAppURL    = errors.New("invalid dapp url")
+)
+
+// Action is a typed descriptor of what link asks application to do.
+// Exactly one of the fields, corresponding to Type, is set.
+type Action struct {
+	Type           string          `json:"type"`
+	PaymentRequest *PaymentRequest `json:"paymentRequest,omitempty"`
+	Chat           *Chat           `json:"chat,omitempty"`
+	Contact        *Contact        `json:"contact,omitempty"`
+	DApp           *DApp           `json:"dapp,omitempty"`
+}
+
+// Parse validates link and returns action described by it.
+// Supported links are ERC-681 payment requests (optionally with ERC-831 "pay-" prefix),
+// e.g. "ethereum:0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359?value=2.014e18",
+// and Status links, e.g. "status-im://chat/public/status", "status-im://user/0x04...",
+// "status-im://browse/https://dapp.example".
+func Parse(link string) (*Action, error) {
+	if len(link) > MaxLinkLength {
+		return nil, ErrLinkTooLong
+	}
+	for _, r := range link {
+		if r == unicode.ReplacementChar || unicode.IsControl(r) || unicode.IsSpace(r) {
+			return nil, ErrInvalidCharacters
+		}
+	}
+
+	parts := strings.SplitN(link, ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrUnknownScheme
+	}
+
+	switch strings.ToLower(parts[0]) {
+	case SchemeEthereum:
+		return parseEthereumLink(parts[1])
+	case SchemeStatus:
+		return parseStatusLink(parts[1])
+	}
+
+	return nil, ErrUnknownScheme
+}
@@ -0,0 +1,188 @@
+package deeplink
+
+import (
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// erc831PayPrefix is ERC-831 prefix of ERC-681 payment requests
+const erc831PayPrefix = "pay"
+
+var (
+	addressRe  = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	ensNameRe  = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+	functionRe = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+	abiTypeRe  = regexp.MustCompile(`^(address|bool|string|bytes([1-9]|[12][0-9]|3[0-2])?|u?int(8|16|24|32|40|48|56|64|72|80|88|96|104|112|120|128|136|144|152|160|168|176|184|192|200|208|216|224|232|240|248|256)?)$`)
+	numberRe   = regexp.MustCompile(`^([0-9]+)(\.([0-9]+))?([eE]([0-9]+))?$`)
+)
+
+// PaymentRequest is ERC-681 request to send ether, or to call a contract function.
+type PaymentRequest struct {
+	Target     string       `json:"target"`            // address or ENS name
+	ChainID    uint64       `json:"chainId,omitempty"` // 0, if not specified
+	Function   string       `json:"function,omitempty"`
+	Parameters []Parameter  `json:"parameters,omitempty"` // arguments of the function
+	Value      *hexutil.Big `json:"value,omitempty"`
+	Gas        *hexutil.Big `json:"gas,omitempty"`
+	GasPrice   *hexutil.Big `json:"gasPrice,omitempty"`
+}
+
+// Parameter is a typed argument of contract function call.
+type Parameter struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// parseEthereumLink parses ERC-681 link, following "ethereum:" scheme:
+// [pay-]target[@chain_id][/function_name][?parameters]
+func parseEthereumLink(link string) (*Action, error) {
+	// ERC-831 prefix is followed by dash, while ENS names may contain dashes too,
+	// so only prefixes followed by address are distinguished from names
+	if strings.HasPrefix(link, erc831PayPrefix+"-") {
+		link = strings.TrimPrefix(link, erc831PayPrefix+"-")
+	} else if i := strings.Index(link, "-"); i > 0 && strings.HasPrefix(link[i+1:], "0x") {
+		return nil, ErrUnknownPrefix
+	}
+
+	var query string
+	if i := strings.Index(link, "?"); i >= 0 {
+		link, query = link[:i], link[i+1:]
+	}
+
+	request := &PaymentRequest{}
+	if i := strings.Index(link, "/"); i >= 0 {
+		link, request.Function = link[:i], link[i+1:]
+		if !functionRe.MatchString(request.Function) {
+			return nil, ErrInvalidFunction
+		}
+	}
+
+	if i := strings.Index(link, "@"); i >= 0 {
+		chainID, err := strconv.ParseUint(link[i+1:], 10, 64)
+		if err != nil || chainID == 0 {
+			return nil, ErrInvalidChainID
+		}
+		link, request.ChainID = link[:i], chainID
+	}
+
+	target, err := parseTarget(link)
+	if err != nil {
+		return nil, err
+	}
+	request.Target = target
+
+	if err := request.parseQuery(query); err != nil {
+		return nil, err
+	}
+
+	return &Action{Type: ActionPaymentRequest, PaymentRequest: request}, nil
+}
+
+// parseTarget validates address (including its checksum, if it is mixed-case) or ENS name.
+func parseTarget(target string) (string, error) {
+	if addressRe.MatchString(target) {
+		hex := target[2:]
+		if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) &&
+			common.HexToAddress(target).Hex() != target {
+			return "", ErrInvalidAddress
+		}
+		return target, nil
+	}
+
+	if ensNameRe.MatchString(target) {
+		return target, nil
+	}
+
+	return "", ErrInvalidAddress
+}
+
+// parseQuery parses parameters of payment request: value and gas are allowed for any request,
+// while typed arguments (such as "address=0x..." or "uint256=1") are allowed for function calls only.
+// Parameters are kept in the order they are listed, as ERC-681 arguments are positional.
+func (r *PaymentRequest) parseQuery(query string) error {
+	if query == "" {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(query, "&") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return ErrInvalidParameter
+		}
+		key := parts[0]
+		value, err := url.QueryUnescape(parts[1])
+		if err != nil {
+			return ErrInvalidParameter
+		}
+
+		switch key {
+		case "value", "gas", "gasLimit", "gasPrice":
+			if seen[key] || (key == "gasLimit" && seen["gas"]) || (key == "gas" && seen["gasLimit"]) {
+				return ErrDuplicateParam
+			}
+			seen[key] = true
+
+			number, err := parseNumber(value)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "value":
+				r.Value = (*hexutil.Big)(number)
+			case "gas", "gasLimit":
+				r.Gas = (*hexutil.Big)(number)
+			case "gasPrice":
+				r.GasPrice = (*hexutil.Big)(number)
+			}
+		default:
+			if r.Function == "" || !abiTypeRe.MatchString(key) {
+				return ErrInvalidParameter
+			}
+			r.Parameters = append(r.Parameters, Parameter{Type: key, Value: value})
+		}
+	}
+
+	return nil
+}
+
+// parseNumber parses non-negative integer, allowing scientific notation, such as "2.014e18".
+func parseNumber(s string) (*big.Int, error) {
+	m := numberRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, ErrInvalidNumber
+	}
+
+	digits, fraction := m[1]+m[3], len(m[3])
+	exponent := 0
+	if m[5] != "" {
+		var err error
+		// limit exponent, so that huge numbers couldn't be used to exhaust memory
+		if exponent, err = strconv.Atoi(m[5]); err != nil || exponent > 77 {
+			return nil, ErrInvalidNumber
+		}
+	}
+
+	if exponent < fraction {
+		// only trailing zeros of fraction may be dropped
+		if strings.Trim(digits[len(digits)-(fraction-exponent):], "0") != "" {
+			return nil, ErrInvalidNumber
+		}
+		digits = digits[:len(digits)-(fraction-exponent)]
+	} else {
+		digits += strings.Repeat("0", exponent-fraction)
+	}
+
+	number, ok := new(big.Int).SetString(digits, 10)
+	if !ok || number.BitLen() > 256 {
+		return nil, ErrInvalidNumber
+	}
+
+	return number, nil
+}
@@ -0,0 +1,90 @@
+package deeplink
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	publicKeyRe  = regexp.MustCompile(`^0x04[0-9a-fA-F]{128}$`)
+	publicChatRe = regexp.MustCompile(`^[a-z0-9\-]{1,64}$`)
+)
+
+// Chat types
+const (
+	ChatPublic   = "public"
+	ChatOneToOne = "one-to-one"
+)
+
+// Chat describes chat to be opened: public one by its name, or one-to-one by public key of contact.
+type Chat struct {
+	Type      string `json:"type"`
+	Name      string `json:"name,omitempty"`
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// Contact describes contact to be added.
+type Contact struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// DApp describes dapp to be opened in browser.
+type DApp struct {
+	URL string `json:"url"`
+}
+
+// parseStatusLink parses links of "status-im:" scheme, such as "//chat/public/<name>",
+// "//chat/<public key>", "//user/<public key>" and "//browse/<url>".
+func parseStatusLink(link string) (*Action, error) {
+	path := strings.TrimPrefix(link, "//")
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, ErrUnknownAction
+	}
+
+	switch action, arg := parts[0], parts[1]; action {
+	case "chat":
+		if strings.HasPrefix(arg, ChatPublic+"/") {
+			name := strings.TrimPrefix(arg, ChatPublic+"/")
+			if !publicChatRe.MatchString(name) {
+				return nil, ErrInvalidChatName
+			}
+			return &Action{Type: ActionOpenChat, Chat: &Chat{Type: ChatPublic, Name: name}}, nil
+		}
+		if !publicKeyRe.MatchString(arg) {
+			return nil, ErrInvalidPublicKey
+		}
+		return &Action{Type: ActionOpenChat, Chat: &Chat{Type: ChatOneToOne, PublicKey: arg}}, nil
+	case "user":
+		if !publicKeyRe.MatchString(arg) {
+			return nil, ErrInvalidPublicKey
+		}
+		return &Action{Type: ActionAddContact, Contact: &Contact{PublicKey: arg}}, nil
+	case "browse":
+		dappURL, err := parseDAppURL(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &Action{Type: ActionOpenDApp, DApp: &DApp{URL: dappURL}}, nil
+	}
+
+	return nil, ErrUnknownAction
+}
+
+// parseDAppURL accepts only http(s) URLs, without credentials, adding https scheme, if it is omitted.
+func parseDAppURL(rawURL string) (string, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return "", ErrInvalidDAppURL
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", ErrInvalidDAppURL
+	}
+
+	return u.String(), nil
+}
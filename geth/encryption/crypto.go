@@ -0,0 +1,103 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+)
+
+// keyLength is a length of root, chain and message keys
+const keyLength = 32
+
+// errors
+var (
+	ErrCiphertextTooShort = errors.New("ciphertext is too short")
+)
+
+// dh returns shared secret of ECDH key agreement on secp256k1 curve.
+func dh(private *ecdsa.PrivateKey, public *ecdsa.PublicKey) ([]byte, error) {
+	return ecies.ImportECDSA(private).GenerateShared(ecies.ImportECDSAPublic(public), keyLength/2, keyLength/2)
+}
+
+// hkdf derives length bytes from input key material, salt and info (RFC 5869, with SHA-256).
+func hkdf(ikm, salt, info []byte, length int) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, sha256.Size)
+	}
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm) // nolint: errcheck
+	prk := extract.Sum(nil)
+
+	var out, block []byte
+	for i := byte(1); len(out) < length; i++ {
+		expand := hmac.New(sha256.New, prk)
+		expand.Write(block)     // nolint: errcheck
+		expand.Write(info)      // nolint: errcheck
+		expand.Write([]byte{i}) // nolint: errcheck
+		block = expand.Sum(nil)
+		out = append(out, block...)
+	}
+
+	return out[:length]
+}
+
+// kdfRK derives new root key and chain key from root key and output of DH ratchet step.
+func kdfRK(rootKey, dhOut []byte) (newRootKey, chainKey []byte) {
+	out := hkdf(dhOut, rootKey, []byte("status-go/encryption/ratchet"), 2*keyLength)
+	return out[:keyLength], out[keyLength:]
+}
+
+// kdfCK derives next chain key and message key from chain key.
+func kdfCK(chainKey []byte) (nextChainKey, messageKey []byte) {
+	mac := hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{0x02}) // nolint: errcheck
+	nextChainKey = mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{0x01}) // nolint: errcheck
+	messageKey = mac.Sum(nil)
+
+	return nextChainKey, messageKey
+}
+
+// encrypt seals plaintext with AES-256-GCM, authenticating associated data. Nonce is prepended to ciphertext.
+func encrypt(key, plaintext, ad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, ad), nil
+}
+
+// decrypt opens ciphertext, sealed with encrypt.
+func decrypt(key, ciphertext, ad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrCiphertextTooShort
+	}
+
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], ad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,261 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// maxSkip limits number of message keys, which are skipped within a single step, and number of stored
+// skipped keys (the oldest ones are evicted), so that malicious sender couldn't make receiver compute
+// and store too many of them
+const maxSkip = 1000
+
+// errors
+var (
+	ErrTooManySkipped   = errors.New("too many skipped messages")
+	ErrSessionNotReady  = errors.New("session can't be used to send messages, until a message is received")
+	ErrDecryptionFailed = errors.New("message can't be decrypted")
+)
+
+// RatchetHeader is sent along with each message, so that receiver could advance its ratchet.
+type RatchetHeader struct {
+	DH hexutil.Bytes `json:"dh"` // current ratchet public key of sender
+	PN uint32        `json:"pn"` // number of messages in previous sending chain
+	N  uint32        `json:"n"`  // number of message in current sending chain
+}
+
+// bytes encodes header for authentication as associated data.
+func (h RatchetHeader) bytes() []byte {
+	data := make([]byte, len(h.DH)+8)
+	copy(data, h.DH)
+	binary.BigEndian.PutUint32(data[len(h.DH):], h.PN)
+	binary.BigEndian.PutUint32(data[len(h.DH)+4:], h.N)
+	return data
+}
+
+// ratchet is a state of Double Ratchet (see https://signal.org/docs/specifications/doubleratchet/).
+// It is serialized as is, to be persisted along with session.
+type ratchet struct {
+	DHs     hexutil.Bytes            `json:"dhs"` // private ratchet key
+	DHr     hexutil.Bytes            `json:"dhr"` // public ratchet key of other party
+	RK      hexutil.Bytes            `json:"rk"`
+	CKs     hexutil.Bytes            `json:"cks"`
+	CKr     hexutil.Bytes            `json:"ckr"`
+	Ns      uint32                   `json:"ns"`
+	Nr      uint32                   `json:"nr"`
+	PN      uint32                   `json:"pn"`
+	Skipped map[string]hexutil.Bytes `json:"skipped"` // message keys of skipped messages, keyed by skippedKey
+	Order   []string                 `json:"order"`   // keys of Skipped, from the oldest one
+}
+
+// newInitiatorRatchet initializes ratchet of party, which sends the first message, given shared secret
+// and ratchet key of other party (its signed pre-key).
+func newInitiatorRatchet(secret []byte, theirRatchetKey *ecdsa.PublicKey) (*ratchet, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	dhOut, err := dh(key, theirRatchetKey)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &ratchet{
+		DHs:     crypto.FromECDSA(key),
+		DHr:     crypto.FromECDSAPub(theirRatchetKey),
+		Skipped: make(map[string]hexutil.Bytes),
+	}
+	r.RK, r.CKs = kdfRK(secret, dhOut)
+
+	return r, nil
+}
+
+// newResponderRatchet initializes ratchet of party, which receives the first message, given shared secret
+// and its ratchet key (signed pre-key).
+func newResponderRatchet(secret []byte, ratchetKey *ecdsa.PrivateKey) *ratchet {
+	return &ratchet{
+		DHs:     crypto.FromECDSA(ratchetKey),
+		RK:      secret,
+		Skipped: make(map[string]hexutil.Bytes),
+	}
+}
+
+// encrypt encrypts plaintext with the next message key of sending chain.
+func (r *ratchet) encrypt(plaintext, ad []byte) (RatchetHeader, []byte, error) {
+	if r.CKs == nil {
+		return RatchetHeader{}, nil, ErrSessionNotReady
+	}
+	key, err := crypto.ToECDSA(r.DHs)
+	if err != nil {
+		return RatchetHeader{}, nil, err
+	}
+
+	var messageKey []byte
+	r.CKs, messageKey = kdfCK(r.CKs)
+	header := RatchetHeader{
+		DH: crypto.FromECDSAPub(&key.PublicKey),
+		PN: r.PN,
+		N:  r.Ns,
+	}
+	r.Ns++
+
+	ciphertext, err := encrypt(messageKey, plaintext, append(append([]byte{}, ad...), header.bytes()...))
+	if err != nil {
+		return RatchetHeader{}, nil, err
+	}
+
+	return header, ciphertext, nil
+}
+
+// decrypt decrypts message, performing DH ratchet step, if sender's ratchet key is new.
+// State is not changed, unless message is decrypted, so it's safe to call it with forged messages.
+func (r *ratchet) decrypt(header RatchetHeader, ciphertext, ad []byte) ([]byte, error) {
+	ad = append(append([]byte{}, ad...), header.bytes()...)
+
+	skipped := skippedKey(header.DH, header.N)
+	if messageKey, ok := r.Skipped[skipped]; ok {
+		plaintext, err := decrypt(messageKey, ciphertext, ad)
+		if err != nil {
+			return nil, ErrDecryptionFailed
+		}
+		r.forget(skipped)
+		return plaintext, nil
+	}
+
+	next := r.clone()
+	if header.DH.String() != next.DHr.String() {
+		if err := next.skip(header.PN); err != nil {
+			return nil, err
+		}
+		if err := next.dhRatchet(header); err != nil {
+			return nil, err
+		}
+	}
+	if err := next.skip(header.N); err != nil {
+		return nil, err
+	}
+
+	var messageKey []byte
+	next.CKr, messageKey = kdfCK(next.CKr)
+	next.Nr++
+
+	plaintext, err := decrypt(messageKey, ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	*r = *next
+
+	return plaintext, nil
+}
+
+// skip stores message keys of receiving chain up to message until, evicting the oldest stored keys,
+// so that no more than maxSkip of them are kept.
+func (r *ratchet) skip(until uint32) error {
+	if r.CKr == nil || until <= r.Nr {
+		return nil
+	}
+	if until-r.Nr > maxSkip {
+		return ErrTooManySkipped
+	}
+
+	r.restoreOrder()
+	for len(r.Skipped)+int(until-r.Nr) > maxSkip {
+		r.forget(r.Order[0])
+	}
+	for ; r.Nr < until; r.Nr++ {
+		var messageKey []byte
+		r.CKr, messageKey = kdfCK(r.CKr)
+		key := skippedKey(r.DHr, r.Nr)
+		r.Skipped[key] = messageKey
+		r.Order = append(r.Order, key)
+	}
+	return nil
+}
+
+// forget removes skipped message key.
+func (r *ratchet) forget(key string) {
+	delete(r.Skipped, key)
+	for i := range r.Order {
+		if r.Order[i] == key {
+			r.Order = append(r.Order[:i], r.Order[i+1:]...)
+			break
+		}
+	}
+}
+
+// restoreOrder appends keys, which are missing in order of skipped keys (e.g. of states persisted
+// before order was kept), as the oldest ones.
+func (r *ratchet) restoreOrder() {
+	if len(r.Order) == len(r.Skipped) {
+		return
+	}
+
+	ordered := make(map[string]bool, len(r.Order))
+	for _, key := range r.Order {
+		ordered[key] = true
+	}
+	var missing []string
+	for key := range r.Skipped {
+		if !ordered[key] {
+			missing = append(missing, key)
+		}
+	}
+	r.Order = append(missing, r.Order...)
+}
+
+// dhRatchet starts new receiving and sending chains with a new ratchet key of other party.
+func (r *ratchet) dhRatchet(header RatchetHeader) error {
+	theirKey, err := toPublicKey(header.DH)
+	if err != nil {
+		return err
+	}
+	key, err := crypto.ToECDSA(r.DHs)
+	if err != nil {
+		return err
+	}
+
+	r.PN = r.Ns
+	r.Ns = 0
+	r.Nr = 0
+	r.DHr = header.DH
+
+	dhOut, err := dh(key, theirKey)
+	if err != nil {
+		return err
+	}
+	r.RK, r.CKr = kdfRK(r.RK, dhOut)
+
+	if key, err = crypto.GenerateKey(); err != nil {
+		return err
+	}
+	r.DHs = crypto.FromECDSA(key)
+	if dhOut, err = dh(key, theirKey); err != nil {
+		return err
+	}
+	r.RK, r.CKs = kdfRK(r.RK, dhOut)
+
+	return nil
+}
+
+// clone returns copy of ratchet, which can be changed independently.
+func (r *ratchet) clone() *ratchet {
+	c := *r
+	c.Skipped = make(map[string]hexutil.Bytes, len(r.Skipped))
+	for k, v := range r.Skipped {
+		c.Skipped[k] = v
+	}
+	if r.Order != nil {
+		c.Order = append(make([]string, 0, len(r.Order)), r.Order...)
+	}
+	return &c
+}
+
+// skippedKey returns key of skipped message key: ratchet key of its chain, and its number.
+func skippedKey(dh hexutil.Bytes, n uint32) string {
+	return fmt.Sprintf("%s:%d", dh.String(), n)
+}
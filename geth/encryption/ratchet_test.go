@@ -0,0 +1,146 @@
+package encryption
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// newRatchets returns ratchets of initiator and responder, which agreed on X3DH secret.
+func newRatchets(t *testing.T) (alice, bob *ratchet, ad []byte) {
+	aliceIdentity, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bobIdentity, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bobSignedPreKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ephemeral, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	bundle, err := newBundle(bobIdentity, bobSignedPreKey, 1)
+	require.NoError(t, err)
+	theirIdentity, theirSignedPreKey, err := bundle.Verify()
+	require.NoError(t, err)
+
+	aliceSecret, err := x3dhInitiate(aliceIdentity, ephemeral, theirIdentity, theirSignedPreKey)
+	require.NoError(t, err)
+	bobSecret, err := x3dhRespond(bobIdentity, bobSignedPreKey, &aliceIdentity.PublicKey, &ephemeral.PublicKey)
+	require.NoError(t, err)
+	require.Equal(t, aliceSecret, bobSecret)
+
+	alice, err = newInitiatorRatchet(aliceSecret, theirSignedPreKey)
+	require.NoError(t, err)
+	bob = newResponderRatchet(bobSecret, bobSignedPreKey)
+
+	return alice, bob, associatedData(&aliceIdentity.PublicKey, &bobIdentity.PublicKey)
+}
+
+func TestBundleVerify(t *testing.T) {
+	identity, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signedPreKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	bundle, err := newBundle(identity, signedPreKey, 1)
+	require.NoError(t, err)
+	_, _, err = bundle.Verify()
+	require.NoError(t, err)
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bundle.Identity = crypto.FromECDSAPub(&other.PublicKey)
+	_, _, err = bundle.Verify()
+	require.Equal(t, ErrInvalidBundle, err)
+
+	bundle.Identity = []byte{0x04, 0x01}
+	_, _, err = bundle.Verify()
+	require.Equal(t, ErrInvalidPublicKey, err)
+}
+
+func TestRatchet(t *testing.T) {
+	alice, bob, ad := newRatchets(t)
+
+	// responder can't send, until it receives a message
+	_, _, err := bob.encrypt([]byte("hello"), ad)
+	require.Equal(t, ErrSessionNotReady, err)
+
+	header1, ciphertext1, err := alice.encrypt([]byte("one"), ad)
+	require.NoError(t, err)
+	header2, ciphertext2, err := alice.encrypt([]byte("two"), ad)
+	require.NoError(t, err)
+
+	// messages are decrypted out of order
+	plaintext, err := bob.decrypt(header2, ciphertext2, ad)
+	require.NoError(t, err)
+	require.Equal(t, []byte("two"), plaintext)
+	plaintext, err = bob.decrypt(header1, ciphertext1, ad)
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), plaintext)
+	require.Empty(t, bob.Skipped)
+
+	// replayed message can't be decrypted
+	_, err = bob.decrypt(header1, ciphertext1, ad)
+	require.Equal(t, ErrDecryptionFailed, err)
+
+	header, ciphertext, err := bob.encrypt([]byte("three"), ad)
+	require.NoError(t, err)
+	plaintext, err = alice.decrypt(header, ciphertext, ad)
+	require.NoError(t, err)
+	require.Equal(t, []byte("three"), plaintext)
+
+	// forged message doesn't change state
+	header, ciphertext, err = alice.encrypt([]byte("four"), ad)
+	require.NoError(t, err)
+	state := bob.clone()
+	_, err = bob.decrypt(header, append([]byte{}, ciphertext[:len(ciphertext)-1]...), ad)
+	require.Equal(t, ErrDecryptionFailed, err)
+	require.Equal(t, state, bob)
+	plaintext, err = bob.decrypt(header, ciphertext, ad)
+	require.NoError(t, err)
+	require.Equal(t, []byte("four"), plaintext)
+}
+
+func TestRatchetTooManySkipped(t *testing.T) {
+	alice, bob, ad := newRatchets(t)
+
+	header, ciphertext, err := alice.encrypt([]byte("one"), ad)
+	require.NoError(t, err)
+	header.N = maxSkip + 1
+	_, err = bob.decrypt(header, ciphertext, ad)
+	require.Equal(t, ErrTooManySkipped, err)
+}
+
+func TestRatchetSkippedKeysEviction(t *testing.T) {
+	alice, bob, ad := newRatchets(t)
+
+	// more than maxSkip messages are skipped over time, only the last one of each batch is received
+	var first, recent struct {
+		header     RatchetHeader
+		ciphertext []byte
+	}
+	for batch := 0; batch < 3; batch++ {
+		for i := 0; i < maxSkip/2; i++ {
+			header, ciphertext, err := alice.encrypt([]byte("skipped"), ad)
+			require.NoError(t, err)
+			if batch == 0 && i == 0 {
+				first.header, first.ciphertext = header, ciphertext
+			}
+			recent.header, recent.ciphertext = header, ciphertext
+		}
+		header, ciphertext, err := alice.encrypt([]byte("received"), ad)
+		require.NoError(t, err)
+		plaintext, err := bob.decrypt(header, ciphertext, ad)
+		require.NoError(t, err)
+		require.Equal(t, []byte("received"), plaintext)
+		require.True(t, len(bob.Skipped) <= maxSkip)
+		require.Len(t, bob.Order, len(bob.Skipped))
+	}
+
+	// the oldest skipped keys are evicted, recent ones are kept
+	_, err := bob.decrypt(first.header, first.ciphertext, ad)
+	require.Equal(t, ErrDecryptionFailed, err)
+	plaintext, err := bob.decrypt(recent.header, recent.ciphertext, ad)
+	require.NoError(t, err)
+	require.Equal(t, []byte("skipped"), plaintext)
+}
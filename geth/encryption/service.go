@@ -0,0 +1,345 @@
+// Package encryption implements end-to-end encrypted one-to-one sessions with forward secrecy:
+// sessions are started with X3DH key agreement against published bundle of contact, and messages
+// are encrypted with Double Ratchet. Sessions are persisted per account, encrypted under its key.
+package encryption
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/common"
+)
+
+const (
+	// encryptionDir is a directory (relative to Whisper data dir) where state files are stored
+	encryptionDir = "encryption"
+
+	// signedPreKeyLifetime is a period, signed pre-key is rotated after
+	signedPreKeyLifetime = 7 * 24 * time.Hour
+
+	// maxSignedPreKeys is a number of signed pre-keys kept, so that sessions could be started
+	// with bundles, which were published before rotation
+	maxSignedPreKeys = 3
+)
+
+// errors
+var (
+	ErrLocked              = errors.New("encryption sessions are locked, please login")
+	ErrNoBundle            = errors.New("no session and no bundle of contact to start it with")
+	ErrNoSession           = errors.New("no session with contact")
+	ErrUnknownSignedPreKey = errors.New("session is started with unknown signed pre-key")
+)
+
+// EncryptedMessage is a payload encrypted within session.
+type EncryptedMessage struct {
+	X3DH       *X3DHHeader   `json:"x3dh,omitempty"`
+	Header     RatchetHeader `json:"header"`
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+}
+
+// Service manages encrypted sessions of the selected account with its contacts.
+type Service struct {
+	nodeManager common.NodeManager
+
+	mu       sync.Mutex
+	path     string // path to the state file of the selected account
+	encKey   []byte // key the state file is encrypted with
+	identity *ecdsa.PrivateKey
+	state    *state
+	now      func() time.Time
+}
+
+// New returns new encryption service.
+func New(nodeManager common.NodeManager) *Service {
+	return &Service{
+		nodeManager: nodeManager,
+		now:         time.Now,
+	}
+}
+
+// Unlock loads sessions of a given account.
+func (s *Service) Unlock(address gethcommon.Address, accountKey *ecdsa.PrivateKey) error {
+	config, err := s.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(config.WhisperConfig.DataDir, encryptionDir, strings.ToLower(address.Hex())+".json")
+	encKey := stateEncryptionKey(accountKey)
+	st, err := loadState(path, encKey)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+	s.encKey = encKey
+	s.identity = accountKey
+	s.state = st
+
+	return nil
+}
+
+// Lock clears sessions from memory.
+func (s *Service) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = ""
+	s.encKey = nil
+	s.identity = nil
+	s.state = nil
+}
+
+// Bundle returns bundle of the selected account, which is to be published to contacts.
+// Signed pre-key is rotated, if it's older than a week.
+func (s *Service) Bundle() (Bundle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return Bundle{}, ErrLocked
+	}
+
+	now := s.now()
+	keys := s.state.SignedPreKeys
+	if len(keys) == 0 || now.Sub(time.Unix(keys[len(keys)-1].Timestamp, 0)) >= signedPreKeyLifetime {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return Bundle{}, err
+		}
+		keys = append(keys, signedPreKey{Key: crypto.FromECDSA(key), Timestamp: now.Unix()})
+		if len(keys) > maxSignedPreKeys {
+			keys = keys[len(keys)-maxSignedPreKeys:]
+		}
+		s.state.SignedPreKeys = keys
+		if err := s.save(); err != nil {
+			return Bundle{}, err
+		}
+	}
+
+	latest := keys[len(keys)-1]
+	key, err := crypto.ToECDSA(latest.Key)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	return newBundle(s.identity, key, latest.Timestamp)
+}
+
+// ProcessBundle verifies and stores bundle of contact, so that session could be started with it.
+// Bundle is ignored, if a newer one is stored already.
+func (s *Service) ProcessBundle(bundle Bundle) error {
+	identity, _, err := bundle.Verify()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return ErrLocked
+	}
+
+	id := keyID(identity)
+	if stored, ok := s.state.Bundles[id]; ok && stored.Timestamp >= bundle.Timestamp {
+		return nil
+	}
+	s.state.Bundles[id] = bundle
+
+	return s.save()
+}
+
+// CanEncrypt returns true, if there's a session with contact, or a bundle to start it with.
+func (s *Service) CanEncrypt(theirIdentity *ecdsa.PublicKey) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return false
+	}
+
+	id := keyID(theirIdentity)
+	_, hasSession := s.state.Sessions[id]
+	_, hasBundle := s.state.Bundles[id]
+
+	return hasSession || hasBundle
+}
+
+// Encrypt encrypts payload for contact, starting a new session with its bundle, if there's no session yet.
+func (s *Service) Encrypt(theirIdentity *ecdsa.PublicKey, payload []byte) (*EncryptedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return nil, ErrLocked
+	}
+
+	id := keyID(theirIdentity)
+	sess, ok := s.state.Sessions[id]
+	if !ok {
+		bundle, ok := s.state.Bundles[id]
+		if !ok {
+			return nil, ErrNoBundle
+		}
+		var err error
+		if sess, err = s.initiate(theirIdentity, bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	header, ciphertext, err := sess.Ratchet.encrypt(payload, sess.AD)
+	if err != nil {
+		return nil, err
+	}
+	s.state.Sessions[id] = sess
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+
+	return &EncryptedMessage{
+		X3DH:       sess.X3DH,
+		Header:     header,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Decrypt decrypts message of contact, starting a new session, if contact has started it.
+// If both parties start sessions simultaneously, the one started by party with greater identity key wins.
+func (s *Service) Decrypt(theirIdentity *ecdsa.PublicKey, msg *EncryptedMessage) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return nil, ErrLocked
+	}
+
+	id := keyID(theirIdentity)
+	sess := s.state.Sessions[id]
+	keep := true
+	if msg.X3DH != nil && (sess == nil || !bytes.Equal(sess.TheirEphemeral, msg.X3DH.Ephemeral)) {
+		if sess != nil && sess.X3DH != nil {
+			own := crypto.FromECDSAPub(&s.identity.PublicKey)
+			keep = bytes.Compare(own, crypto.FromECDSAPub(theirIdentity)) < 0
+		}
+		var err error
+		if sess, err = s.respond(theirIdentity, msg.X3DH); err != nil {
+			return nil, err
+		}
+	}
+	if sess == nil {
+		return nil, ErrNoSession
+	}
+
+	plaintext, err := sess.Ratchet.decrypt(msg.Header, msg.Ciphertext, sess.AD)
+	if err != nil {
+		return nil, err
+	}
+	if !keep {
+		// message of session, which lost to our own one, is decrypted, but session is not stored
+		return plaintext, nil
+	}
+
+	// message is received, so initiator doesn't need to attach X3DH header anymore
+	sess.X3DH = nil
+	s.state.Sessions[id] = sess
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+
+	return plaintext, nil
+}
+
+// initiate starts a new session with owner of bundle. Must be called with lock held.
+func (s *Service) initiate(theirIdentity *ecdsa.PublicKey, bundle Bundle) (*session, error) {
+	_, theirSignedPreKey, err := bundle.Verify()
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := x3dhInitiate(s.identity, ephemeral, theirIdentity, theirSignedPreKey)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newInitiatorRatchet(secret, theirSignedPreKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{
+		Ratchet: r,
+		AD:      associatedData(&s.identity.PublicKey, theirIdentity),
+		X3DH: &X3DHHeader{
+			Ephemeral:    crypto.FromECDSAPub(&ephemeral.PublicKey),
+			SignedPreKey: bundle.SignedPreKey,
+		},
+	}, nil
+}
+
+// respond starts a new session, initiated by contact. Must be called with lock held.
+func (s *Service) respond(theirIdentity *ecdsa.PublicKey, header *X3DHHeader) (*session, error) {
+	theirEphemeral, err := toPublicKey(header.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	signedPreKey, err := s.signedPreKey(header.SignedPreKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := x3dhRespond(s.identity, signedPreKey, theirIdentity, theirEphemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{
+		Ratchet:        newResponderRatchet(secret, signedPreKey),
+		AD:             associatedData(theirIdentity, &s.identity.PublicKey),
+		TheirEphemeral: header.Ephemeral,
+	}, nil
+}
+
+// signedPreKey returns own signed pre-key with a given public key. Must be called with lock held.
+func (s *Service) signedPreKey(public []byte) (*ecdsa.PrivateKey, error) {
+	for _, spk := range s.state.SignedPreKeys {
+		key, err := crypto.ToECDSA(spk.Key)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(crypto.FromECDSAPub(&key.PublicKey), public) {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownSignedPreKey
+}
+
+// save writes state. Must be called with lock held.
+func (s *Service) save() error {
+	return saveState(s.path, s.encKey, s.state)
+}
+
+// associatedData binds session to identity keys of both parties: of initiator and of responder.
+func associatedData(initiator, responder *ecdsa.PublicKey) []byte {
+	return append(crypto.FromECDSAPub(initiator), crypto.FromECDSAPub(responder)...)
+}
+
+// keyID returns key of contact in state.
+func keyID(identity *ecdsa.PublicKey) string {
+	return hexutil.Encode(crypto.FromECDSAPub(identity))
+}
@@ -0,0 +1,193 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+type testAccount struct {
+	address gethcommon.Address
+	key     *ecdsa.PrivateKey
+	service *Service
+}
+
+func newTestAccount(t *testing.T, nodeManager common.NodeManager) *testAccount {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	account := &testAccount{
+		address: crypto.PubkeyToAddress(key.PublicKey),
+		key:     key,
+		service: New(nodeManager),
+	}
+	require.NoError(t, account.service.Unlock(account.address, key))
+	return account
+}
+
+func (a *testAccount) restart(t *testing.T, nodeManager common.NodeManager) {
+	a.service = New(nodeManager)
+	require.NoError(t, a.service.Unlock(a.address, a.key))
+}
+
+func newNodeManager(t *testing.T, ctrl *gomock.Controller, dir string) common.NodeManager {
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{DataDir: dir},
+	}, nil).AnyTimes()
+	return nodeManager
+}
+
+func TestSession(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	nodeManager := newNodeManager(t, ctrl, dir)
+
+	alice := newTestAccount(t, nodeManager)
+	bob := newTestAccount(t, nodeManager)
+
+	_, err = alice.service.Encrypt(&bob.key.PublicKey, []byte("hello"))
+	require.Equal(t, ErrNoBundle, err)
+	require.False(t, alice.service.CanEncrypt(&bob.key.PublicKey))
+
+	bundle, err := bob.service.Bundle()
+	require.NoError(t, err)
+	require.NoError(t, alice.service.ProcessBundle(bundle))
+	require.True(t, alice.service.CanEncrypt(&bob.key.PublicKey))
+
+	// initiator attaches X3DH header, until it receives a reply
+	msg1, err := alice.service.Encrypt(&bob.key.PublicKey, []byte("one"))
+	require.NoError(t, err)
+	require.NotNil(t, msg1.X3DH)
+	msg2, err := alice.service.Encrypt(&bob.key.PublicKey, []byte("two"))
+	require.NoError(t, err)
+	require.Equal(t, msg1.X3DH, msg2.X3DH)
+
+	plaintext, err := bob.service.Decrypt(&alice.key.PublicKey, msg2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("two"), plaintext)
+
+	// sessions survive restarts
+	bob.restart(t, nodeManager)
+	plaintext, err = bob.service.Decrypt(&alice.key.PublicKey, msg1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), plaintext)
+
+	reply, err := bob.service.Encrypt(&alice.key.PublicKey, []byte("three"))
+	require.NoError(t, err)
+	require.Nil(t, reply.X3DH)
+
+	alice.restart(t, nodeManager)
+	plaintext, err = alice.service.Decrypt(&bob.key.PublicKey, reply)
+	require.NoError(t, err)
+	require.Equal(t, []byte("three"), plaintext)
+
+	msg, err := alice.service.Encrypt(&bob.key.PublicKey, []byte("four"))
+	require.NoError(t, err)
+	require.Nil(t, msg.X3DH)
+	plaintext, err = bob.service.Decrypt(&alice.key.PublicKey, msg)
+	require.NoError(t, err)
+	require.Equal(t, []byte("four"), plaintext)
+
+	// message can't be decrypted by another contact
+	eve := newTestAccount(t, nodeManager)
+	_, err = eve.service.Decrypt(&alice.key.PublicKey, msg)
+	require.Equal(t, ErrNoSession, err)
+
+	alice.service.Lock()
+	_, err = alice.service.Encrypt(&bob.key.PublicKey, []byte("five"))
+	require.Equal(t, ErrLocked, err)
+}
+
+func TestSimultaneousSessions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	nodeManager := newNodeManager(t, ctrl, dir)
+
+	alice := newTestAccount(t, nodeManager)
+	bob := newTestAccount(t, nodeManager)
+
+	aliceBundle, err := alice.service.Bundle()
+	require.NoError(t, err)
+	bobBundle, err := bob.service.Bundle()
+	require.NoError(t, err)
+	require.NoError(t, alice.service.ProcessBundle(bobBundle))
+	require.NoError(t, bob.service.ProcessBundle(aliceBundle))
+
+	fromAlice, err := alice.service.Encrypt(&bob.key.PublicKey, []byte("alice"))
+	require.NoError(t, err)
+	fromBob, err := bob.service.Encrypt(&alice.key.PublicKey, []byte("bob"))
+	require.NoError(t, err)
+
+	plaintext, err := bob.service.Decrypt(&alice.key.PublicKey, fromAlice)
+	require.NoError(t, err)
+	require.Equal(t, []byte("alice"), plaintext)
+	plaintext, err = alice.service.Decrypt(&bob.key.PublicKey, fromBob)
+	require.NoError(t, err)
+	require.Equal(t, []byte("bob"), plaintext)
+
+	// both parties end up in the same session
+	for i := 0; i < 2; i++ {
+		msg, err := alice.service.Encrypt(&bob.key.PublicKey, []byte("ping"))
+		require.NoError(t, err)
+		plaintext, err = bob.service.Decrypt(&alice.key.PublicKey, msg)
+		require.NoError(t, err)
+		require.Equal(t, []byte("ping"), plaintext)
+
+		msg, err = bob.service.Encrypt(&alice.key.PublicKey, []byte("pong"))
+		require.NoError(t, err)
+		plaintext, err = alice.service.Decrypt(&bob.key.PublicKey, msg)
+		require.NoError(t, err)
+		require.Equal(t, []byte("pong"), plaintext)
+	}
+}
+
+func TestSignedPreKeyRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	nodeManager := newNodeManager(t, ctrl, dir)
+
+	alice := newTestAccount(t, nodeManager)
+	bob := newTestAccount(t, nodeManager)
+
+	now := time.Now()
+	bob.service.now = func() time.Time { return now }
+	oldBundle, err := bob.service.Bundle()
+	require.NoError(t, err)
+	bundle, err := bob.service.Bundle()
+	require.NoError(t, err)
+	require.Equal(t, oldBundle.SignedPreKey, bundle.SignedPreKey)
+
+	now = now.Add(signedPreKeyLifetime)
+	bundle, err = bob.service.Bundle()
+	require.NoError(t, err)
+	require.NotEqual(t, oldBundle.SignedPreKey, bundle.SignedPreKey)
+
+	// sessions can still be started with bundle published before rotation
+	require.NoError(t, alice.service.ProcessBundle(oldBundle))
+	msg, err := alice.service.Encrypt(&bob.key.PublicKey, []byte("hello"))
+	require.NoError(t, err)
+	plaintext, err := bob.service.Decrypt(&alice.key.PublicKey, msg)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), plaintext)
+}
@@ -0,0 +1,131 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// stateFileVersion is a version of the state file format
+const stateFileVersion = 1
+
+// stateKeySalt is mixed into account key to obtain state encryption key
+var stateKeySalt = []byte("status-go/encryption/sessions")
+
+// errors
+var (
+	ErrStateFileVersion = errors.New("unsupported encryption state file version")
+)
+
+// signedPreKey is a signed pre-key of account, along with time it was created at.
+type signedPreKey struct {
+	Key       hexutil.Bytes `json:"key"`
+	Timestamp int64         `json:"timestamp"`
+}
+
+// session is a state of session with contact.
+type session struct {
+	Ratchet *ratchet      `json:"ratchet"`
+	AD      hexutil.Bytes `json:"ad"` // associated data: identity keys of initiator and responder
+
+	// X3DH is attached to sent messages by initiator, until a message is received within session
+	X3DH *X3DHHeader `json:"x3dh,omitempty"`
+	// TheirEphemeral is ephemeral key of initiator, if session has been started by contact
+	TheirEphemeral hexutil.Bytes `json:"theirEphemeral,omitempty"`
+}
+
+// state is everything, which is persisted for account: own signed pre-keys (newest last),
+// bundles of contacts and sessions with them, keyed by hex-encoded identity key of contact.
+type state struct {
+	SignedPreKeys []signedPreKey      `json:"signedPreKeys"`
+	Bundles       map[string]Bundle   `json:"bundles"`
+	Sessions      map[string]*session `json:"sessions"`
+}
+
+func newState() *state {
+	return &state{
+		Bundles:  make(map[string]Bundle),
+		Sessions: make(map[string]*session),
+	}
+}
+
+// stateFile is an on-disk representation of encrypted state.
+type stateFile struct {
+	Version    int           `json:"version"`
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+}
+
+// stateEncryptionKey derives state encryption key from account's private key.
+func stateEncryptionKey(accountKey *ecdsa.PrivateKey) []byte {
+	return crypto.Keccak256(crypto.FromECDSA(accountKey), stateKeySalt)
+}
+
+// loadState reads and decrypts state stored at a given path.
+// Missing file is not an error: empty state is returned instead.
+func loadState(path string, encKey []byte) (*state, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newState(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != stateFileVersion {
+		return nil, ErrStateFileVersion
+	}
+
+	plaintext, err := decrypt(encKey, file.Ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newState()
+	if err := json.Unmarshal(plaintext, s); err != nil {
+		return nil, err
+	}
+	if s.Bundles == nil {
+		s.Bundles = make(map[string]Bundle)
+	}
+	if s.Sessions == nil {
+		s.Sessions = make(map[string]*session)
+	}
+
+	return s, nil
+}
+
+// saveState encrypts and writes state to a given path.
+func saveState(path string, encKey []byte, s *state) error {
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := encrypt(encKey, plaintext, nil)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(stateFile{
+		Version:    stateFileVersion,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
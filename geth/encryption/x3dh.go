@@ -0,0 +1,127 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// errors
+var (
+	ErrInvalidBundle    = errors.New("invalid bundle, signed pre-key is not signed by identity key")
+	ErrInvalidPublicKey = errors.New("invalid public key")
+)
+
+// Bundle is published by contact, so that others could start sessions with it, without it being online:
+// signed pre-key, which is signed by identity key (the one of account), is used for X3DH key agreement,
+// and as the first ratchet key of receiving party. One-time pre-keys are not used, as there's no server
+// to hand them out one by one.
+type Bundle struct {
+	Identity     hexutil.Bytes `json:"identity"`
+	SignedPreKey hexutil.Bytes `json:"signedPreKey"`
+	Timestamp    int64         `json:"timestamp"` // unix time, signed pre-key is created at
+	Signature    hexutil.Bytes `json:"signature"`
+}
+
+// X3DHHeader is attached to messages of initiator of session, until response is received,
+// so that receiving party could agree on the same shared secret.
+type X3DHHeader struct {
+	Ephemeral    hexutil.Bytes `json:"ephemeral"`
+	SignedPreKey hexutil.Bytes `json:"signedPreKey"` // signed pre-key of receiving party, which has been used
+}
+
+// newBundle returns bundle of signed pre-key, signed with identity key.
+func newBundle(identity, signedPreKey *ecdsa.PrivateKey, timestamp int64) (Bundle, error) {
+	bundle := Bundle{
+		Identity:     crypto.FromECDSAPub(&identity.PublicKey),
+		SignedPreKey: crypto.FromECDSAPub(&signedPreKey.PublicKey),
+		Timestamp:    timestamp,
+	}
+	signature, err := crypto.Sign(bundle.hash(), identity)
+	if err != nil {
+		return Bundle{}, err
+	}
+	bundle.Signature = signature
+
+	return bundle, nil
+}
+
+// hash returns hash of signed part of bundle.
+func (b Bundle) hash() []byte {
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, uint64(b.Timestamp))
+	return crypto.Keccak256(b.SignedPreKey, timestamp)
+}
+
+// Verify checks that signed pre-key of bundle is signed by its identity key, and returns both keys.
+func (b Bundle) Verify() (identity, signedPreKey *ecdsa.PublicKey, err error) {
+	if identity, err = toPublicKey(b.Identity); err != nil {
+		return nil, nil, err
+	}
+	if signedPreKey, err = toPublicKey(b.SignedPreKey); err != nil {
+		return nil, nil, err
+	}
+
+	signer, err := crypto.SigToPub(b.hash(), b.Signature)
+	if err != nil || !bytes.Equal(crypto.FromECDSAPub(signer), b.Identity) {
+		return nil, nil, ErrInvalidBundle
+	}
+
+	return identity, signedPreKey, nil
+}
+
+// x3dhInitiate agrees on shared secret with owner of bundle (X3DH, without one-time pre-keys):
+// DH1 = DH(IKa, SPKb), DH2 = DH(EKa, IKb), DH3 = DH(EKa, SPKb), SK = KDF(DH1 || DH2 || DH3).
+func x3dhInitiate(identity, ephemeral *ecdsa.PrivateKey, theirIdentity, theirSignedPreKey *ecdsa.PublicKey) ([]byte, error) {
+	dh1, err := dh(identity, theirSignedPreKey)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := dh(ephemeral, theirIdentity)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := dh(ephemeral, theirSignedPreKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return x3dhSecret(dh1, dh2, dh3), nil
+}
+
+// x3dhRespond agrees on the same shared secret as initiator, given its identity and ephemeral keys.
+func x3dhRespond(identity, signedPreKey *ecdsa.PrivateKey, theirIdentity, theirEphemeral *ecdsa.PublicKey) ([]byte, error) {
+	dh1, err := dh(signedPreKey, theirIdentity)
+	if err != nil {
+		return nil, err
+	}
+	dh2, err := dh(identity, theirEphemeral)
+	if err != nil {
+		return nil, err
+	}
+	dh3, err := dh(signedPreKey, theirEphemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	return x3dhSecret(dh1, dh2, dh3), nil
+}
+
+func x3dhSecret(dh1, dh2, dh3 []byte) []byte {
+	ikm := make([]byte, 0, len(dh1)+len(dh2)+len(dh3))
+	ikm = append(append(append(ikm, dh1...), dh2...), dh3...)
+	return hkdf(ikm, nil, []byte("status-go/encryption/x3dh"), keyLength)
+}
+
+// toPublicKey decodes and validates public key.
+func toPublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	key := crypto.ToECDSAPub(data)
+	if key == nil || key.X == nil || !key.Curve.IsOnCurve(key.X, key.Y) {
+		return nil, ErrInvalidPublicKey
+	}
+	return key, nil
+}
@@ -0,0 +1,338 @@
+// Package ens resolves ENS names to addresses and public keys of contacts, and back, so that contacts
+// could be discovered by usernames (subdomains of Status domain) without round-tripping through JS in the jail.
+// Calls are made with RPC client of the running node, so they are routed to LES or to upstream.
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventENSResolved is triggered when name is resolved (not from cache), either forward or reverse
+	EventENSResolved = "ens.resolved"
+
+	// StatusDomain is a domain, usernames of Status are registered as subdomains of
+	StatusDomain = "stateofus.eth"
+
+	// cacheTTL is a time, resolutions are cached for
+	cacheTTL = 10 * time.Minute
+)
+
+// registries are addresses of ENS registry contracts of public networks
+var registries = map[uint64]gethcommon.Address{
+	params.MainNetworkID:    gethcommon.HexToAddress("0x314159265dD8dbb310642f98f50C066173C1259b"),
+	params.RopstenNetworkID: gethcommon.HexToAddress("0x112234455C3a32FD11230C42E7Bccd4A84e02010"),
+	params.RinkebyNetworkID: gethcommon.HexToAddress("0xe7410170f87102DF0055eB195163A03B7F2Bff4A"),
+}
+
+// selectors of functions of registry, resolver and registrar contracts
+var (
+	ownerSelector    = crypto.Keccak256([]byte("owner(bytes32)"))[:4]
+	resolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	addrSelector     = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+	pubkeySelector   = crypto.Keccak256([]byte("pubkey(bytes32)"))[:4]
+	nameSelector     = crypto.Keccak256([]byte("name(bytes32)"))[:4]
+	registerSelector = crypto.Keccak256([]byte("register(bytes32,address,bytes32,bytes32)"))[:4]
+)
+
+// errors
+var (
+	ErrNoRegistry       = errors.New("ENS registry is not known for the network")
+	ErrNameNotFound     = errors.New("ENS name has no resolver or address")
+	ErrReverseNotFound  = errors.New("address has no ENS reverse record")
+	ErrReverseMismatch  = errors.New("ENS reverse record doesn't resolve back to address")
+	ErrInvalidResponse  = errors.New("invalid response of ENS contract")
+	ErrInvalidUsername  = errors.New("username must be a single label, without dots")
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	ErrInvalidKey       = errors.New("key must be either an address or a public key")
+)
+
+// Resolution is a result of resolution of ENS name, it's sent with EventENSResolved signal.
+type Resolution struct {
+	Name      string             `json:"name"`
+	Address   gethcommon.Address `json:"address"`
+	PublicKey hexutil.Bytes      `json:"publicKey,omitempty"` // uncompressed public key, if it's set by resolver
+}
+
+// cached is a cached resolution.
+type cached struct {
+	resolution Resolution
+	expires    time.Time
+}
+
+// Service resolves ENS names with registry of the running node's network. Resolutions are cached
+// for a while, by names, and by addresses, if they are resolved in reverse.
+type Service struct {
+	nodeManager common.NodeManager
+
+	mu        sync.Mutex
+	byName    map[string]cached // keyed by normalized name
+	byAddress map[string]cached // keyed by hex of address
+	now       func() time.Time
+}
+
+// New returns new ENS resolution service.
+func New(nodeManager common.NodeManager) *Service {
+	return &Service{
+		nodeManager: nodeManager,
+		byName:      make(map[string]cached),
+		byAddress:   make(map[string]cached),
+		now:         time.Now,
+	}
+}
+
+// ResolveName resolves name (bare usernames are resolved as subdomains of Status domain)
+// to address and public key.
+func (s *Service) ResolveName(ctx context.Context, name string) (Resolution, error) {
+	name = Normalize(name)
+	if resolution, ok := s.cached(s.byName, name); ok {
+		return resolution, nil
+	}
+
+	client, registry, err := s.client()
+	if err != nil {
+		return Resolution{}, err
+	}
+	resolution, err := resolve(ctx, client, registry, name)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	s.cache(resolution, false)
+	return resolution, nil
+}
+
+// ReverseResolve resolves address (or address of public key) to its primary ENS name.
+// Name is verified to resolve back to address, so that reverse records couldn't claim others' names.
+func (s *Service) ReverseResolve(ctx context.Context, key hexutil.Bytes) (Resolution, error) {
+	address, err := toAddress(key)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	if resolution, ok := s.cached(s.byAddress, address.Hex()); ok {
+		return resolution, nil
+	}
+
+	client, registry, err := s.client()
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	node := NameHash(reverseName(address))
+	resolver, err := callAddress(ctx, client, registry, resolverSelector, node)
+	if err != nil {
+		return Resolution{}, err
+	}
+	if resolver == (gethcommon.Address{}) {
+		return Resolution{}, ErrReverseNotFound
+	}
+	result, err := call(ctx, client, resolver, nameSelector, node)
+	if err != nil {
+		return Resolution{}, err
+	}
+	name, err := decodeString(result)
+	if err != nil {
+		return Resolution{}, err
+	}
+	if name == "" {
+		return Resolution{}, ErrReverseNotFound
+	}
+
+	resolution, err := resolve(ctx, client, registry, Normalize(name))
+	if err != nil {
+		return Resolution{}, err
+	}
+	if resolution.Address != address {
+		return Resolution{}, ErrReverseMismatch
+	}
+
+	s.cache(resolution, true)
+	return resolution, nil
+}
+
+// RegisterSubdomain returns arguments of transaction, which registers username as subdomain of
+// Status domain with registrar (owner of the domain), pointing it to address and public key of account.
+func (s *Service) RegisterSubdomain(ctx context.Context, from gethcommon.Address, username string, publicKey hexutil.Bytes) (common.SendTxArgs, error) {
+	label := strings.TrimSuffix(Normalize(username), "."+StatusDomain)
+	if label == "" || strings.Contains(label, ".") {
+		return common.SendTxArgs{}, ErrInvalidUsername
+	}
+	key := crypto.ToECDSAPub(publicKey)
+	if key == nil || key.X == nil {
+		return common.SendTxArgs{}, ErrInvalidPublicKey
+	}
+
+	client, registry, err := s.client()
+	if err != nil {
+		return common.SendTxArgs{}, err
+	}
+	registrar, err := callAddress(ctx, client, registry, ownerSelector, NameHash(StatusDomain))
+	if err != nil {
+		return common.SendTxArgs{}, err
+	}
+	if registrar == (gethcommon.Address{}) {
+		return common.SendTxArgs{}, ErrNameNotFound
+	}
+
+	data := append([]byte{}, registerSelector...)
+	data = append(data, crypto.Keccak256([]byte(label))...)
+	data = append(data, gethcommon.LeftPadBytes(from.Bytes(), 32)...)
+	data = append(data, gethcommon.LeftPadBytes(key.X.Bytes(), 32)...)
+	data = append(data, gethcommon.LeftPadBytes(key.Y.Bytes(), 32)...)
+
+	return common.SendTxArgs{
+		From:  from,
+		To:    &registrar,
+		Value: (*hexutil.Big)(new(big.Int)),
+		Data:  data,
+	}, nil
+}
+
+// client returns RPC client and address of registry of the running node's network.
+func (s *Service) client() (*ethclient.Client, gethcommon.Address, error) {
+	config, err := s.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, gethcommon.Address{}, err
+	}
+	registry, ok := registries[config.NetworkID]
+	if !ok {
+		return nil, gethcommon.Address{}, ErrNoRegistry
+	}
+
+	return ethclient.NewClient(s.nodeManager.RPCClient()), registry, nil
+}
+
+// cached returns unexpired resolution of cache entries.
+func (s *Service) cached(entries map[string]cached, key string) (Resolution, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := entries[key]
+	if !ok || !s.now().Before(entry.expires) {
+		return Resolution{}, false
+	}
+	return entry.resolution, true
+}
+
+// cache stores resolution, and sends it as signal. Resolution is cached by address only if it's reverse,
+// as name resolving to address is not necessarily the primary name of address.
+func (s *Service) cache(resolution Resolution, reverse bool) {
+	s.mu.Lock()
+	entry := cached{resolution: resolution, expires: s.now().Add(cacheTTL)}
+	s.byName[resolution.Name] = entry
+	if reverse {
+		s.byAddress[resolution.Address.Hex()] = entry
+	}
+	s.mu.Unlock()
+
+	signal.Send(signal.Envelope{
+		Type:  EventENSResolved,
+		Event: resolution,
+	})
+}
+
+// resolve resolves normalized name with resolver, set in registry.
+func resolve(ctx context.Context, client *ethclient.Client, registry gethcommon.Address, name string) (Resolution, error) {
+	node := NameHash(name)
+	resolver, err := callAddress(ctx, client, registry, resolverSelector, node)
+	if err != nil {
+		return Resolution{}, err
+	}
+	if resolver == (gethcommon.Address{}) {
+		return Resolution{}, ErrNameNotFound
+	}
+
+	address, err := callAddress(ctx, client, resolver, addrSelector, node)
+	if err != nil {
+		return Resolution{}, err
+	}
+	if address == (gethcommon.Address{}) {
+		return Resolution{}, ErrNameNotFound
+	}
+	resolution := Resolution{Name: name, Address: address}
+
+	// public key is optional, resolvers without pubkey() fail the call or return nothing
+	result, err := call(ctx, client, resolver, pubkeySelector, node)
+	if err == nil && len(result) == 64 && new(big.Int).SetBytes(result).Sign() != 0 {
+		resolution.PublicKey = append([]byte{0x04}, result...)
+	}
+
+	return resolution, nil
+}
+
+// call calls function of contract, which takes node as its only argument.
+func call(ctx context.Context, client *ethclient.Client, contract gethcommon.Address, selector []byte, node gethcommon.Hash) ([]byte, error) {
+	return client.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: append(append([]byte{}, selector...), node.Bytes()...),
+	}, nil)
+}
+
+// callAddress calls function of contract, which returns address.
+func callAddress(ctx context.Context, client *ethclient.Client, contract gethcommon.Address, selector []byte, node gethcommon.Hash) (gethcommon.Address, error) {
+	result, err := call(ctx, client, contract, selector, node)
+	if err != nil {
+		return gethcommon.Address{}, err
+	}
+	// calls of accounts without code succeed with empty result
+	if len(result) == 0 {
+		return gethcommon.Address{}, nil
+	}
+	if len(result) != 32 {
+		return gethcommon.Address{}, ErrInvalidResponse
+	}
+
+	return gethcommon.BytesToAddress(result), nil
+}
+
+// decodeString decodes ABI-encoded string, returned by contract.
+func decodeString(result []byte) (string, error) {
+	if len(result) == 0 {
+		return "", nil
+	}
+	if len(result) < 64 {
+		return "", ErrInvalidResponse
+	}
+	offset := new(big.Int).SetBytes(result[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(result)-32) {
+		return "", ErrInvalidResponse
+	}
+	start := offset.Uint64() + 32
+	length := new(big.Int).SetBytes(result[start-32 : start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(result))-start {
+		return "", ErrInvalidResponse
+	}
+
+	return string(result[start : start+length.Uint64()]), nil
+}
+
+// toAddress returns address, given either as is, or as public key.
+func toAddress(key hexutil.Bytes) (gethcommon.Address, error) {
+	switch len(key) {
+	case gethcommon.AddressLength:
+		return gethcommon.BytesToAddress(key), nil
+	case 65:
+		publicKey := crypto.ToECDSAPub(key)
+		if publicKey == nil || publicKey.X == nil {
+			return gethcommon.Address{}, ErrInvalidPublicKey
+		}
+		return crypto.PubkeyToAddress(*publicKey), nil
+	default:
+		return gethcommon.Address{}, ErrInvalidKey
+	}
+}
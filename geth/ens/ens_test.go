@@ -0,0 +1,163 @@
+package ens
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethnode "github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameHash(t *testing.T) {
+	require.Equal(t, gethcommon.Hash{}, NameHash(""))
+	require.Equal(t, "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", NameHash("eth").Hex())
+	require.Equal(t, "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", NameHash("foo.eth").Hex())
+
+	require.Equal(t, "alice.stateofus.eth", Normalize(" Alice "))
+	require.Equal(t, "alice.eth", Normalize("Alice.eth"))
+}
+
+// abiString returns ABI encoding of string, returned by contract.
+func abiString(s string) hexutil.Bytes {
+	data := gethcommon.LeftPadBytes(big.NewInt(32).Bytes(), 32)
+	data = append(data, gethcommon.LeftPadBytes(big.NewInt(int64(len(s))).Bytes(), 32)...)
+	padded := make([]byte, (len(s)+31)/32*32)
+	copy(padded, s)
+	return append(data, padded...)
+}
+
+func TestService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ens")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	require.NoError(t, err)
+	require.NoError(t, stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+
+	registry := registries[params.RopstenNetworkID]
+	resolver := gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	registrar := gethcommon.HexToAddress("0x1000000000000000000000000000000000000002")
+	alice := NameHash("alice.stateofus.eth")
+	carol := NameHash("carol.stateofus.eth")
+	dave := NameHash("dave.stateofus.eth")
+	reverse := NameHash(reverseName(address))
+
+	// contract calls are answered by function selector, contract and node
+	type callKey struct {
+		to       gethcommon.Address
+		selector string
+		node     gethcommon.Hash
+	}
+	results := map[callKey]hexutil.Bytes{
+		{registry, string(resolverSelector), alice}:               resolver.Hash().Bytes(),
+		{registry, string(resolverSelector), reverse}:             resolver.Hash().Bytes(),
+		{registry, string(ownerSelector), NameHash(StatusDomain)}: registrar.Hash().Bytes(),
+		{resolver, string(addrSelector), alice}:                   address.Hash().Bytes(),
+		{resolver, string(pubkeySelector), alice}:                 publicKey[1:],
+		{resolver, string(nameSelector), reverse}:                 abiString("alice.stateofus.eth"),
+		{registry, string(resolverSelector), carol}:               resolver.Hash().Bytes(),
+		{registry, string(resolverSelector), dave}:                resolver.Hash().Bytes(),
+		{resolver, string(addrSelector), dave}:                    address.Hash().Bytes(),
+	}
+	calls := 0
+	rpcClient.RegisterHandler("eth_call", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		calls++
+		call := args[0].(map[string]interface{})
+		data := call["data"].(hexutil.Bytes)
+		result, ok := results[callKey{*call["to"].(*gethcommon.Address), string(data[:4]), gethcommon.BytesToHash(data[4:])}]
+		if !ok {
+			return hexutil.Bytes{}, nil
+		}
+		return result, nil
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config, err := params.NewNodeConfig(dir, params.RopstenNetworkID, true)
+	require.NoError(t, err)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	nodeManager.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+
+	service := New(nodeManager)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	resolution, err := service.ResolveName(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, Resolution{Name: "alice.stateofus.eth", Address: address, PublicKey: publicKey}, resolution)
+
+	_, err = service.ResolveName(context.Background(), "bob")
+	require.Equal(t, ErrNameNotFound, err)
+
+	// names without address are not resolved
+	_, err = service.ResolveName(context.Background(), "carol")
+	require.Equal(t, ErrNameNotFound, err)
+
+	// forward resolutions are not used in reverse, name resolving to address is not its primary name
+	resolution, err = service.ResolveName(context.Background(), "dave")
+	require.NoError(t, err)
+	require.Equal(t, address, resolution.Address)
+	resolution, err = service.ReverseResolve(context.Background(), publicKey)
+	require.NoError(t, err)
+	require.Equal(t, "alice.stateofus.eth", resolution.Name)
+
+	// resolutions are cached by names, and reverse ones by addresses
+	calls = 0
+	resolution, err = service.ResolveName(context.Background(), "Alice.stateofus.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, resolution.Address)
+	resolution, err = service.ReverseResolve(context.Background(), publicKey)
+	require.NoError(t, err)
+	require.Equal(t, "alice.stateofus.eth", resolution.Name)
+	require.Equal(t, 0, calls)
+
+	// expired resolutions are resolved again
+	now = now.Add(cacheTTL)
+	resolution, err = service.ReverseResolve(context.Background(), address.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "alice.stateofus.eth", resolution.Name)
+	require.NotZero(t, calls)
+
+	// reverse record must resolve back to address
+	other := gethcommon.HexToAddress("0x2000000000000000000000000000000000000001")
+	otherReverse := NameHash(reverseName(other))
+	results[callKey{registry, string(resolverSelector), otherReverse}] = resolver.Hash().Bytes()
+	results[callKey{resolver, string(nameSelector), otherReverse}] = abiString("alice.stateofus.eth")
+	_, err = service.ReverseResolve(context.Background(), other.Bytes())
+	require.Equal(t, ErrReverseMismatch, err)
+	_, err = service.ReverseResolve(context.Background(), hexutil.Bytes{0x01})
+	require.Equal(t, ErrInvalidKey, err)
+
+	args, err := service.RegisterSubdomain(context.Background(), address, "alice", publicKey)
+	require.NoError(t, err)
+	require.Equal(t, registrar, *args.To)
+	require.Equal(t, registerSelector, []byte(args.Data[:4]))
+	require.Equal(t, crypto.Keccak256([]byte("alice")), []byte(args.Data[4:36]))
+	require.Equal(t, address, gethcommon.BytesToAddress(args.Data[36:68]))
+	require.Equal(t, publicKey[1:], []byte(args.Data[68:]))
+
+	_, err = service.RegisterSubdomain(context.Background(), address, "alice.eth", publicKey)
+	require.Equal(t, ErrInvalidUsername, err)
+}
@@ -0,0 +1,38 @@
+package ens
+
+import (
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NameHash returns node of name, as defined by EIP-137: labels are hashed recursively,
+// starting from the top-level one. Name is expected to be normalized (see Normalize).
+func NameHash(name string) gethcommon.Hash {
+	var node gethcommon.Hash
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+
+	return node
+}
+
+// Normalize lowercases name and appends Status domain to bare usernames (names without dots).
+func Normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name != "" && !strings.Contains(name, ".") {
+		name += "." + StatusDomain
+	}
+	return name
+}
+
+// reverseName returns name, which reverse record of address is stored under.
+func reverseName(address gethcommon.Address) string {
+	return strings.ToLower(address.Hex()[2:]) + ".addr.reverse"
+}
@@ -0,0 +1,149 @@
+package explorer
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// Kinds of explorers
+const (
+	KindEtherscan  = "etherscan"
+	KindBlockscout = "blockscout"
+)
+
+// errors
+var (
+	ErrUnknownNetwork = errors.New("no block explorer is registered for the network")
+	ErrUnknownKind    = errors.New("unknown kind of block explorer")
+	ErrInvalidTxHash  = errors.New("invalid transaction hash")
+	ErrInvalidAddress = errors.New("invalid address")
+)
+
+// Paths are templates of paths (relative to base URL of explorer) of pages of transactions
+// and addresses, "{hash}" and "{address}" are substituted with hex-encoded values.
+type Paths struct {
+	Tx      string
+	Address string
+}
+
+// Explorer is a block explorer of network.
+type Explorer struct {
+	Kind string
+	URL  string // base URL, without trailing slash
+}
+
+// registry keeps paths of known kinds of explorers and explorers of known networks.
+var registry = struct {
+	sync.RWMutex
+	kinds    map[string]Paths
+	networks map[uint64]Explorer
+}{
+	kinds: map[string]Paths{
+		KindEtherscan:  {Tx: "/tx/{hash}", Address: "/address/{address}"},
+		KindBlockscout: {Tx: "/tx/{hash}", Address: "/address/{address}/transactions"},
+	},
+	networks: map[uint64]Explorer{
+		params.MainNetworkID:    {Kind: KindEtherscan, URL: "https://etherscan.io"},
+		params.RopstenNetworkID: {Kind: KindEtherscan, URL: "https://ropsten.etherscan.io"},
+		params.RinkebyNetworkID: {Kind: KindEtherscan, URL: "https://rinkeby.etherscan.io"},
+	},
+}
+
+// RegisterKind registers (or replaces) paths of a kind of explorer.
+func RegisterKind(kind string, paths Paths) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.kinds[kind] = paths
+}
+
+// RegisterNetwork registers (or replaces) explorer of a network, used, unless
+// params.ExplorerConfig defines another one.
+func RegisterNetwork(networkID uint64, explorer Explorer) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.networks[networkID] = explorer
+}
+
+// Resolver resolves links to pages of transactions and addresses on block explorer of the active network,
+// so that clients don't need to keep URL templates per network.
+type Resolver struct {
+	nodeManager common.NodeManager
+}
+
+// NewResolver returns new resolver.
+func NewResolver(nodeManager common.NodeManager) *Resolver {
+	return &Resolver{nodeManager: nodeManager}
+}
+
+// TxURL returns URL of page of transaction with a given hash.
+func (r *Resolver) TxURL(hash string) (string, error) {
+	if len(strings.TrimPrefix(hash, "0x")) != 2*gethcommon.HashLength || !isHex(hash) {
+		return "", ErrInvalidTxHash
+	}
+
+	explorer, paths, err := r.explorer()
+	if err != nil {
+		return "", err
+	}
+
+	return explorer.URL + strings.Replace(paths.Tx, "{hash}", gethcommon.HexToHash(hash).Hex(), -1), nil
+}
+
+// AddressURL returns URL of page of a given address (of account or contract).
+func (r *Resolver) AddressURL(address string) (string, error) {
+	if !gethcommon.IsHexAddress(address) {
+		return "", ErrInvalidAddress
+	}
+
+	explorer, paths, err := r.explorer()
+	if err != nil {
+		return "", err
+	}
+
+	return explorer.URL + strings.Replace(paths.Address, "{address}", gethcommon.HexToAddress(address).Hex(), -1), nil
+}
+
+// explorer selects explorer of the active network: the configured one, or the one registered for network.
+func (r *Resolver) explorer() (Explorer, Paths, error) {
+	config, err := r.nodeManager.NodeConfig()
+	if err != nil {
+		return Explorer{}, Paths{}, err
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	explorer, ok := registry.networks[config.NetworkID]
+	if c := config.ExplorerConfig; c != nil && c.URL != "" {
+		explorer = Explorer{Kind: c.Kind, URL: c.URL}
+		ok = true
+	}
+	if !ok {
+		return Explorer{}, Paths{}, ErrUnknownNetwork
+	}
+	if explorer.Kind == "" {
+		explorer.Kind = KindEtherscan
+	}
+
+	paths, ok := registry.kinds[explorer.Kind]
+	if !ok {
+		return Explorer{}, Paths{}, ErrUnknownKind
+	}
+	explorer.URL = strings.TrimSuffix(explorer.URL, "/")
+
+	return explorer, paths, nil
+}
+
+func isHex(s string) bool {
+	for _, c := range strings.TrimPrefix(s, "0x") {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package explorer
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHash    = "0x5e9ac5a9f8d2a1f4c36a3a4e6f5f3b0a9c3a5b4e0f7c1d2e3f4a5b6c7d8e9f00"
+	testAddress = "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"
+)
+
+func TestResolver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := &params.NodeConfig{NetworkID: params.RopstenNetworkID, ExplorerConfig: &params.ExplorerConfig{}}
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	resolver := NewResolver(nodeManager)
+
+	url, err := resolver.TxURL(testHash)
+	require.NoError(t, err)
+	require.Equal(t, "https://ropsten.etherscan.io/tx/"+testHash, url)
+
+	url, err = resolver.AddressURL(testAddress)
+	require.NoError(t, err)
+	require.Equal(t, "https://ropsten.etherscan.io/address/0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", url)
+
+	_, err = resolver.TxURL("0x1234")
+	require.Equal(t, ErrInvalidTxHash, err)
+	_, err = resolver.AddressURL("0xzz")
+	require.Equal(t, ErrInvalidAddress, err)
+
+	// custom chains have no explorer, unless it is configured
+	config.NetworkID = 777
+	_, err = resolver.TxURL(testHash)
+	require.Equal(t, ErrUnknownNetwork, err)
+
+	config.ExplorerConfig = &params.ExplorerConfig{Kind: KindBlockscout, URL: "https://blockscout.example/"}
+	url, err = resolver.AddressURL(testAddress)
+	require.NoError(t, err)
+	require.Equal(t, "https://blockscout.example/address/0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359/transactions", url)
+
+	config.ExplorerConfig.Kind = "unknown"
+	_, err = resolver.TxURL(testHash)
+	require.Equal(t, ErrUnknownKind, err)
+
+	RegisterKind("unknown", Paths{Tx: "/transaction/{hash}"})
+	url, err = resolver.TxURL(testHash)
+	require.NoError(t, err)
+	require.Equal(t, "https://blockscout.example/transaction/"+testHash, url)
+}
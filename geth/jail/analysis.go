@@ -0,0 +1,291 @@
+package jail
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja/parser"
+	"github.com/dop251/goja/token"
+)
+
+// bundleFilename is a name of script with dapp's bundle, errors and diagnostics are reported relative to
+const bundleFilename = "bundle.js"
+
+// Diagnostic severities
+const (
+	SeverityError   = "error"   // bundle is rejected
+	SeverityWarning = "warning" // bundle is flagged, but still executed
+)
+
+// Analysis rules
+const (
+	RuleBundleSize    = "bundle-size"
+	RuleBannedGlobal  = "banned-global"
+	RuleFlaggedGlobal = "flagged-global"
+	RuleInfiniteLoop  = "infinite-loop"
+)
+
+// Diagnostic describes an issue found by static analysis of a bundle.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+}
+
+// AnalysisConfig defines what is checked by static analysis of bundles, before they are parsed.
+type AnalysisConfig struct {
+	MaxBundleSize  int      // bundles of larger size (in bytes) are rejected, 0 disables the check
+	BannedGlobals  []string // references to these globals are rejected
+	FlaggedGlobals []string // references to these globals are reported as warnings
+}
+
+// DefaultAnalysisConfig is used by jail, unless another one is set.
+// Dynamically evaluated code is banned, as it can't be analyzed.
+var DefaultAnalysisConfig = AnalysisConfig{
+	MaxBundleSize: 5 * 1024 * 1024,
+	BannedGlobals: []string{"eval", "Function"},
+}
+
+// analyzeBundle runs a lightweight static analysis of JavaScript bundle. It is parsed as ES6
+// (goja's parser accepts ES5 as well), so that bundles of every engine are analyzed.
+// Bundle with syntax errors is not analyzed, as those are reported once it is run.
+func analyzeBundle(js string, config AnalysisConfig) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	if config.MaxBundleSize > 0 && len(js) > config.MaxBundleSize {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityError,
+			Rule:     RuleBundleSize,
+			Message:  fmt.Sprintf("bundle size %d exceeds limit of %d bytes", len(js), config.MaxBundleSize),
+		})
+		// don't waste time parsing oversized bundle
+		return diagnostics
+	}
+
+	program, err := parser.ParseFile(nil, bundleFilename, js, 0)
+	if err != nil {
+		return diagnostics
+	}
+
+	a := &analyzer{
+		file:     program.File,
+		severity: make(map[string]string),
+	}
+	for _, name := range config.FlaggedGlobals {
+		a.severity[name] = SeverityWarning
+	}
+	for _, name := range config.BannedGlobals {
+		a.severity[name] = SeverityError
+	}
+
+	walk(program, a.visit)
+
+	return append(diagnostics, a.diagnostics...)
+}
+
+// hasErrors returns true, if bundle should be rejected.
+func hasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// analyzer collects diagnostics, while walking syntax tree of a bundle.
+type analyzer struct {
+	file        *file.File
+	severity    map[string]string // global name -> severity of referencing it
+	diagnostics []Diagnostic
+}
+
+func (a *analyzer) report(severity, rule, message string, idx file.Idx) {
+	d := Diagnostic{
+		Severity: severity,
+		Rule:     rule,
+		Message:  message,
+	}
+	if idx > 0 {
+		p := a.file.Position(int(idx) - a.file.Base())
+		d.Line, d.Column = p.Line, p.Column
+	}
+
+	a.diagnostics = append(a.diagnostics, d)
+}
+
+// visit checks a single node, and returns false, if its children are walked by itself
+// (to skip identifiers, which are not references to variables, e.g. property or parameter names).
+func (a *analyzer) visit(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Identifier:
+		a.checkReference(n)
+	case *ast.PropertyShort:
+		// shorthand property (e.g. {eval}) references variable of the same name
+		a.checkReference(&n.Name)
+		walk(n.Initializer, a.visit)
+		return false
+	case *ast.DotExpression:
+		walk(n.Left, a.visit)
+		return false
+	case *ast.FunctionLiteral:
+		walk(n.Body, a.visit)
+		return false
+	case *ast.ArrowFunctionLiteral:
+		walk(n.Body, a.visit)
+		return false
+	case *ast.LabelledStatement:
+		walk(n.Statement, a.visit)
+		return false
+	case *ast.CatchStatement:
+		walk(n.Body, a.visit)
+		return false
+	case *ast.BranchStatement:
+		return false
+	case *ast.WhileStatement:
+		a.checkLoop(n.Test, n.Body, n.While)
+	case *ast.DoWhileStatement:
+		a.checkLoop(n.Test, n.Body, n.Do)
+	case *ast.ForStatement:
+		a.checkLoop(n.Test, n.Body, n.For)
+	}
+
+	return true
+}
+
+// checkReference reports reference to banned or flagged global.
+func (a *analyzer) checkReference(n *ast.Identifier) {
+	name := n.Name.String()
+	if severity, ok := a.severity[name]; ok {
+		rule := RuleBannedGlobal
+		if severity == SeverityWarning {
+			rule = RuleFlaggedGlobal
+		}
+		a.report(severity, rule, fmt.Sprintf("reference to '%s'", name), n.Idx)
+	}
+}
+
+// checkLoop reports loop with a constant true condition and no way to leave its body.
+func (a *analyzer) checkLoop(test ast.Expression, body ast.Statement, idx file.Idx) {
+	if !isAlwaysTrue(test) || canExit(body) {
+		return
+	}
+
+	// position of loop keyword may be missing
+	if idx == 0 && test != nil {
+		idx = test.Idx0()
+	}
+	if idx == 0 && body != nil {
+		idx = body.Idx0()
+	}
+
+	a.report(SeverityError, RuleInfiniteLoop, "loop never exits: condition is always true and body has no break, return or throw", idx)
+}
+
+// isAlwaysTrue returns true for missing condition (as in `for (;;)`) and truthy literals.
+func isAlwaysTrue(test ast.Expression) bool {
+	switch t := test.(type) {
+	case nil:
+		return true
+	case *ast.BooleanLiteral:
+		return t.Value
+	case *ast.NumberLiteral:
+		switch v := t.Value.(type) {
+		case int64:
+			return v != 0
+		case float64:
+			return v != 0
+		}
+	}
+
+	return false
+}
+
+// canExit returns true, if loop body contains statement which leaves the loop.
+// Unlabeled breaks of nested loops and switches, as well as nested functions, are not counted.
+func canExit(body ast.Statement) bool {
+	exits := false
+
+	var visit func(depth int) func(ast.Node) bool
+	visit = func(depth int) func(ast.Node) bool {
+		return func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.ReturnStatement, *ast.ThrowStatement:
+				exits = true
+			case *ast.BranchStatement:
+				if n.Token == token.BREAK && (n.Label != nil || depth == 0) {
+					exits = true
+				}
+			case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral, *ast.ClassLiteral:
+				return false
+			case *ast.WhileStatement:
+				walk(n.Body, visit(depth+1))
+				return false
+			case *ast.DoWhileStatement:
+				walk(n.Body, visit(depth+1))
+				return false
+			case *ast.ForStatement:
+				walk(n.Body, visit(depth+1))
+				return false
+			case *ast.ForInStatement:
+				walk(n.Body, visit(depth+1))
+				return false
+			case *ast.ForOfStatement:
+				walk(n.Body, visit(depth+1))
+				return false
+			case *ast.SwitchStatement:
+				for _, c := range n.Body {
+					walk(c, visit(depth+1))
+				}
+				return false
+			}
+
+			return !exits
+		}
+	}
+	walk(body, visit(0))
+
+	return exits
+}
+
+// walk calls visit for every node of the syntax tree in depth-first order.
+// Children of the node are skipped, if visit returns false.
+func walk(node ast.Node, visit func(ast.Node) bool) {
+	walkValue(reflect.ValueOf(node), visit)
+}
+
+var nodeType = reflect.TypeOf((*ast.Node)(nil)).Elem()
+
+func walkValue(v reflect.Value, visit func(ast.Node) bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			walkValue(v.Elem(), visit)
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if v.Type().Implements(nodeType) && !visit(v.Interface().(ast.Node)) {
+			return
+		}
+		walkValue(v.Elem(), visit)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkValue(v.Index(i), visit)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			switch v.Type().Field(i).Name {
+			case "DeclarationList", "File", "Comments": // duplicate nodes from body, or not nodes at all
+				continue
+			}
+			walkValue(v.Field(i), visit)
+		}
+	}
+}
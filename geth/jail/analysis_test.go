@@ -0,0 +1,78 @@
+package jail
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeBundle(t *testing.T) {
+	config := AnalysisConfig{
+		MaxBundleSize:  1024,
+		BannedGlobals:  []string{"eval", "Function"},
+		FlaggedGlobals: []string{"setInterval"},
+	}
+
+	testCases := []struct {
+		name  string
+		js    string
+		rules []string
+	}{
+		{"clean", `function call(x) { while (x > 0) { x--; } return obj.eval; }`, nil},
+		{"banned eval", "var x = 1;\neval('x');", []string{RuleBannedGlobal}},
+		{"banned Function", `var f = new Function('return 1');`, []string{RuleBannedGlobal}},
+		{"flagged", `setInterval(function() {}, 10);`, []string{RuleFlaggedGlobal}},
+		{"property named as banned", `var o = {eval: 1}; o.eval = function(Function) { return 1; };`, nil},
+		{"while true", `while (true) { x++; }`, []string{RuleInfiniteLoop}},
+		{"for ever", `for (;;) { for (;;) { break; } }`, []string{RuleInfiniteLoop}},
+		{"do while 1", `do { x++; } while (1);`, []string{RuleInfiniteLoop}},
+		{"nested function return", `while (true) { var f = function() { return 1; }; }`, []string{RuleInfiniteLoop}},
+		{"while true with break", `while (true) { if (x++ > 10) break; }`, nil},
+		{"while true with return", `function f() { for (;;) { return 1; } }`, nil},
+		{"labeled break", `outer: while (true) { for (;;) { break outer; } }`, nil},
+		{"switch break", `while (true) { switch (x) { case 1: break; } }`, []string{RuleInfiniteLoop}},
+		{"oversized", strings.Repeat(" ", 1025), []string{RuleBundleSize}},
+		{"syntax error", `var = ;`, nil},
+		{"es6 banned", "const f = () => eval('x');\nlet o = {Function};", []string{RuleBannedGlobal, RuleBannedGlobal}},
+		{"es6 clean", "class A { eval() { return `${this.x}`; } }\nconst {eval: e} = obj;", nil},
+		{"es6 for of", `while (true) { for (const x of xs) { break; } }`, []string{RuleInfiniteLoop}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var rules []string
+			for _, d := range analyzeBundle(tc.js, config) {
+				rules = append(rules, d.Rule)
+			}
+			require.Equal(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestParseRejectedBundle(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	var jsonErr JSONError
+	res := jail.Parse("chat", "var _status_catalog = {};\nwhile (true) {}")
+	require.NoError(t, json.Unmarshal([]byte(res), &jsonErr))
+	require.True(t, strings.HasPrefix(jsonErr.Error, ErrBundleRejected.Error()))
+	require.Equal(t, bundleFilename, jsonErr.File)
+	require.Equal(t, 2, jsonErr.Line)
+	require.Equal(t, []Diagnostic{{
+		Severity: SeverityError,
+		Rule:     RuleInfiniteLoop,
+		Message:  jsonErr.Diagnostics[0].Message,
+		Line:     2,
+		Column:   jsonErr.Column,
+	}}, jsonErr.Diagnostics)
+
+	// rejected bundle doesn't create a cell
+	_, err := jail.Cell("chat")
+	require.Error(t, err)
+
+	jail.SetAnalysisConfig(AnalysisConfig{})
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", "var _status_catalog = {};\neval('1');"))
+}
@@ -0,0 +1,88 @@
+package jail
+
+import (
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// Reasons of termination of cells
+const (
+	KillReasonCPU    = "cpu"
+	KillReasonMemory = "memory"
+)
+
+// CellKilledEvent is a signal sent, when cell is terminated for exceeding its budget
+// (see params.JailConfig.CellCPUBudget and params.JailConfig.CellHeapBudget).
+type CellKilledEvent struct {
+	ChatID     string `json:"chat_id"`
+	Reason     string `json:"reason"`      // KillReasonCPU or KillReasonMemory
+	CPUTime    int64  `json:"cpu_time"`    // milliseconds
+	HeapGrowth int64  `json:"heap_growth"` // bytes
+}
+
+// enforceBudget terminates cell, once resources consumed by its JavaScript code exceed configured budget.
+// Usage is accounted since cell has been created, so it starts over, when cell is restored or recycled.
+func (jail *Jail) enforceBudget(cell *Cell, config params.JailConfig) {
+	cpuBudget := time.Duration(config.CellCPUBudget) * time.Millisecond
+	heapBudget := int64(config.CellHeapBudget) * 1024 * 1024
+	if cpuBudget <= 0 && heapBudget <= 0 {
+		return
+	}
+
+	cell.MeasureHeap(heapBudget > 0)
+
+	var once sync.Once
+	cell.SetUsageHandler(func(usage vm.Usage) {
+		var reason string
+		switch {
+		case cpuBudget > 0 && usage.CPUTime > cpuBudget:
+			reason = KillReasonCPU
+		case heapBudget > 0 && usage.HeapGrowth > heapBudget:
+			reason = KillReasonMemory
+		default:
+			return
+		}
+
+		// handler is called with VM's lock held, which cell's removal waits for
+		once.Do(func() { go jail.killCell(cell, reason, usage) })
+	})
+}
+
+// killCell stops and removes cell, which has exceeded its budget, unless it has been removed already.
+// Entries cell has logged are kept for inspection.
+func (jail *Jail) killCell(cell *Cell, reason string, usage vm.Usage) {
+	jail.restoreMx.Lock()
+	defer jail.restoreMx.Unlock()
+
+	jail.cellsMx.Lock()
+	current, ok := jail.cells[cell.id]
+	if ok && current == cell {
+		delete(jail.cells, cell.id)
+	}
+	jail.cellsMx.Unlock()
+
+	if !ok || current != cell {
+		return
+	}
+
+	cell.Interrupt()
+	cell.Stop()
+	jail.removeCallQueue(cell.id)
+
+	log.Warn("Jail cell has exceeded its budget and is killed", "chatID", cell.id, "reason", reason,
+		"cpuTime", usage.CPUTime, "heapGrowth", usage.HeapGrowth)
+	signal.Send(signal.Envelope{
+		Type: EventJailCellKilled,
+		Event: CellKilledEvent{
+			ChatID:     cell.id,
+			Reason:     reason,
+			CPUTime:    int64(usage.CPUTime / time.Millisecond),
+			HeapGrowth: usage.HeapGrowth,
+		},
+	})
+}
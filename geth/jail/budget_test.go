@@ -0,0 +1,83 @@
+package jail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellBudget(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config params.JailConfig
+		js     string // run repeatedly, until cell is killed
+		reason string
+	}{
+		{
+			name:   "cpu",
+			config: params.JailConfig{CellCPUBudget: 100},
+			// CPU time is accounted for timer callbacks as well
+			js: `setTimeout(function() {
+				var start = Date.now();
+				while (Date.now() - start < 20) {}
+			}, 1)`,
+			reason: KillReasonCPU,
+		},
+		{
+			name:   "memory",
+			config: params.JailConfig{CellHeapBudget: 1},
+			js: `var kept = kept || [];
+			for (var i = 0; i < 10000; i++) { kept.push({i: i, s: 'item ' + i}); }`,
+			reason: KillReasonMemory,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			config := tc.config
+			nodeManager := common.NewMockNodeManager(ctrl)
+			nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{JailConfig: &config}, nil).AnyTimes()
+
+			killed := make(chan string, 1)
+			signal.SetDefaultNodeNotificationHandler(func(event string) {
+				if strings.Contains(event, EventJailCellKilled) {
+					killed <- event
+				}
+			})
+			defer signal.ResetDefaultNodeNotificationHandler()
+
+			jail := New(nodeManager)
+			defer jail.Stop()
+
+			cell, err := jail.NewCell("chat")
+			require.NoError(t, err)
+
+			var event string
+			timeout := time.After(5 * time.Second)
+			for event == "" {
+				select {
+				case event = <-killed:
+				case <-timeout:
+					require.FailNow(t, "cell has not been killed")
+				default:
+					cell.Run(tc.js) // nolint: errcheck
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+
+			require.Contains(t, event, `"chat_id":"chat","reason":"`+tc.reason+`"`)
+			require.Empty(t, jail.Cells())
+			_, err = jail.Cell("chat")
+			require.EqualError(t, err, "cell[chat] doesn't exist")
+		})
+	}
+}
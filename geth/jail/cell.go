@@ -2,11 +2,15 @@ package jail
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/robertkrimen/otto"
+	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/jail/internal/fetch"
 	"github.com/status-im/status-go/geth/jail/internal/loop"
 	"github.com/status-im/status-go/geth/jail/internal/loop/looptask"
+	"github.com/status-im/status-go/geth/jail/internal/promise"
 	"github.com/status-im/status-go/geth/jail/internal/timers"
 	"github.com/status-im/status-go/geth/jail/internal/vm"
 )
@@ -15,58 +19,110 @@ import (
 type Cell struct {
 	*vm.VM
 	id     string
+	ctx    context.Context // done, once cell is stopped
 	cancel context.CancelFunc
 	lo     *loop.Loop
+
+	limiterOnce sync.Once
+	limiter     *rateLimiter // nil, if RPC requests of cell are not limited
+
+	fetchMx      sync.RWMutex
+	fetchOptions fetch.Options // limits of requests cell sends with fetch()
+
+	callsMx sync.RWMutex // held for reading by calls in flight, and for writing, while cell is recycled
+
+	used         uint64    // value of jail's usage counter, when cell was accessed the last time
+	calls        uint64    // number of calls handled since cell has been created, to find one to recycle
+	bundle       string    // JavaScript code cell has been parsed with, to restore it after eviction
+	parseVersion uint64    // version of base JavaScript cell has been parsed with (see Jail.ParseVersion)
+	createdAt    time.Time // when cell has been created (or restored)
+
+	modules map[string]vm.Value // shared modules loaded with require(), guarded by lock of VM
 }
 
 // newCell encapsulates what we need to create a new jailCell from the
-// provided vm and eventloop instance.
-func newCell(id string, ottoVM *otto.Otto) (*Cell, error) {
-	cellVM := vm.New(ottoVM)
+// provided engine and eventloop instance.
+func newCell(id string, engine vm.Engine) (*Cell, error) {
+	cellVM := vm.New(engine)
 
 	lo := loop.New(cellVM)
 
-	registerVMHandlers(cellVM, lo)
+	// requests of cell are tagged with its id (e.g. transactions it sends)
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), common.OriginKey, id))
 
-	ctx, cancel := context.WithCancel(context.Background())
+	cell := &Cell{
+		VM:        cellVM,
+		id:        id,
+		ctx:       ctx,
+		cancel:    cancel,
+		lo:        lo,
+		createdAt: time.Now(),
+		modules:   make(map[string]vm.Value),
+	}
+
+	registerVMHandlers(cellVM, lo, cell.getFetchOptions)
 
 	// start event loop in background
 	go lo.Run(ctx)
 
-	return &Cell{
-		VM:     cellVM,
-		id:     id,
-		cancel: cancel,
-		lo:     lo,
-	}, nil
+	return cell, nil
 }
 
 // registerHandlers register variuous functions and handlers
-// to the Otto VM, such as Fetch API callbacks or promises.
-func registerVMHandlers(v *vm.VM, lo *loop.Loop) error {
+// to the VM, such as Fetch API callbacks or promises.
+func registerVMHandlers(v *vm.VM, lo *loop.Loop, fetchOptions func() fetch.Options) error {
 	// setTimeout/setInterval functions
 	if err := timers.Define(v, lo); err != nil {
 		return err
 	}
 
+	// Promise, settled with microtasks
+	if err := promise.Define(v, lo); err != nil {
+		return err
+	}
+
 	// FetchAPI functions
-	if err := fetch.Define(v, lo); err != nil {
+	if err := fetch.DefineWithOptions(v, lo, nil, fetchOptions); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// lastUsed returns value of jail's usage counter, when cell was accessed the last time.
+func (c *Cell) lastUsed() uint64 {
+	return atomic.LoadUint64(&c.used)
+}
+
+// setFetchOptions changes limits of requests, cell sends with fetch().
+func (c *Cell) setFetchOptions(options fetch.Options) {
+	c.fetchMx.Lock()
+	c.fetchOptions = options
+	c.fetchMx.Unlock()
+}
+
+func (c *Cell) getFetchOptions() fetch.Options {
+	c.fetchMx.RLock()
+	defer c.fetchMx.RUnlock()
+
+	return c.fetchOptions
+}
+
 // Stop halts event loop associated with cell.
 func (c *Cell) Stop() {
 	c.cancel()
 }
 
-// CallAsync puts otto's function with given args into
+// stopped checks whether cell has been stopped (e.g. evicted or recycled).
+func (c *Cell) stopped() bool {
+	return c.ctx.Err() != nil
+}
+
+// CallAsync puts JavaScript function with given args into
 // event queue loop and schedules for immediate execution.
 // Intended to be used by any cell user that want's to run
 // async call, like callback.
-func (c *Cell) CallAsync(fn otto.Value, args ...interface{}) {
+func (c *Cell) CallAsync(fn vm.Value, args ...interface{}) {
 	task := looptask.NewCallTask(fn, args...)
 	c.lo.Add(task)
 	// TODO(divan): review API of `loop` package, it's contrintuitive
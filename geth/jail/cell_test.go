@@ -3,10 +3,15 @@ package jail_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
+	"testing"
 	"time"
 
-	"github.com/robertkrimen/otto"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/jail"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/params"
 	"github.com/status-im/status-go/static"
 	"github.com/stretchr/testify/suite"
 )
@@ -21,14 +26,40 @@ var (
 
 type CellTestSuite struct {
 	suite.Suite
-	jail *jail.Jail
+	engine string // JavaScript engine of cells, default one if empty
+	ctrl   *gomock.Controller
+	jail   *jail.Jail
+}
+
+func TestCellTestSuite(t *testing.T) {
+	suite.Run(t, new(CellTestSuite))
+}
+
+func TestCellTestSuiteGoja(t *testing.T) {
+	suite.Run(t, &CellTestSuite{engine: params.JailEngineGoja})
 }
 
 func (s *CellTestSuite) SetupTest() {
-	s.jail = jail.New(nil)
+	if s.engine == "" {
+		s.jail = jail.New(nil)
+	} else {
+		s.ctrl = gomock.NewController(s.T())
+		nodeManager := common.NewMockNodeManager(s.ctrl)
+		nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+			JailConfig: &params.JailConfig{Engine: s.engine},
+		}, nil).AnyTimes()
+		s.jail = jail.New(nodeManager)
+	}
 	s.NotNil(s.jail)
 }
 
+func (s *CellTestSuite) TearDownTest() {
+	s.jail.Stop()
+	if s.ctrl != nil {
+		s.ctrl.Finish()
+	}
+}
+
 func (s *CellTestSuite) TestJailTimeout() {
 	require := s.Require()
 
@@ -74,9 +105,8 @@ func (s *CellTestSuite) TestJailLoopInCall() {
 
 	items := make(chan string)
 
-	err = cell.Set("__captureResponse", func(val string) otto.Value {
+	err = cell.Set("__captureResponse", func(val string) {
 		go func() { items <- val }()
-		return otto.UndefinedValue()
 	})
 	require.NoError(err)
 
@@ -113,9 +143,8 @@ func (s *CellTestSuite) TestJailLoopRace() {
 
 	items := make(chan struct{})
 
-	err = cell.Set("__captureResponse", func() otto.Value {
+	err = cell.Set("__captureResponse", func() {
 		go func() { items <- struct{}{} }()
-		return otto.UndefinedValue()
 	})
 	require.NoError(err)
 
@@ -157,12 +186,18 @@ func (s *CellTestSuite) TestJailFetchPromise() {
 	require.NotNil(cell)
 	defer cell.Stop()
 
-	dataCh := make(chan otto.Value, 1)
-	errCh := make(chan otto.Value, 1)
+	dataCh := make(chan vm.Value, 1)
+	errCh := make(chan vm.Value, 1)
 
-	err = cell.Set("__captureSuccess", func(res otto.Value) { dataCh <- res })
+	err = cell.Set("__captureSuccess", func(call vm.FunctionCall) vm.Value {
+		dataCh <- call.Argument(0)
+		return nil
+	})
 	require.NoError(err)
-	err = cell.Set("__captureError", func(res otto.Value) { errCh <- res })
+	err = cell.Set("__captureError", func(call vm.FunctionCall) vm.Value {
+		errCh <- call.Argument(0)
+		return nil
+	})
 	require.NoError(err)
 
 	// run JS code for fetching valid URL
@@ -186,6 +221,49 @@ func (s *CellTestSuite) TestJailFetchPromise() {
 	}
 }
 
+func (s *CellTestSuite) TestJailFetchWhitelist() {
+	require := s.Require()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello")) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	s.jail.SetFetchWhitelist(testChatID, []string{"https://api.example.com"})
+	defer s.jail.SetFetchWhitelist(testChatID, nil)
+
+	cell, err := s.jail.NewCell(testChatID)
+	require.NoError(err)
+	defer cell.Stop()
+
+	resultCh := make(chan string, 1)
+	err = cell.Set("__capture", func(res string) { resultCh <- res })
+	require.NoError(err)
+
+	fetch := func() string {
+		_, err := cell.Run(`fetch('` + server.URL + `').then(function(r) {
+			return r.text()
+		}).then(__capture, function(e) {
+			__capture(e.message)
+		})`)
+		require.NoError(err)
+
+		select {
+		case res := <-resultCh:
+			return res
+		case <-time.After(1 * time.Second):
+			require.Fail("test timed out")
+			return ""
+		}
+	}
+
+	require.Equal("origin is not whitelisted: "+server.URL, fetch())
+
+	// whitelist of live cell is changed
+	s.jail.SetFetchWhitelist(testChatID, []string{server.URL})
+	require.Equal("hello", fetch())
+}
+
 func (s *CellTestSuite) TestJailFetchCatch() {
 	require := s.Require()
 
@@ -194,12 +272,18 @@ func (s *CellTestSuite) TestJailFetchCatch() {
 	require.NotNil(cell)
 	defer cell.Stop()
 
-	dataCh := make(chan otto.Value, 1)
-	errCh := make(chan otto.Value, 1)
+	dataCh := make(chan vm.Value, 1)
+	errCh := make(chan vm.Value, 1)
 
-	err = cell.Set("__captureSuccess", func(res otto.Value) { dataCh <- res })
+	err = cell.Set("__captureSuccess", func(call vm.FunctionCall) vm.Value {
+		dataCh <- call.Argument(0)
+		return nil
+	})
 	require.NoError(err)
-	err = cell.Set("__captureError", func(res otto.Value) { errCh <- res })
+	err = cell.Set("__captureError", func(call vm.FunctionCall) vm.Value {
+		errCh <- call.Argument(0)
+		return nil
+	})
 	require.NoError(err)
 
 	// run JS code for fetching invalid URL
@@ -217,10 +301,10 @@ func (s *CellTestSuite) TestJailFetchCatch() {
 		require.Fail("request should have failed, but returned", data)
 	case e := <-errCh:
 		require.True(e.IsObject())
-		name, err := e.Object().Get("name")
+		name, err := e.Get("name")
 		require.NoError(err)
 		require.Equal("Error", name.String())
-		_, err = e.Object().Get("message")
+		_, err = e.Get("message")
 		require.NoError(err)
 	case <-time.After(1 * time.Second):
 		require.Fail("test timed out")
@@ -243,12 +327,18 @@ func (s *CellTestSuite) TestJailFetchRace() {
 	require.NotNil(cell)
 	defer cell.Stop()
 
-	dataCh := make(chan otto.Value, 1)
-	errCh := make(chan otto.Value, 1)
+	dataCh := make(chan vm.Value, 1)
+	errCh := make(chan vm.Value, 1)
 
-	err = cell.Set("__captureSuccess", func(res otto.Value) { dataCh <- res })
+	err = cell.Set("__captureSuccess", func(call vm.FunctionCall) vm.Value {
+		dataCh <- call.Argument(0)
+		return nil
+	})
 	require.NoError(err)
-	err = cell.Set("__captureError", func(res otto.Value) { errCh <- res })
+	err = cell.Set("__captureError", func(call vm.FunctionCall) vm.Value {
+		errCh <- call.Argument(0)
+		return nil
+	})
 	require.NoError(err)
 
 	// run JS code for fetching valid URL
@@ -278,10 +368,10 @@ func (s *CellTestSuite) TestJailFetchRace() {
 			require.Equal(body, data.String())
 		case e := <-errCh:
 			require.True(e.IsObject())
-			name, err := e.Object().Get("name")
+			name, err := e.Get("name")
 			require.NoError(err)
 			require.Equal("Error", name.String())
-			_, err = e.Object().Get("message")
+			_, err = e.Get("message")
 			require.NoError(err)
 		case <-time.After(1 * time.Second):
 			require.Fail("test timed out")
@@ -303,10 +393,9 @@ func (s *CellTestSuite) TestJailLoopCancel() {
 	require.NoError(err)
 	require.NotNil(cell)
 
-	var count int
-	err = cell.Set("__captureResponse", func(val string) otto.Value {
-		count++
-		return otto.UndefinedValue()
+	var count int32
+	err = cell.Set("__captureResponse", func(val string) {
+		atomic.AddInt32(&count, 1)
 	})
 	require.NoError(err)
 
@@ -331,12 +420,78 @@ func (s *CellTestSuite) TestJailLoopCancel() {
 	cell.Stop()
 
 	// check that only 1 task has increased counter
-	require.Equal(1, count)
+	require.Equal(int32(1), atomic.LoadInt32(&count))
 
 	// wait 2 seconds more (so at least two more tasks would
 	// have been executed if event loop is still running)
 	<-time.After(2 * time.Second)
 
 	// check that counter hasn't increased
-	require.Equal(1, count)
+	require.Equal(int32(1), atomic.LoadInt32(&count))
+}
+
+// TestJailMicrotasks tests that callbacks of promises are called as microtasks:
+// once the current code completes, before any timer.
+func (s *CellTestSuite) TestJailMicrotasks() {
+	require := s.Require()
+
+	cell, err := s.jail.NewCell(testChatID)
+	require.NoError(err)
+	require.NotNil(cell)
+	defer cell.Stop()
+
+	_, err = cell.Run(`
+		var order = [];
+		setTimeout(function() { order.push('timeout'); }, 0);
+		Promise.resolve(1).then(function(v) {
+			order.push('then ' + v);
+			return v + 1;
+		}).then(function(v) {
+			order.push('then ' + v);
+		});
+		queueMicrotask(function() { order.push('microtask'); });
+		order.push('sync');
+	`)
+	require.NoError(err)
+
+	value, err := cell.Run(`order.join(', ')`)
+	require.NoError(err)
+	require.Equal("sync, then 1, microtask, then 2", value.String())
+
+	time.Sleep(100 * time.Millisecond)
+
+	value, err = cell.Run(`order.join(', ')`)
+	require.NoError(err)
+	require.Equal("sync, then 1, microtask, then 2, timeout", value.String())
+}
+
+// TestJailLoopTaskFailure tests that exception thrown by a timer callback
+// doesn't cancel other timers of the cell.
+func (s *CellTestSuite) TestJailLoopTaskFailure() {
+	require := s.Require()
+
+	cell, err := s.jail.NewCell(testChatID)
+	require.NoError(err)
+	require.NotNil(cell)
+	defer cell.Stop()
+
+	_, err = cell.Run(`
+		var ticks = 0, done = false;
+		var iv = setInterval(function() {
+			ticks++;
+			throw new Error('tick');
+		}, 10);
+		setTimeout(function() { throw new Error('failed'); }, 5);
+		setTimeout(function() {
+			clearInterval(iv);
+			done = true;
+		}, 100);
+	`)
+	require.NoError(err)
+
+	time.Sleep(300 * time.Millisecond)
+
+	value, err := cell.Run(`done && ticks > 1`)
+	require.NoError(err)
+	require.Equal("true", value.String())
 }
@@ -0,0 +1,172 @@
+package jail
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// JavaScript functions, bundle may define to keep its state across eviction of cell
+const (
+	snapshotFunction = "_status_snapshot" // returns state (JSON string) of bundle
+	restoreFunction  = "_status_restore"  // restores state, returned by _status_snapshot
+)
+
+// CellSnapshot is what is kept of a cell, evicted to keep number of live cells bounded
+// (see params.JailConfig.MaxCells). Cell is restored from it on next access.
+type CellSnapshot struct {
+	ChatID string `json:"chatID"`
+	Bundle string `json:"bundle"`          // JavaScript code, cell has been parsed with
+	State  string `json:"state,omitempty"` // result of _status_snapshot(), if bundle defines it
+}
+
+// CellStore persists snapshots of evicted cells.
+type CellStore interface {
+	// SaveCell stores snapshot of an evicted cell.
+	SaveCell(snapshot CellSnapshot) error
+
+	// LoadCell returns snapshot of an evicted cell, or nil, if there is none.
+	LoadCell(chatID string) (*CellSnapshot, error)
+
+	// DeleteCell removes snapshot of a cell, once it is restored.
+	DeleteCell(chatID string) error
+}
+
+// memoryCellStore keeps snapshots in memory, it is used, unless another store is set.
+type memoryCellStore struct {
+	mu        sync.Mutex
+	snapshots map[string]CellSnapshot
+}
+
+func newMemoryCellStore() *memoryCellStore {
+	return &memoryCellStore{snapshots: make(map[string]CellSnapshot)}
+}
+
+func (s *memoryCellStore) SaveCell(snapshot CellSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots[snapshot.ChatID] = snapshot
+	return nil
+}
+
+func (s *memoryCellStore) LoadCell(chatID string) (*CellSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[chatID]
+	if !ok {
+		return nil, nil
+	}
+	return &snapshot, nil
+}
+
+func (s *memoryCellStore) DeleteCell(chatID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.snapshots, chatID)
+	return nil
+}
+
+// jailConfig returns jail config of the running node (empty one, if it can't be obtained).
+func (jail *Jail) jailConfig() params.JailConfig {
+	if jail.nodeManager == nil {
+		return params.JailConfig{}
+	}
+
+	config, err := jail.nodeManager.NodeConfig()
+	if err != nil || config.JailConfig == nil {
+		return params.JailConfig{}
+	}
+
+	return *config.JailConfig
+}
+
+// evictCells removes least recently used cells (except the one being kept), until number
+// of cells fits the limit. Snapshots of evicted cells are saved to cell store.
+// Must be called with cellsMx held.
+func (jail *Jail) evictCells(maxCells int, keep string) {
+	for maxCells > 0 && len(jail.cells) > maxCells {
+		var lru *Cell
+		for id, cell := range jail.cells {
+			if id != keep && (lru == nil || cell.lastUsed() < lru.lastUsed()) {
+				lru = cell
+			}
+		}
+		if lru == nil {
+			return
+		}
+
+		delete(jail.cells, lru.id)
+		jail.saveCell(lru)
+		lru.Stop()
+
+		log.Info("Evicted least recently used jail cell", "chatID", lru.id, "maxCells", maxCells)
+	}
+}
+
+// saveCell stores snapshot of cell. Cells, which have not been parsed, can't be restored.
+func (jail *Jail) saveCell(cell *Cell) {
+	snapshot := CellSnapshot{
+		ChatID: cell.id,
+		Bundle: cell.bundle,
+	}
+	if snapshot.Bundle == "" {
+		return
+	}
+
+	if fn, err := cell.Get(snapshotFunction); err == nil && fn.IsFunction() {
+		state, err := cell.Call(snapshotFunction, nil)
+		if err != nil {
+			log.Warn("Failed to take snapshot of jail cell state", "chatID", cell.id, "error", err)
+		} else if state.IsDefined() {
+			snapshot.State = state.String()
+		}
+	}
+
+	if err := jail.cellStore.SaveCell(snapshot); err != nil {
+		log.Warn("Failed to save snapshot of jail cell", "chatID", cell.id, "error", err)
+	}
+}
+
+// restoreCell re-creates evicted cell from its snapshot, returning nil, if there is none.
+func (jail *Jail) restoreCell(chatID string) (*Cell, error) {
+	jail.restoreMx.Lock()
+	defer jail.restoreMx.Unlock()
+
+	// cell may have been restored, while waiting for lock
+	if cell, ok := jail.cell(chatID); ok {
+		return cell, nil
+	}
+
+	snapshot, err := jail.cellStore.LoadCell(chatID)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	if res, ok := jail.parse(chatID, snapshot.Bundle); !ok {
+		return nil, fmt.Errorf("restore cell[%s]: %s", chatID, res)
+	}
+	cell, ok := jail.cell(chatID)
+	if !ok {
+		return nil, fmt.Errorf("cell[%s] doesn't exist", chatID)
+	}
+
+	if snapshot.State != "" {
+		if fn, err := cell.Get(restoreFunction); err == nil && fn.IsFunction() {
+			if _, err := cell.Call(restoreFunction, nil, snapshot.State); err != nil {
+				log.Warn("Failed to restore jail cell state", "chatID", chatID, "error", err)
+			}
+		}
+	}
+
+	if err := jail.cellStore.DeleteCell(chatID); err != nil {
+		log.Warn("Failed to delete snapshot of jail cell", "chatID", chatID, "error", err)
+	}
+	log.Info("Restored jail cell from snapshot", "chatID", chatID)
+
+	return cell, nil
+}
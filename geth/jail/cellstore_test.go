@@ -0,0 +1,132 @@
+package jail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+const testSnapshotBundle = `
+var _status_catalog = {};
+var counter = 0;
+function _status_snapshot() { return JSON.stringify({counter: counter}); }
+function _status_restore(state) { counter = JSON.parse(state).counter; }
+`
+
+func TestCellEviction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{MaxCells: 2},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", testSnapshotBundle))
+	require.Equal(t, `{"result": {}}`, jail.Parse("b", testSnapshotBundle))
+
+	cell, err := jail.Cell("b")
+	require.NoError(t, err)
+	_, err = cell.Run(`counter = 7`)
+	require.NoError(t, err)
+
+	// "a" is the least recently used one, after it is accessed, "b" is
+	_, err = jail.Cell("a")
+	require.NoError(t, err)
+	require.Equal(t, `{"result": {}}`, jail.Parse("c", testSnapshotBundle))
+	require.Len(t, jail.cells, 2)
+	require.NotContains(t, jail.cells, "b")
+
+	snapshot, err := jail.cellStore.LoadCell("b")
+	require.NoError(t, err)
+	require.Equal(t, `{"counter":7}`, snapshot.State)
+
+	// evicted cell is restored with its state on access, evicting "a"
+	cell, err = jail.Cell("b")
+	require.NoError(t, err)
+	counter, err := cell.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, "7", counter.String())
+	require.Len(t, jail.cells, 2)
+	require.NotContains(t, jail.cells, "a")
+
+	snapshot, err = jail.cellStore.LoadCell("b")
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+
+	// cells, which have never been parsed, are dropped
+	_, err = jail.NewCell("d")
+	require.NoError(t, err)
+	_, err = jail.NewCell("e")
+	require.NoError(t, err)
+	_, err = jail.NewCell("f")
+	require.NoError(t, err)
+	_, err = jail.Cell("d")
+	require.Error(t, err)
+}
+
+func TestRemoveCell(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{MaxCells: 2},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	bundle := testSnapshotBundle + `function call() { return ++counter; }`
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", bundle))
+	require.Equal(t, `{"result": {}}`, jail.Parse("b", bundle))
+	require.Equal(t, `{"result": 1}`, jail.Call("a", "", ""))
+
+	cells := jail.Cells()
+	require.Len(t, cells, 2)
+	require.Equal(t, "a", cells[0].ChatID)
+	require.EqualValues(t, 1, cells[0].Calls)
+	require.Equal(t, len(bundle), cells[0].BundleSize)
+	require.Equal(t, "b", cells[1].ChatID)
+
+	// execution of removed cell is halted
+	cell, err := jail.Cell("b")
+	require.NoError(t, err)
+	done := make(chan error)
+	go func() {
+		_, err := cell.Run(`while (true) {}`)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, jail.RemoveCell("b"))
+	select {
+	case err := <-done:
+		require.Equal(t, vm.ErrInterrupted, err)
+	case <-time.After(time.Second):
+		t.Fatal("execution of removed cell has not been halted")
+	}
+	require.Len(t, jail.Cells(), 1)
+
+	// evicted cells are removed along with their snapshots
+	require.Equal(t, `{"result": {}}`, jail.Parse("c", bundle))
+	require.Equal(t, `{"result": {}}`, jail.Parse("d", bundle))
+	snapshot, err := jail.cellStore.LoadCell("a")
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	require.NoError(t, jail.RemoveCell("a"))
+	snapshot, err = jail.cellStore.LoadCell("a")
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+
+	_, err = jail.Cell("a")
+	require.Error(t, err)
+	require.Error(t, jail.RemoveCell("unknown"))
+}
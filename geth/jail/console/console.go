@@ -5,27 +5,27 @@ import (
 	"io"
 	"strings"
 
-	"github.com/robertkrimen/otto"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
 	"github.com/status-im/status-go/geth/signal"
 )
 
 // Write provides the base function to write data to the underline writer
-// for the underline otto vm.
-func Write(fn otto.FunctionCall, w io.Writer, consoleEventName string) otto.Value {
+// for the underline JavaScript vm.
+func Write(fn vm.FunctionCall, w io.Writer, consoleEventName string) vm.Value {
 	signal.Send(signal.Envelope{
 		Type:  consoleEventName,
 		Event: convertArgs(fn.ArgumentList),
 	})
 
 	// Next print out the giving values.
-	fmt.Fprintf(w, "%s: %s", consoleEventName, formatForConsole(fn.ArgumentList))
+	fmt.Fprintf(w, "%s: %s", consoleEventName, Format(fn.ArgumentList))
 
-	return otto.UndefinedValue()
+	return nil
 }
 
-// formatForConsole handles conversion of giving otto.Values into
+// Format handles conversion of giving JavaScript values into
 // string counter part.
-func formatForConsole(argumentList []otto.Value) string {
+func Format(argumentList []vm.Value) string {
 	output := []string{}
 	for _, argument := range argumentList {
 		output = append(output, fmt.Sprintf("%v", argument))
@@ -33,9 +33,9 @@ func formatForConsole(argumentList []otto.Value) string {
 	return strings.Join(output, " ")
 }
 
-// convertArgs attempts to convert otto.Values into proper go types else
+// convertArgs attempts to convert JavaScript values into proper go types else
 // uses original.
-func convertArgs(argumentList []otto.Value) []interface{} {
+func convertArgs(argumentList []vm.Value) []interface{} {
 	var items []interface{}
 
 	for _, arg := range argumentList {
@@ -7,8 +7,8 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/robertkrimen/otto"
 	"github.com/status-im/status-go/geth/jail/console"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
 	"github.com/status-im/status-go/geth/signal"
 	"github.com/stretchr/testify/suite"
 )
@@ -20,15 +20,15 @@ func TestConsole(t *testing.T) {
 
 type ConsoleTestSuite struct {
 	suite.Suite
-	vm *otto.Otto
+	vm vm.Engine
 }
 
 func (s *ConsoleTestSuite) SetupTest() {
 	require := s.Require()
 
-	vm := otto.New()
-	require.NotNil(vm)
-	s.vm = vm
+	engine := vm.NewOtto()
+	require.NotNil(engine)
+	s.vm = engine
 }
 
 // TestConsoleLog will validate the operations of the console.log extension
@@ -40,7 +40,7 @@ func (s *ConsoleTestSuite) TestConsoleLog() {
 	var customWriter bytes.Buffer
 
 	err := s.vm.Set("console", map[string]interface{}{
-		"log": func(fn otto.FunctionCall) otto.Value {
+		"log": func(fn vm.FunctionCall) vm.Value {
 			return console.Write(fn, &customWriter, "vm.console")
 		},
 	})
@@ -79,7 +79,7 @@ func (s *ConsoleTestSuite) TestObjectLogging() {
 	})
 
 	err := s.vm.Set("console", map[string]interface{}{
-		"log": func(fn otto.FunctionCall) otto.Value {
+		"log": func(fn vm.FunctionCall) vm.Value {
 			return console.Write(fn, &customWriter, "vm.console")
 		},
 	})
@@ -2,44 +2,82 @@
 jail - jailed enviroment for executing JS code.
 
 Package jail implements "jailed" enviroment for executing arbitrary
-JavaScript code using Otto JS interpreter (https://github.com/robertkrimen/otto).
+JavaScript code using Otto (https://github.com/robertkrimen/otto) or goja (https://github.com/dop251/goja)
+JS interpreter.
 
 Jail create multiple Cells, one cell per status client chat. Each cell runs own
-Otto virtual machine and lives forever, but that may change in the future.
+JavaScript engine chosen with params.JailConfig.Engine: "otto" (ES5, default) or "goja" (ES6 and later,
+with native promises), engines are compared by BenchmarkEngine* benchmarks. Cell lives until jail
+is stopped, or cell is removed with RemoveCell (which halts JavaScript code it executes).
+Cells returns calls, uptime and CPU time of live cells.
+Execution of JavaScript code of cells is interrupted after params.JailConfig.ExecTimeout
+(error returned to caller has "timeout" flag set), leaving cell usable. Errors thrown by JavaScript
+code on Parse and Call are returned as JSONError (class, message, stack trace, location and chat ID),
+and sent as EventJailError signals for crash reporting. If number of cells is limited
+(see params.JailConfig.MaxCells), least recently used cells are evicted: their bundles
+(and state returned by _status_snapshot JS function, if bundle defines one) are saved
+to CellStore, and cells are re-created on next access, passing state to _status_restore.
+
+Engines can't release memory accumulated by JavaScript code of long-lived cells, so if heap grows
+above params.JailConfig.MaxHeapSize (it is checked after calls, at most every 30 seconds), the cell,
+that has handled the most calls, is recycled: re-created from its snapshot the same way. Size of
+heap and number of recycled cells are reported as "jail/heap" and "jail/cells/recycled" metrics.
+
+Base JavaScript (see BaseJS) can be updated with ReloadBaseJS, which re-parses live cells the same
+way, passing state of bundles to re-created cells. ParseVersion changes on every update of base
+JavaScript, cells parsed with stale one are found by comparing it to ParseVersion reported by Cells.
+
+Cells, which exceed their budget of CPU time (params.JailConfig.CellCPUBudget) or heap growth
+(params.JailConfig.CellHeapBudget), accounted across calls and callbacks, are terminated and removed,
+and EventJailCellKilled signal is sent. Heap is shared by cells, so its growth is approximate.
+
+	+----------------------------------------------+
+	|                     Jail                     |
+	+----------------------------------------------+
+	+---------+ +---------+ +---------+  +---------+
+	|  Cell   | |  Cell   | |  Cell   |  |  Cell   |
+	|ChatID 1 | |ChatID 2 | |ChatID 3 |  |ChatID N |
+	|+-------+| |+-------+| |+-------+|  |+-------+|
+	||  VM   || ||  VM   || ||  VM   ||  ||  VM   ||
+	|+-------+| |+-------+| |+-------+|  |+-------+|
+	|| Loop  || || Loop  || || Loop  ||  || Loop  ||
+	++-------++ ++-------++ ++-------++  ++-------++
+
+# Cells
+
+Each Cell object embeds *VM from 'jail/internal/vm' for concurrency safe wrapper around
+functions of JavaScript engine (vm.Engine), which is implemented for every engine supported
+(vm.NewOtto, vm.NewGoja). Values of engines are exposed as common.JailValue, independent of engine,
+and Go functions of vm.Function type can be called by JavaScript code of any engine. This is important
+when dealing with setTimeout and Fetch API functions (see below).
+
+# Get and Set
 
-  +----------------------------------------------+
-  |                     Jail                     |
-  +----------------------------------------------+
-  +---------+ +---------+ +---------+  +---------+
-  |  Cell   | |  Cell   | |  Cell   |  |  Cell   |
-  |ChatID 1 | |ChatID 2 | |ChatID 3 |  |ChatID N |
-  |+-------+| |+-------+| |+-------+|  |+-------+|
-  ||Otto VM|| ||Otto VM|| ||Otto VM||  ||Otto VM||
-  |+-------+| |+-------+| |+-------+|  |+-------+|
-  || Loop  || || Loop  || || Loop  ||  || Loop  ||
-  ++-------++ ++-------++ ++-------++  ++-------++
+(*VM).Get/Set functions provide transparent and concurrently safe wrappers for
+Get and Set functions of engine respectively.
 
-Cells
+# Call and Run
 
-Each Cell object embeds *VM from 'jail/vm' for concurrency safe wrapper around
-*otto.VM functions. This is important when dealing with setTimeout and Fetch API
-functions (see below).
+(*VM).Call/Run functions allows executing arbitrary JS in the cell. They're also
+wrappers arount engine functions of the same name. Run accepts raw JS strings for execution,
+Call takes a JS function name (defined in VM) and parameters.
 
-Get and Set
+Concurrent jail.Call() calls of a cell are serialized by VM's lock, which doesn't guarantee order.
+If params.JailConfig.OrderedCalls is set, calls of each cell are queued and executed in order they
+have been made, and responses are tagged with sequence numbers of calls:
 
-(*VM).Get/Set functions provide transparent and concurrently safe wrappers for
-Otto VM Get and Set functions respectively. See Otto documentation for usage examples:
-https://godoc.org/github.com/robertkrimen/otto
+	{"seq": 3, "result": ...}
 
-Call and Run
+Number of calls, which are executed or wait in queue, is returned by jail.CallQueueDepth().
 
-(*VM).Call/Run functions allows executing arbitrary JS in the cell. They're also
-wrappers arount Otto VM functions of the same name. Run accepts raw JS strings for execution,
-Call takes a JS function name (defined in VM) and parameters.
+jail.CallAsync() returns ID of call immediately, so that caller's thread is not blocked by long-running
+commands, and sends response (the same as returned by jail.Call()) as EventJailCallResult signal:
+
+	{"type": "jail.call.result", "event": {"call_id": "...", "chat_id": "...", "response": {"result": ...}}}
 
-Timeouts and intervals support
+# Timeouts and intervals support
 
-Default Otto VM interpreter doesn't support setTimeout()/setInterval() JS functions,
+JavaScript engines don't support setTimeout()/setInterval() JS functions,
 because they're not part of ECMA-262 spec, but properties of the window object in browser.
 We add support for them using own implementation of Event Loop, heavily based on http://github.com/status-im/ottoext package. See loop/fetch/promise packages under `jail/internal/`.
 
@@ -56,13 +94,27 @@ be executed after 2 seconds in the loop that was started upon current cell.
 
 In order to capture response one may use following approach:
 
-	err = cell.Set("__captureResponse", func(val string) otto.Value {
+	err = cell.Set("__captureResponse", func(val string) {
 		fmt.Println("Captured response from callback:", val)
-		return otto.UndefinedValue()
 	})
 	cell.Run(`setTimeout(function(){ __captureResponse("OK") }, 2000);`)
 
-Fetch support
+An exception thrown by a callback doesn't affect other timers of the cell. Once cell is stopped,
+its pending timers are cancelled.
+
+# Promises and microtasks
+
+Promise is available in every cell (goja's native one, or own implementation for otto). Callbacks
+of settled promises (as well as functions queued with queueMicrotask()) are microtasks: they are called
+once the current code completes, before Run/Call returns, and before the next timer callback is handled
+by the loop. Thus, following code:
+
+	cell.Run(`Promise.resolve(42).then(function(v){ value = v })`)
+
+sets value before cell.Run returns. Microtasks are limited by execution timeout of the cell, as well as
+code which has queued them.
+
+# Fetch support
 
 Fetch API is implemented in a similar way using the same loop. When Cell is created, corresponding handlers are registered within VM and associated event loop.
 
@@ -73,7 +125,7 @@ Due to asynchronous nature of Fetch API, the following code will return immediat
 and callback function in a promise will be executed in a event loop in the background. Thus,
 it's user responsibility to register a corresponding callback function before:
 
-	cell.Set("__captureSuccess", func(res otto.Value) { ... })
+	cell.Set("__captureSuccess", func(call vm.FunctionCall) vm.Value { ... })
 
 	cell.Run(`fetch('http://example.com').then(function(r) {
 		return r.text()
@@ -82,6 +134,73 @@ it's user responsibility to register a corresponding callback function before:
 		__captureSuccess(data)
 	}))
 
+Requests of cells can be limited to whitelisted origins with params.JailConfig.FetchWhitelist,
+or jail.SetFetchWhitelist() for a given cell. Responses larger than params.JailConfig.FetchMaxResponseSize
+(1 MB by default) fail, as well as requests taking longer than params.JailConfig.FetchTimeout (30 seconds
+by default).
+
+# Console
+
+console.log/info/debug/warn/error of parsed cells write entries to the log of status-go, and keep
+the last ones (see params.JailConfig.LogBufferSize) to be inspected with jail.CellLogs(), so that
+commands of bundles can be debugged from the app. With params.JailConfig.StreamLogs set, every entry
+is also sent as "jail.log" signal.
+
+# Whisper
+
+Cells use Whisper with web3.shh methods (posting of messages and polling filters), routed the same
+way as other RPC requests. Besides, web3.shh.subscribe() creates shh_subscribe subscription, so that
+bots can react to messages matching criteria as they arrive:
+
+	var id = web3.shh.subscribe({topics: [topic], symKeyID: keyID}, function(message) { ... })
+	web3.shh.unsubscribe(id)
+
+EIP-1193 provider
+
+Bundles are parsed with the legacy web3 (0.20) connected to jeth. If params.JailConfig.EIP1193Provider is set,
+EIP-1193 provider is exposed as ethereum object as well, so that web3 1.x and newer libraries can be used:
+
+	ethereum.request({method: 'eth_accounts'}).then(function(accounts) { ... })
+	ethereum.on('message', function(message) { ... }) // notifications of eth_subscribe subscriptions
+
+# Shared modules
+
+Libraries used by many bundles can be registered with jail.RegisterModule(), instead of being a part of
+base JavaScript, so that they are parsed once, rather than on every jail.Parse():
+
+	jail.RegisterModule("lodash", lodashJS)
+
+Modules are CommonJS ones, cells load them with require(name), which evaluates module on its first call
+in a cell. Modules are not shared between cells at runtime, every cell gets its own exports, which are
+frozen, so that bundles can't alter them.
+
+# Permissions
+
+RPC requests of a cell (including subscriptions) can be restricted with jail.SetPermissions(), usually
+before bundle of untrusted chat bot is parsed:
+
+	jail.SetPermissions(chatID, &common.JailPermissions{
+		RPCMethods:    []string{"eth_blockNumber", "shh_*"},
+		WhisperTopics: []string{"0x5df7ab4c"},
+	})
+
+Sending or signing transactions, and reading accounts are allowed with SendTransactions and ReadAccounts
+flags, other methods have to match one of RPCMethods (a trailing * matches a prefix). Whisper messages
+can be posted to, and received from WhisperTopics only. Denied requests get error response with
+rpc.ErrCodeUnauthorized code, and "jail.permission.denied" signal is sent. Cells without permissions
+set are not restricted.
+
+# Local storage
+
+Cells parsed with jail.Parse() get localStorage object, items of which are persisted in node's data dir
+(under "jailstorage"), separately for each chat ID, so that chat bots and DApps can keep small state
+across restarts of app:
+
+	cell.Run(`localStorage.setItem('visits', Number(localStorage.getItem('visits')) + 1)`)
+
+Size of keys and values of a cell is limited by params.JailConfig.StorageQuota (5 MB by default),
+setItem() throws QuotaExceededError above it. Items can be inspected with jail.Storage() and removed
+with jail.ClearStorage().
 */
 package jail
 
@@ -0,0 +1,27 @@
+package jail
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// errors
+var (
+	ErrUnsupportedEngine = errors.New("unsupported JavaScript engine")
+)
+
+// newEngine returns JavaScript engine configured with params.JailConfig.Engine.
+// Cells use engines through package jail/internal/vm, so they are independent of engine.
+func newEngine(engine string) (vm.Engine, error) {
+	switch engine {
+	case "", params.JailEngineOtto:
+		return vm.NewOtto(), nil
+	case params.JailEngineGoja:
+		return vm.NewGoja(), nil
+	}
+
+	return nil, fmt.Errorf("%v: %s", ErrUnsupportedEngine, engine)
+}
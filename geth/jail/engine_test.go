@@ -0,0 +1,148 @@
+package jail
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+// benchmarkEngines are engines compared by benchmarks
+var benchmarkEngines = []string{params.JailEngineOtto, params.JailEngineGoja}
+
+// benchmarkBundle is a bundle with a command doing some computation, along with a typical amount of JSON
+const benchmarkBundle = `
+var _status_catalog = {commands: {}};
+function fib(n) { return n < 2 ? n : fib(n - 1) + fib(n - 2); }
+function call(path, args) {
+	var params = JSON.parse(args);
+	var items = [];
+	for (var i = 0; i < 100; i++) {
+		items.push({index: i, value: fib(params.n)});
+	}
+	return JSON.stringify({items: items});
+}
+`
+
+func newEngineJail(t gomock.TestReporter, engine string) (*Jail, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{Engine: engine},
+	}, nil).AnyTimes()
+
+	return New(nodeManager), ctrl
+}
+
+func TestEngine(t *testing.T) {
+	jail, ctrl := newEngineJail(t, "unknown")
+	defer ctrl.Finish()
+	defer jail.Stop()
+
+	_, err := jail.NewCell("chat")
+	require.EqualError(t, err, "unsupported JavaScript engine: unknown")
+	require.Equal(t, `{"error":"unsupported JavaScript engine: unknown"}`, jail.Parse("chat", benchmarkBundle))
+}
+
+func TestEngineGoja(t *testing.T) {
+	jail, ctrl := newEngineJail(t, params.JailEngineGoja)
+	defer ctrl.Finish()
+	defer jail.Stop()
+
+	// bundle may use ES6
+	require.Equal(t, `{"result": {"commands":{"greet":{}}}}`, jail.Parse("chat", `
+		const _status_catalog = {commands: {greet: {}}};
+		class Greeter {
+			constructor(greeting) { this.greeting = greeting; }
+			greet(...names) { return names.map((name) => `+"`${this.greeting} ${name}`"+`).join(', '); }
+		}
+		function call(path, args) {
+			const {names} = JSON.parse(args);
+			return JSON.stringify(new Greeter('hello').greet(...names));
+		}
+	`))
+	require.Equal(t, `{"result": "hello bob, hello alice"}`, jail.Call("chat", `["commands", "greet"]`, `{"names": ["bob", "alice"]}`))
+
+	// modules are compiled by engine of cell
+	require.NoError(t, jail.RegisterModule("counter", `let counter = 0; exports.next = () => ++counter;`))
+	cell, err := jail.Cell("chat")
+	require.NoError(t, err)
+	value, err := cell.Run(`require('counter').next() + require('counter').next()`)
+	require.NoError(t, err)
+	require.Equal(t, "3", value.String())
+
+	// errors are located in source (redeclaration of constant of the previous bundle would fail)
+	var jsonErr JSONError
+	require.NoError(t, json.Unmarshal([]byte(jail.Parse("other", "var _status_catalog = {};\nvar = 1;")), &jsonErr))
+	require.Equal(t, "SyntaxError", jsonErr.Name)
+	require.Equal(t, "bundle.js", jsonErr.File)
+	require.Equal(t, 2, jsonErr.Line)
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("other", `var _status_catalog = {};
+function call(path, args) {
+	while (args === "loop") {}
+	if (args === "fail") throw new TypeError('failed');
+	return 1;
+}`))
+	jsonErr = JSONError{}
+	require.NoError(t, json.Unmarshal([]byte(jail.Call("other", "", "fail")), &jsonErr))
+	require.Equal(t, "TypeError: failed", jsonErr.Error)
+	require.Equal(t, "TypeError", jsonErr.Name)
+	require.Equal(t, "bundle.js", jsonErr.File)
+	require.Equal(t, 4, jsonErr.Line)
+	require.Equal(t, "call (bundle.js:4:29)", jsonErr.Stack[0])
+
+	// execution is interrupted after timeout, cell is usable afterwards
+	timedCell, ok := jail.cell("other")
+	require.True(t, ok)
+	timedCell.SetTimeout(100 * time.Millisecond)
+	jsonErr = JSONError{}
+	require.NoError(t, json.Unmarshal([]byte(jail.Call("other", "", "loop")), &jsonErr))
+	require.True(t, jsonErr.Timeout)
+	require.Equal(t, `{"result": 1}`, jail.Call("other", "", ""))
+}
+
+func BenchmarkEngineParse(b *testing.B) {
+	for _, engine := range benchmarkEngines {
+		b.Run(engine, func(b *testing.B) {
+			jail, ctrl := newEngineJail(b, engine)
+			defer ctrl.Finish()
+			defer jail.Stop()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				chatID := fmt.Sprintf("chat-%d", i)
+				if res := jail.Parse(chatID, benchmarkBundle); res != `{"result": {"commands":{}}}` {
+					b.Fatal(res)
+				}
+				b.StopTimer()
+				jail.RemoveCell(chatID) // nolint: errcheck
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkEngineCall(b *testing.B) {
+	for _, engine := range benchmarkEngines {
+		b.Run(engine, func(b *testing.B) {
+			jail, ctrl := newEngineJail(b, engine)
+			defer ctrl.Finish()
+			defer jail.Stop()
+
+			if res := jail.Parse("chat", benchmarkBundle); res != `{"result": {"commands":{}}}` {
+				b.Fatal(res)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				jail.Call("chat", `["commands", "fib"]`, `{"n": 10}`)
+			}
+		})
+	}
+}
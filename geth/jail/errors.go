@@ -0,0 +1,166 @@
+package jail
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dop251/goja"
+	gojaparser "github.com/dop251/goja/parser"
+	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/parser"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// frameLocationRe matches location of a stack frame, e.g. "call (bundle.js:12:5)" or "base.js:1:10"
+var frameLocationRe = regexp.MustCompile(`([^\s()]+):(\d+):(\d+)\)?$`)
+
+// gojaFramePCRe matches program counter, goja appends to location of a stack frame, e.g. "(12)" of "base.js:1:10(12)"
+var gojaFramePCRe = regexp.MustCompile(`\(\d+\)(\)?)$`)
+
+// JSONError is wrapper around errors, that are sent upwards.
+// For JavaScript errors it also contains stack trace and location,
+// where error has been thrown, mapped to the source file (and source map, if bundle has one).
+type JSONError struct {
+	Error   string   `json:"error"`
+	Name    string   `json:"name,omitempty"`    // class of JavaScript error, e.g. "TypeError"
+	Message string   `json:"message,omitempty"` // message of JavaScript error, without its class
+	ChatID  string   `json:"chatID,omitempty"`  // cell, code of which has thrown error
+	Stack   []string `json:"stack,omitempty"`
+	File    string   `json:"file,omitempty"`
+	Line    int      `json:"line,omitempty"`
+	Column  int      `json:"column,omitempty"`
+
+	// Diagnostics are issues of bundle found by static analysis
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+
+	// Timeout is set, if execution has been interrupted after params.JailConfig.ExecTimeout
+	Timeout bool `json:"timeout,omitempty"`
+}
+
+// newJSONError converts error into JSONError, extracting location from runtime errors
+// and syntax errors of engines.
+func newJSONError(err error) JSONError {
+	jsonErr := JSONError{Error: err.Error(), Timeout: err == vm.ErrTimeout}
+
+	switch e := err.(type) {
+	case *otto.Error:
+		jsonErr.setStack(strings.Split(strings.TrimSpace(e.String()), "\n")[1:])
+	case *goja.Exception:
+		// location is reported separately, as it is for otto's errors
+		jsonErr.Error = e.Value().String()
+		lines := strings.Split(strings.TrimSpace(e.String()), "\n")
+		for i, line := range lines {
+			lines[i] = gojaFramePCRe.ReplaceAllString(line, "$1")
+		}
+		jsonErr.setStack(lines[1:])
+	case *goja.CompilerSyntaxError:
+		jsonErr.Name, jsonErr.Message = "SyntaxError", e.Message
+		if e.File != nil {
+			position := e.File.Position(e.Offset)
+			jsonErr.File, jsonErr.Line, jsonErr.Column = position.Filename, position.Line, position.Column
+		}
+	case parser.ErrorList:
+		if len(e) > 0 {
+			jsonErr.Name, jsonErr.Message = "SyntaxError", e[0].Message
+			jsonErr.File = e[0].Position.Filename
+			jsonErr.Line = e[0].Position.Line
+			jsonErr.Column = e[0].Position.Column
+		}
+	case *parser.Error:
+		jsonErr.Name, jsonErr.Message = "SyntaxError", e.Message
+		jsonErr.File = e.Position.Filename
+		jsonErr.Line = e.Position.Line
+		jsonErr.Column = e.Position.Column
+	case gojaparser.ErrorList:
+		if len(e) > 0 {
+			jsonErr.Name, jsonErr.Message = "SyntaxError", e[0].Message
+			jsonErr.File = e[0].Position.Filename
+			jsonErr.Line = e[0].Position.Line
+			jsonErr.Column = e[0].Position.Column
+		}
+	case *gojaparser.Error:
+		jsonErr.Name, jsonErr.Message = "SyntaxError", e.Message
+		jsonErr.File = e.Position.Filename
+		jsonErr.Line = e.Position.Line
+		jsonErr.Column = e.Position.Column
+	}
+
+	return jsonErr
+}
+
+// setStack sets name and message of error, along with stack, location is taken from the first frame,
+// which has it.
+func (jsonErr *JSONError) setStack(lines []string) {
+	jsonErr.Name, jsonErr.Message = "Error", jsonErr.Error
+	if parts := strings.SplitN(jsonErr.Error, ": ", 2); len(parts) == 2 {
+		jsonErr.Name, jsonErr.Message = parts[0], parts[1]
+	}
+
+	for _, line := range lines {
+		frame := strings.TrimPrefix(strings.TrimSpace(line), "at ")
+		if frame == "<unknown>" {
+			continue
+		}
+		jsonErr.Stack = append(jsonErr.Stack, frame)
+
+		if jsonErr.File != "" {
+			continue
+		}
+		// native frames have no column and are skipped
+		if m := frameLocationRe.FindStringSubmatch(frame); m != nil {
+			jsonErr.File = m[1]
+			jsonErr.Line, _ = strconv.Atoi(m[2])
+			jsonErr.Column, _ = strconv.Atoi(m[3])
+		}
+	}
+}
+
+func makeError(error string) string {
+	str := JSONError{
+		Error: error,
+	}
+	outBytes, _ := json.Marshal(&str)
+	return string(outBytes)
+}
+
+// makeJSError logs error thrown by cell's JavaScript along with its location,
+// sends it as EventJailError signal, and returns it as JSON.
+func makeJSError(chatID string, err error) string {
+	jsonErr := newJSONError(err)
+	jsonErr.ChatID = chatID
+	log.Error("JavaScript error in jail cell", "chatID", chatID, "error", jsonErr.Error,
+		"file", jsonErr.File, "line", jsonErr.Line, "column", jsonErr.Column)
+
+	signal.Send(signal.Envelope{
+		Type:  EventJailError,
+		Event: jsonErr,
+	})
+
+	outBytes, _ := json.Marshal(&jsonErr)
+	return string(outBytes)
+}
+
+// makeAnalysisError returns JSON error for a bundle rejected by static analysis,
+// pointing to the first issue found, along with all of them.
+func makeAnalysisError(diagnostics []Diagnostic) string {
+	jsonErr := JSONError{
+		Error:       ErrBundleRejected.Error(),
+		Diagnostics: diagnostics,
+	}
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			jsonErr.Error += ": " + d.Message
+			if d.Line > 0 {
+				jsonErr.File, jsonErr.Line, jsonErr.Column = bundleFilename, d.Line, d.Column
+			}
+			break
+		}
+	}
+
+	outBytes, _ := json.Marshal(&jsonErr)
+	return string(outBytes)
+}
@@ -0,0 +1,98 @@
+package jail
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorLocation(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	var jsonErr JSONError
+	res := jail.Parse("chat", "var _status_catalog = {};\nundefinedVariable;")
+	require.NoError(t, json.Unmarshal([]byte(res), &jsonErr))
+	require.Equal(t, "ReferenceError: 'undefinedVariable' is not defined", jsonErr.Error)
+	require.Equal(t, "bundle.js", jsonErr.File)
+	require.Equal(t, 2, jsonErr.Line)
+	require.Equal(t, 1, jsonErr.Column)
+
+	res = jail.Parse("chat", "var _status_catalog = {};\nvar = 1;")
+	require.NoError(t, json.Unmarshal([]byte(res), &jsonErr))
+	require.Equal(t, "SyntaxError", jsonErr.Name)
+	require.Equal(t, "bundle.js", jsonErr.File)
+	require.Equal(t, 2, jsonErr.Line)
+}
+
+func TestCallErrorStack(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	jail.BaseJS("function fail() {\n  throw new Error('failed');\n}")
+	res := jail.Parse("chat", "var _status_catalog = {};\nfunction call() { fail(); }")
+	require.Equal(t, `{"result": {}}`, res)
+
+	var signals []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, EventJailError) {
+			signals = append(signals, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	var jsonErr JSONError
+	res = jail.Call("chat", `["commands", "test"]`, `{}`)
+	require.NoError(t, json.Unmarshal([]byte(res), &jsonErr))
+	require.Equal(t, "Error: failed", jsonErr.Error)
+	require.Equal(t, "Error", jsonErr.Name)
+	require.Equal(t, "failed", jsonErr.Message)
+	require.Equal(t, "chat", jsonErr.ChatID)
+	require.Equal(t, "base.js", jsonErr.File)
+	require.Equal(t, 2, jsonErr.Line)
+	require.Equal(t, "fail (base.js:2:13)", jsonErr.Stack[0])
+	require.Equal(t, "call (bundle.js:2:19)", jsonErr.Stack[1])
+
+	// error is reported as signal as well
+	require.Len(t, signals, 1)
+	var envelope struct {
+		Event JSONError `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(signals[0]), &envelope))
+	require.Equal(t, jsonErr, envelope.Event)
+
+	// class of error is reported separately
+	jail.Parse("chat", "var _status_catalog = {};\nfunction call() { null.x; }")
+	require.NoError(t, json.Unmarshal([]byte(jail.Call("chat", `[]`, `{}`)), &jsonErr))
+	require.Equal(t, "TypeError", jsonErr.Name)
+}
+
+func TestCallTimeout(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	bundle := `var _status_catalog = {};
+function call(path, args) {
+	while (args === "loop") {}
+	return 1;
+}`
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", bundle))
+	cell, ok := jail.cell("chat")
+	require.True(t, ok)
+	cell.SetTimeout(100 * time.Millisecond)
+
+	var jsonErr JSONError
+	require.NoError(t, json.Unmarshal([]byte(jail.Call("chat", "", "loop")), &jsonErr))
+	require.True(t, jsonErr.Timeout)
+	require.Equal(t, vm.ErrTimeout.Error(), jsonErr.Error)
+
+	// cell is usable after timeout
+	require.Equal(t, `{"result": 1}`, jail.Call("chat", "", ""))
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, `{"result": 1}`, jail.Call("chat", "", ""))
+}
@@ -0,0 +1,62 @@
+package jail
+
+import (
+	"time"
+
+	"github.com/status-im/status-go/geth/jail/internal/fetch"
+	"github.com/status-im/status-go/geth/params"
+)
+
+const (
+	// DefaultFetchMaxResponseSize is a size (in bytes) of response body, fetch() of cells reads,
+	// unless params.JailConfig.FetchMaxResponseSize is set
+	DefaultFetchMaxResponseSize = 1024 * 1024
+
+	// DefaultFetchTimeout is a timeout of requests, cells send with fetch(),
+	// unless params.JailConfig.FetchTimeout is set
+	DefaultFetchTimeout = 30 * time.Second
+)
+
+// SetFetchWhitelist changes origins, cell identified by chatID may send requests to with fetch(),
+// overriding params.JailConfig.FetchWhitelist (nil restores it). Whitelist is kept, when cell
+// is removed or re-created.
+func (jail *Jail) SetFetchWhitelist(chatID string, origins []string) {
+	jail.fetchMx.Lock()
+	if origins == nil {
+		delete(jail.fetchWhitelists, chatID)
+	} else {
+		jail.fetchWhitelists[chatID] = origins
+	}
+	jail.fetchMx.Unlock()
+
+	jail.cellsMx.RLock()
+	cell, ok := jail.cells[chatID]
+	jail.cellsMx.RUnlock()
+
+	if ok {
+		cell.setFetchOptions(jail.fetchOptions(chatID, jail.jailConfig()))
+	}
+}
+
+// fetchOptions returns limits of requests, cell identified by chatID sends with fetch().
+func (jail *Jail) fetchOptions(chatID string, config params.JailConfig) fetch.Options {
+	options := fetch.Options{
+		Whitelist:       config.FetchWhitelist,
+		MaxResponseSize: DefaultFetchMaxResponseSize,
+		Timeout:         DefaultFetchTimeout,
+	}
+	if config.FetchMaxResponseSize > 0 {
+		options.MaxResponseSize = int64(config.FetchMaxResponseSize)
+	}
+	if config.FetchTimeout > 0 {
+		options.Timeout = time.Duration(config.FetchTimeout) * time.Millisecond
+	}
+
+	jail.fetchMx.RLock()
+	if origins, ok := jail.fetchWhitelists[chatID]; ok {
+		options.Whitelist = origins
+	}
+	jail.fetchMx.RUnlock()
+
+	return options
+}
@@ -1,12 +1,16 @@
 package jail
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 
-	"github.com/robertkrimen/otto"
 	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/jail/console"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/log"
 	"github.com/status-im/status-go/geth/node"
+	"github.com/status-im/status-go/geth/rpc"
 	"github.com/status-im/status-go/geth/signal"
 )
 
@@ -14,6 +18,25 @@ import (
 const (
 	EventSignal = "jail.signal"
 
+	// EventRateLimited is triggered when RPC requests of a cell start being rejected by rate limiter
+	EventRateLimited = "jail.rate.limited"
+
+	// EventJailLog is triggered for every entry cells log with console, if params.JailConfig.StreamLogs is set
+	EventJailLog = "jail.log"
+
+	// EventJailCellKilled is triggered when a cell is terminated for exceeding its CPU or memory budget
+	EventJailCellKilled = "jail.cell.killed"
+
+	// EventJailError is triggered when JavaScript code of a cell throws on jail.Parse() or jail.Call(),
+	// the event is JSONError returned to caller
+	EventJailError = "jail.error"
+
+	// EventJailCallResult is triggered with response of a call made with jail.CallAsync()
+	EventJailCallResult = "jail.call.result"
+
+	// EventJailPermissionDenied is triggered when RPC request of a cell is denied by its permissions
+	EventJailPermissionDenied = "jail.permission.denied"
+
 	// EventConsoleLog defines the event type for the console.log call.
 	eventConsoleLog = "vm.console.log"
 )
@@ -26,10 +49,10 @@ func registerHandlers(jail *Jail, cell common.JailCell, chatID string) error {
 		return err
 	}
 
-	registerHandler := jeth.Object().Set
+	registerHandler := jeth.Set
 
 	if err = registerHandler("console", map[string]interface{}{
-		"log": func(fn otto.FunctionCall) otto.Value {
+		"log": func(fn vm.FunctionCall) vm.Value {
 			return console.Write(fn, os.Stdout, eventConsoleLog)
 		},
 	}); err != nil {
@@ -46,71 +69,182 @@ func registerHandlers(jail *Jail, cell common.JailCell, chatID string) error {
 		return err
 	}
 
+	// register subscribe/unsubscribe handlers
+	if err = registerHandler("subscribe", makeSubscribeHandler(jail, cell)); err != nil {
+		return err
+	}
+	if err = registerHandler("unsubscribe", makeUnsubscribeHandler(jail)); err != nil {
+		return err
+	}
+
 	// register isConnected handler
-	if err = registerHandler("isConnected", makeJethIsConnectedHandler(jail, cell)); err != nil {
+	if err = registerHandler("isConnected", makeJethIsConnectedHandler(jail)); err != nil {
 		return err
 	}
 
 	// register sendMessage/showSuggestions handlers
-	if err = cell.Set("statusSignals", struct{}{}); err != nil {
+	if err = cell.Set("statusSignals", map[string]interface{}{}); err != nil {
 		return err
 	}
 	statusSignals, err := cell.Get("statusSignals")
 	if err != nil {
 		return err
 	}
-	registerHandler = statusSignals.Object().Set
+	registerHandler = statusSignals.Set
 	if err = registerHandler("sendSignal", makeSignalHandler(chatID)); err != nil {
 		return err
 	}
 
-	return nil
+	// register console, entries of which are kept in cell's logs
+	if err = cell.Set("console", makeConsole(jail, chatID)); err != nil {
+		return err
+	}
+
+	// register localStorage, persisted across restarts of app
+	return registerLocalStorage(jail, cell, chatID)
 }
 
 // makeAsyncSendHandler returns jeth.sendAsync() handler.
-func makeAsyncSendHandler(jail *Jail, cellInt common.JailCell) func(call otto.FunctionCall) otto.Value {
+func makeAsyncSendHandler(jail *Jail, cellInt common.JailCell) vm.Function {
 	// FIXME(tiabc): Get rid of this.
 	cell := cellInt.(*Cell)
-	return func(call otto.FunctionCall) otto.Value {
+	return func(call vm.FunctionCall) vm.Value {
+		callback := call.Argument(1)
+		if !jail.allowRequest(cell) {
+			if callback.Class() == "Function" {
+				cell.CallAsync(callback, nil, jail.rateLimitedResponse(call))
+			}
+			return nil
+		}
+		if err := jail.allowRequests(cell.id, call); err != nil {
+			if callback.Class() == "Function" {
+				cell.CallAsync(callback, nil, jail.permissionDeniedResponse(call, err))
+			}
+			return nil
+		}
+
+		// request is serialized by cell's engine, while it is locked, response is converted
+		// by the engine once callback is called, so RPC call doesn't need the engine
+		request := stringifyRequest(call)
 		go func() {
-			response := jail.Send(call)
+			response, err := jail.sendRequest(cell.ctx, request)
 
-			callback := call.Argument(1)
 			if callback.Class() == "Function" {
 				// run callback asyncronously with args (error, response)
-				err := otto.NullValue()
-				cell.CallAsync(callback, err, response)
+				if err != nil {
+					cell.CallAsync(callback, err.Error(), nil)
+					return
+				}
+				cell.CallAsync(callback, nil, response)
 			}
 		}()
-		return otto.UndefinedValue()
+		return nil
 	}
 }
 
 // makeSendHandler returns jeth.send() and jeth.sendAsync() handler
 // TODO(tiabc): get rid of an extra parameter.
-func makeSendHandler(jail *Jail, cellInt common.JailCell) func(call otto.FunctionCall) otto.Value {
-	return func(call otto.FunctionCall) otto.Value {
-		return jail.Send(call)
+func makeSendHandler(jail *Jail, cellInt common.JailCell) vm.Function {
+	// FIXME(tiabc): Get rid of this.
+	cell := cellInt.(*Cell)
+	return func(call vm.FunctionCall) vm.Value {
+		if !jail.allowRequest(cell) {
+			return jail.rateLimitedResponse(call)
+		}
+		if err := jail.allowRequests(cell.id, call); err != nil {
+			return jail.permissionDeniedResponse(call, err)
+		}
+		return jail.send(cell.ctx, call)
 	}
 }
 
 // makeJethIsConnectedHandler returns jeth.isConnected() handler
-func makeJethIsConnectedHandler(jail *Jail, cellInt common.JailCell) func(call otto.FunctionCall) (response otto.Value) {
-	// FIXME(tiabc): Get rid of this.
-	cell := cellInt.(*Cell)
-	return func(call otto.FunctionCall) otto.Value {
+func makeJethIsConnectedHandler(jail *Jail) vm.Function {
+	return func(call vm.FunctionCall) vm.Value {
 		client := jail.nodeManager.RPCClient()
 
 		var netListeningResult bool
 		if err := client.Call(&netListeningResult, "net_listening"); err != nil {
-			return newErrorResponseOtto(cell.VM, err.Error(), nil)
+			return newErrorResponseValue(call.Engine, rpc.ErrCodeResourceUnavailable, err.Error(), nil)
 		}
 
 		if !netListeningResult {
-			return newErrorResponseOtto(cell.VM, node.ErrNoRunningNode.Error(), nil)
+			return newErrorResponseValue(call.Engine, rpc.ErrCodeResourceUnavailable, node.ErrNoRunningNode.Error(), nil)
+		}
+
+		return newResultResponse(call.Engine, true)
+	}
+}
+
+// makeSubscribeHandler returns jeth.subscribe(type, [filter], callback) handler, which creates
+// eth_subscribe subscription and returns its id. Callback is called with every notification,
+// until subscription is cancelled with jeth.unsubscribe(id), or cell is stopped.
+func makeSubscribeHandler(jail *Jail, cellInt common.JailCell) vm.Function {
+	// FIXME(tiabc): Get rid of this.
+	cell := cellInt.(*Cell)
+	return func(call vm.FunctionCall) vm.Value {
+		if len(call.ArgumentList) < 2 {
+			throwJSException(errors.New("jeth.subscribe expects subscription type and callback"))
+		}
+		callback := call.ArgumentList[len(call.ArgumentList)-1]
+		if callback.Class() != "Function" {
+			throwJSException(errors.New("jeth.subscribe expects callback as the last argument"))
+		}
+
+		var args []interface{}
+		for _, arg := range call.ArgumentList[1 : len(call.ArgumentList)-1] {
+			value, err := arg.Export()
+			if err != nil {
+				throwJSException(err)
+			}
+			args = append(args, value)
+		}
+
+		kind := call.Argument(0).String()
+		method := "eth_subscribe"
+		if kind == rpc.SubscriptionMessages {
+			method = "shh_subscribe"
+		}
+		if err := jail.allowMethod(cell.id, method, append([]interface{}{kind}, args...)); err != nil {
+			throwJSException(err)
+		}
+
+		client := jail.nodeManager.RPCClient()
+		if client == nil {
+			throwJSException(node.ErrNoRunningNode)
+		}
+
+		id, err := client.Subscribe(cell.ctx, func(result json.RawMessage) {
+			var value interface{}
+			if err := json.Unmarshal(result, &value); err != nil {
+				log.Error("Failed to unmarshal subscription notification", "error", err)
+				return
+			}
+			cell.CallAsync(callback, value)
+		}, kind, args...)
+		if err != nil {
+			throwJSException(err)
+		}
+
+		response, _ := call.Engine.ToValue(id)
+		return response
+	}
+}
+
+// makeUnsubscribeHandler returns jeth.unsubscribe(id) handler.
+func makeUnsubscribeHandler(jail *Jail) vm.Function {
+	return func(call vm.FunctionCall) vm.Value {
+		client := jail.nodeManager.RPCClient()
+		if client == nil {
+			throwJSException(node.ErrNoRunningNode)
+		}
+
+		if err := client.Unsubscribe(call.Argument(0).String()); err != nil {
+			throwJSException(err)
 		}
 
-		return newResultResponse(call.Otto, true)
+		response, _ := call.Engine.ToValue(true)
+		return response
 	}
 }
 
@@ -120,8 +254,8 @@ type SignalEvent struct {
 	Data   string `json:"data"`
 }
 
-func makeSignalHandler(chatID string) func(call otto.FunctionCall) otto.Value {
-	return func(call otto.FunctionCall) otto.Value {
+func makeSignalHandler(chatID string) vm.Function {
+	return func(call vm.FunctionCall) vm.Value {
 		message := call.Argument(0).String()
 
 		signal.Send(signal.Envelope{
@@ -132,6 +266,6 @@ func makeSignalHandler(chatID string) func(call otto.FunctionCall) otto.Value {
 			},
 		})
 
-		return newResultResponse(call.Otto, true)
+		return newResultResponse(call.Engine, true)
 	}
 }
@@ -1,21 +1,42 @@
 package fetch
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/GeertJohan/go.rice"
-	"github.com/robertkrimen/otto"
 
 	"github.com/status-im/status-go/geth/jail/internal/loop"
 	"github.com/status-im/status-go/geth/jail/internal/promise"
 	"github.com/status-im/status-go/geth/jail/internal/vm"
 )
 
-func mustValue(v otto.Value, err error) otto.Value {
+// errors
+var (
+	ErrOriginNotAllowed = errors.New("origin is not whitelisted")
+	ErrResponseTooLarge = errors.New("response exceeds size limit")
+)
+
+// Options limit requests sent with fetch().
+type Options struct {
+	// Whitelist of origins requests may be sent to (see Allowed), any origin is allowed, if empty
+	Whitelist []string
+
+	// MaxResponseSize limits size (in bytes) of response body, 0 means no limit
+	MaxResponseSize int64
+
+	// Timeout of request, including reading of response body, 0 means no timeout
+	Timeout time.Duration
+}
+
+func mustValue(v vm.Value, err error) vm.Value {
 	if err != nil {
 		panic(err)
 	}
@@ -25,8 +46,8 @@ func mustValue(v otto.Value, err error) otto.Value {
 
 type fetchTask struct {
 	id           int64
-	jsReq, jsRes *otto.Object
-	cb           otto.Value
+	jsReq, jsRes vm.Value
+	cb           vm.Value
 	err          error
 	status       int
 	statusText   string
@@ -49,29 +70,31 @@ func (t *fetchTask) Execute(vm *vm.VM, l *loop.Loop) error {
 		arguments = append(arguments, e)
 	}
 
-	// We're locking on VM here because underlying otto's VM
+	// We're locking on VM here because underlying engine
 	// is not concurrently safe, and this function indirectly
 	// access vm's functions in cb.Call/h.Set.
 	vm.Lock()
 	defer vm.Unlock()
+	defer vm.Track()()
 
 	t.jsRes.Set("status", t.status)
 	t.jsRes.Set("statusText", t.statusText)
-	h := mustValue(t.jsRes.Get("headers")).Object()
+	h := mustValue(t.jsRes.Get("headers"))
+	appendHeader := mustValue(h.Get("append"))
 	for k, vs := range t.headers {
 		for _, v := range vs {
-			if _, err := h.Call("append", k, v); err != nil {
+			if _, err := appendHeader.Call(h, k, v); err != nil {
 				return err
 			}
 		}
 	}
 	t.jsRes.Set("_body", string(t.body))
 
-	if _, err := t.cb.Call(otto.NullValue(), arguments...); err != nil {
-		return err
-	}
+	_, err := t.cb.Call(nil, arguments...)
+	// callback settles promise of fetch, callbacks of which are microtasks
+	vm.DrainMicrotasks()
 
-	return nil
+	return err
 }
 
 func (t *fetchTask) Cancel() {
@@ -82,25 +105,35 @@ func Define(vm *vm.VM, l *loop.Loop) error {
 }
 
 func DefineWithHandler(vm *vm.VM, l *loop.Loop, h http.Handler) error {
-	if err := promise.Define(vm, l); err != nil {
+	return DefineWithOptions(vm, l, h, nil)
+}
+
+// DefineWithOptions defines fetch(), requests of which are limited by options
+// (options is called for every request, so that they may be changed later).
+func DefineWithOptions(jsvm *vm.VM, l *loop.Loop, h http.Handler, options func() Options) error {
+	if options == nil {
+		options = func() Options { return Options{} }
+	}
+
+	if err := promise.Define(jsvm, l); err != nil {
 		return err
 	}
 
 	jsData := rice.MustFindBox("dist-fetch").MustString("bundle.js")
 	smData := rice.MustFindBox("dist-fetch").MustString("bundle.js.map")
 
-	s, err := vm.CompileWithSourceMap("fetch-bundle.js", jsData, smData)
+	s, err := jsvm.CompileWithSourceMap("fetch-bundle.js", jsData, smData)
 	if err != nil {
 		return err
 	}
 
-	if _, err := vm.Run(s); err != nil {
+	if _, err := jsvm.Run(s); err != nil {
 		return err
 	}
 
-	vm.Set("__private__fetch_execute", func(c otto.FunctionCall) otto.Value {
-		jsReq := c.Argument(0).Object()
-		jsRes := c.Argument(1).Object()
+	jsvm.Set("__private__fetch_execute", func(c vm.FunctionCall) vm.Value {
+		jsReq := c.Argument(0)
+		jsRes := c.Argument(1)
 		cb := c.Argument(2)
 
 		method := mustValue(jsReq.Get("method")).String()
@@ -138,27 +171,111 @@ func DefineWithHandler(vm *vm.VM, l *loop.Loop, h http.Handler) error {
 				t.headers = res.Header()
 				t.body = res.Body.Bytes()
 			} else {
-				res, err := http.DefaultClient.Do(req)
-				if err != nil {
-					t.err = err
-					return
-				}
-
-				d, err := ioutil.ReadAll(res.Body)
-				if err != nil {
-					t.err = err
-					return
-				}
-
-				t.status = res.StatusCode
-				t.statusText = res.Status
-				t.headers = res.Header
-				t.body = d
+				t.err = t.do(req, options())
 			}
 		}()
 
-		return otto.UndefinedValue()
+		return nil
 	})
 
 	return nil
 }
+
+// do sends request, limited by options, and reads response.
+func (t *fetchTask) do(req *http.Request, options Options) error {
+	if !Allowed(req.URL, options.Whitelist) {
+		return fmt.Errorf("%v: %s", ErrOriginNotAllowed, origin(req.URL))
+	}
+
+	client := &http.Client{
+		Timeout: options.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !Allowed(req.URL, options.Whitelist) {
+				return fmt.Errorf("%v: %s", ErrOriginNotAllowed, origin(req.URL))
+			}
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() // nolint: errcheck
+
+	var body io.Reader = res.Body
+	if options.MaxResponseSize > 0 {
+		body = io.LimitReader(res.Body, options.MaxResponseSize+1)
+	}
+	d, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if options.MaxResponseSize > 0 && int64(len(d)) > options.MaxResponseSize {
+		return ErrResponseTooLarge
+	}
+
+	t.status = res.StatusCode
+	t.statusText = res.Status
+	t.headers = res.Header
+	t.body = d
+
+	return nil
+}
+
+// Allowed reports whether origin of URL matches one of whitelisted origins. Origin is matched
+// by scheme, host and port (e.g. "https://api.example.com"), "*." prefix of host matches any
+// subdomain (e.g. "https://*.example.com"), and origin without scheme matches both http and https
+// (e.g. "api.example.com"). Any origin is allowed, if whitelist is empty.
+func Allowed(u *url.URL, whitelist []string) bool {
+	if len(whitelist) == 0 {
+		return true
+	}
+
+	scheme, host := strings.ToLower(u.Scheme), hostPort(u)
+	for _, entry := range whitelist {
+		entry = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(entry), "/"))
+		if i := strings.Index(entry, "://"); i >= 0 {
+			if entry[:i] != scheme {
+				continue
+			}
+			entry = entry[i+3:]
+		} else if scheme != "http" && scheme != "https" {
+			continue
+		}
+		entry = strings.TrimSuffix(strings.TrimSuffix(entry, ":"+defaultPort(scheme)), "/")
+
+		if entry == host {
+			return true
+		}
+		if strings.HasPrefix(entry, "*.") && strings.HasSuffix(host, entry[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// origin returns origin of URL, as it is matched against whitelist.
+func origin(u *url.URL) string {
+	return strings.ToLower(u.Scheme) + "://" + hostPort(u)
+}
+
+// hostPort returns lowercased host of URL, with port, unless it is default one.
+func hostPort(u *url.URL) string {
+	host := strings.ToLower(u.Host)
+	return strings.TrimSuffix(host, ":"+defaultPort(strings.ToLower(u.Scheme)))
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	}
+
+	return ""
+}
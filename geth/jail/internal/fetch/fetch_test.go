@@ -4,14 +4,14 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
-	"github.com/robertkrimen/otto"
-
 	"github.com/status-im/status-go/geth/jail/internal/fetch"
 	"github.com/status-im/status-go/geth/jail/internal/loop"
 	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -169,10 +169,10 @@ func (s *FetchSuite) TestFetchWithHandlerParallel() {
 	s.NoError(err)
 
 	ch := make(chan struct{})
-	err = s.vm.Set("__capture", func(c otto.FunctionCall) otto.Value {
+	err = s.vm.Set("__capture", func(c vm.FunctionCall) vm.Value {
 		ch <- struct{}{}
 
-		return otto.UndefinedValue()
+		return nil
 	})
 	s.NoError(err)
 
@@ -193,6 +193,73 @@ loop:
 	s.Equal(5, count)
 }
 
+func (s *FetchSuite) TestFetchWithOptions() {
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	s.mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.com/", http.StatusFound)
+	})
+
+	options := fetch.Options{Whitelist: []string{s.srv.URL}, MaxResponseSize: 5}
+	err := fetch.DefineWithOptions(s.vm, s.loop, nil, func() fetch.Options { return options })
+	s.NoError(err)
+
+	ch := make(chan string)
+	err = s.vm.Set("__capture", func(str string) {
+		ch <- str
+	})
+	s.NoError(err)
+
+	fetchText := func(url string) string {
+		err := s.loop.Eval(`fetch('` + url + `').then(function(r) {
+			return r.text();
+		}).then(__capture, function(e) { __capture(e.message); })`)
+		s.NoError(err)
+
+		select {
+		case str := <-ch:
+			return str
+		case <-time.After(1 * time.Second):
+			s.Fail("test timed out")
+			return ""
+		}
+	}
+
+	s.Equal("hello", fetchText(s.srv.URL))
+	s.Equal("origin is not whitelisted: http://example.com", fetchText("http://example.com/"))
+	s.Contains(fetchText(s.srv.URL+"/redirect"), "origin is not whitelisted: http://example.com")
+
+	options.MaxResponseSize = 4
+	s.Equal("response exceeds size limit", fetchText(s.srv.URL))
+}
+
+func TestAllowed(t *testing.T) {
+	whitelist := []string{"https://api.example.com", "https://*.status.im:8443", "localhost:8080"}
+
+	for rawurl, allowed := range map[string]bool{
+		"https://api.example.com/v1":      true,
+		"https://API.example.com:443/":    true,
+		"http://api.example.com/":         false,
+		"https://api.example.com:8443/":   false,
+		"https://example.com/":            false,
+		"https://a.b.status.im:8443/":     true,
+		"https://status.im:8443/":         false,
+		"https://evilstatus.im:8443/":     false,
+		"http://localhost:8080/":          true,
+		"https://localhost:8080/":         true,
+		"ftp://localhost:8080/":           false,
+		"https://api.example.com.evil.io": false,
+	} {
+		u, err := url.Parse(rawurl)
+		require.NoError(t, err)
+		require.Equal(t, allowed, fetch.Allowed(u, whitelist), rawurl)
+	}
+
+	u, _ := url.Parse("http://example.com")
+	require.True(t, fetch.Allowed(u, nil))
+}
+
 type FetchSuite struct {
 	suite.Suite
 
@@ -207,8 +274,7 @@ func (s *FetchSuite) SetupTest() {
 	s.mux = http.NewServeMux()
 	s.srv = httptest.NewServer(s.mux)
 
-	o := otto.New()
-	s.vm = vm.New(o)
+	s.vm = vm.New(vm.NewOtto())
 	s.loop = loop.New(s.vm)
 
 	go s.loop.Run(context.Background())
@@ -115,22 +115,18 @@ func (l *Loop) Eval(s interface{}) error {
 	return nil
 }
 
-func (l *Loop) processTask(t Task) error {
-	id := t.GetID()
-
-	if err := t.Execute(l.vm, l); err != nil {
-		l.lock.RLock()
-		for _, t := range l.tasks {
-			t.Cancel()
-		}
-		l.lock.RUnlock()
+func (l *Loop) processTask(t Task) (err error) {
+	// task may be halted, once cell is removed
+	defer vm.RecoverInterrupted(&err)
 
-		return err
-	}
+	id := t.GetID()
 
+	// failure of a task (e.g. exception thrown by timer callback) doesn't affect other tasks,
+	// as loop keeps running, until cell is stopped
+	err = t.Execute(l.vm, l)
 	l.removeByID(id)
 
-	return nil
+	return err
 }
 
 // Run handles the task scheduling and finalisation.
@@ -152,6 +148,13 @@ func (l *Loop) Run(ctx context.Context) error {
 				continue
 			}
 		case <-ctx.Done():
+			// pending tasks (e.g. timers) are not going to be finalised
+			l.lock.RLock()
+			for _, t := range l.tasks {
+				t.Cancel()
+			}
+			l.lock.RUnlock()
+
 			return context.Canceled
 		}
 	}
@@ -3,7 +3,6 @@ package looptask
 import (
 	"errors"
 
-	"github.com/robertkrimen/otto"
 	"github.com/status-im/status-go/geth/jail/internal/loop"
 	"github.com/status-im/status-go/geth/jail/internal/vm"
 )
@@ -34,22 +33,22 @@ func (i IdleTask) Execute(vm *vm.VM, l *loop.Loop) error {
 	return errors.New("Idle task should never execute")
 }
 
-// EvalTask schedules running an otto.Script. It has two channels for
+// EvalTask schedules running a script. It has two channels for
 // communicating the result of the execution.
 type EvalTask struct {
 	ID        int64
 	Script    interface{}
-	Value     chan otto.Value
+	Value     chan vm.Value
 	Error     chan error
 	SoftError bool
 }
 
-// NewEvalTask creates a new EvalTask for a given otto.Script, creating two
+// NewEvalTask creates a new EvalTask for a given script, creating two
 // buffered channels for the response.
 func NewEvalTask(s interface{}) *EvalTask {
 	return &EvalTask{
 		Script: s,
-		Value:  make(chan otto.Value, 1),
+		Value:  make(chan vm.Value, 1),
 		Error:  make(chan error, 1),
 	}
 }
@@ -63,7 +62,7 @@ func (e EvalTask) GetID() int64 { return e.ID }
 // Cancel does nothing for an EvalTask, as there's nothing to clean up.
 func (e EvalTask) Cancel() {}
 
-// Execute runs the EvalTask's otto.Script in the vm provided, pushing the
+// Execute runs the EvalTask's script in the vm provided, pushing the
 // resultant return value and error (or nil) into the associated channels.
 // If the execution results in an error, it will return that error.
 func (e EvalTask) Execute(vm *vm.VM, l *loop.Loop) error {
@@ -78,26 +77,26 @@ func (e EvalTask) Execute(vm *vm.VM, l *loop.Loop) error {
 	return err
 }
 
-// CallTask schedules an otto.Value (which should be a function) to be called
+// CallTask schedules a value (which should be a function) to be called
 // with a specific set of arguments. It has two channels for communicating the
 // result of the call.
 type CallTask struct {
 	ID        int64
-	Function  otto.Value
+	Function  vm.Value
 	Args      []interface{}
-	Value     chan otto.Value
+	Value     chan vm.Value
 	Error     chan error
 	SoftError bool
 }
 
-// NewCallTask creates a new CallTask object for a given otto.Value (which
+// NewCallTask creates a new CallTask object for a given value (which
 // should be a function) and set of arguments, creating two buffered channels
 // for the response.
-func NewCallTask(fn otto.Value, args ...interface{}) *CallTask {
+func NewCallTask(fn vm.Value, args ...interface{}) *CallTask {
 	return &CallTask{
 		Function: fn,
 		Args:     args,
-		Value:    make(chan otto.Value, 1),
+		Value:    make(chan vm.Value, 1),
 		Error:    make(chan error, 1),
 	}
 }
@@ -115,13 +114,9 @@ func (c CallTask) Cancel() {}
 // pushing the resultant return value and error (or nil) into the associated
 // channels. If the call results in an error, it will return that error.
 func (c CallTask) Execute(vm *vm.VM, l *loop.Loop) error {
-	// vm is not used directly here, but underlying
-	// FunctionCall in CallTask likely does use it,
-	// so we must to guard it here
-	vm.Lock()
-	defer vm.Unlock()
-
-	v, err := c.Function.Call(otto.NullValue(), c.Args...)
+	// function is called with VM locked, as underlying
+	// FunctionCall likely uses it, and limited by its timeout
+	v, err := vm.CallFunction(c.Function, nil, c.Args...)
 	c.Value <- v
 	c.Error <- err
 
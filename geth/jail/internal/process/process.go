@@ -4,10 +4,10 @@ import (
 	"os"
 	"strings"
 
-	"github.com/robertkrimen/otto"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
 )
 
-func Define(vm *otto.Otto, argv []string) error {
+func Define(vm *vm.VM, argv []string) error {
 	if v, err := vm.Get("process"); err != nil {
 		return err
 	} else if !v.IsUndefined() {
@@ -36,7 +36,13 @@ function Promise(resolver) {
       val = v;
       state = 1;
 
-      setImmediate(fire);
+      // promise without callback, resolved with a plain value, is settled right away,
+      // so that callbacks of its children are not delayed by an extra microtask
+      if (typeof fn !== 'function' && (v === null || (typeof v !== 'object' && typeof v !== 'function'))) {
+        finish(3);
+      } else {
+        queueMicrotask(fire);
+      }
     }
 
     return self;
@@ -50,7 +56,7 @@ function Promise(resolver) {
       val = v;
       state = 2;
 
-      setImmediate(fire);
+      queueMicrotask(fire);
     }
 
     return self;
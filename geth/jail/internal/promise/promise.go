@@ -6,15 +6,17 @@ import (
 	"github.com/status-im/status-go/geth/jail/internal/vm"
 )
 
+// Define defines Promise, unless engine has native one, along with timers and queueMicrotask,
+// callbacks of promises are queued with.
 func Define(vm *vm.VM, l *loop.Loop) error {
-	if v, err := vm.Get("Promise"); err != nil {
+	if err := timers.Define(vm, l); err != nil {
 		return err
-	} else if !v.IsUndefined() {
-		return nil
 	}
 
-	if err := timers.Define(vm, l); err != nil {
+	if v, err := vm.Get("Promise"); err != nil {
 		return err
+	} else if !v.IsUndefined() {
+		return nil
 	}
 
 	s, err := vm.Compile("promise-bundle.js", src)
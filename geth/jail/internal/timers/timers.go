@@ -3,8 +3,6 @@ package timers
 import (
 	"time"
 
-	"github.com/robertkrimen/otto"
-
 	"github.com/status-im/status-go/geth/jail/internal/loop"
 	"github.com/status-im/status-go/geth/jail/internal/vm"
 )
@@ -14,15 +12,15 @@ var minDelay = map[bool]int64{
 	false: 4,
 }
 
-func Define(vm *vm.VM, l *loop.Loop) error {
-	if v, err := vm.Get("setTimeout"); err != nil {
+func Define(jsvm *vm.VM, l *loop.Loop) error {
+	if v, err := jsvm.Get("setTimeout"); err != nil {
 		return err
 	} else if !v.IsUndefined() {
 		return nil
 	}
 
-	newTimer := func(interval bool) func(call otto.FunctionCall) otto.Value {
-		return func(call otto.FunctionCall) otto.Value {
+	newTimer := func(interval bool) vm.Function {
+		return func(call vm.FunctionCall) vm.Value {
 			delay, _ := call.Argument(1).ToInteger()
 			if delay < minDelay[interval] {
 				delay = minDelay[interval]
@@ -39,7 +37,7 @@ func Define(vm *vm.VM, l *loop.Loop) error {
 				l.Ready(t)
 			})
 
-			value, err := call.Otto.ToValue(t)
+			value, err := call.Engine.ToValue(t)
 			if err != nil {
 				panic(err)
 			}
@@ -47,10 +45,10 @@ func Define(vm *vm.VM, l *loop.Loop) error {
 			return value
 		}
 	}
-	vm.Set("setTimeout", newTimer(false))
-	vm.Set("setInterval", newTimer(true))
+	jsvm.Set("setTimeout", newTimer(false))
+	jsvm.Set("setInterval", newTimer(true))
 
-	vm.Set("setImmediate", func(call otto.FunctionCall) otto.Value {
+	jsvm.Set("setImmediate", func(call vm.FunctionCall) vm.Value {
 		t := &timerTask{
 			duration: time.Millisecond,
 			call:     call,
@@ -61,7 +59,7 @@ func Define(vm *vm.VM, l *loop.Loop) error {
 			l.Ready(t)
 		})
 
-		value, err := call.Otto.ToValue(t)
+		value, err := call.Engine.ToValue(t)
 		if err != nil {
 			panic(err)
 		}
@@ -69,7 +67,7 @@ func Define(vm *vm.VM, l *loop.Loop) error {
 		return value
 	})
 
-	clearTimeout := func(call otto.FunctionCall) otto.Value {
+	clearTimeout := func(call vm.FunctionCall) vm.Value {
 		v, _ := call.Argument(0).Export()
 		if t, ok := v.(*timerTask); ok {
 			t.stopped = true
@@ -77,11 +75,22 @@ func Define(vm *vm.VM, l *loop.Loop) error {
 			l.Remove(t)
 		}
 
-		return otto.UndefinedValue()
+		return nil
 	}
-	vm.Set("clearTimeout", clearTimeout)
-	vm.Set("clearInterval", clearTimeout)
-	vm.Set("clearImmediate", clearTimeout)
+	jsvm.Set("clearTimeout", clearTimeout)
+	jsvm.Set("clearInterval", clearTimeout)
+	jsvm.Set("clearImmediate", clearTimeout)
+
+	// microtasks (e.g. callbacks of promises) are called once the current task completes, before the next one
+	jsvm.Set("queueMicrotask", func(call vm.FunctionCall) vm.Value {
+		fn := call.Argument(0)
+		if !fn.IsFunction() {
+			vm.ThrowError("TypeError", "queueMicrotask expects a function")
+		}
+		jsvm.QueueMicrotask(fn)
+
+		return nil
+	})
 
 	return nil
 }
@@ -91,7 +100,7 @@ type timerTask struct {
 	timer    *time.Timer
 	duration time.Duration
 	interval bool
-	call     otto.FunctionCall
+	call     vm.FunctionCall
 	stopped  bool
 }
 
@@ -99,6 +108,11 @@ func (t *timerTask) SetID(id int64) { t.id = id }
 func (t *timerTask) GetID() int64   { return t.id }
 
 func (t *timerTask) Execute(vm *vm.VM, l *loop.Loop) error {
+	if t.stopped {
+		// timer is cleared after it has fired, but before its callback is called
+		return nil
+	}
+
 	var arguments []interface{}
 
 	if len(t.call.ArgumentList) > 2 {
@@ -114,16 +128,15 @@ func (t *timerTask) Execute(vm *vm.VM, l *loop.Loop) error {
 
 	arguments[0] = t.call.ArgumentList[0]
 
-	if _, err := vm.Call(`Function.call.call`, nil, arguments...); err != nil {
-		return err
-	}
+	_, err := vm.Call(`Function.call.call`, nil, arguments...)
 
+	// interval is kept, even if callback has thrown
 	if t.interval && !t.stopped {
 		t.timer.Reset(t.duration)
 		l.Add(t)
 	}
 
-	return nil
+	return err
 }
 
 func (t *timerTask) Cancel() {
@@ -0,0 +1,96 @@
+package vm
+
+import "github.com/status-im/status-go/geth/common"
+
+// Value is a JavaScript value, independent of engine it belongs to.
+type Value = common.JailValue
+
+// Script is a JavaScript code compiled by engine, it can be run only by engines of the same kind.
+type Script = common.JailScript
+
+// Function is a Go function, which may be called by JavaScript code of any engine. It returns
+// nil for undefined. Function throws JavaScript exception by panicking with Throw or ThrowError.
+//
+// Go functions of other signatures are converted by engine itself, and their conversion
+// of arguments differs between engines, so they are to be used only with plain Go types.
+type Function func(call FunctionCall) Value
+
+// FunctionCall is a call of Go function by JavaScript code.
+type FunctionCall struct {
+	This         Value
+	ArgumentList []Value
+	Engine       Engine // engine of calling code, valid only during the call
+}
+
+// Argument returns argument of call at given index, undefined if there is none.
+func (c FunctionCall) Argument(i int) Value {
+	if i < len(c.ArgumentList) {
+		return c.ArgumentList[i]
+	}
+
+	return c.Engine.Undefined()
+}
+
+// Engine is a JavaScript engine, VM executes code with. Engine is not safe for concurrent use.
+//
+// Values passed to engine (e.g. arguments of Call) may be Go values, values of the same
+// engine, and Functions, including ones nested in maps and slices.
+type Engine interface {
+	// Name returns kind of engine (e.g. "otto").
+	Name() string
+
+	// Set sets global variable.
+	Set(name string, value interface{}) error
+	// Get returns global variable, undefined if it is not set.
+	Get(name string) (Value, error)
+	// Call evaluates source (e.g. "JSON.stringify") and calls resulting function with given this
+	// and arguments, "new " prefix of source (e.g. "new Error") calls it as constructor.
+	Call(source string, this interface{}, args ...interface{}) (Value, error)
+	// Run evaluates source, which may be string or Script.
+	Run(src interface{}) (Value, error)
+	// Compile parses source (string or []byte), with optional source map, as a named script.
+	Compile(filename string, src, sourceMap interface{}) (Script, error)
+	// ToValue converts Go value to JavaScript one.
+	ToValue(value interface{}) (Value, error)
+	// Undefined returns undefined value.
+	Undefined() Value
+
+	// Interrupt halts code being executed (or the next one, if there is none), which panics with v.
+	Interrupt(v interface{})
+	// ClearInterrupt drops pending interruption, along with microtasks queued by halted code.
+	ClearInterrupt()
+
+	// QueueMicrotask queues function to be called once code being executed completes.
+	QueueMicrotask(fn Value)
+	// RunMicrotasks calls queued microtasks in order, including the ones queued meanwhile.
+	RunMicrotasks()
+}
+
+// exception is a value, Function panics with to throw JavaScript exception.
+type exception struct {
+	value   interface{} // thrown as is, if name is empty
+	name    string      // class of error (e.g. "TypeError")
+	message string
+}
+
+// Throw throws value (e.g. error message) as JavaScript exception from Function.
+func Throw(value interface{}) {
+	panic(exception{value: value})
+}
+
+// ThrowError throws JavaScript error of given class (e.g. "TypeError") from Function,
+// errors of classes, which are not built in, are instances of Error with given name.
+func ThrowError(name, message string) {
+	panic(exception{name: name, message: message})
+}
+
+// builtinErrors are classes of errors, which are built into every engine.
+var builtinErrors = map[string]bool{
+	"Error":          true,
+	"EvalError":      true,
+	"RangeError":     true,
+	"ReferenceError": true,
+	"SyntaxError":    true,
+	"TypeError":      true,
+	"URIError":       true,
+}
@@ -0,0 +1,355 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dop251/goja"
+	"github.com/dop251/goja/parser"
+)
+
+// EngineGoja is a name of engine, which executes code with goja (ES6 and later, with native promises).
+const EngineGoja = "goja"
+
+// errUnsupportedSource is returned, when source of script is neither string, nor compiled script.
+var errUnsupportedSource = errors.New("unsupported source of script")
+
+// gojaEngine implements Engine with goja. Microtasks are queued as jobs of goja's native promises,
+// which goja runs itself, once control returns to Go.
+type gojaEngine struct {
+	rt             *goja.Runtime
+	queueMicrotask goja.Callable // queues function as a job of resolved promise
+}
+
+// NewGoja creates new engine, which executes code with goja.
+func NewGoja() Engine {
+	rt := goja.New()
+	rt.SetParserOptions(parser.WithDisableSourceMaps)
+	queue, err := rt.RunString(`(function(fn) { Promise.resolve().then(function() { fn(); }); })`)
+	if err != nil {
+		panic(err)
+	}
+	queueMicrotask, _ := goja.AssertFunction(queue)
+
+	return &gojaEngine{rt: rt, queueMicrotask: queueMicrotask}
+}
+
+// gojaScript is a script compiled by goja.
+type gojaScript struct {
+	program  *goja.Program
+	filename string
+}
+
+func (s gojaScript) Filename() string { return s.filename }
+
+func (e *gojaEngine) Name() string {
+	return EngineGoja
+}
+
+func (e *gojaEngine) Set(name string, value interface{}) error {
+	return e.rt.Set(name, e.native(value))
+}
+
+func (e *gojaEngine) Get(name string) (Value, error) {
+	return e.value(e.rt.Get(name)), nil
+}
+
+func (e *gojaEngine) Call(source string, this interface{}, args ...interface{}) (Value, error) {
+	constructor := strings.HasPrefix(source, "new ")
+	source = strings.TrimPrefix(source, "new ")
+	if i := strings.LastIndex(source, "."); this == nil && i > 0 {
+		// like otto, nil this is bound to object of member expression (e.g. JSON of "JSON.stringify")
+		object, err := e.result(e.rt.RunString(source[:i]))
+		if err != nil {
+			return object, err
+		}
+		this = object
+	}
+	fn, err := e.result(e.rt.RunString(source))
+	if err != nil {
+		return fn, err
+	}
+
+	if constructor {
+		object, err := e.rt.New(e.goja(fn), e.gojas(args)...)
+		if err != nil {
+			return e.result(nil, err)
+		}
+		return e.value(object), nil
+	}
+
+	return fn.Call(this, args...)
+}
+
+func (e *gojaEngine) Run(src interface{}) (Value, error) {
+	switch s := src.(type) {
+	case string:
+		return e.result(e.rt.RunString(s))
+	case []byte:
+		return e.result(e.rt.RunString(string(s)))
+	case gojaScript:
+		return e.result(e.rt.RunProgram(s.program))
+	}
+
+	return e.Undefined(), errUnsupportedSource
+}
+
+func (e *gojaEngine) Compile(filename string, src, sourceMap interface{}) (Script, error) {
+	code, err := sourceString(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// source maps are given explicitly, rather than loaded from files named by comments of source
+	option := parser.WithDisableSourceMaps
+	if sourceMap != nil {
+		sm, err := sourceString(sourceMap)
+		if err != nil {
+			return nil, err
+		}
+		option = parser.WithSourceMapLoader(func(string) ([]byte, error) { return []byte(sm), nil })
+	}
+
+	// parsed directly, as goja.Compile drops location of syntax errors
+	ast, err := parser.ParseFile(nil, filename, code, 0, option)
+	if err != nil {
+		return nil, err
+	}
+	program, err := goja.CompileAST(ast, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return gojaScript{program: program, filename: filename}, nil
+}
+
+func (e *gojaEngine) ToValue(value interface{}) (Value, error) {
+	return e.value(e.goja(value)), nil
+}
+
+func (e *gojaEngine) Undefined() Value {
+	return e.value(goja.Undefined())
+}
+
+func (e *gojaEngine) Interrupt(v interface{}) {
+	e.rt.Interrupt(v)
+}
+
+func (e *gojaEngine) ClearInterrupt() {
+	// jobs queued by interrupted code are dropped by goja
+	e.rt.ClearInterrupt()
+}
+
+func (e *gojaEngine) QueueMicrotask(fn Value) {
+	if _, err := e.queueMicrotask(goja.Undefined(), e.goja(fn)); err != nil {
+		panic(err)
+	}
+}
+
+func (e *gojaEngine) RunMicrotasks() {
+	// jobs are run by goja, once control returns to Go
+}
+
+func (e *gojaEngine) value(v goja.Value) Value {
+	if v == nil {
+		v = goja.Undefined()
+	}
+
+	return gojaValue{engine: e, value: v}
+}
+
+// result converts result of execution. Interruption propagates as panic: goja rethrows it
+// as uncatchable exception from nested calls, and RecoverInterrupted unwraps it.
+func (e *gojaEngine) result(v goja.Value, err error) (Value, error) {
+	if interrupted, ok := err.(*goja.InterruptedError); ok {
+		panic(interrupted)
+	}
+
+	return e.value(v), err
+}
+
+// native converts value passed to engine, so that goja can convert it itself.
+func (e *gojaEngine) native(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return goja.Null()
+	case gojaValue:
+		return v.value
+	case Function:
+		return e.function(v)
+	case func(FunctionCall) Value:
+		return e.function(v)
+	case map[string]interface{}:
+		// maps and slices are converted to plain objects and arrays, rather than wrapped
+		object := e.rt.NewObject()
+		for key, item := range v {
+			object.Set(key, e.native(item)) // nolint: errcheck
+		}
+		return object
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = e.native(item)
+		}
+		return e.rt.NewArray(items...)
+	}
+
+	return value
+}
+
+func (e *gojaEngine) goja(value interface{}) goja.Value {
+	return e.rt.ToValue(e.native(value))
+}
+
+func (e *gojaEngine) gojas(values []interface{}) []goja.Value {
+	gojas := make([]goja.Value, len(values))
+	for i, value := range values {
+		gojas[i] = e.goja(value)
+	}
+	return gojas
+}
+
+// function converts Function to goja's function, exceptions thrown by Function are rethrown by goja.
+func (e *gojaEngine) function(fn Function) func(goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		defer func() {
+			if r := recover(); r != nil {
+				if ex, ok := r.(exception); ok {
+					panic(e.exception(ex))
+				}
+				panic(r)
+			}
+		}()
+
+		args := make([]Value, len(call.Arguments))
+		for i, arg := range call.Arguments {
+			args[i] = e.value(arg)
+		}
+
+		result := fn(FunctionCall{This: e.value(call.This), ArgumentList: args, Engine: e})
+		if result == nil {
+			return goja.Undefined()
+		}
+		return e.goja(result)
+	}
+}
+
+func (e *gojaEngine) exception(ex exception) goja.Value {
+	if ex.name == "" {
+		return e.goja(ex.value)
+	}
+
+	class := ex.name
+	if !builtinErrors[class] {
+		class = "Error"
+	}
+	object, err := e.rt.New(e.rt.Get(class), e.rt.ToValue(ex.message))
+	if err != nil {
+		panic(err)
+	}
+	if class != ex.name {
+		object.Set("name", ex.name) // nolint: errcheck
+	}
+	return object
+}
+
+func sourceString(src interface{}) (string, error) {
+	switch s := src.(type) {
+	case string:
+		return s, nil
+	case []byte:
+		return string(s), nil
+	}
+
+	return "", errUnsupportedSource
+}
+
+// gojaValue implements Value with goja.
+type gojaValue struct {
+	engine *gojaEngine
+	value  goja.Value
+}
+
+func (v gojaValue) String() string               { return v.value.String() }
+func (v gojaValue) Export() (interface{}, error) { return v.value.Export(), nil }
+func (v gojaValue) IsDefined() bool              { return !goja.IsUndefined(v.value) }
+func (v gojaValue) IsUndefined() bool            { return goja.IsUndefined(v.value) }
+func (v gojaValue) IsNull() bool                 { return goja.IsNull(v.value) }
+func (v gojaValue) IsBoolean() bool              { return v.kind() == reflect.Bool }
+func (v gojaValue) IsString() bool               { return v.kind() == reflect.String }
+func (v gojaValue) ToBoolean() (bool, error)     { return v.value.ToBoolean(), nil }
+func (v gojaValue) ToInteger() (int64, error)    { return v.value.ToInteger(), nil }
+func (v gojaValue) ToFloat() (float64, error)    { return v.value.ToFloat(), nil }
+func (v gojaValue) ToString() (string, error)    { return v.value.String(), nil }
+
+func (v gojaValue) IsNumber() bool {
+	kind := v.kind()
+	return kind == reflect.Int64 || kind == reflect.Float64
+}
+
+func (v gojaValue) IsObject() bool {
+	_, ok := v.value.(*goja.Object)
+	return ok
+}
+
+func (v gojaValue) IsFunction() bool {
+	_, ok := goja.AssertFunction(v.value)
+	return ok
+}
+
+func (v gojaValue) Class() string {
+	if object, ok := v.value.(*goja.Object); ok {
+		return object.ClassName()
+	}
+
+	return ""
+}
+
+// kind returns kind of Go type of primitive value, invalid for objects, undefined and null.
+func (v gojaValue) kind() reflect.Kind {
+	if _, ok := v.value.(*goja.Object); ok {
+		return reflect.Invalid
+	}
+	if t := v.value.ExportType(); t != nil {
+		return t.Kind()
+	}
+
+	return reflect.Invalid
+}
+
+func (v gojaValue) Get(name string) (Value, error) {
+	object, ok := v.value.(*goja.Object)
+	if !ok {
+		return v.engine.Undefined(), nil
+	}
+
+	return v.engine.value(object.Get(name)), nil
+}
+
+func (v gojaValue) Set(name string, value interface{}) error {
+	object, ok := v.value.(*goja.Object)
+	if !ok {
+		return fmt.Errorf("cannot set property %s of %s", name, v.value)
+	}
+
+	return object.Set(name, v.engine.native(value))
+}
+
+func (v gojaValue) Keys() []string {
+	if object, ok := v.value.(*goja.Object); ok {
+		return object.Keys()
+	}
+
+	return nil
+}
+
+func (v gojaValue) Call(this interface{}, args ...interface{}) (Value, error) {
+	fn, ok := goja.AssertFunction(v.value)
+	if !ok {
+		return v.engine.Undefined(), fmt.Errorf("TypeError: %s is not a function", v.value)
+	}
+
+	return v.engine.result(fn(v.engine.goja(this), v.engine.gojas(args)...))
+}
@@ -0,0 +1,49 @@
+package vm
+
+// QueueMicrotask queues JavaScript function to be called, once code being executed completes
+// (e.g. callbacks of settled promises), before Run, Call or CallFunction returns.
+// Must be called from JavaScript code, with lock held.
+func (vm *VM) QueueMicrotask(fn Value) {
+	vm.engine.QueueMicrotask(fn)
+}
+
+// withMicrotasks wraps fn, draining queue of microtasks once it is completed.
+func (vm *VM) withMicrotasks(fn func() (Value, error)) func() (Value, error) {
+	return func() (Value, error) {
+		value, err := fn()
+		vm.DrainMicrotasks()
+		return value, err
+	}
+}
+
+// DrainMicrotasks calls queued microtasks in order, including the ones queued meanwhile.
+// Must be called with lock held, it is to be called by callers, which execute JavaScript
+// functions bypassing VM.
+func (vm *VM) DrainMicrotasks() {
+	vm.engine.RunMicrotasks()
+}
+
+// microtaskQueue is a queue of microtasks for engines, which have no queue of their own.
+type microtaskQueue struct {
+	tasks []Value
+}
+
+func (q *microtaskQueue) queue(fn Value) {
+	q.tasks = append(q.tasks, fn)
+}
+
+func (q *microtaskQueue) run(this Value) {
+	for len(q.tasks) > 0 {
+		fn := q.tasks[0]
+		q.tasks[0] = nil
+		q.tasks = q.tasks[1:]
+
+		// exception thrown by a microtask affects neither code, which queued it, nor other microtasks
+		fn.Call(this) // nolint: errcheck
+	}
+	q.tasks = nil
+}
+
+func (q *microtaskQueue) clear() {
+	q.tasks = nil
+}
@@ -0,0 +1,255 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/robertkrimen/otto"
+)
+
+// EngineOtto is a name of engine, which executes code with otto (ES5 only).
+const EngineOtto = "otto"
+
+// ottoEngine implements Engine with otto.
+type ottoEngine struct {
+	vm         *otto.Otto
+	microtasks microtaskQueue // otto has no queue of its own
+}
+
+// NewOtto creates new engine, which executes code with otto.
+func NewOtto() Engine {
+	vm := otto.New()
+	vm.Interrupt = make(chan func(), 1) // buffer keeps interruption, until code is executed
+
+	return &ottoEngine{vm: vm}
+}
+
+// ottoScript is a script compiled by otto.
+type ottoScript struct {
+	script   *otto.Script
+	filename string
+}
+
+func (s ottoScript) Filename() string { return s.filename }
+
+func (e *ottoEngine) Name() string {
+	return EngineOtto
+}
+
+func (e *ottoEngine) Set(name string, value interface{}) error {
+	return e.vm.Set(name, e.native(value))
+}
+
+func (e *ottoEngine) Get(name string) (Value, error) {
+	return e.result(e.vm.Get(name))
+}
+
+func (e *ottoEngine) Call(source string, this interface{}, args ...interface{}) (Value, error) {
+	if this != nil {
+		// otto binds nil this to object of member expression (e.g. JSON of "JSON.stringify")
+		this = e.native(this)
+	}
+
+	return e.result(e.vm.Call(source, this, e.natives(args)...))
+}
+
+func (e *ottoEngine) Run(src interface{}) (Value, error) {
+	if script, ok := src.(ottoScript); ok {
+		src = script.script
+	}
+
+	return e.result(e.vm.Run(src))
+}
+
+func (e *ottoEngine) Compile(filename string, src, sourceMap interface{}) (Script, error) {
+	var script *otto.Script
+	var err error
+	if sourceMap == nil {
+		script, err = e.vm.Compile(filename, src)
+	} else {
+		script, err = e.vm.CompileWithSourceMap(filename, src, sourceMap)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ottoScript{script: script, filename: filename}, nil
+}
+
+func (e *ottoEngine) ToValue(value interface{}) (Value, error) {
+	return e.result(e.vm.ToValue(e.native(value)))
+}
+
+func (e *ottoEngine) Undefined() Value {
+	return e.value(otto.UndefinedValue())
+}
+
+func (e *ottoEngine) Interrupt(v interface{}) {
+	select {
+	case e.vm.Interrupt <- func() { panic(v) }:
+	default: // interruption is pending already
+	}
+}
+
+func (e *ottoEngine) ClearInterrupt() {
+	select {
+	case <-e.vm.Interrupt:
+	default:
+	}
+	e.microtasks.clear()
+}
+
+func (e *ottoEngine) QueueMicrotask(fn Value) {
+	e.microtasks.queue(fn)
+}
+
+func (e *ottoEngine) RunMicrotasks() {
+	e.microtasks.run(e.Undefined())
+}
+
+func (e *ottoEngine) value(v otto.Value) Value {
+	return ottoValue{engine: e, value: v}
+}
+
+func (e *ottoEngine) result(v otto.Value, err error) (Value, error) {
+	return e.value(v), err
+}
+
+// native converts value passed to engine, so that otto can convert it itself.
+func (e *ottoEngine) native(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return otto.NullValue()
+	case ottoValue:
+		return v.value
+	case Function:
+		return e.function(v)
+	case func(FunctionCall) Value:
+		return e.function(v)
+	case map[string]interface{}:
+		// maps and slices are converted to plain objects and arrays, rather than wrapped
+		object, err := e.vm.Object(`({})`)
+		if err != nil {
+			panic(err)
+		}
+		for key, item := range v {
+			object.Set(key, e.native(item)) // nolint: errcheck
+		}
+		return object.Value()
+	case []interface{}:
+		array, err := e.vm.Object(`([])`)
+		if err != nil {
+			panic(err)
+		}
+		if len(v) > 0 {
+			array.Call("push", e.natives(v)...) // nolint: errcheck
+		}
+		return array.Value()
+	}
+
+	return value
+}
+
+func (e *ottoEngine) natives(values []interface{}) []interface{} {
+	natives := make([]interface{}, len(values))
+	for i, value := range values {
+		natives[i] = e.native(value)
+	}
+	return natives
+}
+
+func (e *ottoEngine) otto(value interface{}) otto.Value {
+	v, err := e.vm.ToValue(e.native(value))
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// function converts Function to otto's function, exceptions thrown by Function are rethrown by otto.
+func (e *ottoEngine) function(fn Function) func(otto.FunctionCall) otto.Value {
+	return func(call otto.FunctionCall) otto.Value {
+		defer func() {
+			if r := recover(); r != nil {
+				if ex, ok := r.(exception); ok {
+					panic(e.exception(ex))
+				}
+				panic(r)
+			}
+		}()
+
+		args := make([]Value, len(call.ArgumentList))
+		for i, arg := range call.ArgumentList {
+			args[i] = e.value(arg)
+		}
+
+		result := fn(FunctionCall{This: e.value(call.This), ArgumentList: args, Engine: e})
+		if result == nil {
+			return otto.UndefinedValue()
+		}
+		return e.otto(result)
+	}
+}
+
+func (e *ottoEngine) exception(ex exception) otto.Value {
+	if ex.name == "" {
+		return e.otto(ex.value)
+	}
+	if !builtinErrors[ex.name] {
+		return e.vm.MakeCustomError(ex.name, ex.message)
+	}
+
+	v, err := e.vm.Call("new "+ex.name, nil, ex.message)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ottoValue implements Value with otto.
+type ottoValue struct {
+	engine *ottoEngine
+	value  otto.Value
+}
+
+func (v ottoValue) String() string               { return v.value.String() }
+func (v ottoValue) Export() (interface{}, error) { return v.value.Export() }
+func (v ottoValue) IsDefined() bool              { return v.value.IsDefined() }
+func (v ottoValue) IsUndefined() bool            { return v.value.IsUndefined() }
+func (v ottoValue) IsNull() bool                 { return v.value.IsNull() }
+func (v ottoValue) IsBoolean() bool              { return v.value.IsBoolean() }
+func (v ottoValue) IsNumber() bool               { return v.value.IsNumber() }
+func (v ottoValue) IsString() bool               { return v.value.IsString() }
+func (v ottoValue) IsObject() bool               { return v.value.IsObject() }
+func (v ottoValue) IsFunction() bool             { return v.value.IsFunction() }
+func (v ottoValue) Class() string                { return v.value.Class() }
+func (v ottoValue) ToBoolean() (bool, error)     { return v.value.ToBoolean() }
+func (v ottoValue) ToInteger() (int64, error)    { return v.value.ToInteger() }
+func (v ottoValue) ToFloat() (float64, error)    { return v.value.ToFloat() }
+func (v ottoValue) ToString() (string, error)    { return v.value.ToString() }
+func (v ottoValue) Call(this interface{}, args ...interface{}) (Value, error) {
+	return v.engine.result(v.value.Call(v.engine.otto(this), v.engine.natives(args)...))
+}
+
+func (v ottoValue) Get(name string) (Value, error) {
+	if !v.value.IsObject() {
+		return v.engine.Undefined(), nil
+	}
+
+	return v.engine.result(v.value.Object().Get(name))
+}
+
+func (v ottoValue) Set(name string, value interface{}) error {
+	if !v.value.IsObject() {
+		return fmt.Errorf("cannot set property %s of %s", name, v.value)
+	}
+
+	return v.value.Object().Set(name, v.engine.native(value))
+}
+
+func (v ottoValue) Keys() []string {
+	if !v.value.IsObject() {
+		return nil
+	}
+
+	return v.value.Object().Keys()
+}
@@ -0,0 +1,69 @@
+package vm
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Usage is an amount of resources consumed by JavaScript code executed by VM.
+type Usage struct {
+	CPUTime    time.Duration // total duration of execution
+	HeapGrowth int64         // growth of heap during execution (in bytes), if measured (see MeasureHeap)
+}
+
+// MeasureHeap enables measuring of heap growth during execution. Heap is shared by all VMs,
+// so growth is approximate, and reading its size stops the world, so it is disabled by default.
+func (vm *VM) MeasureHeap(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&vm.measureHeap, v)
+}
+
+// SetUsageHandler sets function, which is called with total usage after every execution.
+// It is called with VM's lock held, so it must not use VM.
+func (vm *VM) SetUsageHandler(handler func(Usage)) {
+	vm.usageHandler.Store(handler)
+}
+
+// Usage returns resources consumed by JavaScript code executed by VM so far.
+func (vm *VM) Usage() Usage {
+	return Usage{
+		CPUTime:    time.Duration(atomic.LoadInt64(&vm.cpuTime)),
+		HeapGrowth: atomic.LoadInt64(&vm.heapGrowth),
+	}
+}
+
+// Track starts measuring resources consumed by code executed, until returned function is called.
+// It is to be used by callers, which execute JavaScript functions bypassing VM, with lock held.
+func (vm *VM) Track() (stop func()) {
+	measureHeap := atomic.LoadInt32(&vm.measureHeap) == 1
+	var heapBefore uint64
+	if measureHeap {
+		heapBefore = readHeapSize()
+	}
+	start := time.Now()
+
+	return func() {
+		atomic.AddInt64(&vm.cpuTime, int64(time.Since(start)))
+		if measureHeap {
+			growth := atomic.LoadInt64(&vm.heapGrowth) + int64(readHeapSize()) - int64(heapBefore)
+			if growth < 0 {
+				growth = 0 // memory released by GC is not credited
+			}
+			atomic.StoreInt64(&vm.heapGrowth, growth)
+		}
+
+		if handler, ok := vm.usageHandler.Load().(func(Usage)); ok && handler != nil {
+			handler(vm.Usage())
+		}
+	}
+}
+
+func readHeapSize() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
@@ -1,79 +1,190 @@
 package vm
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/robertkrimen/otto"
+	"github.com/dop251/goja"
 )
 
+// errors
+var (
+	ErrInterrupted = errors.New("execution has been interrupted")
+	ErrTimeout     = errors.New("execution has timed out")
+)
+
+// interrupted is a value, interrupted execution panics with.
+type interrupted struct {
+	err error
+}
+
 // VM implements concurrency safe wrapper to
-// otto's VM object.
+// JavaScript engine.
 type VM struct {
 	sync.Mutex
 
-	vm *otto.Otto
+	engine  Engine
+	timeout int64 // time.Duration, limits execution of Run, Call and CallFunction (0 means no limit)
+
+	cpuTime      int64        // time.Duration, total duration of execution (see Usage)
+	heapGrowth   int64        // total growth of heap during execution, in bytes
+	measureHeap  int32        // 1, if heap growth is measured
+	usageHandler atomic.Value // func(Usage), called after every execution
 }
 
 // New creates new instance of VM.
-func New(vm *otto.Otto) *VM {
+func New(engine Engine) *VM {
 	return &VM{
-		vm: vm,
+		engine: engine,
 	}
 }
 
+// Engine returns name of JavaScript engine of VM (e.g. "otto").
+func (vm *VM) Engine() string {
+	return vm.engine.Name()
+}
+
+// SetTimeout limits duration of execution of JavaScript code with Run, Call and CallFunction,
+// which return ErrTimeout, once it is exceeded. VM remains usable afterwards.
+func (vm *VM) SetTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&vm.timeout, int64(timeout))
+}
+
+// Interrupt halts JavaScript code, which is being executed (or the next one, if there is none),
+// without waiting for the lock. Halted Run, Call or CallFunction returns ErrInterrupted.
+func (vm *VM) Interrupt() {
+	vm.interrupt(ErrInterrupted)
+}
+
+func (vm *VM) interrupt(err error) {
+	vm.engine.Interrupt(interrupted{err})
+}
+
+// RecoverInterrupted turns panic of interrupted execution into error (ErrInterrupted or ErrTimeout),
+// it is to be deferred by callers, which execute JavaScript functions bypassing VM.
+func RecoverInterrupted(err *error) {
+	if r := recover(); r != nil {
+		if e, ok := r.(*goja.InterruptedError); ok {
+			r = e.Value()
+		}
+		i, ok := r.(interrupted)
+		if !ok {
+			panic(r)
+		}
+		*err = i.err
+	}
+}
+
+// guard executes fn, which runs JavaScript code, and microtasks queued by it, interrupting them
+// after timeout of VM. Must be called with lock held.
+func (vm *VM) guard(fn func() (Value, error)) (value Value, err error) {
+	defer func() {
+		if err == ErrInterrupted || err == ErrTimeout {
+			value = vm.engine.Undefined()
+			vm.engine.ClearInterrupt() // microtasks are dropped along with interrupted code
+		}
+	}()
+	defer RecoverInterrupted(&err)
+	defer vm.Track()()
+
+	fn = vm.withMicrotasks(fn)
+
+	timeout := time.Duration(atomic.LoadInt64(&vm.timeout))
+	if timeout <= 0 {
+		return fn()
+	}
+
+	var mu sync.Mutex
+	done := false
+	timer := time.AfterFunc(timeout, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			vm.interrupt(ErrTimeout)
+		}
+	})
+	defer func() {
+		mu.Lock()
+		done = true
+		mu.Unlock()
+		if !timer.Stop() {
+			// code has completed after timer had fired, drop interruption, so that it doesn't halt next execution
+			vm.engine.ClearInterrupt()
+		}
+	}()
+
+	return fn()
+}
+
 // Set sets the value to be keyed by the provided keyname.
 func (vm *VM) Set(key string, val interface{}) error {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.Set(key, val)
+	return vm.engine.Set(key, val)
 }
 
-// Get returns the giving key's otto.Value from the underline otto vm.
-func (vm *VM) Get(key string) (otto.Value, error) {
+// Get returns the giving key's value from the underlying engine.
+func (vm *VM) Get(key string) (Value, error) {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.Get(key)
+	return vm.engine.Get(key)
 }
 
 // Call attempts to call the internal call function for the giving response associated with the
 // proper values.
-func (vm *VM) Call(item string, this interface{}, args ...interface{}) (otto.Value, error) {
+func (vm *VM) Call(item string, this interface{}, args ...interface{}) (Value, error) {
+	vm.Lock()
+	defer vm.Unlock()
+
+	return vm.guard(func() (Value, error) {
+		return vm.engine.Call(item, this, args...)
+	})
+}
+
+// CallFunction calls JavaScript function value (e.g. callback) with given arguments.
+func (vm *VM) CallFunction(fn Value, this interface{}, args ...interface{}) (Value, error) {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.Call(item, this, args...)
+	return vm.guard(func() (Value, error) {
+		return fn.Call(this, args...)
+	})
 }
 
-// Run evaluates JS source, which may be string or otto.Script variable.
-func (vm *VM) Run(src interface{}) (otto.Value, error) {
+// Run evaluates JS source, which may be string or Script.
+func (vm *VM) Run(src interface{}) (Value, error) {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.Run(src)
+	return vm.guard(func() (Value, error) {
+		return vm.engine.Run(src)
+	})
 }
 
-// Compile parses given source and returns otto.Script.
-func (vm *VM) Compile(filename string, src interface{}) (*otto.Script, error) {
+// Compile parses given source and returns Script.
+func (vm *VM) Compile(filename string, src interface{}) (Script, error) {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.Compile(filename, src)
+	return vm.engine.Compile(filename, src, nil)
 }
 
-// CompileWithSourceMap parses given source with source map and returns otto.Script.
-func (vm *VM) CompileWithSourceMap(filename string, src, sm interface{}) (*otto.Script, error) {
+// CompileWithSourceMap parses given source with source map and returns Script.
+func (vm *VM) CompileWithSourceMap(filename string, src, sm interface{}) (Script, error) {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.CompileWithSourceMap(filename, src, sm)
+	return vm.engine.Compile(filename, src, sm)
 }
 
-// ToValue will convert an interface{} value to a value digestible by otto/JavaScript.
-func (vm *VM) ToValue(value interface{}) (otto.Value, error) {
+// ToValue will convert an interface{} value to a value digestible by JavaScript.
+func (vm *VM) ToValue(value interface{}) (Value, error) {
 	vm.Lock()
 	defer vm.Unlock()
 
-	return vm.vm.ToValue(value)
+	return vm.engine.ToValue(value)
 }
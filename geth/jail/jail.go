@@ -1,15 +1,21 @@
 package jail
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/robertkrimen/otto"
+	"github.com/pborman/uuid"
 	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/jail/internal/vm"
 	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/signal"
 	"github.com/status-im/status-go/static"
 )
 
@@ -17,19 +23,58 @@ var (
 	// FIXME(tiabc): Get rid of this global variable. Move it to a constructor or initialization.
 	web3JSCode = static.MustAsset("scripts/web3.js")
 
-	ErrInvalidJail = errors.New("jail environment is not properly initialized")
+	// web3InitJSCode creates web3 instance connected to jeth
+	web3InitJSCode = `
+	var Web3 = require('web3');
+	var web3 = new Web3(jeth);
+	var Bignumber = require("bignumber.js");
+	function bn(val){
+		return new Bignumber(val);
+	}
+	`
+
+	ErrInvalidJail    = errors.New("jail environment is not properly initialized")
+	ErrBundleRejected = errors.New("bundle rejected by static analysis")
+	ErrRateLimited    = errors.New("too many RPC requests, rate limit exceeded")
 )
 
 // Jail represents jailed environment inside of which we hold multiple cells.
 // Each cell is a separate JavaScript VM.
 type Jail struct {
 	nodeManager common.NodeManager
-	baseJSCode  string // JavaScript used to initialize all new cells with
+
+	baseJSMx      sync.RWMutex
+	baseJSCode    string // JavaScript used to initialize all new cells with
+	baseJSVersion uint64 // incremented on every change of baseJSCode (see ParseVersion)
+
+	analysisConfig AnalysisConfig // static analysis of bundles, before they are parsed
 
 	cellsMx sync.RWMutex
 	cells   map[string]*Cell // jail supports running many isolated instances of jailed runtime
+	usage   uint64           // counter of cell accesses, to find least recently used ones
+
+	restoreMx sync.Mutex // serializes restoring of evicted cells
+	cellStore CellStore  // snapshots of evicted cells
+
+	heap *heapMonitor // triggers recycling of cells, when heap grows too big
+
+	storageMx sync.Mutex
+	storage   *storage // localStorage of cells, opened on first access
+
+	fetchMx         sync.RWMutex
+	fetchWhitelists map[string][]string // per-cell overrides of params.JailConfig.FetchWhitelist
+
+	permissionsMx sync.RWMutex
+	permissions   map[string]*common.JailPermissions // restrictions of RPC requests of cells
+
+	modulesMx sync.RWMutex
+	modules   map[string]*module // shared modules, cells load with require()
 
-	vm *vm.VM // vm for internal otto related tasks (see Send method)
+	logsMx sync.Mutex
+	logs   map[string]*logBuffer // the last console entries of cells
+
+	queuesMx sync.Mutex
+	queues   map[string]*callQueue // calls of cells, which are executed or wait for execution
 }
 
 // New returns new Jail environment with the associated NodeManager.
@@ -38,30 +83,88 @@ func New(nodeManager common.NodeManager) *Jail {
 	return &Jail{
 		nodeManager: nodeManager,
 		cells:       make(map[string]*Cell),
-		vm:          vm.New(otto.New()),
+		cellStore:   newMemoryCellStore(),
+		heap:        newHeapMonitor(),
+
+		analysisConfig:  DefaultAnalysisConfig,
+		fetchWhitelists: make(map[string][]string),
+		permissions:     make(map[string]*common.JailPermissions),
+		modules:         make(map[string]*module),
+		logs:            make(map[string]*logBuffer),
+		queues:          make(map[string]*callQueue),
 	}
 }
 
 // BaseJS allows to setup initial JavaScript to be loaded on each jail.Parse().
+// Cells, which have been parsed already, are not affected (see ReloadBaseJS).
 func (jail *Jail) BaseJS(js string) {
-	jail.baseJSCode = js
+	jail.baseJSMx.Lock()
+	defer jail.baseJSMx.Unlock()
+
+	if js != jail.baseJSCode {
+		jail.baseJSCode = js
+		jail.baseJSVersion++
+	}
+}
+
+// baseJS returns base JavaScript along with its version.
+func (jail *Jail) baseJS() (string, uint64) {
+	jail.baseJSMx.RLock()
+	defer jail.baseJSMx.RUnlock()
+
+	return jail.baseJSCode, jail.baseJSVersion
+}
+
+// ParseVersion returns version of base JavaScript, cells are parsed with. It changes on every
+// change of base JavaScript, so that cells parsed with stale one can be found (see Cells).
+func (jail *Jail) ParseVersion() uint64 {
+	_, version := jail.baseJS()
+	return version
+}
+
+// SetAnalysisConfig changes checks of static analysis, which bundles go through on jail.Parse().
+func (jail *Jail) SetAnalysisConfig(config AnalysisConfig) {
+	jail.analysisConfig = config
+}
+
+// SetCellStore changes store, which snapshots of evicted cells (see params.JailConfig.MaxCells)
+// are saved to. By default, they are kept in memory.
+func (jail *Jail) SetCellStore(store CellStore) {
+	jail.cellStore = store
 }
 
 // NewCell initializes and returns a new jail cell.
+// If number of cells exceeds configured limit, least recently used ones are evicted.
 func (jail *Jail) NewCell(chatID string) (common.JailCell, error) {
 	if jail == nil {
 		return nil, ErrInvalidJail
 	}
 
-	vm := otto.New()
+	config := jail.jailConfig()
+
+	engine, err := newEngine(config.Engine)
+	if err != nil {
+		return nil, err
+	}
 
-	cell, err := newCell(chatID, vm)
+	cell, err := newCell(chatID, engine)
 	if err != nil {
 		return nil, err
 	}
 
+	jail.heap.setLimit(config.MaxHeapSize)
+	cell.SetTimeout(time.Duration(config.ExecTimeout) * time.Millisecond)
+	cell.setFetchOptions(jail.fetchOptions(chatID, config))
+	jail.enforceBudget(cell, config)
+	if err := cell.Set("require", jail.makeRequireHandler(cell)); err != nil {
+		cell.Stop()
+		return nil, err
+	}
+
 	jail.cellsMx.Lock()
 	jail.cells[chatID] = cell
+	jail.touch(cell)
+	jail.evictCells(config.MaxCells, chatID)
 	jail.cellsMx.Unlock()
 
 	return cell, nil
@@ -77,21 +180,198 @@ func (jail *Jail) Stop() {
 	}
 	// TODO(tiabc): Move this initialisation to a proper place.
 	jail.cells = make(map[string]*Cell)
+
+	jail.closeStorage()
 }
 
-// Cell returns the existing instance of Cell.
-func (jail *Jail) Cell(chatID string) (common.JailCell, error) {
+// RemoveCell stops cell, halting JavaScript code it executes (and its pending timers and callbacks),
+// and removes it, along with its snapshot, if cell has been evicted.
+func (jail *Jail) RemoveCell(chatID string) error {
+	// cell must not be restored, while being removed
+	jail.restoreMx.Lock()
+	defer jail.restoreMx.Unlock()
+
+	jail.cellsMx.Lock()
+	cell, ok := jail.cells[chatID]
+	delete(jail.cells, chatID)
+	jail.cellsMx.Unlock()
+
+	if ok {
+		cell.Interrupt()
+		cell.Stop()
+	}
+	jail.removeLogs(chatID)
+	jail.removeCallQueue(chatID)
+
+	snapshot, err := jail.cellStore.LoadCell(chatID)
+	if err != nil {
+		return err
+	}
+	if snapshot != nil {
+		if err := jail.cellStore.DeleteCell(chatID); err != nil {
+			return err
+		}
+	} else if !ok {
+		return fmt.Errorf("cell[%s] doesn't exist", chatID)
+	}
+
+	log.Info("Removed jail cell", "chatID", chatID)
+	return nil
+}
+
+// Cells returns stats of live cells (evicted ones are not included), ordered by chatID.
+// Engines don't account memory of VMs, so size of bundle, cell has been parsed with, is reported instead.
+func (jail *Jail) Cells() []common.JailCellInfo {
 	jail.cellsMx.RLock()
 	defer jail.cellsMx.RUnlock()
 
-	cell, ok := jail.cells[chatID]
-	if !ok {
+	cells := make([]common.JailCellInfo, 0, len(jail.cells))
+	for _, cell := range jail.cells {
+		cells = append(cells, common.JailCellInfo{
+			ChatID:       cell.id,
+			StartedAt:    cell.createdAt.Unix(),
+			Uptime:       int64(time.Since(cell.createdAt) / time.Second),
+			Calls:        atomic.LoadUint64(&cell.calls),
+			BundleSize:   len(cell.bundle),
+			CPUTime:      int64(cell.Usage().CPUTime / time.Millisecond),
+			ParseVersion: cell.parseVersion,
+		})
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i].ChatID < cells[j].ChatID })
+
+	return cells
+}
+
+// Cell returns the existing instance of Cell. Evicted cell is restored from its snapshot.
+// Methods of returned cell hold it as calls do, so that it's not recycled while they run.
+func (jail *Jail) Cell(chatID string) (common.JailCell, error) {
+	if _, err := jail.liveCell(chatID); err != nil {
+		return nil, err
+	}
+
+	return &heldCell{jail: jail, chatID: chatID}, nil
+}
+
+// liveCell implements Cell.
+func (jail *Jail) liveCell(chatID string) (*Cell, error) {
+	if cell, ok := jail.cell(chatID); ok {
+		return cell, nil
+	}
+
+	cell, err := jail.restoreCell(chatID)
+	if err != nil {
+		return nil, err
+	}
+	if cell == nil {
 		return nil, fmt.Errorf("cell[%s] doesn't exist", chatID)
 	}
 
 	return cell, nil
 }
 
+// busyCell returns live cell, holding it for reading, so that it's not recycled, until call completes.
+func (jail *Jail) busyCell(chatID string) (*Cell, error) {
+	for {
+		cell, err := jail.liveCell(chatID)
+		if err != nil {
+			return nil, err
+		}
+
+		cell.callsMx.RLock()
+		if !cell.stopped() {
+			return cell, nil
+		}
+		// cell has been recycled meanwhile, call is made by the one it's re-created as
+		cell.callsMx.RUnlock()
+	}
+}
+
+// heldCell implements common.JailCell, running each method with live cell held by busyCell.
+type heldCell struct {
+	jail   *Jail
+	chatID string
+}
+
+// Set implements common.JailCell.
+func (c *heldCell) Set(key string, val interface{}) error {
+	cell, err := c.jail.busyCell(c.chatID)
+	if err != nil {
+		return err
+	}
+	defer cell.callsMx.RUnlock()
+
+	return cell.Set(key, val)
+}
+
+// Get implements common.JailCell.
+func (c *heldCell) Get(key string) (common.JailValue, error) {
+	cell, err := c.jail.busyCell(c.chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer cell.callsMx.RUnlock()
+
+	return cell.Get(key)
+}
+
+// Run implements common.JailCell.
+func (c *heldCell) Run(source interface{}) (common.JailValue, error) {
+	cell, err := c.jail.busyCell(c.chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer cell.callsMx.RUnlock()
+
+	return cell.Run(source)
+}
+
+// Compile implements common.JailCell.
+func (c *heldCell) Compile(filename string, src interface{}) (common.JailScript, error) {
+	cell, err := c.jail.busyCell(c.chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer cell.callsMx.RUnlock()
+
+	return cell.Compile(filename, src)
+}
+
+// Call implements common.JailCell.
+func (c *heldCell) Call(item string, this interface{}, args ...interface{}) (common.JailValue, error) {
+	cell, err := c.jail.busyCell(c.chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer cell.callsMx.RUnlock()
+
+	return cell.Call(item, this, args...)
+}
+
+// Stop implements common.JailCell.
+func (c *heldCell) Stop() {
+	if cell, ok := c.jail.cell(c.chatID); ok {
+		cell.Stop()
+	}
+}
+
+// cell returns live cell, marking it as recently used.
+func (jail *Jail) cell(chatID string) (*Cell, bool) {
+	jail.cellsMx.RLock()
+	defer jail.cellsMx.RUnlock()
+
+	cell, ok := jail.cells[chatID]
+	if ok {
+		jail.touch(cell)
+	}
+
+	return cell, ok
+}
+
+// touch marks cell as the most recently used one.
+func (jail *Jail) touch(cell *Cell) {
+	atomic.StoreUint64(&cell.used, atomic.AddUint64(&jail.usage, 1))
+}
+
 // Parse creates a new jail cell context, with the given chatID as identifier.
 // New context executes provided JavaScript code, right after the initialization.
 func (jail *Jail) Parse(chatID, js string) string {
@@ -99,142 +379,308 @@ func (jail *Jail) Parse(chatID, js string) string {
 		return makeError(ErrInvalidJail.Error())
 	}
 
-	cell, err := jail.Cell(chatID)
-	if err != nil {
-		if _, mkerr := jail.NewCell(chatID); mkerr != nil {
-			return makeError(mkerr.Error())
+	res, _ := jail.parse(chatID, js)
+	return res
+}
+
+// parse implements Parse, also reporting whether bundle has been parsed successfully.
+func (jail *Jail) parse(chatID, js string) (string, bool) {
+	diagnostics := analyzeBundle(js, jail.analysisConfig)
+	if len(diagnostics) > 0 {
+		log.Warn("Static analysis of bundle found issues", "chatID", chatID, "diagnostics", diagnostics)
+	}
+	if hasErrors(diagnostics) {
+		return makeAnalysisError(diagnostics), false
+	}
+
+	cell, ok := jail.cell(chatID)
+	if !ok {
+		if _, err := jail.NewCell(chatID); err != nil {
+			return makeError(err.Error()), false
 		}
 
-		cell, _ = jail.Cell(chatID)
+		if cell, ok = jail.cell(chatID); !ok {
+			return makeError(fmt.Sprintf("cell[%s] doesn't exist", chatID)), false
+		}
 	}
 
 	// init jeth and its handlers
-	if err = cell.Set("jeth", struct{}{}); err != nil {
-		return makeError(err.Error())
+	if err := cell.Set("jeth", map[string]interface{}{}); err != nil {
+		return makeError(err.Error()), false
 	}
 
-	if err = registerHandlers(jail, cell, chatID); err != nil {
-		return makeError(err.Error())
+	if err := registerHandlers(jail, cell, chatID); err != nil {
+		return makeError(err.Error()), false
 	}
 
-	initJs := jail.baseJSCode + ";"
-	if _, err = cell.Run(initJs); err != nil {
-		return makeError(err.Error())
+	// every part is compiled as a separate named script,
+	// so that location of errors could be reported relative to it
+	type namedScript struct {
+		filename string
+		src      string
 	}
-
-	jjs := string(web3JSCode) + `
-	var Web3 = require('web3');
-	var web3 = new Web3(jeth);
-	var Bignumber = require("bignumber.js");
-        function bn(val){
-            return new Bignumber(val);
-        }
-	` + js + "; var catalog = JSON.stringify(_status_catalog);"
-	if _, err = cell.Run(jjs); err != nil {
-		return makeError(err.Error())
+	baseJS, version := jail.baseJS()
+	scripts := []namedScript{
+		{"base.js", baseJS},
+		{"web3.js", string(web3JSCode)},
+		{"init.js", web3InitJSCode},
+		{"shh.js", shhJSCode},
+	}
+	if jail.jailConfig().EIP1193Provider {
+		scripts = append(scripts, namedScript{"provider.js", providerJSCode})
+	}
+	scripts = append(scripts,
+		namedScript{bundleFilename, js},
+		namedScript{"catalog.js", "var catalog = JSON.stringify(_status_catalog);"},
+	)
+	for _, script := range scripts {
+		if err := runScript(cell, script.filename, script.src); err != nil {
+			return makeJSError(chatID, err), false
+		}
 	}
 
 	res, err := cell.Get("catalog")
 	if err != nil {
-		return makeError(err.Error())
+		return makeError(err.Error()), false
 	}
+	cell.bundle = js
+	cell.parseVersion = version
 
-	return makeResult(res.String(), err)
+	return makeResult(res.String(), err), true
+}
+
+// runScript compiles and runs JavaScript source as a named script.
+// Inline source map of the source (if any) is used to map locations of errors.
+func runScript(cell common.JailCell, filename, src string) error {
+	script, err := cell.Compile(filename, src)
+	if err != nil {
+		return err
+	}
+
+	_, err = cell.Run(script)
+	return err
 }
 
 // Call executes the `call` function w/i a jail cell context identified by the chatID.
+// If params.JailConfig.OrderedCalls is set, calls of cell are executed in order they have
+// been made, and responses are tagged with sequence numbers of calls ("seq" field).
 func (jail *Jail) Call(chatID, this, args string) string {
-	cell, err := jail.Cell(chatID)
+	queue := jail.callQueue(chatID)
+	return jail.callQueued(queue, queue.reserve(), chatID, this, args)
+}
+
+// CallResultEvent is a signal sent with response of call made with CallAsync.
+type CallResultEvent struct {
+	CallID   string          `json:"call_id"`
+	ChatID   string          `json:"chat_id"`
+	Response json.RawMessage `json:"response"` // the same as returned by Call
+}
+
+// CallAsync queues call of the `call` function w/i a jail cell context identified by the chatID,
+// and returns ID of call immediately. Response is sent as EventJailCallResult signal.
+func (jail *Jail) CallAsync(chatID, this, args string) string {
+	callID := uuid.New()
+
+	// sequence number is reserved right away, so that order of calls is kept
+	queue := jail.callQueue(chatID)
+	seq := queue.reserve()
+
+	go func() {
+		response := jail.callQueued(queue, seq, chatID, this, args)
+
+		event := CallResultEvent{
+			CallID:   callID,
+			ChatID:   chatID,
+			Response: json.RawMessage(response),
+		}
+		if !json.Valid(event.Response) {
+			// bundle has returned malformed JSON, it is passed as a string
+			event.Response, _ = json.Marshal(response)
+		}
+		signal.Send(signal.Envelope{
+			Type:  EventJailCallResult,
+			Event: event,
+		})
+	}()
+
+	return callID
+}
+
+// callQueued executes call, which has been queued with a given sequence number.
+func (jail *Jail) callQueued(queue *callQueue, seq uint64, chatID, this, args string) string {
+	defer queue.leave()
+
+	ordered := jail.jailConfig().OrderedCalls
+	if ordered {
+		queue.wait(seq)
+	}
+
+	response := jail.call(chatID, this, args)
+	if ordered {
+		response = withSequence(response, seq)
+	}
+	return response
+}
+
+// call implements Call, returning untagged response.
+func (jail *Jail) call(chatID, this, args string) string {
+	cell, err := jail.busyCell(chatID)
 	if err != nil {
 		return makeError(err.Error())
 	}
 
 	res, err := cell.Call("call", nil, this, args)
+	cell.callsMx.RUnlock()
+	jail.callCompleted(cell)
+	if err != nil {
+		return makeJSError(chatID, err)
+	}
 
 	return makeResult(res.String(), err)
 }
 
-// Send is a wrapper for executing RPC calls from within Otto VM.
-// It uses own jail's VM instance instead of cell's one to
-// increase safety of cell's vm usage.
-// TODO(divan): investigate if it's possible to do conversions
-// withouth involving otto code at all.
+// Send is a wrapper for executing RPC calls from within JavaScript VM.
+// nolint: errcheck, unparam
+func (jail *Jail) Send(call vm.FunctionCall) vm.Value {
+	return jail.send(context.Background(), call)
+}
+
+// send implements Send, cancelling RPC call, once ctx is done (e.g. cell is stopped).
 // nolint: errcheck, unparam
-func (jail *Jail) Send(call otto.FunctionCall) otto.Value {
-	request, err := jail.vm.Call("JSON.stringify", nil, call.Argument(0))
+func (jail *Jail) send(ctx context.Context, call vm.FunctionCall) vm.Value {
+	response, err := jail.sendRequest(ctx, stringifyRequest(call))
+	if err != nil {
+		throwJSException(err)
+	}
+
+	respValue, err := call.Engine.ToValue(response)
+	if err != nil {
+		throwJSException(fmt.Errorf("Error converting result to JavaScript value: %s", err))
+	}
+
+	return respValue
+}
+
+// stringifyRequest serializes request of jeth.send() call with engine of calling cell.
+func stringifyRequest(call vm.FunctionCall) string {
+	request, err := call.Engine.Call("JSON.stringify", nil, call.Argument(0))
 	if err != nil {
 		throwJSException(err)
 	}
 
-	rpc := jail.nodeManager.RPCClient()
+	return request.String()
+}
+
+// sendRequest sends serialized RPC request and returns unmarshalled response. It doesn't use
+// engine of calling cell, so it may be called without cell being locked.
+func (jail *Jail) sendRequest(ctx context.Context, request string) (interface{}, error) {
+	client := jail.nodeManager.RPCClient()
 	// TODO(divan): remove this check as soon as jail cells have
 	// proper cancellation mechanism implemented.
-	if rpc == nil {
-		throwJSException(fmt.Errorf("Error getting RPC client. Node stopped?"))
+	if client == nil {
+		return nil, fmt.Errorf("Error getting RPC client. Node stopped?")
 	}
-	response := rpc.CallRaw(request.String())
 
-	// unmarshal response to pass to otto
+	// call is traced with request ID, so that it could be correlated with resulting signals
+	ctx, requestID := rpc.WithRequestID(ctx)
+	log.Debug("RPC call of jail cell", "requestID", requestID, "chatID", common.OriginFromContext(ctx))
+
+	// failures are reported in response, web3 handles them
+	response, err := client.CallRaw(ctx, request)
+	if err != nil {
+		log.Debug("RPC call of jail cell failed", "requestID", requestID, "error", err)
+	}
+
+	// unmarshal response to pass to JavaScript
 	var resp interface{}
 	err = json.Unmarshal([]byte(response), &resp)
 	if err != nil {
-		throwJSException(fmt.Errorf("Error unmarshalling result: %s", err))
+		return nil, fmt.Errorf("Error unmarshalling result: %s", err)
 	}
-	respValue, err := jail.vm.ToValue(resp)
-	if err != nil {
-		throwJSException(fmt.Errorf("Error converting result to Otto's value: %s", err))
+
+	return resp, nil
+}
+
+// allowRequest checks rate limit of RPC requests of cell (see params.JailConfig),
+// notifying application, once cell starts being throttled.
+func (jail *Jail) allowRequest(cell *Cell) bool {
+	cell.limiterOnce.Do(func() {
+		config, err := jail.nodeManager.NodeConfig()
+		if err != nil {
+			log.Warn("Cannot obtain jail config, RPC requests are not limited", "chatID", cell.id, "error", err)
+			return
+		}
+		cell.limiter = newRateLimiter(config.JailConfig)
+	})
+
+	if cell.limiter == nil {
+		return true
 	}
 
-	return respValue
+	allowed, throttled := cell.limiter.allow()
+	if throttled {
+		log.Warn("RPC requests of jail cell are rate limited", "chatID", cell.id)
+		signal.Send(signal.Envelope{
+			Type: EventRateLimited,
+			Event: RateLimitedEvent{
+				ChatID: cell.id,
+				Limit:  cell.limiter.rate,
+			},
+		})
+	}
+
+	return allowed
+}
+
+// rateLimitedResponse returns error response to a rejected request.
+func (jail *Jail) rateLimitedResponse(call vm.FunctionCall) vm.Value {
+	return newErrorResponseValue(call.Engine, rpc.ErrCodeLimitExceeded, ErrRateLimited.Error(), requestID(call))
 }
 
-func newErrorResponse(msg string, id interface{}) map[string]interface{} {
-	// Bundle the error into a JSON RPC call response
+// requestID returns id of request of jeth.send() call, nil for batch requests,
+// which get a single error response.
+func requestID(call vm.FunctionCall) (id interface{}) {
+	if request := call.Argument(0); request.IsObject() && request.Class() != "Array" {
+		if value, err := request.Get("id"); err == nil {
+			id, _ = value.Export()
+		}
+	}
+	return id
+}
+
+// newErrorResponse bundles the error into a JSON RPC call response, code is one of rpc.ErrCode* values.
+func newErrorResponse(code int, msg string, id interface{}) map[string]interface{} {
 	return map[string]interface{}{
 		"jsonrpc": "2.0",
 		"id":      id,
 		"error": map[string]interface{}{
-			"code":    -32603, // Internal JSON-RPC Error, see http://www.jsonrpc.org/specification#error_object
+			"code":    code,
 			"message": msg,
 		},
 	}
 }
 
-func newErrorResponseOtto(vm *vm.VM, msg string, id interface{}) otto.Value {
+// newErrorResponseValue converts error response into JavaScript value of engine.
+func newErrorResponseValue(engine vm.Engine, code int, msg string, id interface{}) vm.Value {
 	// TODO(tiabc): Handle errors.
-	errResp, _ := json.Marshal(newErrorResponse(msg, id))
-	errRespVal, _ := vm.Run("(" + string(errResp) + ")")
+	errRespVal, _ := engine.ToValue(newErrorResponse(code, msg, id))
 	return errRespVal
 }
 
-func newResultResponse(vm *otto.Otto, result interface{}) otto.Value {
-	resp, _ := vm.Object(`({"jsonrpc":"2.0"})`)
-	resp.Set("result", result) // nolint: errcheck
+func newResultResponse(engine vm.Engine, result interface{}) vm.Value {
+	resp, _ := engine.ToValue(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"result":  result,
+	})
 
-	return resp.Value()
+	return resp
 }
 
-// throwJSException panics on an otto.Value. The Otto VM will recover from the
+// throwJSException panics with msg, engine of cell will recover from the
 // Go panic and throw msg as a JavaScript error.
-func throwJSException(msg error) otto.Value {
-	val, err := otto.ToValue(msg.Error())
-	if err != nil {
-		log.Error(fmt.Sprintf("Failed to serialize JavaScript exception %v: %v", msg.Error(), err))
-	}
-	panic(val)
-}
-
-// JSONError is wrapper around errors, that are sent upwards
-type JSONError struct {
-	Error string `json:"error"`
-}
-
-func makeError(error string) string {
-	str := JSONError{
-		Error: error,
-	}
-	outBytes, _ := json.Marshal(&str)
-	return string(outBytes)
+func throwJSException(msg error) vm.Value {
+	vm.Throw(msg.Error())
+	return nil
 }
 
 func makeResult(res string, err error) string {
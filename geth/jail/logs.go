@@ -0,0 +1,127 @@
+package jail
+
+import (
+	"time"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/jail/console"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// DefaultLogBufferSize is a number of the last console entries kept for each cell,
+// unless params.JailConfig.LogBufferSize is set
+const DefaultLogBufferSize = 100
+
+// JailLogEvent is a signal sent for every entry cells log with console, if params.JailConfig.StreamLogs is set.
+type JailLogEvent struct {
+	ChatID string `json:"chat_id"`
+	common.JailLogEntry
+}
+
+// logBuffer is a ring buffer of the last console entries of a cell.
+type logBuffer struct {
+	entries []common.JailLogEntry
+	size    int // capacity of buffer
+	start   int // index of the oldest entry, once buffer is full
+}
+
+func newLogBuffer(size int) *logBuffer {
+	return &logBuffer{size: size}
+}
+
+func (b *logBuffer) add(entry common.JailLogEntry) {
+	if len(b.entries) < b.size {
+		b.entries = append(b.entries, entry)
+		return
+	}
+
+	b.entries[b.start] = entry
+	b.start = (b.start + 1) % b.size
+}
+
+// last returns the last n entries in order they have been logged (all entries, if n <= 0).
+func (b *logBuffer) last(n int) []common.JailLogEntry {
+	entries := make([]common.JailLogEntry, 0, len(b.entries))
+	entries = append(entries, b.entries[b.start:]...)
+	entries = append(entries, b.entries[:b.start]...)
+
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries
+}
+
+// CellLogs returns the last n entries (all kept entries, if n <= 0), cell identified by chatID
+// has logged with console. Entries are kept, when cell is evicted or recycled, until it is removed.
+func (jail *Jail) CellLogs(chatID string, n int) []common.JailLogEntry {
+	jail.logsMx.Lock()
+	defer jail.logsMx.Unlock()
+
+	buffer, ok := jail.logs[chatID]
+	if !ok {
+		return []common.JailLogEntry{}
+	}
+
+	return buffer.last(n)
+}
+
+// removeLogs drops entries cell has logged.
+func (jail *Jail) removeLogs(chatID string) {
+	jail.logsMx.Lock()
+	delete(jail.logs, chatID)
+	jail.logsMx.Unlock()
+}
+
+// logEntry keeps entry cell has logged, writes it to log and streams it, if configured.
+func (jail *Jail) logEntry(chatID string, entry common.JailLogEntry) {
+	config := jail.jailConfig()
+
+	jail.logsMx.Lock()
+	buffer, ok := jail.logs[chatID]
+	if !ok {
+		size := config.LogBufferSize
+		if size <= 0 {
+			size = DefaultLogBufferSize
+		}
+		buffer = newLogBuffer(size)
+		jail.logs[chatID] = buffer
+	}
+	buffer.add(entry)
+	jail.logsMx.Unlock()
+
+	switch entry.Level {
+	case "warn":
+		log.Warn("Jail cell console", "chatID", chatID, "message", entry.Message)
+	case "error":
+		log.Error("Jail cell console", "chatID", chatID, "message", entry.Message)
+	default:
+		log.Debug("Jail cell console", "chatID", chatID, "level", entry.Level, "message", entry.Message)
+	}
+
+	if config.StreamLogs {
+		signal.Send(signal.Envelope{
+			Type:  EventJailLog,
+			Event: JailLogEvent{ChatID: chatID, JailLogEntry: entry},
+		})
+	}
+}
+
+// makeConsole returns console object, entries of which are kept in cell's logs.
+func makeConsole(jail *Jail, chatID string) map[string]interface{} {
+	handlers := make(map[string]interface{})
+	for _, level := range []string{"log", "info", "debug", "warn", "error"} {
+		level := level
+		handlers[level] = func(call vm.FunctionCall) vm.Value {
+			jail.logEntry(chatID, common.JailLogEntry{
+				Level:     level,
+				Message:   console.Format(call.ArgumentList),
+				Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+			})
+			return nil
+		}
+	}
+
+	return handlers
+}
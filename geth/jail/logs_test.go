@@ -0,0 +1,71 @@
+package jail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogBuffer(t *testing.T) {
+	buffer := newLogBuffer(3)
+	require.Empty(t, buffer.last(0))
+
+	for _, message := range []string{"a", "b", "c", "d", "e"} {
+		buffer.add(common.JailLogEntry{Message: message})
+	}
+
+	var messages []string
+	for _, entry := range buffer.last(0) {
+		messages = append(messages, entry.Message)
+	}
+	require.Equal(t, []string{"c", "d", "e"}, messages)
+	require.Equal(t, "e", buffer.last(1)[0].Message)
+}
+
+func TestCellLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{LogBufferSize: 2, StreamLogs: true},
+	}, nil).AnyTimes()
+
+	var signals []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, EventJailLog) {
+			signals = append(signals, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", `
+		var _status_catalog = {};
+		console.log('parsed', 1);
+		console.warn('deprecated');
+		console.error('failed:', {code: 1});
+	`))
+
+	logs := jail.CellLogs("chat", 0)
+	require.Len(t, logs, 2)
+	require.Equal(t, "warn", logs[0].Level)
+	require.Equal(t, "deprecated", logs[0].Message)
+	require.Equal(t, "error", logs[1].Level)
+	require.Equal(t, "failed: [object Object]", logs[1].Message)
+	require.NotZero(t, logs[1].Timestamp)
+	require.Equal(t, logs[1:], jail.CellLogs("chat", 1))
+
+	require.Len(t, signals, 3)
+	require.Contains(t, signals[0], `"chat_id":"chat","level":"log","message":"parsed 1"`)
+
+	require.NoError(t, jail.RemoveCell("chat"))
+	require.Empty(t, jail.CellLogs("chat", 0))
+}
@@ -0,0 +1,141 @@
+package jail
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/log"
+)
+
+// errors
+var (
+	ErrInvalidModuleName = errors.New("module name must not be empty")
+)
+
+// moduleWrapper wraps code of module into a function, which is called with CommonJS module and exports.
+// It is kept on the first line, so that lines of errors match lines of module code.
+const (
+	moduleWrapperHead = "(function (module, exports) {"
+	moduleWrapperTail = "\n})"
+)
+
+// module is a registered module, compiled once per kind of engine, scripts of which are shared by cells.
+type module struct {
+	name    string
+	src     string               // wrapped code of module
+	scripts map[string]vm.Script // keyed by name of engine, guarded by modulesMx
+}
+
+// RegisterModule compiles JavaScript module once per engine (see params.JailConfig.Engine), so that
+// every cell can load it with require(name) without parsing it again. Module is a CommonJS one, assigning its API to module.exports (or exports),
+// which is frozen once module is loaded. Module is evaluated lazily, on the first require() of a cell,
+// and cells, which have loaded it already, keep their copy, if module is registered again.
+func (jail *Jail) RegisterModule(name, js string) error {
+	if name == "" {
+		return ErrInvalidModuleName
+	}
+
+	// module is compiled with configured engine right away, so that its syntax errors are reported
+	engine, err := newEngine(jail.jailConfig().Engine)
+	if err != nil {
+		return err
+	}
+	m := &module{name: name, src: moduleWrapperHead + js + moduleWrapperTail, scripts: make(map[string]vm.Script)}
+	script, err := engine.Compile(name, m.src, nil)
+	if err != nil {
+		return err
+	}
+	m.scripts[engine.Name()] = script
+
+	jail.modulesMx.Lock()
+	jail.modules[name] = m
+	jail.modulesMx.Unlock()
+
+	log.Info("Jail module registered", "name", name, "size", len(js))
+
+	return nil
+}
+
+// Modules returns sorted names of registered modules.
+func (jail *Jail) Modules() []string {
+	jail.modulesMx.RLock()
+	defer jail.modulesMx.RUnlock()
+
+	names := make([]string, 0, len(jail.modules))
+	for name := range jail.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// module returns script of module compiled by engine, compiling it, if engine hasn't done it yet.
+func (jail *Jail) module(name string, engine vm.Engine) (vm.Script, error) {
+	jail.modulesMx.Lock()
+	defer jail.modulesMx.Unlock()
+
+	m, ok := jail.modules[name]
+	if !ok {
+		return nil, fmt.Errorf("cannot find module '%s'", name)
+	}
+	if script, ok := m.scripts[engine.Name()]; ok {
+		return script, nil
+	}
+
+	script, err := engine.Compile(m.name, m.src, nil)
+	if err != nil {
+		return nil, err
+	}
+	m.scripts[engine.Name()] = script
+
+	return script, nil
+}
+
+// makeRequireHandler returns require() function, which loads registered modules in cell.
+// Exports of loaded modules are cached, so that module is evaluated once per cell.
+func (jail *Jail) makeRequireHandler(cell *Cell) vm.Function {
+	return func(call vm.FunctionCall) vm.Value {
+		name := call.Argument(0).String()
+
+		// require() is called from JavaScript, so modules of cell are guarded by lock of its VM
+		if module, ok := cell.modules[name]; ok {
+			return moduleExports(module)
+		}
+
+		script, err := jail.module(name, call.Engine)
+		if err != nil {
+			throwJSException(err)
+		}
+
+		module, _ := call.Engine.ToValue(map[string]interface{}{"exports": map[string]interface{}{}})
+		// cached before evaluation, so that cyclic requires get exports, which have been assigned so far
+		cell.modules[name] = module
+
+		fn, err := call.Engine.Run(script)
+		if err == nil {
+			exports := moduleExports(module)
+			_, err = fn.Call(call.Engine.Undefined(), module, exports)
+		}
+		if err != nil {
+			delete(cell.modules, name)
+			throwJSException(err)
+		}
+
+		exports := moduleExports(module)
+		if exports.IsObject() || exports.IsFunction() {
+			if _, err := call.Engine.Call("Object.freeze", nil, exports); err != nil {
+				throwJSException(err)
+			}
+		}
+
+		return exports
+	}
+}
+
+func moduleExports(module vm.Value) vm.Value {
+	exports, _ := module.Get("exports")
+	return exports
+}
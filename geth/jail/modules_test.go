@@ -0,0 +1,83 @@
+package jail
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testModuleJS = `
+	var counter = 0;
+	exports.next = function () { return ++counter; };
+`
+
+func TestRegisterModule(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	require.Equal(t, ErrInvalidModuleName, jail.RegisterModule("", testModuleJS))
+	require.Error(t, jail.RegisterModule("broken", `exports.x = ;`))
+	require.NoError(t, jail.RegisterModule("counter", testModuleJS))
+	require.Equal(t, []string{"counter"}, jail.Modules())
+
+	cell, err := jail.NewCell("chat")
+	require.NoError(t, err)
+
+	value, err := cell.Run(`
+		var counter = require('counter');
+		counter.next();
+		counter.next = function () { return 0; }; // exports are read-only
+		require('counter') === counter ? counter.next() : -1;
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "2", value.String())
+
+	_, err = cell.Run(`require('unknown')`)
+	require.EqualError(t, err, "cannot find module 'unknown'")
+
+	// cells get their own instances of modules
+	cell, err = jail.NewCell("another chat")
+	require.NoError(t, err)
+	value, err = cell.Run(`require('counter').next()`)
+	require.NoError(t, err)
+	require.Equal(t, "1", value.String())
+}
+
+func TestRequireModuleFromBundle(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	require.NoError(t, jail.RegisterModule("greeting", `module.exports = function (name) { return 'hello ' + name; };`))
+	jail.BaseJS(`var greet = require('greeting');`)
+
+	// modules of web3 are resolved first, other ones are passed to shared modules
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", `
+		var _status_catalog = {};
+		function call() { return greet('bot').length + new (require('bignumber.js'))(2).plus(2).toNumber(); }
+	`))
+	require.Equal(t, `{"result": 13}`, jail.Call("chat", `[]`, `{}`))
+}
+
+func TestRequireModuleConcurrently(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	require.NoError(t, jail.RegisterModule("counter", testModuleJS))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		cell, err := jail.NewCell(fmt.Sprintf("chat%d", i))
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cell.Run(`require('counter').next() + require('counter').next()`)
+			require.NoError(t, err)
+			require.Equal(t, "3", value.String())
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,213 @@
+package jail
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// errors
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// methods, which are allowed by common.JailPermissions flags rather than by RPCMethods
+var (
+	transactionMethods = map[string]bool{
+		"eth_sendTransaction":    true,
+		"eth_sendRawTransaction": true,
+		"eth_sign":               true,
+		"eth_signTypedData":      true,
+		"eth_signTypedData_v4":   true,
+		"personal_sign":          true,
+	}
+	accountMethods = map[string]bool{
+		"eth_accounts":        true,
+		"eth_coinbase":        true,
+		"eth_requestAccounts": true,
+	}
+)
+
+// PermissionDeniedEvent is a signal sent, when RPC request of a cell is denied by its permissions.
+type PermissionDeniedEvent struct {
+	ChatID string `json:"chat_id"`
+	Method string `json:"method"`
+	Reason string `json:"reason"`
+}
+
+// SetPermissions restricts RPC requests (including subscriptions) of cell identified by chatID to
+// a given permission set, nil lifts restrictions. Permissions are kept, when cell is removed or re-created,
+// so they are to be set before cell is parsed.
+func (jail *Jail) SetPermissions(chatID string, permissions *common.JailPermissions) {
+	jail.permissionsMx.Lock()
+	defer jail.permissionsMx.Unlock()
+
+	if permissions == nil {
+		delete(jail.permissions, chatID)
+		return
+	}
+	jail.permissions[chatID] = permissions
+}
+
+// allowMethod checks whether cell identified by chatID may call method with params,
+// sending EventJailPermissionDenied signal, if it may not.
+func (jail *Jail) allowMethod(chatID, method string, params []interface{}) error {
+	jail.permissionsMx.RLock()
+	permissions, ok := jail.permissions[chatID]
+	jail.permissionsMx.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	err := checkPermissions(permissions, method, params)
+	if err != nil {
+		log.Warn("RPC request of jail cell is denied", "chatID", chatID, "method", method, "error", err)
+		signal.Send(signal.Envelope{
+			Type: EventJailPermissionDenied,
+			Event: PermissionDeniedEvent{
+				ChatID: chatID,
+				Method: method,
+				Reason: err.Error(),
+			},
+		})
+	}
+
+	return err
+}
+
+// allowRequests checks every request of JSON-RPC call (a single request or a batch) made with jeth.send().
+func (jail *Jail) allowRequests(chatID string, call vm.FunctionCall) error {
+	value, err := call.Argument(0).Export()
+	if err != nil {
+		return err
+	}
+
+	var requests []map[string]interface{}
+	switch value := value.(type) {
+	case []map[string]interface{}:
+		requests = value
+	case []interface{}:
+		for _, request := range value {
+			request, _ := request.(map[string]interface{})
+			requests = append(requests, request)
+		}
+	default:
+		request, _ := value.(map[string]interface{})
+		requests = append(requests, request)
+	}
+
+	for _, request := range requests {
+		method, _ := request["method"].(string)
+		params, _ := request["params"].([]interface{})
+		if err := jail.allowMethod(chatID, method, params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// permissionDeniedResponse returns error response for request of jeth.send() denied with err.
+func (jail *Jail) permissionDeniedResponse(call vm.FunctionCall, err error) vm.Value {
+	return newErrorResponseValue(call.Engine, rpc.ErrCodeUnauthorized, err.Error(), requestID(call))
+}
+
+// checkPermissions returns ErrPermissionDenied, if permissions don't allow to call method with params.
+func checkPermissions(permissions *common.JailPermissions, method string, params []interface{}) error {
+	switch {
+	case transactionMethods[method]:
+		if !permissions.SendTransactions {
+			return fmt.Errorf("%v: cell may not send transactions", ErrPermissionDenied)
+		}
+		return nil
+	case accountMethods[method]:
+		if !permissions.ReadAccounts {
+			return fmt.Errorf("%v: cell may not read accounts", ErrPermissionDenied)
+		}
+		return nil
+	}
+
+	allowed := false
+	for _, pattern := range permissions.RPCMethods {
+		if pattern == method || strings.HasSuffix(pattern, "*") && strings.HasPrefix(method, strings.TrimSuffix(pattern, "*")) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%v: method %s is not allowed", ErrPermissionDenied, method)
+	}
+
+	topics, ok := whisperTopics(method, params)
+	if !ok {
+		return nil
+	}
+	// criteria without topics would match messages of all topics
+	if len(topics) == 0 {
+		return fmt.Errorf("%v: topics must be specified", ErrPermissionDenied)
+	}
+	for _, topic := range topics {
+		if !containsFold(permissions.WhisperTopics, topic) {
+			return fmt.Errorf("%v: topic %s is not allowed", ErrPermissionDenied, topic)
+		}
+	}
+
+	return nil
+}
+
+// whisperTopics returns topics, Whisper method posts to or receives messages of,
+// and false, if method is not related to topics.
+func whisperTopics(method string, params []interface{}) ([]string, bool) {
+	var (
+		argument map[string]interface{}
+		key      = "topics"
+	)
+
+	switch method {
+	case "shh_post":
+		key = "topic"
+		fallthrough
+	case "shh_newMessageFilter":
+		if len(params) > 0 {
+			argument, _ = params[0].(map[string]interface{})
+		}
+	case "shh_subscribe":
+		if len(params) > 1 {
+			argument, _ = params[1].(map[string]interface{})
+		}
+	default:
+		return nil, false
+	}
+
+	var topics []string
+	switch value := argument[key].(type) {
+	case string:
+		topics = append(topics, value)
+	case []interface{}:
+		for _, topic := range value {
+			if topic, ok := topic.(string); ok {
+				topics = append(topics, topic)
+			}
+		}
+	case []string:
+		topics = value
+	}
+
+	return topics, true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
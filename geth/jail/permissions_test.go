@@ -0,0 +1,95 @@
+package jail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPermissions(t *testing.T) {
+	permissions := &common.JailPermissions{
+		ReadAccounts:  true,
+		RPCMethods:    []string{"eth_blockNumber", "shh_*"},
+		WhisperTopics: []string{"0xabcdef01"},
+	}
+
+	testCases := []struct {
+		method  string
+		params  []interface{}
+		allowed bool
+	}{
+		{"eth_blockNumber", nil, true},
+		{"eth_getBalance", nil, false},
+		{"eth_accounts", nil, true},
+		{"eth_sendTransaction", nil, false},
+		{"shh_version", nil, true},
+		{"shh_post", []interface{}{map[string]interface{}{"topic": "0xABCDEF01"}}, true},
+		{"shh_post", []interface{}{map[string]interface{}{"topic": "0x01020304"}}, false},
+		{"shh_newMessageFilter", []interface{}{map[string]interface{}{"topics": []interface{}{"0xabcdef01"}}}, true},
+		{"shh_newMessageFilter", []interface{}{map[string]interface{}{}}, false},
+		{"shh_subscribe", []interface{}{"messages", map[string]interface{}{"topics": []interface{}{"0xabcdef01", "0x01020304"}}}, false},
+	}
+
+	for _, tc := range testCases {
+		err := checkPermissions(permissions, tc.method, tc.params)
+		if tc.allowed {
+			require.NoError(t, err, "%s %v", tc.method, tc.params)
+		} else {
+			require.Error(t, err, "%s %v", tc.method, tc.params)
+			require.Contains(t, err.Error(), ErrPermissionDenied.Error())
+		}
+	}
+}
+
+func TestSendPermissionDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{JailConfig: &params.JailConfig{}}, nil).AnyTimes()
+
+	var signals []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, EventJailPermissionDenied) {
+			signals = append(signals, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	jail := New(nodeManager)
+	jail.SetPermissions("chat", &common.JailPermissions{RPCMethods: []string{"eth_blockNumber"}})
+	cellInt, err := jail.NewCell("chat")
+	require.NoError(t, err)
+	cell := cellInt.(*Cell)
+	defer cell.Stop()
+	require.NoError(t, cell.Set("jeth", struct{}{}))
+	require.NoError(t, registerHandlers(jail, cell, "chat"))
+
+	value, err := cell.Run(`JSON.stringify(jeth.send({"jsonrpc": "2.0", "id": 1, "method": "eth_sendTransaction", "params": [{}]}))`)
+	require.NoError(t, err)
+	require.Contains(t, value.String(), `"code":4100`)
+	require.Contains(t, value.String(), `"id":1`)
+	require.Len(t, signals, 1)
+	require.Contains(t, signals[0], `"method":"eth_sendTransaction"`)
+
+	// a single denied request rejects the whole batch
+	value, err = cell.Run(`JSON.stringify(jeth.send([
+		{"jsonrpc": "2.0", "id": 2, "method": "eth_blockNumber", "params": []},
+		{"jsonrpc": "2.0", "id": 3, "method": "eth_getBalance", "params": []}
+	]))`)
+	require.NoError(t, err)
+	require.Contains(t, value.String(), `"code":4100`)
+	require.Len(t, signals, 2)
+
+	// lifted restrictions let requests through, which fail as node is not running
+	jail.SetPermissions("chat", nil)
+	nodeManager.EXPECT().RPCClient().Return(nil)
+	_, err = cell.Run(`jeth.send({"jsonrpc": "2.0", "id": 4, "method": "eth_sendTransaction", "params": [{}]})`)
+	require.Error(t, err)
+	require.Len(t, signals, 2)
+}
@@ -0,0 +1,117 @@
+package jail
+
+// providerJSCode exposes EIP-1193 provider (https://eips.ethereum.org/EIPS/eip-1193) as `ethereum`,
+// bridged to jeth, so that web3 1.x and newer libraries can be used by bundles:
+//
+//	var web3 = new Web3(ethereum);
+//	ethereum.request({method: 'eth_blockNumber'}).then(function(number) { ... });
+//
+// Subscriptions (eth_subscribe and shh_subscribe) are delivered as "message" events.
+const providerJSCode = `
+var ethereum = (function(jeth) {
+	var nextID = 1;
+	var listeners = {};
+
+	function providerError(code, message, data) {
+		var err = new Error(message);
+		err.code = code;
+		if (data !== undefined) {
+			err.data = data;
+		}
+		return err;
+	}
+
+	function emit(event) {
+		var args = Array.prototype.slice.call(arguments, 1);
+		(listeners[event] || []).slice().forEach(function(listener) {
+			listener.apply(provider, args);
+		});
+		return (listeners[event] || []).length > 0;
+	}
+
+	function subscribe(params) {
+		var id;
+		var args = params.slice();
+		args.push(function(result) {
+			emit('message', {type: 'eth_subscription', data: {subscription: id, result: result}});
+		});
+		id = jeth.subscribe.apply(jeth, args);
+		return id;
+	}
+
+	function send(payload, callback) {
+		jeth.sendAsync(payload, function(err, response) {
+			if (err) {
+				return callback(err);
+			}
+			if (response && response.error) {
+				return callback(providerError(response.error.code, response.error.message, response.error.data));
+			}
+			callback(null, response ? response.result : undefined);
+		});
+	}
+
+	var provider = {
+		isStatus: true,
+
+		request: function(args) {
+			return new Promise(function(resolve, reject) {
+				if (!args || typeof args.method !== 'string') {
+					return reject(providerError(-32602, 'request expects {method, params} argument'));
+				}
+				var method = args.method;
+				var params = args.params || [];
+
+				switch (method) {
+				case 'eth_requestAccounts':
+					// accounts are not exposed to cells on request, selected account is
+					method = 'eth_accounts';
+					break;
+				case 'eth_subscribe':
+				case 'shh_subscribe':
+					try {
+						return resolve(subscribe(params));
+					} catch (err) {
+						return reject(providerError(-32603, err.message));
+					}
+				case 'eth_unsubscribe':
+				case 'shh_unsubscribe':
+					try {
+						return resolve(jeth.unsubscribe(params[0]));
+					} catch (err) {
+						return reject(providerError(-32603, err.message));
+					}
+				}
+
+				send({jsonrpc: '2.0', id: nextID++, method: method, params: params}, function(err, result) {
+					if (err) {
+						return reject(err);
+					}
+					resolve(result);
+				});
+			});
+		},
+
+		// legacy provider API, still used by web3 1.x
+		sendAsync: function(payload, callback) {
+			jeth.sendAsync(payload, callback);
+		},
+
+		on: function(event, listener) {
+			(listeners[event] = listeners[event] || []).push(listener);
+			return provider;
+		},
+
+		removeListener: function(event, listener) {
+			listeners[event] = (listeners[event] || []).filter(function(l) {
+				return l !== listener;
+			});
+			return provider;
+		},
+
+		emit: emit
+	};
+
+	return provider;
+})(jeth);
+`
@@ -0,0 +1,90 @@
+package jail
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testJethJSCode fakes jeth, responding to requests synchronously
+const testJethJSCode = `
+var subscriptions = {};
+var jeth = {
+	sendAsync: function(payload, callback) {
+		switch (payload.method) {
+		case 'eth_accounts':
+			return callback(null, {jsonrpc: '2.0', id: payload.id, result: ['0x01']});
+		case 'eth_getBalance':
+			return callback(null, {jsonrpc: '2.0', id: payload.id, result: payload.params[0] === '0x01' ? '0x10' : '0x0'});
+		default:
+			return callback(null, {jsonrpc: '2.0', id: payload.id, error: {code: -32601, message: 'method not found'}});
+		}
+	},
+	subscribe: function(kind, callback) {
+		subscriptions['0xs'] = callback;
+		return '0xs';
+	},
+	unsubscribe: function(id) {
+		delete subscriptions[id];
+		return true;
+	}
+};
+`
+
+func TestEIP1193Provider(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	cellInt, err := jail.NewCell("chat")
+	require.NoError(t, err)
+	cell := cellInt.(*Cell)
+
+	_, err = cell.Run(testJethJSCode)
+	require.NoError(t, err)
+	_, err = cell.Run(providerJSCode)
+	require.NoError(t, err)
+
+	results := make(chan string, 10)
+	require.NoError(t, cell.Set("__capture", func(result string) { results <- result }))
+
+	_, err = cell.Run(`
+		ethereum.on('message', function(message) {
+			__capture(message.type + ' ' + message.data.subscription + ' ' + message.data.result);
+		});
+
+		ethereum.request({method: 'eth_requestAccounts'}).then(function(accounts) {
+			return ethereum.request({method: 'eth_getBalance', params: [accounts[0], 'latest']});
+		}).then(__capture);
+
+		ethereum.request({method: 'eth_mining'}).catch(function(err) {
+			__capture(err.code + ' ' + err.message);
+		});
+
+		ethereum.request({method: 'eth_subscribe', params: ['newHeads']}).then(function(id) {
+			subscriptions[id]('0x1');
+			return ethereum.request({method: 'eth_unsubscribe', params: [id]});
+		}).then(function(ok) {
+			__capture('unsubscribed ' + ok + ' ' + Object.keys(subscriptions).length);
+		});
+	`)
+	require.NoError(t, err)
+
+	var captured []string
+	for len(captured) < 4 {
+		select {
+		case result := <-results:
+			captured = append(captured, result)
+		case <-time.After(time.Second):
+			require.FailNow(t, "test timed out", "captured: %v", captured)
+		}
+	}
+	sort.Strings(captured)
+	require.Equal(t, []string{
+		"-32601 method not found",
+		"0x10",
+		"eth_subscription 0xs 0x1",
+		"unsubscribed true 0",
+	}, captured)
+}
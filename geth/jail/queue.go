@@ -0,0 +1,94 @@
+package jail
+
+import (
+	"fmt"
+	"sync"
+)
+
+// callQueue counts calls of a cell and, in ordered mode (see params.JailConfig.OrderedCalls),
+// makes them wait for preceding ones, so that calls are executed in order they have been made.
+type callQueue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	next uint64 // sequence number of the last call made
+	done uint64 // number of completed calls
+}
+
+func newCallQueue() *callQueue {
+	q := &callQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// reserve returns sequence number of a new call. Every call must be completed with leave.
+func (q *callQueue) reserve() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.next++
+	return q.next
+}
+
+// wait waits for completion of all calls preceding one with a given sequence number.
+func (q *callQueue) wait(seq uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.done < seq-1 {
+		q.cond.Wait()
+	}
+}
+
+func (q *callQueue) leave() {
+	q.mu.Lock()
+	q.done++
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// depth returns number of calls, which are either executed or wait for execution.
+func (q *callQueue) depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return int(q.next - q.done)
+}
+
+// callQueue returns queue of calls of cell identified by chatID. Queues are kept,
+// when cells are evicted or recycled, so that sequence of calls is not interrupted.
+func (jail *Jail) callQueue(chatID string) *callQueue {
+	jail.queuesMx.Lock()
+	defer jail.queuesMx.Unlock()
+
+	q, ok := jail.queues[chatID]
+	if !ok {
+		q = newCallQueue()
+		jail.queues[chatID] = q
+	}
+	return q
+}
+
+// removeCallQueue drops queue of cell, calls, which wait in it, are still executed.
+func (jail *Jail) removeCallQueue(chatID string) {
+	jail.queuesMx.Lock()
+	delete(jail.queues, chatID)
+	jail.queuesMx.Unlock()
+}
+
+// CallQueueDepth returns number of calls of cell identified by chatID, which are
+// either executed or wait for execution.
+func (jail *Jail) CallQueueDepth(chatID string) int {
+	jail.queuesMx.Lock()
+	q, ok := jail.queues[chatID]
+	jail.queuesMx.Unlock()
+
+	if !ok {
+		return 0
+	}
+	return q.depth()
+}
+
+// withSequence tags JSON response of call with its sequence number.
+func withSequence(response string, seq uint64) string {
+	return fmt.Sprintf(`{"seq": %d, %s`, seq, response[1:])
+}
@@ -0,0 +1,129 @@
+package jail
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallQueue(t *testing.T) {
+	q := newCallQueue()
+	require.Equal(t, uint64(1), q.reserve())
+	q.wait(1)
+
+	// the second call waits until the first one leaves
+	entered := make(chan uint64)
+	go func() {
+		seq := q.reserve()
+		q.wait(seq)
+		entered <- seq
+	}()
+
+	for q.depth() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-entered:
+		require.FailNow(t, "call entered before preceding one left")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.leave()
+	require.Equal(t, uint64(2), <-entered)
+	q.leave()
+	require.Equal(t, 0, q.depth())
+}
+
+func TestOrderedCalls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{OrderedCalls: true},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", `
+		var _status_catalog = {};
+		var calls = [];
+		function call(path, args) { calls.push(args); return calls.length; }
+	`))
+
+	// calls are executed in order of their sequence numbers
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var response struct {
+				Seq    uint64 `json:"seq"`
+				Result uint64 `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(jail.Call("chat", `["commands", "x"]`, `{}`)), &response))
+			require.Equal(t, response.Seq, response.Result)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 0, jail.CallQueueDepth("chat"))
+
+	require.Equal(t, `{"seq": 11, "result": 11}`, jail.Call("chat", `["commands", "x"]`, `{"n": 10}`))
+	require.Equal(t, `{"seq": 1, "error":"cell[unknown] doesn't exist"}`, jail.Call("unknown", `[]`, `{}`))
+}
+
+func TestCallAsync(t *testing.T) {
+	results := make(chan CallResultEvent, 3)
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		var envelope struct {
+			Type  string          `json:"type"`
+			Event CallResultEvent `json:"event"`
+		}
+		if err := json.Unmarshal([]byte(event), &envelope); err == nil && envelope.Type == EventJailCallResult {
+			results <- envelope.Event
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	jail := New(nil)
+	defer jail.Stop()
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", `
+		var _status_catalog = {};
+		function call(path, args) { return args === "raw" ? "not json" : args; }
+	`))
+
+	ids := map[string]string{
+		jail.CallAsync("chat", `[]`, `{"n": 1}`): `{"result": {"n": 1}}`,
+		jail.CallAsync("chat", `[]`, `raw`):      `{"result": not json}`,
+		jail.CallAsync("unknown", `[]`, `{}`):    `{"error":"cell[unknown] doesn't exist"}`,
+	}
+	require.Len(t, ids, 3)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-results:
+			expected, ok := ids[event.CallID]
+			require.True(t, ok)
+			if !json.Valid([]byte(expected)) {
+				// malformed JSON is passed as a string
+				var response string
+				require.NoError(t, json.Unmarshal(event.Response, &response))
+				require.Equal(t, expected, response)
+				continue
+			}
+			require.JSONEq(t, expected, string(event.Response))
+		case <-time.After(time.Second):
+			require.FailNow(t, "response has not been delivered")
+		}
+	}
+}
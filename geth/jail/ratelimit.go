@@ -0,0 +1,70 @@
+package jail
+
+import (
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+)
+
+// RateLimitedEvent is a signal sent, when RPC requests of a cell start being rejected by rate limiter.
+// It is sent once, until requests of a cell are allowed again.
+type RateLimitedEvent struct {
+	ChatID string  `json:"chat_id"`
+	Limit  float64 `json:"limit"` // requests per second
+}
+
+// rateLimiter is a token bucket, limiting rate of RPC requests of a single cell.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // tokens added per second
+	burst     float64 // capacity of bucket
+	tokens    float64
+	last      time.Time // time tokens have been added at
+	throttled bool      // the last request has been rejected
+	now       func() time.Time
+}
+
+// newRateLimiter returns limiter configured by config, or nil, if rate limiting is disabled.
+func newRateLimiter(config *params.JailConfig) *rateLimiter {
+	if config == nil || config.RateLimit <= 0 {
+		return nil
+	}
+
+	burst := float64(config.RateBurst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rate:   config.RateLimit,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// allow takes a token from bucket, if there is any. It also reports,
+// whether request is the first one rejected since requests have been allowed.
+func (l *rateLimiter) allow() (allowed bool, throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		throttled = !l.throttled
+		l.throttled = true
+		return false, throttled
+	}
+
+	l.tokens--
+	l.throttled = false
+	return true, false
+}
@@ -0,0 +1,82 @@
+package jail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	require.Nil(t, newRateLimiter(nil))
+	require.Nil(t, newRateLimiter(&params.JailConfig{}))
+
+	now := time.Now()
+	limiter := newRateLimiter(&params.JailConfig{RateLimit: 2, RateBurst: 2})
+	limiter.last = now
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		allowed, _ := limiter.allow()
+		require.True(t, allowed)
+	}
+
+	// only the first rejected request is reported
+	allowed, throttled := limiter.allow()
+	require.False(t, allowed)
+	require.True(t, throttled)
+	allowed, throttled = limiter.allow()
+	require.False(t, allowed)
+	require.False(t, throttled)
+
+	// tokens are added with configured rate
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.allow()
+	require.True(t, allowed)
+	allowed, throttled = limiter.allow()
+	require.False(t, allowed)
+	require.True(t, throttled)
+}
+
+func TestSendRateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	// config is read, when cell is created (cells limit), and on the first request (rate limit)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{RateLimit: 0.001, RateBurst: 1},
+	}, nil).Times(2)
+	// the first request is allowed, and fails, as node is not running
+	nodeManager.EXPECT().RPCClient().Return(nil)
+
+	var signals []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, EventRateLimited) {
+			signals = append(signals, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	jail := New(nodeManager)
+	cellInt, err := jail.NewCell("chat")
+	require.NoError(t, err)
+	cell := cellInt.(*Cell)
+	defer cell.Stop()
+	require.NoError(t, cell.Set("jeth", struct{}{}))
+	require.NoError(t, registerHandlers(jail, cell, "chat"))
+
+	_, err = cell.Run(`jeth.send({"jsonrpc": "2.0", "id": 1, "method": "eth_blockNumber", "params": []})`)
+	require.Error(t, err)
+
+	value, err := cell.Run(`JSON.stringify(jeth.send({"jsonrpc": "2.0", "id": 2, "method": "eth_blockNumber", "params": []}))`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"jsonrpc":"2.0","id":2,"error":{"code":-32005,"message":"`+ErrRateLimited.Error()+`"}}`, value.String())
+	require.Len(t, signals, 1)
+	require.Contains(t, signals[0], `"chat_id":"chat"`)
+}
@@ -0,0 +1,141 @@
+package jail
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+	"github.com/rcrowley/go-metrics"
+	"github.com/status-im/status-go/geth/log"
+)
+
+// heapCheckInterval is a minimal interval between checks of heap size, as reading it stops the world
+const heapCheckInterval = 30 * time.Second
+
+// heapMonitor checks size of heap after calls of cells, against limit of params.JailConfig.MaxHeapSize.
+//
+// Otto has no means to release memory accumulated by JavaScript code (e.g. in globals, caches
+// or closures kept by bundle), nor an arena that could be reset between calls, so the only way
+// to bound memory of long-lived cells is to re-create them from their snapshots.
+type heapMonitor struct {
+	limit uint64 // in bytes, 0 disables recycling
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	interval  time.Duration
+	read      func() uint64 // returns size of allocated heap
+
+	recycled metrics.Counter
+	heapSize metrics.Gauge
+}
+
+func newHeapMonitor() *heapMonitor {
+	m := &heapMonitor{
+		interval: heapCheckInterval,
+		read:     readHeapSize,
+		recycled: gethmetrics.NewCounter("jail/cells/recycled"),
+		heapSize: new(metrics.NilGauge),
+	}
+	if gethmetrics.Enabled {
+		m.heapSize = metrics.GetOrRegisterGauge("jail/heap", metrics.DefaultRegistry)
+	}
+
+	return m
+}
+
+// setLimit sets limit of heap size, in megabytes.
+func (m *heapMonitor) setLimit(megabytes int) {
+	if megabytes < 0 {
+		megabytes = 0
+	}
+	atomic.StoreUint64(&m.limit, uint64(megabytes)*1024*1024)
+}
+
+// exceeded checks whether heap has grown above limit, unless it has been checked recently.
+func (m *heapMonitor) exceeded() (uint64, bool) {
+	limit := atomic.LoadUint64(&m.limit)
+	if limit == 0 {
+		return 0, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.checkedAt) < m.interval {
+		return 0, false
+	}
+	m.checkedAt = time.Now()
+
+	size := m.read()
+	m.heapSize.Update(int64(size))
+
+	return size, size > limit
+}
+
+func readHeapSize() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// callCompleted counts calls of cell, recycling the busiest cell, once heap grows above limit.
+func (jail *Jail) callCompleted(cell *Cell) {
+	atomic.AddUint64(&cell.calls, 1)
+
+	if size, ok := jail.heap.exceeded(); ok {
+		jail.recycleCell(size)
+	}
+}
+
+// recycleCell re-creates cell, that has handled the most calls, from its snapshot
+// (see CellSnapshot), releasing memory accumulated by its VM. Calls in flight on the cell
+// complete first, and the following ones wait, until it's re-created.
+func (jail *Jail) recycleCell(sizeBefore uint64) {
+	jail.cellsMx.RLock()
+	var busiest *Cell
+	for _, cell := range jail.cells {
+		// cells, which have not been parsed, can't be restored
+		if cell.bundle != "" && (busiest == nil || atomic.LoadUint64(&cell.calls) > atomic.LoadUint64(&busiest.calls)) {
+			busiest = cell
+		}
+	}
+	jail.cellsMx.RUnlock()
+
+	if busiest == nil {
+		return
+	}
+
+	// lock of cell is taken without lock of cells, as calls may access other cells
+	busiest.callsMx.Lock()
+	jail.cellsMx.Lock()
+	// cell may have been evicted or recycled, while waiting for calls
+	recycled := !busiest.stopped() && jail.cells[busiest.id] == busiest
+	if recycled {
+		delete(jail.cells, busiest.id)
+		jail.saveCell(busiest)
+		busiest.Stop()
+	}
+	jail.cellsMx.Unlock()
+	busiest.callsMx.Unlock()
+
+	if !recycled {
+		return
+	}
+
+	if _, err := jail.restoreCell(busiest.id); err != nil {
+		log.Warn("Failed to restore recycled jail cell", "chatID", busiest.id, "error", err)
+	}
+
+	runtime.GC()
+	debug.FreeOSMemory()
+
+	sizeAfter := jail.heap.read()
+	jail.heap.heapSize.Update(int64(sizeAfter))
+	jail.heap.recycled.Inc(1)
+
+	log.Info("Recycled jail cell", "chatID", busiest.id, "calls", atomic.LoadUint64(&busiest.calls),
+		"heapBefore", sizeBefore, "heapAfter", sizeAfter)
+}
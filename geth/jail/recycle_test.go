@@ -0,0 +1,158 @@
+package jail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellRecycling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{MaxHeapSize: 1},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	heapSize := uint64(0)
+	jail.heap.interval = 0
+	jail.heap.read = func() uint64 { return heapSize }
+
+	bundle := testSnapshotBundle + `function call() { return ++counter; }`
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", bundle))
+	require.Equal(t, `{"result": {}}`, jail.Parse("b", bundle))
+
+	require.Equal(t, `{"result": 1}`, jail.Call("a", "", ""))
+	require.Equal(t, `{"result": 2}`, jail.Call("a", "", ""))
+	require.Equal(t, `{"result": 1}`, jail.Call("b", "", ""))
+	cell, _ := jail.cell("a")
+
+	// once heap is above limit, the busiest cell is re-created with its state
+	heapSize = 2 * 1024 * 1024
+	require.Equal(t, `{"result": 3}`, jail.Call("a", "", ""))
+
+	recycled, ok := jail.cell("a")
+	require.True(t, ok)
+	require.NotEqual(t, cell, recycled)
+	counter, err := recycled.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, "3", counter.String())
+	require.Equal(t, `{"result": 4}`, jail.Call("a", "", ""))
+	require.Len(t, jail.cells, 2)
+}
+
+func TestCellRecyclingWaitsForCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	bundle := testSnapshotBundle + `function call() { wait(); return ++counter; }`
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", bundle))
+	cell, _ := jail.cell("a")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, cell.Set("wait", func(vm.FunctionCall) vm.Value {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	response := make(chan string)
+	go func() { response <- jail.Call("a", "", "") }()
+	<-started
+
+	// cell is recycled, once call in flight completes on it
+	recycled := make(chan struct{})
+	go func() {
+		jail.recycleCell(0)
+		close(recycled)
+	}()
+	select {
+	case <-recycled:
+		t.Fatal("cell is recycled while call is in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+	current, _ := jail.cell("a")
+	require.Equal(t, cell, current)
+
+	close(release)
+	require.Equal(t, `{"result": 1}`, <-response)
+	<-recycled
+
+	restored, ok := jail.cell("a")
+	require.True(t, ok)
+	require.NotEqual(t, cell, restored)
+	counter, err := restored.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, "1", counter.String())
+}
+
+func TestCellRecyclingWaitsForCellAccessor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		JailConfig: &params.JailConfig{},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", testSnapshotBundle))
+	cell, err := jail.Cell("a")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, cell.Set("wait", func(vm.FunctionCall) vm.Value {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	done := make(chan error)
+	go func() {
+		_, err := cell.Run(`wait(); ++counter`)
+		done <- err
+	}()
+	<-started
+
+	// cell is recycled, once code run through accessor completes on it
+	recycled := make(chan struct{})
+	go func() {
+		jail.recycleCell(0)
+		close(recycled)
+	}()
+	select {
+	case <-recycled:
+		t.Fatal("cell is recycled while code is run")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+	<-recycled
+
+	// accessor follows the re-created cell
+	counter, err := cell.Get("counter")
+	require.NoError(t, err)
+	require.Equal(t, "1", counter.String())
+}
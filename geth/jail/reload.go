@@ -0,0 +1,51 @@
+package jail
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/status-im/status-go/geth/log"
+)
+
+// ReloadBaseJS changes base JavaScript (see BaseJS) and re-parses live cells with it, the same way
+// they are recycled: state of bundle (result of _status_snapshot, if bundle defines it) is passed to
+// _status_restore of re-created cell. Evicted cells are restored with new base JavaScript on next access.
+// Cells, which fail to be re-parsed, are kept evicted.
+func (jail *Jail) ReloadBaseJS(js string) error {
+	if jail == nil {
+		return ErrInvalidJail
+	}
+
+	jail.BaseJS(js)
+	_, version := jail.baseJS()
+
+	jail.cellsMx.Lock()
+	var stale []string
+	for chatID, cell := range jail.cells {
+		// cells, which have not been parsed, can't be restored
+		if cell.bundle == "" || cell.parseVersion == version {
+			continue
+		}
+		delete(jail.cells, chatID)
+		jail.saveCell(cell)
+		cell.Stop()
+		stale = append(stale, chatID)
+	}
+	jail.cellsMx.Unlock()
+
+	var failed []string
+	for _, chatID := range stale {
+		if _, err := jail.restoreCell(chatID); err != nil {
+			log.Warn("Failed to reload jail cell", "chatID", chatID, "error", err)
+			failed = append(failed, chatID)
+		}
+	}
+	log.Info("Reloaded base JavaScript of jail", "version", version, "cells", len(stale), "failed", len(failed))
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to reload cells: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
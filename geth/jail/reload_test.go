@@ -0,0 +1,39 @@
+package jail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadBaseJS(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	require.Equal(t, uint64(0), jail.ParseVersion())
+	jail.BaseJS(`var base = 1;`)
+	require.Equal(t, uint64(1), jail.ParseVersion())
+	jail.BaseJS(`var base = 1;`) // unchanged
+	require.Equal(t, uint64(1), jail.ParseVersion())
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("chat", `
+		var _status_catalog = {};
+		var counter = 0;
+		function call() { counter++; return base * 100 + counter; }
+		function _status_snapshot() { return JSON.stringify({counter: counter}); }
+		function _status_restore(state) { counter = JSON.parse(state).counter; }
+	`))
+	require.Equal(t, `{"result": 101}`, jail.Call("chat", `[]`, `{}`))
+
+	require.NoError(t, jail.ReloadBaseJS(`var base = 2;`))
+	require.Equal(t, uint64(2), jail.ParseVersion())
+
+	// base JavaScript is changed, state of bundle is kept
+	require.Equal(t, `{"result": 202}`, jail.Call("chat", `[]`, `{}`))
+	cells := jail.Cells()
+	require.Len(t, cells, 1)
+	require.Equal(t, uint64(2), cells[0].ParseVersion)
+
+	// cells, which fail to be re-parsed, are reported
+	require.EqualError(t, jail.ReloadBaseJS(`throw new Error('broken');`), "failed to reload cells: chat")
+}
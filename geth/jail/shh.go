@@ -0,0 +1,24 @@
+package jail
+
+// shhJSCode extends web3.shh of cells with push-based subscriptions of Whisper messages, so that
+// bots can react to messages without polling filters:
+//
+//	var id = web3.shh.subscribe({topics: ['0xdeadbeef'], symKeyID: keyID}, function(message) { ... });
+//	web3.shh.unsubscribe(id);
+//
+// Subscriptions are created with shh_subscribe, routed and filtered by node the same way
+// as subscriptions of Go code (see rpc.Client.Subscribe), and are cancelled once cell is stopped.
+const shhJSCode = `
+(function(shh, jeth) {
+	shh.subscribe = function(criteria, callback) {
+		if (typeof callback !== 'function') {
+			throw new Error('web3.shh.subscribe expects criteria and callback');
+		}
+		return jeth.subscribe('messages', criteria, callback);
+	};
+
+	shh.unsubscribe = function(id) {
+		return jeth.unsubscribe(id);
+	};
+})(web3.shh, jeth);
+`
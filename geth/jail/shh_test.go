@@ -0,0 +1,44 @@
+package jail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShhSubscribe(t *testing.T) {
+	jail := New(nil)
+	defer jail.Stop()
+
+	cellInt, err := jail.NewCell("chat")
+	require.NoError(t, err)
+	cell := cellInt.(*Cell)
+
+	_, err = cell.Run(`
+		var subscriptions = {};
+		var jeth = {
+			subscribe: function(kind, criteria, callback) {
+				subscriptions['0xs'] = {kind: kind, criteria: criteria, callback: callback};
+				return '0xs';
+			},
+			unsubscribe: function(id) { return delete subscriptions[id]; }
+		};
+		var web3 = {shh: {}};
+	`)
+	require.NoError(t, err)
+	_, err = cell.Run(shhJSCode)
+	require.NoError(t, err)
+
+	value, err := cell.Run(`
+		var received = [];
+		var id = web3.shh.subscribe({topics: ['0xdeadbeef']}, function(message) { received.push(message.payload); });
+		var s = subscriptions[id];
+		s.callback({payload: '0x01'});
+		[s.kind, s.criteria.topics[0], received[0], web3.shh.unsubscribe(id), Object.keys(subscriptions).length].join(' ');
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "messages 0xdeadbeef 0x01 true 0", value.String())
+
+	_, err = cell.Run(`web3.shh.subscribe({topics: ['0xdeadbeef']})`)
+	require.EqualError(t, err, "Error: web3.shh.subscribe expects criteria and callback")
+}
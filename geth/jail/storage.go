@@ -0,0 +1,365 @@
+package jail
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/jail/internal/vm"
+	"github.com/syndtr/goleveldb/leveldb"
+	leveldbstorage "github.com/syndtr/goleveldb/leveldb/storage"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	// storageDirName is a name of directory (in node's data dir), localStorage of cells is kept in
+	storageDirName = "jailstorage"
+
+	// DefaultStorageQuota is a size (in bytes) of keys and values, each cell may keep in localStorage,
+	// unless params.JailConfig.StorageQuota is set
+	DefaultStorageQuota = 5 * 1024 * 1024
+)
+
+// errors
+var (
+	ErrStorageQuotaExceeded = errors.New("localStorage quota exceeded")
+)
+
+// localStorageJSCode wraps native storage functions into localStorage-compatible object
+const localStorageJSCode = `
+var localStorage = (function(native) {
+	var storage = {
+		getItem: function(key) { return native.getItem(String(key)); },
+		setItem: function(key, value) { native.setItem(String(key), String(value)); },
+		removeItem: function(key) { native.removeItem(String(key)); },
+		clear: function() { native.clear(); },
+		key: function(index) { return native.key(Number(index)); }
+	};
+	Object.defineProperty(storage, 'length', { get: function() { return native.length(); } });
+	return storage;
+})(__localStorage);
+delete __localStorage;
+`
+
+// storage is a persistent key-value store, backing localStorage of cells. Items of every cell
+// are kept under a separate namespace (prefix of keys), size of which is limited by quota.
+type storage struct {
+	mu    sync.Mutex
+	db    *leveldb.DB
+	path  string         // empty, if items are kept in memory
+	sizes map[string]int // sizes of namespaces, computed on first access
+}
+
+// openStorage opens storage at path, or in memory, if path is empty.
+func openStorage(path string) (*storage, error) {
+	var db *leveldb.DB
+	var err error
+	if path == "" {
+		db, err = leveldb.Open(leveldbstorage.NewMemStorage(), nil)
+	} else {
+		db, err = leveldb.OpenFile(path, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{
+		db:    db,
+		path:  path,
+		sizes: make(map[string]int),
+	}, nil
+}
+
+// namespace returns prefix of keys of a given cell.
+func namespace(chatID string) []byte {
+	return []byte(strconv.Itoa(len(chatID)) + ":" + chatID)
+}
+
+func (s *storage) get(chatID, key string) (string, bool, error) {
+	value, err := s.db.Get(append(namespace(chatID), key...), nil)
+	if err == leveldb.ErrNotFound {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(value), true, nil
+}
+
+// set stores item, failing with ErrStorageQuotaExceeded, if size of cell's items would exceed quota
+// (0 means no limit).
+func (s *storage) set(chatID, key, value string, quota int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size, err := s.size(chatID)
+	if err != nil {
+		return err
+	}
+	old, ok, err := s.get(chatID, key)
+	if err != nil {
+		return err
+	}
+	if ok {
+		size -= len(key) + len(old)
+	}
+	size += len(key) + len(value)
+	if quota > 0 && size > quota {
+		return ErrStorageQuotaExceeded
+	}
+
+	if err := s.db.Put(append(namespace(chatID), key...), []byte(value), nil); err != nil {
+		return err
+	}
+	s.sizes[chatID] = size
+
+	return nil
+}
+
+func (s *storage) remove(chatID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok, err := s.get(chatID, key)
+	if err != nil || !ok {
+		return err
+	}
+	if err := s.db.Delete(append(namespace(chatID), key...), nil); err != nil {
+		return err
+	}
+	if size, ok := s.sizes[chatID]; ok {
+		s.sizes[chatID] = size - len(key) - len(old)
+	}
+
+	return nil
+}
+
+// items returns items of a cell, sorted by keys.
+func (s *storage) items(chatID string) ([]string, map[string]string, error) {
+	prefix := namespace(chatID)
+	it := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer it.Release()
+
+	var keys []string
+	items := make(map[string]string)
+	for it.Next() {
+		key := string(it.Key()[len(prefix):])
+		keys = append(keys, key)
+		items[key] = string(it.Value())
+	}
+
+	return keys, items, it.Error()
+}
+
+func (s *storage) clear(chatID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, _, err := s.items(chatID)
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	for _, key := range keys {
+		batch.Delete(append(namespace(chatID), key...))
+	}
+	if err := s.db.Write(batch, nil); err != nil {
+		return err
+	}
+	s.sizes[chatID] = 0
+
+	return nil
+}
+
+// size returns size of keys and values of cell's items. Must be called with lock held.
+func (s *storage) size(chatID string) (int, error) {
+	if size, ok := s.sizes[chatID]; ok {
+		return size, nil
+	}
+
+	keys, items, err := s.items(chatID)
+	if err != nil {
+		return 0, err
+	}
+	size := 0
+	for _, key := range keys {
+		size += len(key) + len(items[key])
+	}
+	s.sizes[chatID] = size
+
+	return size, nil
+}
+
+func (s *storage) close() error {
+	return s.db.Close()
+}
+
+// cellStorage returns storage of the running node (kept in its data dir), or in-memory one,
+// if node's data dir is unknown.
+func (jail *Jail) cellStorage() (*storage, error) {
+	path := ""
+	if jail.nodeManager != nil {
+		if config, err := jail.nodeManager.NodeConfig(); err == nil && config.DataDir != "" {
+			path = filepath.Join(config.DataDir, storageDirName)
+		}
+	}
+
+	jail.storageMx.Lock()
+	defer jail.storageMx.Unlock()
+
+	if jail.storage != nil && jail.storage.path == path {
+		return jail.storage, nil
+	}
+	if jail.storage != nil {
+		jail.storage.close() // nolint: errcheck
+		jail.storage = nil
+	}
+
+	s, err := openStorage(path)
+	if err != nil {
+		return nil, err
+	}
+	jail.storage = s
+
+	return s, nil
+}
+
+// closeStorage closes storage, it is opened again on next access.
+func (jail *Jail) closeStorage() {
+	jail.storageMx.Lock()
+	defer jail.storageMx.Unlock()
+
+	if jail.storage != nil {
+		jail.storage.close() // nolint: errcheck
+		jail.storage = nil
+	}
+}
+
+// Storage returns items, cell identified by chatID keeps in localStorage.
+func (jail *Jail) Storage(chatID string) (map[string]string, error) {
+	s, err := jail.cellStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	_, items, err := s.items(chatID)
+	return items, err
+}
+
+// ClearStorage removes all items, cell identified by chatID keeps in localStorage.
+func (jail *Jail) ClearStorage(chatID string) error {
+	s, err := jail.cellStorage()
+	if err != nil {
+		return err
+	}
+
+	return s.clear(chatID)
+}
+
+// storageQuota returns size of items, each cell may keep in localStorage.
+func (jail *Jail) storageQuota() int {
+	if quota := jail.jailConfig().StorageQuota; quota > 0 {
+		return quota
+	}
+
+	return DefaultStorageQuota
+}
+
+// registerLocalStorage exposes localStorage object, items of which are kept in jail's storage
+// under namespace of cell.
+func registerLocalStorage(jail *Jail, cell common.JailCell, chatID string) error {
+	native := map[string]interface{}{
+		"getItem": func(call vm.FunctionCall) vm.Value {
+			s, err := jail.cellStorage()
+			if err != nil {
+				return throwJSException(err)
+			}
+			value, ok, err := s.get(chatID, call.Argument(0).String())
+			if err != nil {
+				return throwJSException(err)
+			}
+			if !ok {
+				result, _ := call.Engine.ToValue(nil)
+				return result
+			}
+			result, _ := call.Engine.ToValue(value)
+			return result
+		},
+		"setItem": func(call vm.FunctionCall) vm.Value {
+			s, err := jail.cellStorage()
+			if err != nil {
+				return throwJSException(err)
+			}
+			err = s.set(chatID, call.Argument(0).String(), call.Argument(1).String(), jail.storageQuota())
+			if err == ErrStorageQuotaExceeded {
+				vm.ThrowError("QuotaExceededError", err.Error())
+			}
+			if err != nil {
+				return throwJSException(err)
+			}
+			return nil
+		},
+		"removeItem": func(call vm.FunctionCall) vm.Value {
+			s, err := jail.cellStorage()
+			if err != nil {
+				return throwJSException(err)
+			}
+			if err := s.remove(chatID, call.Argument(0).String()); err != nil {
+				return throwJSException(err)
+			}
+			return nil
+		},
+		"clear": func(call vm.FunctionCall) vm.Value {
+			if err := jail.ClearStorage(chatID); err != nil {
+				return throwJSException(err)
+			}
+			return nil
+		},
+		"key": func(call vm.FunctionCall) vm.Value {
+			keys, err := jail.storageKeys(chatID)
+			if err != nil {
+				return throwJSException(err)
+			}
+			index, _ := call.Argument(0).ToInteger()
+			if index < 0 || index >= int64(len(keys)) {
+				result, _ := call.Engine.ToValue(nil)
+				return result
+			}
+			result, _ := call.Engine.ToValue(keys[index])
+			return result
+		},
+		"length": func(call vm.FunctionCall) vm.Value {
+			keys, err := jail.storageKeys(chatID)
+			if err != nil {
+				return throwJSException(err)
+			}
+			result, _ := call.Engine.ToValue(len(keys))
+			return result
+		},
+	}
+
+	if err := cell.Set("__localStorage", native); err != nil {
+		return err
+	}
+	if _, err := cell.Run(localStorageJSCode); err != nil {
+		return fmt.Errorf("failed to init localStorage: %v", err)
+	}
+
+	return nil
+}
+
+// storageKeys returns sorted keys of items, cell keeps in localStorage.
+func (jail *Jail) storageKeys(chatID string) ([]string, error) {
+	s, err := jail.cellStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, _, err := s.items(chatID)
+	return keys, err
+}
@@ -0,0 +1,79 @@
+package jail
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+const testStorageBundle = `
+var _status_catalog = {};
+var visits = Number(localStorage.getItem('visits')) + 1;
+localStorage.setItem('visits', visits);
+`
+
+func TestLocalStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jailstorage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		DataDir:    dir,
+		JailConfig: &params.JailConfig{StorageQuota: 32},
+	}, nil).AnyTimes()
+
+	jail := New(nodeManager)
+	defer jail.Stop()
+
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", testStorageBundle))
+	require.Equal(t, `{"result": {}}`, jail.Parse("b", testStorageBundle))
+
+	// items survive restart of jail, and are kept separately for each cell
+	jail.Stop()
+	require.Equal(t, `{"result": {}}`, jail.Parse("a", testStorageBundle))
+
+	items, err := jail.Storage("a")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"visits": "2"}, items)
+	items, err = jail.Storage("b")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"visits": "1"}, items)
+
+	cell, err := jail.Cell("a")
+	require.NoError(t, err)
+	value, err := cell.Run(`
+		localStorage.setItem('name', 'bot');
+		[localStorage.length, localStorage.key(0), localStorage.key(2), localStorage.getItem('missing')].join()
+	`)
+	require.NoError(t, err)
+	require.Equal(t, "2,name,,", value.String())
+
+	// 14 bytes are taken by 'visits', 'name' and their values, so 'data' (4 bytes) may take up to 14 bytes more
+	_, err = cell.Run(`localStorage.setItem('data', '012345678901234')`)
+	require.EqualError(t, err, "QuotaExceededError: localStorage quota exceeded")
+	_, err = cell.Run(`localStorage.setItem('data', '01234567890123')`)
+	require.NoError(t, err)
+
+	_, err = cell.Run(`localStorage.removeItem('visits')`)
+	require.NoError(t, err)
+	items, err = jail.Storage("a")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "bot", "data": "01234567890123"}, items)
+
+	require.NoError(t, jail.ClearStorage("a"))
+	value, err = cell.Run(`localStorage.length`)
+	require.NoError(t, err)
+	require.Equal(t, "0", value.String())
+	items, err = jail.Storage("b")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+}
@@ -24,6 +24,14 @@ Note, in this case parameters should be in in pairs (key, value).
 This logger is based upon log15-logger, so see its documentation for advanced usage: https://github.com/inconshreveable/log15
 
 
+Redaction
+
+Sensitive values are masked before log records are formatted: values of keys like "password" or "mnemonic"
+are never logged, while private keys and addresses (except their prefixes) are masked in messages and values.
+Values can be marked explicitly with typed wrappers:
+
+	log.Info("Account selected", "address", log.Address(address), "password", log.Secret(password))
+
 Initialization
 
 By default logger is set to log to stdout with Error level via `init()` function.
@@ -100,7 +108,7 @@ func levelFromString(level string) log.Lvl {
 // setHandler is a helper that allows log (re)initialization
 // with different level and handler. Useful for testing.
 func setHandler(lvl log.Lvl, handler log.Handler) {
-	h := log.LvlFilterHandler(lvl, handler)
+	h := log.LvlFilterHandler(lvl, redactHandler(handler))
 	logger.SetHandler(h)
 	log.Root().SetHandler(h) // ethereum-go logger
 }
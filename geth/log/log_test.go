@@ -2,6 +2,8 @@ package log
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"testing"
 
@@ -71,3 +73,32 @@ func TestLogFile(t *testing.T) {
 	require.Contains(t, got, info)
 	require.NotContains(t, got, debug)
 }
+
+func TestLogRedaction(t *testing.T) {
+	var records []*log.Record
+	handler := log.FuncHandler(func(r *log.Record) error {
+		records = append(records, r)
+		return nil
+	})
+	setHandler(log.LvlInfo, handler)
+	defer setHandler(logger.level, logger.handler)
+
+	const (
+		address    = "0xaddBf83ae0C65d7b8C9A1BAdC1F8E1D0Fb56B6e2"
+		privateKey = "2ae5ce2ce7e8e7a66e0adf5e7a7fb1b0e6d63e5bc7ee8e49d5e4e1e2a5c4f6d8"
+	)
+
+	Info("Account "+address+" imported with key "+privateKey,
+		"password", "pass", "Mnemonic", "one two three", "address", address, "to", Address(address),
+		"secret", Secret("pass"), "error", errors.New("invalid key "+privateKey), "count", 1)
+
+	require.Len(t, records, 1)
+	require.Equal(t, "Account 0xaddB… imported with key [REDACTED]", records[0].Msg)
+
+	var buf bytes.Buffer
+	for i := 0; i < len(records[0].Ctx); i += 2 {
+		fmt.Fprintf(&buf, "%v=%v ", records[0].Ctx[i], records[0].Ctx[i+1])
+	}
+	require.Equal(t, "geth=StatusIM password=[REDACTED] Mnemonic=[REDACTED] address=0xaddB… to=0xaddB… "+
+		"secret=[REDACTED] error=invalid key [REDACTED] count=1 ", buf.String())
+}
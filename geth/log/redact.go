@@ -0,0 +1,129 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// redacted replaces sensitive values in log output
+	redacted = "[REDACTED]"
+
+	// addressPrefixLength is a number of characters of address (including "0x"), kept in log output
+	addressPrefixLength = 6
+)
+
+var (
+	// sensitiveKeys are parts of names of context keys, values of which are never logged
+	sensitiveKeys = []string{"password", "passphrase", "mnemonic", "privatekey", "private_key", "secret", "seed"}
+
+	// privateKeyPattern matches hex-encoded private keys (without "0x", which hashes are usually logged with)
+	privateKeyPattern = regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`)
+
+	// addressPattern matches hex-encoded addresses
+	addressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+)
+
+// Secret is a value, which is never logged (e.g. password, mnemonic or private key):
+//
+//	log.Debug("Account recovered", "mnemonic", log.Secret(mnemonic))
+type Secret string
+
+// String implements fmt.Stringer interface.
+func (s Secret) String() string {
+	return redacted
+}
+
+// TerminalString implements log.TerminalStringer interface.
+func (s Secret) TerminalString() string {
+	return redacted
+}
+
+// Format implements fmt.Formatter interface, so that secret is redacted with any verb.
+func (s Secret) Format(f fmt.State, c rune) {
+	fmt.Fprint(f, redacted) // nolint: errcheck
+}
+
+// Address is a hex-encoded address, only prefix of which is logged:
+//
+//	log.Info("Account selected", "address", log.Address(address))
+type Address string
+
+// String implements fmt.Stringer interface.
+func (a Address) String() string {
+	return RedactAddress(string(a))
+}
+
+// TerminalString implements log.TerminalStringer interface.
+func (a Address) TerminalString() string {
+	return a.String()
+}
+
+// RedactAddress leaves only prefix of address.
+func RedactAddress(address string) string {
+	if len(address) <= addressPrefixLength {
+		return address
+	}
+
+	return address[:addressPrefixLength] + "…"
+}
+
+// Redact masks private keys and addresses (keeping their prefixes) in s.
+func Redact(s string) string {
+	s = privateKeyPattern.ReplaceAllString(s, redacted)
+	return addressPattern.ReplaceAllStringFunc(s, RedactAddress)
+}
+
+// redactHandler masks sensitive values of records, before they are formatted by handler:
+// values of sensitive keys (see sensitiveKeys) are replaced, while private keys and addresses
+// are masked in messages and string values.
+func redactHandler(handler log.Handler) log.Handler {
+	return log.FuncHandler(func(r *log.Record) error {
+		r.Msg = Redact(r.Msg)
+		for i := 1; i < len(r.Ctx); i += 2 {
+			r.Ctx[i] = redactValue(fmt.Sprint(r.Ctx[i-1]), r.Ctx[i])
+		}
+
+		return handler.Log(r)
+	})
+}
+
+// redactValue masks sensitive context value.
+func redactValue(key string, value interface{}) (result interface{}) {
+	defer func() {
+		// String() of nil pointer may panic, such values are formatted as "nil"
+		if recover() != nil {
+			result = value
+		}
+	}()
+
+	switch v := value.(type) {
+	case Secret, Address:
+		return v
+	}
+
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return Secret("")
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return Redact(v)
+	case error:
+		if s := v.Error(); Redact(s) != s {
+			return Redact(s)
+		}
+	case fmt.Stringer:
+		if s := v.String(); Redact(s) != s {
+			return Redact(s)
+		}
+	}
+
+	return value
+}
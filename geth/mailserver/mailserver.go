@@ -0,0 +1,356 @@
+// Package mailserver lets status-go node serve as Whisper mail server: envelopes passing through
+// the node are archived (in leveldb, for limited time), and delivered to peers, which request
+// historic messages they have missed while offline (see package mailservice).
+package mailserver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"path/filepath"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	// pruneInterval is an interval, archive is pruned of envelopes beyond retention limits at
+	pruneInterval = time.Hour
+
+	// archiveDir is a subdirectory of Whisper data dir, archive is stored in
+	archiveDir = "mailserver"
+)
+
+// errors
+var (
+	ErrNoPassword       = errors.New("password of mail server is not set")
+	ErrInvalidRequest   = errors.New("invalid request of historic messages")
+	ErrUnsignedRequest  = errors.New("request of historic messages is not signed by requesting peer")
+	ErrInsufficientWork = errors.New("insufficient PoW of request of historic messages")
+)
+
+// MailServer archives envelopes received by Whisper, and delivers them to peers on request.
+// It is a node service, which is to be registered after Whisper.
+type MailServer struct {
+	whisper      *whisper.Whisper
+	db           *leveldb.DB
+	key          []byte  // symmetric key of requests
+	pow          float64 // minimum PoW of requests
+	retention    time.Duration
+	maxEnvelopes int
+	now          func() time.Time
+
+	mu    sync.RWMutex // serializes updates of archive, and keeps it open while it's read
+	count int          // number of archived envelopes
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New opens archive of envelopes in subdirectory of Whisper data dir, and registers mail server in Whisper.
+// Requests are encrypted with key derived from password file, or MailServerPassword, if file is not set.
+func New(whisperService *whisper.Whisper, config *params.WhisperConfig) (*MailServer, error) {
+	password := config.MailServerPassword
+	if config.PasswordFile != "" {
+		data, err := config.ReadPasswordFile()
+		if err != nil {
+			return nil, err
+		}
+		password = string(data)
+	}
+	if password == "" {
+		return nil, ErrNoPassword
+	}
+
+	keyID, err := whisperService.AddSymKeyFromPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	key, err := whisperService.GetSymKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	db, err := leveldb.OpenFile(filepath.Join(config.DataDir, archiveDir), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newMailServer(whisperService, db, key, config)
+	if err != nil {
+		db.Close() // nolint: errcheck
+		return nil, err
+	}
+	whisperService.RegisterServer(s)
+
+	return s, nil
+}
+
+// newMailServer returns mail server, which archives envelopes in db.
+func newMailServer(whisperService *whisper.Whisper, db *leveldb.DB, key []byte, config *params.WhisperConfig) (*MailServer, error) {
+	s := &MailServer{
+		whisper:      whisperService,
+		db:           db,
+		key:          key,
+		pow:          config.MinimumPoW,
+		retention:    time.Duration(config.MailServerRetention) * time.Second,
+		maxEnvelopes: config.MailServerMaxEnvelopes,
+		now:          time.Now,
+	}
+
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		s.count++
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Protocols implements node.Service interface, mail server runs on top of Whisper protocol.
+func (s *MailServer) Protocols() []p2p.Protocol {
+	return nil
+}
+
+// APIs implements node.Service interface.
+func (s *MailServer) APIs() []rpc.API {
+	return nil
+}
+
+// Start implements node.Service interface, it starts pruning of archive.
+func (s *MailServer) Start(*p2p.Server) error {
+	s.quit = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+		for {
+			if err := s.prune(); err != nil {
+				log.Error("Failed to prune mail server archive", "error", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+
+	log.Info("Mail server started", "envelopes", s.count)
+	return nil
+}
+
+// Stop implements node.Service interface, it closes archive.
+func (s *MailServer) Stop() error {
+	if s.quit != nil {
+		close(s.quit)
+		s.wg.Wait()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// Archive implements whisper.MailServer interface, it stores envelope keyed by time it's sent at.
+func (s *MailServer) Archive(envelope *whisper.Envelope) {
+	key := dbKey(envelope.Expiry-envelope.TTL, envelope.Hash())
+	data, err := rlp.EncodeToBytes(envelope)
+	if err != nil {
+		log.Error("Failed to encode envelope", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists, err := s.db.Has(key, nil)
+	if err != nil {
+		log.Error("Failed to read mail server archive", "error", err)
+		return
+	}
+	if err := s.db.Put(key, data, nil); err != nil {
+		log.Error("Failed to archive envelope", "error", err)
+		return
+	}
+	if !exists {
+		s.count++
+	}
+}
+
+// DeliverMail implements whisper.MailServer interface, it sends requested envelopes to peer,
+// and notifies it once all of them are sent.
+func (s *MailServer) DeliverMail(peer *whisper.Peer, request *whisper.Envelope) {
+	if peer == nil {
+		return
+	}
+	peerID := gethcommon.Bytes2Hex(peer.ID())
+	lower, upper, topics, limit, err := s.validateRequest(peer.ID(), request)
+	if err != nil {
+		log.Warn("Invalid request of historic messages", "peer", peerID, "error", err)
+		return
+	}
+
+	envelopes, err := s.envelopes(lower, upper, topics, limit)
+	if err != nil {
+		log.Error("Failed to read mail server archive", "error", err)
+		return
+	}
+	for _, envelope := range envelopes {
+		if err := s.whisper.SendP2PDirect(peer, envelope); err != nil {
+			log.Warn("Failed to deliver envelope", "peer", peerID, "error", err)
+			return
+		}
+	}
+	if err := s.whisper.SendRequestCompleted(peer, request.Hash()); err != nil {
+		log.Warn("Failed to send request completion", "peer", peerID, "error", err)
+		return
+	}
+
+	log.Debug("Delivered historic messages", "peer", peerID, "envelopes", len(envelopes))
+}
+
+// validateRequest checks that request is signed by peer, and returns requested range, topics and limit.
+// Payload of request is lower and upper bounds of range, followed either by a single topic,
+// or by limit and one or more topics (an empty topic stands for all topics).
+func (s *MailServer) validateRequest(peerID []byte, request *whisper.Envelope) (lower, upper uint32, topics []whisper.TopicType, limit uint32, err error) {
+	if s.pow > 0 && request.PoW() < s.pow {
+		return 0, 0, nil, 0, ErrInsufficientWork
+	}
+
+	decrypted := request.Open(&whisper.Filter{KeySym: s.key})
+	if decrypted == nil || len(decrypted.Payload) < 8 {
+		return 0, 0, nil, 0, ErrInvalidRequest
+	}
+	if decrypted.Src == nil {
+		return 0, 0, nil, 0, ErrUnsignedRequest
+	}
+	src := crypto.FromECDSAPub(decrypted.Src)
+	if len(src)-len(peerID) == 1 {
+		src = src[1:]
+	}
+	if !bytes.Equal(peerID, src) {
+		return 0, 0, nil, 0, ErrUnsignedRequest
+	}
+
+	payload := decrypted.Payload
+	lower = binary.BigEndian.Uint32(payload)
+	upper = binary.BigEndian.Uint32(payload[4:])
+	payload = payload[8:]
+
+	switch {
+	case len(payload) > whisper.TopicLength && (len(payload)-4)%whisper.TopicLength == 0:
+		limit = binary.BigEndian.Uint32(payload)
+		for payload = payload[4:]; len(payload) > 0; payload = payload[whisper.TopicLength:] {
+			topics = append(topics, whisper.BytesToTopic(payload))
+		}
+	case len(payload) >= whisper.TopicLength:
+		topics = []whisper.TopicType{whisper.BytesToTopic(payload)}
+	default:
+		topics = []whisper.TopicType{{}}
+	}
+
+	return lower, upper, topics, limit, nil
+}
+
+// envelopes returns archived envelopes of topics, sent within range [lower, upper], at most limit
+// of them, unless limit is zero.
+func (s *MailServer) envelopes(lower, upper uint32, topics []whisper.TopicType, limit uint32) ([]*whisper.Envelope, error) {
+	var envelopes []*whisper.Envelope
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rng := &util.Range{Start: dbKey(lower, gethcommon.Hash{})}
+	if upper < math.MaxUint32 {
+		rng.Limit = dbKey(upper+1, gethcommon.Hash{})
+	}
+	it := s.db.NewIterator(rng, nil)
+	defer it.Release()
+	for it.Next() {
+		if limit > 0 && uint32(len(envelopes)) >= limit {
+			break
+		}
+		var envelope whisper.Envelope
+		if err := rlp.DecodeBytes(it.Value(), &envelope); err != nil {
+			log.Warn("Failed to decode archived envelope", "error", err)
+			continue
+		}
+		if matchTopics(topics, envelope.Topic) {
+			envelopes = append(envelopes, &envelope)
+		}
+	}
+
+	return envelopes, it.Error()
+}
+
+// prune removes envelopes sent before retention period, and the oldest envelopes beyond maximum
+// number of archived envelopes.
+func (s *MailServer) prune() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cutoff uint32
+	if s.retention > 0 {
+		cutoff = uint32(s.now().Add(-s.retention).Unix())
+	}
+
+	batch := new(leveldb.Batch)
+	it := s.db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		old := binary.BigEndian.Uint32(it.Key()) < cutoff
+		excess := s.maxEnvelopes > 0 && s.count-batch.Len() > s.maxEnvelopes
+		if !old && !excess {
+			break
+		}
+		batch.Delete(append([]byte{}, it.Key()...))
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	if err := s.db.Write(batch, nil); err != nil {
+		return err
+	}
+
+	s.count -= batch.Len()
+	log.Info("Pruned mail server archive", "removed", batch.Len(), "envelopes", s.count)
+	return nil
+}
+
+// dbKey returns key of envelope in archive: time it's sent at, followed by its hash.
+func dbKey(timestamp uint32, hash gethcommon.Hash) []byte {
+	key := make([]byte, 4+gethcommon.HashLength)
+	binary.BigEndian.PutUint32(key, timestamp)
+	copy(key[4:], hash[:])
+	return key
+}
+
+// matchTopics checks whether topic is one of requested topics, an empty one matches any topic.
+func matchTopics(topics []whisper.TopicType, topic whisper.TopicType) bool {
+	var empty whisper.TopicType
+	for _, t := range topics {
+		if t == empty || t == topic {
+			return true
+		}
+	}
+	return false
+}
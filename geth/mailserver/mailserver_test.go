@@ -0,0 +1,105 @@
+package mailserver
+
+import (
+	"testing"
+	"time"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+func newTestMailServer(t *testing.T, config *params.WhisperConfig) *MailServer {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	require.NoError(t, err)
+	s, err := newMailServer(whisper.New(nil), db, make([]byte, 32), config)
+	require.NoError(t, err)
+	return s
+}
+
+func newTestEnvelope(sent uint32, topic string) *whisper.Envelope {
+	return &whisper.Envelope{
+		Version: []byte{0},
+		Expiry:  sent + 10,
+		TTL:     10,
+		Topic:   whisper.BytesToTopic([]byte(topic)),
+		Data:    []byte(topic),
+	}
+}
+
+func TestArchiveAndEnvelopes(t *testing.T) {
+	s := newTestMailServer(t, &params.WhisperConfig{})
+	defer s.Stop() // nolint: errcheck
+
+	s.Archive(newTestEnvelope(10, "abcd"))
+	s.Archive(newTestEnvelope(20, "efgh"))
+	s.Archive(newTestEnvelope(30, "abcd"))
+	s.Archive(newTestEnvelope(30, "abcd")) // duplicate is archived once
+	require.Equal(t, 3, s.count)
+
+	all := []whisper.TopicType{{}}
+	envelopes, err := s.envelopes(0, 100, all, 0)
+	require.NoError(t, err)
+	require.Len(t, envelopes, 3)
+
+	// range is inclusive
+	envelopes, err = s.envelopes(20, 30, all, 0)
+	require.NoError(t, err)
+	require.Len(t, envelopes, 2)
+
+	envelopes, err = s.envelopes(0, 100, []whisper.TopicType{whisper.BytesToTopic([]byte("abcd"))}, 0)
+	require.NoError(t, err)
+	require.Len(t, envelopes, 2)
+	for _, envelope := range envelopes {
+		require.Equal(t, []byte("abcd"), envelope.Data)
+	}
+
+	envelopes, err = s.envelopes(0, 100, all, 1)
+	require.NoError(t, err)
+	require.Len(t, envelopes, 1)
+	require.Equal(t, uint32(20), envelopes[0].Expiry)
+}
+
+func TestPrune(t *testing.T) {
+	s := newTestMailServer(t, &params.WhisperConfig{
+		MailServerRetention:    100,
+		MailServerMaxEnvelopes: 2,
+	})
+	defer s.Stop() // nolint: errcheck
+	s.now = func() time.Time { return time.Unix(150, 0) }
+
+	for _, sent := range []uint32{10, 60, 70, 80} {
+		s.Archive(newTestEnvelope(sent, "abcd"))
+	}
+	require.NoError(t, s.prune())
+	require.Equal(t, 2, s.count)
+
+	// envelope sent before retention period, and the oldest one beyond maximum are removed
+	envelopes, err := s.envelopes(0, 100, []whisper.TopicType{{}}, 0)
+	require.NoError(t, err)
+	require.Len(t, envelopes, 2)
+	require.Equal(t, uint32(80), envelopes[0].Expiry)
+	require.Equal(t, uint32(90), envelopes[1].Expiry)
+}
+
+func TestEnvelopesOfStoppedMailServer(t *testing.T) {
+	s := newTestMailServer(t, &params.WhisperConfig{})
+	s.Archive(newTestEnvelope(10, "abcd"))
+
+	// requests being delivered keep archive open, and fail once it's closed
+	s.mu.RLock()
+	stopped := make(chan error)
+	go func() { stopped <- s.Stop() }()
+	select {
+	case <-stopped:
+		t.Fatal("archive is closed while it's read")
+	case <-time.After(50 * time.Millisecond):
+	}
+	s.mu.RUnlock()
+	require.NoError(t, <-stopped)
+
+	_, err := s.envelopes(0, 100, []whisper.TopicType{{}}, 0)
+	require.Equal(t, leveldb.ErrClosed, err)
+}
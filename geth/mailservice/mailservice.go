@@ -0,0 +1,290 @@
+// Package mailservice requests historic (expired) Whisper messages from mail servers, so that users
+// receive messages sent while they were offline, and reports completion of requests with signals.
+package mailservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/backoff"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventMailServerRequestCompleted is triggered when mail server reports that requested messages are sent
+	EventMailServerRequestCompleted = "mailserver.request.completed"
+
+	// EventMailServerRequestExpired is triggered when mail server doesn't complete request in time
+	EventMailServerRequestExpired = "mailserver.request.expired"
+)
+
+const (
+	// requestTimeout is time, within which mail server must report that request is completed
+	requestTimeout = 30 * time.Second
+
+	// requestWorkTime is time (in seconds) spent on PoW of request to mail server
+	requestWorkTime = 5
+)
+
+// requestPolicy retries requests, which fail as mail server is not connected (yet)
+var requestPolicy = backoff.Policy{
+	Backoff:     backoff.Backoff{Base: 500 * time.Millisecond, Max: 2 * time.Second, Jitter: 0.5},
+	MaxAttempts: 4,
+}
+
+// errors
+var (
+	ErrNoMailServer       = errors.New("no mail server is configured")
+	ErrInvalidRange       = errors.New("invalid range of historic messages, 'from' is after 'to'")
+	ErrNoMailServerSymKey = errors.New("password of mail servers is not configured")
+)
+
+// RequestEvent is sent with EventMailServerRequestCompleted and EventMailServerRequestExpired signals.
+type RequestEvent struct {
+	RequestID string `json:"requestID"`
+	Peer      string `json:"peer"`
+}
+
+// Service sends requests of historic messages to mail servers, and tracks them until mail server
+// reports that requested messages are sent, or request expires.
+type Service struct {
+	nodeManager common.NodeManager
+	timeout     time.Duration
+
+	mu       sync.Mutex
+	symKeyID string                       // id of key derived from password of mail servers, once it's added
+	requests map[gethcommon.Hash]*request // requests, which are not completed yet
+}
+
+// request is a request of historic messages, sent to mail server.
+type request struct {
+	peer    discover.NodeID
+	expired *time.Timer
+}
+
+// New returns new mail service.
+func New(nodeManager common.NodeManager) *Service {
+	return &Service{
+		nodeManager: nodeManager,
+		timeout:     requestTimeout,
+		requests:    make(map[gethcommon.Hash]*request),
+	}
+}
+
+// Start starts tracking completion of requests by Whisper of the running node.
+// It is to be called whenever node is started, as Whisper service is re-created.
+func (s *Service) Start() error {
+	whisperService, err := s.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.symKeyID = ""
+	s.mu.Unlock()
+
+	whisperService.RegisterRequestTracker(s)
+
+	return nil
+}
+
+// Stop forgets requests in progress, their completion can not be reported anymore.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, req := range s.requests {
+		req.expired.Stop()
+		delete(s.requests, id)
+	}
+}
+
+// RequestHistoricMessages requests messages of topics (all topics, if none is given), sent within range
+// [from, to] (unix time), from mail server, which must be connected as a peer. At most limit messages
+// are requested, unless limit is zero. The first of configured mail servers is requested, if peer
+// (enode URL) is empty. Id of request is returned, it's reported with EventMailServerRequestCompleted
+// signal, once requested messages are sent, or EventMailServerRequestExpired one.
+func (s *Service) RequestHistoricMessages(peer string, topics []whisper.TopicType, from, to, limit uint32) (gethcommon.Hash, error) {
+	if from > to {
+		return gethcommon.Hash{}, ErrInvalidRange
+	}
+	mailServer, err := s.mailServer(peer)
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+
+	envelope, err := s.makeEnvelope(requestPayload(topics, from, to, limit))
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	requestID := envelope.Hash()
+
+	whisperService, err := s.nodeManager.WhisperService()
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	err = backoff.Retry(context.Background(), requestPolicy, func(attempt int) error {
+		err := whisperService.RequestHistoricMessages(mailServer.ID[:], envelope)
+		if err != nil {
+			log.Debug("Mail server request failed", "peer", mailServer.ID.String(), "attempt", attempt, "error", err)
+		}
+		return err
+	})
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+
+	s.track(requestID, mailServer.ID)
+	log.Info("Requested historic messages", "request", requestID.Hex(), "peer", mailServer.ID.String(),
+		"topics", len(topics), "from", from, "to", to, "limit", limit)
+
+	return requestID, nil
+}
+
+// RequestCompleted implements whisper.RequestTracker interface.
+func (s *Service) RequestCompleted(peerID []byte, requestID gethcommon.Hash) {
+	s.mu.Lock()
+	req, ok := s.requests[requestID]
+	if !ok || !bytes.Equal(req.peer[:], peerID) {
+		s.mu.Unlock()
+		return
+	}
+	req.expired.Stop()
+	delete(s.requests, requestID)
+	s.mu.Unlock()
+
+	log.Info("Mail server request completed", "request", requestID.Hex())
+	signal.Send(signal.Envelope{
+		Type: EventMailServerRequestCompleted,
+		Event: RequestEvent{
+			RequestID: requestID.Hex(),
+			Peer:      req.peer.String(),
+		},
+	})
+}
+
+// track starts waiting for completion of request, sent to peer.
+func (s *Service) track(requestID gethcommon.Hash, peer discover.NodeID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req := &request{peer: peer}
+	req.expired = time.AfterFunc(s.timeout, func() {
+		s.mu.Lock()
+		if s.requests[requestID] != req {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.requests, requestID)
+		s.mu.Unlock()
+
+		log.Warn("Mail server request expired", "request", requestID.Hex())
+		signal.Send(signal.Envelope{
+			Type: EventMailServerRequestExpired,
+			Event: RequestEvent{
+				RequestID: requestID.Hex(),
+				Peer:      peer.String(),
+			},
+		})
+	})
+	s.requests[requestID] = req
+}
+
+// mailServer returns node of mail server, given its enode URL, or the first configured one.
+func (s *Service) mailServer(peer string) (*discover.Node, error) {
+	if peer == "" {
+		config, err := s.nodeManager.NodeConfig()
+		if err != nil {
+			return nil, err
+		}
+		if len(config.WhisperConfig.MailServerNodes) == 0 {
+			return nil, ErrNoMailServer
+		}
+		peer = config.WhisperConfig.MailServerNodes[0]
+	}
+
+	return discover.ParseNode(peer)
+}
+
+// requestPayload encodes requested range, limit and topics: bounds of range are followed by limit,
+// and one or more topics (an empty topic stands for all topics).
+func requestPayload(topics []whisper.TopicType, from, to, limit uint32) []byte {
+	if len(topics) == 0 {
+		topics = []whisper.TopicType{{}}
+	}
+
+	payload := make([]byte, 12, 12+len(topics)*whisper.TopicLength)
+	binary.BigEndian.PutUint32(payload, from)
+	binary.BigEndian.PutUint32(payload[4:], to)
+	binary.BigEndian.PutUint32(payload[8:], limit)
+	for _, topic := range topics {
+		payload = append(payload, topic[:]...)
+	}
+	return payload
+}
+
+// makeEnvelope creates request envelope, signed with node's key (as mail server checks
+// that request comes from peer it is sent by), and encrypted with key derived from password
+// of mail servers.
+func (s *Service) makeEnvelope(payload []byte) (*whisper.Envelope, error) {
+	config, err := s.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	node, err := s.nodeManager.Node()
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.symKey(config.WhisperConfig.MailServerPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &whisper.MessageParams{
+		PoW:      config.WhisperConfig.MinimumPoW,
+		Payload:  payload,
+		KeySym:   key,
+		Src:      node.Server().PrivateKey,
+		WorkTime: requestWorkTime,
+	}
+	message, err := whisper.NewSentMessage(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return message.Wrap(params)
+}
+
+// symKey returns symmetric key of requests, derived from password of mail servers.
+// Key is added to Whisper once, and reused afterwards.
+func (s *Service) symKey(password string) ([]byte, error) {
+	if password == "" {
+		return nil, ErrNoMailServerSymKey
+	}
+	whisperService, err := s.nodeManager.WhisperService()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.symKeyID == "" {
+		id, err := whisperService.AddSymKeyFromPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		s.symKeyID = id
+	}
+
+	return whisperService.GetSymKey(s.symKeyID)
+}
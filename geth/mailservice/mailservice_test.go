@@ -0,0 +1,102 @@
+package mailservice
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestPayload(t *testing.T) {
+	topics := []whisper.TopicType{whisper.BytesToTopic([]byte("abcd")), whisper.BytesToTopic([]byte("efgh"))}
+	require.Equal(t, []byte{
+		0, 0, 0, 10, // from
+		0, 0, 0, 20, // to
+		0, 0, 0, 100, // limit
+		'a', 'b', 'c', 'd',
+		'e', 'f', 'g', 'h',
+	}, requestPayload(topics, 10, 20, 100))
+
+	// all topics are requested with an empty topic
+	require.Equal(t, []byte{0, 0, 0, 10, 0, 0, 0, 20, 0, 0, 0, 0, 0, 0, 0, 0}, requestPayload(nil, 10, 20, 0))
+}
+
+func TestRequestHistoricMessagesErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{},
+	}, nil).AnyTimes()
+	service := New(nodeManager)
+
+	_, err := service.RequestHistoricMessages("", nil, 20, 10, 0)
+	require.Equal(t, ErrInvalidRange, err)
+	_, err = service.RequestHistoricMessages("", nil, 10, 20, 0)
+	require.Equal(t, ErrNoMailServer, err)
+	_, err = service.RequestHistoricMessages("invalid", nil, 10, 20, 0)
+	require.Error(t, err)
+}
+
+func TestRequestTracking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	whisperService := whisper.New(nil)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+
+	service := New(nodeManager)
+	service.timeout = 50 * time.Millisecond
+	require.NoError(t, service.Start())
+	defer service.Stop()
+
+	events := make(chan signal.Envelope, 10)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event RequestEvent
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		events <- signal.Envelope{Type: envelope.Type, Event: envelope.Event}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	peer := discover.NodeID{1}
+	completed := gethcommon.HexToHash("0x01")
+	service.track(completed, peer)
+
+	// completions of other peers, and unknown requests are ignored
+	other := discover.NodeID{2}
+	service.RequestCompleted(other[:], completed)
+	service.RequestCompleted(peer[:], gethcommon.HexToHash("0x03"))
+
+	service.RequestCompleted(peer[:], completed)
+	envelope := <-events
+	require.Equal(t, EventMailServerRequestCompleted, envelope.Type)
+	require.Equal(t, RequestEvent{RequestID: completed.Hex(), Peer: peer.String()}, envelope.Event)
+
+	// requests, which are not completed in time, expire
+	expired := gethcommon.HexToHash("0x02")
+	service.track(expired, peer)
+	envelope = <-events
+	require.Equal(t, EventMailServerRequestExpired, envelope.Type)
+	require.Equal(t, RequestEvent{RequestID: expired.Hex(), Peer: peer.String()}, envelope.Event)
+
+	// completion of expired request is not reported
+	service.RequestCompleted(peer[:], expired)
+	select {
+	case envelope := <-events:
+		require.Fail(t, "unexpected signal", envelope.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/syndtr/goleveldb/leveldb"
+	leveldberrors "github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// chainDataDirName is a name of directory (in node's instance dir), where LES keeps chain data
+const chainDataDirName = "lightchaindata"
+
+// chainDataDir returns path to chain data of a node with a given config.
+func chainDataDir(config *params.NodeConfig) string {
+	return filepath.Join(config.DataDir, config.Name, chainDataDirName)
+}
+
+// isChainDataCorrupted returns true, if error indicates corruption of leveldb database,
+// which happens e.g. after unclean shutdown of node.
+func isChainDataCorrupted(err error) bool {
+	return leveldberrors.IsCorrupted(err)
+}
+
+// repairChainData attempts to recover a corrupted leveldb database in dir, by rebuilding
+// its manifest from table files available (so that only corrupted records are lost).
+func repairChainData(dir string) error {
+	db, err := leveldb.RecoverFile(dir, nil)
+	if err != nil {
+		return err
+	}
+
+	return db.Close()
+}
+
+// checkChainData opens chain data in dir (if any), to detect its corruption before node is started,
+// and attempts to repair corrupted data. Corruption error is returned, if data can't be repaired.
+func checkChainData(dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := leveldb.OpenFile(dir, &opt.Options{ReadOnly: true})
+	if err == nil {
+		return db.Close()
+	}
+	if !isChainDataCorrupted(err) {
+		// other failures are reported by node, once it opens chain data itself
+		log.Warn("Failed to check chain data", "dir", dir, "error", err)
+		return nil
+	}
+
+	log.Warn("Chain data is corrupted, attempting repair", "dir", dir, "error", err)
+	if repairErr := repairChainData(dir); repairErr != nil {
+		log.Error("Chain data repair failed", "dir", dir, "error", repairErr)
+		return err
+	}
+	log.Info("Chain data has been repaired", "dir", dir)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func TestCheckChainData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chaindata")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	// missing chain data is not checked
+	require.NoError(t, checkChainData(filepath.Join(dir, "missing")))
+	_, err = os.Stat(filepath.Join(dir, "missing"))
+	require.True(t, os.IsNotExist(err))
+
+	db, err := leveldb.OpenFile(dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Put([]byte("key"), []byte("value"), nil))
+	require.NoError(t, db.CompactRange(util.Range{}))
+	require.NoError(t, db.Close())
+	require.NoError(t, checkChainData(dir))
+
+	// manifest is corrupted e.g. by unclean shutdown
+	current, err := ioutil.ReadFile(filepath.Join(dir, "CURRENT"))
+	require.NoError(t, err)
+	manifest := filepath.Join(dir, strings.TrimSpace(string(current)))
+	require.NoError(t, ioutil.WriteFile(manifest, []byte("garbage"), 0600))
+
+	_, err = leveldb.OpenFile(dir, nil)
+	require.True(t, isChainDataCorrupted(err))
+
+	// data is repaired, and could be opened again
+	require.NoError(t, checkChainData(dir))
+	db, err = leveldb.OpenFile(dir, nil)
+	require.NoError(t, err)
+	defer db.Close() // nolint: errcheck
+	value, err := db.Get([]byte("key"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "value", string(value))
+}
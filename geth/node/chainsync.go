@@ -0,0 +1,39 @@
+package node
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/status-im/status-go/geth/progress"
+)
+
+// chainSyncInterval is an interval progress of chain synchronization is checked with
+const chainSyncInterval = 3 * time.Second
+
+// watchChainSync reports progress of chain synchronization (see progress.OperationChainSync),
+// until quit is closed. Synchronization is in progress, while the highest known block is ahead
+// of the current one.
+func watchChainSync(syncProgress func() ethereum.SyncProgress, interval time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var tracker *progress.Tracker
+	for {
+		select {
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+
+		p := syncProgress()
+		if p.HighestBlock > p.CurrentBlock {
+			if tracker == nil {
+				tracker = progress.NewTracker(progress.OperationChainSync)
+			}
+			tracker.Update("headers", p.CurrentBlock-p.StartingBlock, p.HighestBlock-p.StartingBlock)
+		} else if tracker != nil {
+			tracker.Done()
+			tracker = nil
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package node
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchChainSync(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.Contains(event, signal.EventOperationProgress) {
+			events = append(events, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	progresses := make(chan ethereum.SyncProgress)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		watchChainSync(func() ethereum.SyncProgress { return <-progresses }, time.Millisecond, quit)
+		close(done)
+	}()
+
+	progresses <- ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 100, HighestBlock: 100}
+	progresses <- ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 150, HighestBlock: 200}
+	progresses <- ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 200, HighestBlock: 200}
+	progresses <- ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 200, HighestBlock: 200}
+	close(quit)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 2)
+	require.Contains(t, events[0], `"operation":"chain.sync","phase":"headers","percent":50`)
+	require.Contains(t, events[1], `"phase":"done","percent":100`)
+}
@@ -0,0 +1,195 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+)
+
+const (
+	// fleetCacheFile is a file within data directory, last verified fleet registry is cached in
+	fleetCacheFile = "fleet.json"
+
+	// fleetFetchTimeout is a timeout of fleet registry request
+	fleetFetchTimeout = 10 * time.Second
+
+	// fleetMaxSize is a maximum size (in bytes) of fleet registry document
+	fleetMaxSize = 1024 * 1024
+)
+
+// errors
+var (
+	ErrFleetRegistryKey       = errors.New("fleet registry key is invalid")
+	ErrFleetSignature         = errors.New("fleet registry signature is invalid")
+	ErrFleetOutdated          = errors.New("fleet registry is older than the cached one")
+	ErrFleetNetworkNotFound   = errors.New("fleet registry has no boot nodes for the network")
+	ErrFleetRegistryNotCached = errors.New("fleet registry is not cached")
+	ErrFleetRegistryTooLarge  = errors.New("fleet registry exceeds maximum size")
+)
+
+// signedFleet is a fleet registry document, as it is served and cached.
+// Signature is made over Keccak256 hash of exact bytes of the fleet.
+type signedFleet struct {
+	Fleet     json.RawMessage `json:"fleet"`
+	Signature hexutil.Bytes   `json:"signature"`
+}
+
+// fleetCluster lists boot nodes of a single network.
+type fleetCluster struct {
+	NetworkID uint64 `json:"networkID"`
+	Prod      struct {
+		BootNodes []string `json:"bootnodes"`
+	} `json:"prod"`
+	Dev struct {
+		BootNodes []string `json:"bootnodes"`
+	} `json:"dev"`
+}
+
+// fleet is a payload of fleet registry.
+type fleet struct {
+	Timestamp int64          `json:"timestamp"`
+	Clusters  []fleetCluster `json:"clusters"`
+}
+
+// bootNodes returns boot nodes for a given network and mode.
+func (f *fleet) bootNodes(networkID uint64, devMode bool) ([]string, error) {
+	for _, cluster := range f.Clusters {
+		if cluster.NetworkID != networkID {
+			continue
+		}
+
+		bootNodes := cluster.Prod.BootNodes
+		if devMode {
+			bootNodes = cluster.Dev.BootNodes
+		}
+		if len(bootNodes) == 0 {
+			break
+		}
+
+		return bootNodes, nil
+	}
+
+	return nil, ErrFleetNetworkNotFound
+}
+
+// fleetBootNodes returns boot nodes listed in the fleet registry configured.
+// Registry is fetched and cached in data directory; cached copy is used,
+// if registry is unavailable or fetched one is older than cached.
+func fleetBootNodes(config *params.NodeConfig) ([]string, error) {
+	registryKey, err := hexutil.Decode(config.BootClusterConfig.RegistryKey)
+	if err != nil || len(registryKey) == 0 {
+		return nil, ErrFleetRegistryKey
+	}
+
+	cacheFile := filepath.Join(config.DataDir, fleetCacheFile)
+	cached, cachedErr := loadFleet(cacheFile, registryKey)
+	if cachedErr != nil && cachedErr != ErrFleetRegistryNotCached {
+		log.Warn("Cached fleet registry is ignored", "file", cacheFile, "error", cachedErr)
+	}
+
+	fetched, err := fetchFleet(config.BootClusterConfig.RegistryURL, registryKey)
+	if err == nil && cached != nil && fetched.Timestamp < cached.Timestamp {
+		err = ErrFleetOutdated
+	}
+
+	current := cached
+	if err == nil {
+		current = fetched
+		if err := saveFleet(cacheFile, fetched.raw); err != nil {
+			log.Warn("Failed to cache fleet registry", "file", cacheFile, "error", err)
+		}
+	} else {
+		log.Warn("Fleet registry is not fetched", "url", params.RedactURL(config.BootClusterConfig.RegistryURL), "error", err)
+	}
+
+	if current == nil {
+		return nil, fmt.Errorf("fleet registry is unavailable: %v", err)
+	}
+
+	return current.bootNodes(config.NetworkID, config.DevMode)
+}
+
+// verifiedFleet is a fleet with signed document it has been decoded from.
+type verifiedFleet struct {
+	fleet
+	raw []byte
+}
+
+// fetchFleet downloads and verifies fleet registry.
+func fetchFleet(url string, registryKey []byte) (*verifiedFleet, error) {
+	client := http.Client{Timeout: fleetFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		if urlErr, ok := err.(*neturl.Error); ok {
+			urlErr.URL = params.RedactURL(urlErr.URL)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	// one byte above limit is read to tell too large document from the one of maximum size
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, fleetMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > fleetMaxSize {
+		return nil, ErrFleetRegistryTooLarge
+	}
+
+	return verifyFleet(data, registryKey)
+}
+
+// loadFleet reads and verifies cached fleet registry.
+func loadFleet(path string, registryKey []byte) (*verifiedFleet, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrFleetRegistryNotCached
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyFleet(data, registryKey)
+}
+
+// saveFleet caches signed fleet registry document.
+func saveFleet(path string, data []byte) error {
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// verifyFleet decodes fleet registry document, making sure it is signed with a given key.
+func verifyFleet(data []byte, registryKey []byte) (*verifiedFleet, error) {
+	var doc signedFleet
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	signer, err := crypto.SigToPub(crypto.Keccak256(doc.Fleet), doc.Signature)
+	if err != nil || !bytes.Equal(crypto.FromECDSAPub(signer), registryKey) {
+		return nil, ErrFleetSignature
+	}
+
+	f := &verifiedFleet{raw: data}
+	if err := json.Unmarshal(doc.Fleet, &f.fleet); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
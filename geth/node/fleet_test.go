@@ -0,0 +1,129 @@
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSignedFleet(t *testing.T, timestamp int64, bootNode string) ([]byte, []byte) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	return signFleet(t, crypto.FromECDSA(key), timestamp, bootNode), crypto.FromECDSAPub(&key.PublicKey)
+}
+
+func signFleet(t *testing.T, rawKey []byte, timestamp int64, bootNode string) []byte {
+	key, err := crypto.ToECDSA(rawKey)
+	require.NoError(t, err)
+
+	var f fleet
+	f.Timestamp = timestamp
+	f.Clusters = []fleetCluster{{NetworkID: params.RopstenNetworkID}}
+	f.Clusters[0].Prod.BootNodes = []string{bootNode}
+	payload, err := json.Marshal(f)
+	require.NoError(t, err)
+
+	sig, err := crypto.Sign(crypto.Keccak256(payload), key)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(signedFleet{Fleet: payload, Signature: sig})
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestFleetBootNodes(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	rawKey := crypto.FromECDSA(key)
+	pubKey := crypto.FromECDSAPub(&key.PublicKey)
+
+	var served []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if served == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(served) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	dataDir, err := ioutil.TempDir("", "fleet")
+	require.NoError(t, err)
+	defer os.RemoveAll(dataDir) // nolint: errcheck
+
+	config := &params.NodeConfig{
+		DataDir:   dataDir,
+		NetworkID: params.RopstenNetworkID,
+		BootClusterConfig: &params.BootClusterConfig{
+			RegistryURL: server.URL,
+			RegistryKey: hexutil.Encode(pubKey),
+		},
+	}
+
+	// neither registry nor cache is available
+	_, err = fleetBootNodes(config)
+	require.Error(t, err)
+
+	// registry is fetched and cached
+	served = signFleet(t, rawKey, 2, "enode://second")
+	bootNodes, err := fleetBootNodes(config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"enode://second"}, bootNodes)
+
+	// older registry is ignored in favour of cached one
+	served = signFleet(t, rawKey, 1, "enode://first")
+	bootNodes, err = fleetBootNodes(config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"enode://second"}, bootNodes)
+
+	// registry signed with a different key is ignored
+	served, _ = makeSignedFleet(t, 3, "enode://forged")
+	bootNodes, err = fleetBootNodes(config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"enode://second"}, bootNodes)
+
+	// cache is used, when registry is unavailable
+	served = nil
+	bootNodes, err = fleetBootNodes(config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"enode://second"}, bootNodes)
+
+	// no boot nodes for the network
+	config.NetworkID = params.RinkebyNetworkID
+	_, err = fleetBootNodes(config)
+	require.Equal(t, ErrFleetNetworkNotFound, err)
+}
+
+func TestVerifyFleetSignature(t *testing.T) {
+	data, registryKey := makeSignedFleet(t, 1, "enode://node")
+
+	f, err := verifyFleet(data, registryKey)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, f.Timestamp)
+
+	// tampered payload
+	tampered := []byte(string(data))
+	copy(tampered[len(`{"fleet":{"timestamp":`):], "2")
+	_, err = verifyFleet(tampered, registryKey)
+	require.Equal(t, ErrFleetSignature, err)
+}
+
+func TestFetchFleetSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, fleetMaxSize+1)) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	_, err := fetchFleet(server.URL, nil)
+	require.Equal(t, ErrFleetRegistryTooLarge, err)
+}
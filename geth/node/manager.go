@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -15,6 +14,7 @@ import (
 	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
 	"github.com/status-im/status-go/geth/log"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/peers"
 	"github.com/status-im/status-go/geth/rpc"
 	"github.com/status-im/status-go/geth/signal"
 )
@@ -35,18 +35,37 @@ var (
 type NodeManager struct {
 	sync.RWMutex
 	config         *params.NodeConfig // Status node configuration
+	lastConfig     *params.NodeConfig // configuration of the last started node (kept, when node is stopped)
+	corruptConfig  *params.NodeConfig // configuration of node, which failed to start due to corrupted chain data
 	node           *node.Node         // reference to Geth P2P stack/node
 	nodeStarted    chan struct{}      // channel to wait for start up notifications
 	nodeStopped    chan struct{}      // channel to wait for termination notifications
 	whisperService *whisper.Whisper   // reference to Whisper service
 	lesService     *les.LightEthereum // reference to LES service
 	rpcClient      *rpc.Client        // reference to RPC client
+	rpcEndpoint    *rpcEndpoint       // JSON-RPC servers opened at runtime
+	peerMonitor    *peers.Monitor     // latency and quality measurements of peers
+	peerPool       *peers.Pool        // drops the least useful peers, once all connection slots are taken
+
+	signalHandlerDisabled bool         // whether interrupt signals are left to the host process
+	panicHandler          PanicHandler // handler of panics in background routines
 }
 
 // NewNodeManager makes new instance of node manager
-func NewNodeManager() *NodeManager {
-	m := &NodeManager{}
-	go HaltOnInterruptSignal(m) // allow interrupting running nodes
+func NewNodeManager(opts ...Option) *NodeManager {
+	m := &NodeManager{
+		panicHandler: haltOnPanic,
+		peerMonitor:  peers.NewMonitor(peers.DefaultRequestTimeout),
+	}
+	m.peerPool = peers.NewPool(m.peerMonitor)
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if !m.signalHandlerDisabled {
+		go HaltOnInterruptSignal(m) // allow interrupting running nodes
+	}
 
 	return m
 }
@@ -67,7 +86,7 @@ func (m *NodeManager) startNode(config *params.NodeConfig) (<-chan struct{}, err
 
 	m.initLog(config)
 
-	ethNode, err := MakeNode(config)
+	ethNode, err := makeNode(config, m.peerMonitor)
 	if err != nil {
 		return nil, err
 	}
@@ -75,14 +94,33 @@ func (m *NodeManager) startNode(config *params.NodeConfig) (<-chan struct{}, err
 	m.nodeStarted = make(chan struct{}, 1)
 
 	go func() {
-		defer HaltOnPanic()
+		defer m.recoverOnPanic()
 
-		// start underlying node
-		if err := ethNode.Start(); err != nil {
+		// repair chain data corrupted e.g. by unclean shutdown, before it is opened by LES
+		err := checkChainData(chainDataDir(config))
+		if err == nil {
+			// start underlying node
+			err = ethNode.Start()
+		}
+		if err != nil {
 			close(m.nodeStarted)
 			m.Lock()
 			m.nodeStarted = nil
+			if isChainDataCorrupted(err) {
+				m.corruptConfig = config
+			}
 			m.Unlock()
+			if isChainDataCorrupted(err) {
+				log.Error("Chain data is corrupted", "dir", chainDataDir(config), "error", err)
+				signal.Send(signal.Envelope{
+					Type: signal.EventChainDataCorrupted,
+					Event: signal.ChainDataCorruptedEvent{
+						Error: err.Error(),
+						Dir:   chainDataDir(config),
+					},
+				})
+				return
+			}
 			signal.Send(signal.Envelope{
 				Type: signal.EventNodeCrashed,
 				Event: signal.NodeCrashEvent{
@@ -96,6 +134,13 @@ func (m *NodeManager) startNode(config *params.NodeConfig) (<-chan struct{}, err
 		m.node = ethNode
 		m.nodeStopped = make(chan struct{}, 1)
 		m.config = config
+		m.corruptConfig = nil
+
+		var configChanges []params.ConfigChange
+		if m.lastConfig != nil {
+			configChanges = m.lastConfig.Diff(config)
+		}
+		m.lastConfig = config
 
 		// init RPC client for this node
 		m.rpcClient, err = rpc.NewClient(m.node, m.config.UpstreamConfig)
@@ -112,6 +157,18 @@ func (m *NodeManager) startNode(config *params.NodeConfig) (<-chan struct{}, err
 		}
 		m.Unlock()
 
+		if len(configChanges) > 0 {
+			reportConfigChanges(configChanges)
+		}
+
+		m.peerMonitor.SetMailServers(parseNodeIDs(config.WhisperConfig.MailServerNodes))
+		m.peerPool.Start(ethNode.Server())
+
+		var lightEthereum *les.LightEthereum
+		if err := ethNode.Service(&lightEthereum); err == nil {
+			go watchChainSync(lightEthereum.Downloader().Progress, chainSyncInterval, m.nodeStopped)
+		}
+
 		// underlying node is started, every method can use it, we use it immediately
 		go func() {
 			if err := m.PopulateStaticPeers(); err != nil {
@@ -156,6 +213,12 @@ func (m *NodeManager) StopNode() (<-chan struct{}, error) {
 
 // stopNode stop Status node. Stopped node cannot be resumed.
 func (m *NodeManager) stopNode() (<-chan struct{}, error) {
+	if m.rpcEndpoint != nil {
+		m.stopRPCEndpoint() // nolint: errcheck
+	}
+
+	m.peerPool.Stop()
+
 	// now attempt to stop
 	if err := m.node.Stop(); err != nil {
 		return nil, err
@@ -171,6 +234,9 @@ func (m *NodeManager) stopNode() (<-chan struct{}, error) {
 		m.config = nil
 		m.lesService = nil
 		m.whisperService = nil
+		if m.rpcClient != nil {
+			m.rpcClient.Close()
+		}
 		m.rpcClient = nil
 		m.nodeStarted = nil
 		m.node = nil
@@ -239,7 +305,17 @@ func (m *NodeManager) populateStaticPeers() error {
 		return nil
 	}
 
-	for _, enode := range m.config.BootClusterConfig.BootNodes {
+	bootNodes := m.config.BootClusterConfig.BootNodes
+	if m.config.BootClusterConfig.RegistryURL != "" {
+		fleetNodes, err := fleetBootNodes(m.config)
+		if err != nil {
+			log.Warn("Fleet registry boot nodes are unavailable, using embedded ones", "error", err)
+		} else {
+			bootNodes = fleetNodes
+		}
+	}
+
+	for _, enode := range bootNodes {
 		err := m.addPeer(enode)
 		if err != nil {
 			log.Warn("Boot node addition failed", "error", err)
@@ -278,16 +354,43 @@ func (m *NodeManager) addPeer(url string) error {
 		return err
 	}
 	server.AddPeer(parsedNode)
+	m.peerPool.Protect(parsedNode.ID)
 
 	return nil
 }
 
+// parseNodeIDs returns IDs of nodes with given enode URLs, skipping invalid ones.
+func parseNodeIDs(urls []string) []discover.NodeID {
+	var ids []discover.NodeID
+	for _, url := range urls {
+		node, err := discover.ParseNode(url)
+		if err != nil {
+			log.Warn("Invalid enode URL", "url", url, "error", err)
+			continue
+		}
+		ids = append(ids, node.ID)
+	}
+
+	return ids
+}
+
 // ResetChainData remove chain data from data directory.
 // Node is stopped, and new node is started, with clean data directory.
+// If node failed to start due to corrupted chain data (see signal.EventChainDataCorrupted),
+// chain data is removed, and node is started again.
 func (m *NodeManager) ResetChainData() (<-chan struct{}, error) {
 	m.Lock()
 	defer m.Unlock()
 
+	if m.nodeStarted == nil && m.corruptConfig != nil {
+		config := m.corruptConfig
+		if err := removeChainData(config); err != nil {
+			return nil, err
+		}
+		m.corruptConfig = nil
+		return m.startNode(config)
+	}
+
 	if err := m.isNodeAvailable(); err != nil {
 		return nil, err
 	}
@@ -310,21 +413,30 @@ func (m *NodeManager) resetChainData() (<-chan struct{}, error) {
 	<-nodeStopped
 	m.Lock()
 
-	chainDataDir := filepath.Join(prevConfig.DataDir, prevConfig.Name, "lightchaindata")
-	if _, err := os.Stat(chainDataDir); os.IsNotExist(err) {
+	if err := removeChainData(&prevConfig); err != nil {
 		return nil, err
 	}
-	if err := os.RemoveAll(chainDataDir); err != nil {
-		return nil, err
+
+	return m.startNode(&prevConfig)
+}
+
+// removeChainData removes chain data of a (stopped) node with a given config.
+func removeChainData(config *params.NodeConfig) error {
+	dir := chainDataDir(config)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
 	}
 	// send signal up to native app
 	signal.Send(signal.Envelope{
 		Type:  signal.EventChainDataRemoved,
 		Event: struct{}{},
 	})
-	log.Info("Chain data has been removed", "dir", chainDataDir)
+	log.Info("Chain data has been removed", "dir", dir)
 
-	return m.startNode(&prevConfig)
+	return nil
 }
 
 // RestartNode restart running Status node, fails if node is not running
@@ -344,6 +456,26 @@ func (m *NodeManager) RestartNode() (<-chan struct{}, error) {
 // restartNode restart running Status node, fails if node is not running
 func (m *NodeManager) restartNode() (<-chan struct{}, error) {
 	prevConfig := *m.config
+
+	return m.restartNodeWithConfig(&prevConfig)
+}
+
+// UpdateConfig restarts running Status node with a given configuration, fails if node is not running
+func (m *NodeManager) UpdateConfig(config *params.NodeConfig) (<-chan struct{}, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.isNodeAvailable(); err != nil {
+		return nil, err
+	}
+
+	<-m.nodeStarted
+
+	return m.restartNodeWithConfig(config)
+}
+
+// restartNodeWithConfig stops running Status node, and starts a new one with a given configuration
+func (m *NodeManager) restartNodeWithConfig(config *params.NodeConfig) (<-chan struct{}, error) {
 	nodeStopped, err := m.stopNode()
 	if err != nil {
 		return nil, err
@@ -353,7 +485,26 @@ func (m *NodeManager) restartNode() (<-chan struct{}, error) {
 	<-nodeStopped
 	m.Lock()
 
-	return m.startNode(&prevConfig)
+	return m.startNode(config)
+}
+
+// Peers returns info of connected peers, along with their latency and quality measurements
+func (m *NodeManager) Peers() ([]peers.Info, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if err := m.isNodeAvailable(); err != nil {
+		return nil, err
+	}
+
+	<-m.nodeStarted
+
+	server := m.node.Server()
+	if server == nil {
+		return nil, ErrP2PServerNotRunning
+	}
+
+	return m.peerMonitor.Peers(server), nil
 }
 
 // NodeConfig exposes reference to running node's configuration
@@ -489,6 +640,30 @@ func (m *NodeManager) initLog(config *params.NodeConfig) {
 	}
 }
 
+// ConfigChangedEvent is a signal sent, when node is started with config different from the previous one
+type ConfigChangedEvent struct {
+	Changes []params.ConfigChange `json:"changes"`
+}
+
+// reportConfigChanges logs config changes and notifies application about them.
+func reportConfigChanges(changes []params.ConfigChange) {
+	for _, change := range changes {
+		log.Info("Node config changed", "field", change.Field, "old", change.Old, "new", change.New)
+	}
+
+	signal.Send(signal.Envelope{
+		Type:  signal.EventNodeConfigChanged,
+		Event: ConfigChangedEvent{Changes: changes},
+	})
+}
+
+// recoverOnPanic recovers from panic and passes recovered value to the panic handler
+func (m *NodeManager) recoverOnPanic() {
+	if r := recover(); r != nil {
+		m.panicHandler(r)
+	}
+}
+
 // isNodeAvailable check if we have a node running and make sure is fully started
 func (m *NodeManager) isNodeAvailable() error {
 	if m.nodeStarted == nil || m.node == nil {
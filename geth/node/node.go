@@ -22,11 +22,12 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discv5"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	gethparams "github.com/ethereum/go-ethereum/params"
-	"github.com/ethereum/go-ethereum/whisper/mailserver"
 	"github.com/ethereum/go-ethereum/whisper/notifications"
 	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
 	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/mailserver"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/peers"
 )
 
 // node-related errors
@@ -39,8 +40,23 @@ var (
 	ErrNodeStartFailure                  = errors.New("error starting p2p node")
 )
 
+// lesResponses maps codes of LES request messages to codes of their responses
+var lesResponses = map[uint64]uint64{
+	les.GetBlockHeadersMsg: les.BlockHeadersMsg,
+	les.GetBlockBodiesMsg:  les.BlockBodiesMsg,
+	les.GetReceiptsMsg:     les.ReceiptsMsg,
+	les.GetProofsMsg:       les.ProofsMsg,
+	les.GetCodeMsg:         les.CodeMsg,
+	les.GetHeaderProofsMsg: les.HeaderProofsMsg,
+}
+
 // MakeNode create a geth node entity
 func MakeNode(config *params.NodeConfig) (*node.Node, error) {
+	return makeNode(config, nil)
+}
+
+// makeNode create a geth node entity, with peers of its sub-protocols measured by a given monitor (if any)
+func makeNode(config *params.NodeConfig, monitor *peers.Monitor) (*node.Node, error) {
 	// make sure data directory exists
 	if err := os.MkdirAll(filepath.Join(config.DataDir), os.ModePerm); err != nil {
 		return nil, err
@@ -72,7 +88,7 @@ func MakeNode(config *params.NodeConfig) (*node.Node, error) {
 
 	// Start Ethereum service if we are not expected to use an upstream server.
 	if !config.UpstreamConfig.Enabled {
-		if err := activateEthService(stack, config); err != nil {
+		if err := activateEthService(stack, config, monitor); err != nil {
 			return nil, fmt.Errorf("%v: %v", ErrEthServiceRegistrationFailure, err)
 		}
 	}
@@ -119,6 +135,12 @@ func defaultEmbeddedNodeConfig(config *params.NodeConfig) *node.Config {
 		nc.HTTPPort = config.HTTPPort
 	}
 
+	if config.KeyStoreConfig != nil {
+		nc.UseLightweightKDF = config.KeyStoreConfig.LightKDF
+		nc.KeyStoreScryptN, nc.KeyStoreScryptP = config.KeyStoreConfig.ScryptParams()
+		nc.KeyStoreArgon2 = config.KeyStoreConfig.Argon2Params()
+	}
+
 	return nc
 }
 
@@ -145,7 +167,7 @@ func updateCHT(eth *les.LightEthereum, config *params.NodeConfig) {
 }
 
 // activateEthService configures and registers the eth.Ethereum service with a given node.
-func activateEthService(stack *node.Node, config *params.NodeConfig) error {
+func activateEthService(stack *node.Node, config *params.NodeConfig, monitor *peers.Monitor) error {
 	if !config.LightEthConfig.Enabled {
 		log.Info("LES protocol is disabled")
 		return nil
@@ -170,6 +192,9 @@ func activateEthService(stack *node.Node, config *params.NodeConfig) error {
 		if err == nil {
 			updateCHT(lightEth, config)
 		}
+		if err == nil && monitor != nil {
+			monitorLES(lightEth, monitor)
+		}
 		return lightEth, err
 	}); err != nil {
 		return fmt.Errorf("%v: %v", ErrLightEthRegistrationFailure, err)
@@ -178,7 +203,18 @@ func activateEthService(stack *node.Node, config *params.NodeConfig) error {
 	return nil
 }
 
+// monitorLES makes LES requests measured by a given monitor.
+// Protocols are replaced in place, as LES service exposes its own slice of sub-protocols.
+func monitorLES(lightEth *les.LightEthereum, monitor *peers.Monitor) {
+	protocols := lightEth.Protocols()
+	for i := range protocols {
+		protocols[i] = monitor.WrapProtocol(protocols[i], lesResponses)
+	}
+}
+
 // activateShhService configures Whisper and adds it to the given node.
+// Only Whisper v5 is supported: v6 is not part of vendored go-ethereum (1.7.0),
+// and packages built on top of Whisper depend on v5 API.
 func activateShhService(stack *node.Node, config *params.NodeConfig) error {
 	if !config.WhisperConfig.Enabled {
 		log.Info("SHH protocol is disabled")
@@ -187,19 +223,12 @@ func activateShhService(stack *node.Node, config *params.NodeConfig) error {
 
 	serviceConstructor := func(*node.ServiceContext) (node.Service, error) {
 		whisperConfig := config.WhisperConfig
-		whisperService := whisper.New(nil)
-
-		// enable mail service
-		if whisperConfig.MailServerNode {
-			password, err := whisperConfig.ReadPasswordFile()
-			if err != nil {
-				return nil, err
-			}
-
-			var mailServer mailserver.WMailServer
-			whisperService.RegisterServer(&mailServer)
-			mailServer.Init(whisperService, whisperConfig.DataDir, string(password), whisperConfig.MinimumPoW)
+		shhConfig := whisper.DefaultConfig
+		if whisperConfig.LightClient {
+			// light client doesn't relay envelopes, so it accepts its own ones with lower PoW
+			shhConfig.LightClient = true
 		}
+		whisperService := whisper.New(&shhConfig)
 
 		// enable notification service
 		if whisperConfig.NotificationServerNode {
@@ -212,7 +241,22 @@ func activateShhService(stack *node.Node, config *params.NodeConfig) error {
 		return whisperService, nil
 	}
 
-	return stack.Register(serviceConstructor)
+	if err := stack.Register(serviceConstructor); err != nil {
+		return err
+	}
+
+	// enable mail service, it archives envelopes received by Whisper, and delivers them on request
+	if config.WhisperConfig.MailServerNode {
+		return stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+			var whisperService *whisper.Whisper
+			if err := ctx.Service(&whisperService); err != nil {
+				return nil, err
+			}
+			return mailserver.New(whisperService, config.WhisperConfig)
+		})
+	}
+
+	return nil
 }
 
 // makeIPCPath returns IPC-RPC filename
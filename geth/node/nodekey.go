@@ -0,0 +1,126 @@
+package node
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/status-im/status-go/geth/log"
+)
+
+// nodeKeyFileName is a name of node key file within node's instance directory (same as in geth)
+const nodeKeyFileName = "nodekey"
+
+// errors
+var (
+	ErrInvalidNodeKey      = errors.New("invalid node key")
+	ErrP2PServerNotRunning = errors.New("p2p server is not running")
+)
+
+// NodeID returns ID of the running node (public part of node's devp2p key).
+func (m *NodeManager) NodeID() (discover.NodeID, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	key, err := m.nodeKey()
+	if err != nil {
+		return discover.NodeID{}, err
+	}
+
+	return discover.PubkeyID(&key.PublicKey), nil
+}
+
+// ExportNodeKey returns hex-encoded private devp2p key of the running node.
+func (m *NodeManager) ExportNodeKey() (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	key, err := m.nodeKey()
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(crypto.FromECDSA(key)), nil
+}
+
+// ImportNodeKey replaces devp2p key of the running node with a given hex-encoded one.
+// Key is persisted, and node is restarted in order to start using a new identity.
+func (m *NodeManager) ImportNodeKey(hexKey string) (<-chan struct{}, error) {
+	key, err := crypto.HexToECDSA(trimHexPrefix(hexKey))
+	if err != nil {
+		return nil, ErrInvalidNodeKey
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	return m.importNodeKey(key)
+}
+
+// RotateNodeKey replaces devp2p key of the running node with a freshly generated one.
+// Key is persisted, and node is restarted in order to start using a new identity.
+func (m *NodeManager) RotateNodeKey() (<-chan struct{}, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	return m.importNodeKey(key)
+}
+
+// importNodeKey persists a given node key and restarts the node.
+func (m *NodeManager) importNodeKey(key *ecdsa.PrivateKey) (<-chan struct{}, error) {
+	if err := m.isNodeAvailable(); err != nil {
+		return nil, err
+	}
+
+	<-m.nodeStarted
+
+	keyFile := m.nodeKeyFile()
+	if err := crypto.SaveECDSA(keyFile, key); err != nil {
+		return nil, err
+	}
+	log.Info("Node key has been replaced", "file", keyFile, "id", discover.PubkeyID(&key.PublicKey).String())
+
+	return m.restartNode()
+}
+
+// nodeKey returns private key of the running p2p server.
+func (m *NodeManager) nodeKey() (*ecdsa.PrivateKey, error) {
+	if err := m.isNodeAvailable(); err != nil {
+		return nil, err
+	}
+
+	<-m.nodeStarted
+
+	server := m.node.Server()
+	if server == nil || server.PrivateKey == nil {
+		return nil, ErrP2PServerNotRunning
+	}
+
+	return server.PrivateKey, nil
+}
+
+// nodeKeyFile returns path of the file node key is loaded from:
+// either explicitly configured one, or the default file within node's instance directory.
+func (m *NodeManager) nodeKeyFile() string {
+	if m.config.NodeKeyFile != "" {
+		return m.config.NodeKeyFile
+	}
+
+	return filepath.Join(m.node.InstanceDir(), nodeKeyFileName)
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) > 1 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+
+	return s
+}
@@ -0,0 +1,27 @@
+package node
+
+// PanicHandler is a function that receives a value recovered from panic
+// inside of the node manager's background routines.
+type PanicHandler func(r interface{})
+
+// Option configures NodeManager on creation.
+type Option func(*NodeManager)
+
+// WithoutSignalHandler disables interrupt signal handling, so that
+// host process, which embeds status-go as a library, is in charge of
+// the process lifetime.
+func WithoutSignalHandler() Option {
+	return func(m *NodeManager) {
+		m.signalHandlerDisabled = true
+	}
+}
+
+// WithPanicHandler overrides default panic handler (which notifies
+// the application and exits the process) with a custom one.
+func WithPanicHandler(fn PanicHandler) Option {
+	return func(m *NodeManager) {
+		if fn != nil {
+			m.panicHandler = fn
+		}
+	}
+}
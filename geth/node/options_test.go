@@ -0,0 +1,24 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeManagerOptions(t *testing.T) {
+	m := NewNodeManager(WithoutSignalHandler())
+	require.True(t, m.signalHandlerDisabled)
+	require.NotNil(t, m.panicHandler)
+
+	var recovered interface{}
+	m = NewNodeManager(WithoutSignalHandler(), WithPanicHandler(func(r interface{}) {
+		recovered = r
+	}))
+
+	func() {
+		defer m.recoverOnPanic()
+		panic("test panic")
+	}()
+	require.Equal(t, "test panic", recovered)
+}
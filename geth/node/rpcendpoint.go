@@ -0,0 +1,361 @@
+package node
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/les"
+	"github.com/ethereum/go-ethereum/node"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// errors
+var (
+	ErrRPCEndpointExists   = errors.New("RPC endpoint is already opened")
+	ErrRPCEndpointNotFound = errors.New("RPC endpoint is not opened")
+	ErrRPCEndpointEmpty    = errors.New("none of HTTP, WS or IPC endpoints is configured")
+	ErrRPCEndpointNoToken  = errors.New("token of session is required for HTTP and WS endpoints")
+)
+
+// defaultVirtualHosts is a list of virtual hosts accepted, when none is provided
+var defaultVirtualHosts = []string{"localhost"}
+
+// authTokenSize is a size (in bytes) of random token of session, HTTP and WS requests are authenticated with
+const authTokenSize = 32
+
+// rpcEndpoint holds listeners and handlers of JSON-RPC servers opened at runtime.
+type rpcEndpoint struct {
+	listeners []net.Listener
+	handlers  []*gethrpc.Server
+	token     string // empty, if authentication is disabled
+}
+
+// close stops all listeners and handlers of endpoint.
+func (e *rpcEndpoint) close() {
+	for _, listener := range e.listeners {
+		if err := listener.Close(); err != nil {
+			log.Warn("Failed to close RPC endpoint listener", "addr", listener.Addr(), "error", err)
+		}
+	}
+	for _, handler := range e.handlers {
+		handler.Stop()
+	}
+}
+
+// StartRPCEndpoint opens HTTP, WS and/or IPC JSON-RPC servers of a running node.
+func (m *NodeManager) StartRPCEndpoint(config params.RPCEndpointConfig) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.isNodeAvailable(); err != nil {
+		return err
+	}
+
+	<-m.nodeStarted
+
+	return m.startRPCEndpoint(config)
+}
+
+// startRPCEndpoint opens HTTP, WS and/or IPC JSON-RPC servers of a running node.
+func (m *NodeManager) startRPCEndpoint(config params.RPCEndpointConfig) error {
+	if m.rpcEndpoint != nil {
+		return ErrRPCEndpointExists
+	}
+
+	if config.HTTPHost == "" && config.WSHost == "" && config.IPCFile == "" {
+		return ErrRPCEndpointEmpty
+	}
+
+	vhosts := config.VirtualHosts
+	if len(vhosts) == 0 {
+		vhosts = defaultVirtualHosts
+	}
+
+	endpoint := &rpcEndpoint{}
+	apis := m.rpcAPIs()
+
+	if !config.AuthDisabled {
+		token, err := newAuthToken()
+		if err != nil {
+			return err
+		}
+		// empty token would disable authentication of handlers
+		if token == "" {
+			return ErrRPCEndpointNoToken
+		}
+		endpoint.token = token
+	}
+
+	if config.HTTPHost != "" {
+		handler, err := newRPCHandler(apis, config.Modules)
+		if err != nil {
+			endpoint.close()
+			return err
+		}
+		server := gethrpc.NewHTTPServer(config.CORS, handler)
+		server.Handler = newVirtualHostHandler(vhosts, newAuthHandler(endpoint.token, server.Handler))
+
+		addr := fmt.Sprintf("%s:%d", config.HTTPHost, config.HTTPPort)
+		if err := endpoint.serve(server, handler, addr); err != nil {
+			return err
+		}
+		log.Info("HTTP RPC endpoint opened", "url", "http://"+addr)
+	}
+
+	if config.WSHost != "" {
+		handler, err := newRPCHandler(apis, config.Modules)
+		if err != nil {
+			endpoint.close()
+			return err
+		}
+		server := gethrpc.NewWSServer(config.CORS, handler)
+		server.Handler = newVirtualHostHandler(vhosts, newAuthHandler(endpoint.token, server.Handler))
+
+		addr := fmt.Sprintf("%s:%d", config.WSHost, config.WSPort)
+		if err := endpoint.serve(server, handler, addr); err != nil {
+			return err
+		}
+		log.Info("WebSocket RPC endpoint opened", "url", "ws://"+addr)
+	}
+
+	if config.IPCFile != "" {
+		handler, err := newRPCHandler(apis, config.Modules)
+		if err != nil {
+			endpoint.close()
+			return err
+		}
+
+		endpoint.handlers = append(endpoint.handlers, handler)
+
+		path := filepath.Join(m.config.DataDir, config.IPCFile)
+		listener, err := gethrpc.CreateIPCListener(path)
+		if err != nil {
+			endpoint.close()
+			return err
+		}
+		endpoint.listeners = append(endpoint.listeners, listener)
+
+		go handler.ServeListener(listener) // nolint: errcheck
+		log.Info("IPC RPC endpoint opened", "path", path)
+	}
+
+	m.rpcEndpoint = endpoint
+
+	return nil
+}
+
+// RPCEndpointToken returns token of session, HTTP and WS requests to endpoint opened with StartRPCEndpoint
+// must carry (empty, if params.RPCEndpointConfig.AuthDisabled is set). Token is re-generated, whenever
+// endpoint is opened, and is to be handed to UI only.
+func (m *NodeManager) RPCEndpointToken() (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.rpcEndpoint == nil {
+		return "", ErrRPCEndpointNotFound
+	}
+
+	return m.rpcEndpoint.token, nil
+}
+
+// StopRPCEndpoint closes JSON-RPC servers opened with StartRPCEndpoint.
+func (m *NodeManager) StopRPCEndpoint() error {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.stopRPCEndpoint()
+}
+
+// stopRPCEndpoint closes JSON-RPC servers opened with StartRPCEndpoint.
+func (m *NodeManager) stopRPCEndpoint() error {
+	if m.rpcEndpoint == nil {
+		return ErrRPCEndpointNotFound
+	}
+
+	m.rpcEndpoint.close()
+	m.rpcEndpoint = nil
+	log.Info("RPC endpoint closed")
+
+	return nil
+}
+
+// newAuthToken generates random token of session.
+func newAuthToken() (string, error) {
+	token := make([]byte, authTokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// serve starts listening on a given address and serves requests with HTTP server.
+func (e *rpcEndpoint) serve(server *http.Server, handler *gethrpc.Server, addr string) error {
+	e.handlers = append(e.handlers, handler)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		e.close()
+		return err
+	}
+	e.listeners = append(e.listeners, listener)
+
+	go server.Serve(listener) // nolint: errcheck
+
+	return nil
+}
+
+// rpcAPIs returns APIs offered by the running node and its services.
+func (m *NodeManager) rpcAPIs() []gethrpc.API {
+	apis := []gethrpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   node.NewPrivateAdminAPI(m.node),
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   node.NewPublicAdminAPI(m.node),
+			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   node.NewPublicDebugAPI(m.node),
+			Public:    true,
+		}, {
+			Namespace: "web3",
+			Version:   "1.0",
+			Service:   node.NewPublicWeb3API(m.node),
+			Public:    true,
+		},
+	}
+
+	var lesService *les.LightEthereum
+	if err := m.node.Service(&lesService); err == nil {
+		apis = append(apis, lesService.APIs()...)
+	}
+
+	var whisperService *whisper.Whisper
+	if err := m.node.Service(&whisperService); err == nil {
+		apis = append(apis, whisperService.APIs()...)
+	}
+
+	return apis
+}
+
+// newRPCHandler creates JSON-RPC server with APIs from whitelisted modules
+// (or all public APIs, if whitelist is empty).
+func newRPCHandler(apis []gethrpc.API, modules []string) (*gethrpc.Server, error) {
+	whitelist := make(map[string]bool)
+	for _, module := range modules {
+		whitelist[module] = true
+	}
+
+	handler := gethrpc.NewServer()
+	for _, api := range apis {
+		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
+			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return handler, nil
+}
+
+// virtualHostHandler validates Host header of incoming requests,
+// in order to prevent DNS rebinding attacks.
+type virtualHostHandler struct {
+	vhosts map[string]struct{}
+	next   http.Handler
+}
+
+func newVirtualHostHandler(vhosts []string, next http.Handler) http.Handler {
+	vhostMap := make(map[string]struct{})
+	for _, vhost := range vhosts {
+		vhostMap[strings.ToLower(vhost)] = struct{}{}
+	}
+
+	return &virtualHostHandler{vhosts: vhostMap, next: next}
+}
+
+// ServeHTTP serves JSON-RPC requests over HTTP, implements http.Handler
+func (h *virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// if r.Host is not set, we can continue serving since a browser would set the Host header
+	if r.Host == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		// either invalid (too many colons) or no port specified
+		host = r.Host
+	}
+
+	// it's an IP address, we can serve that
+	if ip := net.ParseIP(host); ip != nil {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	if _, ok := h.vhosts["*"]; ok {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	if _, ok := h.vhosts[strings.ToLower(host)]; ok {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	http.Error(w, "invalid host specified", http.StatusForbidden)
+}
+
+// authHandler rejects requests, which don't carry token of session, either as "Authorization: Bearer <token>"
+// header, or as "token" query parameter (browsers can't set headers of WebSocket handshake), so that other
+// local processes could not drive the node.
+type authHandler struct {
+	token []byte
+	next  http.Handler
+}
+
+// newAuthHandler returns handler, which authenticates requests with a given token (or next, if token is empty).
+func newAuthHandler(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	return &authHandler{token: []byte(token), next: next}
+}
+
+// ServeHTTP serves JSON-RPC requests over HTTP, implements http.Handler
+func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// CORS preflight requests can't carry credentials
+	if r.Method == http.MethodOptions {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), h.token) != 1 {
+		log.Warn("Rejected unauthenticated RPC request", "remoteAddr", r.RemoteAddr)
+		http.Error(w, "invalid or missing auth token", http.StatusUnauthorized)
+		return
+	}
+
+	h.next.ServeHTTP(w, r)
+}
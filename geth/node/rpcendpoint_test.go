@@ -0,0 +1,112 @@
+package node
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualHostHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		vhosts []string
+		host   string
+		status int
+	}{
+		{[]string{"localhost"}, "localhost:8545", http.StatusOK},
+		{[]string{"localhost"}, "LOCALHOST", http.StatusOK},
+		{[]string{"localhost"}, "127.0.0.1:8545", http.StatusOK},
+		{[]string{"localhost"}, "evil.com", http.StatusForbidden},
+		{[]string{"*"}, "evil.com:8545", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		handler := newVirtualHostHandler(tc.vhosts, next)
+
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Host = tc.host
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, tc.status, rec.Code, "host %s, vhosts %v", tc.host, tc.vhosts)
+	}
+}
+
+func TestAuthHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := newAuthHandler("secret", next)
+
+	testCases := []struct {
+		method string
+		target string
+		header string
+		status int
+	}{
+		{"POST", "/", "Bearer secret", http.StatusOK},
+		{"GET", "/?token=secret", "", http.StatusOK},
+		{"OPTIONS", "/", "", http.StatusOK},
+		{"POST", "/", "", http.StatusUnauthorized},
+		{"POST", "/", "Bearer other", http.StatusUnauthorized},
+		{"GET", "/?token=other", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(tc.method, tc.target, nil)
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, tc.status, rec.Code, "%s %s, authorization %q", tc.method, tc.target, tc.header)
+	}
+
+	// authentication is disabled with empty token
+	_, ok := newAuthHandler("", next).(*authHandler)
+	require.False(t, ok)
+}
+
+func TestRPCEndpointAuthRequiredByDefault(t *testing.T) {
+	stack, err := node.New(&node.Config{})
+	require.NoError(t, err)
+	m := &NodeManager{node: stack, config: &params.NodeConfig{}}
+
+	require.NoError(t, m.startRPCEndpoint(params.RPCEndpointConfig{HTTPHost: "127.0.0.1"}))
+	defer m.stopRPCEndpoint() // nolint: errcheck
+
+	token, err := m.RPCEndpointToken()
+	require.NoError(t, err)
+	require.Len(t, token, 2*authTokenSize)
+
+	url := "http://" + m.rpcEndpoint.listeners[0].Addr().String()
+	body := `{"jsonrpc":"2.0","id":1,"method":"web3_clientVersion","params":[]}`
+	for _, tc := range []struct {
+		authorization string
+		status        int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer " + token, http.StatusOK},
+	} {
+		req, err := http.NewRequest("POST", url, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Host = "localhost"
+		req.Header.Set("Content-Type", "application/json")
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close() // nolint: errcheck
+		require.Equal(t, tc.status, resp.StatusCode)
+	}
+}
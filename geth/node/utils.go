@@ -13,18 +13,23 @@ import (
 // HaltOnPanic recovers from panic, logs issue, sends upward notification, and exits
 func HaltOnPanic() {
 	if r := recover(); r != nil {
-		err := fmt.Errorf("%v: %v", ErrNodeRunFailure, r)
+		haltOnPanic(r)
+	}
+}
 
-		// send signal up to native app
-		signal.Send(signal.Envelope{
-			Type: signal.EventNodeCrashed,
-			Event: signal.NodeCrashEvent{
-				Error: err.Error(),
-			},
-		})
+// haltOnPanic is a default panic handler: it sends upward notification, and exits
+func haltOnPanic(r interface{}) {
+	err := fmt.Errorf("%v: %v", ErrNodeRunFailure, r)
 
-		common.Fatalf(err) // os.exit(1) is called internally
-	}
+	// send signal up to native app
+	signal.Send(signal.Envelope{
+		Type: signal.EventNodeCrashed,
+		Event: signal.NodeCrashEvent{
+			Error: err.Error(),
+		},
+	})
+
+	common.Fatalf(err) // os.exit(1) is called internally
 }
 
 // HaltOnInterruptSignal stops node and panics if you press Ctrl-C enough times
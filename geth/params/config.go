@@ -3,14 +3,18 @@ package params
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/status-im/status-go/geth/log"
@@ -39,6 +43,11 @@ var (
 	ErrEmptyIdentityFile          = errors.New("identity file cannot be empty")
 	ErrEmptyAuthorizationKeyFile  = errors.New("authorization key file cannot be empty")
 	ErrAuthorizationKeyFileNotSet = errors.New("authorization key file is not set")
+	ErrInvalidUpstreamHeader      = errors.New("invalid upstream header, \"Name: value\" expected")
+	ErrInvalidUpstreamBasicAuth   = errors.New("invalid upstream basic auth, \"user:password\" expected")
+	ErrInvalidScryptParams        = errors.New("invalid scrypt parameters, N must be a power of 2 greater than 1, and P must be positive")
+	ErrInvalidArgon2Params        = errors.New("invalid Argon2id parameters, time, memory and threads must be positive")
+	ErrInvalidRelayHubAddress     = errors.New("invalid address of relay hub")
 )
 
 // LightEthConfig holds LES-related configuration
@@ -56,6 +65,9 @@ type LightEthConfig struct {
 
 // FirebaseConfig holds FCM-related configuration
 type FirebaseConfig struct {
+	// AuthorizationKey is FCM authorization (server) key, it takes precedence over AuthorizationKeyFile
+	AuthorizationKey string
+
 	// AuthorizationKeyFile file path that contains FCM authorization key
 	AuthorizationKeyFile string
 
@@ -63,8 +75,12 @@ type FirebaseConfig struct {
 	NotificationTriggerURL string
 }
 
-// ReadAuthorizationKeyFile reads and loads FCM authorization key
+// ReadAuthorizationKeyFile reads and loads FCM authorization key, unless it is set in config
 func (c *FirebaseConfig) ReadAuthorizationKeyFile() ([]byte, error) {
+	if len(c.AuthorizationKey) > 0 {
+		return []byte(c.AuthorizationKey), nil
+	}
+
 	if len(c.AuthorizationKeyFile) == 0 {
 		return nil, ErrAuthorizationKeyFileNotSet
 	}
@@ -104,9 +120,32 @@ type WhisperConfig struct {
 	// ForwarderNode is mode when node only forwards messages, neither sends nor decrypts messages
 	ForwarderNode bool
 
+	// LightClient is mode when node only sends and receives its own messages, not relaying messages of others.
+	// It saves bandwidth and battery of mobile devices
+	LightClient bool
+
+	// LightClientPoW is PoW of messages sent by light client (MinimumPoW, if zero).
+	// It is lower than MinimumPoW, if peers accept it
+	LightClientPoW float64
+
 	// MailServerNode is mode when node is capable of delivering expired messages on demand
 	MailServerNode bool
 
+	// MailServerRetention is time (in seconds), envelopes are archived by mail server for (forever, if zero)
+	MailServerRetention int `validate:"min=0"`
+
+	// MailServerMaxEnvelopes is a maximum number of envelopes archived by mail server (unlimited, if zero).
+	// The oldest ones are removed beyond it
+	MailServerMaxEnvelopes int `validate:"min=0"`
+
+	// MailServerNodes lists enode URLs of known mail servers, connections with which are preferred.
+	// The first one is requested for historic messages, unless mail server is given explicitly
+	MailServerNodes []string
+
+	// MailServerPassword is a password of mail servers, symmetric key of requests of historic messages
+	// is derived from
+	MailServerPassword string
+
 	// NotificationServerNode is mode when node is capable of sending Push (and probably other kinds) Notifications
 	NotificationServerNode bool
 
@@ -122,10 +161,24 @@ type WhisperConfig struct {
 	// TTL time to live for messages, in seconds
 	TTL int
 
+	// OutboxMaxRetries is a maximum number of attempts to send message of outbox (unlimited, if zero)
+	OutboxMaxRetries int `validate:"min=0"`
+
+	// OutboxTTL is time (in seconds), message is kept in outbox for, until it's sent (forever, if zero)
+	OutboxTTL int `validate:"min=0"`
+
 	// FirebaseConfig extra configuration for Firebase Cloud Messaging
 	FirebaseConfig *FirebaseConfig `json:"FirebaseConfig,"`
 }
 
+// MessagePoW returns PoW of outgoing messages, it's lower for light client, if LightClientPoW is set.
+func (c *WhisperConfig) MessagePoW() float64 {
+	if c.LightClient && c.LightClientPoW > 0 && c.LightClientPoW < c.MinimumPoW {
+		return c.LightClientPoW
+	}
+	return c.MinimumPoW
+}
+
 // ReadPasswordFile reads and returns content of the password file
 func (c *WhisperConfig) ReadPasswordFile() ([]byte, error) {
 	if len(c.PasswordFile) == 0 {
@@ -181,6 +234,293 @@ func (c *SwarmConfig) String() string {
 	return string(data)
 }
 
+// JailConfig holds configuration of jail, where scripts of chat bots and dapps are run
+type JailConfig struct {
+	// Engine is JavaScript engine cells are run with, "otto" (ES5, default) or "goja" (ES6 and later)
+	Engine string
+
+	// RateLimit is a number of RPC requests per second, each jail cell may send (0 disables rate limiting)
+	RateLimit float64
+
+	// RateBurst is a number of RPC requests, cell may send at once, before rate limit applies (default is 1)
+	RateBurst int
+
+	// MaxCells is a number of live jail cells, above which least recently used cells are evicted
+	// (and restored from snapshots on next access); 0 means no limit
+	MaxCells int
+
+	// MaxHeapSize is a size of heap (in megabytes), above which the cell, that has handled the most calls,
+	// is recycled (re-created from its snapshot), releasing memory accumulated by its VM; 0 disables recycling
+	MaxHeapSize int
+
+	// ExecTimeout is a duration (in milliseconds), execution of JavaScript code of cell (parsing of bundle,
+	// calls and callbacks) is interrupted after, so that infinite loops don't hang; 0 means no limit
+	ExecTimeout int
+
+	// CellCPUBudget is a total duration (in milliseconds) of execution of JavaScript code of cell,
+	// above which cell is terminated; 0 means no limit
+	CellCPUBudget int
+
+	// CellHeapBudget is a growth of heap (in megabytes) during execution of JavaScript code of cell,
+	// above which cell is terminated; 0 means no limit. Heap is shared by cells, so growth is approximate
+	CellHeapBudget int
+
+	// StorageQuota is a size (in bytes) of keys and values, each cell may keep in its localStorage
+	// (persisted in data dir); 0 means default quota of 5 MB
+	StorageQuota int
+
+	// FetchWhitelist lists origins, cells may send requests to with fetch(), like "https://api.example.com"
+	// or "https://*.example.com" (any subdomain); any origin is allowed, if empty
+	FetchWhitelist []string
+
+	// FetchMaxResponseSize is a size (in bytes) of response body, above which fetch() fails;
+	// 0 means default limit of 1 MB
+	FetchMaxResponseSize int
+
+	// FetchTimeout is a timeout (in milliseconds) of requests cells send with fetch(); 0 means 30 seconds
+	FetchTimeout int
+
+	// EIP1193Provider exposes EIP-1193 provider as ethereum object in cells, so that web3 1.x
+	// and newer libraries can be used along with the legacy web3
+	EIP1193Provider bool
+
+	// LogBufferSize is a number of the last entries, each cell has logged with console, kept for
+	// inspection; 0 means 100 entries
+	LogBufferSize int
+
+	// StreamLogs sends every entry cells log with console as a signal
+	StreamLogs bool
+
+	// OrderedCalls executes calls of each cell in order they have been made, tagging responses
+	// with sequence numbers, so that clients can rely on order of responses to chains of commands
+	OrderedCalls bool
+}
+
+// String dumps config object as nicely indented JSON
+func (c *JailConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// SigningConfig holds policy of signing transactions, meant for headless deployments
+// (e.g. bots and bridges), which need limited autonomous signing
+type SigningConfig struct {
+	// AllowedAccounts lists addresses of accounts permitted to sign transactions (any account, if empty)
+	AllowedAccounts []string
+
+	// AutoApprove flag specifies whether queued transactions are approved or rejected without user
+	// interaction: transactions of value up to AutoApproveMaxValue are completed, others are rejected
+	AutoApprove bool
+
+	// AutoApproveMaxValue is maximum value (decimal, in wei) of automatically approved transaction
+	AutoApproveMaxValue string
+
+	// PasswordFile is path to the file with password of selected account, approved transactions are signed with.
+	// It is required, if AutoApprove is set
+	PasswordFile string
+
+	// TypedTransactions flag specifies whether typed transaction envelopes (EIP-2718) may be signed,
+	// provided that signer of account supports them. Otherwise, only legacy transactions are signed
+	TypedTransactions bool
+}
+
+// String dumps config object as nicely indented JSON
+func (c *SigningConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// ExplorerConfig holds block explorer of the network, links to transactions and addresses are resolved with
+type ExplorerConfig struct {
+	// Kind is a kind of explorer ("etherscan" or "blockscout"), it defines paths of pages of transactions and addresses
+	Kind string
+
+	// URL is a base URL of explorer, if empty, explorer registered for the network is used (Etherscan on public networks)
+	URL string
+}
+
+// String dumps config object as nicely indented JSON
+func (c *ExplorerConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// FeeConfig holds sources of gas price, fees of transactions are estimated with
+type FeeConfig struct {
+	// GasPriceOracleURL is URL of gas price oracle, which is asked for gas price before the node
+	// (if empty, gas price is suggested by the node). Oracle responds with JSON object, gasPrice field
+	// of which is a price in wei (hex-encoded or decimal)
+	GasPriceOracleURL string
+}
+
+// String dumps config object as nicely indented JSON
+func (c *FeeConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// RelayConfig holds parameters of gas relay (Gas Station Network style), which sends transactions
+// on behalf of accounts, so that accounts without ether can call contracts accepting relayed calls
+type RelayConfig struct {
+	// Enabled flag specifies whether transactions are signed as relayed calls, and submitted to relay,
+	// instead of being sent as raw transactions
+	Enabled bool
+
+	// URL is an endpoint of relay, signed relayed calls are posted to
+	URL string `validate:"url"`
+
+	// HubAddress is an address of relay hub contract, which nonces of senders are kept by,
+	// and which relayed calls are signed for
+	HubAddress string
+
+	// Fee is a fee of relay, percents of gas cost of relayed call
+	Fee uint64
+}
+
+// Validate checks that relay endpoint and hub address are set.
+func (c *RelayConfig) Validate() error {
+	if err := NewValidator().Struct(c); err != nil {
+		return err
+	}
+	if !gethcommon.IsHexAddress(c.HubAddress) {
+		return ErrInvalidRelayHubAddress
+	}
+
+	return nil
+}
+
+// String dumps config object as nicely indented JSON
+func (c *RelayConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// TxQueueConfig holds limits of queue of transactions, which wait to be completed or discarded by user
+type TxQueueConfig struct {
+	// Size is a maximum number of queued transactions
+	Size int `validate:"min=1"`
+
+	// TTL is a time (in seconds), within which queued transaction must be completed, otherwise it expires
+	TTL int `validate:"min=1"`
+
+	// Eviction is a policy applied to new transactions, once queue is full: either the oldest transaction
+	// is evicted to make room for a new one ("evict-oldest"), or a new one is rejected ("reject-new")
+	Eviction string `validate:"eq=evict-oldest|eq=reject-new"`
+}
+
+// String dumps config object as nicely indented JSON
+func (c *TxQueueConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// TxMonitorConfig holds parameters of monitoring of sent transactions, until they are mined and confirmed
+type TxMonitorConfig struct {
+	// Enabled flag specifies whether sent transactions are monitored, so that signals are sent once they
+	// are confirmed, or fail
+	Enabled bool
+
+	// Confirmations is a number of blocks (including the one transaction is mined in), transaction is
+	// considered confirmed after
+	Confirmations uint64 `validate:"min=1"`
+
+	// Timeout is a time (in seconds), within which transaction must be mined, otherwise it's considered failed
+	Timeout int `validate:"min=1"`
+}
+
+// String dumps config object as nicely indented JSON
+func (c *TxMonitorConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// TxHistoryConfig holds parameters of history of transactions of accounts, which is kept by the node
+type TxHistoryConfig struct {
+	// Enabled flag specifies whether history of transactions (sent ones, and ones found in chain) is kept
+	Enabled bool
+}
+
+// String dumps config object as nicely indented JSON
+func (c *TxHistoryConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
+// KeyStoreConfig holds parameters of key derivation function (KDF), which protects account keys with password.
+// Keys are decrypted with parameters they have been encrypted with, so changes apply to new keys only.
+type KeyStoreConfig struct {
+	// KDF is key derivation function of new keys, "scrypt" or "argon2id"
+	KDF string `validate:"eq=scrypt|eq=argon2id"`
+
+	// LightKDF selects light scrypt parameters (4MB of memory), for low-end devices. Ignored if ScryptN and ScryptP are set
+	LightKDF bool
+
+	// ScryptN is CPU/memory cost parameter of scrypt, power of 2 (standard parameters are used, if zero)
+	ScryptN int
+
+	// ScryptP is parallelization parameter of scrypt (standard parameters are used, if zero)
+	ScryptP int
+
+	// Argon2Time is the number of passes of Argon2id over the memory
+	Argon2Time uint32
+
+	// Argon2Memory is the memory (in KiB) used by Argon2id
+	Argon2Memory uint32
+
+	// Argon2Threads is the number of lanes of Argon2id
+	Argon2Threads uint8
+}
+
+// Validate checks that parameters of selected KDF are consistent.
+func (c *KeyStoreConfig) Validate() error {
+	if err := NewValidator().Struct(c); err != nil {
+		return err
+	}
+
+	if c.ScryptN != 0 || c.ScryptP != 0 {
+		if c.ScryptN <= 1 || c.ScryptN&(c.ScryptN-1) != 0 || c.ScryptP <= 0 {
+			return ErrInvalidScryptParams
+		}
+	}
+
+	if c.KDF == KeyStoreKDFArgon2id && (c.Argon2Time == 0 || c.Argon2Memory == 0 || c.Argon2Threads == 0) {
+		return ErrInvalidArgon2Params
+	}
+
+	return nil
+}
+
+// ScryptParams returns scrypt parameters of new keys: custom, light or standard ones.
+func (c *KeyStoreConfig) ScryptParams() (n, p int) {
+	switch {
+	case c.ScryptN > 0 && c.ScryptP > 0:
+		return c.ScryptN, c.ScryptP
+	case c.LightKDF:
+		return keystore.LightScryptN, keystore.LightScryptP
+	default:
+		return keystore.StandardScryptN, keystore.StandardScryptP
+	}
+}
+
+// Argon2Params returns Argon2id parameters of new keys, or nil if keys are encrypted with scrypt.
+func (c *KeyStoreConfig) Argon2Params() *keystore.Argon2Params {
+	if c.KDF != KeyStoreKDFArgon2id {
+		return nil
+	}
+
+	return &keystore.Argon2Params{
+		Time:    c.Argon2Time,
+		Memory:  c.Argon2Memory,
+		Threads: c.Argon2Threads,
+	}
+}
+
+// String dumps config object as nicely indented JSON
+func (c *KeyStoreConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ")
+	return string(data)
+}
+
 // BootClusterConfig holds configuration for supporting boot cluster, which is a temporary
 // means for mobile devices to get connected to Ethereum network (UDP-based discovery
 // may not be available, so we need means to discover the network manually).
@@ -197,6 +537,13 @@ type BootClusterConfig struct {
 	// BootNodes list of bootstrap nodes for a given network (Ropsten, Rinkeby, Homestead),
 	// for a given mode (production vs development)
 	BootNodes []string
+
+	// RegistryURL is URL of signed fleet registry, boot nodes are fetched from.
+	// BootNodes are used, if it is empty or registry (and its cached copy) is unavailable.
+	RegistryURL string
+
+	// RegistryKey is hex-encoded public key fleet registry is expected to be signed with
+	RegistryKey string
 }
 
 // String dumps config object as nicely indented JSON
@@ -215,6 +562,130 @@ type UpstreamRPCConfig struct {
 	// URL sets the rpc upstream host address for communication with
 	// a non-local infura endpoint.
 	URL string
+
+	// WebSocketURL is an upstream WebSocket endpoint, used for eth_subscribe subscriptions
+	WebSocketURL string
+
+	// FallbackURLs lists upstream providers to switch to, when the one at URL is unavailable
+	FallbackURLs []string
+
+	// Headers lists HTTP headers "Name: value" (e.g. "Authorization: Bearer <token>"), sent with every
+	// request to upstream providers
+	Headers []string
+
+	// BasicAuth is "user:password" pair, sent with every request to upstream providers
+	BasicAuth string
+
+	// Strategy defines how provider is selected among healthy ones: "priority" (default) or "round-robin"
+	Strategy string
+
+	// HealthCheckInterval is interval (in seconds) between health checks of providers (default is 30)
+	HealthCheckInterval int
+
+	// CallTimeout is a timeout (in seconds) of a single attempt of RPC call (default is 60)
+	CallTimeout int
+
+	// Retries is a number of times an upstream call is retried (with jittered backoff) on connectivity errors
+	Retries int
+
+	// BreakerThreshold is a number of consecutive failed upstream calls, after which circuit breaker opens,
+	// failing further calls immediately (0 disables breaker)
+	BreakerThreshold int
+
+	// BreakerCooldown is interval (in seconds), after which open circuit breaker lets a probe call through (default is 30)
+	BreakerCooldown int
+
+	// MethodRouting lists rules "method=local" or "method=upstream" (method may end with "*" to match a prefix),
+	// overriding the default routing of RPC calls; the first matching rule wins
+	MethodRouting []string
+
+	// CacheEnabled flag specifies whether results of idempotent calls are cached in memory
+	CacheEnabled bool
+
+	// OfflineStateEnabled flag specifies whether the last known balances, nonces, gas price and block number
+	// are kept, in order to serve them (labeled as stale), when upstream is unreachable
+	OfflineStateEnabled bool
+
+	// OfflineQueueEnabled flag specifies whether calls fail fast, while upstream is unreachable, except of signed
+	// raw transactions and Whisper posts, which are queued (and persisted) and replayed, once connectivity is restored
+	OfflineQueueEnabled bool
+
+	// CacheTTLs lists rules "method=seconds", overriding how long results of method are cached (0 disables caching)
+	CacheTTLs []string
+}
+
+// URLs returns URLs of all upstream providers, in order of priority
+func (c UpstreamRPCConfig) URLs() []string {
+	urls := []string{c.URL}
+	for _, url := range c.FallbackURLs {
+		if url != "" && url != c.URL {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+// Header returns HTTP header, sent with requests to upstream providers
+func (c UpstreamRPCConfig) Header() (http.Header, error) {
+	header := make(http.Header)
+	for _, rule := range c.Headers {
+		parts := strings.SplitN(rule, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, ErrInvalidUpstreamHeader
+		}
+		header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	if c.BasicAuth != "" {
+		if !strings.Contains(c.BasicAuth, ":") {
+			return nil, ErrInvalidUpstreamBasicAuth
+		}
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.BasicAuth)))
+	}
+
+	return header, nil
+}
+
+//=====================================================================================
+
+// RPCEndpointConfig stores configuration of JSON-RPC endpoints opened at runtime
+// (see NodeManager.StartRPCEndpoint), e.g. for local dApp development.
+type RPCEndpointConfig struct {
+	// HTTPHost is the host interface on which to start the HTTP RPC server.
+	// Pass empty string if no HTTP RPC interface needs to be started.
+	HTTPHost string
+
+	// HTTPPort is the TCP port number on which to start the HTTP RPC server.
+	HTTPPort int
+
+	// WSHost is the host interface on which to start the WebSocket RPC server.
+	// Pass empty string if no WebSocket RPC interface needs to be started.
+	WSHost string
+
+	// WSPort is the TCP port number on which to start the WebSocket RPC server.
+	WSPort int
+
+	// IPCFile is filename (relative to DataDir) of IPC RPC server.
+	// Pass empty string if no IPC RPC interface needs to be started.
+	IPCFile string
+
+	// CORS is a list of domains from which to accept cross origin requests
+	// (for WebSocket server it is a list of accepted origins).
+	CORS []string
+
+	// VirtualHosts is a list of host names from which to accept HTTP and WebSocket requests.
+	// Requests addressed by IP are always accepted. Defaults to "localhost", use "*" to accept any host.
+	VirtualHosts []string
+
+	// Modules is a list of API modules exposed via opened endpoints.
+	// If list is empty, only public APIs are exposed.
+	Modules []string
+
+	// AuthDisabled flag specifies whether HTTP and WebSocket requests are accepted without token of session.
+	// By default, they must carry token generated by the backend (see NodeManager.RPCEndpointToken).
+	// IPC endpoint is protected by permissions of its file only.
+	AuthDisabled bool
 }
 
 //=====================================================================================
@@ -296,6 +767,10 @@ type NodeConfig struct {
 	// LogToStderr defines whether logged info should also be output to os.Stderr
 	LogToStderr bool
 
+	// SecretFields lists paths of string fields (such as "UpstreamConfig.URL"), which are encrypted at rest
+	// with a key of the device, kept in DataDir (see SaveEncrypted, LoadNodeConfig); DefaultSecretFields, if empty
+	SecretFields []string
+
 	// UpstreamConfig extra config for providing upstream infura server.
 	UpstreamConfig UpstreamRPCConfig `json:"UpstreamConfig"`
 
@@ -310,6 +785,33 @@ type NodeConfig struct {
 
 	// SwarmConfig extra configuration for Swarm and ENS
 	SwarmConfig *SwarmConfig `json:"SwarmConfig," validate:"structonly"`
+
+	// JailConfig extra configuration for jail
+	JailConfig *JailConfig `json:"JailConfig," validate:"structonly"`
+
+	// SigningConfig extra configuration for signing policy
+	SigningConfig *SigningConfig `json:"SigningConfig," validate:"structonly"`
+
+	// ExplorerConfig extra configuration for block explorer links
+	ExplorerConfig *ExplorerConfig `json:"ExplorerConfig," validate:"structonly"`
+
+	// KeyStoreConfig extra configuration for key derivation of account keys
+	KeyStoreConfig *KeyStoreConfig `json:"KeyStoreConfig," validate:"structonly"`
+
+	// FeeConfig extra configuration for estimation of transaction fees
+	FeeConfig *FeeConfig `json:"FeeConfig," validate:"structonly"`
+
+	// RelayConfig extra configuration for gas relay of transactions
+	RelayConfig *RelayConfig `json:"RelayConfig," validate:"structonly"`
+
+	// TxQueueConfig extra configuration for queue of transactions
+	TxQueueConfig *TxQueueConfig `json:"TxQueueConfig," validate:"structonly"`
+
+	// TxMonitorConfig extra configuration for monitoring of sent transactions
+	TxMonitorConfig *TxMonitorConfig `json:"TxMonitorConfig," validate:"structonly"`
+
+	// TxHistoryConfig extra configuration for history of transactions
+	TxHistoryConfig *TxHistoryConfig `json:"TxHistoryConfig," validate:"structonly"`
 }
 
 // NewNodeConfig creates new node configuration object
@@ -319,7 +821,7 @@ func NewNodeConfig(dataDir string, networkID uint64, devMode bool) (*NodeConfig,
 		NetworkID:       networkID,
 		DataDir:         dataDir,
 		Name:            ClientIdentifier,
-		Version:         Version,
+		Version:         VersionWithCommit(),
 		RPCEnabled:      RPCEnabledDefault,
 		HTTPHost:        HTTPHost,
 		HTTPPort:        HTTPPort,
@@ -341,15 +843,44 @@ func NewNodeConfig(dataDir string, networkID uint64, devMode bool) (*NodeConfig,
 			DatabaseCache: DatabaseCache,
 		},
 		WhisperConfig: &WhisperConfig{
-			Enabled:    true,
-			Port:       WhisperPort,
-			MinimumPoW: WhisperMinimumPoW,
-			TTL:        WhisperTTL,
+			Enabled:             true,
+			Port:                WhisperPort,
+			MinimumPoW:          WhisperMinimumPoW,
+			TTL:                 WhisperTTL,
+			OutboxMaxRetries:    WhisperOutboxMaxRetries,
+			OutboxTTL:           WhisperOutboxTTL,
+			MailServerPassword:  WhisperMailServerPassword,
+			MailServerRetention: WhisperMailServerRetention,
 			FirebaseConfig: &FirebaseConfig{
 				NotificationTriggerURL: FirebaseNotificationTriggerURL,
 			},
 		},
-		SwarmConfig: &SwarmConfig{},
+		SwarmConfig:    &SwarmConfig{},
+		JailConfig:     &JailConfig{},
+		SigningConfig:  &SigningConfig{},
+		ExplorerConfig: &ExplorerConfig{},
+		KeyStoreConfig: &KeyStoreConfig{
+			KDF:           KeyStoreKDFScrypt,
+			LightKDF:      true,
+			Argon2Time:    KeyStoreArgon2Time,
+			Argon2Memory:  KeyStoreArgon2Memory,
+			Argon2Threads: KeyStoreArgon2Threads,
+		},
+		FeeConfig:   &FeeConfig{},
+		RelayConfig: &RelayConfig{},
+		TxQueueConfig: &TxQueueConfig{
+			Size:     TxQueueSize,
+			TTL:      TxQueueTTL,
+			Eviction: TxQueueEvictOldest,
+		},
+		TxMonitorConfig: &TxMonitorConfig{
+			Enabled:       true,
+			Confirmations: TxMonitorConfirmations,
+			Timeout:       TxMonitorTimeout,
+		},
+		TxHistoryConfig: &TxHistoryConfig{
+			Enabled: true,
+		},
 	}
 
 	// adjust dependent values
@@ -360,13 +891,18 @@ func NewNodeConfig(dataDir string, networkID uint64, devMode bool) (*NodeConfig,
 	return nodeConfig, nil
 }
 
-// LoadNodeConfig parses incoming JSON and returned it as Config
+// LoadNodeConfig parses incoming JSON and returned it as Config.
+// Encrypted secret fields are decrypted with key of the device, kept in DataDir.
 func LoadNodeConfig(configJSON string) (*NodeConfig, error) {
 	nodeConfig, err := loadNodeConfig(configJSON)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := nodeConfig.decryptSecretsOfDevice(); err != nil {
+		return nil, err
+	}
+
 	if err := nodeConfig.Validate(); err != nil {
 		return nil, err
 	}
@@ -441,6 +977,28 @@ func (c *NodeConfig) Validate() error {
 		}
 	}
 
+	if c.RelayConfig != nil && c.RelayConfig.Enabled {
+		if err := c.RelayConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.TxQueueConfig != nil {
+		if err := validate.Struct(c.TxQueueConfig); err != nil {
+			return err
+		}
+	}
+
+	if c.TxMonitorConfig.Enabled {
+		if err := validate.Struct(c.TxMonitorConfig); err != nil {
+			return err
+		}
+	}
+
+	if err := c.KeyStoreConfig.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
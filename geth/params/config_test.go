@@ -10,6 +10,7 @@ import (
 
 	"gopkg.in/go-playground/validator.v9"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/core"
 	gethparams "github.com/ethereum/go-ethereum/params"
 	"github.com/status-im/status-go/geth/params"
@@ -72,6 +73,148 @@ var loadConfigTestCases = []struct {
 			require.Equal(t, "/foo/bar", nodeConfig.KeyStoreDir)
 		},
 	},
+	{
+		`default KeyStoreConfig`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR"
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.NoError(t, err)
+			require.Equal(t, params.KeyStoreKDFScrypt, nodeConfig.KeyStoreConfig.KDF)
+			require.Nil(t, nodeConfig.KeyStoreConfig.Argon2Params())
+			scryptN, scryptP := nodeConfig.KeyStoreConfig.ScryptParams()
+			require.Equal(t, keystore.LightScryptN, scryptN)
+			require.Equal(t, keystore.LightScryptP, scryptP)
+		},
+	},
+	{
+		`custom scrypt parameters`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"KeyStoreConfig": {
+				"ScryptN": 8192,
+				"ScryptP": 2
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.NoError(t, err)
+			scryptN, scryptP := nodeConfig.KeyStoreConfig.ScryptParams()
+			require.Equal(t, 8192, scryptN)
+			require.Equal(t, 2, scryptP)
+		},
+	},
+	{
+		`invalid scrypt parameters`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"KeyStoreConfig": {
+				"ScryptN": 1000,
+				"ScryptP": 1
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.Equal(t, params.ErrInvalidScryptParams, err)
+		},
+	},
+	{
+		`Argon2id KDF`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"KeyStoreConfig": {
+				"KDF": "argon2id",
+				"Argon2Memory": 16384
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.NoError(t, err)
+			require.Equal(t, &keystore.Argon2Params{
+				Time:    params.KeyStoreArgon2Time,
+				Memory:  16384,
+				Threads: params.KeyStoreArgon2Threads,
+			}, nodeConfig.KeyStoreConfig.Argon2Params())
+		},
+	},
+	{
+		`unknown KDF`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"KeyStoreConfig": {
+				"KDF": "pbkdf2"
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.Error(t, err)
+		},
+	},
+	{
+		`transaction queue limits`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"TxQueueConfig": {
+				"Size": 10,
+				"Eviction": "reject-new"
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.NoError(t, err)
+			require.Equal(t, &params.TxQueueConfig{
+				Size:     10,
+				TTL:      params.TxQueueTTL,
+				Eviction: params.TxQueueRejectNew,
+			}, nodeConfig.TxQueueConfig)
+		},
+	},
+	{
+		`unknown eviction policy of transaction queue`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"TxQueueConfig": {
+				"Eviction": "evict-newest"
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.Error(t, err)
+		},
+	},
+	{
+		`gas relay`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"RelayConfig": {
+				"Enabled": true,
+				"URL": "https://relay.example.com/relay",
+				"HubAddress": "0xd216153c06e857cd7f72665e0af1d7d82172f494",
+				"Fee": 10
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.NoError(t, err)
+			require.True(t, nodeConfig.RelayConfig.Enabled)
+			require.Equal(t, uint64(10), nodeConfig.RelayConfig.Fee)
+		},
+	},
+	{
+		`gas relay without hub address`,
+		`{
+			"NetworkId": 3,
+			"DataDir": "$TMPDIR",
+			"RelayConfig": {
+				"Enabled": true,
+				"URL": "https://relay.example.com/relay"
+			}
+		}`,
+		func(t *testing.T, dataDir string, nodeConfig *params.NodeConfig, err error) {
+			require.Equal(t, params.ErrInvalidRelayHubAddress, err)
+		},
+	},
 	{
 		`test Upstream config setting`,
 		`{
@@ -530,3 +673,16 @@ func TestNodeConfigValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestWhisperConfigMessagePoW(t *testing.T) {
+	config := params.WhisperConfig{MinimumPoW: 0.001, LightClientPoW: 0.0005}
+	require.Equal(t, 0.001, config.MessagePoW())
+
+	// light client uses lower PoW, if it's set
+	config.LightClient = true
+	require.Equal(t, 0.0005, config.MessagePoW())
+	config.LightClientPoW = 0
+	require.Equal(t, 0.001, config.MessagePoW())
+	config.LightClientPoW = 0.01
+	require.Equal(t, 0.001, config.MessagePoW())
+}
@@ -76,9 +76,54 @@ const (
 	// WhisperTTL is time to live for messages, in seconds
 	WhisperTTL = 120
 
+	// WhisperOutboxMaxRetries is a maximum number of attempts to send message of outbox
+	WhisperOutboxMaxRetries = 10
+
+	// WhisperOutboxTTL is time (in seconds), message is kept in outbox for, until it's sent
+	WhisperOutboxTTL = 24 * 60 * 60
+
+	// WhisperMailServerPassword is a password of Status mail servers
+	WhisperMailServerPassword = "status-offline-inbox"
+
+	// WhisperMailServerRetention is time (in seconds), envelopes are archived by mail server for
+	WhisperMailServerRetention = 30 * 24 * 60 * 60
+
 	// FirebaseNotificationTriggerURL is URL where FCM notification requests are sent to
 	FirebaseNotificationTriggerURL = "https://fcm.googleapis.com/fcm/send"
 
+	// KeyStoreKDFScrypt is scrypt key derivation function of account keys (see KeyStoreConfig.KDF)
+	KeyStoreKDFScrypt = "scrypt"
+
+	// KeyStoreKDFArgon2id is Argon2id key derivation function of account keys (see KeyStoreConfig.KDF)
+	KeyStoreKDFArgon2id = "argon2id"
+
+	// KeyStoreArgon2Time is the default number of passes of Argon2id over the memory
+	KeyStoreArgon2Time = 3
+
+	// KeyStoreArgon2Memory is the default memory (in KiB) used by Argon2id
+	KeyStoreArgon2Memory = 64 * 1024
+
+	// KeyStoreArgon2Threads is the default number of lanes of Argon2id
+	KeyStoreArgon2Threads = 4
+
+	// TxQueueSize is the default maximum number of queued transactions (see TxQueueConfig)
+	TxQueueSize = 35
+
+	// TxQueueTTL is the default time (in seconds), within which queued transaction must be completed
+	TxQueueTTL = 300
+
+	// TxQueueEvictOldest is eviction policy, the oldest queued transaction is evicted with, once queue is full
+	TxQueueEvictOldest = "evict-oldest"
+
+	// TxQueueRejectNew is eviction policy, new transactions are rejected with, once queue is full
+	TxQueueRejectNew = "reject-new"
+
+	// TxMonitorConfirmations is the default number of blocks, transaction is confirmed with (see TxMonitorConfig)
+	TxMonitorConfirmations = 1
+
+	// TxMonitorTimeout is the default time (in seconds), sent transaction is expected to be mined within
+	TxMonitorTimeout = 3600
+
 	// UpstreamMainNetEthereumNetworkURL is URL where the upstream ethereum network is loaded to
 	// allow us avoid syncing node.
 	UpstreamMainNetEthereumNetworkURL = "https://mainnet.infura.io/nKmXgiFgc2KqtoQ8BCGJ"
@@ -91,6 +136,33 @@ const (
 	// allow us avoid syncing node.
 	UpstreamRinkebyEthereumNetworkURL = "https://rinkeby.infura.io/nKmXgiFgc2KqtoQ8BCGJ"
 
+	// UpstreamStrategyPriority makes upstream calls go to the first healthy provider, in order they are listed
+	UpstreamStrategyPriority = "priority"
+
+	// UpstreamStrategyRoundRobin makes upstream calls spread evenly across healthy providers
+	UpstreamStrategyRoundRobin = "round-robin"
+
+	// UpstreamRouteLocal routes RPC method to the local node
+	UpstreamRouteLocal = "local"
+
+	// UpstreamRouteUpstream routes RPC method to upstream providers
+	UpstreamRouteUpstream = "upstream"
+
+	// JailEngineOtto is JavaScript engine of jail cells (see JailConfig.Engine)
+	JailEngineOtto = "otto"
+
+	// JailEngineGoja is JavaScript engine of jail cells, supporting ES6 and later (see JailConfig.Engine)
+	JailEngineGoja = "goja"
+
+	// UpstreamHealthCheckInterval is interval (in seconds) between health checks of upstream providers
+	UpstreamHealthCheckInterval = 30
+
+	// UpstreamCallTimeout is a timeout (in seconds) of a single attempt of RPC call
+	UpstreamCallTimeout = 60
+
+	// UpstreamBreakerCooldown is interval (in seconds), after which open circuit breaker lets a probe call through
+	UpstreamBreakerCooldown = 30
+
 	// MainNetworkID is id of the main network
 	MainNetworkID = 1
 
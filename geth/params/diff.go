@@ -0,0 +1,142 @@
+package params
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces values, which are not disclosed by config diff
+const RedactedValue = "<redacted>"
+
+// redactors hide values of config fields, by field path. Secret fields (see DefaultSecretFields)
+// are hidden completely, unless a redactor leaving their non-sensitive part is set here.
+var redactors = map[string]func(value interface{}) interface{}{
+	"UpstreamConfig.URL":            redactURL,
+	"UpstreamConfig.FallbackURLs":   redactURLs,
+	"UpstreamConfig.WebSocketURL":   redactURL,
+	"UpstreamConfig.Headers":        redactHeaders,
+	"BootClusterConfig.RegistryURL": redactURL,
+	"FeeConfig.GasPriceOracleURL":   redactURL,
+	"RelayConfig.URL":               redactURL,
+	"LightEthConfig.Genesis":        redactAll,
+}
+
+func init() {
+	for _, field := range DefaultSecretFields {
+		if _, ok := redactors[field]; !ok {
+			redactors[field] = redactAll
+		}
+	}
+}
+
+// ConfigChange describes change of a single config field.
+type ConfigChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// String returns human-readable representation of change
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Field, c.Old, c.New)
+}
+
+// Diff returns changes of fields of other config, relative to this one.
+// Values of sensitive fields (e.g. upstream URL, possibly containing API key) are redacted.
+func (c *NodeConfig) Diff(other *NodeConfig) []ConfigChange {
+	var changes []ConfigChange
+	diffValues("", reflect.ValueOf(c), reflect.ValueOf(other), &changes)
+
+	return changes
+}
+
+// diffValues compares values field by field, descending into nested structs.
+func diffValues(path string, a, b reflect.Value, changes *[]ConfigChange) {
+	if a.Kind() == reflect.Ptr && b.Kind() == reflect.Ptr && a.Type().Elem().Kind() == reflect.Struct {
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*changes = append(*changes, newConfigChange(path, a.Interface(), b.Interface()))
+			}
+			return
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+
+	if a.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*changes = append(*changes, newConfigChange(path, a.Interface(), b.Interface()))
+		}
+		return
+	}
+
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		diffValues(fieldPath, a.Field(i), b.Field(i), changes)
+	}
+}
+
+func newConfigChange(path string, oldValue, newValue interface{}) ConfigChange {
+	if redact, ok := redactors[path]; ok {
+		oldValue, newValue = redact(oldValue), redact(newValue)
+	}
+
+	return ConfigChange{Field: path, Old: oldValue, New: newValue}
+}
+
+// RedactURL leaves only scheme and host of URL, as the rest may contain access tokens.
+func RedactURL(rawURL string) string {
+	if rawURL == "" {
+		return rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return RedactedValue
+	}
+	if u.Path == "" && u.RawQuery == "" && u.User == nil {
+		return u.Scheme + "://" + u.Host
+	}
+
+	return u.Scheme + "://" + u.Host + "/" + RedactedValue
+}
+
+func redactURL(value interface{}) interface{} {
+	rawURL, _ := value.(string)
+	return RedactURL(rawURL)
+}
+
+func redactURLs(value interface{}) interface{} {
+	rawURLs, _ := value.([]string)
+	urls := make([]string, len(rawURLs))
+	for i, rawURL := range rawURLs {
+		urls[i] = RedactURL(rawURL)
+	}
+
+	return urls
+}
+
+// redactAll hides value completely.
+func redactAll(value interface{}) interface{} {
+	return RedactedValue
+}
+
+// redactHeaders leaves only names of headers.
+func redactHeaders(value interface{}) interface{} {
+	rawHeaders, _ := value.([]string)
+	headers := make([]string, len(rawHeaders))
+	for i, rawHeader := range rawHeaders {
+		headers[i] = strings.SplitN(rawHeader, ":", 2)[0] + ": " + RedactedValue
+	}
+
+	return headers
+}
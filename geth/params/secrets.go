@@ -0,0 +1,274 @@
+package params
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// EncryptedValuePrefix marks encrypted values of secret config fields
+const EncryptedValuePrefix = "encrypted:"
+
+// ConfigKeyFileName is a name of file in DataDir, which holds key of the device,
+// secret config fields are encrypted with
+const ConfigKeyFileName = "config.key"
+
+// configKeySize is a size of key of the device (AES-256)
+const configKeySize = 32
+
+// DefaultSecretFields lists config fields encrypted at rest, unless NodeConfig.SecretFields is set
+// (upstream URLs and headers may contain API keys).
+var DefaultSecretFields = []string{
+	"UpstreamConfig.URL",
+	"UpstreamConfig.FallbackURLs",
+	"UpstreamConfig.WebSocketURL",
+	"UpstreamConfig.Headers",
+	"UpstreamConfig.BasicAuth",
+	"BootClusterConfig.RegistryURL",
+	"WhisperConfig.MailServerPassword",
+	"WhisperConfig.FirebaseConfig.AuthorizationKey",
+	"FeeConfig.GasPriceOracleURL",
+	"RelayConfig.URL",
+}
+
+// errors
+var (
+	ErrUnknownSecretField     = errors.New("unknown secret config field, string or []string field expected")
+	ErrConfigSecretsEncrypted = errors.New("config contains encrypted secrets, but key of the device is missing")
+	ErrInvalidEncryptedValue  = errors.New("invalid encrypted config value")
+	ErrInvalidConfigKey       = errors.New("invalid key of the device, config secrets are encrypted with")
+)
+
+// DeviceConfigKey returns key of the device, secret config fields are encrypted with, generating it
+// on the first call. Key is kept in data dir (see ConfigKeyFileName), readable by owner only, so that
+// it's available, when node is started, before any account is selected.
+func DeviceConfigKey(dataDir string) ([]byte, error) {
+	key, err := readDeviceConfigKey(dataDir)
+	if !os.IsNotExist(err) {
+		return key, err
+	}
+
+	key = make([]byte, configKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dataDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dataDir, ConfigKeyFileName), key, 0600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func readDeviceConfigKey(dataDir string) ([]byte, error) {
+	key, err := ioutil.ReadFile(filepath.Join(dataDir, ConfigKeyFileName))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != configKeySize {
+		return nil, ErrInvalidConfigKey
+	}
+
+	return key, nil
+}
+
+// decryptSecretsOfDevice decrypts secret fields with key of the device, if any of them is encrypted.
+func (c *NodeConfig) decryptSecretsOfDevice() error {
+	if !c.HasEncryptedSecrets() {
+		return nil
+	}
+
+	key, err := readDeviceConfigKey(c.DataDir)
+	if os.IsNotExist(err) {
+		return ErrConfigSecretsEncrypted
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.DecryptSecrets(key)
+}
+
+// EncryptSecrets encrypts values of secret fields (see SecretFields) in place.
+// Empty and already encrypted values are left as is.
+func (c *NodeConfig) EncryptSecrets(key []byte) error {
+	gcm, err := newConfigGCM(key)
+	if err != nil {
+		return err
+	}
+
+	return c.updateSecrets(func(field, value string) (string, error) {
+		if value == "" || strings.HasPrefix(value, EncryptedValuePrefix) {
+			return value, nil
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		// field is authenticated, so that encrypted values can't be swapped between fields
+		sealed := gcm.Seal(nonce, nonce, []byte(value), []byte(field))
+
+		return EncryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+	})
+}
+
+// DecryptSecrets decrypts encrypted values of secret fields in place.
+func (c *NodeConfig) DecryptSecrets(key []byte) error {
+	if !c.HasEncryptedSecrets() {
+		return nil
+	}
+
+	gcm, err := newConfigGCM(key)
+	if err != nil {
+		return err
+	}
+
+	return c.updateSecrets(func(field, value string) (string, error) {
+		if !strings.HasPrefix(value, EncryptedValuePrefix) {
+			return value, nil
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedValuePrefix))
+		if err != nil || len(sealed) < gcm.NonceSize() {
+			return "", fmt.Errorf("%s: %v", field, ErrInvalidEncryptedValue)
+		}
+		plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(field))
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", field, err)
+		}
+
+		return string(plaintext), nil
+	})
+}
+
+// HasEncryptedSecrets returns true, if any of secret fields holds encrypted value.
+func (c *NodeConfig) HasEncryptedSecrets() bool {
+	encrypted := false
+	c.updateSecrets(func(field, value string) (string, error) { // nolint: errcheck
+		encrypted = encrypted || strings.HasPrefix(value, EncryptedValuePrefix)
+		return value, nil
+	})
+
+	return encrypted
+}
+
+// Encrypted returns a copy of configuration, secret fields of which are encrypted with key of the device
+// (see DeviceConfigKey), so that LoadNodeConfig decrypts them. Configuration itself is left unchanged.
+func (c *NodeConfig) Encrypted() (*NodeConfig, error) {
+	key, err := DeviceConfigKey(c.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := &NodeConfig{}
+	if err := json.Unmarshal(data, encrypted); err != nil {
+		return nil, err
+	}
+
+	if err := encrypted.EncryptSecrets(key); err != nil {
+		return nil, err
+	}
+
+	return encrypted, nil
+}
+
+// SaveEncrypted dumps configuration to the disk, with secret fields encrypted (see Encrypted).
+func (c *NodeConfig) SaveEncrypted() error {
+	encrypted, err := c.Encrypted()
+	if err != nil {
+		return err
+	}
+
+	return encrypted.Save()
+}
+
+// secretFields returns paths of fields encrypted at rest.
+func (c *NodeConfig) secretFields() []string {
+	if len(c.SecretFields) > 0 {
+		return c.SecretFields
+	}
+	return DefaultSecretFields
+}
+
+// updateSecrets replaces values of secret fields with results of update.
+func (c *NodeConfig) updateSecrets(update func(field, value string) (string, error)) error {
+	for _, field := range c.secretFields() {
+		value, ok := configField(reflect.ValueOf(c), field)
+		if !ok {
+			return fmt.Errorf("%s: %v", field, ErrUnknownSecretField)
+		}
+		// parent struct is not configured
+		if !value.IsValid() {
+			continue
+		}
+
+		switch {
+		case value.Kind() == reflect.String:
+			updated, err := update(field, value.String())
+			if err != nil {
+				return err
+			}
+			value.SetString(updated)
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			for i := 0; i < value.Len(); i++ {
+				updated, err := update(field, value.Index(i).String())
+				if err != nil {
+					return err
+				}
+				value.Index(i).SetString(updated)
+			}
+		default:
+			return fmt.Errorf("%s: %v", field, ErrUnknownSecretField)
+		}
+	}
+
+	return nil
+}
+
+// configField finds field by path, such as "UpstreamConfig.URL". Invalid value is returned,
+// if one of structs along the path is nil.
+func configField(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, true
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+
+	return v, true
+}
+
+func newConfigGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,78 @@
+package params_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSecretsEncryption(t *testing.T) {
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "geth-config-tests")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	nodeConfig, err := params.NewNodeConfig(tmpDir, params.RopstenNetworkID, true)
+	require.NoError(t, err)
+	nodeConfig.UpstreamConfig.URL = "https://ropsten.infura.io/secret"
+	nodeConfig.UpstreamConfig.FallbackURLs = []string{"https://fallback.example/secret"}
+	nodeConfig.WhisperConfig.FirebaseConfig.AuthorizationKey = "fcm-secret"
+
+	require.NoError(t, nodeConfig.SaveEncrypted())
+	require.Equal(t, "https://ropsten.infura.io/secret", nodeConfig.UpstreamConfig.URL, "config itself is not changed")
+
+	configJSON, err := ioutil.ReadFile(filepath.Join(tmpDir, "config.json"))
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(configJSON), "secret"), "secrets are not stored in plaintext")
+
+	// key of the device is generated once, readable by owner only
+	info, err := os.Stat(filepath.Join(tmpDir, params.ConfigKeyFileName))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	key, err := params.DeviceConfigKey(tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, nodeConfig.SaveEncrypted())
+	sameKey, err := params.DeviceConfigKey(tmpDir)
+	require.NoError(t, err)
+	require.Equal(t, key, sameKey)
+
+	// encrypted config is decrypted on load, as node is started
+	loaded, err := params.LoadNodeConfig(string(configJSON))
+	require.NoError(t, err)
+	require.Equal(t, nodeConfig.UpstreamConfig.URL, loaded.UpstreamConfig.URL)
+	require.Equal(t, nodeConfig.UpstreamConfig.FallbackURLs, loaded.UpstreamConfig.FallbackURLs)
+	fcmKey, err := loaded.WhisperConfig.FirebaseConfig.ReadAuthorizationKeyFile()
+	require.NoError(t, err)
+	require.Equal(t, "fcm-secret", string(fcmKey))
+
+	// encrypted config can't be loaded without key of the device, or with another one
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, params.ConfigKeyFileName), make([]byte, 32), 0600))
+	_, err = params.LoadNodeConfig(string(configJSON))
+	require.Error(t, err)
+
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, params.ConfigKeyFileName)))
+	_, err = params.LoadNodeConfig(string(configJSON))
+	require.Equal(t, params.ErrConfigSecretsEncrypted, err)
+}
+
+func TestConfigSecretFields(t *testing.T) {
+	nodeConfig, err := params.NewNodeConfig("/tmp/data", params.RopstenNetworkID, true)
+	require.NoError(t, err)
+	key := make([]byte, 32)
+
+	// values can't be swapped between fields
+	nodeConfig.SecretFields = []string{"UpstreamConfig.URL", "Name"}
+	require.NoError(t, nodeConfig.EncryptSecrets(key))
+	nodeConfig.Name, nodeConfig.UpstreamConfig.URL = nodeConfig.UpstreamConfig.URL, nodeConfig.Name
+	require.Error(t, nodeConfig.DecryptSecrets(key))
+
+	nodeConfig.SecretFields = []string{"UpstreamConfig.Missing"}
+	require.Error(t, nodeConfig.EncryptSecrets(key))
+
+	nodeConfig.SecretFields = []string{"NetworkID"}
+	require.Error(t, nodeConfig.EncryptSecrets(key))
+}
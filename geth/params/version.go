@@ -2,6 +2,10 @@ package params
 
 import (
 	"fmt"
+	"runtime"
+	"strings"
+
+	gethparams "github.com/ethereum/go-ethereum/params"
 )
 
 const (
@@ -20,3 +24,60 @@ const (
 
 // Version exposes string representation of program version.
 var Version = fmt.Sprintf("%d.%d.%d-%s", VersionMajor, VersionMinor, VersionPatch, VersionMeta)
+
+// Build metadata, set at compile time:
+//
+//	-ldflags '-X github.com/status-im/status-go/geth/params.GitCommit=...'
+//
+// (see build/testnet-flags.sh). BuildTime is the time of commit, rather than of build, so that
+// builds of the same commit are reproducible.
+var (
+	// GitCommit is a hash of commit the binary is built from
+	GitCommit = ""
+
+	// BuildTime is a time (RFC 3339) of commit the binary is built from
+	BuildTime = ""
+
+	// BuildFeatures is a comma separated list of features enabled at compile time
+	BuildFeatures = ""
+)
+
+// BuildInfo describes exact code the binary is built from.
+type BuildInfo struct {
+	Version     string   `json:"version"`
+	GitCommit   string   `json:"gitCommit"`
+	BuildTime   string   `json:"buildTime"`
+	GethVersion string   `json:"gethVersion"`
+	GoVersion   string   `json:"goVersion"`
+	Features    []string `json:"features"`
+}
+
+// VersionWithCommit returns version, suffixed with short commit hash if it is known.
+func VersionWithCommit() string {
+	if len(GitCommit) >= 8 {
+		return Version + "-" + GitCommit[:8]
+	}
+	return Version
+}
+
+// Build returns metadata of the running binary.
+func Build() BuildInfo {
+	features := []string{}
+	if UseMainnetFlag == "true" {
+		features = append(features, "mainnet")
+	}
+	for _, feature := range strings.Split(BuildFeatures, ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			features = append(features, feature)
+		}
+	}
+
+	return BuildInfo{
+		Version:     Version,
+		GitCommit:   GitCommit,
+		BuildTime:   BuildTime,
+		GethVersion: gethparams.Version,
+		GoVersion:   runtime.Version(),
+		Features:    features,
+	}
+}
@@ -0,0 +1,29 @@
+package params
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	defer func(commit, features string) {
+		GitCommit, BuildFeatures = commit, features
+	}(GitCommit, BuildFeatures)
+
+	GitCommit = ""
+	BuildFeatures = ""
+	require.Equal(t, Version, VersionWithCommit())
+	require.Equal(t, []string{}, Build().Features)
+
+	GitCommit = "0123456789abcdef"
+	BuildFeatures = "jail-eip1193, ,debug"
+	require.Equal(t, Version+"-01234567", VersionWithCommit())
+
+	build := Build()
+	require.Equal(t, GitCommit, build.GitCommit)
+	require.Equal(t, runtime.Version(), build.GoVersion)
+	require.NotEmpty(t, build.GethVersion)
+	require.Equal(t, []string{"jail-eip1193", "debug"}, build.Features)
+}
@@ -0,0 +1,14 @@
+/*
+Package peers - quality measurements of connected peers.
+
+Package peers observes messages of node's sub-protocols, in order to measure
+round-trip latency and request success rate of every connected peer. Measurements
+are exposed along with peers info and as metrics, and are meant to be used when
+choosing among available peers.
+
+Peers are scored by their capabilities (mail servers and LES/2 servers are preferred)
+and measured performance, score is exposed along with its rationale in peers info.
+Once all connection slots are taken, Pool drops the least useful peer, so that
+limited slots of mobile nodes are spent on the most useful peers.
+*/
+package peers
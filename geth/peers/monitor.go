@@ -0,0 +1,278 @@
+package peers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	// DefaultRequestTimeout is a period after which unanswered request is counted as failed
+	DefaultRequestTimeout = 10 * time.Second
+
+	// latencyWeight is a weight of the latest sample in latency moving average
+	latencyWeight = 0.2
+)
+
+// Stats holds quality measurements of a single sub-protocol of a peer.
+type Stats struct {
+	Latency   time.Duration `json:"latency"`   // moving average of round-trip time
+	Requests  uint64        `json:"requests"`  // number of requests sent
+	Responses uint64        `json:"responses"` // number of responses received
+	Failures  uint64        `json:"failures"`  // number of requests timed out
+}
+
+// SuccessRate returns ratio of answered requests to all completed ones.
+// Peer without completed requests is assumed to be successful.
+func (s Stats) SuccessRate() float64 {
+	completed := s.Responses + s.Failures
+	if completed == 0 {
+		return 1
+	}
+
+	return float64(s.Responses) / float64(completed)
+}
+
+// Score rates peer's quality: the higher, the better.
+// Slow or unreliable peers have score close to 0, while perfect ones have score close to 1.
+func (s Stats) Score() float64 {
+	return s.SuccessRate() / (1 + s.Latency.Seconds())
+}
+
+// Info describes connected peer along with its quality measurements.
+type Info struct {
+	*p2p.PeerInfo
+	Quality map[string]Stats `json:"quality"` // measurements by sub-protocol name
+	Score   Score            `json:"score"`
+}
+
+// Monitor measures round-trip latency and request success rate of connected peers,
+// by timing request and response messages of wrapped sub-protocols.
+type Monitor struct {
+	mu          sync.RWMutex
+	peers       map[discover.NodeID]map[string]*peerProtocol
+	mailServers map[discover.NodeID]bool
+	timeout     time.Duration
+}
+
+// NewMonitor returns monitor counting requests not answered within timeout as failed.
+func NewMonitor(timeout time.Duration) *Monitor {
+	return &Monitor{
+		peers:   make(map[discover.NodeID]map[string]*peerProtocol),
+		timeout: timeout,
+	}
+}
+
+// WrapProtocol returns sub-protocol with messages being measured.
+// Responses maps codes of request messages to codes of corresponding responses.
+func (m *Monitor) WrapProtocol(protocol p2p.Protocol, responses map[uint64]uint64) p2p.Protocol {
+	run := protocol.Run
+	meters := newProtocolMeters(protocol.Name)
+
+	protocol.Run = func(peer *p2p.Peer, rw p2p.MsgReadWriter) error {
+		pp := m.addPeer(peer.ID(), protocol.Name, responses, meters)
+		defer m.removePeer(peer.ID(), protocol.Name)
+
+		return run(peer, &measuredReadWriter{MsgReadWriter: rw, peer: pp})
+	}
+
+	return protocol
+}
+
+// Stats returns measurements of a connected peer, by sub-protocol name.
+func (m *Monitor) Stats(id discover.NodeID) (map[string]Stats, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	protocols, ok := m.peers[id]
+	if !ok {
+		return nil, false
+	}
+
+	stats := make(map[string]Stats, len(protocols))
+	for name, pp := range protocols {
+		stats[name] = pp.stats()
+	}
+
+	return stats, true
+}
+
+// Score returns quality score of a peer for a given sub-protocol,
+// in order to prefer better peers, when choosing among them.
+func (m *Monitor) Score(id discover.NodeID, protocol string) float64 {
+	stats, ok := m.Stats(id)
+	if !ok {
+		return 0
+	}
+
+	s, ok := stats[protocol]
+	if !ok {
+		return 0
+	}
+
+	return s.Score()
+}
+
+// Peers returns info of connected peers, along with their measurements and scores,
+// the most useful peers first.
+func (m *Monitor) Peers(server *p2p.Server) []Info {
+	var infos []Info
+	for _, peer := range server.Peers() {
+		stats, _ := m.Stats(peer.ID())
+		infos = append(infos, Info{
+			PeerInfo: peer.Info(),
+			Quality:  stats,
+			Score:    m.PeerScore(peer),
+		})
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Score.Value > infos[j].Score.Value
+	})
+
+	return infos
+}
+
+func (m *Monitor) addPeer(id discover.NodeID, protocol string, responses map[uint64]uint64, meters protocolMeters) *peerProtocol {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pp := &peerProtocol{
+		responses: responses,
+		pending:   make(map[uint64][]time.Time),
+		timeout:   m.timeout,
+		meters:    meters,
+	}
+
+	if _, ok := m.peers[id]; !ok {
+		m.peers[id] = make(map[string]*peerProtocol)
+	}
+	m.peers[id][protocol] = pp
+
+	return pp
+}
+
+func (m *Monitor) removePeer(id discover.NodeID, protocol string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.peers[id], protocol)
+	if len(m.peers[id]) == 0 {
+		delete(m.peers, id)
+	}
+}
+
+// protocolMeters are metrics shared by all peers of a sub-protocol.
+type protocolMeters struct {
+	latency  metrics.Timer
+	failures metrics.Meter
+}
+
+func newProtocolMeters(protocol string) protocolMeters {
+	return protocolMeters{
+		latency:  gethmetrics.NewTimer("peers/" + protocol + "/latency"),
+		failures: gethmetrics.NewMeter("peers/" + protocol + "/failures"),
+	}
+}
+
+// peerProtocol tracks requests of a single sub-protocol of a peer.
+type peerProtocol struct {
+	mu        sync.Mutex
+	responses map[uint64]uint64      // request code -> response code
+	pending   map[uint64][]time.Time // response code -> times of requests waiting for it
+	timeout   time.Duration
+	meters    protocolMeters
+	current   Stats
+}
+
+func (pp *peerProtocol) stats() Stats {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	pp.expire(time.Now())
+
+	return pp.current
+}
+
+// sent registers outgoing message, starting the timer if it is a request.
+func (pp *peerProtocol) sent(code uint64, now time.Time) {
+	responseCode, ok := pp.responses[code]
+	if !ok {
+		return
+	}
+
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	pp.expire(now)
+	pp.pending[responseCode] = append(pp.pending[responseCode], now)
+	pp.current.Requests++
+}
+
+// received registers incoming message, stopping the timer of the oldest request,
+// if message is a response to it.
+func (pp *peerProtocol) received(code uint64, now time.Time) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	pp.expire(now)
+
+	pending := pp.pending[code]
+	if len(pending) == 0 {
+		return
+	}
+	pp.pending[code] = pending[1:]
+
+	latency := now.Sub(pending[0])
+	pp.current.Responses++
+	if pp.current.Responses == 1 {
+		pp.current.Latency = latency
+	} else {
+		pp.current.Latency += time.Duration(latencyWeight * float64(latency-pp.current.Latency))
+	}
+	pp.meters.latency.Update(latency)
+}
+
+// expire counts requests waiting for longer than timeout as failed.
+func (pp *peerProtocol) expire(now time.Time) {
+	for code, pending := range pp.pending {
+		expired := 0
+		for expired < len(pending) && now.Sub(pending[expired]) > pp.timeout {
+			expired++
+		}
+		if expired == 0 {
+			continue
+		}
+
+		pp.pending[code] = pending[expired:]
+		pp.current.Failures += uint64(expired)
+		pp.meters.failures.Mark(int64(expired))
+	}
+}
+
+// measuredReadWriter reports messages passing through to the peer's tracker.
+type measuredReadWriter struct {
+	p2p.MsgReadWriter
+	peer *peerProtocol
+}
+
+// ReadMsg implements p2p.MsgReader
+func (rw *measuredReadWriter) ReadMsg() (p2p.Msg, error) {
+	msg, err := rw.MsgReadWriter.ReadMsg()
+	if err == nil {
+		rw.peer.received(msg.Code, time.Now())
+	}
+
+	return msg, err
+}
+
+// WriteMsg implements p2p.MsgWriter
+func (rw *measuredReadWriter) WriteMsg(msg p2p.Msg) error {
+	rw.peer.sent(msg.Code, time.Now())
+
+	return rw.MsgReadWriter.WriteMsg(msg)
+}
@@ -0,0 +1,94 @@
+package peers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRequestCode  = 1
+	testResponseCode = 2
+	testAnnounceCode = 3
+)
+
+func TestPeerProtocolStats(t *testing.T) {
+	pp := &peerProtocol{
+		responses: map[uint64]uint64{testRequestCode: testResponseCode},
+		pending:   make(map[uint64][]time.Time),
+		timeout:   time.Second,
+		meters:    newProtocolMeters("test"),
+	}
+
+	start := time.Now()
+	pp.sent(testRequestCode, start)
+	pp.sent(testRequestCode, start)
+	pp.sent(testAnnounceCode, start) // not a request
+	pp.received(testResponseCode, start.Add(100*time.Millisecond))
+	pp.received(testAnnounceCode, start.Add(100*time.Millisecond)) // not a response
+
+	pp.mu.Lock()
+	pp.expire(start.Add(2 * time.Second))
+	pp.mu.Unlock()
+
+	stats := pp.current
+	require.EqualValues(t, 2, stats.Requests)
+	require.EqualValues(t, 1, stats.Responses)
+	require.EqualValues(t, 1, stats.Failures)
+	require.Equal(t, 100*time.Millisecond, stats.Latency)
+	require.Equal(t, 0.5, stats.SuccessRate())
+}
+
+func TestMonitorWrapProtocol(t *testing.T) {
+	monitor := NewMonitor(DefaultRequestTimeout)
+	peerID := discover.NodeID{1}
+
+	read := make(chan struct{})
+	done := make(chan struct{})
+	protocol := monitor.WrapProtocol(p2p.Protocol{
+		Name: "test",
+		Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) error {
+			if err := p2p.Send(rw, testRequestCode, []uint{1}); err != nil {
+				return err
+			}
+			msg, err := rw.ReadMsg()
+			if err != nil {
+				return err
+			}
+			if err := msg.Discard(); err != nil {
+				return err
+			}
+			close(read)
+			<-done
+			return nil
+		},
+	}, map[uint64]uint64{testRequestCode: testResponseCode})
+
+	local, remote := p2p.MsgPipe()
+	defer local.Close()  // nolint: errcheck
+	defer remote.Close() // nolint: errcheck
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- protocol.Run(p2p.NewPeer(peerID, "test", nil), local)
+	}()
+
+	require.NoError(t, p2p.ExpectMsg(remote, testRequestCode, []uint{1}))
+	require.NoError(t, p2p.Send(remote, testResponseCode, []uint{2}))
+
+	<-read
+	stats, ok := monitor.Stats(peerID)
+	require.True(t, ok)
+	require.EqualValues(t, 1, stats["test"].Responses)
+	require.True(t, monitor.Score(peerID, "test") > 0)
+
+	close(done)
+	require.NoError(t, <-errc)
+
+	// stats are dropped, once peer is disconnected
+	_, ok = monitor.Stats(peerID)
+	require.False(t, ok)
+}
@@ -0,0 +1,140 @@
+package peers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/status-im/status-go/geth/log"
+)
+
+const (
+	// poolInterval is an interval between checks of connected peers
+	poolInterval = time.Minute
+
+	// poolMinPeerAge is a period peer is kept connected for, so that its performance is measured
+	poolMinPeerAge = 5 * time.Minute
+
+	// poolDropScore is a score, peers below which are dropped, once all connection slots are taken
+	poolDropScore = 0.5
+)
+
+// Pool spends limited connection slots on the most useful peers: once all of them are taken,
+// the least useful peer (without preferred capabilities, and performing poorly) is dropped,
+// so that the slot is taken by another peer, found by discovery.
+type Pool struct {
+	monitor *Monitor
+
+	mu        sync.Mutex
+	protected map[discover.NodeID]bool      // e.g. static peers, which are never dropped
+	seen      map[discover.NodeID]time.Time // when connected peers were seen the first time
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	clock     func() time.Time
+}
+
+// NewPool returns pool of peers rated by a given monitor.
+func NewPool(monitor *Monitor) *Pool {
+	return &Pool{
+		monitor:   monitor,
+		protected: make(map[discover.NodeID]bool),
+		seen:      make(map[discover.NodeID]time.Time),
+		clock:     time.Now,
+	}
+}
+
+// Protect marks peer (e.g. static one) as never dropped.
+func (p *Pool) Protect(id discover.NodeID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.protected[id] = true
+}
+
+// Start starts checking peers of a given server periodically.
+func (p *Pool) Start(server *p2p.Server) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.quit != nil {
+		return
+	}
+	p.quit = make(chan struct{})
+
+	p.wg.Add(1)
+	go func(quit chan struct{}) {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(poolInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				p.balance(server.Peers(), server.MaxPeers)
+			case <-quit:
+				return
+			}
+		}
+	}(p.quit)
+}
+
+// Stop stops checking peers.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if p.quit == nil {
+		p.mu.Unlock()
+		return
+	}
+	close(p.quit)
+	p.quit = nil
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// balance drops the least useful peer, if all slots are taken. Dropped peer is returned (if any).
+func (p *Pool) balance(peers []*p2p.Peer, maxPeers int) *p2p.Peer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.clock()
+	seen := make(map[discover.NodeID]time.Time, len(peers))
+	for _, peer := range peers {
+		since, ok := p.seen[peer.ID()]
+		if !ok {
+			since = now
+		}
+		seen[peer.ID()] = since
+	}
+	p.seen = seen // disconnected peers are forgotten
+
+	if maxPeers <= 0 || len(peers) < maxPeers {
+		return nil
+	}
+
+	var worst *p2p.Peer
+	var worstScore Score
+	for _, peer := range peers {
+		if p.protected[peer.ID()] || now.Sub(seen[peer.ID()]) < poolMinPeerAge {
+			continue
+		}
+		score := p.monitor.PeerScore(peer)
+		if score.Value >= poolDropScore {
+			continue
+		}
+		if worst == nil || score.Value < worstScore.Value {
+			worst, worstScore = peer, score
+		}
+	}
+	if worst == nil {
+		return nil
+	}
+
+	log.Info("Dropping the least useful peer", "peer", worst.ID(), "score", worstScore.Value, "rationale", worstScore.Rationale)
+	worst.Disconnect(p2p.DiscUselessPeer)
+	delete(p.seen, worst.ID())
+
+	return worst
+}
@@ -0,0 +1,57 @@
+package peers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScore(t *testing.T) {
+	monitor := NewMonitor(DefaultRequestTimeout)
+	mailServer := p2p.NewPeer(discover.NodeID{1}, "mail", []p2p.Cap{{Name: "shh", Version: 5}})
+	lightServer := p2p.NewPeer(discover.NodeID{2}, "les", []p2p.Cap{{Name: "les", Version: 2}})
+	monitor.SetMailServers([]discover.NodeID{mailServer.ID()})
+
+	score := monitor.PeerScore(mailServer)
+	require.Equal(t, MailServerWeight+unmeasuredPerformance, score.Value)
+	require.Equal(t, []string{"+1.00 mail server", "+0.50 performance is not measured"}, score.Rationale)
+
+	// slow peer is rated lower, than a peer without measurements
+	pp := monitor.addPeer(lightServer.ID(), "les", map[uint64]uint64{testRequestCode: testResponseCode}, newProtocolMeters("test"))
+	start := time.Now()
+	pp.sent(testRequestCode, start)
+	pp.received(testResponseCode, start.Add(3*time.Second))
+
+	score = monitor.PeerScore(lightServer)
+	require.Equal(t, LES2Weight+0.25, score.Value)
+	require.Equal(t, []string{"+0.50 les/2", "+0.25 les latency 3s, success rate 1.00"}, score.Rationale)
+}
+
+func TestPoolBalance(t *testing.T) {
+	monitor := NewMonitor(DefaultRequestTimeout)
+	pool := NewPool(monitor)
+	now := time.Now()
+	pool.clock = func() time.Time { return now }
+
+	useful := p2p.NewPeer(discover.NodeID{1}, "useful", []p2p.Cap{{Name: "les", Version: 2}})
+	useless := p2p.NewPeer(discover.NodeID{2}, "useless", []p2p.Cap{{Name: "les", Version: 1}})
+	static := p2p.NewPeer(discover.NodeID{3}, "static", nil)
+	pp := monitor.addPeer(useless.ID(), "les", map[uint64]uint64{testRequestCode: testResponseCode}, newProtocolMeters("test"))
+	pp.sent(testRequestCode, now)
+	pp.received(testResponseCode, now.Add(5*time.Second))
+	peers := []*p2p.Peer{useful, useless, static}
+
+	// peers are measured for a while, before they are dropped
+	require.Nil(t, pool.balance(peers, 3))
+	now = now.Add(poolMinPeerAge)
+
+	// peers are kept, while there are free slots
+	require.Nil(t, pool.balance(peers, 4))
+
+	pool.Protect(static.ID())
+	require.Equal(t, useless, pool.balance(peers, 3))
+	require.Nil(t, pool.balance([]*p2p.Peer{useful, static}, 2))
+}
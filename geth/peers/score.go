@@ -0,0 +1,82 @@
+package peers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// Weights of score components: advertised capabilities are added to measured performance,
+// which ranges from 0 (slow or unreliable peer) to 1 (perfect one).
+const (
+	MailServerWeight  = 1.0 // peer is a mail server, delivering expired Whisper messages on demand
+	LES2Weight        = 0.5 // peer serves LES/2 requests
+	PerformanceWeight = 1.0 // measured latency and success rate of requests
+
+	// unmeasuredPerformance is assumed for peers without measured requests (e.g. Whisper-only peers)
+	unmeasuredPerformance = 0.5
+)
+
+// Score rates usefulness of a peer: the higher, the better.
+type Score struct {
+	Value     float64  `json:"value"`
+	Rationale []string `json:"rationale"` // components of score, for debugging
+}
+
+func (s *Score) add(value float64, reason string, args ...interface{}) {
+	s.Value += value
+	s.Rationale = append(s.Rationale, fmt.Sprintf("%+.2f ", value)+fmt.Sprintf(reason, args...))
+}
+
+// SetMailServers sets nodes known to be mail servers, connections with which are preferred.
+func (m *Monitor) SetMailServers(ids []discover.NodeID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mailServers = make(map[discover.NodeID]bool, len(ids))
+	for _, id := range ids {
+		m.mailServers[id] = true
+	}
+}
+
+// PeerScore rates usefulness of a connected peer, by its capabilities and measured performance.
+func (m *Monitor) PeerScore(peer *p2p.Peer) Score {
+	var score Score
+
+	m.mu.RLock()
+	mailServer := m.mailServers[peer.ID()]
+	m.mu.RUnlock()
+	if mailServer {
+		score.add(MailServerWeight, "mail server")
+	}
+
+	for _, c := range peer.Caps() {
+		if c.Name == "les" && c.Version >= 2 {
+			score.add(LES2Weight, "%s", c)
+			break
+		}
+	}
+
+	stats, _ := m.Stats(peer.ID())
+	if len(stats) == 0 {
+		score.add(PerformanceWeight*unmeasuredPerformance, "performance is not measured")
+		return score
+	}
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// performance is averaged over measured sub-protocols
+	for _, name := range names {
+		s := stats[name]
+		score.add(PerformanceWeight*s.Score()/float64(len(names)), "%s latency %v, success rate %.2f",
+			name, s.Latency, s.SuccessRate())
+	}
+
+	return score
+}
@@ -0,0 +1,16 @@
+/*
+Package progress - reporting of progress of long-running operations.
+
+Long-running operations (chain sync, catch-up with mail server, keystore backup) report their progress with Tracker,
+which estimates percentage and time remaining, and sends them as EventOperationProgress signals,
+so that UI can render consistent progress bars:
+
+	tracker := progress.NewTracker(progress.OperationChainSync)
+	tracker.Update("headers", current-start, highest-start)
+	...
+	tracker.Done()
+
+Every run of operation has unique ID, signals are sent once percentage changes by at least 1,
+or phase of operation changes.
+*/
+package progress
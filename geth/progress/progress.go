@@ -0,0 +1,125 @@
+package progress
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// Operations, progress of which is reported
+const (
+	OperationChainSync         = "chain.sync"
+	OperationMailServerCatchUp = "mailserver.catchup"
+	OperationKeystoreExport    = "keystore.export"
+	OperationKeystoreImport    = "keystore.import"
+)
+
+// Phases of completed operations
+const (
+	PhaseDone   = "done"
+	PhaseFailed = "failed"
+)
+
+// minPercentStep is a change of percentage, progress is reported after (unless phase changes)
+const minPercentStep = 1.0
+
+// Event is sent with signal.EventOperationProgress.
+type Event struct {
+	ID        string  `json:"id"`        // unique for every run of operation
+	Operation string  `json:"operation"` // e.g. OperationChainSync
+	Phase     string  `json:"phase"`     // specific to operation, PhaseDone or PhaseFailed once it completes
+	Percent   float64 `json:"percent"`
+	ETA       int64   `json:"eta"` // estimated seconds until operation completes, -1 if unknown
+	Error     string  `json:"error,omitempty"`
+}
+
+// Tracker estimates and reports progress of a single run of operation.
+type Tracker struct {
+	mu           sync.Mutex
+	last         Event     // the last reported event
+	sent         bool      // at least one event has been reported
+	startedAt    time.Time // time of the first update, estimation of ETA starts from
+	startPercent float64   // percentage of the first update
+	now          func() time.Time
+}
+
+// NewTracker returns tracker of a new run of operation.
+func NewTracker(operation string) *Tracker {
+	return &Tracker{
+		last: Event{
+			ID:        uuid.New(),
+			Operation: operation,
+			ETA:       -1,
+		},
+		now: time.Now,
+	}
+}
+
+// ID returns ID of operation's run.
+func (t *Tracker) ID() string {
+	return t.last.ID
+}
+
+// Update reports that done units out of total have been processed in a given phase of operation.
+// ETA is extrapolated from the rate of progress since the first update.
+func (t *Tracker) Update(phase string, done, total uint64) {
+	percent := 0.0
+	if total > 0 {
+		percent = 100 * float64(done) / float64(total)
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.startedAt.IsZero() {
+		t.startedAt = now
+		t.startPercent = percent
+	}
+
+	eta := int64(-1)
+	if elapsed := now.Sub(t.startedAt).Seconds(); elapsed > 0 && percent > t.startPercent {
+		rate := (percent - t.startPercent) / elapsed // percents per second
+		eta = int64((100 - percent) / rate)
+	}
+
+	if t.sent && phase == t.last.Phase && math.Abs(percent-t.last.Percent) < minPercentStep {
+		return
+	}
+	t.send(Event{Phase: phase, Percent: percent, ETA: eta})
+}
+
+// Done reports that operation has completed.
+func (t *Tracker) Done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.send(Event{Phase: PhaseDone, Percent: 100, ETA: 0})
+}
+
+// Fail reports that operation has failed.
+func (t *Tracker) Fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.send(Event{Phase: PhaseFailed, Percent: t.last.Percent, ETA: -1, Error: err.Error()})
+}
+
+// send reports event, filling its ID and operation. Must be called with lock held.
+func (t *Tracker) send(event Event) {
+	event.ID = t.last.ID
+	event.Operation = t.last.Operation
+	t.last = event
+	t.sent = true
+
+	signal.Send(signal.Envelope{
+		Type:  signal.EventOperationProgress,
+		Event: event,
+	})
+}
@@ -0,0 +1,55 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	var events []Event
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event Event
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		require.Equal(t, signal.EventOperationProgress, envelope.Type)
+		events = append(events, envelope.Event)
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	now := time.Now()
+	tracker := NewTracker(OperationChainSync)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Update("headers", 100, 1000)
+	now = now.Add(10 * time.Second)
+	tracker.Update("headers", 105, 1000) // less than 1% is not reported
+	tracker.Update("headers", 200, 1000)
+	tracker.Update("state", 200, 1000) // phase change is reported
+	tracker.Done()
+
+	require.Len(t, events, 4)
+	for _, event := range events {
+		require.Equal(t, tracker.ID(), event.ID)
+		require.Equal(t, OperationChainSync, event.Operation)
+	}
+	require.Equal(t, Event{ID: tracker.ID(), Operation: OperationChainSync, Phase: "headers", Percent: 10, ETA: -1}, events[0])
+	// 10% in 10 seconds, 80% remains
+	require.Equal(t, float64(20), events[1].Percent)
+	require.Equal(t, int64(80), events[1].ETA)
+	require.Equal(t, "state", events[2].Phase)
+	require.Equal(t, Event{ID: tracker.ID(), Operation: OperationChainSync, Phase: PhaseDone, Percent: 100}, events[3])
+
+	failed := NewTracker(OperationMailServerCatchUp)
+	failed.Fail(errors.New("timeout"))
+	require.Len(t, events, 5)
+	require.NotEqual(t, tracker.ID(), failed.ID())
+	require.Equal(t, PhaseFailed, events[4].Phase)
+	require.Equal(t, "timeout", events[4].Error)
+}
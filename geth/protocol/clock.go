@@ -0,0 +1,42 @@
+package protocol
+
+import "sync"
+
+// Clock is a Lamport clock, which orders messages of chat consistently across its participants,
+// regardless of skew of their system clocks.
+type Clock struct {
+	mu    sync.Mutex
+	value uint64
+}
+
+// NewClock returns clock, starting at a given value (e.g. the greatest one of stored messages).
+func NewClock(value uint64) *Clock {
+	return &Clock{value: value}
+}
+
+// Tick advances clock for a message, which is to be sent, and returns its value.
+func (c *Clock) Tick() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.value++
+	return c.value
+}
+
+// Update advances clock past value of a received message.
+func (c *Clock) Update(received uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if received > c.value {
+		c.value = received
+	}
+}
+
+// Value returns current value of clock.
+func (c *Clock) Value() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.value
+}
@@ -0,0 +1,104 @@
+// Package protocol defines wire format of chat messages, which are sent over Whisper, so that
+// clients don't need to reimplement it: versioned envelope of content, bound to chat by its topic.
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Version is a version of message format, which is sent
+const Version = 1
+
+// ContentType describes, how content of message is to be interpreted.
+type ContentType string
+
+// Known content types
+const (
+	ContentTypeText           ContentType = "text/plain"
+	ContentTypeEmoji          ContentType = "emoji"           // single emoji, shown enlarged
+	ContentTypeCommand        ContentType = "command"         // command (e.g. sent transaction), content is its JSON
+	ContentTypeCommandRequest ContentType = "command-request" // request of command (e.g. of transaction), content is its JSON
+	ContentTypeSeen           ContentType = "seen"            // acknowledgement, content is id of seen message
+
+	ContentTypeMembershipUpdate ContentType = "group/membership-update" // update of members of group chat, content is its JSON
+)
+
+var contentTypes = map[ContentType]bool{
+	ContentTypeText:           true,
+	ContentTypeEmoji:          true,
+	ContentTypeCommand:        true,
+	ContentTypeCommandRequest: true,
+	ContentTypeSeen:           true,
+
+	ContentTypeMembershipUpdate: true,
+}
+
+// errors
+var (
+	ErrUnsupportedVersion = errors.New("unsupported version of message format")
+	ErrUnknownContentType = errors.New("unknown content type")
+	ErrEmptyChatID        = errors.New("chat id cannot be empty")
+	ErrEmptyContent       = errors.New("message content cannot be empty")
+)
+
+// Message is an envelope of chat message content, which is sent as payload of Whisper message.
+type Message struct {
+	Version     int         `json:"version"`
+	ContentType ContentType `json:"contentType"`
+	Content     string      `json:"content"`
+	ChatID      string      `json:"chatId"`
+	ReplyTo     string      `json:"replyTo,omitempty"` // id of message, this one is reply to
+	Clock       uint64      `json:"clock"`             // Lamport clock value of chat, messages are ordered by
+	Timestamp   int64       `json:"timestamp"`         // unix time, in milliseconds
+}
+
+// NewMessage returns message of the current version.
+func NewMessage(chatID string, contentType ContentType, content string) Message {
+	return Message{
+		Version:     Version,
+		ContentType: contentType,
+		Content:     content,
+		ChatID:      chatID,
+	}
+}
+
+// Validate checks that message can be interpreted by this version of protocol.
+func (m Message) Validate() error {
+	if m.Version < 1 || m.Version > Version {
+		return ErrUnsupportedVersion
+	}
+	if !contentTypes[m.ContentType] {
+		return ErrUnknownContentType
+	}
+	if m.ChatID == "" {
+		return ErrEmptyChatID
+	}
+	if m.Content == "" {
+		return ErrEmptyContent
+	}
+
+	return nil
+}
+
+// Marshal validates and encodes message.
+func Marshal(msg Message) ([]byte, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(msg)
+}
+
+// Unmarshal decodes and validates message.
+func Unmarshal(data []byte) (Message, error) {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return Message{}, err
+	}
+	if err := msg.Validate(); err != nil {
+		return Message{}, err
+	}
+
+	return msg, nil
+}
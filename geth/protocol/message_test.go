@@ -0,0 +1,60 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	msg := NewMessage("status", ContentTypeText, "hello")
+	msg.ReplyTo = "0x01"
+	msg.Clock = 10
+	msg.Timestamp = 1000
+
+	data, err := Marshal(msg)
+	require.NoError(t, err)
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, msg, decoded)
+
+	_, err = Unmarshal([]byte(`{"version":2,"contentType":"text/plain","content":"hello","chatId":"status"}`))
+	require.Equal(t, ErrUnsupportedVersion, err)
+	_, err = Unmarshal([]byte(`{"contentType":"text/plain","content":"hello","chatId":"status"}`))
+	require.Equal(t, ErrUnsupportedVersion, err)
+	_, err = Unmarshal([]byte(`not json`))
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		msg  Message
+		err  error
+	}{
+		{"valid", NewMessage("status", ContentTypeEmoji, ":)"), nil},
+		{"unknown content type", NewMessage("status", "image/png", "data"), ErrUnknownContentType},
+		{"empty chat id", NewMessage("", ContentTypeText, "hello"), ErrEmptyChatID},
+		{"empty content", NewMessage("status", ContentTypeText, ""), ErrEmptyContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, tc.msg.Validate())
+			_, err := Marshal(tc.msg)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestClock(t *testing.T) {
+	clock := NewClock(5)
+	require.Equal(t, uint64(6), clock.Tick())
+
+	// received messages move clock forward, but never back
+	clock.Update(10)
+	require.Equal(t, uint64(11), clock.Tick())
+	clock.Update(3)
+	require.Equal(t, uint64(12), clock.Tick())
+	require.Equal(t, uint64(12), clock.Value())
+}
@@ -0,0 +1,73 @@
+package protocol
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+)
+
+// ReceivedMessage is a message received from Whisper.
+type ReceivedMessage struct {
+	Message
+	ID   string        `json:"id"`   // hash of envelope
+	From hexutil.Bytes `json:"from"` // public key of sender, if message is signed
+}
+
+// Topic returns topic of chat, its messages are sent with by default.
+func Topic(chatID string) whisper.TopicType {
+	return whisper.BytesToTopic(crypto.Keccak256([]byte(chatID)))
+}
+
+// Wrap encodes message as payload of Whisper message, and seals it into envelope.
+// Keys, TTL and PoW are taken from params; topic of chat is used, unless params set one.
+func Wrap(params *whisper.MessageParams, msg Message) (*whisper.Envelope, error) {
+	payload, err := Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := *params
+	p.Payload = payload
+	if p.Topic == (whisper.TopicType{}) {
+		p.Topic = Topic(msg.ChatID)
+	}
+
+	sent, err := whisper.NewSentMessage(&p)
+	if err != nil {
+		return nil, err
+	}
+
+	return sent.Wrap(&p)
+}
+
+// Send wraps message and posts it to Whisper, returning posted envelope.
+func Send(whisperService *whisper.Whisper, params *whisper.MessageParams, msg Message) (*whisper.Envelope, error) {
+	envelope, err := Wrap(params, msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := whisperService.Send(envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope, nil
+}
+
+// Receive decodes message from payload of received Whisper message.
+func Receive(received *whisper.ReceivedMessage) (ReceivedMessage, error) {
+	msg, err := Unmarshal(received.Payload)
+	if err != nil {
+		return ReceivedMessage{}, err
+	}
+
+	var from []byte
+	if received.Src != nil {
+		from = crypto.FromECDSAPub(received.Src)
+	}
+
+	return ReceivedMessage{
+		Message: msg,
+		ID:      received.EnvelopeHash.Hex(),
+		From:    from,
+	}, nil
+}
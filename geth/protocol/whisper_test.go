@@ -0,0 +1,48 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendReceive(t *testing.T) {
+	senderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	recipientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	whisperService := whisper.New(nil)
+	msg := NewMessage("status", ContentTypeText, "hello")
+	msg.Clock = 1
+
+	envelope, err := Send(whisperService, &whisper.MessageParams{
+		TTL:      whisper.DefaultTTL,
+		WorkTime: 1,
+		Src:      senderKey,
+		Dst:      &recipientKey.PublicKey,
+	}, msg)
+	require.NoError(t, err)
+	require.Equal(t, Topic("status"), envelope.Topic)
+	require.Len(t, whisperService.Envelopes(), 1)
+
+	opened, err := envelope.OpenAsymmetric(recipientKey)
+	require.NoError(t, err)
+	require.True(t, opened.Validate())
+	opened.EnvelopeHash = envelope.Hash()
+
+	received, err := Receive(opened)
+	require.NoError(t, err)
+	require.Equal(t, msg, received.Message)
+	require.Equal(t, envelope.Hash().Hex(), received.ID)
+	require.Equal(t, crypto.FromECDSAPub(&senderKey.PublicKey), []byte(received.From))
+
+	// invalid messages are neither sent, nor received
+	_, err = Send(whisperService, &whisper.MessageParams{Dst: &recipientKey.PublicKey}, NewMessage("status", ContentTypeText, ""))
+	require.Equal(t, ErrEmptyContent, err)
+	opened.Payload = []byte(`{"version":1}`)
+	_, err = Receive(opened)
+	require.Equal(t, ErrUnknownContentType, err)
+}
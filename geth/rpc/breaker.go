@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/geth/backoff"
+)
+
+// upstreamBackoff is a jittered interval between retries of upstream call
+var upstreamBackoff = backoff.Backoff{
+	Base:   500 * time.Millisecond,
+	Max:    10 * time.Second,
+	Jitter: 0.5,
+}
+
+// errors
+var (
+	ErrUpstreamUnavailable = errors.New("upstream is unavailable, circuit breaker is open")
+)
+
+// UpstreamDegradedEvent is a signal sent, when circuit breaker opens after consecutive failures of upstream calls.
+type UpstreamDegradedEvent struct {
+	Failures int    `json:"failures"`
+	Error    string `json:"error"`
+	RetryIn  int    `json:"retryIn"` // seconds, until a probe call is let through
+}
+
+// circuitBreaker fails upstream calls immediately after a number of consecutive connectivity failures,
+// so that callers don't wait for timeouts, while upstream is down. Once cooldown passes,
+// a single probe call is let through: breaker closes, if it succeeds, and opens again otherwise.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int       // consecutive failures
+	openedAt  time.Time // zero, if breaker is closed
+	probing   bool      // probe call is in flight
+	now       func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns false, if call should fail immediately.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// success closes breaker.
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.openedAt = time.Time{}
+	b.probing = false
+}
+
+// failure counts connectivity failure, returning true, if breaker has been opened by it
+// (failed probe re-opens breaker, but is not reported, as breaker has been open already).
+func (b *circuitBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.probing {
+		b.probing = false
+		b.openedAt = b.now()
+		return false
+	}
+	if b.openedAt.IsZero() && b.failures >= b.threshold {
+		b.openedAt = b.now()
+		return true
+	}
+
+	return false
+}
+
+// cancel releases probe call, which has been cancelled by caller, without counting its result.
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
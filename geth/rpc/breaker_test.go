@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	require.True(t, b.allow())
+	require.False(t, b.failure())
+	require.True(t, b.allow())
+	require.True(t, b.failure(), "breaker opens after threshold is reached")
+	require.False(t, b.allow())
+
+	// single probe is let through after cooldown, failed probe re-opens breaker silently
+	now = now.Add(time.Minute)
+	require.True(t, b.allow())
+	require.False(t, b.allow())
+	require.False(t, b.failure())
+	require.False(t, b.allow())
+
+	// cancelled probe is not counted
+	now = now.Add(time.Minute)
+	require.True(t, b.allow())
+	b.cancel()
+	require.True(t, b.allow())
+
+	// successful probe closes breaker
+	b.success()
+	require.True(t, b.allow())
+	require.True(t, b.allow())
+}
+
+func TestCallUpstreamRetriesAndBreaker(t *testing.T) {
+	server, setDown := newTestUpstream("0x1")
+	defer server.Close()
+
+	var signals []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, signal.EventUpstreamDegraded) {
+			signals = append(signals, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{URL: server.URL})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c := &Client{
+		upstream:    pool,
+		callTimeout: time.Second,
+		retries:     1,
+		breaker:     newCircuitBreaker(2, time.Minute),
+	}
+
+	var result string
+	require.NoError(t, c.callUpstream(context.Background(), &result, "eth_blockNumber"))
+	require.Equal(t, "0x1", result)
+
+	// both attempts fail, opening breaker
+	setDown(true)
+	require.Error(t, c.callUpstream(context.Background(), &result, "eth_blockNumber"))
+	require.Len(t, signals, 1)
+
+	// further calls fail immediately, even if upstream is back
+	setDown(false)
+	require.Equal(t, ErrUpstreamUnavailable, c.callUpstream(context.Background(), &result, "eth_blockNumber"))
+	require.Len(t, signals, 1)
+
+	// probe call closes breaker after cooldown
+	c.breaker.cooldown = 0
+	require.NoError(t, c.callUpstream(context.Background(), &result, "eth_blockNumber"))
+	require.True(t, c.breaker.allow())
+}
@@ -0,0 +1,247 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxCacheEntries limits number of responses kept in cache
+	maxCacheEntries = 1024
+
+	// finalizedBlockDepth is a number of blocks on top of a block, after which it is not expected to be reorganized
+	finalizedBlockDepth = 12
+)
+
+// errors
+var (
+	ErrInvalidCacheTTL = errors.New("invalid cache TTL, expected \"method=seconds\"")
+)
+
+// defaultCacheTTLs lists idempotent methods, which responses are cached by default.
+var defaultCacheTTLs = map[string]time.Duration{
+	"eth_chainId":          24 * time.Hour,
+	"net_version":          24 * time.Hour,
+	"eth_getBlockByNumber": 10 * time.Minute, // finalized blocks only
+	"eth_call":             15 * time.Second,
+}
+
+// cacheEntry is a cached result of a call.
+type cacheEntry struct {
+	result        json.RawMessage
+	expires       time.Time
+	headDependent bool // result may change with a new head, e.g. eth_call on the latest block
+}
+
+// responseCache keeps results of idempotent calls for a configured TTL,
+// dropping those, which depend on the latest block, once a new head is notified.
+type responseCache struct {
+	mu      sync.Mutex
+	ttls    map[string]time.Duration
+	entries map[string]cacheEntry
+	head    uint64 // the latest known block number
+	now     func() time.Time
+}
+
+// newResponseCache returns cache with default TTLs overridden by rules "method=seconds".
+// TTL of 0 disables caching of a method.
+func newResponseCache(rules []string) (*responseCache, error) {
+	c := &responseCache{
+		ttls:    make(map[string]time.Duration, len(defaultCacheTTLs)),
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+
+	for method, ttl := range defaultCacheTTLs {
+		c.ttls[method] = ttl
+	}
+
+	for _, rule := range rules {
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, ErrInvalidCacheTTL
+		}
+
+		seconds, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || seconds < 0 {
+			return nil, ErrInvalidCacheTTL
+		}
+		c.ttls[strings.TrimSpace(parts[0])] = time.Duration(seconds) * time.Second
+	}
+
+	return c, nil
+}
+
+// handles returns true, if results of method are cached.
+func (c *responseCache) handles(method string) bool {
+	return c.ttls[method] > 0
+}
+
+// get returns cached result of a call, if any.
+func (c *responseCache) get(method string, args []interface{}) (json.RawMessage, bool) {
+	key, ok := c.key(method, args)
+	if !ok {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.result, true
+}
+
+// put caches result of a call, if method is cacheable with given args.
+func (c *responseCache) put(method string, args []interface{}, result json.RawMessage) {
+	// empty results (e.g. unknown block) are expected to change
+	if len(result) == 0 || string(result) == "null" {
+		return
+	}
+
+	key, ok := c.key(method, args)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	headDependent, ok := c.cacheable(method, args)
+	if !ok {
+		return
+	}
+
+	if len(c.entries) >= maxCacheEntries {
+		c.evict()
+	}
+	c.entries[key] = cacheEntry{
+		result:        result,
+		expires:       c.now().Add(c.ttls[method]),
+		headDependent: headDependent,
+	}
+}
+
+// watchHeads drops results depending on the latest block, as new heads are notified by subscription,
+// until it's ended.
+func (c *responseCache) watchHeads(sub *gethrpc.ClientSubscription, heads <-chan json.RawMessage) {
+	for {
+		select {
+		case head := <-heads:
+			var header struct {
+				Number *hexutil.Uint64 `json:"number"`
+			}
+			if err := json.Unmarshal(head, &header); err == nil && header.Number != nil {
+				c.newHead(uint64(*header.Number))
+			}
+		case <-sub.Err():
+			return
+		}
+	}
+}
+
+// newHead drops results depending on the latest block, if head has been changed.
+func (c *responseCache) newHead(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if number <= c.head {
+		return
+	}
+	c.head = number
+
+	for key, entry := range c.entries {
+		if entry.headDependent {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// key returns cache key of a call to a method with TTL configured.
+func (c *responseCache) key(method string, args []interface{}) (string, bool) {
+	if c.ttls[method] <= 0 {
+		return "", false
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+
+	return method + string(data), true
+}
+
+// cacheable checks whether result of a call may be cached, and whether it depends on the latest block.
+// Must be called with lock held.
+func (c *responseCache) cacheable(method string, args []interface{}) (headDependent bool, ok bool) {
+	switch method {
+	case "eth_getBlockByNumber":
+		// only blocks, which are not expected to be reorganized
+		if len(args) == 0 || c.head == 0 {
+			return false, false
+		}
+		number, ok := blockNumberArg(args[0])
+		return false, ok && number+finalizedBlockDepth <= c.head
+	case "eth_call":
+		if len(args) < 2 {
+			return true, true
+		}
+		// pending state changes with every transaction, not with new heads only
+		if tag, _ := args[1].(string); tag == "pending" {
+			return false, false
+		}
+		_, ok := blockNumberArg(args[1])
+		return !ok, true
+	}
+
+	return false, true
+}
+
+// evict drops expired entries, or an arbitrary one, if none is expired.
+// Must be called with lock held.
+func (c *responseCache) evict() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+
+	if len(c.entries) < maxCacheEntries {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
+
+// blockNumberArg parses explicit block number argument,
+// returning false for tags like "latest" or "pending".
+func blockNumberArg(arg interface{}) (uint64, bool) {
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return 0, false
+	}
+
+	var number hexutil.Uint64
+	if err := json.Unmarshal(data, &number); err != nil {
+		return 0, false
+	}
+
+	return uint64(number), true
+}
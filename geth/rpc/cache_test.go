@@ -0,0 +1,110 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCacheTTL(t *testing.T) {
+	cache, err := newResponseCache([]string{"eth_gasPrice=10", "eth_call=0"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	require.True(t, cache.handles("eth_gasPrice"))
+	require.False(t, cache.handles("eth_call"))
+	require.False(t, cache.handles("eth_sendRawTransaction"))
+
+	cache.put("eth_gasPrice", nil, json.RawMessage(`"0x1"`))
+	result, ok := cache.get("eth_gasPrice", nil)
+	require.True(t, ok)
+	require.Equal(t, `"0x1"`, string(result))
+
+	// arguments are part of the key
+	_, ok = cache.get("eth_gasPrice", []interface{}{"0x2"})
+	require.False(t, ok)
+
+	now = now.Add(11 * time.Second)
+	_, ok = cache.get("eth_gasPrice", nil)
+	require.False(t, ok)
+
+	_, err = newResponseCache([]string{"eth_call"})
+	require.Equal(t, ErrInvalidCacheTTL, err)
+	_, err = newResponseCache([]string{"eth_call=-1"})
+	require.Equal(t, ErrInvalidCacheTTL, err)
+}
+
+func TestResponseCacheNewHead(t *testing.T) {
+	cache, err := newResponseCache(nil)
+	require.NoError(t, err)
+
+	callLatest := []interface{}{map[string]string{"to": "0x01"}, "latest"}
+	callAtBlock := []interface{}{map[string]string{"to": "0x01"}, "0x10"}
+	cache.put("eth_call", callLatest, json.RawMessage(`"0x1"`))
+	cache.put("eth_call", callAtBlock, json.RawMessage(`"0x2"`))
+
+	// head is unknown, so no block is considered finalized
+	cache.put("eth_getBlockByNumber", []interface{}{"0x1", false}, json.RawMessage(`{}`))
+	_, ok := cache.get("eth_getBlockByNumber", []interface{}{"0x1", false})
+	require.False(t, ok)
+
+	cache.newHead(0x20)
+	require.EqualValues(t, 0x20, cache.head)
+
+	// call on the latest block is dropped, once a new head is seen
+	_, ok = cache.get("eth_call", callLatest)
+	require.False(t, ok)
+	_, ok = cache.get("eth_call", callAtBlock)
+	require.True(t, ok)
+
+	cache.put("eth_getBlockByNumber", []interface{}{"0x1", false}, json.RawMessage(`{}`))
+	_, ok = cache.get("eth_getBlockByNumber", []interface{}{"0x1", false})
+	require.True(t, ok)
+
+	// recent and pending blocks are not cached
+	for _, number := range []string{"0x1f", "latest", "pending"} {
+		cache.put("eth_getBlockByNumber", []interface{}{number, false}, json.RawMessage(`{}`))
+		_, ok = cache.get("eth_getBlockByNumber", []interface{}{number, false})
+		require.False(t, ok, number)
+	}
+
+	// calls on pending state are not cached
+	callPending := []interface{}{map[string]string{"to": "0x01"}, "pending"}
+	cache.put("eth_call", callPending, json.RawMessage(`"0x3"`))
+	_, ok = cache.get("eth_call", callPending)
+	require.False(t, ok)
+
+	// empty results are not cached
+	cache.put("eth_chainId", nil, json.RawMessage(`null`))
+	_, ok = cache.get("eth_chainId", nil)
+	require.False(t, ok)
+}
+
+func TestResponseCacheWatchesHeads(t *testing.T) {
+	client := newTestSubscriptionsClient(t, &TestEthService{})
+	var err error
+	client.cache, err = newResponseCache(nil)
+	require.NoError(t, err)
+
+	callLatest := []interface{}{map[string]string{"to": "0x01"}, "latest"}
+	client.cache.put("eth_call", callLatest, json.RawMessage(`"0x1"`))
+	client.watchHeads()
+	defer client.Close()
+
+	// call on the latest block is dropped, once a new head is notified
+	deadline := time.After(time.Second)
+	for {
+		if _, ok := client.cache.get("eth_call", callLatest); !ok {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("cached result is not dropped on new head")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
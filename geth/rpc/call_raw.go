@@ -3,14 +3,29 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net"
+	"time"
 
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/status-im/status-go/geth/log"
 )
 
+const jsonrpcVersion = "2.0"
+
+// Kinds of errors of calls, performed with CallRaw
+const (
+	CallErrorInvalidRequest = "invalid_request" // request can't be parsed
+	CallErrorTransport      = "transport"       // server is not reachable or misbehaves
+	CallErrorTimeout        = "timeout"         // call timed out
+	CallErrorCancelled      = "cancelled"       // context of call is cancelled
+	CallErrorRPC            = "rpc"             // server responded with JSON-RPC error
+)
+
+// Origins of responses to calls
 const (
-	jsonrpcVersion        = "2.0"
-	errInvalidMessageCode = -32700 // from go-ethereum/rpc/errors.go
+	CallOriginLocal    = "local"
+	CallOriginUpstream = "upstream"
 )
 
 // for JSON-RPC responses obtained via CallRaw(), we have no way
@@ -21,10 +36,47 @@ const (
 // thus, we will use zero ID as a workaround of this limitation
 var defaultMsgID = json.RawMessage(`0`)
 
+// CallError describes failed call, performed with CallRaw. Kind and origin of error (and ID of
+// request) are also reported in "data" field of JSON-RPC error response.
+type CallError struct {
+	Kind      string `json:"kind"`
+	Origin    string `json:"origin,omitempty"` // empty, if request can't be parsed
+	RequestID string `json:"requestId,omitempty"`
+	Method    string `json:"-"`
+	Code      int    `json:"-"`
+	Err       error  `json:"-"`
+}
+
+// Error implements error interface.
+func (e *CallError) Error() string {
+	if e.Origin == "" {
+		return fmt.Sprintf("%s error: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %s %s error: %v", e.Method, e.Origin, e.Kind, e.Err)
+}
+
+// BatchError reports failed calls of batch request, other calls of which may have succeeded.
+type BatchError struct {
+	Errors map[int]*CallError // keyed by index of call in batch
+	Total  int                // number of calls in batch
+}
+
+// Error implements error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d calls of batch failed", len(e.Errors), e.Total)
+}
+
 // CallRaw performs a JSON-RPC call with already crafted JSON-RPC body. It
-// returns string in JSON format with response (successul or error).
-func (c *Client) CallRaw(body string) string {
-	ctx := context.Background()
+// returns string in JSON format with response (successul or error), which
+// is to be passed to JavaScript as is.
+//
+// Failures are also returned as errors: *CallError for a single call, or
+// *BatchError, if some of calls of batch request have failed.
+//
+// Calls are traced with ID of request carried by context, a new one is assigned,
+// if there is none (see WithRequestID).
+func (c *Client) CallRaw(ctx context.Context, body string) (string, error) {
+	ctx, _ = WithRequestID(ctx)
 	return c.callRawContext(ctx, json.RawMessage(body))
 }
 
@@ -37,6 +89,12 @@ type jsonrpcMessage struct {
 	Params  json.RawMessage `json:"params,omitempty"`
 	Error   *jsonError      `json:"error,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
+	Stale   *staleInfo      `json:"stale,omitempty"`
+}
+
+// staleInfo labels the last known result, served while upstream is unreachable.
+type staleInfo struct {
+	UpdatedAt int64 `json:"updatedAt"` // unix time, when result has been obtained
 }
 
 // jsonError represents Error message for JSON-RPC responses.
@@ -56,12 +114,17 @@ type jsonError struct {
 // This is waste of CPU and memory and should be avoided if possible,
 // either by changing exported API (provide only Call, not CallRaw) or
 // refactoring go-ethereum's client to allow using raw JSON directly.
-func (c *Client) callRawContext(ctx context.Context, body json.RawMessage) string {
+func (c *Client) callRawContext(ctx context.Context, body json.RawMessage) (string, error) {
 	if isBatch(body) {
 		return c.callBatchMethods(ctx, body)
 	}
 
-	return c.callSingleMethod(ctx, body)
+	response, callErr := c.callSingleMethod(ctx, body)
+	if callErr != nil {
+		return response, callErr
+	}
+
+	return response, nil
 }
 
 // callBatchMethods handles batched JSON-RPC requests, calling each of
@@ -71,57 +134,129 @@ func (c *Client) callRawContext(ctx context.Context, body json.RawMessage) strin
 //
 // We can't use gethtrpc.BatchCall here, because each call should go through
 // our routing logic and router to corresponding destination.
-func (c *Client) callBatchMethods(ctx context.Context, msgs json.RawMessage) string {
+func (c *Client) callBatchMethods(ctx context.Context, msgs json.RawMessage) (string, error) {
 	var requests []json.RawMessage
 
 	err := json.Unmarshal(msgs, &requests)
 	if err != nil {
-		return newErrorResponse(errInvalidMessageCode, err, defaultMsgID)
+		callErr := newInvalidRequestError(err)
+		return newCallErrorResponse(callErr, defaultMsgID), callErr
 	}
 
 	// run all methods sequentially, this seems to be main
 	// objective to use batched requests.
 	// See: https://github.com/ethereum/wiki/wiki/JavaScript-API#batch-requests
+	batchErr := &BatchError{Errors: make(map[int]*CallError), Total: len(requests)}
 	responses := make([]json.RawMessage, len(requests))
 	for i := range requests {
-		resp := c.callSingleMethod(ctx, requests[i])
+		resp, callErr := c.callSingleMethod(ctx, requests[i])
+		if callErr != nil {
+			batchErr.Errors[i] = callErr
+		}
 		responses[i] = json.RawMessage(resp)
 	}
 
 	data, err := json.Marshal(responses)
 	if err != nil {
 		log.Error("Failed to marshal batch responses:", err)
-		return newErrorResponse(errInvalidMessageCode, err, defaultMsgID)
+		callErr := newInvalidRequestError(err)
+		return newCallErrorResponse(callErr, defaultMsgID), callErr
 	}
 
-	return string(data)
+	if len(batchErr.Errors) > 0 {
+		return string(data), batchErr
+	}
+
+	return string(data), nil
 }
 
 // callSingleMethod executes single JSON-RPC message and constructs proper response.
-func (c *Client) callSingleMethod(ctx context.Context, msg json.RawMessage) string {
+func (c *Client) callSingleMethod(ctx context.Context, msg json.RawMessage) (string, *CallError) {
 	// unmarshal JSON body into json-rpc request
 	method, params, id, err := methodAndParamsFromBody(msg)
 	if err != nil {
-		return newErrorResponse(errInvalidMessageCode, err, id)
+		callErr := newInvalidRequestError(err)
+		return newCallErrorResponse(callErr, id), callErr
 	}
 
 	// route and execute
 	var result json.RawMessage
+	start := time.Now()
 	err = c.CallContext(ctx, &result, method, params...)
+	traceCall(ctx, method, c.origin(method), start, err)
+
+	// keep the last known state of accounts and chain,
+	// serving it, if upstream is unreachable
+	if c.state != nil && c.state.handles(method) && c.router.routeRemote(method) {
+		if err == nil {
+			c.state.put(method, params, result)
+		} else if isConnectivityError(ctx, err) {
+			if entry, ok := c.state.get(method, params); ok {
+				return newStaleResponse(entry, id), nil
+			}
+		}
+	}
 
 	// as we have to return original JSON, we have to
 	// analyze returned error and reconstruct original
 	// JSON error response.
 	if err != nil && err != gethrpc.ErrNoResult {
-		if er, ok := err.(gethrpc.Error); ok {
-			return newErrorResponse(er.ErrorCode(), err, id)
+		callErr := c.newCallError(ctx, method, err)
+		return newCallErrorResponse(callErr, id), callErr
+	}
+
+	// finally, marshal answer
+	return newSuccessResponse(result, id), nil
+}
+
+// newCallError classifies error of a call.
+func (c *Client) newCallError(ctx context.Context, method string, err error) *CallError {
+	callErr := &CallError{
+		Kind:      CallErrorTransport,
+		Origin:    c.origin(method),
+		RequestID: RequestIDFromContext(ctx),
+		Method:    method,
+		Code:      errorCode(ctx, c.origin(method), err),
+		Err:       err,
+	}
+
+	if _, ok := err.(gethrpc.Error); ok {
+		callErr.Kind = CallErrorRPC
+		return callErr
+	}
+
+	switch {
+	case ctx.Err() == context.Canceled:
+		callErr.Kind = CallErrorCancelled
+	case ctx.Err() == context.DeadlineExceeded || err == context.DeadlineExceeded:
+		callErr.Kind = CallErrorTimeout
+	default:
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			callErr.Kind = CallErrorTimeout
 		}
+	}
+
+	return callErr
+}
 
-		return newErrorResponse(errInvalidMessageCode, err, id)
+// origin returns where call of method goes to.
+func (c *Client) origin(method string) string {
+	if _, ok := c.handler(method); ok {
+		return CallOriginLocal
+	}
+	if c.router != nil && c.router.routeRemote(method) {
+		return CallOriginUpstream
 	}
 
-	// finally, marshal answer
-	return newSuccessResponse(result, id)
+	return CallOriginLocal
+}
+
+func newInvalidRequestError(err error) *CallError {
+	return &CallError{
+		Kind: CallErrorInvalidRequest,
+		Code: ErrCodeParse,
+		Err:  err,
+	}
 }
 
 // methodAndParamsFromBody extracts Method and Params of
@@ -173,7 +308,24 @@ func newSuccessResponse(result json.RawMessage, id json.RawMessage) string {
 	return string(data)
 }
 
-func newErrorResponse(code int, err error, id json.RawMessage) string {
+// newStaleResponse returns the last known result of a call, labeled with time it has been obtained at.
+func newStaleResponse(entry chainStateEntry, id json.RawMessage) string {
+	if id == nil {
+		id = defaultMsgID
+	}
+
+	msg := &jsonrpcMessage{
+		ID:      id,
+		Version: jsonrpcVersion,
+		Result:  entry.Result,
+		Stale:   &staleInfo{UpdatedAt: entry.UpdatedAt},
+	}
+	data, _ := json.Marshal(msg)
+	return string(data)
+}
+
+// newCallErrorResponse returns error response, with kind and origin of error as its data.
+func newCallErrorResponse(callErr *CallError, id json.RawMessage) string {
 	if id == nil {
 		id = defaultMsgID
 	}
@@ -182,8 +334,9 @@ func newErrorResponse(code int, err error, id json.RawMessage) string {
 		Version: jsonrpcVersion,
 		ID:      id,
 		Error: &jsonError{
-			Code:    code,
-			Message: err.Error(),
+			Code:    callErr.Code,
+			Message: callErr.Err.Error(),
+			Data:    callErr,
 		},
 	}
 
@@ -1,10 +1,13 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/status-im/status-go/geth/params"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,10 +33,16 @@ func TestNewSuccessResponse(t *testing.T) {
 	}
 }
 
-func TestNewErrorResponse(t *testing.T) {
-	got := newErrorResponse(-32601, errors.New("Method not found"), json.RawMessage(`42`))
+func TestNewCallErrorResponse(t *testing.T) {
+	got := newCallErrorResponse(&CallError{
+		Kind:   CallErrorRPC,
+		Origin: CallOriginUpstream,
+		Method: "eth_foo",
+		Code:   -32601,
+		Err:    errors.New("Method not found"),
+	}, json.RawMessage(`42`))
 
-	expected := `{"jsonrpc":"2.0","id":42,"error":{"code":-32601,"message":"Method not found"}}`
+	expected := `{"jsonrpc":"2.0","id":42,"error":{"code":-32601,"message":"Method not found","data":{"kind":"rpc","origin":"upstream"}}}`
 	require.Equal(t, expected, got)
 }
 
@@ -146,3 +155,73 @@ func TestIsBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestCallRawErrors(t *testing.T) {
+	server, setDown := newTestUpstream("0x10")
+	defer server.Close()
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{URL: server.URL})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	router, err := newRouter(true, nil)
+	require.NoError(t, err)
+
+	c := &Client{
+		upstream: pool,
+		router:   router,
+		handlers: make(map[string]Handler),
+	}
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"eth_gasPrice","params":[]}`
+	response, err := c.CallRaw(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`, response)
+
+	// errors are reported both in response and as error, along with ID of request
+	ctx, requestID := WithRequestID(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	response, err = c.CallRaw(ctx, request)
+	require.Equal(t, CallErrorCancelled, err.(*CallError).Kind)
+	require.Equal(t, CallOriginUpstream, err.(*CallError).Origin)
+	require.Equal(t, requestID, err.(*CallError).RequestID)
+	require.Equal(t, ErrCodeResourceUnavailable, err.(*CallError).Code)
+	require.Contains(t, response, `"data":{"kind":"cancelled","origin":"upstream","requestId":"`+requestID+`"}`)
+
+	// request ID is assigned, unless context carries it
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = c.CallRaw(ctx, request)
+	require.NotEmpty(t, err.(*CallError).RequestID)
+	require.NotEqual(t, requestID, err.(*CallError).RequestID)
+
+	c.callTimeout = time.Nanosecond
+	_, err = c.CallRaw(context.Background(), request)
+	require.Equal(t, CallErrorTimeout, err.(*CallError).Kind)
+	c.callTimeout = 0
+
+	setDown(true)
+	_, err = c.CallRaw(context.Background(), request)
+	require.Equal(t, CallErrorTransport, err.(*CallError).Kind)
+	require.Equal(t, ErrCodeResourceUnavailable, err.(*CallError).Code)
+	setDown(false)
+
+	_, err = c.CallRaw(context.Background(), `{"jsonrpc":"2.0","id":1,"method":`)
+	require.Equal(t, CallErrorInvalidRequest, err.(*CallError).Kind)
+	require.Equal(t, ErrCodeParse, err.(*CallError).Code)
+
+	// failed calls of batch are reported, others succeed
+	response, err = c.CallRaw(context.Background(), `[`+request+`, 1]`)
+	batchErr, ok := err.(*BatchError)
+	require.True(t, ok)
+	require.Equal(t, 2, batchErr.Total)
+	require.Len(t, batchErr.Errors, 1)
+	require.Equal(t, CallErrorInvalidRequest, batchErr.Errors[1].Kind)
+
+	var responses []jsonrpcMessage
+	require.NoError(t, json.Unmarshal([]byte(response), &responses))
+	require.Len(t, responses, 2)
+	require.Equal(t, json.RawMessage(`"0x10"`), responses[0].Result)
+	require.NotNil(t, responses[1].Error)
+}
@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/geth/log"
+)
+
+// chainStateFile is a name of file in node's instance dir, where the last known chain state is kept
+const chainStateFile = "chainstate.json"
+
+// chainStateMethods lists methods, which last known results are served, when upstream is unreachable
+var chainStateMethods = map[string]bool{
+	"eth_getBalance":          true,
+	"eth_getTransactionCount": true,
+	"eth_gasPrice":            true,
+	"eth_blockNumber":         true,
+}
+
+// chainStateEntry is the last known result of a call, along with time it has been obtained at.
+type chainStateEntry struct {
+	Result    json.RawMessage `json:"result"`
+	UpdatedAt int64           `json:"updatedAt"` // unix time
+}
+
+// chainState keeps the last known balances, nonces, gas price and block number,
+// so that wallet could render them (labeled as stale), while device is offline.
+// State is persisted, in order to survive restart of the node without connectivity.
+type chainState struct {
+	mu      sync.Mutex
+	path    string // empty, if state is not persisted
+	entries map[string]chainStateEntry
+	now     func() time.Time
+}
+
+// newChainState loads state persisted at path (if any).
+func newChainState(path string) *chainState {
+	s := &chainState{
+		path:    path,
+		entries: make(map[string]chainStateEntry),
+		now:     time.Now,
+	}
+
+	if path == "" {
+		return s
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("Failed to read chain state", "path", path, "error", err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		log.Warn("Failed to parse chain state", "path", path, "error", err)
+		s.entries = make(map[string]chainStateEntry)
+	}
+
+	return s
+}
+
+// handles returns true, if last known results of method are kept.
+func (s *chainState) handles(method string) bool {
+	return chainStateMethods[method]
+}
+
+// get returns the last known result of a call.
+func (s *chainState) get(method string, args []interface{}) (chainStateEntry, bool) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return chainStateEntry{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[method+string(data)]
+	return entry, ok
+}
+
+// put updates the last known result of a call, persisting state, if result has been changed.
+func (s *chainState) put(method string, args []interface{}, result json.RawMessage) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return
+	}
+	key := method + string(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := string(s.entries[key].Result) != string(result)
+	s.entries[key] = chainStateEntry{
+		Result:    result,
+		UpdatedAt: s.now().Unix(),
+	}
+
+	if changed && s.path != "" {
+		if err := s.save(); err != nil {
+			log.Warn("Failed to save chain state", "path", s.path, "error", err)
+		}
+	}
+}
+
+// save writes state to a temporary file, replacing persisted one with it.
+// Must be called with lock held.
+func (s *chainState) save() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
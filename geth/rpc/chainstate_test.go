@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainStatePersisted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chainstate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	path := filepath.Join(dir, chainStateFile)
+	state := newChainState(path)
+	state.now = func() time.Time { return time.Unix(100, 0) }
+
+	args := []interface{}{"0xaddress", "latest"}
+	state.put("eth_getBalance", args, json.RawMessage(`"0x1"`))
+
+	entry, ok := newChainState(path).get("eth_getBalance", args)
+	require.True(t, ok)
+	require.Equal(t, `"0x1"`, string(entry.Result))
+	require.EqualValues(t, 100, entry.UpdatedAt)
+
+	_, ok = newChainState(path).get("eth_getBalance", []interface{}{"0xother", "latest"})
+	require.False(t, ok)
+}
+
+func TestCallRawServesStaleState(t *testing.T) {
+	server, setDown := newTestUpstream("0x10")
+	defer server.Close()
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{URL: server.URL})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	router, err := newRouter(true, nil)
+	require.NoError(t, err)
+
+	c := &Client{
+		upstream: pool,
+		router:   router,
+		state:    newChainState(""),
+		handlers: make(map[string]Handler),
+	}
+	c.state.now = func() time.Time { return time.Unix(100, 0) }
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"eth_gasPrice","params":[]}`
+	response, err := c.CallRaw(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x10"}`, response)
+
+	setDown(true)
+	response, err = c.CallRaw(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x10","stale":{"updatedAt":100}}`, response)
+
+	// there is no last known result of the other call
+	response, err = c.CallRaw(context.Background(), `{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber","params":[]}`)
+	require.Error(t, err)
+	var msg jsonrpcMessage
+	require.NoError(t, json.Unmarshal([]byte(response), &msg))
+	require.NotNil(t, msg.Error)
+	require.Nil(t, msg.Stale)
+}
@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/status-im/status-go/geth/backoff"
+	"github.com/status-im/status-go/geth/log"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
 
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 )
@@ -24,10 +29,21 @@ type Client struct {
 	upstreamEnabled bool
 	upstreamURL     string
 
-	local    *gethrpc.Client
-	upstream *gethrpc.Client
+	local      *gethrpc.Client
+	upstream   *upstreamPool
+	upstreamWS *gethrpc.Client // nil, if upstream WebSocket endpoint is not configured
 
 	router *router
+	cache  *responseCache              // nil, if caching is disabled
+	heads  *gethrpc.ClientSubscription // new heads, cache is invalidated on; nil, if not subscribed
+	state  *chainState                 // nil, if last known chain state is not kept
+
+	callTimeout time.Duration   // timeout of a single attempt of a call
+	retries     int             // number of retries of upstream calls on connectivity errors
+	breaker     *circuitBreaker // nil, if circuit breaker is disabled
+	offline     *offlineMode    // nil, if offline mode is disabled
+
+	subscriptions *subscriptions
 
 	handlersMx sync.RWMutex       // mx guards handlers
 	handlers   map[string]Handler // locally registered handlers
@@ -40,7 +56,13 @@ type Client struct {
 // reconnect to the server if connection is lost.
 func NewClient(node *node.Node, upstream params.UpstreamRPCConfig) (*Client, error) {
 	c := &Client{
-		handlers: make(map[string]Handler),
+		handlers:      make(map[string]Handler),
+		subscriptions: newSubscriptions(),
+		callTimeout:   time.Duration(upstream.CallTimeout) * time.Second,
+		retries:       upstream.Retries,
+	}
+	if c.callTimeout <= 0 {
+		c.callTimeout = params.UpstreamCallTimeout * time.Second
 	}
 
 	var err error
@@ -49,21 +71,110 @@ func NewClient(node *node.Node, upstream params.UpstreamRPCConfig) (*Client, err
 		return nil, fmt.Errorf("attach to local node: %s", err)
 	}
 
-	if upstream.Enabled {
-		c.upstreamEnabled = upstream.Enabled
+	c.router, err = newRouter(upstream.Enabled, upstream.MethodRouting)
+	if err != nil {
+		return nil, err
+	}
+
+	if upstream.CacheEnabled {
+		c.cache, err = newResponseCache(upstream.CacheTTLs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.router.usesUpstream() {
+		c.upstreamEnabled = true
 		c.upstreamURL = upstream.URL
 
-		c.upstream, err = gethrpc.Dial(c.upstreamURL)
+		c.upstream, err = newUpstreamPool(upstream)
 		if err != nil {
 			return nil, fmt.Errorf("dial upstream server: %s", err)
 		}
+
+		if upstream.BreakerThreshold > 0 {
+			cooldown := time.Duration(upstream.BreakerCooldown) * time.Second
+			if cooldown <= 0 {
+				cooldown = params.UpstreamBreakerCooldown * time.Second
+			}
+			c.breaker = newCircuitBreaker(upstream.BreakerThreshold, cooldown)
+		}
+
+		if upstream.WebSocketURL != "" {
+			header, err := upstream.Header()
+			if err != nil {
+				return nil, err
+			}
+			c.upstreamWS, err = dialUpstream(context.Background(), upstream.WebSocketURL, header)
+			if err != nil {
+				return nil, fmt.Errorf("dial upstream websocket: %s", err)
+			}
+		}
+
+		if upstream.OfflineStateEnabled {
+			var path string
+			if dir := node.InstanceDir(); dir != "" {
+				path = filepath.Join(dir, chainStateFile)
+			}
+			c.state = newChainState(path)
+		}
+
+		if upstream.OfflineQueueEnabled {
+			var path string
+			if dir := node.InstanceDir(); dir != "" {
+				path = filepath.Join(dir, offlineQueueFile)
+			}
+			c.offline = newOfflineMode(path)
+		}
 	}
 
-	c.router = newRouter(c.upstreamEnabled)
+	if c.cache != nil {
+		c.watchHeads()
+	}
 
 	return c, nil
 }
 
+// watchHeads subscribes cache to new heads, on the local node or on the upstream WebSocket endpoint,
+// depending on routing of eth_subscribe. Without subscription, cached results only expire.
+func (c *Client) watchHeads() {
+	client := c.local
+	if c.router.routeRemote("eth_subscribe") {
+		if c.upstreamWS == nil {
+			log.Info("Cached results are not dropped on new heads", "error", ErrSubscriptionsNotSupported)
+			return
+		}
+		client = c.upstreamWS
+	}
+
+	heads := make(chan json.RawMessage, subscriptionBufferSize)
+	sub, err := client.EthSubscribe(context.Background(), heads, SubscriptionNewHeads)
+	if err != nil {
+		log.Warn("Failed to subscribe cache to new heads", "error", err)
+		return
+	}
+	c.heads = sub
+	go c.cache.watchHeads(sub, heads)
+}
+
+// Close ends subscriptions and releases connections to upstream servers.
+func (c *Client) Close() {
+	c.subscriptions.removeAll()
+	if c.heads != nil {
+		c.heads.Unsubscribe()
+	}
+
+	if c.upstream != nil {
+		c.upstream.Close()
+	}
+	if c.upstreamWS != nil {
+		c.upstreamWS.Close()
+	}
+	if c.offline != nil {
+		c.offline.close()
+	}
+}
+
 // Call performs a JSON-RPC call with the given arguments and unmarshals into
 // result if no error occurred.
 //
@@ -89,12 +200,121 @@ func (c *Client) CallContext(ctx context.Context, result interface{}, method str
 		return c.callMethod(ctx, result, handler, args...)
 	}
 
+	// while upstream is unreachable, reads fail fast and writes are queued
+	if c.Offline() && (offlineQueueMethods[method] || c.router.routeRemote(method)) {
+		return c.callOffline(result, method, args...)
+	}
+
+	if c.cache != nil && c.cache.handles(method) {
+		return c.callCached(ctx, result, method, args...)
+	}
+
+	return c.callRouted(ctx, result, method, args...)
+}
+
+// callCached returns cached result of a call, if any,
+// otherwise performs the call and caches its result.
+func (c *Client) callCached(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	data, ok := c.cache.get(method, args)
+	if !ok {
+		if err := c.callRouted(ctx, &data, method, args...); err != nil {
+			return err
+		}
+		c.cache.put(method, args, data)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// callRouted performs a call either on upstream or local node.
+func (c *Client) callRouted(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	if c.router.routeRemote(method) {
-		return c.upstream.CallContext(ctx, result, method, args...)
+		return c.callUpstream(ctx, result, method, args...)
 	}
+
+	ctx, cancel := c.withCallTimeout(ctx)
+	defer cancel()
+
 	return c.local.CallContext(ctx, result, method, args...)
 }
 
+// callUpstream performs a call on upstream, retrying it on connectivity errors,
+// unless circuit breaker is open.
+func (c *Client) callUpstream(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	policy := backoff.Policy{Backoff: upstreamBackoff, MaxAttempts: c.retries + 1}
+	err := backoff.Retry(ctx, policy, func(attempt int) error {
+		if c.breaker != nil && !c.breaker.allow() {
+			return backoff.Permanent(ErrUpstreamUnavailable)
+		}
+
+		err := c.callUpstreamOnce(ctx, result, method, args...)
+		if !isConnectivityError(ctx, err) {
+			if c.breaker != nil {
+				if ctx.Err() != nil {
+					c.breaker.cancel()
+				} else {
+					c.breaker.success()
+				}
+			}
+			if ctx.Err() == nil {
+				c.SetOnline(true)
+			}
+			return backoff.Permanent(err)
+		}
+
+		if c.breaker != nil && c.breaker.failure() {
+			c.upstreamDegraded(err)
+		}
+		log.Debug("Upstream call failed", "method", method, "attempt", attempt, "error", err)
+		return err
+	})
+
+	// upstream is unavailable or retries are exhausted
+	if isConnectivityError(ctx, err) {
+		c.SetOnline(false)
+	}
+	return err
+}
+
+func (c *Client) callUpstreamOnce(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := c.withCallTimeout(ctx)
+	defer cancel()
+
+	return c.upstream.CallContext(ctx, result, method, args...)
+}
+
+// upstreamDegraded notifies application, that upstream calls fail immediately, until breaker cooldown passes.
+func (c *Client) upstreamDegraded(err error) {
+	event := UpstreamDegradedEvent{
+		Failures: c.breaker.threshold,
+		Error:    err.Error(),
+		RetryIn:  int(c.breaker.cooldown / time.Second),
+	}
+	log.Warn("Upstream is degraded, circuit breaker is open", "failures", event.Failures, "error", event.Error)
+	signal.Send(signal.Envelope{
+		Type:  signal.EventUpstreamDegraded,
+		Event: event,
+	})
+}
+
+// withCallTimeout limits a single attempt of a call with configured timeout.
+func (c *Client) withCallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.callTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.callTimeout)
+}
+
+// CallTimeout returns timeout of a single attempt of a call, to be used by callers,
+// which talk to the node bypassing the client.
+func (c *Client) CallTimeout() time.Duration {
+	return c.callTimeout
+}
+
 // RegisterHandler registers local handler for specific RPC method.
 //
 // If method is registered, it will be executed with given handler and
@@ -12,8 +12,64 @@ Routing rules are following:
 - if Upstream is disabled, everything is routed to local ethereum-go node
 - otherwise, some requests (from the list, see below) are routed to upstream, others - locally.
 
+Default routing can be overridden by rules of params.UpstreamRPCConfig.MethodRouting, such as
+"eth_getBalance=upstream" or "shh_*=local": rules are checked in order they are listed, before the
+list of methods described above, and may route calls to upstream even if Upstream is disabled
+(in that case, local node is still started, and other calls are routed locally).
+
 List of methods to be routed is currently available here: https://docs.google.com/spreadsheets/d/1N1nuzVN5tXoDmzkBLeC9_mwIlVH8DGF7YD2XwxA8BAE/edit#gid=0
 
+Upstream may consist of several providers (see params.UpstreamRPCConfig.FallbackURLs): their health
+is checked periodically, and calls fail over to another provider, if the selected one is unreachable.
+Authenticated providers are supported: params.UpstreamRPCConfig.Headers and BasicAuth are sent with every
+request (and WebSocket handshake); they are redacted from logs and config diffs.
+
+If params.UpstreamRPCConfig.CacheEnabled is set, results of idempotent calls (such as eth_chainId,
+eth_call or eth_getBlockByNumber for finalized blocks) are cached in memory for a TTL configured
+per method; those depending on the latest block are dropped, once a new head is notified by newHeads
+subscription (on the local node or upstream WebSocket endpoint, as eth_subscribe is routed).
+
+If params.UpstreamRPCConfig.OfflineStateEnabled is set, the last known balances, nonces, gas price and
+block number are kept (and persisted in node's instance dir), so that CallRaw can return them, while
+upstream is unreachable; such responses are labeled with "stale" field, holding time of the update.
+
+Every attempt of a call is limited by params.UpstreamRPCConfig.CallTimeout; upstream calls failed
+with connectivity errors are retried (see Retries) with jittered exponential backoff. If BreakerThreshold
+is set, consecutive failures open circuit breaker: upstream calls fail immediately with ErrUpstreamUnavailable
+(and "upstream.degraded" signal is sent), until a probe call, let through after BreakerCooldown, succeeds.
+
+CallRaw takes context of a call (cancelling it cancels pending requests) and returns JSON response
+to be passed to JavaScript as is; failures are also returned as *CallError (or *BatchError, listing
+failed calls of a batch), which tells transport failures, timeouts, cancellations and JSON-RPC errors
+apart, as well as whether call went to upstream or local node. The same is reported in "data" field
+of JSON-RPC error response.
+
+Every call of CallRaw is traced with ID of request, carried by context (see WithRequestID); a new one
+is assigned, unless jail (or another caller) has done it. ID is logged along with duration of call,
+reported in "data" of error responses, and included in signals resulting from the call (such as
+"transaction.queued"), so that UI action could be correlated with backend calls.
+
+If offline mode is enabled (see params.UpstreamRPCConfig.OfflineQueueEnabled), client goes offline, once
+upstream is not reachable (or application reports it with Client.SetOnline): reads fail fast with ErrOffline,
+while signed raw transactions and Whisper posts are queued in a persistent store. Upstream is probed
+periodically, and queued calls are replayed, once "connectivity.restored" signal is sent.
+
+Errors are reported with EIP-1474 error codes (see ErrCode* constants): the ones returned by
+server are kept, timeouts and failures to reach upstream are "resource unavailable", and errors of
+local handlers are looked up in a table, which packages extend with RegisterErrorCode (e.g. geth/txqueue
+registers its errors as "transaction rejected"). Other errors are reported as internal ones.
+
+Client.Subscribe creates eth_subscribe subscriptions (newHeads, logs and newPendingTransactions)
+and shh_subscribe subscriptions of Whisper messages on the local node, or on upstream WebSocket endpoint (see params.UpstreamRPCConfig.WebSocketURL);
+notifications are sent as "rpc.subscription" signals, and passed to an optional handler.
+
+Go code should prefer typed wrappers of common calls (balances, nonces, contract calls, sending
+of raw transactions) of package geth/rpc/ethclient, which are made with Client and routed the same way.
+
+WebView-based dApps may send requests via local HTTP proxy of package geth/rpc/proxy, which forwards
+them to Client (so they get the same routing and transaction approval as jail cells), rejecting methods
+that sign data or manage accounts and node.
+
 Note, upon creation of a new client, it ok to be offline - client will keep trying to reconnect in background.
 
 */
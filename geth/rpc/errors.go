@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// JSON-RPC error codes, see http://www.jsonrpc.org/specification#error_object
+// and https://github.com/ethereum/EIPs/blob/master/EIPS/eip-1474.md#error-codes
+const (
+	ErrCodeParse               = -32700 // invalid JSON
+	ErrCodeInvalidRequest      = -32600 // JSON is not a valid request object
+	ErrCodeMethodNotFound      = -32601 // method does not exist
+	ErrCodeInvalidParams       = -32602 // invalid method parameters
+	ErrCodeInternal            = -32603 // internal JSON-RPC error
+	ErrCodeInvalidInput        = -32000 // missing or invalid parameters
+	ErrCodeResourceNotFound    = -32001 // requested resource not found
+	ErrCodeResourceUnavailable = -32002 // requested resource not available
+	ErrCodeTransactionRejected = -32003 // transaction creation failed
+	ErrCodeMethodNotSupported  = -32004 // method is not implemented
+	ErrCodeLimitExceeded       = -32005 // request exceeds defined limit
+	ErrCodeUnauthorized        = 4100   // requested method or data has not been authorized (EIP-1193)
+)
+
+// errorCodes maps errors of local handlers and of the client itself to JSON-RPC error codes
+var errorCodes = struct {
+	sync.RWMutex
+	codes map[error]int
+}{
+	codes: map[error]int{
+		ErrUpstreamUnavailable:              ErrCodeResourceUnavailable,
+		ErrNoUpstreamProviders:              ErrCodeResourceUnavailable,
+		ErrOffline:                          ErrCodeResourceUnavailable,
+		ErrSubscriptionNotFound:             ErrCodeResourceNotFound,
+		ErrUnknownSubscriptionType:          ErrCodeInvalidParams,
+		ErrSubscriptionsNotSupported:        ErrCodeMethodNotSupported,
+		gethrpc.ErrClientQuit:               ErrCodeResourceUnavailable,
+		gethrpc.ErrNotificationsUnsupported: ErrCodeMethodNotSupported,
+	},
+}
+
+// RegisterErrorCode sets JSON-RPC error code, a given error is reported with to JavaScript
+// (e.g. errors of local handlers, see Client.RegisterHandler). Errors are matched by identity,
+// registering the same error again replaces its code.
+func RegisterErrorCode(err error, code int) {
+	errorCodes.Lock()
+	defer errorCodes.Unlock()
+	errorCodes.codes[err] = code
+}
+
+// ErrorCode returns JSON-RPC error code, registered for a given error (see RegisterErrorCode).
+func ErrorCode(err error) (int, bool) {
+	errorCodes.RLock()
+	defer errorCodes.RUnlock()
+	code, ok := errorCodes.codes[err]
+	return code, ok
+}
+
+// errorCode selects JSON-RPC error code of failed call: the one of JSON-RPC error, returned by
+// server, registered one, or resource unavailable for timeouts, cancelled calls and failures
+// to reach upstream. Internal error is a fallback.
+func errorCode(ctx context.Context, origin string, err error) int {
+	if er, ok := err.(gethrpc.Error); ok {
+		return er.ErrorCode()
+	}
+	if code, ok := ErrorCode(err); ok {
+		return code
+	}
+
+	if ctx.Err() != nil || err == context.DeadlineExceeded {
+		return ErrCodeResourceUnavailable
+	}
+	if _, ok := err.(net.Error); ok || (origin == CallOriginUpstream && isConnectivityError(ctx, err)) {
+		return ErrCodeResourceUnavailable
+	}
+
+	return ErrCodeInternal
+}
@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodes(t *testing.T) {
+	errRejected := errors.New("rejected")
+	errUnknown := errors.New("unknown")
+	RegisterErrorCode(errRejected, ErrCodeTransactionRejected)
+
+	c := &Client{handlers: make(map[string]Handler)}
+	c.RegisterHandler("test_rejected", func(context.Context, ...interface{}) (interface{}, error) {
+		return nil, errRejected
+	})
+	c.RegisterHandler("test_unknown", func(context.Context, ...interface{}) (interface{}, error) {
+		return nil, errUnknown
+	})
+
+	response, err := c.CallRaw(context.Background(), `{"jsonrpc":"2.0","id":1,"method":"test_rejected","params":[]}`)
+	require.Equal(t, ErrCodeTransactionRejected, err.(*CallError).Code)
+	require.Contains(t, response, `"code":-32003,"message":"rejected"`)
+
+	// errors, which are not registered, are internal ones
+	_, err = c.CallRaw(context.Background(), `{"jsonrpc":"2.0","id":1,"method":"test_unknown","params":[]}`)
+	require.Equal(t, ErrCodeInternal, err.(*CallError).Code)
+
+	code, ok := ErrorCode(ErrUpstreamUnavailable)
+	require.True(t, ok)
+	require.Equal(t, ErrCodeResourceUnavailable, code)
+}
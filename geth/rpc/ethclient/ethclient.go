@@ -0,0 +1,258 @@
+// Package ethclient provides typed wrappers of common Ethereum JSON-RPC calls.
+//
+// Calls are made with status-go RPC client (see geth/rpc), so they are routed
+// to either upstream or local node, and are subject to its caching, timeouts
+// and retries, the same way as calls coming from JS code.
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Caller performs JSON-RPC calls, rpc.Client implements it.
+type Caller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// Client defines typed wrappers for the Ethereum RPC API.
+type Client struct {
+	c Caller
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c Caller) *Client {
+	return &Client{c}
+}
+
+// BalanceAt returns the wei balance of the given account.
+// The block number can be nil, in which case the balance is taken from the latest known block.
+func (ec *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var result hexutil.Big
+	if err := ec.c.CallContext(ctx, &result, "eth_getBalance", account, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// NonceAt returns the account nonce of the given account.
+// The block number can be nil, in which case the nonce is taken from the latest known block.
+func (ec *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var result hexutil.Uint64
+	err := ec.c.CallContext(ctx, &result, "eth_getTransactionCount", account, toBlockNumArg(blockNumber))
+	return uint64(result), err
+}
+
+// PendingNonceAt returns the account nonce of the given account in the pending state,
+// i.e. nonce to be used for the next transaction.
+func (ec *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result hexutil.Uint64
+	err := ec.c.CallContext(ctx, &result, "eth_getTransactionCount", account, "pending")
+	return uint64(result), err
+}
+
+// HeaderByNumber returns a block header from the current canonical chain. If number is
+// nil, the latest known header is returned.
+func (ec *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var head *types.Header
+	err := ec.c.CallContext(ctx, &head, "eth_getBlockByNumber", toBlockNumArg(number), false)
+	if err == nil && head == nil {
+		err = ethereum.NotFound
+	}
+	return head, err
+}
+
+// Block is a block, returned by eth_getBlockByNumber, along with its transactions.
+type Block struct {
+	Number       *hexutil.Big     `json:"number"`
+	Hash         common.Hash      `json:"hash"`
+	Time         *hexutil.Big     `json:"timestamp"`
+	Transactions []RPCTransaction `json:"transactions"`
+}
+
+// RPCTransaction is a transaction of block, along with its sender.
+type RPCTransaction struct {
+	Hash  common.Hash     `json:"hash"`
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Nonce hexutil.Uint64  `json:"nonce"`
+}
+
+// BlockByNumber returns a block with the given number, along with its transactions.
+// The block number can be nil, in which case the latest known block is returned.
+func (ec *Client) BlockByNumber(ctx context.Context, number *big.Int) (*Block, error) {
+	var block *Block
+	err := ec.c.CallContext(ctx, &block, "eth_getBlockByNumber", toBlockNumArg(number), true)
+	if err == nil && block == nil {
+		err = ethereum.NotFound
+	}
+	return block, err
+}
+
+// FilterLogs executes a filter query.
+func (ec *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := ec.c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(q))
+	return result, err
+}
+
+// CallContract executes a message call transaction, which is directly executed in the VM
+// of the node, but never mined into the blockchain.
+// The block number can be nil, in which case the call runs at the latest known block.
+func (ec *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &result, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (ec *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var result hexutil.Big
+	if err := ec.c.CallContext(ctx, &result, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// EstimateGas estimates the gas needed to execute a transaction based on the pending state.
+func (ec *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (*big.Int, error) {
+	var result hexutil.Big
+	if err := ec.c.CallContext(ctx, &result, "eth_estimateGas", toCallArg(msg)); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// rpcTransaction is a transaction, returned by eth_getTransactionByHash,
+// along with number of block it's included in (nil for pending transactions).
+type rpcTransaction struct {
+	tx          *types.Transaction
+	BlockNumber *string
+}
+
+func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
+	var block struct {
+		BlockNumber *string `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(msg, &block); err != nil {
+		return err
+	}
+	tx.BlockNumber = block.BlockNumber
+
+	return json.Unmarshal(msg, &tx.tx)
+}
+
+// TransactionByHash returns the transaction with the given hash, and whether it's still pending.
+func (ec *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	// raw result is decoded separately, so that local handlers of the method (see rpc.Client) are supported
+	var raw json.RawMessage
+	if err := ec.c.CallContext(ctx, &raw, "eth_getTransactionByHash", hash); err != nil {
+		return nil, false, err
+	}
+	var result *rpcTransaction
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, false, err
+	}
+	if result == nil {
+		return nil, false, ethereum.NotFound
+	}
+	return result.tx, result.BlockNumber == nil, nil
+}
+
+// Receipt is a receipt of mined transaction.
+type Receipt struct {
+	TxHash          common.Hash     `json:"transactionHash"`
+	BlockNumber     *hexutil.Big    `json:"blockNumber"`
+	GasUsed         *hexutil.Big    `json:"gasUsed"`
+	ContractAddress *common.Address `json:"contractAddress"`
+	Status          *hexutil.Uint64 `json:"status"` // 1 (success) or 0 (failure), nil for blocks before Byzantium
+}
+
+// TransactionReceipt returns the receipt of a mined transaction.
+// The returned error is ethereum.NotFound, if transaction is not mined yet.
+func (ec *Client) TransactionReceipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	var result *Receipt
+	if err := ec.c.CallContext(ctx, &result, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	if result == nil || result.BlockNumber == nil {
+		return nil, ethereum.NotFound
+	}
+	return result, nil
+}
+
+// BlockNumber returns the number of the most recent block.
+func (ec *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result hexutil.Uint64
+	err := ec.c.CallContext(ctx, &result, "eth_blockNumber")
+	return uint64(result), err
+}
+
+// SendRawTransaction injects a signed transaction into the pending pool, returning its hash.
+func (ec *Client) SendRawTransaction(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return ec.SendRawTransactionData(ctx, data)
+}
+
+// SendRawTransactionData injects encoded signed transaction (either legacy RLP one, or typed envelope)
+// into the pending pool, returning its hash.
+func (ec *Client) SendRawTransactionData(ctx context.Context, data []byte) (common.Hash, error) {
+	if err := ec.c.CallContext(ctx, nil, "eth_sendRawTransaction", common.ToHex(data)); err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
+
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != nil {
+		arg["gas"] = (*hexutil.Big)(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+func toFilterArg(q ethereum.FilterQuery) interface{} {
+	arg := map[string]interface{}{
+		"fromBlock": toBlockNumArg(q.FromBlock),
+		"toBlock":   toBlockNumArg(q.ToBlock),
+		"address":   q.Addresses,
+		"topics":    q.Topics,
+	}
+	if q.FromBlock == nil {
+		arg["fromBlock"] = "0x0"
+	}
+	return arg
+}
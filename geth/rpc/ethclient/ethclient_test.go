@@ -0,0 +1,213 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	method string
+	args   []interface{}
+}
+
+// testCaller returns canned (JSON) results of methods, recording calls.
+type testCaller struct {
+	results map[string]string
+	calls   []call
+}
+
+func (c *testCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.calls = append(c.calls, call{method, args})
+
+	response, ok := c.results[method]
+	if !ok {
+		return ethereum.NotFound
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(response), result)
+}
+
+func TestClient(t *testing.T) {
+	caller := &testCaller{results: map[string]string{
+		"eth_getBalance":          `"0x3e8"`,
+		"eth_getTransactionCount": `"0x7"`,
+		"eth_call":                `"0x0102"`,
+		"eth_gasPrice":            `"0x14"`,
+		"eth_estimateGas":         `"0x5208"`,
+		"eth_sendRawTransaction":  `"0x0"`,
+		"eth_getBlockByNumber":    `null`,
+		"eth_blockNumber":         `"0x5daf3c"`,
+		"eth_getTransactionReceipt": `{
+			"transactionHash": "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+			"blockNumber": "0x5daf3b",
+			"gasUsed": "0x5208",
+			"contractAddress": null,
+			"status": "0x1"
+		}`,
+	}}
+	client := NewClient(caller)
+	ctx := context.Background()
+	account := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	balance, err := client.BalanceAt(ctx, account, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), balance)
+	require.Equal(t, []interface{}{account, "latest"}, caller.calls[0].args)
+
+	nonce, err := client.NonceAt(ctx, account, big.NewInt(16))
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nonce)
+	require.Equal(t, []interface{}{account, "0x10"}, caller.calls[1].args)
+
+	nonce, err = client.PendingNonceAt(ctx, account)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nonce)
+	require.Equal(t, []interface{}{account, "pending"}, caller.calls[2].args)
+
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &account, Data: []byte{1}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, result)
+
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20), gasPrice)
+
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: account, To: &account})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(21000), gas)
+
+	tx := types.NewTransaction(7, account, big.NewInt(1), gas, gasPrice, nil)
+	hash, err := client.SendRawTransaction(ctx, tx)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), hash)
+	require.Equal(t, "eth_sendRawTransaction", caller.calls[len(caller.calls)-1].method)
+
+	_, _, err = client.TransactionByHash(ctx, hash)
+	require.Equal(t, ethereum.NotFound, err)
+
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(6139707), receipt.BlockNumber.ToInt())
+	require.Equal(t, big.NewInt(21000), receipt.GasUsed.ToInt())
+	require.Equal(t, uint64(1), uint64(*receipt.Status))
+	require.Nil(t, receipt.ContractAddress)
+
+	blockNumber, err := client.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6139708), blockNumber)
+
+	caller.results["eth_getTransactionReceipt"] = `null`
+	_, err = client.TransactionReceipt(ctx, hash)
+	require.Equal(t, ethereum.NotFound, err)
+
+	_, err = client.HeaderByNumber(ctx, nil)
+	require.Equal(t, ethereum.NotFound, err)
+
+	// errors are passed as is
+	delete(caller.results, "eth_getBalance")
+	_, err = client.BalanceAt(ctx, account, nil)
+	require.Equal(t, ethereum.NotFound, err)
+}
+
+func TestTransactionByHash(t *testing.T) {
+	const pendingTx = `{
+		"blockHash": null,
+		"blockNumber": null,
+		"from": "0xa7d9ddbe1f17865597fbd27ec712455208b6b76d",
+		"gas": "0x5208",
+		"gasPrice": "0x4a817c800",
+		"hash": "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",
+		"input": "0x",
+		"nonce": "0x15",
+		"to": "0xf02c1c8e6114b1dbe8937a39260b5b0a374432bb",
+		"transactionIndex": null,
+		"value": "0xf3dbb76162000",
+		"v": "0x25",
+		"r": "0x1b5e176d927f8e9ab405058b2d2457392da3e20f328b16ddabcebc33eaac5fea",
+		"s": "0x4ba69724e8f69de52f0125ad8b3c5c2cef33019bac3249e2c0a2192766d1721c"
+	}`
+	caller := &testCaller{results: map[string]string{
+		"eth_getTransactionByHash": pendingTx,
+	}}
+	client := NewClient(caller)
+	hash := common.HexToHash("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")
+
+	tx, pending, err := client.TransactionByHash(context.Background(), hash)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, uint64(21), tx.Nonce())
+	require.Equal(t, big.NewInt(20000000000), tx.GasPrice())
+	require.Equal(t, []interface{}{hash}, caller.calls[0].args)
+
+	caller.results["eth_getTransactionByHash"] = strings.Replace(pendingTx, `"blockNumber": null`, `"blockNumber": "0x5daf3b"`, 1)
+	_, pending, err = client.TransactionByHash(context.Background(), hash)
+	require.NoError(t, err)
+	require.False(t, pending)
+
+	caller.results["eth_getTransactionByHash"] = `null`
+	_, _, err = client.TransactionByHash(context.Background(), hash)
+	require.Equal(t, ethereum.NotFound, err)
+}
+
+func TestBlocksAndLogs(t *testing.T) {
+	caller := &testCaller{results: map[string]string{
+		"eth_getBlockByNumber": `{
+			"number": "0x10",
+			"hash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"timestamp": "0x5a8f0c3e",
+			"transactions": [{
+				"hash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+				"from": "0x1111111111111111111111111111111111111111",
+				"to": "0x2222222222222222222222222222222222222222",
+				"value": "0x3e8",
+				"nonce": "0x1"
+			}]
+		}`,
+		"eth_getLogs": `[{
+			"address": "0x3333333333333333333333333333333333333333",
+			"topics": ["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],
+			"data": "0x00000000000000000000000000000000000000000000000000000000000003e8",
+			"blockNumber": "0x10",
+			"transactionHash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+			"transactionIndex": "0x0",
+			"blockHash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"logIndex": "0x0",
+			"removed": false
+		}]`,
+	}}
+	client := NewClient(caller)
+	ctx := context.Background()
+
+	block, err := client.BlockByNumber(ctx, big.NewInt(16))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(16), block.Number.ToInt())
+	require.Len(t, block.Transactions, 1)
+	require.Equal(t, common.HexToAddress("0x1111111111111111111111111111111111111111"), block.Transactions[0].From)
+	require.Equal(t, []interface{}{"0x10", true}, caller.calls[0].args)
+
+	account := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(16),
+		ToBlock:   big.NewInt(32),
+		Topics:    [][]common.Hash{nil, {account.Hash()}},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, uint64(16), logs[0].BlockNumber)
+	require.Equal(t, map[string]interface{}{
+		"fromBlock": "0x10",
+		"toBlock":   "0x20",
+		"address":   []common.Address(nil),
+		"topics":    [][]common.Hash{nil, {account.Hash()}},
+	}, caller.calls[1].args[0])
+}
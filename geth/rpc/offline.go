@@ -0,0 +1,335 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// offlineQueueFile is a name of file in node's instance dir, where calls queued while offline are kept
+	offlineQueueFile = "offlinequeue.json"
+
+	// offlineProbeInterval is an interval between probe calls of upstream, while offline
+	offlineProbeInterval = 10 * time.Second
+)
+
+// errors
+var (
+	ErrOffline = errors.New("upstream is not reachable, client is offline")
+)
+
+// offlineQueueMethods lists methods, calls of which are queued while offline, and replayed once connectivity is restored
+var offlineQueueMethods = map[string]bool{
+	"eth_sendRawTransaction": true,
+	"shh_post":               true,
+}
+
+// ConnectivityEvent is a signal sent, when connectivity to upstream is lost or restored.
+type ConnectivityEvent struct {
+	Queued int `json:"queued"` // number of calls queued while offline
+}
+
+// queuedCall is a call made while offline.
+type queuedCall struct {
+	ID       string          `json:"id"`
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params"`
+	QueuedAt int64           `json:"queuedAt"` // unix time
+}
+
+// offlineMode tracks connectivity to upstream: while offline, calls fail fast with ErrOffline, except of
+// signed raw transactions and Whisper posts, which are queued (and persisted, so that they survive
+// restart of the node) and replayed, once connectivity is restored.
+type offlineMode struct {
+	mu      sync.Mutex
+	offline bool
+	path    string // empty, if queue is not persisted
+	calls   []queuedCall
+	probing bool
+	quit    chan struct{}
+	now     func() time.Time
+}
+
+// newOfflineMode loads calls persisted at path (if any).
+func newOfflineMode(path string) *offlineMode {
+	m := &offlineMode{
+		path: path,
+		quit: make(chan struct{}),
+		now:  time.Now,
+	}
+
+	if path == "" {
+		return m
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("Failed to read offline queue", "path", path, "error", err)
+		}
+		return m
+	}
+	if err := json.Unmarshal(data, &m.calls); err != nil {
+		log.Warn("Failed to parse offline queue", "path", path, "error", err)
+		m.calls = nil
+	}
+
+	return m
+}
+
+func (m *offlineMode) isOffline() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.offline
+}
+
+// push queues call, persisting queue.
+func (m *offlineMode) push(method string, args []interface{}) error {
+	params, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, queuedCall{
+		ID:       uuid.New(),
+		Method:   method,
+		Params:   params,
+		QueuedAt: m.now().Unix(),
+	})
+
+	return m.save()
+}
+
+// requeue puts call back to the front of queue.
+func (m *offlineMode) requeue(call queuedCall) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append([]queuedCall{call}, m.calls...)
+	return m.save()
+}
+
+// pop removes the first queued call, if any.
+func (m *offlineMode) pop() (queuedCall, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.calls) == 0 {
+		return queuedCall{}, false
+	}
+	call := m.calls[0]
+	m.calls = m.calls[1:]
+	if err := m.save(); err != nil {
+		log.Warn("Failed to save offline queue", "path", m.path, "error", err)
+	}
+
+	return call, true
+}
+
+// save writes queue to a temporary file, replacing persisted one with it.
+// Must be called with lock held.
+func (m *offlineMode) save() error {
+	if m.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(m.calls)
+	if err != nil {
+		return err
+	}
+
+	tmp := m.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, m.path)
+}
+
+// Offline returns true, if upstream is not reachable (always false, unless offline mode is enabled,
+// see params.UpstreamRPCConfig.OfflineQueueEnabled).
+func (c *Client) Offline() bool {
+	return c.offline != nil && c.offline.isOffline()
+}
+
+// SetOnline sets connectivity state, e.g. once application is notified of changes of network
+// reachability by OS. Connectivity is also detected by client, based on results of upstream calls.
+func (c *Client) SetOnline(online bool) {
+	if c.offline == nil {
+		return
+	}
+
+	m := c.offline
+	m.mu.Lock()
+	if m.offline == !online {
+		m.mu.Unlock()
+		return
+	}
+	m.offline = !online
+	startProbe := m.offline && !m.probing
+	if startProbe {
+		m.probing = true
+	}
+	queued := len(m.calls)
+	m.mu.Unlock()
+
+	if online {
+		log.Info("Connectivity to upstream is restored", "queued", queued)
+		signal.Send(signal.Envelope{
+			Type:  signal.EventConnectivityRestored,
+			Event: ConnectivityEvent{Queued: queued},
+		})
+		go c.replayQueued()
+		return
+	}
+
+	log.Warn("Connectivity to upstream is lost", "queued", queued)
+	signal.Send(signal.Envelope{
+		Type:  signal.EventConnectivityLost,
+		Event: ConnectivityEvent{Queued: queued},
+	})
+	if startProbe {
+		go c.probeUpstream()
+	}
+}
+
+// callOffline handles a call made while offline: queues it, if method is a write operation,
+// which could be replayed, and fails it with ErrOffline otherwise.
+func (c *Client) callOffline(result interface{}, method string, args ...interface{}) error {
+	if !offlineQueueMethods[method] {
+		return ErrOffline
+	}
+
+	response, err := offlineResponse(method, args)
+	if err != nil {
+		return err
+	}
+	if err := c.offline.push(method, args); err != nil {
+		return err
+	}
+	log.Info("Call is queued until connectivity is restored", "method", method)
+
+	if result == nil {
+		return nil
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// offlineResponse returns result of a queued call, the same as it would be returned by node: hash of
+// signed raw transaction (which is validated beforehand), or true for Whisper post.
+func offlineResponse(method string, args []interface{}) (interface{}, error) {
+	if method != "eth_sendRawTransaction" {
+		return true, nil
+	}
+
+	if len(args) != 1 {
+		return nil, errors.New("eth_sendRawTransaction expects a single argument")
+	}
+	// argument is either a hex string (CallRaw), or hexutil.Bytes (ethclient)
+	arg, err := json.Marshal(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var data hexutil.Bytes
+	if err := json.Unmarshal(arg, &data); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		return nil, err
+	}
+
+	return tx.Hash().Hex(), nil
+}
+
+// replayQueued replays queued calls in order, until connectivity is lost again. Calls, which fail for
+// other reasons (e.g. transaction is rejected by node), are dropped, as they would fail on any retry.
+func (c *Client) replayQueued() {
+	for !c.offline.isOffline() {
+		call, ok := c.offline.pop()
+		if !ok {
+			return
+		}
+
+		var args []interface{}
+		if err := json.Unmarshal(call.Params, &args); err != nil {
+			log.Warn("Dropped malformed queued call", "id", call.ID, "method", call.Method, "error", err)
+			continue
+		}
+
+		err := c.callRouted(context.Background(), nil, call.Method, args...)
+		if err == ErrUpstreamUnavailable || (c.router.routeRemote(call.Method) && isConnectivityError(context.Background(), err)) {
+			// call is put back, it is replayed once connectivity is restored again
+			if err := c.offline.requeue(call); err != nil {
+				log.Warn("Failed to re-queue call", "id", call.ID, "method", call.Method, "error", err)
+			}
+			return
+		}
+		if err != nil {
+			log.Warn("Replayed queued call failed", "id", call.ID, "method", call.Method, "error", err)
+			continue
+		}
+		log.Info("Replayed queued call", "id", call.ID, "method", call.Method)
+	}
+}
+
+// probeUpstream periodically checks whether upstream is reachable, while offline.
+func (c *Client) probeUpstream() {
+	defer func() {
+		c.offline.mu.Lock()
+		c.offline.probing = false
+		c.offline.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(offlineProbeInterval)
+	defer ticker.Stop()
+
+	for c.offline.isOffline() {
+		select {
+		case <-ticker.C:
+		case <-c.offline.quit:
+			return
+		}
+
+		var version string
+		err := c.callUpstreamOnce(context.Background(), &version, "net_version")
+		if !isConnectivityError(context.Background(), err) {
+			if c.breaker != nil {
+				c.breaker.success()
+			}
+			c.SetOnline(true)
+		}
+	}
+}
+
+// close stops probing of upstream.
+func (m *offlineMode) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	select {
+	case <-m.quit:
+	default:
+		close(m.quit)
+	}
+}
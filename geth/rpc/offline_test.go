@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineQueueReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "offline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	var mu sync.Mutex
+	var down bool
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if down {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		var req jsonrpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Method == "eth_sendRawTransaction" {
+			sent = append(sent, string(req.Params))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(newSuccessResponse(json.RawMessage(`"0x10"`), req.ID))) // nolint: errcheck
+	}))
+	defer server.Close()
+	setDown := func(value bool) {
+		mu.Lock()
+		down = value
+		mu.Unlock()
+	}
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{URL: server.URL})
+	require.NoError(t, err)
+	router, err := newRouter(true, nil)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, offlineQueueFile)
+	c := &Client{
+		upstream:      pool,
+		router:        router,
+		offline:       newOfflineMode(path),
+		subscriptions: newSubscriptions(),
+		handlers:      make(map[string]Handler),
+	}
+	defer c.Close()
+
+	// client goes offline, once upstream is not reachable, failing further reads immediately
+	setDown(true)
+	err = c.Call(nil, "eth_gasPrice")
+	require.Error(t, err)
+	require.True(t, c.Offline())
+	require.Equal(t, ErrOffline, c.Call(nil, "eth_gasPrice"))
+
+	// signed transactions are queued, and their hashes are returned
+	tx := types.NewTransaction(1, common.HexToAddress("0x01"), big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+	data, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	raw := hexutil.Encode(data)
+	response, err := c.CallRaw(context.Background(), `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["`+raw+`"]}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"`+tx.Hash().Hex()+`"}`, response)
+	require.Len(t, newOfflineMode(path).calls, 1)
+
+	_, err = c.CallRaw(context.Background(), `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x01"]}`)
+	require.Error(t, err)
+
+	// queued calls are replayed, once connectivity is restored
+	setDown(false)
+	c.SetOnline(true)
+	require.False(t, c.Offline())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(sent)
+		mu.Unlock()
+		if n > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	require.Equal(t, []string{`["` + raw + `"]`}, sent)
+	mu.Unlock()
+	require.Empty(t, newOfflineMode(path).calls)
+}
@@ -0,0 +1,270 @@
+// Package proxy implements local HTTP JSON-RPC proxy for dApps, opened in the in-app WebView.
+//
+// Requests are forwarded to status-go RPC client (see geth/rpc), so they are routed and handled
+// the same way as requests of jail cells: eth_sendTransaction is queued for user approval (and
+// checked against signing policy, see params.SigningConfig), rather than signed by node, and
+// queued transactions are tagged with origin of the page (see common.OriginKey).
+//
+// Proxy is read-only in the sense that it never signs anything on its own: methods, which sign
+// data or manage accounts and node (such as eth_sign, personal_* or admin_*), are rejected.
+// It only listens on loopback interface, and requests addressed to other hosts are rejected
+// to prevent DNS rebinding.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/rpc"
+)
+
+// errors
+var (
+	ErrNotLoopback      = errors.New("proxy can only listen on loopback interface")
+	ErrProxyRunning     = errors.New("proxy is already running")
+	ErrProxyNotRunning  = errors.New("proxy is not running")
+	ErrMethodNotAllowed = errors.New("method is not allowed")
+	ErrNodeStopped      = errors.New("node is not running")
+)
+
+// maxRequestSize limits size of request body
+const maxRequestSize = 5 * 1024 * 1024
+
+// deniedPrefixes lists namespaces of methods, which are never forwarded
+var deniedPrefixes = []string{"admin_", "debug_", "miner_", "personal_", "txpool_"}
+
+// deniedMethods lists methods, which are never forwarded
+var deniedMethods = map[string]struct{}{
+	"eth_sign":            {},
+	"eth_signTransaction": {},
+}
+
+// Caller sends raw JSON-RPC requests, rpc.Client implements it.
+type Caller interface {
+	CallRaw(ctx context.Context, body string) (string, error)
+}
+
+// Server is HTTP JSON-RPC proxy server.
+type Server struct {
+	mu       sync.Mutex
+	client   func() Caller // returns nil, if node is not running
+	listener net.Listener
+	server   *http.Server
+}
+
+// New creates proxy server, forwarding requests to client returned by a given function
+// (client of the running node, or nil, if node is not running).
+func New(client func() Caller) *Server {
+	return &Server{client: client}
+}
+
+// Start starts listening on a given address ("127.0.0.1:0" picks a free port),
+// which must be a loopback one.
+func (s *Server) Start(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		return ErrProxyRunning
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if !isLoopback(host) {
+		return ErrNotLoopback
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s.listener = listener
+	s.server = &http.Server{Handler: s}
+	go s.server.Serve(listener) // nolint: errcheck
+
+	log.Info("RPC proxy started", "url", s.url())
+
+	return nil
+}
+
+// Stop stops the server.
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return ErrProxyNotRunning
+	}
+
+	err := s.server.Close()
+	s.listener = nil
+	s.server = nil
+	log.Info("RPC proxy stopped")
+
+	return err
+}
+
+// URL returns URL of the running server, or empty string, if it is not running.
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.url()
+}
+
+func (s *Server) url() string {
+	if s.listener == nil {
+		return ""
+	}
+
+	return "http://" + s.listener.Addr().String()
+}
+
+// ServeHTTP forwards JSON-RPC request to RPC client.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLoopbackHost(r.Host) {
+		http.Error(w, "invalid host specified", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write([]byte(s.handle(r.Context(), r.Header.Get("Origin"), body))); err != nil {
+		log.Debug("RPC proxy failed to write response", "error", err)
+	}
+}
+
+// handle checks methods of request (or batch of them) and forwards it to RPC client.
+func (s *Server) handle(ctx context.Context, origin string, body []byte) string {
+	calls, err := parseCalls(body)
+	if err != nil {
+		return newErrorResponse(nil, rpc.ErrCodeParse, err)
+	}
+
+	for _, call := range calls {
+		if !isAllowed(call.Method) {
+			log.Warn("RPC proxy rejected method", "method", call.Method, "origin", origin)
+			return newErrorResponse(call.ID, rpc.ErrCodeMethodNotFound, fmt.Errorf("%v: %s", ErrMethodNotAllowed, call.Method))
+		}
+	}
+
+	client := s.client()
+	if client == nil {
+		return newErrorResponse(calls[0].ID, rpc.ErrCodeResourceUnavailable, ErrNodeStopped)
+	}
+
+	if origin != "" {
+		ctx = context.WithValue(ctx, common.OriginKey, origin)
+	}
+
+	// failures are reported in response
+	response, _ := client.CallRaw(ctx, string(body))
+	return response
+}
+
+// call holds fields of JSON-RPC request, proxy is interested in.
+type call struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// parseCalls parses either a single JSON-RPC request, or a batch of them.
+func parseCalls(body []byte) ([]call, error) {
+	var calls []call
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil, err
+		}
+	} else {
+		var c call
+		if err := json.Unmarshal(body, &c); err != nil {
+			return nil, err
+		}
+		calls = append(calls, c)
+	}
+
+	if len(calls) == 0 {
+		return nil, errors.New("empty batch")
+	}
+
+	return calls, nil
+}
+
+// isAllowed checks whether method may be forwarded.
+func isAllowed(method string) bool {
+	if _, ok := deniedMethods[method]; ok {
+		return false
+	}
+	for _, prefix := range deniedPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newErrorResponse returns JSON-RPC error response.
+func newErrorResponse(id json.RawMessage, code int, err error) string {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+
+	response, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": err.Error(),
+		},
+	})
+
+	return string(response)
+}
+
+// isLoopback checks whether host (name or IP) refers to loopback interface.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isLoopbackHost checks Host header of request.
+func isLoopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+
+	return isLoopback(strings.Trim(host, "[]"))
+}
@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testCaller echoes requests, recording their origin.
+type testCaller struct {
+	origin string
+	calls  int
+}
+
+func (c *testCaller) CallRaw(ctx context.Context, body string) (string, error) {
+	c.origin = common.OriginFromContext(ctx)
+	c.calls++
+	return `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, nil
+}
+
+func TestProxy(t *testing.T) {
+	caller := &testCaller{}
+	running := true
+	server := New(func() Caller {
+		if !running {
+			return nil
+		}
+		return caller
+	})
+
+	require.Equal(t, ErrNotLoopback, server.Start("0.0.0.0:0"))
+	require.NoError(t, server.Start("127.0.0.1:0"))
+	defer server.Stop() // nolint: errcheck
+	require.Equal(t, ErrProxyRunning, server.Start("127.0.0.1:0"))
+
+	post := func(body string) string {
+		request, err := http.NewRequest(http.MethodPost, server.URL(), bytes.NewBufferString(body))
+		require.NoError(t, err)
+		request.Header.Set("Origin", "https://dapp.example")
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close() // nolint: errcheck
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		data, err := ioutil.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// requests are forwarded, tagged with origin
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"0x1"}`, post(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`))
+	require.Equal(t, "https://dapp.example", caller.origin)
+
+	// methods, which sign data or manage node, are rejected
+	require.Contains(t, post(`{"jsonrpc":"2.0","id":2,"method":"eth_sign","params":[]}`), `"code":-32601`)
+	require.Contains(t, post(`[{"jsonrpc":"2.0","id":3,"method":"eth_call"},{"jsonrpc":"2.0","id":4,"method":"personal_unlockAccount"}]`), `"id":4`)
+	require.Contains(t, post(`not json`), `"code":-32700`)
+	require.Equal(t, 1, caller.calls)
+
+	running = false
+	require.Contains(t, post(`{"jsonrpc":"2.0","id":5,"method":"eth_blockNumber"}`), ErrNodeStopped.Error())
+
+	// requests addressed to other hosts are rejected
+	request, err := http.NewRequest(http.MethodPost, server.URL(), bytes.NewBufferString(`{}`))
+	require.NoError(t, err)
+	request.Host = "attacker.example"
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	require.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	require.NoError(t, server.Stop())
+	require.Equal(t, ErrProxyNotRunning, server.Stop())
+}
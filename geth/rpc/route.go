@@ -1,15 +1,43 @@
 package rpc
 
+import (
+	"errors"
+	"strings"
+
+	"github.com/status-im/status-go/geth/params"
+)
+
+// errors
+var (
+	ErrInvalidRoutingRule = errors.New("invalid method routing rule, expected \"method=local\" or \"method=upstream\"")
+)
+
+// routingRule routes methods matching pattern either to Upstream or Local node.
+type routingRule struct {
+	pattern string // method name, or prefix of it, if ends with "*"
+	remote  bool
+}
+
+func (r routingRule) match(method string) bool {
+	if strings.HasSuffix(r.pattern, "*") {
+		return strings.HasPrefix(method, strings.TrimSuffix(r.pattern, "*"))
+	}
+
+	return method == r.pattern
+}
+
 // router implements logic for routing
 // JSON-RPC requests either to Upstream or
 // Local node.
 type router struct {
+	rules           []routingRule // configured rules, checked before the default methods list
 	methods         map[string]bool
 	upstreamEnabled bool
 }
 
-// newRouter inits new router.
-func newRouter(upstreamEnabled bool) *router {
+// newRouter inits new router. Methods are routed by configured rules first,
+// then by the default methods list, if upstream is enabled.
+func newRouter(upstreamEnabled bool, routing []string) (*router, error) {
 	r := &router{
 		methods:         make(map[string]bool),
 		upstreamEnabled: upstreamEnabled,
@@ -19,11 +47,47 @@ func newRouter(upstreamEnabled bool) *router {
 		r.methods[m] = true
 	}
 
-	return r
+	for _, rule := range routing {
+		parsed, err := parseRoutingRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		r.rules = append(r.rules, parsed)
+	}
+
+	return r, nil
+}
+
+// parseRoutingRule parses rule in format "method=local" or "method=upstream".
+func parseRoutingRule(rule string) (routingRule, error) {
+	parts := strings.SplitN(rule, "=", 2)
+	if len(parts) != 2 {
+		return routingRule{}, ErrInvalidRoutingRule
+	}
+
+	pattern := strings.TrimSpace(parts[0])
+	if pattern == "" || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+		return routingRule{}, ErrInvalidRoutingRule
+	}
+
+	switch strings.TrimSpace(parts[1]) {
+	case params.UpstreamRouteLocal:
+		return routingRule{pattern: pattern, remote: false}, nil
+	case params.UpstreamRouteUpstream:
+		return routingRule{pattern: pattern, remote: true}, nil
+	}
+
+	return routingRule{}, ErrInvalidRoutingRule
 }
 
 // routeRemote returns true if given method should be routed to the remote node
 func (r *router) routeRemote(method string) bool {
+	for _, rule := range r.rules {
+		if rule.match(method) {
+			return rule.remote
+		}
+	}
+
 	if !r.upstreamEnabled {
 		return false
 	}
@@ -32,6 +96,21 @@ func (r *router) routeRemote(method string) bool {
 	return r.methods[method]
 }
 
+// usesUpstream returns true if any method may be routed to the remote node.
+func (r *router) usesUpstream() bool {
+	if r.upstreamEnabled {
+		return true
+	}
+
+	for _, rule := range r.rules {
+		if rule.remote {
+			return true
+		}
+	}
+
+	return false
+}
+
 // remoteMethods contains methods that should be routed to
 // the upstream node; the rest is considered to be routed to
 // the local node.
@@ -89,4 +168,6 @@ var remoteMethods = [...]string{
 	"net_version",
 	"net_peerCount",
 	"net_listening",
+	"eth_subscribe",   // requires params.UpstreamRPCConfig.WebSocketURL
+	"eth_unsubscribe", // requires params.UpstreamRPCConfig.WebSocketURL
 }
@@ -10,7 +10,8 @@ import (
 var localTestMethods = []string{"some_weirdo_method", "shh_newMessageFilter", "eth_accounts"}
 
 func TestRouteWithUpstream(t *testing.T) {
-	router := newRouter(true)
+	router, err := newRouter(true, nil)
+	require.NoError(t, err)
 
 	for _, method := range remoteMethods {
 		require.True(t, router.routeRemote(method), "method "+method+" should routed to remote")
@@ -24,7 +25,8 @@ func TestRouteWithUpstream(t *testing.T) {
 }
 
 func TestRouteWithoutUpstream(t *testing.T) {
-	router := newRouter(false)
+	router, err := newRouter(false, nil)
+	require.NoError(t, err)
 
 	for _, method := range remoteMethods {
 		require.False(t, router.routeRemote(method), "method "+method+" should routed to locally without UpstreamEnabled")
@@ -34,3 +36,46 @@ func TestRouteWithoutUpstream(t *testing.T) {
 		require.False(t, router.routeRemote(method), "method "+method+" should routed to local")
 	}
 }
+
+func TestRouteWithRules(t *testing.T) {
+	router, err := newRouter(true, []string{
+		"eth_getBalance=local",
+		"shh_post=upstream",
+		"eth_getBlock*=local",
+		"net_*=upstream",
+	})
+	require.NoError(t, err)
+	require.True(t, router.usesUpstream())
+
+	require.False(t, router.routeRemote("eth_getBalance"))
+	require.False(t, router.routeRemote("eth_getBlockByHash"))
+	require.False(t, router.routeRemote("eth_getBlockByNumber"))
+	require.True(t, router.routeRemote("shh_post"))
+	require.True(t, router.routeRemote("net_version"))
+	require.True(t, router.routeRemote("net_unknownMethod"))
+
+	// methods without rules are routed by default
+	require.True(t, router.routeRemote("eth_call"))
+	require.False(t, router.routeRemote("eth_accounts"))
+}
+
+func TestRouteRulesWithoutUpstream(t *testing.T) {
+	router, err := newRouter(false, []string{"eth_call=upstream", "eth_getLogs=local"})
+	require.NoError(t, err)
+	require.True(t, router.usesUpstream())
+
+	require.True(t, router.routeRemote("eth_call"))
+	require.False(t, router.routeRemote("eth_getLogs"))
+	require.False(t, router.routeRemote("eth_getBalance"))
+
+	router, err = newRouter(false, []string{"eth_*=local"})
+	require.NoError(t, err)
+	require.False(t, router.usesUpstream())
+}
+
+func TestRouteInvalidRules(t *testing.T) {
+	for _, rule := range []string{"eth_call", "eth_call=remote", "=local", "eth_*_*=local"} {
+		_, err := newRouter(true, []string{rule})
+		require.Equal(t, ErrInvalidRoutingRule, err, "rule "+rule)
+	}
+}
@@ -0,0 +1,205 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// Subscription types supported by eth_subscribe
+const (
+	SubscriptionNewHeads               = "newHeads"
+	SubscriptionLogs                   = "logs"
+	SubscriptionNewPendingTransactions = "newPendingTransactions"
+)
+
+// SubscriptionMessages is a subscription type of shh_subscribe, notifying of Whisper messages
+// matching criteria (topics, keys and PoW) passed as its argument
+const SubscriptionMessages = "messages"
+
+// subscriptionBufferSize is a number of notifications buffered for a slow handler
+const subscriptionBufferSize = 100
+
+// errors
+var (
+	ErrUnknownSubscriptionType   = errors.New("unknown subscription type")
+	ErrSubscriptionNotFound      = errors.New("subscription not found")
+	ErrSubscriptionsNotSupported = errors.New("subscriptions are not supported by upstream, WebSocketURL is not configured")
+)
+
+// SubscriptionEvent is a signal sent for every notification of a subscription.
+type SubscriptionEvent struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Result json.RawMessage `json:"result"`
+}
+
+// SubscriptionHandler receives notifications of a subscription.
+type SubscriptionHandler func(result json.RawMessage)
+
+// subscription is an active eth_subscribe subscription.
+type subscription struct {
+	id      string
+	kind    string
+	sub     *gethrpc.ClientSubscription
+	results chan json.RawMessage
+	handler SubscriptionHandler
+	quit    chan struct{}
+}
+
+// subscriptions keeps track of active subscriptions of a client.
+type subscriptions struct {
+	mu     sync.Mutex
+	active map[string]*subscription
+	wg     sync.WaitGroup
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{
+		active: make(map[string]*subscription),
+	}
+}
+
+// Subscribe creates eth_subscribe subscription of a given type ("newHeads", "logs" with optional
+// filter argument, or "newPendingTransactions"), or shh_subscribe subscription of Whisper "messages"
+// with criteria argument, either on the local node or on the upstream WebSocket endpoint, depending
+// on routing of eth_subscribe (shh_subscribe) method.
+// Every notification is delivered as EventRPCSubscription signal, and passed to handler, if it is not nil.
+// Subscription is ended with Unsubscribe, or once ctx is done.
+func (c *Client) Subscribe(ctx context.Context, handler SubscriptionHandler, kind string, args ...interface{}) (string, error) {
+	method := "eth_subscribe"
+	switch kind {
+	case SubscriptionNewHeads, SubscriptionLogs, SubscriptionNewPendingTransactions:
+	case SubscriptionMessages:
+		method = "shh_subscribe"
+	default:
+		return "", ErrUnknownSubscriptionType
+	}
+
+	client := c.local
+	if c.router.routeRemote(method) {
+		if c.upstreamWS == nil {
+			return "", ErrSubscriptionsNotSupported
+		}
+		client = c.upstreamWS
+	}
+
+	s := &subscription{
+		id:      uuid.New(),
+		kind:    kind,
+		results: make(chan json.RawMessage, subscriptionBufferSize),
+		handler: handler,
+		quit:    make(chan struct{}),
+	}
+
+	var err error
+	if method == "shh_subscribe" {
+		s.sub, err = client.ShhSubscribe(ctx, s.results, append([]interface{}{kind}, args...)...)
+	} else {
+		s.sub, err = client.EthSubscribe(ctx, s.results, append([]interface{}{kind}, args...)...)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	c.subscriptions.add(s)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.subscriptions.remove(s.id) // nolint: errcheck
+		case <-s.quit:
+		}
+	}()
+
+	return s.id, nil
+}
+
+// Unsubscribe ends subscription created with Subscribe.
+func (c *Client) Unsubscribe(id string) error {
+	return c.subscriptions.remove(id)
+}
+
+func (s *subscriptions) add(sub *subscription) {
+	s.mu.Lock()
+	s.active[sub.id] = sub
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		sub.deliver()
+	}()
+}
+
+func (s *subscriptions) remove(id string) error {
+	s.mu.Lock()
+	sub, ok := s.active[id]
+	delete(s.active, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+
+	sub.sub.Unsubscribe()
+	close(sub.quit)
+
+	return nil
+}
+
+// removeAll ends all active subscriptions, waiting for their notifications to be delivered.
+func (s *subscriptions) removeAll() {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.active))
+	for id := range s.active {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		s.remove(id) // nolint: errcheck
+	}
+	s.wg.Wait()
+}
+
+// deliver sends notifications as signals and passes them to handler, until subscription is ended.
+func (sub *subscription) deliver() {
+	for {
+		select {
+		case result := <-sub.results:
+			signal.Send(signal.Envelope{
+				Type: signal.EventRPCSubscription,
+				Event: SubscriptionEvent{
+					ID:     sub.id,
+					Type:   sub.kind,
+					Result: result,
+				},
+			})
+			if sub.handler != nil {
+				sub.handler(result)
+			}
+		case err := <-sub.sub.Err():
+			if err != nil {
+				log.Warn("Subscription failed", "id", sub.id, "type", sub.kind, "error", err)
+			}
+			return
+		case <-sub.quit:
+			return
+		}
+	}
+}
+
+// has returns true, if subscription is active.
+func (s *subscriptions) has(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.active[id]
+	return ok
+}
@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEthService is exported, as required by RPC server. It notifies subscribers of newHeads
+// periodically, as notifications are dropped by server, until subscription id is sent to client.
+type TestEthService struct{}
+
+func (s *TestEthService) NewHeads(ctx context.Context) (*gethrpc.Subscription, error) {
+	notifier, _ := gethrpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				notifier.Notify(sub.ID, map[string]hexutil.Uint64{"number": 1}) // nolint: errcheck
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// TestShhService is exported, as required by RPC server. It notifies subscribers of messages
+// matching requested topic.
+type TestShhService struct{}
+
+func (s *TestShhService) Messages(ctx context.Context, criteria map[string]interface{}) (*gethrpc.Subscription, error) {
+	notifier, _ := gethrpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				notifier.Notify(sub.ID, map[string]interface{}{"topic": criteria["topics"]}) // nolint: errcheck
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+func newTestSubscriptionsClient(t *testing.T, service *TestEthService) *Client {
+	server := gethrpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", service))
+	require.NoError(t, server.RegisterName("shh", &TestShhService{}))
+
+	router, err := newRouter(false, nil)
+	require.NoError(t, err)
+
+	return &Client{
+		local:         gethrpc.DialInProc(server),
+		router:        router,
+		subscriptions: newSubscriptions(),
+		handlers:      make(map[string]Handler),
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	client := newTestSubscriptionsClient(t, &TestEthService{})
+	defer client.Close()
+
+	signals := make(chan string, 1)
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, signal.EventRPCSubscription) {
+			select {
+			case signals <- event:
+			default:
+			}
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	results := make(chan json.RawMessage, 1)
+	id, err := client.Subscribe(context.Background(), func(result json.RawMessage) {
+		select {
+		case results <- result:
+		default:
+		}
+	}, SubscriptionNewHeads)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{"number":"0x1"}`, string(<-results))
+
+	var envelope struct {
+		Event SubscriptionEvent `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(<-signals), &envelope))
+	require.Equal(t, id, envelope.Event.ID)
+	require.Equal(t, SubscriptionNewHeads, envelope.Event.Type)
+
+	require.NoError(t, client.Unsubscribe(id))
+	require.Equal(t, ErrSubscriptionNotFound, client.Unsubscribe(id))
+}
+
+func TestSubscribeMessages(t *testing.T) {
+	client := newTestSubscriptionsClient(t, &TestEthService{})
+	defer client.Close()
+
+	results := make(chan json.RawMessage, 1)
+	id, err := client.Subscribe(context.Background(), func(result json.RawMessage) {
+		select {
+		case results <- result:
+		default:
+		}
+	}, SubscriptionMessages, map[string]interface{}{"topics": []string{"0xdeadbeef"}})
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{"topic":["0xdeadbeef"]}`, string(<-results))
+	require.NoError(t, client.Unsubscribe(id))
+}
+
+func TestSubscribeCancelledWithContext(t *testing.T) {
+	client := newTestSubscriptionsClient(t, &TestEthService{})
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	id, err := client.Subscribe(ctx, nil, SubscriptionNewHeads)
+	require.NoError(t, err)
+
+	cancel()
+	for i := 0; i < 100 && client.subscriptions.has(id); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, ErrSubscriptionNotFound, client.Unsubscribe(id))
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	client := newTestSubscriptionsClient(t, &TestEthService{})
+	defer client.Close()
+
+	_, err := client.Subscribe(context.Background(), nil, "syncing")
+	require.Equal(t, ErrUnknownSubscriptionType, err)
+
+	client.router, err = newRouter(true, nil)
+	require.NoError(t, err)
+	_, err = client.Subscribe(context.Background(), nil, SubscriptionNewHeads)
+	require.Equal(t, ErrSubscriptionsNotSupported, err)
+}
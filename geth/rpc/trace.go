@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/log"
+)
+
+type contextKey string // in order to make sure that our context key does not collide with keys from other packages
+
+// requestIDKey is a key of ID of request, calls are traced with
+const requestIDKey = contextKey("request_id")
+
+// WithRequestID returns context carrying ID of request, assigning a new one, unless context
+// carries it already. ID is included in logs of calls and signals resulting from them (e.g.
+// of queued transactions), so that UI action could be correlated with backend calls.
+func WithRequestID(ctx context.Context) (context.Context, string) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return ctx, id
+	}
+
+	id := uuid.New()
+	return context.WithValue(ctx, requestIDKey, id), id
+}
+
+// RequestIDFromContext returns ID of request from context (if exists)
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+
+	return ""
+}
+
+// traceCall logs completed call and updates timer of calls of its origin
+// ("rpc/local/duration" or "rpc/upstream/duration", if metrics are enabled).
+func traceCall(ctx context.Context, method, origin string, start time.Time, err error) {
+	duration := time.Since(start)
+	gethmetrics.NewTimer("rpc/" + origin + "/duration").Update(duration)
+
+	log.Debug("RPC call completed", "requestID", RequestIDFromContext(ctx), "method", method,
+		"origin", origin, "duration", duration, "error", err)
+}
@@ -0,0 +1,307 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// healthCheckTimeout is a timeout of a single health check request
+const healthCheckTimeout = 5 * time.Second
+
+// errors
+var (
+	ErrUnknownUpstreamStrategy = errors.New("unknown upstream selection strategy")
+	ErrNoUpstreamProviders     = errors.New("no upstream providers configured")
+)
+
+// UpstreamSwitchedEvent is a signal sent, when calls start going to another upstream provider.
+// URLs are redacted, as they may contain access tokens.
+type UpstreamSwitchedEvent struct {
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// upstreamProvider is a single upstream RPC server.
+type upstreamProvider struct {
+	url     string
+	header  http.Header // sent with every request (e.g. API key)
+	client  *gethrpc.Client
+	healthy bool
+}
+
+// upstreamPool routes calls to one of upstream providers, checks their health
+// and fails over to another provider, if the selected one is not reachable.
+type upstreamPool struct {
+	mu        sync.RWMutex
+	providers []*upstreamProvider
+	active    int // index of provider served the last call
+	strategy  string
+	next      uint32 // round-robin counter
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newUpstreamPool dials all providers configured and starts checking their health.
+// Providers, which can't be dialed, are considered unhealthy, and are re-dialed by health checks.
+func newUpstreamPool(config params.UpstreamRPCConfig) (*upstreamPool, error) {
+	strategy := config.Strategy
+	switch strategy {
+	case "":
+		strategy = params.UpstreamStrategyPriority
+	case params.UpstreamStrategyPriority, params.UpstreamStrategyRoundRobin:
+	default:
+		return nil, ErrUnknownUpstreamStrategy
+	}
+
+	p := &upstreamPool{
+		strategy: strategy,
+		quit:     make(chan struct{}),
+	}
+
+	header, err := config.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	var dialErr error
+	for _, url := range config.URLs() {
+		if url == "" {
+			continue
+		}
+
+		provider := &upstreamProvider{url: url, header: header}
+		if dialErr = provider.dial(); dialErr != nil {
+			log.Warn("Failed to dial upstream provider", "url", params.RedactURL(url), "error", dialErr)
+		}
+		p.providers = append(p.providers, provider)
+	}
+
+	if len(p.providers) == 0 {
+		return nil, ErrNoUpstreamProviders
+	}
+	// single provider has nothing to fail over to, so behave as plain client
+	if len(p.providers) == 1 {
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		return p, nil
+	}
+
+	interval := time.Duration(config.HealthCheckInterval) * time.Second
+	if interval <= 0 {
+		interval = params.UpstreamHealthCheckInterval * time.Second
+	}
+	p.wg.Add(1)
+	go p.checkHealthLoop(interval)
+
+	return p, nil
+}
+
+func (u *upstreamProvider) dial() error {
+	client, err := dialUpstream(context.Background(), u.url, u.header)
+	if err != nil {
+		return err
+	}
+
+	u.client = client
+	u.healthy = true
+
+	return nil
+}
+
+// CallContext performs a JSON-RPC call on the selected provider,
+// failing over to other providers on connectivity errors.
+func (p *upstreamPool) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	err := ErrNoUpstreamProviders
+	for i, index := range p.order() {
+		client := p.client(index)
+		if client == nil {
+			continue
+		}
+
+		err = client.CallContext(ctx, result, method, args...)
+		if !isConnectivityError(ctx, err) {
+			p.setActive(index, i > 0)
+			return err
+		}
+
+		log.Warn("Upstream call failed", "url", params.RedactURL(p.providers[index].url), "method", method, "error", err)
+		p.setHealthy(index, false)
+	}
+
+	return err
+}
+
+// Close stops health checks and closes connections to providers.
+func (p *upstreamPool) Close() {
+	close(p.quit)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, provider := range p.providers {
+		if provider.client != nil {
+			provider.client.Close()
+		}
+	}
+}
+
+// order returns indexes of providers in order they should be tried:
+// healthy ones go first (according to selection strategy), then unhealthy ones as the last resort.
+func (p *upstreamPool) order() []int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	n := len(p.providers)
+	start := 0
+	if p.strategy == params.UpstreamStrategyRoundRobin {
+		start = int(atomic.AddUint32(&p.next, 1)-1) % n
+	}
+
+	healthy := make([]int, 0, n)
+	unhealthy := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		index := (start + i) % n
+		if p.providers[index].healthy {
+			healthy = append(healthy, index)
+		} else {
+			unhealthy = append(unhealthy, index)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (p *upstreamPool) client(index int) *gethrpc.Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.providers[index].client
+}
+
+func (p *upstreamPool) setHealthy(index int, healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.providers[index].healthy = healthy
+}
+
+// setActive marks provider as healthy, and notifies application, if calls have been switched to it:
+// with priority strategy - whenever provider changes, with round-robin - when failover happens.
+func (p *upstreamPool) setActive(index int, failover bool) {
+	p.mu.Lock()
+	previous := p.active
+	p.active = index
+	p.providers[index].healthy = true
+	switched := previous != index && (failover || p.strategy == params.UpstreamStrategyPriority)
+	p.mu.Unlock()
+
+	if !switched {
+		return
+	}
+
+	event := UpstreamSwitchedEvent{
+		Previous: params.RedactURL(p.providers[previous].url),
+		Current:  params.RedactURL(p.providers[index].url),
+	}
+	log.Info("Upstream provider switched", "previous", event.Previous, "current", event.Current)
+	signal.Send(signal.Envelope{
+		Type:  signal.EventUpstreamSwitched,
+		Event: event,
+	})
+}
+
+// checkHealthLoop periodically checks health of all providers.
+func (p *upstreamPool) checkHealthLoop(interval time.Duration) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.checkHealth()
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// checkHealth requests the latest block number from every provider,
+// (re-)dialing providers which have not been dialed yet.
+func (p *upstreamPool) checkHealth() {
+	for index, provider := range p.providers {
+		p.mu.RLock()
+		client := provider.client
+		p.mu.RUnlock()
+
+		var err error
+		if client == nil {
+			client, err = dialUpstream(context.Background(), provider.url, provider.header)
+			if err == nil {
+				p.mu.Lock()
+				provider.client = client
+				p.mu.Unlock()
+			}
+		}
+
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			var blockNumber string
+			err = client.CallContext(ctx, &blockNumber, "eth_blockNumber")
+			cancel()
+		}
+
+		if err != nil {
+			log.Debug("Upstream provider is unhealthy", "url", params.RedactURL(provider.url), "error", err)
+		}
+		p.setHealthy(index, err == nil)
+	}
+}
+
+// dialUpstream connects to upstream server, sending header with requests over HTTP
+// and with handshake of WebSocket connection.
+func dialUpstream(ctx context.Context, rawURL string, header http.Header) (*gethrpc.Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return gethrpc.DialHTTPWithHeader(rawURL, header)
+	case "ws", "wss":
+		return gethrpc.DialWebsocketWithHeader(ctx, rawURL, "", header)
+	default:
+		return gethrpc.DialContext(ctx, rawURL)
+	}
+}
+
+// isConnectivityError checks whether call failed because provider is not reachable (or misbehaves),
+// rather than because request was processed and rejected, or cancelled by caller.
+func isConnectivityError(ctx context.Context, err error) bool {
+	if err == nil || err == gethrpc.ErrNoResult || ctx.Err() != nil {
+		return false
+	}
+
+	switch err.(type) {
+	case gethrpc.Error, *json.UnmarshalTypeError:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestUpstream starts JSON-RPC server responding with a given result to any request,
+// or with HTTP error, if it is marked as down.
+func newTestUpstream(result string) (*httptest.Server, func(down bool)) {
+	var mu sync.Mutex
+	var isDown bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if isDown {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+
+		var req jsonrpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(newSuccessResponse(json.RawMessage(`"`+result+`"`), req.ID))) // nolint: errcheck
+	}))
+
+	return server, func(down bool) {
+		mu.Lock()
+		isDown = down
+		mu.Unlock()
+	}
+}
+
+func TestUpstreamPoolFailover(t *testing.T) {
+	primary, setPrimaryDown := newTestUpstream("primary")
+	defer primary.Close()
+	fallback, _ := newTestUpstream("fallback")
+	defer fallback.Close()
+
+	var signals []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, signal.EventUpstreamSwitched) {
+			signals = append(signals, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{
+		URL:          primary.URL,
+		FallbackURLs: []string{fallback.URL},
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	var result string
+	require.NoError(t, pool.CallContext(context.Background(), &result, "eth_blockNumber"))
+	require.Equal(t, "primary", result)
+
+	// primary is down, calls go to fallback
+	setPrimaryDown(true)
+	require.NoError(t, pool.CallContext(context.Background(), &result, "eth_blockNumber"))
+	require.Equal(t, "fallback", result)
+	require.NoError(t, pool.CallContext(context.Background(), &result, "eth_blockNumber"))
+	require.Equal(t, "fallback", result)
+
+	// primary is back, health check returns calls to it
+	setPrimaryDown(false)
+	pool.checkHealth()
+	require.NoError(t, pool.CallContext(context.Background(), &result, "eth_blockNumber"))
+	require.Equal(t, "primary", result)
+
+	require.Len(t, signals, 2)
+}
+
+func TestUpstreamPoolRoundRobin(t *testing.T) {
+	first, _ := newTestUpstream("first")
+	defer first.Close()
+	second, _ := newTestUpstream("second")
+	defer second.Close()
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{
+		URL:          first.URL,
+		FallbackURLs: []string{second.URL},
+		Strategy:     params.UpstreamStrategyRoundRobin,
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	results := make(map[string]int)
+	for i := 0; i < 4; i++ {
+		var result string
+		require.NoError(t, pool.CallContext(context.Background(), &result, "eth_blockNumber"))
+		results[result]++
+	}
+	require.Equal(t, map[string]int{"first": 2, "second": 2}, results)
+}
+
+func TestUpstreamPoolUnknownStrategy(t *testing.T) {
+	_, err := newUpstreamPool(params.UpstreamRPCConfig{
+		URL:      "http://localhost:8545",
+		Strategy: "random",
+	})
+	require.Equal(t, ErrUnknownUpstreamStrategy, err)
+}
+
+func TestUpstreamPoolHeaders(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		if r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req jsonrpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(newSuccessResponse(json.RawMessage(`"0x1"`), req.ID))) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	pool, err := newUpstreamPool(params.UpstreamRPCConfig{
+		URL:       server.URL,
+		Headers:   []string{"X-Api-Key: secret"},
+		BasicAuth: "user:password",
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	var result string
+	require.NoError(t, pool.CallContext(context.Background(), &result, "eth_blockNumber"))
+	require.Equal(t, "0x1", result)
+	require.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", header.Get("Authorization"))
+	require.Equal(t, "application/json", header.Get("Content-Type"))
+
+	_, err = newUpstreamPool(params.UpstreamRPCConfig{
+		URL:     server.URL,
+		Headers: []string{"X-Api-Key"},
+	})
+	require.Equal(t, params.ErrInvalidUpstreamHeader, err)
+}
@@ -0,0 +1,165 @@
+package shhext
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/common/message"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// EventMessageStatusChanged is triggered when status of delivery of tracked message changes
+const EventMessageStatusChanged = "messages.status.changed"
+
+// errors
+var (
+	ErrUnknownMessage = errors.New("message is not tracked")
+)
+
+// statusOrder lets statuses of delivery only move forward
+var statusOrder = map[message.Status]int{
+	message.Pending:   0,
+	message.Queued:    1,
+	message.Sent:      2,
+	message.Delivered: 3,
+	message.Expired:   3,
+}
+
+// MessageStatusEvent is sent with EventMessageStatusChanged signal.
+type MessageStatusEvent struct {
+	ID     string         `json:"id"`
+	Status message.Status `json:"status"`
+}
+
+// DeliveryService tracks delivery of outgoing Whisper envelopes, keyed by message ID (hash of envelope):
+// envelope is queued, once it's accepted by Whisper, sent, once it's sent to a peer, and delivered, once
+// it's received back from another peer, i.e. it's propagated through network beyond direct peers.
+// Envelopes, which are not delivered before their TTL passes, are expired.
+type DeliveryService struct {
+	nodeManager common.NodeManager
+
+	mu       sync.Mutex
+	messages map[gethcommon.Hash]*trackedMessage
+	now      func() time.Time
+}
+
+// trackedMessage is an envelope, delivery of which is tracked.
+type trackedMessage struct {
+	status  message.Status
+	expired *time.Timer
+}
+
+// NewDeliveryService returns new delivery service.
+func NewDeliveryService(nodeManager common.NodeManager) *DeliveryService {
+	return &DeliveryService{
+		nodeManager: nodeManager,
+		messages:    make(map[gethcommon.Hash]*trackedMessage),
+		now:         time.Now,
+	}
+}
+
+// Start starts tracking envelopes exchanged by Whisper of the running node with its peers.
+// It is to be called whenever node is started, as Whisper service is re-created.
+func (d *DeliveryService) Start() error {
+	whisperService, err := d.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	whisperService.RegisterDeliveryTracker(d)
+
+	return nil
+}
+
+// Stop forgets tracked messages, as envelopes of a stopped node are not delivered anymore.
+func (d *DeliveryService) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for hash, msg := range d.messages {
+		msg.expired.Stop()
+		delete(d.messages, hash)
+	}
+}
+
+// Track starts tracking delivery of envelope, it is to be called before envelope is passed to Whisper.
+func (d *DeliveryService) Track(envelope *whisper.Envelope) {
+	hash := envelope.Hash()
+	ttl := time.Unix(int64(envelope.Expiry), 0).Sub(d.now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.messages[hash]; ok {
+		return
+	}
+	msg := &trackedMessage{status: message.Queued}
+	msg.expired = time.AfterFunc(ttl, func() {
+		d.update(hash, message.Expired)
+	})
+	d.messages[hash] = msg
+}
+
+// Forget stops tracking delivery of envelope, e.g. if it is not accepted by Whisper.
+func (d *DeliveryService) Forget(envelope *whisper.Envelope) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if msg, ok := d.messages[envelope.Hash()]; ok {
+		msg.expired.Stop()
+		delete(d.messages, envelope.Hash())
+	}
+}
+
+// MessageStatus returns status of delivery of tracked message, given its ID.
+func (d *DeliveryService) MessageStatus(id string) (message.Status, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	msg, ok := d.messages[gethcommon.HexToHash(id)]
+	if !ok {
+		return "", ErrUnknownMessage
+	}
+	return msg.status, nil
+}
+
+// EnvelopeSent implements whisper.DeliveryTracker interface.
+func (d *DeliveryService) EnvelopeSent(peerID []byte, hash gethcommon.Hash) {
+	d.update(hash, message.Sent)
+}
+
+// EnvelopeReceived implements whisper.DeliveryTracker interface. Peers, which envelope is sent to,
+// never send it back, so envelope is received from another peer, which it's relayed to.
+func (d *DeliveryService) EnvelopeReceived(peerID []byte, hash gethcommon.Hash) {
+	d.update(hash, message.Delivered)
+}
+
+// update moves tracked message to a given status, unless it is past it already,
+// and notifies about the change.
+func (d *DeliveryService) update(hash gethcommon.Hash, status message.Status) {
+	d.mu.Lock()
+	msg, ok := d.messages[hash]
+	if !ok || msg.status.Final() || statusOrder[status] <= statusOrder[msg.status] {
+		d.mu.Unlock()
+		return
+	}
+	msg.status = status
+	if status.Final() {
+		msg.expired.Stop()
+	}
+	d.mu.Unlock()
+
+	log.Debug("Message status changed", "id", hash.Hex(), "status", status)
+	signal.Send(signal.Envelope{
+		Type: EventMessageStatusChanged,
+		Event: MessageStatusEvent{
+			ID:     hash.Hex(),
+			Status: status,
+		},
+	})
+}
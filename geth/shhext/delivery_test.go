@@ -0,0 +1,77 @@
+package shhext
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/common/message"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().WhisperService().Return(whisper.New(nil), nil)
+	d := NewDeliveryService(nodeManager)
+	require.NoError(t, d.Start())
+	defer d.Stop()
+
+	events := make(chan MessageStatusEvent, 10)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string             `json:"type"`
+			Event MessageStatusEvent `json:"event"`
+		}
+		if err := json.Unmarshal([]byte(jsonEvent), &envelope); err == nil && envelope.Type == EventMessageStatusChanged {
+			events <- envelope.Event
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	now := time.Now()
+	envelope := &whisper.Envelope{Expiry: uint32(now.Unix()) + 60, TTL: 60, Data: []byte("hello")}
+	id := envelope.Hash().Hex()
+
+	_, err := d.MessageStatus(id)
+	require.Equal(t, ErrUnknownMessage, err)
+
+	d.Track(envelope)
+	status, err := d.MessageStatus(id)
+	require.NoError(t, err)
+	require.Equal(t, message.Queued, status)
+
+	// untracked envelopes are ignored
+	d.EnvelopeSent(nil, (&whisper.Envelope{Data: []byte("other")}).Hash())
+
+	d.EnvelopeSent(nil, envelope.Hash())
+	d.EnvelopeSent(nil, envelope.Hash()) // sent to another peer
+	d.EnvelopeReceived(nil, envelope.Hash())
+	d.EnvelopeSent(nil, envelope.Hash()) // status doesn't move backwards
+
+	require.Equal(t, MessageStatusEvent{ID: id, Status: message.Sent}, <-events)
+	require.Equal(t, MessageStatusEvent{ID: id, Status: message.Delivered}, <-events)
+	status, err = d.MessageStatus(id)
+	require.NoError(t, err)
+	require.Equal(t, message.Delivered, status)
+
+	// envelope, which is not delivered within its TTL, is expired
+	expiring := &whisper.Envelope{Expiry: uint32(now.Unix()), TTL: 60, Data: []byte("expiring")}
+	d.Track(expiring)
+	select {
+	case event := <-events:
+		require.Equal(t, MessageStatusEvent{ID: expiring.Hash().Hex(), Status: message.Expired}, event)
+	case <-time.After(time.Second):
+		t.Fatal("message is not expired")
+	}
+
+	d.Forget(expiring)
+	_, err = d.MessageStatus(expiring.Hash().Hex())
+	require.Equal(t, ErrUnknownMessage, err)
+}
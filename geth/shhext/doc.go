@@ -0,0 +1,30 @@
+/*
+Package shhext - extensions of the Whisper protocol used by Status.
+
+Package shhext implements functionality which is built on top of the
+Whisper service of the running node, but is not a part of the protocol
+itself, e.g. management of symmetric keys used by public and group chats,
+or requests of history missed since the last envelope received from mail server.
+Delivery of requested history is reported as progress of mailserver.catchup
+operation (see package progress).
+
+Messenger lets native clients send chat messages without round-tripping through JS in the jail:
+it handles encryption, topic selection and history of sent messages, posting them through Outbox.
+Payloads of one-to-one chats are encrypted within
+forward-secret sessions of package encryption, once bundle of recipient is known. DeliveryService tracks posted messages through
+statuses of package message (queued, sent, delivered or expired), reporting changes with signals.
+Outbox queues Whisper messages, which can't be posted, as no peers are connected or PoW fails,
+resending them once peers are connected, until they are sent, or maximum of retries or TTL is exceeded.
+Signed messages are held, while the account they have been posted by is not selected.
+
+WhisperSubscriber delivers decrypted messages of subscribed topics as signals and to Go handlers,
+re-installing filters of subscriptions whenever node is restarted. EnvelopeMetrics counts
+envelopes sent, received and dropped by Whisper per topic, reporting them with periodic signals.
+IngressFilterChain drops received messages before delivery, so that clients don't need to decrypt
+and discard them in JS: messages of blocked senders, too large ones, those exceeding rate limit
+of their sender, and those rejected by filters added by clients.
+
+GroupChatManager manages group chats: members share a key of SymKeyVault, and are changed by admin
+with signed membership updates, sent to each member. Key is rotated, whenever members are removed.
+*/
+package shhext
@@ -0,0 +1,132 @@
+package shhext
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+)
+
+// errors
+var (
+	ErrFilterKeyMissing = errors.New("filter must reference either symmetric key or key pair")
+	ErrFilterInvalidSig = errors.New("invalid signing public key of filter")
+)
+
+// FilterDump describes message filter installed into Whisper, referencing its keys by ids
+// (key material is never exported). It is used by support engineers to reproduce
+// subscription state of a user in a test node, when diagnosing missed messages.
+type FilterDump struct {
+	ID           string              `json:"id"`
+	Topics       []whisper.TopicType `json:"topics"`
+	SymKeyID     string              `json:"symKeyID,omitempty"`
+	PrivateKeyID string              `json:"privateKeyID,omitempty"`
+	Sig          hexutil.Bytes       `json:"sig,omitempty"` // public key of the sender filter expects
+	MinPow       float64             `json:"minPow"`
+	AllowP2P     bool                `json:"allowP2P"`
+}
+
+// ExportFilters dumps all message filters installed into Whisper, sorted by id.
+// Key ids are empty, if keys of a filter have been removed from Whisper.
+func ExportFilters(w *whisper.Whisper) []FilterDump {
+	filters := w.Filters()
+
+	dumps := make([]FilterDump, 0, len(filters))
+	for id, f := range filters {
+		dump := FilterDump{
+			ID:       id,
+			Topics:   make([]whisper.TopicType, 0, len(f.Topics)),
+			MinPow:   f.PoW,
+			AllowP2P: f.AllowP2P,
+		}
+		// filters installed with shh_newMessageFilter contain an empty topic
+		for _, topic := range f.Topics {
+			if len(topic) == whisper.TopicLength {
+				dump.Topics = append(dump.Topics, whisper.BytesToTopic(topic))
+			}
+		}
+		if f.KeySym != nil {
+			dump.SymKeyID = w.SymKeyID(f.KeySym)
+		}
+		if f.KeyAsym != nil {
+			dump.PrivateKeyID = w.KeyPairID(f.KeyAsym)
+		}
+		if f.Src != nil {
+			dump.Sig = crypto.FromECDSAPub(f.Src)
+		}
+		dumps = append(dumps, dump)
+	}
+
+	sort.Slice(dumps, func(i, j int) bool { return dumps[i].ID < dumps[j].ID })
+
+	return dumps
+}
+
+// ImportFilters installs dumped filters into Whisper, returning ids of installed filters,
+// keyed by ids of dumped ones. Keys referenced by filters must have been added to Whisper
+// under the same ids (e.g. with shh_addSymKey); either all filters are installed, or none.
+func ImportFilters(w *whisper.Whisper, dumps []FilterDump) (map[string]string, error) {
+	filters := make([]*whisper.Filter, len(dumps))
+	for i, dump := range dumps {
+		f, err := newFilterFromDump(w, dump)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %v", dump.ID, err)
+		}
+		filters[i] = f
+	}
+
+	ids := make(map[string]string, len(dumps))
+	for i, f := range filters {
+		id, err := w.Subscribe(f)
+		if err != nil {
+			for _, installed := range ids {
+				w.Unsubscribe(installed) // nolint: errcheck
+			}
+			return nil, fmt.Errorf("filter %s: %v", dumps[i].ID, err)
+		}
+		ids[dumps[i].ID] = id
+	}
+
+	return ids, nil
+}
+
+func newFilterFromDump(w *whisper.Whisper, dump FilterDump) (*whisper.Filter, error) {
+	if (dump.SymKeyID == "") == (dump.PrivateKeyID == "") {
+		return nil, ErrFilterKeyMissing
+	}
+
+	f := &whisper.Filter{
+		PoW:      dump.MinPow,
+		AllowP2P: dump.AllowP2P,
+		Topics:   make([][]byte, len(dump.Topics)),
+	}
+	for i := range dump.Topics {
+		topic := dump.Topics[i]
+		f.Topics[i] = topic[:]
+	}
+
+	if len(dump.Sig) > 0 {
+		f.Src = crypto.ToECDSAPub(dump.Sig)
+		if !whisper.ValidatePublicKey(f.Src) {
+			return nil, ErrFilterInvalidSig
+		}
+	}
+
+	var err error
+	if dump.SymKeyID != "" {
+		if f.KeySym, err = w.GetSymKey(dump.SymKeyID); err != nil {
+			return nil, err
+		}
+		f.SymKeyHash = crypto.Keccak256Hash(f.KeySym)
+	}
+	if dump.PrivateKeyID != "" {
+		if f.KeyAsym, err = w.GetPrivateKey(dump.PrivateKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
@@ -0,0 +1,65 @@
+package shhext
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportFilters(t *testing.T) {
+	source := whisper.New(nil)
+	api := whisper.NewPublicWhisperAPI(source)
+
+	symKeyID, err := source.GenerateSymKey()
+	require.NoError(t, err)
+	symKey, err := source.GetSymKey(symKeyID)
+	require.NoError(t, err)
+	sender, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	topic := whisper.BytesToTopic([]byte("test"))
+
+	filterID, err := api.NewMessageFilter(whisper.Criteria{
+		SymKeyID: symKeyID,
+		Sig:      crypto.FromECDSAPub(&sender.PublicKey),
+		MinPow:   0.5,
+		Topics:   []whisper.TopicType{topic},
+		AllowP2P: true,
+	})
+	require.NoError(t, err)
+
+	dumps := ExportFilters(source)
+	require.Equal(t, []FilterDump{{
+		ID:       filterID,
+		Topics:   []whisper.TopicType{topic},
+		SymKeyID: symKeyID,
+		Sig:      crypto.FromECDSAPub(&sender.PublicKey),
+		MinPow:   0.5,
+		AllowP2P: true,
+	}}, dumps)
+
+	// keys must be added to the target node under the same ids
+	target := whisper.New(nil)
+	_, err = ImportFilters(target, dumps)
+	require.Error(t, err)
+	require.Empty(t, target.Filters())
+
+	_, err = target.AddSymKey(symKeyID, symKey)
+	require.NoError(t, err)
+	ids, err := ImportFilters(target, dumps)
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+
+	imported := ExportFilters(target)
+	require.Len(t, imported, 1)
+	require.Equal(t, ids[filterID], imported[0].ID)
+	imported[0].ID = filterID
+	require.Equal(t, dumps, imported)
+}
+
+func TestImportFiltersRequiresSingleKey(t *testing.T) {
+	_, err := ImportFilters(whisper.New(nil), []FilterDump{{ID: "1"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrFilterKeyMissing.Error())
+}
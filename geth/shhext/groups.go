@@ -0,0 +1,516 @@
+package shhext
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/protocol"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventGroupMembershipChanged is triggered when members of group chat are changed
+	EventGroupMembershipChanged = "group.membership.changed"
+
+	// groupsDir is a directory (relative to Whisper data dir) where groups of accounts are stored
+	groupsDir = "groups"
+
+	// groupChatPrefix prefixes ids of group chats, which are also names of their keys in SymKeyVault
+	groupChatPrefix = "group/"
+)
+
+// membershipTopic is a topic, membership updates are sent to members with
+var membershipTopic = protocol.Topic("group/membership")
+
+// errors
+var (
+	ErrGroupNotFound        = errors.New("group chat not found")
+	ErrGroupNotAdmin        = errors.New("only admin can change members of group chat")
+	ErrGroupInvalidMember   = errors.New("member of group chat must be a valid public key")
+	ErrGroupInvalidUpdate   = errors.New("membership update is not signed by admin of group chat")
+	ErrGroupUnexpectedAdmin = errors.New("membership update is signed by another admin than the one of group chat")
+)
+
+// Group is a group chat: its members share symmetric key, stored in SymKeyVault under name, which is
+// chat id of the group. Members are changed by admin, who sends signed membership updates to them.
+type Group struct {
+	ChatID    string            `json:"chatId"`
+	Name      string            `json:"name"`
+	Admin     hexutil.Bytes     `json:"admin"`   // public key of admin
+	Members   []hexutil.Bytes   `json:"members"` // public keys of members, including admin
+	Version   uint64            `json:"version"` // version of membership, it's increased with every update
+	Topic     whisper.TopicType `json:"topic"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+
+	// Left is true, if the selected account has been removed from group. Group is kept,
+	// so that replayed updates of older versions couldn't add it back.
+	Left bool `json:"left,omitempty"`
+}
+
+// MembershipUpdate is sent by admin to members of group, whenever they are changed.
+// Removed members receive update without key, while the remaining ones receive a new key.
+type MembershipUpdate struct {
+	ChatID    string          `json:"chatId"`
+	Name      string          `json:"name"`
+	Admin     hexutil.Bytes   `json:"admin"`
+	Members   []hexutil.Bytes `json:"members"`
+	Version   uint64          `json:"version"`
+	Key       hexutil.Bytes   `json:"key,omitempty"`
+	Signature hexutil.Bytes   `json:"signature"` // signature of admin, made over all other fields
+}
+
+// hash returns hash of signed part of update.
+func (u MembershipUpdate) hash() ([]byte, error) {
+	u.Signature = nil
+	data, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(data), nil
+}
+
+// verify checks that update is signed by admin it names.
+func (u MembershipUpdate) verify() error {
+	hash, err := u.hash()
+	if err != nil {
+		return err
+	}
+	signer, err := crypto.SigToPub(hash, u.Signature)
+	if err != nil || !bytes.Equal(crypto.FromECDSAPub(signer), u.Admin) {
+		return ErrGroupInvalidUpdate
+	}
+	return nil
+}
+
+// GroupMembershipEvent is sent with EventGroupMembershipChanged signal.
+type GroupMembershipEvent struct {
+	Group Group `json:"group"`
+}
+
+// GroupChatManager manages group chats of the selected account: keys shared by members are kept in
+// SymKeyVault, and membership updates are received with WhisperSubscriber, sent to public keys of members.
+// Groups are persisted per account.
+type GroupChatManager struct {
+	nodeManager    common.NodeManager
+	accountManager common.AccountManager
+	symKeyVault    *SymKeyVault
+	subscriber     *WhisperSubscriber
+
+	mu     sync.Mutex
+	path   string            // path to the file of account, groups are loaded from
+	groups map[string]*Group // keyed by chat id
+	subID  string            // id of subscription to membership updates
+	clock  *protocol.Clock
+}
+
+// NewGroupChatManager returns new manager of group chats.
+func NewGroupChatManager(nodeManager common.NodeManager, accountManager common.AccountManager, symKeyVault *SymKeyVault, subscriber *WhisperSubscriber) *GroupChatManager {
+	return &GroupChatManager{
+		nodeManager:    nodeManager,
+		accountManager: accountManager,
+		symKeyVault:    symKeyVault,
+		subscriber:     subscriber,
+		clock:          protocol.NewClock(0),
+	}
+}
+
+// Start subscribes to membership updates. Subscription is kept by WhisperSubscriber across
+// restarts of node, so it's made only once.
+func (g *GroupChatManager) Start() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.subID != "" {
+		return nil
+	}
+
+	subID, err := g.subscriber.Subscribe(SubscriptionFilter{
+		Topics: []whisper.TopicType{membershipTopic},
+	}, g.handleMessage)
+	if err != nil {
+		return err
+	}
+	g.subID = subID
+
+	return nil
+}
+
+// Groups returns group chats of the selected account, including those it has been removed from.
+func (g *GroupChatManager) Groups() ([]Group, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, err := g.load(); err != nil {
+		return nil, err
+	}
+
+	groups := make([]Group, 0, len(g.groups))
+	for _, group := range g.groups {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ChatID < groups[j].ChatID })
+
+	return groups, nil
+}
+
+// CreateGroup creates group chat with the selected account as admin, generating key shared by members,
+// and sends membership update to members.
+func (g *GroupChatManager) CreateGroup(name string, members []hexutil.Bytes) (Group, error) {
+	if err := validateMembers(members); err != nil {
+		return Group{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	account, err := g.load()
+	if err != nil {
+		return Group{}, err
+	}
+
+	admin := crypto.FromECDSAPub(&account.PublicKey)
+	chatID := groupChatPrefix + uuid.New()
+	group := &Group{
+		ChatID:  chatID,
+		Name:    name,
+		Admin:   admin,
+		Members: addMembers([]hexutil.Bytes{admin}, members),
+		Topic:   protocol.Topic(chatID),
+	}
+	if _, err := g.symKeyVault.Create(chatID, []whisper.TopicType{group.Topic}); err != nil {
+		return Group{}, err
+	}
+
+	return g.update(account, group, group.Members, nil)
+}
+
+// AddMembers adds members to group chat, sending them (and existing members) membership update.
+func (g *GroupChatManager) AddMembers(chatID string, members []hexutil.Bytes) (Group, error) {
+	if err := validateMembers(members); err != nil {
+		return Group{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	account, group, err := g.adminGroup(chatID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	updated := *group
+	updated.Members = addMembers(group.Members, members)
+
+	return g.update(account, &updated, updated.Members, nil)
+}
+
+// RemoveMembers removes members from group chat. Key of group is rotated, so that removed members
+// can't read messages anymore, and the new key is sent to the remaining members.
+func (g *GroupChatManager) RemoveMembers(chatID string, members []hexutil.Bytes) (Group, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	account, group, err := g.adminGroup(chatID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	updated := *group
+	updated.Members = nil
+	var removed []hexutil.Bytes
+	for _, member := range group.Members {
+		if !bytes.Equal(member, group.Admin) && containsMember(members, member) {
+			removed = append(removed, member)
+			continue
+		}
+		updated.Members = append(updated.Members, member)
+	}
+
+	if _, err := g.symKeyVault.Rotate(chatID); err != nil {
+		return Group{}, err
+	}
+
+	return g.update(account, &updated, updated.Members, removed)
+}
+
+// ProcessUpdate applies membership update, received from admin of group chat. Key of group is stored
+// (or replaced) in SymKeyVault, or removed, if the selected account is not a member anymore.
+// Updates, which are older than the known membership, are ignored.
+func (g *GroupChatManager) ProcessUpdate(update MembershipUpdate) error {
+	if err := update.verify(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	account, err := g.load()
+	if err != nil {
+		return err
+	}
+
+	group, known := g.groups[update.ChatID]
+	if known && !bytes.Equal(group.Admin, update.Admin) {
+		return ErrGroupUnexpectedAdmin
+	}
+	if known && group.Version >= update.Version {
+		return nil
+	}
+
+	updated := &Group{
+		ChatID:    update.ChatID,
+		Name:      update.Name,
+		Admin:     update.Admin,
+		Members:   update.Members,
+		Version:   update.Version,
+		Topic:     protocol.Topic(update.ChatID),
+		UpdatedAt: time.Now(),
+	}
+	updated.Left = !containsMember(updated.Members, crypto.FromECDSAPub(&account.PublicKey)) || len(update.Key) == 0
+
+	if err := g.symKeyVault.Remove(update.ChatID); err != nil && err != ErrSymKeyNotFound {
+		return err
+	}
+	if !updated.Left {
+		if _, err := g.symKeyVault.Add(update.ChatID, update.Key, []whisper.TopicType{updated.Topic}); err != nil {
+			return err
+		}
+	}
+	g.groups[update.ChatID] = updated
+
+	if err := g.save(); err != nil {
+		return err
+	}
+
+	signal.Send(signal.Envelope{
+		Type:  EventGroupMembershipChanged,
+		Event: GroupMembershipEvent{Group: *updated},
+	})
+
+	return nil
+}
+
+// handleMessage processes membership updates, received by subscription.
+func (g *GroupChatManager) handleMessage(subID string, received WhisperMessage) {
+	msg, err := protocol.Unmarshal(received.Payload)
+	if err != nil || msg.ContentType != protocol.ContentTypeMembershipUpdate {
+		return
+	}
+	g.clock.Update(msg.Clock)
+
+	var update MembershipUpdate
+	if err := json.Unmarshal([]byte(msg.Content), &update); err != nil {
+		log.Warn("Failed to decode group membership update", "error", err)
+		return
+	}
+	if update.ChatID != msg.ChatID || !bytes.Equal(update.Admin, received.Sig) {
+		log.Warn("Group membership update is not sent by admin", "chatID", msg.ChatID)
+		return
+	}
+	if err := g.ProcessUpdate(update); err != nil {
+		log.Warn("Failed to process group membership update", "chatID", update.ChatID, "error", err)
+	}
+}
+
+// adminGroup returns group, which the selected account is admin of. Must be called with lock held.
+func (g *GroupChatManager) adminGroup(chatID string) (*ecdsa.PrivateKey, *Group, error) {
+	account, err := g.load()
+	if err != nil {
+		return nil, nil, err
+	}
+	group, ok := g.groups[chatID]
+	if !ok {
+		return nil, nil, ErrGroupNotFound
+	}
+	if !bytes.Equal(group.Admin, crypto.FromECDSAPub(&account.PublicKey)) {
+		return nil, nil, ErrGroupNotAdmin
+	}
+
+	return account, group, nil
+}
+
+// update increases version of group, stores it, and sends signed membership update to members
+// (with key of group) and to removed members (without it). Must be called with lock held.
+func (g *GroupChatManager) update(account *ecdsa.PrivateKey, group *Group, members, removed []hexutil.Bytes) (Group, error) {
+	key, err := g.symKeyVault.Export(group.ChatID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	group.Version++
+	group.UpdatedAt = time.Now()
+	g.groups[group.ChatID] = group
+	if err := g.save(); err != nil {
+		return Group{}, err
+	}
+
+	update := MembershipUpdate{
+		ChatID:  group.ChatID,
+		Name:    group.Name,
+		Admin:   group.Admin,
+		Members: group.Members,
+		Version: group.Version,
+		Key:     key.Key,
+	}
+	for _, member := range members {
+		if bytes.Equal(member, group.Admin) {
+			continue
+		}
+		if err := g.send(account, member, update); err != nil {
+			return Group{}, err
+		}
+	}
+	update.Key = nil
+	for _, member := range removed {
+		if err := g.send(account, member, update); err != nil {
+			return Group{}, err
+		}
+	}
+
+	signal.Send(signal.Envelope{
+		Type:  EventGroupMembershipChanged,
+		Event: GroupMembershipEvent{Group: *group},
+	})
+
+	return *group, nil
+}
+
+// send signs membership update and sends it to public key of member.
+func (g *GroupChatManager) send(account *ecdsa.PrivateKey, member hexutil.Bytes, update MembershipUpdate) error {
+	hash, err := update.hash()
+	if err != nil {
+		return err
+	}
+	if update.Signature, err = crypto.Sign(hash, account); err != nil {
+		return err
+	}
+	content, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	config, err := g.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+	whisperService, err := g.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	msg := protocol.NewMessage(update.ChatID, protocol.ContentTypeMembershipUpdate, string(content))
+	msg.Clock = g.clock.Tick()
+	msg.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	_, err = protocol.Send(whisperService, &whisper.MessageParams{
+		TTL:      uint32(config.WhisperConfig.TTL),
+		PoW:      config.WhisperConfig.MessagePoW(),
+		WorkTime: messageWorkTime,
+		Topic:    membershipTopic,
+		Src:      account,
+		Dst:      crypto.ToECDSAPub(member),
+	}, msg)
+
+	return err
+}
+
+// load reads groups of the selected account, unless they are loaded already, and returns its key.
+// Must be called with lock held.
+func (g *GroupChatManager) load() (*ecdsa.PrivateKey, error) {
+	account, err := g.accountManager.SelectedAccount()
+	if err != nil {
+		return nil, err
+	}
+	config, err := g.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(config.WhisperConfig.DataDir, groupsDir, strings.ToLower(account.Address.Hex())+".json")
+	if path == g.path {
+		return account.AccountKey.PrivateKey, nil
+	}
+
+	groups := make(map[string]*Group)
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		var list []*Group
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		for _, group := range list {
+			groups[group.ChatID] = group
+		}
+	}
+
+	g.path = path
+	g.groups = groups
+
+	return account.AccountKey.PrivateKey, nil
+}
+
+// save writes groups of the selected account. Must be called with lock held.
+func (g *GroupChatManager) save() error {
+	list := make([]*Group, 0, len(g.groups))
+	for _, group := range g.groups {
+		list = append(list, group)
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(g.path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(g.path, data, 0600)
+}
+
+// validateMembers checks that members are valid public keys.
+func validateMembers(members []hexutil.Bytes) error {
+	for _, member := range members {
+		if !whisper.ValidatePublicKey(crypto.ToECDSAPub(member)) {
+			return ErrGroupInvalidMember
+		}
+	}
+	return nil
+}
+
+// addMembers returns members with new ones appended, skipping duplicates.
+func addMembers(members, added []hexutil.Bytes) []hexutil.Bytes {
+	result := append([]hexutil.Bytes{}, members...)
+	for _, member := range added {
+		if !containsMember(result, member) {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// containsMember checks whether public key is one of members.
+func containsMember(members []hexutil.Bytes, member []byte) bool {
+	for _, m := range members {
+		if bytes.Equal(m, member) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package shhext
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+type groupTestAccount struct {
+	key     *ecdsa.PrivateKey
+	whisper *whisper.Whisper
+	vault   *SymKeyVault
+	groups  *GroupChatManager
+}
+
+func newGroupTestAccount(t *testing.T, ctrl *gomock.Controller, dir string) *groupTestAccount {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	whisperService := whisper.New(nil)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{DataDir: dir, TTL: params.WhisperTTL, MinimumPoW: params.WhisperMinimumPoW},
+	}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address:    address,
+		AccountKey: &keystore.Key{PrivateKey: key},
+	}, nil).AnyTimes()
+
+	vault := NewSymKeyVault(nodeManager)
+	require.NoError(t, vault.Unlock(address, key))
+
+	return &groupTestAccount{
+		key:     key,
+		whisper: whisperService,
+		vault:   vault,
+		groups:  NewGroupChatManager(nodeManager, accountManager, vault, nil),
+	}
+}
+
+func (a *groupTestAccount) publicKey() hexutil.Bytes {
+	return crypto.FromECDSAPub(&a.key.PublicKey)
+}
+
+// receiveUpdates passes membership updates, sent by admin to account, to its manager.
+func (a *groupTestAccount) receiveUpdates(t *testing.T, admin *groupTestAccount) int {
+	received := 0
+	for _, envelope := range admin.whisper.Envelopes() {
+		opened, err := envelope.OpenAsymmetric(a.key)
+		if err != nil {
+			continue
+		}
+		require.True(t, opened.Validate())
+		a.groups.handleMessage("", newWhisperMessage(opened))
+		received++
+	}
+	return received
+}
+
+func TestGroupChatManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "groups")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := newGroupTestAccount(t, ctrl, dir)
+	alice := newGroupTestAccount(t, ctrl, dir)
+	bob := newGroupTestAccount(t, ctrl, dir)
+
+	_, err = admin.groups.CreateGroup("friends", []hexutil.Bytes{{0x01}})
+	require.Equal(t, ErrGroupInvalidMember, err)
+
+	group, err := admin.groups.CreateGroup("friends", []hexutil.Bytes{alice.publicKey(), bob.publicKey()})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), group.Version)
+	require.Equal(t, []hexutil.Bytes{admin.publicKey(), alice.publicKey(), bob.publicKey()}, group.Members)
+
+	// members receive group with shared key
+	require.Equal(t, 1, alice.receiveUpdates(t, admin))
+	require.Equal(t, 1, bob.receiveUpdates(t, admin))
+	groups, err := alice.groups.Groups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, group.Members, groups[0].Members)
+	adminKey, err := admin.vault.Export(group.ChatID)
+	require.NoError(t, err)
+	aliceKey, err := alice.vault.Export(group.ChatID)
+	require.NoError(t, err)
+	require.Equal(t, adminKey.Key, aliceKey.Key)
+
+	// only admin changes members
+	_, err = alice.groups.RemoveMembers(group.ChatID, []hexutil.Bytes{bob.publicKey()})
+	require.Equal(t, ErrGroupNotAdmin, err)
+
+	// key is rotated on removal, and removed member forgets key
+	group, err = admin.groups.RemoveMembers(group.ChatID, []hexutil.Bytes{bob.publicKey()})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), group.Version)
+	require.Equal(t, []hexutil.Bytes{admin.publicKey(), alice.publicKey()}, group.Members)
+
+	alice.receiveUpdates(t, admin)
+	bob.receiveUpdates(t, admin)
+	rotatedKey, err := admin.vault.Export(group.ChatID)
+	require.NoError(t, err)
+	require.NotEqual(t, adminKey.Key, rotatedKey.Key)
+	aliceKey, err = alice.vault.Export(group.ChatID)
+	require.NoError(t, err)
+	require.Equal(t, rotatedKey.Key, aliceKey.Key)
+	_, err = bob.vault.Export(group.ChatID)
+	require.Equal(t, ErrSymKeyNotFound, err)
+	groups, err = bob.groups.Groups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.True(t, groups[0].Left)
+
+	// groups are persisted
+	admin.groups = NewGroupChatManager(admin.groups.nodeManager, admin.groups.accountManager, admin.vault, nil)
+	groups, err = admin.groups.Groups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, group.ChatID, groups[0].ChatID)
+	require.Equal(t, group.Members, groups[0].Members)
+	require.Equal(t, group.Version, groups[0].Version)
+}
+
+func TestMembershipUpdateVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "groups")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := newGroupTestAccount(t, ctrl, dir)
+	alice := newGroupTestAccount(t, ctrl, dir)
+	_, err = admin.groups.CreateGroup("friends", []hexutil.Bytes{alice.publicKey()})
+	require.NoError(t, err)
+
+	envelopes := admin.whisper.Envelopes()
+	require.Len(t, envelopes, 1)
+	opened, err := envelopes[0].OpenAsymmetric(alice.key)
+	require.NoError(t, err)
+	require.True(t, opened.Validate())
+	msg, err := protocol.Unmarshal(opened.Payload)
+	require.NoError(t, err)
+	var update MembershipUpdate
+	require.NoError(t, json.Unmarshal([]byte(msg.Content), &update))
+
+	// forged update is rejected
+	forged := update
+	forged.Members = append(forged.Members, alice.publicKey())
+	require.Equal(t, ErrGroupInvalidUpdate, alice.groups.ProcessUpdate(forged))
+
+	require.NoError(t, alice.groups.ProcessUpdate(update))
+	// replayed update is ignored
+	require.NoError(t, alice.groups.ProcessUpdate(update))
+	groups, err := alice.groups.Groups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+}
@@ -0,0 +1,358 @@
+package shhext
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/backoff"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/progress"
+)
+
+const (
+	// historyFile is a file (relative to Whisper data dir), where the last received timestamps are stored
+	historyFile = "history.json"
+
+	// historyOverlap is subtracted from the last received timestamp, as envelopes are neither
+	// sent, nor delivered by mail server in order of their timestamps
+	historyOverlap = 5 * time.Minute
+
+	// defaultHistoryWindow is requested for topics, which have no history received yet
+	defaultHistoryWindow = 24 * time.Hour
+
+	// mailServerRequestWorkTime is time (in seconds) spent on PoW of request to mail server
+	mailServerRequestWorkTime = 5
+
+	// catchUpIdleTimeout is time without envelopes of requested topic, after which catch-up with mail server
+	// is considered done, as mail server doesn't report the end of delivery
+	catchUpIdleTimeout = 10 * time.Second
+)
+
+// mailServerRequestPolicy retries requests, which fail as mail server is not connected (yet)
+var mailServerRequestPolicy = backoff.Policy{
+	Backoff:     backoff.Backoff{Base: 500 * time.Millisecond, Max: 2 * time.Second, Jitter: 0.5},
+	MaxAttempts: 4,
+}
+
+// errors
+var (
+	ErrInvalidMailServerRange = errors.New("invalid mail server request range, 'from' is after 'to'")
+	ErrCatchUpInterrupted     = errors.New("catch-up with mail server is interrupted, as node is stopped")
+)
+
+// MessagesRequest is a request of historic messages of a topic from mail server.
+type MessagesRequest struct {
+	// MailServerPeer is enode URL of mail server, which must be connected as a peer
+	MailServerPeer string `json:"mailServerPeer"`
+
+	// SymKeyID is id of Whisper symmetric key, derived from mail server password
+	SymKeyID string `json:"symKeyID"`
+
+	Topic whisper.TopicType `json:"topic"`
+
+	// From is a lower bound (unix time) of requested range. If it is 0, messages since
+	// the last received envelope of topic (with overlap margin) are requested.
+	From uint32 `json:"from"`
+
+	// To is an upper bound (unix time) of requested range. If it is 0, current time is used.
+	To uint32 `json:"to"`
+}
+
+// MailHistory keeps timestamps of the last envelopes of topics, delivered by mail servers,
+// so that catch-up requests only ask for messages missed since then. Only envelopes delivered
+// by mail servers are tracked: live envelopes, received after node reconnects, do not prove
+// that preceding ones have been received.
+type MailHistory struct {
+	nodeManager common.NodeManager
+
+	mu     sync.Mutex
+	path   string                       // empty, if history is not loaded
+	topics map[whisper.TopicType]uint32 // requested topics, with timestamps of their last received envelopes
+	dirty  bool                         // topics have not been saved since the last change
+	now    func() time.Time
+
+	catchUps map[whisper.TopicType]*catchUp // requests, envelopes of which are being delivered
+}
+
+// catchUp tracks progress of delivery of envelopes of requested range (see progress.OperationMailServerCatchUp).
+type catchUp struct {
+	tracker  *progress.Tracker
+	from, to uint32
+	idle     *time.Timer // finishes catch-up, once envelopes stop being delivered
+}
+
+// NewMailHistory returns new mail server history tracker.
+func NewMailHistory(nodeManager common.NodeManager) *MailHistory {
+	return &MailHistory{
+		nodeManager: nodeManager,
+		topics:      make(map[whisper.TopicType]uint32),
+		now:         time.Now,
+		catchUps:    make(map[whisper.TopicType]*catchUp),
+	}
+}
+
+// Start loads history of the running node and starts tracking envelopes received by Whisper.
+// It is to be called whenever node is started, as Whisper service is re-created.
+func (h *MailHistory) Start() error {
+	config, err := h.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+	whisperService, err := h.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.path = filepath.Join(config.WhisperConfig.DataDir, historyFile)
+	h.topics = make(map[whisper.TopicType]uint32)
+	h.dirty = false
+
+	data, err := ioutil.ReadFile(h.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &h.topics); err != nil {
+			return err
+		}
+	}
+
+	whisperService.RegisterEnvelopeTracker(h)
+
+	return nil
+}
+
+// Stop saves history, interrupting catch-ups in progress.
+func (h *MailHistory) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for topic, c := range h.catchUps {
+		c.idle.Stop()
+		c.tracker.Fail(ErrCatchUpInterrupted)
+		delete(h.catchUps, topic)
+	}
+
+	if err := h.save(); err != nil {
+		log.Warn("Failed to save mail server history", "error", err)
+	}
+}
+
+// EnvelopeProcessed implements whisper.EnvelopeTracker interface.
+func (h *MailHistory) EnvelopeProcessed(envelope *whisper.Envelope, isP2P bool) {
+	if !isP2P {
+		return
+	}
+
+	sent := envelope.Expiry - envelope.TTL
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	last, ok := h.topics[envelope.Topic]
+	if ok && sent > last {
+		h.topics[envelope.Topic] = sent
+		h.dirty = true
+	}
+
+	if c, ok := h.catchUps[envelope.Topic]; ok && sent >= c.from {
+		if sent >= c.to {
+			h.finishCatchUp(envelope.Topic, c)
+			return
+		}
+		c.tracker.Update("receiving", uint64(sent-c.from), uint64(c.to-c.from))
+		c.idle.Reset(catchUpIdleTimeout)
+	}
+}
+
+// LastReceived returns timestamp of the last envelope of topic, delivered by mail server.
+func (h *MailHistory) LastReceived(topic whisper.TopicType) (uint32, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	last, ok := h.topics[topic]
+	return last, ok && last > 0
+}
+
+// RequestMessages requests historic messages of topic from mail server, filling missing bounds
+// of requested range. Request is retried for a few seconds, if mail server is not connected (yet).
+// Effective request is returned.
+func (h *MailHistory) RequestMessages(req MessagesRequest) (MessagesRequest, error) {
+	mailServer, err := discover.ParseNode(req.MailServerPeer)
+	if err != nil {
+		return req, err
+	}
+
+	now := h.now()
+	if req.To == 0 {
+		req.To = uint32(now.Unix())
+	}
+	if req.From == 0 {
+		req.From = h.since(req.Topic, now)
+	}
+	if req.From > req.To {
+		return req, ErrInvalidMailServerRange
+	}
+
+	envelope, err := h.makeEnvelope(req)
+	if err != nil {
+		return req, err
+	}
+
+	whisperService, err := h.nodeManager.WhisperService()
+	if err != nil {
+		return req, err
+	}
+	err = backoff.Retry(context.Background(), mailServerRequestPolicy, func(attempt int) error {
+		err := whisperService.RequestHistoricMessages(mailServer.ID[:], envelope)
+		if err != nil {
+			log.Debug("Mail server request failed", "peer", mailServer.ID.String(), "attempt", attempt, "error", err)
+		}
+		return err
+	})
+	if err != nil {
+		return req, err
+	}
+
+	h.track(req.Topic)
+	h.startCatchUp(req)
+	log.Info("Requested mail server history", "topic", req.Topic.String(), "from", req.From, "to", req.To)
+
+	return req, nil
+}
+
+// startCatchUp starts tracking progress of delivery of requested envelopes, replacing
+// the previous request of topic.
+func (h *MailHistory) startCatchUp(req MessagesRequest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if previous, ok := h.catchUps[req.Topic]; ok {
+		h.finishCatchUp(req.Topic, previous)
+	}
+
+	c := &catchUp{
+		tracker: progress.NewTracker(progress.OperationMailServerCatchUp),
+		from:    req.From,
+		to:      req.To,
+	}
+	c.idle = time.AfterFunc(catchUpIdleTimeout, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if h.catchUps[req.Topic] == c {
+			h.finishCatchUp(req.Topic, c)
+		}
+	})
+	h.catchUps[req.Topic] = c
+	c.tracker.Update("requested", 0, uint64(req.To-req.From))
+}
+
+// finishCatchUp reports catch-up as done. Must be called with lock held.
+func (h *MailHistory) finishCatchUp(topic whisper.TopicType, c *catchUp) {
+	c.idle.Stop()
+	c.tracker.Done()
+	delete(h.catchUps, topic)
+}
+
+// since returns lower bound of request of topic's history.
+func (h *MailHistory) since(topic whisper.TopicType, now time.Time) uint32 {
+	if last, ok := h.LastReceived(topic); ok {
+		overlap := uint32(historyOverlap / time.Second)
+		if last > overlap {
+			return last - overlap
+		}
+		return 0
+	}
+
+	return uint32(now.Add(-defaultHistoryWindow).Unix())
+}
+
+// track starts tracking envelopes of requested topic, and saves history, if it has been changed.
+func (h *MailHistory) track(topic whisper.TopicType) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.topics[topic]; !ok {
+		h.topics[topic] = 0
+		h.dirty = true
+	}
+
+	if err := h.save(); err != nil {
+		log.Warn("Failed to save mail server history", "error", err)
+	}
+}
+
+// makeEnvelope creates request envelope, signed with node's key (as mail server checks
+// that request comes from peer it is sent by), and encrypted with mail server's key.
+func (h *MailHistory) makeEnvelope(req MessagesRequest) (*whisper.Envelope, error) {
+	config, err := h.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+	node, err := h.nodeManager.Node()
+	if err != nil {
+		return nil, err
+	}
+	whisperService, err := h.nodeManager.WhisperService()
+	if err != nil {
+		return nil, err
+	}
+	key, err := whisperService.GetSymKey(req.SymKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := make([]byte, 8+whisper.TopicLength)
+	binary.BigEndian.PutUint32(payload, req.From)
+	binary.BigEndian.PutUint32(payload[4:], req.To)
+	copy(payload[8:], req.Topic[:])
+
+	params := &whisper.MessageParams{
+		PoW:      config.WhisperConfig.MinimumPoW,
+		Payload:  payload,
+		KeySym:   key,
+		Src:      node.Server().PrivateKey,
+		WorkTime: mailServerRequestWorkTime,
+	}
+	message, err := whisper.NewSentMessage(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return message.Wrap(params)
+}
+
+// save writes history, if it has been changed. Must be called with lock held.
+func (h *MailHistory) save() error {
+	if !h.dirty || h.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(h.topics)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(h.path, data, 0600); err != nil {
+		return err
+	}
+
+	h.dirty = false
+	return nil
+}
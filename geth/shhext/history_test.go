@@ -0,0 +1,115 @@
+package shhext
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	whisperService := whisper.New(nil)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{DataDir: dir},
+	}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+
+	history := NewMailHistory(nodeManager)
+	require.NoError(t, history.Start())
+
+	now := time.Unix(1500000000, 0)
+	topic := whisper.BytesToTopic([]byte("test"))
+	envelope := &whisper.Envelope{Topic: topic, TTL: 10, Expiry: uint32(now.Unix()) + 10}
+
+	// untracked topics are ignored, history of a new topic starts from default window
+	history.EnvelopeProcessed(envelope, true)
+	_, ok := history.LastReceived(topic)
+	require.False(t, ok)
+	require.Equal(t, uint32(now.Add(-defaultHistoryWindow).Unix()), history.since(topic, now))
+
+	// live envelopes are ignored, as they do not prove that preceding ones have been received
+	history.track(topic)
+	history.EnvelopeProcessed(envelope, false)
+	_, ok = history.LastReceived(topic)
+	require.False(t, ok)
+
+	history.EnvelopeProcessed(envelope, true)
+	last, ok := history.LastReceived(topic)
+	require.True(t, ok)
+	require.Equal(t, uint32(now.Unix()), last)
+	require.Equal(t, uint32(now.Add(-historyOverlap).Unix()), history.since(topic, now))
+
+	// older envelopes don't move timestamp back
+	history.EnvelopeProcessed(&whisper.Envelope{Topic: topic, TTL: 10, Expiry: uint32(now.Unix())}, true)
+	last, _ = history.LastReceived(topic)
+	require.Equal(t, uint32(now.Unix()), last)
+
+	// history is persisted
+	history.Stop()
+	restored := NewMailHistory(nodeManager)
+	require.NoError(t, restored.Start())
+	last, ok = restored.LastReceived(topic)
+	require.True(t, ok)
+	require.Equal(t, uint32(now.Unix()), last)
+}
+
+func TestMailHistoryRequestRange(t *testing.T) {
+	history := NewMailHistory(nil)
+
+	_, err := history.RequestMessages(MessagesRequest{MailServerPeer: "invalid"})
+	require.Error(t, err)
+
+	_, err = history.RequestMessages(MessagesRequest{
+		MailServerPeer: "enode://a0b3c9a3d4a1e3e5b4a4a0a3a6c2f1ef51e0c8a4b8f4f8ba2a8d0e1f4f9e0f8c76a9c0e4a3b7e8f0d9c5e3a1b2c4d6e8f0a1b3c5d7e9f1a3b5c7d9e1f3a5b7c9@127.0.0.1:30303",
+		From:           20,
+		To:             10,
+	})
+	require.Equal(t, ErrInvalidMailServerRange, err)
+}
+
+func TestMailHistoryCatchUp(t *testing.T) {
+	var events []string
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		if strings.Contains(event, signal.EventOperationProgress) {
+			events = append(events, event)
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	history := NewMailHistory(nil)
+	topic := whisper.BytesToTopic([]byte("test"))
+	history.startCatchUp(MessagesRequest{Topic: topic, From: 1000, To: 2000})
+
+	// envelopes are reported relative to requested range, live ones are ignored
+	history.EnvelopeProcessed(&whisper.Envelope{Topic: topic, TTL: 10, Expiry: 1510}, true)
+	history.EnvelopeProcessed(&whisper.Envelope{Topic: topic, TTL: 10, Expiry: 1910}, false)
+	history.EnvelopeProcessed(&whisper.Envelope{Topic: topic, TTL: 10, Expiry: 2010}, true)
+
+	require.Len(t, events, 3)
+	require.Contains(t, events[0], `"operation":"mailserver.catchup","phase":"requested","percent":0`)
+	require.Contains(t, events[1], `"phase":"receiving","percent":50`)
+	require.Contains(t, events[2], `"phase":"done","percent":100`)
+	require.Empty(t, history.catchUps)
+
+	// catch-ups in progress are interrupted by stop
+	history.startCatchUp(MessagesRequest{Topic: topic, From: 1000, To: 2000})
+	history.Stop()
+	require.Len(t, events, 5)
+	require.Contains(t, events[4], `"phase":"failed"`)
+}
@@ -0,0 +1,184 @@
+package shhext
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+)
+
+// maxTrackedSenders is a number of senders, rate limit windows are kept for, before expired ones are pruned
+const maxTrackedSenders = 10000
+
+// errors
+var (
+	ErrIngressBlockedSender   = errors.New("sender of message is blocked")
+	ErrIngressPayloadTooLarge = errors.New("payload of message exceeds limit of ingress policy")
+	ErrIngressRateLimited     = errors.New("sender of message exceeds rate limit of ingress policy")
+	ErrIngressInvalidKey      = errors.New("invalid public key")
+	ErrIngressInvalidPolicy   = errors.New("limits of ingress policy cannot be negative")
+)
+
+// IngressPolicy limits messages, which are delivered to subscriptions. Zero limits are not applied.
+type IngressPolicy struct {
+	MaxPayloadSize       int `json:"maxPayloadSize"`       // in bytes
+	MaxMessagesPerSender int `json:"maxMessagesPerSender"` // number of signed messages per sender within RateLimitPeriod
+	RateLimitPeriod      int `json:"rateLimitPeriod"`      // in seconds
+}
+
+// IngressFilter decides whether received message is delivered: it returns an error, if message is to be dropped.
+type IngressFilter func(msg WhisperMessage) error
+
+// senderWindow is a number of messages of sender, received since start of rate limit period.
+type senderWindow struct {
+	start time.Time
+	count int
+}
+
+// IngressFilterChain is a chain of filters of received messages, messages dropped by any of filters
+// are not delivered to subscriptions. Messages are checked against limit of payload size, block list
+// of sender keys, added filters and rate limit of signed messages per sender, in that order.
+// Block list and policy are configured at runtime, and are not persisted.
+type IngressFilterChain struct {
+	mu      sync.Mutex
+	policy  IngressPolicy
+	blocked map[string]struct{}      // keyed by hex of public key
+	senders map[string]*senderWindow // keyed by hex of public key
+	filters []IngressFilter
+	now     func() time.Time
+}
+
+// NewIngressFilterChain returns new chain, which doesn't drop any message.
+func NewIngressFilterChain() *IngressFilterChain {
+	return &IngressFilterChain{
+		blocked: make(map[string]struct{}),
+		senders: make(map[string]*senderWindow),
+		now:     time.Now,
+	}
+}
+
+// AddBlockedKey blocks messages signed with public key.
+func (c *IngressFilterChain) AddBlockedKey(key hexutil.Bytes) error {
+	if !whisper.ValidatePublicKey(crypto.ToECDSAPub(key)) {
+		return ErrIngressInvalidKey
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.blocked[key.String()] = struct{}{}
+	return nil
+}
+
+// RemoveBlockedKey unblocks messages signed with public key.
+func (c *IngressFilterChain) RemoveBlockedKey(key hexutil.Bytes) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.blocked, key.String())
+}
+
+// BlockedKeys returns blocked public keys.
+func (c *IngressFilterChain) BlockedKeys() []hexutil.Bytes {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]hexutil.Bytes, 0, len(c.blocked))
+	for key := range c.blocked {
+		keys = append(keys, hexutil.MustDecode(key))
+	}
+	return keys
+}
+
+// SetIngressPolicy replaces limits of messages, rate limit windows of senders are reset.
+func (c *IngressFilterChain) SetIngressPolicy(policy IngressPolicy) error {
+	if policy.MaxPayloadSize < 0 || policy.MaxMessagesPerSender < 0 || policy.RateLimitPeriod < 0 {
+		return ErrIngressInvalidPolicy
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.policy = policy
+	c.senders = make(map[string]*senderWindow)
+	return nil
+}
+
+// IngressPolicy returns current limits of messages.
+func (c *IngressFilterChain) IngressPolicy() IngressPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.policy
+}
+
+// AddFilter appends filter to the chain, so that clients could drop messages by their own rules.
+func (c *IngressFilterChain) AddFilter(filter IngressFilter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.filters = append(c.filters, filter)
+}
+
+// Check returns an error, if message is to be dropped. Only messages, which pass the chain,
+// are counted against rate limit of their sender.
+func (c *IngressFilterChain) Check(msg WhisperMessage) error {
+	c.mu.Lock()
+	policy := c.policy
+	filters := c.filters
+	sender := ""
+	if len(msg.Sig) > 0 {
+		sender = msg.Sig.String()
+	}
+	_, blocked := c.blocked[sender]
+	c.mu.Unlock()
+
+	if policy.MaxPayloadSize > 0 && len(msg.Payload) > policy.MaxPayloadSize {
+		return ErrIngressPayloadTooLarge
+	}
+	if sender != "" && blocked {
+		return ErrIngressBlockedSender
+	}
+	// filters are called without lock held, so that they could configure the chain
+	for _, filter := range filters {
+		if err := filter(msg); err != nil {
+			return err
+		}
+	}
+
+	if sender == "" || policy.MaxMessagesPerSender == 0 || policy.RateLimitPeriod == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	period := time.Duration(policy.RateLimitPeriod) * time.Second
+	window, ok := c.senders[sender]
+	if !ok || now.Sub(window.start) >= period {
+		if len(c.senders) >= maxTrackedSenders {
+			c.prune(now, period)
+		}
+		window = &senderWindow{start: now}
+		c.senders[sender] = window
+	}
+	if window.count >= policy.MaxMessagesPerSender {
+		return ErrIngressRateLimited
+	}
+	window.count++
+
+	return nil
+}
+
+// prune removes expired rate limit windows. Must be called with lock held.
+func (c *IngressFilterChain) prune(now time.Time, period time.Duration) {
+	for sender, window := range c.senders {
+		if now.Sub(window.start) >= period {
+			delete(c.senders, sender)
+		}
+	}
+}
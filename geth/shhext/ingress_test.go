@@ -0,0 +1,71 @@
+package shhext
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngressFilterChain(t *testing.T) {
+	spammerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	friendKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	spammer := hexutil.Bytes(crypto.FromECDSAPub(&spammerKey.PublicKey))
+	friend := hexutil.Bytes(crypto.FromECDSAPub(&friendKey.PublicKey))
+
+	now := time.Now()
+	chain := NewIngressFilterChain()
+	chain.now = func() time.Time { return now }
+
+	// nothing is dropped by default
+	require.NoError(t, chain.Check(WhisperMessage{Sig: spammer, Payload: make([]byte, 1024)}))
+
+	// block list
+	require.Equal(t, ErrIngressInvalidKey, chain.AddBlockedKey([]byte{1, 2, 3}))
+	require.NoError(t, chain.AddBlockedKey(spammer))
+	require.Equal(t, []hexutil.Bytes{spammer}, chain.BlockedKeys())
+	require.Equal(t, ErrIngressBlockedSender, chain.Check(WhisperMessage{Sig: spammer}))
+	require.NoError(t, chain.Check(WhisperMessage{Sig: friend}))
+	require.NoError(t, chain.Check(WhisperMessage{}))
+	chain.RemoveBlockedKey(spammer)
+	require.Empty(t, chain.BlockedKeys())
+	require.NoError(t, chain.Check(WhisperMessage{Sig: spammer}))
+
+	// limits of policy
+	require.Equal(t, ErrIngressInvalidPolicy, chain.SetIngressPolicy(IngressPolicy{MaxPayloadSize: -1}))
+	policy := IngressPolicy{MaxPayloadSize: 100, MaxMessagesPerSender: 2, RateLimitPeriod: 60}
+	require.NoError(t, chain.SetIngressPolicy(policy))
+	require.Equal(t, policy, chain.IngressPolicy())
+
+	require.Equal(t, ErrIngressPayloadTooLarge, chain.Check(WhisperMessage{Sig: friend, Payload: make([]byte, 101)}))
+	require.NoError(t, chain.Check(WhisperMessage{Sig: spammer, Payload: make([]byte, 100)}))
+	require.NoError(t, chain.Check(WhisperMessage{Sig: spammer}))
+	require.Equal(t, ErrIngressRateLimited, chain.Check(WhisperMessage{Sig: spammer}))
+
+	// dropped messages don't count against rate limit, unsigned messages are not rate limited
+	require.NoError(t, chain.Check(WhisperMessage{Sig: friend}))
+	require.NoError(t, chain.Check(WhisperMessage{Sig: friend}))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, chain.Check(WhisperMessage{}))
+	}
+
+	// rate limit is reset after period
+	now = now.Add(time.Minute)
+	require.NoError(t, chain.Check(WhisperMessage{Sig: spammer}))
+
+	// added filters
+	errRejected := errors.New("rejected")
+	chain.AddFilter(func(msg WhisperMessage) error {
+		if string(msg.Payload) == "spam" {
+			return errRejected
+		}
+		return nil
+	})
+	require.Equal(t, errRejected, chain.Check(WhisperMessage{Sig: friend, Payload: []byte("spam")}))
+	require.NoError(t, chain.Check(WhisperMessage{Sig: friend, Payload: []byte("hello")}))
+}
@@ -0,0 +1,395 @@
+package shhext
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/common/message"
+	"github.com/status-im/status-go/geth/encryption"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/protocol"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventMessageSent is triggered when chat message, sent with SendChatMessage, is posted to Whisper
+	EventMessageSent = "messages.sent"
+
+	// messagesFile is a file (relative to Whisper data dir), where sent chat messages are stored
+	messagesFile = "messages.json"
+
+	// messageWorkTime is time (in seconds) spent on PoW of chat message
+	messageWorkTime = 1
+)
+
+// errors
+var (
+	ErrUnknownChat   = errors.New("chat is neither a public key, nor a name of symmetric key")
+	ErrEmptyMessage  = errors.New("message content cannot be empty")
+	ErrMessageSender = errors.New("message has been sent by another account than the selected one")
+)
+
+// ChatMessage is a message sent with Messenger.
+type ChatMessage struct {
+	ID          string               `json:"id"` // hash of posted envelope, or random id, if it's not posted yet
+	ChatID      string               `json:"chatId"`
+	ContentType protocol.ContentType `json:"contentType"`
+	Content     string               `json:"content"`
+	ReplyTo     string               `json:"replyTo,omitempty"`
+	From        hexutil.Bytes        `json:"from"` // public key of sender
+	Topic       whisper.TopicType    `json:"topic"`
+	Clock       uint64               `json:"clock"`     // Lamport clock value, see protocol.Clock
+	Timestamp   int64                `json:"timestamp"` // unix time, in milliseconds
+	Status      message.Status       `json:"status"`
+}
+
+// protocolMessage returns message in wire format of package protocol.
+func (msg *ChatMessage) protocolMessage() protocol.Message {
+	contentType := msg.ContentType
+	if contentType == "" {
+		// messages stored before content types were introduced
+		contentType = protocol.ContentTypeText
+	}
+
+	pm := protocol.NewMessage(msg.ChatID, contentType, msg.Content)
+	pm.ReplyTo = msg.ReplyTo
+	pm.Clock = msg.Clock
+	pm.Timestamp = msg.Timestamp
+	return pm
+}
+
+// encryptedPayload is a payload of Whisper message, carrying chat payload encrypted within
+// session of encryption service.
+type encryptedPayload struct {
+	Encrypted *encryption.EncryptedMessage `json:"encrypted"`
+}
+
+// Messenger sends chat messages directly from Go, so that native clients don't need to
+// round-trip through JavaScript in the jail. Messages are signed with the selected account's key,
+// and encrypted either with public key of recipient (one-to-one chats, chatID is hex-encoded
+// public key), or with a named key of SymKeyVault (public and group chats, chatID is name of key).
+// Payloads of one-to-one chats are additionally encrypted within session of encryption service,
+// if it's given and there's a session with recipient, or its bundle to start one.
+// Messages are posted through Outbox, which resends them, if they can't be posted right away;
+// Messenger only keeps history of sent messages, and updates their status along with Outbox.
+// Delivery of posted messages is tracked with DeliveryService, if it's given.
+type Messenger struct {
+	nodeManager    common.NodeManager
+	accountManager common.AccountManager
+	symKeyVault    *SymKeyVault
+	delivery       *DeliveryService
+	encryption     *encryption.Service
+	outbox         *Outbox
+
+	mu       sync.Mutex
+	messages map[string]*ChatMessage // keyed by id
+	clock    *protocol.Clock
+	loaded   bool
+	now      func() time.Time
+}
+
+// NewMessenger returns new messenger, which posts messages through a given outbox.
+func NewMessenger(nodeManager common.NodeManager, accountManager common.AccountManager, symKeyVault *SymKeyVault, delivery *DeliveryService, encryptionService *encryption.Service, outbox *Outbox) *Messenger {
+	m := &Messenger{
+		nodeManager:    nodeManager,
+		accountManager: accountManager,
+		symKeyVault:    symKeyVault,
+		delivery:       delivery,
+		encryption:     encryptionService,
+		outbox:         outbox,
+		messages:       make(map[string]*ChatMessage),
+		clock:          protocol.NewClock(0),
+		now:            time.Now,
+	}
+	outbox.AddStatusHandler(m.outboxStatus)
+
+	return m
+}
+
+// Start loads sent messages. It is to be called whenever node is started.
+func (m *Messenger) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.load()
+}
+
+// SendChatMessage sends message to a given chat, replying to another message (if replyTo is not empty).
+// If message can't be posted (e.g. there are no peers), it is kept as pending, while Outbox resends it.
+func (m *Messenger) SendChatMessage(chatID, content, replyTo string) (ChatMessage, error) {
+	if content == "" {
+		return ChatMessage{}, ErrEmptyMessage
+	}
+
+	account, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		return ChatMessage{}, err
+	}
+	post, err := m.outboxMessage(chatID)
+	if err != nil {
+		return ChatMessage{}, err
+	}
+
+	m.mu.Lock()
+	if err := m.load(); err != nil {
+		m.mu.Unlock()
+		return ChatMessage{}, err
+	}
+	msg := &ChatMessage{
+		ID:          uuid.New(),
+		ChatID:      chatID,
+		ContentType: protocol.ContentTypeText,
+		Content:     content,
+		ReplyTo:     replyTo,
+		From:        crypto.FromECDSAPub(&account.AccountKey.PrivateKey.PublicKey),
+		Topic:       post.Topic,
+		Clock:       m.clock.Tick(),
+		Timestamp:   m.now().UnixNano() / int64(time.Millisecond),
+		Status:      message.Pending,
+	}
+	post.ID = msg.ID
+	post.Payload, err = m.payload(msg, post.PublicKey)
+	if err == nil {
+		m.messages[msg.ID] = msg
+		err = m.save()
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return ChatMessage{}, err
+	}
+
+	// message is posted without lock held, as its status is updated by outboxStatus meanwhile
+	posted, err := m.outbox.Post(post)
+	if err != nil {
+		m.mu.Lock()
+		delete(m.messages, msg.ID)
+		if err := m.save(); err != nil {
+			log.Warn("Failed to save chat messages", "error", err)
+		}
+		m.mu.Unlock()
+		return ChatMessage{}, err
+	}
+	if posted.Status == ResendQueued {
+		log.Warn("Failed to post chat message, it is kept as pending", "chatID", chatID, "error", posted.Error)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := msg.ID
+	if posted.Status == ResendSent {
+		id = posted.Hash
+	}
+	if current, ok := m.messages[id]; ok {
+		return *current, nil
+	}
+	return *msg, nil
+}
+
+// Messages returns sent messages of a given chat, ordered by time, with their current status of delivery.
+func (m *Messenger) Messages(chatID string) ([]ChatMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+
+	messages := make([]ChatMessage, 0)
+	for _, msg := range m.messages {
+		if msg.ChatID != chatID {
+			continue
+		}
+		if m.delivery != nil && msg.Status != message.Pending {
+			if status, err := m.delivery.MessageStatus(msg.ID); err == nil {
+				msg.Status = status
+			}
+		}
+		messages = append(messages, *msg)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Timestamp < messages[j].Timestamp })
+
+	return messages, nil
+}
+
+// outboxStatus updates status of chat message, whenever status of its message of outbox is changed.
+func (m *Messenger) outboxStatus(posted OutboxMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.load(); err != nil {
+		log.Warn("Failed to load chat messages", "error", err)
+		return
+	}
+
+	msg, ok := m.messages[posted.ID]
+	if !ok {
+		// not a chat message, or it's re-keyed already
+		return
+	}
+
+	switch posted.Status {
+	case ResendSent:
+		// message is re-keyed by hash of envelope, so that it could be matched with received one
+		delete(m.messages, msg.ID)
+		msg.ID = posted.Hash
+		msg.Status = message.Queued
+		m.messages[msg.ID] = msg
+
+		signal.Send(signal.Envelope{
+			Type:  EventMessageSent,
+			Event: *msg,
+		})
+	case ResendFailed:
+		msg.Status = message.Expired
+	default:
+		return
+	}
+
+	if err := m.save(); err != nil {
+		log.Warn("Failed to save chat messages", "error", err)
+	}
+}
+
+// payload returns payload of Whisper message, which carries chat message. Payloads of
+// one-to-one chats are encrypted within session, if there is one. Must be called with lock held,
+// as encryption is done once, before message is posted.
+func (m *Messenger) payload(msg *ChatMessage, dst hexutil.Bytes) ([]byte, error) {
+	payload, err := protocol.Marshal(msg.protocolMessage())
+	if err != nil {
+		return nil, err
+	}
+	if len(dst) == 0 || m.encryption == nil {
+		return payload, nil
+	}
+
+	key := crypto.ToECDSAPub(dst)
+	if !m.encryption.CanEncrypt(key) {
+		return payload, nil
+	}
+	encrypted, err := m.encryption.Encrypt(key, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encryptedPayload{Encrypted: encrypted})
+}
+
+// outboxMessage selects encryption key and topic of chat.
+func (m *Messenger) outboxMessage(chatID string) (OutboxMessage, error) {
+	msg := OutboxMessage{
+		Topic: protocol.Topic(chatID),
+		Sign:  true,
+	}
+
+	if dst := recipientKey(chatID); dst != nil {
+		msg.PublicKey = crypto.FromECDSAPub(dst)
+		return msg, nil
+	}
+
+	if m.symKeyVault == nil {
+		return OutboxMessage{}, ErrUnknownChat
+	}
+	_, topics, err := m.symKeyVault.key(chatID)
+	if err == ErrSymKeyNotFound {
+		return OutboxMessage{}, ErrUnknownChat
+	}
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	msg.SymKey = chatID
+	if len(topics) > 0 {
+		msg.Topic = topics[0]
+	}
+
+	return msg, nil
+}
+
+// recipientKey returns public key of one-to-one chat, or nil, if chatID is not a public key.
+func recipientKey(chatID string) *ecdsa.PublicKey {
+	data, err := hexutil.Decode(chatID)
+	if err != nil {
+		return nil
+	}
+	key := crypto.ToECDSAPub(data)
+	if !whisper.ValidatePublicKey(key) {
+		return nil
+	}
+
+	return key
+}
+
+// path returns path to the file, messages are stored in.
+func (m *Messenger) path() (string, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(config.WhisperConfig.DataDir, messagesFile), nil
+}
+
+// load reads stored messages, unless they are loaded already. Must be called with lock held.
+func (m *Messenger) load() error {
+	if m.loaded {
+		return nil
+	}
+
+	path, err := m.path()
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		var messages []*ChatMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			m.messages[msg.ID] = msg
+			m.clock.Update(msg.Clock)
+		}
+	}
+
+	m.loaded = true
+	return nil
+}
+
+// save writes messages. Must be called with lock held.
+func (m *Messenger) save() error {
+	path, err := m.path()
+	if err != nil {
+		return err
+	}
+
+	messages := make([]*ChatMessage, 0, len(m.messages))
+	for _, msg := range m.messages {
+		messages = append(messages, msg)
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
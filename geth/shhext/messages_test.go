@@ -0,0 +1,166 @@
+package shhext
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/common/message"
+	"github.com/status-im/status-go/geth/encryption"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessenger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messages")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	senderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	recipientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chatID := hexutil.Encode(crypto.FromECDSAPub(&recipientKey.PublicKey))
+
+	whisperService := whisper.New(nil)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{DataDir: dir, TTL: params.WhisperTTL, MinimumPoW: params.WhisperMinimumPoW},
+	}, nil).AnyTimes()
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		AccountKey: &keystore.Key{PrivateKey: senderKey},
+	}, nil).AnyTimes()
+
+	peers := 0
+	outbox := NewOutbox(nodeManager, accountManager, nil, nil)
+	outbox.peerCount = func() (int, error) { return peers, nil }
+	messenger := NewMessenger(nodeManager, accountManager, nil, nil, nil, outbox)
+
+	// message is kept as pending, while there are no peers
+	msg, err := messenger.SendChatMessage(chatID, "hello", "")
+	require.NoError(t, err)
+	require.Equal(t, message.Pending, msg.Status)
+	queued, err := outbox.Message(msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, ResendQueued, queued.Status)
+
+	_, err = messenger.SendChatMessage("unknown", "hello", "")
+	require.Equal(t, ErrUnknownChat, err)
+
+	// pending message is resent by outbox, encrypted to recipient
+	peers = 1
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	require.NoError(t, outbox.Resend())
+
+	messages, err := messenger.Messages(chatID)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, message.Queued, messages[0].Status)
+	require.Equal(t, protocol.Topic(chatID), messages[0].Topic)
+
+	envelopes := whisperService.Envelopes()
+	require.Len(t, envelopes, 1)
+	require.Equal(t, envelopes[0].Hash().Hex(), messages[0].ID)
+
+	received, err := envelopes[0].OpenAsymmetric(recipientKey)
+	require.NoError(t, err)
+	require.True(t, received.Validate())
+	require.Equal(t, senderKey.PublicKey, *received.SigToPubKey())
+
+	payload, err := protocol.Unmarshal(received.Payload)
+	require.NoError(t, err)
+	require.Equal(t, protocol.Message{
+		Version:     protocol.Version,
+		ContentType: protocol.ContentTypeText,
+		Content:     "hello",
+		ChatID:      chatID,
+		Clock:       1,
+		Timestamp:   msg.Timestamp,
+	}, payload)
+
+	// replies are posted immediately
+	reply, err := messenger.SendChatMessage(chatID, "reply", messages[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, message.Queued, reply.Status)
+	require.Equal(t, uint64(2), reply.Clock)
+	messages, err = messenger.Messages(chatID)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	// sent messages are loaded by a new messenger
+	messenger = NewMessenger(nodeManager, accountManager, nil, nil, nil, NewOutbox(nodeManager, accountManager, nil, nil))
+	require.NoError(t, messenger.Start())
+	messages, err = messenger.Messages(chatID)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, reply.ID, messages[1].ID)
+}
+
+func TestMessengerEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messages")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	senderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	recipientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chatID := hexutil.Encode(crypto.FromECDSAPub(&recipientKey.PublicKey))
+
+	whisperService := whisper.New(nil)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{DataDir: dir, TTL: params.WhisperTTL, MinimumPoW: params.WhisperMinimumPoW},
+	}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		AccountKey: &keystore.Key{PrivateKey: senderKey},
+	}, nil).AnyTimes()
+
+	senderEncryption := encryption.New(nodeManager)
+	require.NoError(t, senderEncryption.Unlock(crypto.PubkeyToAddress(senderKey.PublicKey), senderKey))
+	recipientEncryption := encryption.New(nodeManager)
+	require.NoError(t, recipientEncryption.Unlock(crypto.PubkeyToAddress(recipientKey.PublicKey), recipientKey))
+	bundle, err := recipientEncryption.Bundle()
+	require.NoError(t, err)
+	require.NoError(t, senderEncryption.ProcessBundle(bundle))
+
+	outbox := NewOutbox(nodeManager, accountManager, nil, nil)
+	outbox.peerCount = func() (int, error) { return 1, nil }
+	messenger := NewMessenger(nodeManager, accountManager, nil, nil, senderEncryption, outbox)
+	msg, err := messenger.SendChatMessage(chatID, "hello", "")
+	require.NoError(t, err)
+
+	envelopes := whisperService.Envelopes()
+	require.Len(t, envelopes, 1)
+	received, err := envelopes[0].OpenAsymmetric(recipientKey)
+	require.NoError(t, err)
+	require.True(t, received.Validate())
+
+	var encrypted encryptedPayload
+	require.NoError(t, json.Unmarshal(received.Payload, &encrypted))
+	require.NotNil(t, encrypted.Encrypted)
+	plaintext, err := recipientEncryption.Decrypt(received.SigToPubKey(), encrypted.Encrypted)
+	require.NoError(t, err)
+
+	payload, err := protocol.Unmarshal(plaintext)
+	require.NoError(t, err)
+	require.Equal(t, "hello", payload.Content)
+	require.Equal(t, msg.Clock, payload.Clock)
+}
@@ -0,0 +1,168 @@
+package shhext
+
+import (
+	"sync"
+	"time"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventWhisperStats is triggered periodically with envelope metrics of Whisper
+	EventWhisperStats = "whisper.stats"
+
+	// statsInterval is an interval, EventWhisperStats signals are sent at
+	statsInterval = time.Minute
+
+	// maxStatsTopics limits number of topics, metrics are kept for (envelopes of other topics are
+	// only counted in totals), as topics of relayed envelopes are not bounded
+	maxStatsTopics = 1000
+)
+
+// TopicStats counts envelopes of topic: sent to and received from peers (once per peer), dropped
+// as expired or with insufficient PoW, along with bandwidth consumed by sent and received envelopes.
+type TopicStats struct {
+	Sent          uint64 `json:"sent"`
+	Received      uint64 `json:"received"`
+	Expired       uint64 `json:"expired"`
+	LowPoW        uint64 `json:"lowPoW"`
+	BytesSent     uint64 `json:"bytesSent"`
+	BytesReceived uint64 `json:"bytesReceived"`
+}
+
+// add counts envelope event.
+func (s *TopicStats) add(event whisper.EnvelopeEvent, size int) {
+	switch event {
+	case whisper.EnvelopeSent:
+		s.Sent++
+		s.BytesSent += uint64(size)
+	case whisper.EnvelopeReceived:
+		s.Received++
+		s.BytesReceived += uint64(size)
+	case whisper.EnvelopeExpired:
+		s.Expired++
+	case whisper.EnvelopeLowPoW:
+		s.LowPoW++
+	}
+}
+
+// WhisperStats is a snapshot of envelope metrics, it's sent with EventWhisperStats signal.
+type WhisperStats struct {
+	Since  time.Time                        `json:"since"` // start of node, metrics are collected since
+	Total  TopicStats                       `json:"total"`
+	Topics map[whisper.TopicType]TopicStats `json:"topics"`
+}
+
+// EnvelopeMetrics collects per-topic metrics of envelopes of Whisper, in order to diagnose
+// reports of messages, which don't arrive. Metrics are reset, whenever node is started.
+type EnvelopeMetrics struct {
+	nodeManager common.NodeManager
+	interval    time.Duration
+
+	mu     sync.Mutex
+	since  time.Time
+	total  TopicStats
+	topics map[whisper.TopicType]*TopicStats
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewEnvelopeMetrics returns new collector of envelope metrics.
+func NewEnvelopeMetrics(nodeManager common.NodeManager) *EnvelopeMetrics {
+	return &EnvelopeMetrics{
+		nodeManager: nodeManager,
+		interval:    statsInterval,
+		topics:      make(map[whisper.TopicType]*TopicStats),
+	}
+}
+
+// Start starts collecting metrics of Whisper of the running node, and sending them periodically
+// as signals. It is to be called whenever node is started, as Whisper service is re-created.
+func (m *EnvelopeMetrics) Start() error {
+	whisperService, err := m.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	m.Stop()
+
+	m.mu.Lock()
+	m.since = time.Now()
+	m.total = TopicStats{}
+	m.topics = make(map[whisper.TopicType]*TopicStats)
+	m.quit = make(chan struct{})
+	m.wg.Add(1)
+	go m.loop(m.quit)
+	m.mu.Unlock()
+
+	whisperService.RegisterMetricsTracker(m)
+
+	return nil
+}
+
+// Stop stops sending metrics signals.
+func (m *EnvelopeMetrics) Stop() {
+	m.mu.Lock()
+	if m.quit != nil {
+		close(m.quit)
+		m.quit = nil
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// EnvelopeEvent implements whisper.MetricsTracker interface.
+func (m *EnvelopeMetrics) EnvelopeEvent(event whisper.EnvelopeEvent, topic whisper.TopicType, size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total.add(event, size)
+	stats, ok := m.topics[topic]
+	if !ok {
+		if len(m.topics) >= maxStatsTopics {
+			return
+		}
+		stats = &TopicStats{}
+		m.topics[topic] = stats
+	}
+	stats.add(event, size)
+}
+
+// WhisperStats returns snapshot of metrics.
+func (m *EnvelopeMetrics) WhisperStats() WhisperStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := WhisperStats{
+		Since:  m.since,
+		Total:  m.total,
+		Topics: make(map[whisper.TopicType]TopicStats, len(m.topics)),
+	}
+	for topic, topicStats := range m.topics {
+		stats.Topics[topic] = *topicStats
+	}
+
+	return stats
+}
+
+// loop periodically sends metrics signals, until quit is closed.
+func (m *EnvelopeMetrics) loop(quit chan struct{}) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			signal.Send(signal.Envelope{
+				Type:  EventWhisperStats,
+				Event: m.WhisperStats(),
+			})
+		case <-quit:
+			return
+		}
+	}
+}
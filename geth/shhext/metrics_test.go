@@ -0,0 +1,62 @@
+package shhext
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().WhisperService().Return(whisper.New(nil), nil)
+	metrics := NewEnvelopeMetrics(nodeManager)
+	metrics.interval = 10 * time.Millisecond
+
+	events := make(chan WhisperStats, 10)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string       `json:"type"`
+			Event WhisperStats `json:"event"`
+		}
+		if err := json.Unmarshal([]byte(jsonEvent), &envelope); err == nil && envelope.Type == EventWhisperStats {
+			events <- envelope.Event
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	require.NoError(t, metrics.Start())
+	defer metrics.Stop()
+
+	abcd := whisper.BytesToTopic([]byte("abcd"))
+	efgh := whisper.BytesToTopic([]byte("efgh"))
+	metrics.EnvelopeEvent(whisper.EnvelopeSent, abcd, 100)
+	metrics.EnvelopeEvent(whisper.EnvelopeReceived, abcd, 200)
+	metrics.EnvelopeEvent(whisper.EnvelopeReceived, efgh, 300)
+	metrics.EnvelopeEvent(whisper.EnvelopeExpired, efgh, 300)
+	metrics.EnvelopeEvent(whisper.EnvelopeLowPoW, efgh, 300)
+
+	stats := metrics.WhisperStats()
+	require.Equal(t, TopicStats{Sent: 1, Received: 2, Expired: 1, LowPoW: 1, BytesSent: 100, BytesReceived: 500}, stats.Total)
+	require.Equal(t, map[whisper.TopicType]TopicStats{
+		abcd: {Sent: 1, Received: 1, BytesSent: 100, BytesReceived: 200},
+		efgh: {Received: 1, Expired: 1, LowPoW: 1, BytesReceived: 300},
+	}, stats.Topics)
+
+	// metrics are sent periodically with signals
+	select {
+	case event := <-events:
+		require.Equal(t, stats.Total, event.Total)
+		require.Equal(t, stats.Topics, event.Topics)
+	case <-time.After(time.Second):
+		t.Fatal("metrics are not sent")
+	}
+}
@@ -0,0 +1,556 @@
+package shhext
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/backoff"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventOutboxMessageStatus is triggered when status of message of outbox is changed
+	EventOutboxMessageStatus = "outbox.message.status"
+
+	// outboxQueueFile is a file (relative to Whisper data dir), where messages of outbox are stored
+	outboxQueueFile = "queue.json"
+)
+
+// outboxResendPolicy spaces attempts to resend queued messages: interval grows while messages stay queued
+// (e.g. while there are no peers), and is jittered, so that clients reconnected at once don't resend at once
+var outboxResendPolicy = backoff.Policy{
+	Backoff: backoff.Backoff{Base: 5 * time.Second, Max: time.Minute, Jitter: 0.5},
+}
+
+// errors
+var (
+	ErrOutboxNoKey           = errors.New("message must be encrypted either with public key or with symmetric key")
+	ErrOutboxUnknownMessage  = errors.New("message not found in outbox")
+	ErrOutboxMessageExists   = errors.New("message with the same id is in outbox already")
+	ErrOutboxNoPeers         = errors.New("no peers connected")
+	ErrOutboxMessageExpired  = errors.New("message has not been sent within TTL of outbox")
+	ErrOutboxMessageNotFinal = errors.New("only sent and failed messages can be discarded")
+)
+
+// ResendStatus is a state of message of outbox.
+type ResendStatus string
+
+// States of messages of outbox, only queued messages are (re)sent
+const (
+	ResendQueued ResendStatus = "queued" // waiting for peers, or for retry after failed attempt
+	ResendSent   ResendStatus = "sent"   // posted to Whisper
+	ResendFailed ResendStatus = "failed" // maximum number of retries or TTL of outbox is exceeded
+)
+
+// OutboxMessage is a Whisper message, posted through Outbox.
+type OutboxMessage struct {
+	ID        string            `json:"id"` // random id, unless it's given by poster
+	Topic     whisper.TopicType `json:"topic"`
+	Payload   hexutil.Bytes     `json:"payload"`
+	PublicKey hexutil.Bytes     `json:"publicKey,omitempty"` // public key of recipient
+	SymKey    string            `json:"symKey,omitempty"`    // name of key of SymKeyVault
+	Sign      bool              `json:"sign"`                // sign with key of the account selected on post
+	From      hexutil.Bytes     `json:"from,omitempty"`      // public key of signer, set on post
+	Status    ResendStatus      `json:"status"`
+	Retries   int               `json:"retries"`         // number of failed attempts to send message
+	Error     string            `json:"error,omitempty"` // error of the last attempt
+	Hash      string            `json:"hash,omitempty"`  // hash of envelope, once it's sent
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// OutboxStatusHandler is called with message of outbox, whenever its status is changed.
+type OutboxStatusHandler func(msg OutboxMessage)
+
+// Outbox is a persistent queue of outgoing Whisper messages: messages, which can't be sent, as no peers
+// are connected, or PoW computation fails, are queued and resent, once peers are connected again.
+// Messages are given up after OutboxMaxRetries failed attempts, or after OutboxTTL of Whisper config.
+// Signed messages are held, while account, which was selected on post, is not selected.
+type Outbox struct {
+	nodeManager    common.NodeManager
+	accountManager common.AccountManager
+	symKeyVault    *SymKeyVault
+	delivery       *DeliveryService
+
+	mu        sync.Mutex
+	messages  map[string]*OutboxMessage // keyed by id
+	sending   map[string]bool           // ids of messages, which are being sent
+	handlers  []OutboxStatusHandler
+	loaded    bool
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	now       func() time.Time
+	peerCount func() (int, error)
+}
+
+// NewOutbox returns new outbox.
+func NewOutbox(nodeManager common.NodeManager, accountManager common.AccountManager, symKeyVault *SymKeyVault, delivery *DeliveryService) *Outbox {
+	o := &Outbox{
+		nodeManager:    nodeManager,
+		accountManager: accountManager,
+		symKeyVault:    symKeyVault,
+		delivery:       delivery,
+		messages:       make(map[string]*OutboxMessage),
+		sending:        make(map[string]bool),
+		now:            time.Now,
+	}
+	o.peerCount = o.nodePeerCount
+
+	return o
+}
+
+// AddStatusHandler adds handler, which is called (in addition to EventOutboxMessageStatus signal),
+// whenever status of message is changed.
+func (o *Outbox) AddStatusHandler(handler OutboxStatusHandler) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.handlers = append(o.handlers, handler)
+}
+
+// Start loads queued messages, and starts resending them, whenever peers are connected.
+// It is to be called whenever node is started.
+func (o *Outbox) Start() error {
+	o.Stop()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.load(); err != nil {
+		return err
+	}
+
+	o.quit = make(chan struct{})
+	o.wg.Add(1)
+	go o.loop(o.quit)
+
+	return nil
+}
+
+// Stop stops resending queued messages.
+func (o *Outbox) Stop() {
+	o.mu.Lock()
+	if o.quit != nil {
+		close(o.quit)
+		o.quit = nil
+	}
+	o.mu.Unlock()
+
+	o.wg.Wait()
+}
+
+// Post sends message, or queues it, if it can't be sent now. Signed messages are signed with key
+// of the selected account, they are never sent on behalf of another one.
+func (o *Outbox) Post(msg OutboxMessage) (OutboxMessage, error) {
+	if (len(msg.PublicKey) == 0) == (msg.SymKey == "") {
+		return OutboxMessage{}, ErrOutboxNoKey
+	}
+	if len(msg.PublicKey) > 0 && !whisper.ValidatePublicKey(crypto.ToECDSAPub(msg.PublicKey)) {
+		return OutboxMessage{}, ErrFilterInvalidSig
+	}
+	msg.From = nil
+	if msg.Sign {
+		account, err := o.accountManager.SelectedAccount()
+		if err != nil {
+			return OutboxMessage{}, err
+		}
+		msg.From = crypto.FromECDSAPub(&account.AccountKey.PrivateKey.PublicKey)
+	}
+
+	o.mu.Lock()
+	if err := o.load(); err != nil {
+		o.mu.Unlock()
+		return OutboxMessage{}, err
+	}
+	if msg.ID == "" {
+		msg.ID = uuid.New()
+	}
+	if _, ok := o.messages[msg.ID]; ok {
+		o.mu.Unlock()
+		return OutboxMessage{}, ErrOutboxMessageExists
+	}
+	msg.Status = ResendQueued
+	msg.Retries = 0
+	msg.Error = ""
+	msg.Hash = ""
+	msg.CreatedAt = o.now()
+	stored := msg
+	o.messages[msg.ID] = &stored
+	o.sending[msg.ID] = true
+	o.mu.Unlock()
+
+	// PoW is done without lock held, so that outbox could be used meanwhile
+	changed := o.send(&msg)
+
+	o.mu.Lock()
+	delete(o.sending, msg.ID)
+	if current, ok := o.messages[msg.ID]; ok {
+		*current = msg
+	}
+	err := o.save()
+	handlers := o.handlers
+	o.mu.Unlock()
+
+	if changed {
+		o.notify(handlers, msg)
+	}
+
+	return msg, err
+}
+
+// Message returns message of outbox with a given id.
+func (o *Outbox) Message(id string) (OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.load(); err != nil {
+		return OutboxMessage{}, err
+	}
+
+	msg, ok := o.messages[id]
+	if !ok {
+		return OutboxMessage{}, ErrOutboxUnknownMessage
+	}
+	return *msg, nil
+}
+
+// Messages returns messages of outbox, ordered by time.
+func (o *Outbox) Messages() ([]OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+
+	messages := make([]OutboxMessage, 0, len(o.messages))
+	for _, msg := range o.messages {
+		messages = append(messages, *msg)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+
+	return messages, nil
+}
+
+// Discard removes sent or failed message from outbox.
+func (o *Outbox) Discard(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.load(); err != nil {
+		return err
+	}
+
+	msg, ok := o.messages[id]
+	if !ok {
+		return ErrOutboxUnknownMessage
+	}
+	if msg.Status == ResendQueued {
+		return ErrOutboxMessageNotFinal
+	}
+	delete(o.messages, id)
+
+	return o.save()
+}
+
+// Resend tries to send queued messages, which are not being sent already.
+func (o *Outbox) Resend() error {
+	o.mu.Lock()
+	if err := o.load(); err != nil {
+		o.mu.Unlock()
+		return err
+	}
+	var queued []OutboxMessage
+	for id, msg := range o.messages {
+		if msg.Status == ResendQueued && !o.sending[id] {
+			queued = append(queued, *msg)
+			o.sending[id] = true
+		}
+	}
+	o.mu.Unlock()
+
+	// PoW is done without lock held, so that outbox could be used meanwhile
+	var changed []OutboxMessage
+	for i := range queued {
+		if o.send(&queued[i]) {
+			changed = append(changed, queued[i])
+		}
+	}
+
+	o.mu.Lock()
+	for _, msg := range queued {
+		delete(o.sending, msg.ID)
+	}
+	for _, msg := range changed {
+		if current, ok := o.messages[msg.ID]; ok {
+			*current = msg
+		}
+	}
+	var err error
+	if len(changed) > 0 {
+		err = o.save()
+	}
+	handlers := o.handlers
+	o.mu.Unlock()
+
+	for _, msg := range changed {
+		o.notify(handlers, msg)
+	}
+
+	return err
+}
+
+// loop periodically resends queued messages, until quit is closed.
+func (o *Outbox) loop(quit chan struct{}) {
+	defer o.wg.Done()
+
+	retry := 0
+	for {
+		err := o.Resend()
+		if err != nil {
+			log.Warn("Failed to resend messages of outbox", "error", err)
+		}
+		if err != nil || o.hasQueued() {
+			retry++
+		} else {
+			retry = 0
+		}
+
+		timer := time.NewTimer(outboxResendPolicy.Duration(retry))
+		select {
+		case <-timer.C:
+		case <-quit:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// hasQueued checks whether any message is still queued.
+func (o *Outbox) hasQueued() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, msg := range o.messages {
+		if msg.Status == ResendQueued {
+			return true
+		}
+	}
+	return false
+}
+
+// send tries to send queued message, updating its status. It returns true, if message is changed.
+// Attempts are not counted, while there are no peers, or while signer of message is not selected.
+// It's called without lock held, with message, which is marked as being sent.
+func (o *Outbox) send(msg *OutboxMessage) bool {
+	config, err := o.nodeManager.NodeConfig()
+	if err != nil {
+		return false
+	}
+
+	if config.WhisperConfig.OutboxTTL > 0 && o.now().Sub(msg.CreatedAt) >= time.Duration(config.WhisperConfig.OutboxTTL)*time.Second {
+		return setStatus(msg, ResendFailed, ErrOutboxMessageExpired)
+	}
+
+	if peers, err := o.peerCount(); err != nil || peers == 0 {
+		return setStatus(msg, ResendQueued, ErrOutboxNoPeers)
+	}
+
+	var src *ecdsa.PrivateKey
+	if msg.Sign {
+		if src, err = o.signer(msg); err != nil {
+			return setStatus(msg, ResendQueued, err)
+		}
+	}
+
+	hash, err := o.post(msg, src, config.WhisperConfig)
+	if err != nil {
+		msg.Retries++
+		status := ResendQueued
+		if max := config.WhisperConfig.OutboxMaxRetries; max > 0 && msg.Retries >= max {
+			status = ResendFailed
+		}
+		setStatus(msg, status, err)
+		return true
+	}
+
+	msg.Hash = hash
+	return setStatus(msg, ResendSent, nil)
+}
+
+// signer returns key of the selected account, if it's the one, message has been posted by.
+func (o *Outbox) signer(msg *OutboxMessage) (*ecdsa.PrivateKey, error) {
+	account, err := o.accountManager.SelectedAccount()
+	if err != nil {
+		return nil, err
+	}
+	key := account.AccountKey.PrivateKey
+	if string(crypto.FromECDSAPub(&key.PublicKey)) != string(msg.From) {
+		return nil, ErrMessageSender
+	}
+
+	return key, nil
+}
+
+// post builds envelope of message and posts it to Whisper, returning its hash.
+func (o *Outbox) post(msg *OutboxMessage, src *ecdsa.PrivateKey, config *params.WhisperConfig) (string, error) {
+	params := &whisper.MessageParams{
+		TTL:      uint32(config.TTL),
+		PoW:      config.MessagePoW(),
+		WorkTime: messageWorkTime,
+		Topic:    msg.Topic,
+		Payload:  msg.Payload,
+		Src:      src,
+	}
+	if len(msg.PublicKey) > 0 {
+		params.Dst = crypto.ToECDSAPub(msg.PublicKey)
+	} else {
+		if o.symKeyVault == nil {
+			return "", ErrSymKeyNotFound
+		}
+		key, _, err := o.symKeyVault.key(msg.SymKey)
+		if err != nil {
+			return "", err
+		}
+		params.KeySym = key
+	}
+
+	whisperService, err := o.nodeManager.WhisperService()
+	if err != nil {
+		return "", err
+	}
+	sent, err := whisper.NewSentMessage(params)
+	if err != nil {
+		return "", err
+	}
+	envelope, err := sent.Wrap(params)
+	if err != nil {
+		return "", err
+	}
+	if o.delivery != nil {
+		o.delivery.Track(envelope)
+	}
+	if err := whisperService.Send(envelope); err != nil {
+		if o.delivery != nil {
+			o.delivery.Forget(envelope)
+		}
+		return "", err
+	}
+
+	return envelope.Hash().Hex(), nil
+}
+
+// notify sends message as signal, and passes it to handlers.
+func (o *Outbox) notify(handlers []OutboxStatusHandler, msg OutboxMessage) {
+	signal.Send(signal.Envelope{
+		Type:  EventOutboxMessageStatus,
+		Event: msg,
+	})
+	for _, handler := range handlers {
+		handler(msg)
+	}
+}
+
+// setStatus updates status and error of message, returning true, if either of them is changed.
+func setStatus(msg *OutboxMessage, status ResendStatus, err error) bool {
+	errText := ""
+	if err != nil {
+		errText = err.Error()
+	}
+	if msg.Status == status && msg.Error == errText {
+		return false
+	}
+
+	msg.Status = status
+	msg.Error = errText
+	return true
+}
+
+// nodePeerCount returns number of peers of the running node.
+func (o *Outbox) nodePeerCount() (int, error) {
+	node, err := o.nodeManager.Node()
+	if err != nil {
+		return 0, err
+	}
+	server := node.Server()
+	if server == nil {
+		return 0, ErrOutboxNoPeers
+	}
+
+	return server.PeerCount(), nil
+}
+
+// path returns path to the file, messages are stored in.
+func (o *Outbox) path() (string, error) {
+	config, err := o.nodeManager.NodeConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(config.WhisperConfig.DataDir, outboxQueueFile), nil
+}
+
+// load reads stored messages, unless they are loaded already. Must be called with lock held.
+func (o *Outbox) load() error {
+	if o.loaded {
+		return nil
+	}
+
+	path, err := o.path()
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		var messages []*OutboxMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			o.messages[msg.ID] = msg
+		}
+	}
+
+	o.loaded = true
+	return nil
+}
+
+// save writes messages. Must be called with lock held.
+func (o *Outbox) save() error {
+	path, err := o.path()
+	if err != nil {
+		return err
+	}
+
+	messages := make([]*OutboxMessage, 0, len(o.messages))
+	for _, msg := range o.messages {
+		messages = append(messages, msg)
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
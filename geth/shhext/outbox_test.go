@@ -0,0 +1,187 @@
+package shhext
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	senderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	recipientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	whisperService := whisper.New(nil)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{
+			DataDir:          dir,
+			TTL:              params.WhisperTTL,
+			MinimumPoW:       params.WhisperMinimumPoW,
+			OutboxMaxRetries: 2,
+			OutboxTTL:        60,
+		},
+	}, nil).AnyTimes()
+	accountManager := common.NewMockAccountManager(ctrl)
+	selected := &common.SelectedExtKey{AccountKey: &keystore.Key{PrivateKey: senderKey}}
+	accountManager.EXPECT().SelectedAccount().Return(selected, nil).AnyTimes()
+
+	peers := 0
+	now := time.Now()
+	newOutbox := func() *Outbox {
+		outbox := NewOutbox(nodeManager, accountManager, nil, nil)
+		outbox.peerCount = func() (int, error) { return peers, nil }
+		outbox.now = func() time.Time { return now }
+		return outbox
+	}
+	outbox := newOutbox()
+	var statuses []ResendStatus
+	outbox.AddStatusHandler(func(msg OutboxMessage) { statuses = append(statuses, msg.Status) })
+
+	_, err = outbox.Post(OutboxMessage{Payload: []byte("hello")})
+	require.Equal(t, ErrOutboxNoKey, err)
+
+	// message is queued, while there are no peers, without counting attempts
+	msg, err := outbox.Post(OutboxMessage{
+		Topic:     whisper.BytesToTopic([]byte("test")),
+		Payload:   []byte("hello"),
+		PublicKey: crypto.FromECDSAPub(&recipientKey.PublicKey),
+		Sign:      true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ResendQueued, msg.Status)
+	require.Equal(t, 0, msg.Retries)
+	require.Equal(t, ErrOutboxNoPeers.Error(), msg.Error)
+	require.Equal(t, hexutil.Bytes(crypto.FromECDSAPub(&senderKey.PublicKey)), msg.From)
+	require.NoError(t, outbox.Resend())
+	require.Equal(t, []ResendStatus{ResendQueued}, statuses)
+
+	// id given by poster is kept, and must be unique
+	_, err = outbox.Post(OutboxMessage{ID: msg.ID, Payload: []byte("hello"), PublicKey: msg.PublicKey})
+	require.Equal(t, ErrOutboxMessageExists, err)
+	stored, err := outbox.Message(msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, msg, stored)
+
+	// failed attempts are counted, once peers are connected
+	peers = 1
+	nodeManager.EXPECT().WhisperService().Return(nil, errors.New("whisper is not running"))
+	require.NoError(t, outbox.Resend())
+	messages, err := outbox.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, ResendQueued, messages[0].Status)
+	require.Equal(t, 1, messages[0].Retries)
+	require.Error(t, outbox.Discard(msg.ID))
+
+	// queued message is loaded by a new outbox, and held, while another account is selected
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+	outbox = newOutbox()
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	selected.AccountKey = &keystore.Key{PrivateKey: otherKey}
+	require.NoError(t, outbox.Resend())
+	messages, err = outbox.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, ResendQueued, messages[0].Status)
+	require.Equal(t, 1, messages[0].Retries)
+	require.Equal(t, ErrMessageSender.Error(), messages[0].Error)
+	require.Empty(t, whisperService.Envelopes())
+
+	// and is resent, once account is selected again
+	selected.AccountKey = &keystore.Key{PrivateKey: senderKey}
+	require.NoError(t, outbox.Resend())
+	messages, err = outbox.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, ResendSent, messages[0].Status)
+	require.Empty(t, messages[0].Error)
+
+	envelopes := whisperService.Envelopes()
+	require.Len(t, envelopes, 1)
+	require.Equal(t, envelopes[0].Hash().Hex(), messages[0].Hash)
+	received, err := envelopes[0].OpenAsymmetric(recipientKey)
+	require.NoError(t, err)
+	require.True(t, received.Validate())
+	require.Equal(t, []byte("hello"), received.Payload)
+	require.Equal(t, senderKey.PublicKey, *received.SigToPubKey())
+
+	// sent message is not resent, and can be discarded
+	require.NoError(t, outbox.Resend())
+	require.Len(t, whisperService.Envelopes(), 1)
+	require.NoError(t, outbox.Discard(msg.ID))
+	require.Equal(t, ErrOutboxUnknownMessage, outbox.Discard(msg.ID))
+
+	// message, which is not sent within TTL of outbox, fails
+	peers = 0
+	msg, err = outbox.Post(OutboxMessage{
+		Payload:   []byte("hello"),
+		PublicKey: crypto.FromECDSAPub(&recipientKey.PublicKey),
+	})
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	require.NoError(t, outbox.Resend())
+	messages, err = outbox.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, ResendFailed, messages[0].Status)
+	require.Equal(t, ErrOutboxMessageExpired.Error(), messages[0].Error)
+}
+
+func TestOutboxMaxRetries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+		WhisperConfig: &params.WhisperConfig{DataDir: dir, TTL: params.WhisperTTL, OutboxMaxRetries: 2},
+	}, nil).AnyTimes()
+	nodeManager.EXPECT().WhisperService().Return(nil, errors.New("whisper is not running")).AnyTimes()
+
+	outbox := NewOutbox(nodeManager, nil, nil, nil)
+	outbox.peerCount = func() (int, error) { return 1, nil }
+
+	// symmetric key can't be found without vault
+	msg, err := outbox.Post(OutboxMessage{Payload: []byte("hello"), SymKey: "chat"})
+	require.NoError(t, err)
+	require.Equal(t, ResendQueued, msg.Status)
+	require.Equal(t, 1, msg.Retries)
+	require.Equal(t, ErrSymKeyNotFound.Error(), msg.Error)
+
+	require.NoError(t, outbox.Resend())
+	messages, err := outbox.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.Equal(t, ResendFailed, messages[0].Status)
+	require.Equal(t, 2, messages[0].Retries)
+
+	// failed message is not retried anymore
+	require.NoError(t, outbox.Resend())
+	messages, err = outbox.Messages()
+	require.NoError(t, err)
+	require.Equal(t, 2, messages[0].Retries)
+}
@@ -0,0 +1,23 @@
+package shhext
+
+import (
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+)
+
+// RelayStats counts envelopes of other nodes: relayed to peers, and dropped, as light client doesn't relay them.
+// Envelopes are counted once per peer.
+type RelayStats struct {
+	LightClient bool   `json:"lightClient"`
+	Relayed     uint64 `json:"relayed"`
+	Dropped     uint64 `json:"dropped"`
+}
+
+// NewRelayStats returns relay counters of Whisper.
+func NewRelayStats(w *whisper.Whisper) RelayStats {
+	relayed, dropped := w.RelayStats()
+	return RelayStats{
+		LightClient: w.LightClientMode(),
+		Relayed:     relayed,
+		Dropped:     dropped,
+	}
+}
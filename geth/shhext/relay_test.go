@@ -0,0 +1,34 @@
+package shhext
+
+import (
+	"testing"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayStats(t *testing.T) {
+	require.Equal(t, RelayStats{}, NewRelayStats(whisper.New(nil)))
+
+	config := whisper.DefaultConfig
+	config.LightClient = true
+	require.Equal(t, RelayStats{LightClient: true}, NewRelayStats(whisper.New(&config)))
+}
+
+func TestLightClientOwnEnvelopePoW(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	msg, err := whisper.NewSentMessage(&whisper.MessageParams{Payload: []byte("hello")})
+	require.NoError(t, err)
+	envelope, err := msg.Wrap(&whisper.MessageParams{KeySym: key, WorkTime: 1, PoW: 0.001})
+	require.NoError(t, err)
+
+	// envelope with PoW below the minimum accepted one is rejected, unless light client sends it
+	config := whisper.DefaultConfig
+	config.MinimumAcceptedPOW = 1000
+	require.Error(t, whisper.New(&config).Send(envelope))
+
+	config.LightClient = true
+	light := whisper.New(&config)
+	require.NoError(t, light.Send(envelope))
+	require.Len(t, light.Envelopes(), 1)
+}
@@ -0,0 +1,126 @@
+package shhext
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// vaultFileVersion is a version of the vault file format
+const vaultFileVersion = 1
+
+// vaultKeySalt is mixed into account key to obtain vault encryption key
+var vaultKeySalt = []byte("status-go/shhext/symkeys")
+
+// errors
+var (
+	ErrVaultFileVersion = errors.New("unsupported vault file version")
+)
+
+// vaultFile is an on-disk representation of encrypted vault.
+type vaultFile struct {
+	Version    int           `json:"version"`
+	Nonce      hexutil.Bytes `json:"nonce"`
+	Ciphertext hexutil.Bytes `json:"ciphertext"`
+}
+
+// vaultEncryptionKey derives vault encryption key from account's private key.
+func vaultEncryptionKey(accountKey *ecdsa.PrivateKey) []byte {
+	return crypto.Keccak256(crypto.FromECDSA(accountKey), vaultKeySalt)
+}
+
+// loadSymKeys reads and decrypts keys stored at a given path.
+// Missing file is not an error: empty set of keys is returned instead.
+func loadSymKeys(path string, encKey []byte) (map[string]*SymKey, error) {
+	keys := make(map[string]*SymKey)
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != vaultFileVersion {
+		return nil, ErrVaultFileVersion
+	}
+
+	gcm, err := newGCM(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*SymKey
+	if err := json.Unmarshal(plaintext, &list); err != nil {
+		return nil, err
+	}
+	for _, symKey := range list {
+		keys[symKey.Name] = symKey
+	}
+
+	return keys, nil
+}
+
+// saveSymKeys encrypts and writes keys to a given path.
+func saveSymKeys(path string, encKey []byte, keys map[string]*SymKey) error {
+	list := make([]*SymKey, 0, len(keys))
+	for _, symKey := range keys {
+		list = append(list, symKey)
+	}
+
+	plaintext, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(encKey)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(vaultFile{
+		Version:    vaultFileVersion,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
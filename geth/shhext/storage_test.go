@@ -0,0 +1,49 @@
+package shhext
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymKeysStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "symkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	accountKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	encKey := vaultEncryptionKey(accountKey)
+	path := filepath.Join(dir, symKeysDir, "vault.json")
+
+	// missing file results in empty vault
+	keys, err := loadSymKeys(path, encKey)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	keys["status"] = &SymKey{
+		Name:    "status",
+		Key:     make([]byte, symKeyLength),
+		Topics:  []whisper.TopicType{whisper.BytesToTopic([]byte("test"))},
+		Version: 2,
+	}
+	require.NoError(t, saveSymKeys(path, encKey, keys))
+
+	loaded, err := loadSymKeys(path, encKey)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	require.Equal(t, keys["status"].Key, loaded["status"].Key)
+	require.Equal(t, keys["status"].Topics, loaded["status"].Topics)
+	require.Equal(t, 2, loaded["status"].Version)
+
+	// vault can't be decrypted with other account's key
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = loadSymKeys(path, vaultEncryptionKey(otherKey))
+	require.Error(t, err)
+}
@@ -0,0 +1,326 @@
+package shhext
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventWhisperMessage is triggered when message, matching subscription of WhisperSubscriber, is received
+	EventWhisperMessage = "whisper.message"
+
+	// subscriptionPollInterval is an interval, installed filters are checked for received messages at
+	subscriptionPollInterval = 300 * time.Millisecond
+)
+
+// errors
+var (
+	ErrUnknownSubscription = errors.New("subscription not found")
+)
+
+// SubscriptionFilter selects messages, which subscription receives: messages of any of topics (or topics
+// of symmetric key, if none is given), encrypted either with a named key of SymKeyVault, or with public
+// key of the selected account, if SymKey is empty.
+type SubscriptionFilter struct {
+	Topics   []whisper.TopicType `json:"topics"`
+	SymKey   string              `json:"symKey,omitempty"` // name of key of SymKeyVault
+	Sig      hexutil.Bytes       `json:"sig,omitempty"`    // public key of the sender filter expects
+	MinPow   float64             `json:"minPow"`
+	AllowP2P bool                `json:"allowP2P"`
+}
+
+// WhisperMessage is a decrypted message, received by subscription.
+type WhisperMessage struct {
+	Hash      hexutil.Bytes     `json:"hash"`
+	Sig       hexutil.Bytes     `json:"sig,omitempty"` // public key of the sender, if message is signed
+	TTL       uint32            `json:"ttl"`
+	Timestamp uint32            `json:"timestamp"`
+	Topic     whisper.TopicType `json:"topic"`
+	Payload   hexutil.Bytes     `json:"payload"`
+	PoW       float64           `json:"pow"`
+}
+
+// WhisperMessageEvent is sent with EventWhisperMessage signal.
+type WhisperMessageEvent struct {
+	SubscriptionID string         `json:"subscriptionID"`
+	Message        WhisperMessage `json:"message"`
+}
+
+// MessageHandler is called with messages received by subscription.
+type MessageHandler func(subID string, message WhisperMessage)
+
+// WhisperSubscriber delivers messages of subscribed topics as signals, and to Go handlers.
+// Messages are dropped before delivery, if they don't pass IngressFilterChain (if it's given).
+// Subscriptions outlive Whisper service: their filters are re-installed whenever node is started,
+// so ids of subscriptions do not change, while ids of Whisper filters do.
+type WhisperSubscriber struct {
+	nodeManager    common.NodeManager
+	accountManager common.AccountManager
+	symKeyVault    *SymKeyVault
+	ingress        *IngressFilterChain
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription // keyed by id
+	whisper       *whisper.Whisper         // nil, if subscriber is not started
+	quit          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// subscription is a filter, messages of which are delivered to handler.
+type subscription struct {
+	filter   SubscriptionFilter
+	handler  MessageHandler
+	filterID string // id of filter installed into Whisper, empty if it's not installed
+}
+
+// NewWhisperSubscriber returns new subscriber.
+func NewWhisperSubscriber(nodeManager common.NodeManager, accountManager common.AccountManager, symKeyVault *SymKeyVault, ingress *IngressFilterChain) *WhisperSubscriber {
+	return &WhisperSubscriber{
+		nodeManager:    nodeManager,
+		accountManager: accountManager,
+		symKeyVault:    symKeyVault,
+		ingress:        ingress,
+		subscriptions:  make(map[string]*subscription),
+	}
+}
+
+// Start installs filters of subscriptions into Whisper of the running node, and starts delivering
+// their messages. It is to be called whenever node is started, as Whisper service is re-created.
+// Subscriptions, filters of which can't be installed (e.g. their key is not available), are kept,
+// and installed on the next start.
+func (s *WhisperSubscriber) Start() error {
+	whisperService, err := s.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	s.Stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.whisper = whisperService
+	for id, sub := range s.subscriptions {
+		if err := s.install(sub); err != nil {
+			log.Warn("Failed to install filter of subscription", "id", id, "error", err)
+		}
+	}
+
+	s.quit = make(chan struct{})
+	s.wg.Add(1)
+	go s.loop(s.quit)
+
+	return nil
+}
+
+// Stop stops delivering messages, filters installed into Whisper are forgotten.
+func (s *WhisperSubscriber) Stop() {
+	s.mu.Lock()
+	if s.quit != nil {
+		close(s.quit)
+		s.quit = nil
+	}
+	s.whisper = nil
+	for _, sub := range s.subscriptions {
+		sub.filterID = ""
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Subscribe subscribes to messages matching filter, they are sent as EventWhisperMessage signals,
+// and passed to handler (if it's not nil). If node is not running, filter is installed once it's started.
+func (s *WhisperSubscriber) Subscribe(filter SubscriptionFilter, handler MessageHandler) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &subscription{filter: filter, handler: handler}
+	if s.whisper != nil {
+		if err := s.install(sub); err != nil {
+			return "", err
+		}
+	}
+
+	id := uuid.New()
+	s.subscriptions[id] = sub
+	return id, nil
+}
+
+// Unsubscribe removes subscription, uninstalling its filter from Whisper.
+func (s *WhisperSubscriber) Unsubscribe(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return ErrUnknownSubscription
+	}
+	if s.whisper != nil && sub.filterID != "" {
+		s.whisper.Unsubscribe(sub.filterID) // nolint: errcheck
+	}
+	delete(s.subscriptions, id)
+
+	return nil
+}
+
+// install installs filter of subscription into Whisper. Must be called with lock held.
+func (s *WhisperSubscriber) install(sub *subscription) error {
+	f, err := s.newFilter(sub.filter)
+	if err != nil {
+		return err
+	}
+	filterID, err := s.whisper.Subscribe(f)
+	if err != nil {
+		return err
+	}
+	sub.filterID = filterID
+
+	return nil
+}
+
+// newFilter creates Whisper filter, resolving keys of subscription filter.
+func (s *WhisperSubscriber) newFilter(filter SubscriptionFilter) (*whisper.Filter, error) {
+	f := &whisper.Filter{
+		PoW:      filter.MinPow,
+		AllowP2P: filter.AllowP2P,
+	}
+
+	topics := filter.Topics
+	if filter.SymKey != "" {
+		if s.symKeyVault == nil {
+			return nil, ErrSymKeyNotFound
+		}
+		key, keyTopics, err := s.symKeyVault.key(filter.SymKey)
+		if err != nil {
+			return nil, err
+		}
+		f.KeySym = key
+		f.SymKeyHash = crypto.Keccak256Hash(key)
+		if len(topics) == 0 {
+			topics = keyTopics
+		}
+	} else {
+		account, err := s.accountManager.SelectedAccount()
+		if err != nil {
+			return nil, err
+		}
+		f.KeyAsym = account.AccountKey.PrivateKey
+	}
+
+	f.Topics = make([][]byte, len(topics))
+	for i := range topics {
+		topic := topics[i]
+		f.Topics[i] = topic[:]
+	}
+
+	if len(filter.Sig) > 0 {
+		f.Src = crypto.ToECDSAPub(filter.Sig)
+		if !whisper.ValidatePublicKey(f.Src) {
+			return nil, ErrFilterInvalidSig
+		}
+	}
+
+	return f, nil
+}
+
+// loop periodically delivers messages received by installed filters, until quit is closed.
+func (s *WhisperSubscriber) loop(quit chan struct{}) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(subscriptionPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.deliver()
+		case <-quit:
+			return
+		}
+	}
+}
+
+// deliver retrieves messages of installed filters, and sends them to subscribers.
+func (s *WhisperSubscriber) deliver() {
+	type delivery struct {
+		id       string
+		handler  MessageHandler
+		messages []*whisper.ReceivedMessage
+	}
+
+	s.mu.Lock()
+	if s.whisper == nil {
+		s.mu.Unlock()
+		return
+	}
+	var deliveries []delivery
+	for id, sub := range s.subscriptions {
+		if sub.filterID == "" {
+			continue
+		}
+		f := s.whisper.GetFilter(sub.filterID)
+		if f == nil {
+			continue
+		}
+		if messages := f.Retrieve(); len(messages) > 0 {
+			deliveries = append(deliveries, delivery{id: id, handler: sub.handler, messages: messages})
+		}
+	}
+	s.mu.Unlock()
+
+	// handlers are called without lock held, so that they could (un)subscribe
+	dropped := make(map[string]error) // keyed by hex of envelope hash, so that messages are checked once
+	for _, d := range deliveries {
+		for _, received := range d.messages {
+			msg := newWhisperMessage(received)
+			if s.ingress != nil {
+				hash := msg.Hash.String()
+				err, checked := dropped[hash]
+				if !checked {
+					err = s.ingress.Check(msg)
+					dropped[hash] = err
+				}
+				if err != nil {
+					log.Debug("Dropped received message", "hash", hash, "topic", msg.Topic, "error", err)
+					continue
+				}
+			}
+			signal.Send(signal.Envelope{
+				Type: EventWhisperMessage,
+				Event: WhisperMessageEvent{
+					SubscriptionID: d.id,
+					Message:        msg,
+				},
+			})
+			if d.handler != nil {
+				d.handler(d.id, msg)
+			}
+		}
+	}
+}
+
+// newWhisperMessage converts decrypted message received by Whisper.
+func newWhisperMessage(received *whisper.ReceivedMessage) WhisperMessage {
+	msg := WhisperMessage{
+		Hash:      received.EnvelopeHash.Bytes(),
+		TTL:       received.TTL,
+		Timestamp: received.Sent,
+		Topic:     received.Topic,
+		Payload:   received.Payload,
+		PoW:       received.PoW,
+	}
+	if received.Src != nil {
+		msg.Sig = crypto.FromECDSAPub(received.Src)
+	}
+
+	return msg
+}
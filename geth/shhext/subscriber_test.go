@@ -0,0 +1,140 @@
+package shhext
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhisperSubscriber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recipientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	topic := whisper.BytesToTopic([]byte("abcd"))
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		AccountKey: &keystore.Key{PrivateKey: recipientKey},
+	}, nil).AnyTimes()
+	subscriber := NewWhisperSubscriber(nodeManager, accountManager, nil, nil)
+
+	// subscription is installed once node is started
+	received := make(chan WhisperMessage, 10)
+	var subID string
+	subID, err = subscriber.Subscribe(SubscriptionFilter{Topics: []whisper.TopicType{topic}}, func(id string, msg WhisperMessage) {
+		require.Equal(t, subID, id)
+		received <- msg
+	})
+	require.NoError(t, err)
+
+	// filter of subscription is re-installed into Whisper of restarted node
+	for _, payload := range []string{"hello", "again"} {
+		whisperService := whisper.New(nil)
+		require.NoError(t, whisperService.Start(nil))
+		nodeManager.EXPECT().WhisperService().Return(whisperService, nil)
+		require.NoError(t, subscriber.Start())
+
+		params := &whisper.MessageParams{
+			TTL:      10,
+			Dst:      &recipientKey.PublicKey,
+			Topic:    topic,
+			Payload:  []byte(payload),
+			WorkTime: 1,
+		}
+		sent, err := whisper.NewSentMessage(params)
+		require.NoError(t, err)
+		envelope, err := sent.Wrap(params)
+		require.NoError(t, err)
+		require.NoError(t, whisperService.Send(envelope))
+
+		select {
+		case msg := <-received:
+			require.Equal(t, []byte(payload), []byte(msg.Payload))
+			require.Equal(t, topic, msg.Topic)
+			require.Equal(t, envelope.Hash().Bytes(), []byte(msg.Hash))
+		case <-time.After(5 * time.Second):
+			t.Fatal("message is not delivered")
+		}
+
+		require.NoError(t, whisperService.Stop())
+	}
+
+	require.NoError(t, subscriber.Unsubscribe(subID))
+	require.Equal(t, ErrUnknownSubscription, subscriber.Unsubscribe(subID))
+	subscriber.Stop()
+}
+
+func TestWhisperSubscriberIngress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	recipientKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	spammerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	friendKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	topic := whisper.BytesToTopic([]byte("abcd"))
+
+	whisperService := whisper.New(nil)
+	require.NoError(t, whisperService.Start(nil))
+	defer whisperService.Stop() // nolint: errcheck
+
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().WhisperService().Return(whisperService, nil)
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		AccountKey: &keystore.Key{PrivateKey: recipientKey},
+	}, nil).AnyTimes()
+
+	ingress := NewIngressFilterChain()
+	require.NoError(t, ingress.AddBlockedKey(crypto.FromECDSAPub(&spammerKey.PublicKey)))
+	subscriber := NewWhisperSubscriber(nodeManager, accountManager, nil, ingress)
+	require.NoError(t, subscriber.Start())
+	defer subscriber.Stop()
+
+	received := make(chan WhisperMessage, 10)
+	_, err = subscriber.Subscribe(SubscriptionFilter{Topics: []whisper.TopicType{topic}}, func(id string, msg WhisperMessage) {
+		received <- msg
+	})
+	require.NoError(t, err)
+
+	// message of blocked sender is dropped, while message of another one, sent after it, is delivered
+	for _, key := range []*ecdsa.PrivateKey{spammerKey, friendKey} {
+		params := &whisper.MessageParams{
+			TTL:      10,
+			Src:      key,
+			Dst:      &recipientKey.PublicKey,
+			Topic:    topic,
+			Payload:  []byte("hello"),
+			WorkTime: 1,
+		}
+		sent, err := whisper.NewSentMessage(params)
+		require.NoError(t, err)
+		envelope, err := sent.Wrap(params)
+		require.NoError(t, err)
+		require.NoError(t, whisperService.Send(envelope))
+	}
+
+	select {
+	case msg := <-received:
+		require.Equal(t, crypto.FromECDSAPub(&friendKey.PublicKey), []byte(msg.Sig))
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not delivered")
+	}
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected message from %x", []byte(msg.Sig))
+	case <-time.After(2 * subscriptionPollInterval):
+	}
+}
@@ -0,0 +1,434 @@
+package shhext
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// symKeyLength is a length of AES-256 key used by Whisper
+	symKeyLength = 32
+
+	// symKeyPasswordIterations mirrors key derivation used by Whisper's shh_generateSymKeyFromPassword
+	symKeyPasswordIterations = 65356
+
+	// symKeysDir is a directory (relative to Whisper data dir) where vault files are stored
+	symKeysDir = "symkeys"
+)
+
+// errors
+var (
+	ErrSymKeyVaultLocked = errors.New("symmetric key vault is locked, please login")
+	ErrSymKeyNotFound    = errors.New("symmetric key not found")
+	ErrSymKeyExists      = errors.New("symmetric key with a given name already exists")
+	ErrSymKeyEmptyName   = errors.New("symmetric key name cannot be empty")
+	ErrSymKeyInvalid     = errors.New("symmetric key must be 32 bytes long")
+)
+
+// SymKey is a named symmetric key (public channel, group chat etc.) managed by SymKeyVault.
+type SymKey struct {
+	Name      string              `json:"name"`
+	Key       hexutil.Bytes       `json:"key"`
+	Topics    []whisper.TopicType `json:"topics"`
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"createdAt"`
+
+	keyID    string // id of the key installed into Whisper
+	filterID string // id of the message filter installed into Whisper
+}
+
+// SymKeyInfo describes managed symmetric key, without exposing key material.
+type SymKeyInfo struct {
+	Name      string              `json:"name"`
+	KeyID     string              `json:"keyId"`
+	FilterID  string              `json:"filterId"`
+	Topics    []whisper.TopicType `json:"topics"`
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+func (k *SymKey) info() SymKeyInfo {
+	return SymKeyInfo{
+		Name:      k.Name,
+		KeyID:     k.keyID,
+		FilterID:  k.filterID,
+		Topics:    k.Topics,
+		Version:   k.Version,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+// SymKeyVault is a managed store of named symmetric keys.
+// Keys are persisted encrypted under the selected account's key, and are
+// (re-)installed into Whisper, along with corresponding message filters,
+// whenever the account is selected.
+type SymKeyVault struct {
+	nodeManager common.NodeManager
+
+	mu     sync.RWMutex
+	path   string // path to the vault file of the selected account
+	encKey []byte // key the vault file is encrypted with
+	keys   map[string]*SymKey
+}
+
+// NewSymKeyVault returns new symmetric key vault.
+func NewSymKeyVault(nodeManager common.NodeManager) *SymKeyVault {
+	return &SymKeyVault{
+		nodeManager: nodeManager,
+	}
+}
+
+// Unlock loads keys of a given account and installs them into Whisper.
+// Previously unlocked keys (if any) are uninstalled first.
+func (v *SymKeyVault) Unlock(address gethcommon.Address, accountKey *ecdsa.PrivateKey) error {
+	config, err := v.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.uninstallAll()
+
+	path := filepath.Join(config.WhisperConfig.DataDir, symKeysDir, strings.ToLower(address.Hex())+".json")
+	encKey := vaultEncryptionKey(accountKey)
+
+	keys, err := loadSymKeys(path, encKey)
+	if err != nil {
+		return err
+	}
+
+	v.path = path
+	v.encKey = encKey
+	v.keys = keys
+
+	return v.installAll()
+}
+
+// Reinstall installs keys of the unlocked vault into Whisper.
+// It is to be called after node restart, when Whisper service is re-created.
+func (v *SymKeyVault) Reinstall() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.keys == nil {
+		return nil
+	}
+
+	return v.installAll()
+}
+
+// Lock uninstalls all keys from Whisper and clears them from memory.
+func (v *SymKeyVault) Lock() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.uninstallAll()
+
+	v.path = ""
+	v.encKey = nil
+	v.keys = nil
+}
+
+// Create generates new random key with a given name.
+func (v *SymKeyVault) Create(name string, topics []whisper.TopicType) (SymKeyInfo, error) {
+	key := make([]byte, symKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	return v.Add(name, key, topics)
+}
+
+// AddFromPassword adds key with a given name, derived from password
+// (the same way shh_generateSymKeyFromPassword does).
+func (v *SymKeyVault) AddFromPassword(name, password string, topics []whisper.TopicType) (SymKeyInfo, error) {
+	key := pbkdf2.Key([]byte(password), nil, symKeyPasswordIterations, symKeyLength, sha256.New)
+
+	return v.Add(name, key, topics)
+}
+
+// Add stores provided key under a given name.
+func (v *SymKeyVault) Add(name string, key []byte, topics []whisper.TopicType) (SymKeyInfo, error) {
+	if name == "" {
+		return SymKeyInfo{}, ErrSymKeyEmptyName
+	}
+	if len(key) != symKeyLength {
+		return SymKeyInfo{}, ErrSymKeyInvalid
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.keys == nil {
+		return SymKeyInfo{}, ErrSymKeyVaultLocked
+	}
+	if _, ok := v.keys[name]; ok {
+		return SymKeyInfo{}, ErrSymKeyExists
+	}
+
+	symKey := &SymKey{
+		Name:      name,
+		Key:       key,
+		Topics:    topics,
+		Version:   1,
+		CreatedAt: time.Now(),
+	}
+	if err := v.install(symKey); err != nil {
+		return SymKeyInfo{}, err
+	}
+	v.keys[name] = symKey
+
+	if err := v.save(); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	return symKey.info(), nil
+}
+
+// Rotate replaces key material of a named key with a newly generated one.
+// Message filter is re-installed for a new key.
+func (v *SymKeyVault) Rotate(name string) (SymKeyInfo, error) {
+	key := make([]byte, symKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	symKey, err := v.get(name)
+	if err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	v.uninstall(symKey)
+
+	symKey.Key = key
+	symKey.Version++
+	symKey.CreatedAt = time.Now()
+
+	if err := v.install(symKey); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	if err := v.save(); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	return symKey.info(), nil
+}
+
+// SetTopics associates a named key with chat topics. Message filter is re-installed for new topics.
+func (v *SymKeyVault) SetTopics(name string, topics []whisper.TopicType) (SymKeyInfo, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	symKey, err := v.get(name)
+	if err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	v.uninstall(symKey)
+	symKey.Topics = topics
+	if err := v.install(symKey); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	if err := v.save(); err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	return symKey.info(), nil
+}
+
+// Remove uninstalls and removes a named key.
+func (v *SymKeyVault) Remove(name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	symKey, err := v.get(name)
+	if err != nil {
+		return err
+	}
+
+	v.uninstall(symKey)
+	delete(v.keys, name)
+
+	return v.save()
+}
+
+// Get returns info of a named key.
+func (v *SymKeyVault) Get(name string) (SymKeyInfo, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	symKey, err := v.get(name)
+	if err != nil {
+		return SymKeyInfo{}, err
+	}
+
+	return symKey.info(), nil
+}
+
+// Export returns a named key, including its material (e.g. to share key of group chat with new member).
+func (v *SymKeyVault) Export(name string) (SymKey, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	symKey, err := v.get(name)
+	if err != nil {
+		return SymKey{}, err
+	}
+
+	return *symKey, nil
+}
+
+// List returns info of all keys, sorted by name.
+func (v *SymKeyVault) List() ([]SymKeyInfo, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.keys == nil {
+		return nil, ErrSymKeyVaultLocked
+	}
+
+	infos := make([]SymKeyInfo, 0, len(v.keys))
+	for _, symKey := range v.keys {
+		infos = append(infos, symKey.info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos, nil
+}
+
+// key returns material and topics of a named key.
+func (v *SymKeyVault) key(name string) ([]byte, []whisper.TopicType, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	symKey, err := v.get(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return symKey.Key, symKey.Topics, nil
+}
+
+// get returns named key, vault lock must be held by caller.
+func (v *SymKeyVault) get(name string) (*SymKey, error) {
+	if v.keys == nil {
+		return nil, ErrSymKeyVaultLocked
+	}
+
+	symKey, ok := v.keys[name]
+	if !ok {
+		return nil, ErrSymKeyNotFound
+	}
+
+	return symKey, nil
+}
+
+// save persists keys of the unlocked vault.
+func (v *SymKeyVault) save() error {
+	return saveSymKeys(v.path, v.encKey, v.keys)
+}
+
+// installAll installs all keys into Whisper.
+func (v *SymKeyVault) installAll() error {
+	for _, symKey := range v.keys {
+		if err := v.install(symKey); err != nil {
+			return fmt.Errorf("install symmetric key %q: %v", symKey.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// uninstallAll removes all keys from Whisper.
+func (v *SymKeyVault) uninstallAll() {
+	for _, symKey := range v.keys {
+		v.uninstall(symKey)
+	}
+}
+
+// install adds key into Whisper and subscribes message filter for it.
+func (v *SymKeyVault) install(symKey *SymKey) error {
+	whisperService, err := v.nodeManager.WhisperService()
+	if err != nil {
+		return err
+	}
+
+	// Whisper service might have been re-created (on node restart)
+	if symKey.keyID != "" && whisperService.HasSymKey(symKey.keyID) {
+		return nil
+	}
+
+	keyID, err := whisperService.AddSymKeyDirect(symKey.Key)
+	if err != nil {
+		return err
+	}
+
+	topics := make([][]byte, len(symKey.Topics))
+	for i := range symKey.Topics {
+		topic := symKey.Topics[i]
+		topics[i] = topic[:]
+	}
+
+	filterID, err := whisperService.Subscribe(&whisper.Filter{
+		KeySym:     symKey.Key,
+		SymKeyHash: crypto.Keccak256Hash(symKey.Key),
+		Topics:     topics,
+		AllowP2P:   true,
+	})
+	if err != nil {
+		whisperService.DeleteSymKey(keyID)
+		return err
+	}
+
+	symKey.keyID = keyID
+	symKey.filterID = filterID
+
+	return nil
+}
+
+// uninstall removes key and its message filter from Whisper.
+func (v *SymKeyVault) uninstall(symKey *SymKey) {
+	defer func() {
+		symKey.keyID = ""
+		symKey.filterID = ""
+	}()
+
+	whisperService, err := v.nodeManager.WhisperService()
+	if err != nil {
+		return
+	}
+
+	if symKey.filterID != "" {
+		if err := whisperService.Unsubscribe(symKey.filterID); err != nil {
+			log.Warn("Failed to uninstall symmetric key filter", "name", symKey.Name, "error", err)
+		}
+	}
+	if symKey.keyID != "" {
+		whisperService.DeleteSymKey(symKey.keyID)
+	}
+}
@@ -0,0 +1,74 @@
+package shhext
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv5"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymKeyVault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "symkeys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := gethcommon.HexToAddress("0x01")
+	topic := whisper.BytesToTopic([]byte("abcd"))
+
+	newNodeManager := func(whisperService *whisper.Whisper) common.NodeManager {
+		nodeManager := common.NewMockNodeManager(ctrl)
+		nodeManager.EXPECT().NodeConfig().Return(&params.NodeConfig{
+			WhisperConfig: &params.WhisperConfig{DataDir: dir},
+		}, nil).AnyTimes()
+		nodeManager.EXPECT().WhisperService().Return(whisperService, nil).AnyTimes()
+		return nodeManager
+	}
+
+	whisperService := whisper.New(nil)
+	vault := NewSymKeyVault(newNodeManager(whisperService))
+	_, err = vault.Export("status")
+	require.Equal(t, ErrSymKeyVaultLocked, err)
+	require.NoError(t, vault.Unlock(address, accountKey))
+
+	info, err := vault.AddFromPassword("status", "password", nil)
+	require.NoError(t, err)
+	exported, err := vault.Export("status")
+	require.NoError(t, err)
+	installed, err := whisperService.GetSymKey(info.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, []byte(exported.Key), installed)
+
+	// filter of key is re-installed with new topics
+	info, err = vault.SetTopics("status", []whisper.TopicType{topic})
+	require.NoError(t, err)
+	require.Equal(t, []whisper.TopicType{topic}, info.Topics)
+	filter := whisperService.GetFilter(info.FilterID)
+	require.NotNil(t, filter)
+	require.Equal(t, [][]byte{topic[:]}, filter.Topics)
+
+	// keys survive restart of node, and are re-installed into a new Whisper
+	whisperService = whisper.New(nil)
+	vault = NewSymKeyVault(newNodeManager(whisperService))
+	require.NoError(t, vault.Unlock(address, accountKey))
+	info, err = vault.Get("status")
+	require.NoError(t, err)
+	require.Equal(t, []whisper.TopicType{topic}, info.Topics)
+	require.True(t, whisperService.HasSymKey(info.KeyID))
+
+	require.NoError(t, vault.Remove("status"))
+	require.False(t, whisperService.HasSymKey(info.KeyID))
+	_, err = vault.Export("status")
+	require.Equal(t, ErrSymKeyNotFound, err)
+}
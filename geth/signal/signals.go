@@ -28,6 +28,31 @@ const (
 
 	// EventChainDataRemoved is triggered when node's chain data is removed
 	EventChainDataRemoved = "chaindata.removed"
+
+	// EventChainDataCorrupted is triggered when node fails to start due to corrupted chain data, which can't be
+	// repaired automatically (chain data should be removed with ResetChainData, so that node is able to start)
+	EventChainDataCorrupted = "chaindata.corrupted"
+
+	// EventNodeConfigChanged is triggered when node is started with config different from the previous one
+	EventNodeConfigChanged = "node.config.changed"
+
+	// EventUpstreamSwitched is triggered when upstream RPC calls start going to another provider
+	EventUpstreamSwitched = "upstream.switched"
+
+	// EventUpstreamDegraded is triggered when circuit breaker opens after consecutive failures of upstream RPC calls
+	EventUpstreamDegraded = "upstream.degraded"
+
+	// EventConnectivityLost is triggered when upstream becomes unreachable, and RPC client goes offline
+	EventConnectivityLost = "connectivity.lost"
+
+	// EventConnectivityRestored is triggered when upstream becomes reachable again, and calls queued while offline are replayed
+	EventConnectivityRestored = "connectivity.restored"
+
+	// EventRPCSubscription is triggered when notification of eth_subscribe subscription is received
+	EventRPCSubscription = "rpc.subscription"
+
+	// EventOperationProgress is triggered when progress of long-running operation (e.g. chain sync) changes
+	EventOperationProgress = "operation.progress"
 )
 
 // Envelope is a general signal sent upward from node to RN app
@@ -41,6 +66,12 @@ type NodeCrashEvent struct {
 	Error string `json:"error"`
 }
 
+// ChainDataCorruptedEvent is sent with EventChainDataCorrupted
+type ChainDataCorruptedEvent struct {
+	Error string `json:"error"`
+	Dir   string `json:"dir"` // directory of corrupted chain data
+}
+
 // NodeNotificationHandler defines a handler able to process incoming node events.
 // Events are encoded as JSON strings.
 type NodeNotificationHandler func(jsonEvent string)
@@ -0,0 +1,98 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+)
+
+// maxConcurrentCalls is a maximum number of balances of tokens, which are queried at once
+const maxConcurrentCalls = 8
+
+// errors
+var (
+	ErrNotTokenContract = errors.New("address is not a token contract")
+)
+
+// balanceOfSelector is a selector of ERC20 balanceOf(address) function
+var balanceOfSelector = []byte{0x70, 0xa0, 0x82, 0x31}
+
+// TokenBalances returns balances of account in tokens (in the smallest units of tokens), keyed by addresses
+// of tokens. Balances are queried with concurrent eth_call requests, as there is no multicall contract,
+// which could be relied on in every network. Tokens of registry are queried, if tokens are not given.
+func (m *Manager) TokenBalances(ctx context.Context, account gethcommon.Address, tokens []gethcommon.Address) (map[gethcommon.Address]*hexutil.Big, error) {
+	if tokens == nil {
+		registry, err := m.Tokens()
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range registry {
+			tokens = append(tokens, token.Address)
+		}
+	}
+
+	client := ethclient.NewClient(m.nodeManager.RPCClient())
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		slots    = make(chan struct{}, maxConcurrentCalls)
+		balances = make(map[gethcommon.Address]*hexutil.Big, len(tokens))
+	)
+	for _, token := range tokens {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(token gethcommon.Address) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			balance, err := balanceOf(ctx, client, token, account)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel() // the rest of calls are of no use
+				}
+				return
+			}
+			balances[token] = (*hexutil.Big)(balance)
+		}(token)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return balances, nil
+}
+
+// balanceOf calls balanceOf(account) of token contract.
+func balanceOf(ctx context.Context, client *ethclient.Client, token, account gethcommon.Address) (*big.Int, error) {
+	data := append(append([]byte{}, balanceOfSelector...), gethcommon.LeftPadBytes(account.Bytes(), 32)...)
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &token,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	// calls of accounts without code succeed with empty result
+	if len(result) != 32 {
+		return nil, ErrNotTokenContract
+	}
+
+	return new(big.Int).SetBytes(result), nil
+}
@@ -0,0 +1,166 @@
+// Package tokens provides registry of ERC20 tokens of the network (bundled with status-go, and updatable
+// by the app), balances of accounts in those tokens, and arguments of transactions transferring them.
+package tokens
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// registryFileName is a name of file (in node's data dir), updated registry of tokens is stored in
+const registryFileName = "tokens.json"
+
+// errors
+var (
+	ErrTokenNotFound        = errors.New("token is not found in registry")
+	ErrInvalidTokenAddress  = errors.New("invalid address of token")
+	ErrDuplicateTokenSymbol = errors.New("duplicate symbol of token")
+)
+
+// Token is an ERC20 token of the network.
+type Token struct {
+	Address  gethcommon.Address `json:"address"`
+	Symbol   string             `json:"symbol"`
+	Name     string             `json:"name"`
+	Decimals uint8              `json:"decimals"`
+}
+
+// bundledTokens are registries of tokens of public networks, used unless registry is updated.
+var bundledTokens = map[uint64][]Token{
+	params.MainNetworkID: {
+		{
+			Address:  gethcommon.HexToAddress("0x744d70FDBE2Ba4CF95131626614a1763DF805B9E"),
+			Symbol:   "SNT",
+			Name:     "Status Network Token",
+			Decimals: 18,
+		},
+		{
+			Address:  gethcommon.HexToAddress("0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359"),
+			Symbol:   "DAI",
+			Name:     "Dai Stablecoin",
+			Decimals: 18,
+		},
+		{
+			Address:  gethcommon.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"),
+			Symbol:   "MKR",
+			Name:     "Maker",
+			Decimals: 18,
+		},
+	},
+	params.RopstenNetworkID: {
+		{
+			Address:  gethcommon.HexToAddress("0xc55cF4B03948D7EBc8b9E8BAD92643703811d162"),
+			Symbol:   "STT",
+			Name:     "Status Test Token",
+			Decimals: 18,
+		},
+	},
+}
+
+// Manager keeps registry of tokens of the running node's network, and queries balances in them.
+type Manager struct {
+	nodeManager common.NodeManager
+	mu          sync.Mutex // serializes updates of registry
+}
+
+// New returns new manager of tokens.
+func New(nodeManager common.NodeManager) *Manager {
+	return &Manager{
+		nodeManager: nodeManager,
+	}
+}
+
+// Tokens returns registry of tokens of the network: updated one, if any, bundled one otherwise.
+func (m *Manager) Tokens() ([]Token, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := ioutil.ReadFile(filepath.Join(config.DataDir, registryFileName))
+	if os.IsNotExist(err) {
+		tokens := bundledTokens[config.NetworkID]
+		if tokens == nil {
+			tokens = []Token{}
+		}
+		return tokens, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var tokens []Token
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// UpdateTokens replaces registry of tokens of the network, so that it's used instead of the bundled one
+// from now on (it's kept in data dir of node). Nil tokens restore the bundled registry.
+func (m *Manager) UpdateTokens(tokens []Token) error {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path := filepath.Join(config.DataDir, registryFileName)
+	if tokens == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	symbols := make(map[string]struct{}, len(tokens))
+	for _, token := range tokens {
+		if token.Address == (gethcommon.Address{}) {
+			return ErrInvalidTokenAddress
+		}
+		symbol := strings.ToUpper(token.Symbol)
+		if _, ok := symbols[symbol]; ok {
+			return ErrDuplicateTokenSymbol
+		}
+		symbols[symbol] = struct{}{}
+	}
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Token returns token of registry, given either its address or symbol (case insensitive).
+func (m *Manager) Token(addressOrSymbol string) (Token, error) {
+	tokens, err := m.Tokens()
+	if err != nil {
+		return Token{}, err
+	}
+
+	isAddress := gethcommon.IsHexAddress(addressOrSymbol)
+	for _, token := range tokens {
+		if isAddress && token.Address == gethcommon.HexToAddress(addressOrSymbol) {
+			return token, nil
+		}
+		if !isAddress && strings.EqualFold(token.Symbol, addressOrSymbol) {
+			return token, nil
+		}
+	}
+
+	return Token{}, ErrTokenNotFound
+}
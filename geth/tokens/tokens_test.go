@@ -0,0 +1,153 @@
+package tokens
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethnode "github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T, networkID uint64, rpcClient *rpc.Client) (*Manager, func()) {
+	dir, err := ioutil.TempDir("", "tokens")
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	config, err := params.NewNodeConfig(dir, networkID, true)
+	require.NoError(t, err)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	nodeManager.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+
+	return New(nodeManager), func() {
+		ctrl.Finish()
+		os.RemoveAll(dir) // nolint: errcheck
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	manager, cleanup := newTestManager(t, params.RopstenNetworkID, nil)
+	defer cleanup()
+
+	// bundled registry
+	registry, err := manager.Tokens()
+	require.NoError(t, err)
+	require.Equal(t, bundledTokens[params.RopstenNetworkID], registry)
+	token, err := manager.Token("stt")
+	require.NoError(t, err)
+	require.Equal(t, "STT", token.Symbol)
+	_, err = manager.Token("SNT")
+	require.Equal(t, ErrTokenNotFound, err)
+
+	// updated registry
+	updated := []Token{
+		{Address: gethcommon.HexToAddress("0x1000000000000000000000000000000000000001"), Symbol: "AAA", Decimals: 6},
+		{Address: gethcommon.HexToAddress("0x1000000000000000000000000000000000000002"), Symbol: "BBB", Decimals: 18},
+	}
+	require.NoError(t, manager.UpdateTokens(updated))
+	registry, err = manager.Tokens()
+	require.NoError(t, err)
+	require.Equal(t, updated, registry)
+	token, err = manager.Token("0x1000000000000000000000000000000000000002")
+	require.NoError(t, err)
+	require.Equal(t, "BBB", token.Symbol)
+	_, err = manager.Token("STT")
+	require.Equal(t, ErrTokenNotFound, err)
+
+	require.Equal(t, ErrDuplicateTokenSymbol, manager.UpdateTokens(append(updated, Token{Address: updated[0].Address, Symbol: "aaa"})))
+	require.Equal(t, ErrInvalidTokenAddress, manager.UpdateTokens([]Token{{Symbol: "CCC"}}))
+
+	// bundled registry is restored
+	require.NoError(t, manager.UpdateTokens(nil))
+	registry, err = manager.Tokens()
+	require.NoError(t, err)
+	require.Equal(t, bundledTokens[params.RopstenNetworkID], registry)
+
+	// networks without bundled registry have no tokens
+	manager, cleanupRinkeby := newTestManager(t, params.RinkebyNetworkID, nil)
+	defer cleanupRinkeby()
+	registry, err = manager.Tokens()
+	require.NoError(t, err)
+	require.Empty(t, registry)
+}
+
+func TestTransfer(t *testing.T) {
+	manager, cleanup := newTestManager(t, params.RopstenNetworkID, nil)
+	defer cleanup()
+
+	from := gethcommon.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	to := gethcommon.HexToAddress("0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2")
+	args, err := manager.TransferArgs(from, "STT", to, big.NewInt(1000))
+	require.NoError(t, err)
+	require.Equal(t, from, args.From)
+	require.Equal(t, bundledTokens[params.RopstenNetworkID][0].Address, *args.To)
+	require.Equal(t, big.NewInt(0), args.Value.ToInt())
+	require.Equal(t, "0xa9059cbb"+
+		"000000000000000000000000ab8483f64d9c6d1ecf9b849ae677dd3315835cb2"+
+		"00000000000000000000000000000000000000000000000000000000000003e8", args.Data.String())
+
+	_, err = manager.TransferArgs(from, "STT", to, big.NewInt(-1))
+	require.Equal(t, ErrInvalidAmount, err)
+	_, err = manager.TransferArgs(from, "SNT", to, big.NewInt(1))
+	require.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestTokenBalances(t *testing.T) {
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	require.NoError(t, err)
+	require.NoError(t, stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	account := gethcommon.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	notToken := gethcommon.HexToAddress("0x2000000000000000000000000000000000000000")
+	balances := map[gethcommon.Address]int64{
+		gethcommon.HexToAddress("0x1000000000000000000000000000000000000001"): 5,
+		gethcommon.HexToAddress("0x1000000000000000000000000000000000000002"): 0,
+		bundledTokens[params.RopstenNetworkID][0].Address:                     7,
+	}
+	rpcClient.RegisterHandler("eth_call", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		call := args[0].(map[string]interface{})
+		data := call["data"].(hexutil.Bytes)
+		require.Equal(t, balanceOfSelector, []byte(data[:4]))
+		require.Equal(t, account, gethcommon.BytesToAddress(data[4:]))
+
+		balance, ok := balances[*call["to"].(*gethcommon.Address)]
+		if !ok {
+			return hexutil.Bytes{}, nil
+		}
+		return hexutil.Bytes(gethcommon.LeftPadBytes(big.NewInt(balance).Bytes(), 32)), nil
+	})
+
+	manager, cleanup := newTestManager(t, params.RopstenNetworkID, rpcClient)
+	defer cleanup()
+
+	result, err := manager.TokenBalances(context.Background(), account, []gethcommon.Address{
+		gethcommon.HexToAddress("0x1000000000000000000000000000000000000001"),
+		gethcommon.HexToAddress("0x1000000000000000000000000000000000000002"),
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, int64(5), result[gethcommon.HexToAddress("0x1000000000000000000000000000000000000001")].ToInt().Int64())
+	require.Equal(t, int64(0), result[gethcommon.HexToAddress("0x1000000000000000000000000000000000000002")].ToInt().Int64())
+
+	// tokens of registry are queried by default
+	result, err = manager.TokenBalances(context.Background(), account, nil)
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, int64(7), result[bundledTokens[params.RopstenNetworkID][0].Address].ToInt().Int64())
+
+	_, err = manager.TokenBalances(context.Background(), account, []gethcommon.Address{notToken})
+	require.Equal(t, ErrNotTokenContract, err)
+}
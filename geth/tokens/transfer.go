@@ -0,0 +1,51 @@
+package tokens
+
+import (
+	"errors"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/status-im/status-go/geth/common"
+)
+
+// errors
+var (
+	ErrInvalidAmount = errors.New("amount of tokens must be a non-negative 256 bit integer")
+)
+
+// transferSelector is a selector of ERC20 transfer(address,uint256) function
+var transferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+// TransferData returns call data of ERC20 transfer of amount of tokens (in the smallest units) to recipient.
+func TransferData(to gethcommon.Address, amount *big.Int) (hexutil.Bytes, error) {
+	if amount == nil || amount.Sign() < 0 || amount.BitLen() > 256 {
+		return nil, ErrInvalidAmount
+	}
+
+	data := append([]byte{}, transferSelector...)
+	data = append(data, gethcommon.LeftPadBytes(to.Bytes(), 32)...)
+	data = append(data, math.PaddedBigBytes(amount, 32)...)
+	return data, nil
+}
+
+// TransferArgs returns arguments of transaction, which transfers amount of token from sender to recipient.
+// Token is given either by its address or symbol, it must be in registry.
+func (m *Manager) TransferArgs(from gethcommon.Address, token string, to gethcommon.Address, amount *big.Int) (common.SendTxArgs, error) {
+	registered, err := m.Token(token)
+	if err != nil {
+		return common.SendTxArgs{}, err
+	}
+	data, err := TransferData(to, amount)
+	if err != nil {
+		return common.SendTxArgs{}, err
+	}
+
+	return common.SendTxArgs{
+		From:  from,
+		To:    &registered.Address,
+		Value: (*hexutil.Big)(new(big.Int)),
+		Data:  data,
+	}, nil
+}
@@ -0,0 +1,315 @@
+// Package txhistory keeps history of transactions of user's accounts: transactions sent by status-go,
+// and ones found in chain (over LES or upstream node), so that wallets don't depend on block explorers.
+package txhistory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// EventTransactionHistoryUpdated is triggered when transactions are added to history of account, or updated
+const EventTransactionHistoryUpdated = "transaction.history_updated"
+
+// Types of transactions
+const (
+	TypeEther = "eth"   // transfer of ether (or any other transaction, sent from or to account)
+	TypeToken = "erc20" // transfer of ERC20 tokens
+)
+
+// Directions of transactions, relative to account
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
+
+// Statuses of transactions
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+const (
+	// defaultScanInterval is an interval, chain is scanned for new transactions of accounts at
+	defaultScanInterval = 15 * time.Second
+
+	// defaultPageSize is a number of transactions returned, if size of page is not set
+	defaultPageSize = 20
+)
+
+// errors
+var (
+	ErrHistoryNotStarted = errors.New("transaction history is not started")
+)
+
+// Transaction is a transaction in history of account.
+type Transaction struct {
+	ID          string              `json:"id"` // hash, along with index of log for token transfers
+	Hash        gethcommon.Hash     `json:"hash"`
+	Type        string              `json:"type"`
+	Direction   string              `json:"direction"`
+	From        gethcommon.Address  `json:"from"`
+	To          *gethcommon.Address `json:"to"`
+	Contract    *gethcommon.Address `json:"contract,omitempty"` // token contract, set for token transfers
+	Value       *hexutil.Big        `json:"value"`
+	Status      string              `json:"status"`
+	BlockNumber uint64              `json:"blockNumber,omitempty"` // unset for pending transactions
+	Timestamp   int64               `json:"timestamp"`             // unix time of block, or of sending for pending transactions
+}
+
+// Page selects a page of history, transactions are ordered from the latest.
+type Page struct {
+	Number int `json:"number"` // starting from 0
+	Size   int `json:"size"`   // defaultPageSize, if 0
+}
+
+// Filter selects transactions of history, empty fields match any transaction.
+type Filter struct {
+	Type      string              `json:"type,omitempty"`
+	Direction string              `json:"direction,omitempty"`
+	Status    string              `json:"status,omitempty"`
+	Contract  *gethcommon.Address `json:"contract,omitempty"`
+	Since     int64               `json:"since,omitempty"` // unix time
+	Until     int64               `json:"until,omitempty"` // unix time
+}
+
+// Matches returns true, if tx is selected by filter.
+func (f Filter) Matches(tx Transaction) bool {
+	switch {
+	case f.Type != "" && f.Type != tx.Type:
+		return false
+	case f.Direction != "" && f.Direction != tx.Direction:
+		return false
+	case f.Status != "" && f.Status != tx.Status:
+		return false
+	case f.Contract != nil && (tx.Contract == nil || *f.Contract != *tx.Contract):
+		return false
+	case f.Since != 0 && tx.Timestamp < f.Since:
+		return false
+	case f.Until != 0 && tx.Timestamp > f.Until:
+		return false
+	}
+
+	return true
+}
+
+// Result is a page of history of account.
+type Result struct {
+	Transactions []Transaction `json:"transactions"`
+	Total        int           `json:"total"` // number of transactions matching filter
+}
+
+// HistoryUpdatedEvent is a signal sent when transactions are added to history of account, or updated
+type HistoryUpdatedEvent struct {
+	Address      string        `json:"address"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// History keeps history of transactions of accounts, listed by account manager (selected ones and watch-only).
+// Transactions sent by status-go are recorded right away; chain is scanned for transactions of accounts
+// since history of account has been started (see scanner).
+type History struct {
+	nodeManager    common.NodeManager
+	accountManager common.AccountManager
+	interval       time.Duration
+	now            func() time.Time
+
+	mu    sync.Mutex // guards store, and serializes updates of history
+	store *store     // nil, if history is not started
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New returns new history of transactions.
+func New(nodeManager common.NodeManager, accountManager common.AccountManager) *History {
+	return &History{
+		nodeManager:    nodeManager,
+		accountManager: accountManager,
+		interval:       defaultScanInterval,
+		now:            time.Now,
+	}
+}
+
+// Start opens history of the running node (in its data dir), and starts scanning chain for transactions
+// of accounts. It is to be called whenever node is started, unless history is disabled.
+func (h *History) Start() error {
+	config, err := h.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+	if config.TxHistoryConfig == nil || !config.TxHistoryConfig.Enabled {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.store != nil {
+		return nil
+	}
+	s, err := openStore(filepath.Join(config.DataDir, storeDirName))
+	if err != nil {
+		return err
+	}
+	h.store = s
+	h.quit = make(chan struct{})
+
+	h.wg.Add(1)
+	go h.scanLoop(h.quit)
+
+	return nil
+}
+
+// Stop stops scanning of chain and closes history.
+func (h *History) Stop() {
+	h.mu.Lock()
+	if h.store == nil {
+		h.mu.Unlock()
+		return
+	}
+	close(h.quit)
+	h.mu.Unlock()
+
+	h.wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.store.close(); err != nil {
+		log.Warn("Failed to close transaction history", "error", err)
+	}
+	h.store = nil
+}
+
+// RecordSent adds transaction, sent by status-go with hash, to history of sender,
+// as a pending transaction (see common.SentTxHandler).
+func (h *History) RecordSent(queuedTx *common.QueuedTx, hash gethcommon.Hash) {
+	tx := Transaction{
+		ID:        hash.Hex(),
+		Hash:      hash,
+		Type:      TypeEther,
+		Direction: DirectionOutgoing,
+		From:      queuedTx.Args.From,
+		To:        queuedTx.Args.To,
+		Value:     queuedTx.Args.Value,
+		Status:    StatusPending,
+		Timestamp: h.now().Unix(),
+	}
+	if tx.Value == nil {
+		tx.Value = (*hexutil.Big)(new(big.Int))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.store == nil {
+		return
+	}
+	if err := h.update(tx.From, []Transaction{tx}); err != nil {
+		log.Warn("Failed to record sent transaction", "hash", hash, "error", err)
+	}
+}
+
+// TransactionHistory returns page of transactions of account, selected by filter, ordered from the latest.
+func (h *History) TransactionHistory(address gethcommon.Address, page Page, filter Filter) (Result, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.store == nil {
+		return Result{}, ErrHistoryNotStarted
+	}
+
+	txs, err := h.store.list(address)
+	if err != nil {
+		return Result{}, err
+	}
+
+	selected := make([]Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if filter.Matches(tx) {
+			selected = append(selected, tx)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].Timestamp != selected[j].Timestamp {
+			return selected[i].Timestamp > selected[j].Timestamp
+		}
+		return selected[i].ID < selected[j].ID
+	})
+
+	size := page.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	result := Result{
+		Transactions: []Transaction{},
+		Total:        len(selected),
+	}
+	if from := page.Number * size; page.Number >= 0 && from < len(selected) {
+		to := from + size
+		if to > len(selected) {
+			to = len(selected)
+		}
+		result.Transactions = selected[from:to]
+	}
+
+	return result, nil
+}
+
+// update adds transactions to history of account, replacing existing ones with the same ID,
+// and sends EventTransactionHistoryUpdated with those, which have changed. It's called with mu held.
+func (h *History) update(address gethcommon.Address, txs []Transaction) error {
+	// the last of transactions with the same ID wins
+	latest := make(map[string]int, len(txs))
+	for i, tx := range txs {
+		latest[tx.ID] = i
+	}
+
+	var updated []Transaction
+	for i, tx := range txs {
+		if latest[tx.ID] != i {
+			continue
+		}
+		existing, found, err := h.store.get(address, tx.ID)
+		if err != nil {
+			return err
+		}
+		if found && sameTransaction(existing, tx) {
+			continue
+		}
+		if err := h.store.put(address, tx); err != nil {
+			return err
+		}
+		updated = append(updated, tx)
+	}
+
+	if len(updated) > 0 {
+		signal.Send(signal.Envelope{
+			Type: EventTransactionHistoryUpdated,
+			Event: HistoryUpdatedEvent{
+				Address:      address.Hex(),
+				Transactions: updated,
+			},
+		})
+	}
+
+	return nil
+}
+
+// sameTransaction returns true, if transactions are equal, as they are stored.
+func sameTransaction(a, b Transaction) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
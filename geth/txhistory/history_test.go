@@ -0,0 +1,224 @@
+package txhistory
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethnode "github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/golang/mock/gomock"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+// testChain serves blocks 0..head, in which account receives ether in block 13 and tokens in block 14,
+// and sends ether in block 15.
+type testChain struct {
+	mu   sync.Mutex
+	head uint64
+
+	account, sender gethcommon.Address
+	sent, received  gethcommon.Hash
+}
+
+func blockArg(arg interface{}) uint64 {
+	return hexutil.MustDecodeUint64(arg.(string))
+}
+
+func (c *testChain) register(client *rpc.Client) {
+	client.RegisterHandler("eth_blockNumber", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return hexutil.Uint64(c.head), nil
+	})
+	client.RegisterHandler("eth_getBalance", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		if blockArg(args[1]) >= 13 {
+			return hexutil.Big(*big.NewInt(1000)), nil
+		}
+		return hexutil.Big(*big.NewInt(0)), nil
+	})
+	client.RegisterHandler("eth_getTransactionCount", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		if blockArg(args[1]) >= 15 {
+			return hexutil.Uint64(1), nil
+		}
+		return hexutil.Uint64(0), nil
+	})
+	client.RegisterHandler("eth_getBlockByNumber", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		number := blockArg(args[0])
+		if !args[1].(bool) {
+			return &types.Header{Number: new(big.Int).SetUint64(number), Time: new(big.Int).SetUint64(1000 + number)}, nil
+		}
+
+		block := &ethclient.Block{
+			Number: (*hexutil.Big)(new(big.Int).SetUint64(number)),
+			Time:   (*hexutil.Big)(new(big.Int).SetUint64(1000 + number)),
+		}
+		switch number {
+		case 13:
+			block.Transactions = []ethclient.RPCTransaction{
+				{Hash: c.received, From: c.sender, To: &c.account, Value: (*hexutil.Big)(big.NewInt(1000))},
+			}
+		case 15:
+			block.Transactions = []ethclient.RPCTransaction{
+				{Hash: c.sent, From: c.account, To: &c.sender, Value: (*hexutil.Big)(big.NewInt(0))},
+			}
+		}
+		return block, nil
+	})
+	client.RegisterHandler("eth_getTransactionReceipt", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		status := hexutil.Uint64(1)
+		receipt := &ethclient.Receipt{TxHash: args[0].(gethcommon.Hash), Status: &status}
+		switch args[0].(gethcommon.Hash) {
+		case c.received:
+			receipt.BlockNumber = (*hexutil.Big)(big.NewInt(13))
+		case c.sent:
+			receipt.BlockNumber = (*hexutil.Big)(big.NewInt(15))
+		default:
+			return (*ethclient.Receipt)(nil), nil
+		}
+		return receipt, nil
+	})
+	client.RegisterHandler("eth_getLogs", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		query := args[0].(map[string]interface{})
+		topics := query["topics"].([][]gethcommon.Hash)
+		if len(topics) < 3 || blockArg(query["fromBlock"]) > 14 || blockArg(query["toBlock"]) < 14 {
+			return []types.Log{}, nil
+		}
+		return []types.Log{{
+			Address:     gethcommon.HexToAddress("0x3333333333333333333333333333333333333333"),
+			Topics:      []gethcommon.Hash{transferTopic, c.sender.Hash(), c.account.Hash()},
+			Data:        gethcommon.LeftPadBytes([]byte{0x10}, 32),
+			BlockNumber: 14,
+			TxHash:      gethcommon.HexToHash("0x14"),
+			Index:       2,
+		}}, nil
+	})
+}
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txhistory")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	require.NoError(t, err)
+	require.NoError(t, stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	chain := &testChain{
+		head:     10,
+		account:  gethcommon.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4"),
+		sender:   gethcommon.HexToAddress("0xAb8483F64d9C6d1EcF9b849Ae677dD3315835cb2"),
+		sent:     gethcommon.HexToHash("0x15"),
+		received: gethcommon.HexToHash("0x13"),
+	}
+	chain.register(rpcClient)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	config, err := params.NewNodeConfig(dir, params.RopstenNetworkID, true)
+	require.NoError(t, err)
+	nodeManager := common.NewMockNodeManager(ctrl)
+	nodeManager.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	nodeManager.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().Accounts().Return([]gethcommon.Address{chain.account}, nil).AnyTimes()
+	accountManager.EXPECT().WatchOnlyAccounts().Return(nil, nil).AnyTimes()
+
+	var mu sync.Mutex
+	var events []HistoryUpdatedEvent
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event HistoryUpdatedEvent
+		}
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		if envelope.Type == EventTransactionHistoryUpdated {
+			mu.Lock()
+			events = append(events, envelope.Event)
+			mu.Unlock()
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	history := New(nodeManager, accountManager)
+	_, err = history.TransactionHistory(chain.account, Page{}, Filter{})
+	require.Equal(t, ErrHistoryNotStarted, err)
+	require.NoError(t, history.Start())
+	defer history.Stop()
+
+	// history of account starts at the current block
+	require.NoError(t, history.scan(nil))
+	value := hexutil.Big(*big.NewInt(0))
+	history.RecordSent(&common.QueuedTx{Args: common.SendTxArgs{From: chain.account, To: &chain.sender, Value: &value}}, chain.sent)
+
+	result, err := history.TransactionHistory(chain.account, Page{}, Filter{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Total)
+	require.Equal(t, StatusPending, result.Transactions[0].Status)
+
+	chain.mu.Lock()
+	chain.head = 16
+	chain.mu.Unlock()
+	require.NoError(t, history.scan(nil))
+
+	result, err = history.TransactionHistory(chain.account, Page{}, Filter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Total)
+	ids := make([]string, 0, len(result.Transactions))
+	for _, tx := range result.Transactions {
+		ids = append(ids, tx.ID)
+	}
+	tokenTransferID := gethcommon.HexToHash("0x14").Hex() + ":2"
+	require.Equal(t, []string{chain.sent.Hex(), tokenTransferID, chain.received.Hex()}, ids)
+
+	sent := result.Transactions[0]
+	require.Equal(t, StatusSuccess, sent.Status)
+	require.Equal(t, DirectionOutgoing, sent.Direction)
+	require.Equal(t, uint64(15), sent.BlockNumber)
+	require.Equal(t, int64(1015), sent.Timestamp)
+
+	tokenTransfer := result.Transactions[1]
+	require.Equal(t, TypeToken, tokenTransfer.Type)
+	require.Equal(t, DirectionIncoming, tokenTransfer.Direction)
+	require.Equal(t, chain.sender, tokenTransfer.From)
+	require.Equal(t, big.NewInt(16), tokenTransfer.Value.ToInt())
+
+	// filters and pages
+	result, err = history.TransactionHistory(chain.account, Page{}, Filter{Direction: DirectionIncoming})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Total)
+	result, err = history.TransactionHistory(chain.account, Page{}, Filter{Type: TypeEther, Since: 1014})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Total)
+	require.Equal(t, chain.sent, result.Transactions[0].Hash)
+	result, err = history.TransactionHistory(chain.account, Page{Number: 1, Size: 2}, Filter{})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Total)
+	require.Len(t, result.Transactions, 1)
+	require.Equal(t, chain.received, result.Transactions[0].Hash)
+
+	// nothing changes, so no updates are signalled
+	require.NoError(t, history.scan(nil))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, events, 2)
+	require.Len(t, events[0].Transactions, 1)
+	require.Len(t, events[1].Transactions, 3)
+	require.Equal(t, chain.account.Hex(), events[1].Address)
+}
@@ -0,0 +1,417 @@
+package txhistory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+)
+
+// transferTopic is a topic of Transfer(address,address,uint256) event of ERC20 tokens
+var transferTopic = gethcommon.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+const (
+	// maxLogsRange is the largest range of blocks, logs of token transfers are requested for at once
+	maxLogsRange = 10000
+
+	// droppedTimeout is a time, after which pending transaction, which is not known to node, is considered dropped
+	droppedTimeout = time.Hour
+)
+
+// scanLoop scans chain for transactions of accounts, until quit is closed.
+func (h *History) scanLoop(quit chan struct{}) {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+
+		if err := h.scan(quit); err != nil {
+			log.Warn("Failed to scan chain for transactions", "error", err)
+		}
+	}
+}
+
+// scan scans blocks, mined since the last scan, for transactions of accounts, and updates pending transactions.
+func (h *History) scan(quit chan struct{}) error {
+	rpcClient := h.nodeManager.RPCClient()
+	if rpcClient == nil {
+		return nil
+	}
+	s := &scanner{
+		client: ethclient.NewClient(rpcClient),
+		ctx:    context.Background(), // calls are limited by timeout and retries of RPC client
+		times:  make(map[uint64]int64),
+	}
+
+	head, err := s.client.BlockNumber(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range h.accounts() {
+		select {
+		case <-quit:
+			return nil
+		default:
+		}
+
+		if err := h.scanAccount(s, address, head); err != nil {
+			log.Warn("Failed to scan chain for transactions of account", "account", log.Address(address.Hex()), "error", err)
+		}
+	}
+
+	return nil
+}
+
+// accounts returns accounts, history is kept for: selected ones and watch-only ones.
+func (h *History) accounts() []gethcommon.Address {
+	addresses, err := h.accountManager.Accounts()
+	if err != nil {
+		log.Warn("Failed to list accounts", "error", err)
+	}
+
+	watchOnly, err := h.accountManager.WatchOnlyAccounts()
+	if err != nil {
+		log.Warn("Failed to list watch-only accounts", "error", err)
+	}
+	for _, account := range watchOnly {
+		address := gethcommon.HexToAddress(account.Address)
+		if !containsAddress(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// scanAccount scans blocks since the last scan up to head for transactions of account.
+// History of account, which has not been scanned before, starts at head.
+func (h *History) scanAccount(s *scanner, address gethcommon.Address, head uint64) error {
+	h.mu.Lock()
+	if h.store == nil {
+		h.mu.Unlock()
+		return nil
+	}
+	scanned, found, err := h.store.scanned(address)
+	if err != nil {
+		h.mu.Unlock()
+		return err
+	}
+	txs, err := h.store.list(address)
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var updated []Transaction
+	for _, tx := range txs {
+		if tx.Status != StatusPending {
+			continue
+		}
+		if tx, changed, err := s.pendingStatus(tx, h.now()); err != nil {
+			log.Warn("Failed to get status of pending transaction", "hash", tx.Hash, "error", err)
+		} else if changed {
+			updated = append(updated, tx)
+		}
+	}
+
+	if found && scanned < head {
+		tokenTransfers, err := s.tokenTransfers(address, scanned+1, head)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, tokenTransfers...)
+
+		// state of old blocks may be unavailable (e.g. upstream node is not an archive one), history of
+		// ether transfers has a gap then, rather than blocking scanning
+		etherTransfers, err := s.etherTransfers(address, scanned, head)
+		if err != nil {
+			log.Warn("Failed to scan blocks for transfers of ether", "from", scanned+1, "to", head, "error", err)
+		}
+		updated = append(updated, etherTransfers...)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.store == nil {
+		return nil
+	}
+	if err := h.update(address, updated); err != nil {
+		return err
+	}
+	if !found || scanned < head {
+		return h.store.setScanned(address, head)
+	}
+
+	return nil
+}
+
+// scanner finds transactions of accounts in chain.
+type scanner struct {
+	client *ethclient.Client
+	ctx    context.Context
+	times  map[uint64]int64 // timestamps of blocks, by their numbers
+}
+
+// blockTime returns timestamp of block.
+func (s *scanner) blockTime(number uint64) (int64, error) {
+	if t, ok := s.times[number]; ok {
+		return t, nil
+	}
+
+	header, err := s.client.HeaderByNumber(s.ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return 0, err
+	}
+	s.times[number] = header.Time.Int64()
+
+	return s.times[number], nil
+}
+
+// tokenTransfers returns transfers of ERC20 tokens, sent from account or to it in blocks from-to (inclusive).
+func (s *scanner) tokenTransfers(address gethcommon.Address, from, to uint64) ([]Transaction, error) {
+	var txs []Transaction
+
+	for start := from; start <= to; start += maxLogsRange {
+		end := start + maxLogsRange - 1
+		if end > to {
+			end = to
+		}
+
+		for _, topics := range [][][]gethcommon.Hash{
+			{{transferTopic}, {address.Hash()}},      // outgoing
+			{{transferTopic}, nil, {address.Hash()}}, // incoming
+		} {
+			logs, err := s.client.FilterLogs(s.ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Topics:    topics,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, l := range logs {
+				// tokenId of ERC721 Transfer event is indexed, so it has 4 topics
+				if l.Removed || len(l.Topics) != 3 {
+					continue
+				}
+				tx, err := s.tokenTransfer(address, l)
+				if err != nil {
+					return nil, err
+				}
+				txs = append(txs, tx)
+			}
+		}
+	}
+
+	return txs, nil
+}
+
+func (s *scanner) tokenTransfer(address gethcommon.Address, l types.Log) (Transaction, error) {
+	timestamp, err := s.blockTime(l.BlockNumber)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	from := gethcommon.BytesToAddress(l.Topics[1].Bytes())
+	to := gethcommon.BytesToAddress(l.Topics[2].Bytes())
+	contract := l.Address
+	direction := DirectionIncoming
+	if from == address {
+		direction = DirectionOutgoing
+	}
+
+	return Transaction{
+		ID:          fmt.Sprintf("%s:%d", l.TxHash.Hex(), l.Index),
+		Hash:        l.TxHash,
+		Type:        TypeToken,
+		Direction:   direction,
+		From:        from,
+		To:          &to,
+		Contract:    &contract,
+		Value:       (*hexutil.Big)(new(big.Int).SetBytes(l.Data)),
+		Status:      StatusSuccess, // logs of failed transactions are discarded
+		BlockNumber: l.BlockNumber,
+		Timestamp:   timestamp,
+	}, nil
+}
+
+// etherTransfers returns transactions, sent from account or to it in blocks after from, up to to (inclusive).
+// Blocks are found by changes of balance and nonce of account, so transactions are found, unless they change
+// neither (e.g. incoming and outgoing transfers of the same value in the same block). Transfers by contracts
+// (internal transactions) change balance, but are not listed in blocks, so they are not found.
+func (s *scanner) etherTransfers(address gethcommon.Address, from, to uint64) ([]Transaction, error) {
+	blocks, err := s.changedBlocks(address, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []Transaction
+	for _, number := range blocks {
+		block, err := s.client.BlockByNumber(s.ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return txs, err
+		}
+		s.times[number] = block.Time.ToInt().Int64()
+
+		for _, blockTx := range block.Transactions {
+			direction := DirectionOutgoing
+			if blockTx.From != address {
+				if blockTx.To == nil || *blockTx.To != address {
+					continue
+				}
+				direction = DirectionIncoming
+			}
+
+			status, err := s.receiptStatus(blockTx.Hash)
+			if err != nil {
+				return txs, err
+			}
+			txs = append(txs, Transaction{
+				ID:          blockTx.Hash.Hex(),
+				Hash:        blockTx.Hash,
+				Type:        TypeEther,
+				Direction:   direction,
+				From:        blockTx.From,
+				To:          blockTx.To,
+				Value:       blockTx.Value,
+				Status:      status,
+				BlockNumber: number,
+				Timestamp:   s.times[number],
+			})
+		}
+	}
+
+	return txs, nil
+}
+
+// accountState is a state of account, which is changed by its transactions.
+type accountState struct {
+	balance *big.Int
+	nonce   uint64
+}
+
+func (s *scanner) state(address gethcommon.Address, block uint64) (accountState, error) {
+	number := new(big.Int).SetUint64(block)
+
+	balance, err := s.client.BalanceAt(s.ctx, address, number)
+	if err != nil {
+		return accountState{}, err
+	}
+	nonce, err := s.client.NonceAt(s.ctx, address, number)
+	if err != nil {
+		return accountState{}, err
+	}
+
+	return accountState{balance: balance, nonce: nonce}, nil
+}
+
+// changedBlocks returns blocks after from, up to to (inclusive), in which state of account has changed.
+func (s *scanner) changedBlocks(address gethcommon.Address, from, to uint64) ([]uint64, error) {
+	fromState, err := s.state(address, from)
+	if err != nil {
+		return nil, err
+	}
+	toState, err := s.state(address, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bisect(address, from, fromState, to, toState)
+}
+
+// bisect finds blocks, state of account has changed in, by bisection of range, it has changed over.
+func (s *scanner) bisect(address gethcommon.Address, from uint64, fromState accountState, to uint64, toState accountState) ([]uint64, error) {
+	if from >= to || (fromState.nonce == toState.nonce && fromState.balance.Cmp(toState.balance) == 0) {
+		return nil, nil
+	}
+	if to-from == 1 {
+		return []uint64{to}, nil
+	}
+
+	mid := from + (to-from)/2
+	midState, err := s.state(address, mid)
+	if err != nil {
+		return nil, err
+	}
+	left, err := s.bisect(address, from, fromState, mid, midState)
+	if err != nil {
+		return nil, err
+	}
+	right, err := s.bisect(address, mid, midState, to, toState)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(left, right...), nil
+}
+
+// receiptStatus returns status of mined transaction.
+func (s *scanner) receiptStatus(hash gethcommon.Hash) (string, error) {
+	receipt, err := s.client.TransactionReceipt(s.ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	// transactions mined before Byzantium are assumed successful
+	if receipt.Status != nil && *receipt.Status == 0 {
+		return StatusFailed, nil
+	}
+
+	return StatusSuccess, nil
+}
+
+// pendingStatus updates status of pending transaction, changed is false, if it's still pending.
+// Transaction, which is unknown to node for droppedTimeout, is considered failed.
+func (s *scanner) pendingStatus(tx Transaction, now time.Time) (Transaction, bool, error) {
+	receipt, err := s.client.TransactionReceipt(s.ctx, tx.Hash)
+	if err == ethereum.NotFound {
+		if now.Sub(time.Unix(tx.Timestamp, 0)) < droppedTimeout {
+			return tx, false, nil
+		}
+		if _, _, err := s.client.TransactionByHash(s.ctx, tx.Hash); err != ethereum.NotFound {
+			return tx, false, err
+		}
+		tx.Status = StatusFailed
+		return tx, true, nil
+	} else if err != nil {
+		return tx, false, err
+	}
+
+	number := receipt.BlockNumber.ToInt().Uint64()
+	timestamp, err := s.blockTime(number)
+	if err != nil {
+		return tx, false, err
+	}
+	tx.Status = StatusSuccess
+	if receipt.Status != nil && *receipt.Status == 0 {
+		tx.Status = StatusFailed
+	}
+	tx.BlockNumber = number
+	tx.Timestamp = timestamp
+
+	return tx, true, nil
+}
+
+func containsAddress(addresses []gethcommon.Address, address gethcommon.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package txhistory
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// storeDirName is a name of directory (in node's data dir), where history of transactions is stored
+const storeDirName = "txhistory"
+
+// prefixes of keys of store
+var (
+	txPrefix      = []byte("tx")      // tx + address + ID of transaction -> Transaction
+	scannedPrefix = []byte("scanned") // scanned + address -> number of the last scanned block
+)
+
+// store keeps transactions of accounts, along with the last blocks, chain has been scanned up to.
+type store struct {
+	db *leveldb.DB
+}
+
+func openStore(path string) (*store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &store{db: db}, nil
+}
+
+func (s *store) close() error {
+	return s.db.Close()
+}
+
+func txKey(address gethcommon.Address, id string) []byte {
+	return append(append(append([]byte{}, txPrefix...), address.Bytes()...), id...)
+}
+
+func (s *store) put(address gethcommon.Address, tx Transaction) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Put(txKey(address, tx.ID), data, nil)
+}
+
+// get returns transaction of account, found is false, if there is no such transaction.
+func (s *store) get(address gethcommon.Address, id string) (tx Transaction, found bool, err error) {
+	data, err := s.db.Get(txKey(address, id), nil)
+	if err == leveldb.ErrNotFound {
+		return tx, false, nil
+	} else if err != nil {
+		return tx, false, err
+	}
+
+	return tx, true, json.Unmarshal(data, &tx)
+}
+
+// list returns all transactions of account.
+func (s *store) list(address gethcommon.Address) ([]Transaction, error) {
+	var txs []Transaction
+
+	it := s.db.NewIterator(util.BytesPrefix(txKey(address, "")), nil)
+	defer it.Release()
+	for it.Next() {
+		var tx Transaction
+		if err := json.Unmarshal(it.Value(), &tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, it.Error()
+}
+
+func scannedKey(address gethcommon.Address) []byte {
+	return append(append([]byte{}, scannedPrefix...), address.Bytes()...)
+}
+
+// scanned returns the last block, chain has been scanned up to for transactions of account.
+func (s *store) scanned(address gethcommon.Address) (block uint64, found bool, err error) {
+	data, err := s.db.Get(scannedKey(address), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	return binary.BigEndian.Uint64(data), true, nil
+}
+
+func (s *store) setScanned(address gethcommon.Address, block uint64) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, block)
+
+	return s.db.Put(scannedKey(address), data, nil)
+}
@@ -0,0 +1,92 @@
+package txqueue
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+)
+
+// Types of transaction envelopes (EIP-2718)
+const (
+	LegacyTxType = 0
+)
+
+// errors
+var (
+	ErrInvalidChainID    = errors.New("chain ID of transaction does not match the network")
+	ErrUnsupportedTxType = errors.New("type of transaction is not supported")
+	ErrUnprotectedTx     = errors.New("transaction is not signed with chain ID of the network")
+)
+
+func init() {
+	for _, err := range []error{
+		ErrInvalidChainID,
+		ErrUnsupportedTxType,
+	} {
+		rpc.RegisterErrorCode(err, rpc.ErrCodeInvalidInput)
+	}
+}
+
+// typedTxSigner is implemented by signers, which are capable of signing typed transaction envelopes.
+type typedTxSigner interface {
+	// SignTypedTx signs tx as an envelope of txType, and returns it encoded, as it's sent to the network.
+	SignTypedTx(txType uint64, tx *types.Transaction, chainID *big.Int) ([]byte, error)
+}
+
+// chainID returns chain ID of the network (EIP-155), transactions are signed for.
+func chainID(config *params.NodeConfig) *big.Int {
+	return new(big.Int).SetUint64(config.NetworkID)
+}
+
+// txType returns type of envelope of transaction.
+func txType(args common.SendTxArgs) uint64 {
+	if args.Type == nil {
+		return LegacyTxType
+	}
+	return uint64(*args.Type)
+}
+
+// checkTransaction refuses queued transaction, which is meant for another network, than the running one,
+// or whose type of envelope may not be signed (see params.SigningConfig).
+func checkTransaction(queuedTx *common.QueuedTx, config *params.NodeConfig) error {
+	args := queuedTx.Args
+	if args.ChainID != nil && args.ChainID.ToInt().Cmp(chainID(config)) != 0 {
+		log.Warn("transaction is meant for another network", "id", queuedTx.ID, "chainID", args.ChainID.ToInt(), "networkID", config.NetworkID)
+		return ErrInvalidChainID
+	}
+
+	if txType(args) != LegacyTxType && (config.SigningConfig == nil || !config.SigningConfig.TypedTransactions) {
+		log.Warn("typed transactions are disabled", "id", queuedTx.ID, "type", txType(args))
+		return ErrUnsupportedTxType
+	}
+
+	return nil
+}
+
+// signTransaction signs tx as an envelope of txType, and returns it encoded. Legacy transactions must be
+// signed with chainID (EIP-155), so that they can't be replayed on other networks.
+func signTransaction(signer txSigner, txType uint64, tx *types.Transaction, chainID *big.Int) ([]byte, error) {
+	if txType != LegacyTxType {
+		typedSigner, ok := signer.(typedTxSigner)
+		if !ok {
+			return nil, ErrUnsupportedTxType
+		}
+		return typedSigner.SignTypedTx(txType, tx, chainID)
+	}
+
+	signedTx, err := signer.SignTx(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !signedTx.Protected() || signedTx.ChainId().Cmp(chainID) != 0 {
+		return nil, ErrUnprotectedTx
+	}
+
+	return rlp.EncodeToBytes(signedTx)
+}
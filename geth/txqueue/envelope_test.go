@@ -0,0 +1,90 @@
+package txqueue
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+// homesteadSigner signs transactions without chain ID, i.e. without replay protection.
+type homesteadSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s homesteadSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.HomesteadSigner{}, s.key)
+}
+
+// envelopeSigner pretends to sign typed transactions.
+type envelopeSigner struct {
+	homesteadSigner
+}
+
+func (s envelopeSigner) SignTypedTx(txType uint64, tx *types.Transaction, chainID *big.Int) ([]byte, error) {
+	return []byte{byte(txType)}, nil
+}
+
+func TestCheckTransaction(t *testing.T) {
+	config, err := params.NewNodeConfig("/tmp", params.RopstenNetworkID, true)
+	require.NoError(t, err)
+
+	legacy := hexutil.Uint64(LegacyTxType)
+	typed := hexutil.Uint64(2)
+	testCases := []struct {
+		name              string
+		args              common.SendTxArgs
+		typedTransactions bool
+		err               error
+	}{
+		{"no chain ID", common.SendTxArgs{}, false, nil},
+		{"chain ID of network", common.SendTxArgs{ChainID: (*hexutil.Big)(big.NewInt(params.RopstenNetworkID))}, false, nil},
+		{"chain ID of another network", common.SendTxArgs{ChainID: (*hexutil.Big)(big.NewInt(params.MainNetworkID))}, false, ErrInvalidChainID},
+		{"legacy type", common.SendTxArgs{Type: &legacy}, false, nil},
+		{"typed transactions disabled", common.SendTxArgs{Type: &typed}, false, ErrUnsupportedTxType},
+		{"typed transactions enabled", common.SendTxArgs{Type: &typed}, true, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.SigningConfig.TypedTransactions = tc.typedTransactions
+			err := checkTransaction(&common.QueuedTx{ID: "id", Args: tc.args}, config)
+			require.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestSignTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	chainID := big.NewInt(params.RopstenNetworkID)
+	tx := types.NewTransaction(1, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+
+	data, err := signTransaction(keySigner{account: &common.SelectedExtKey{
+		AccountKey: &keystore.Key{PrivateKey: key},
+	}}, LegacyTxType, tx, chainID)
+	require.NoError(t, err)
+	signedTx := new(types.Transaction)
+	require.NoError(t, rlp.DecodeBytes(data, signedTx))
+	require.True(t, signedTx.Protected())
+	require.Equal(t, chainID, signedTx.ChainId())
+
+	// signatures without replay protection are refused
+	_, err = signTransaction(homesteadSigner{key: key}, LegacyTxType, tx, chainID)
+	require.Equal(t, ErrUnprotectedTx, err)
+
+	// typed envelopes are signed by signers, which support them
+	_, err = signTransaction(homesteadSigner{key: key}, 2, tx, chainID)
+	require.Equal(t, ErrUnsupportedTxType, err)
+	data, err = signTransaction(envelopeSigner{homesteadSigner{key: key}}, 2, tx, chainID)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, data)
+}
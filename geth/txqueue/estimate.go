@@ -0,0 +1,118 @@
+package txqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+)
+
+// gasPriceOracleTimeout limits time of request to gas price oracle, node is asked for gas price afterwards
+const gasPriceOracleTimeout = 5 * time.Second
+
+// errors
+var (
+	ErrInvalidGasPriceOracleResponse = errors.New("gas price oracle responded without gas price")
+)
+
+// EstimateTransaction estimates gas of transaction with eth_estimateGas, and suggests gas price,
+// unless they are set in args. Gas price oracle is asked for gas price first, if configured
+// (see params.FeeConfig), and the node (the upstream one, if enabled) otherwise, or if oracle fails.
+func (m *Manager) EstimateTransaction(args common.SendTxArgs) (common.TxEstimate, error) {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return common.TxEstimate{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.nodeManager.RPCClient().CallTimeout())
+	defer cancel()
+	client := ethclient.NewClient(m.nodeManager.RPCClient())
+
+	gasPrice := (*big.Int)(args.GasPrice)
+	if gasPrice == nil {
+		if gasPrice, err = m.suggestGasPrice(ctx, client, config); err != nil {
+			return common.TxEstimate{}, err
+		}
+	}
+
+	gas := (*big.Int)(args.Gas)
+	if gas == nil {
+		gas, err = client.EstimateGas(ctx, ethereum.CallMsg{
+			From:     args.From,
+			To:       args.To,
+			GasPrice: gasPrice,
+			Value:    (*big.Int)(args.Value),
+			Data:     []byte(args.Data),
+		})
+		if err != nil {
+			return common.TxEstimate{}, err
+		}
+	}
+
+	return common.TxEstimate{
+		Gas:      (*hexutil.Big)(gas),
+		GasPrice: (*hexutil.Big)(gasPrice),
+		Fee:      (*hexutil.Big)(new(big.Int).Mul(gas, gasPrice)),
+	}, nil
+}
+
+// suggestGasPrice asks gas price oracle for gas price, if it's configured, and the node otherwise,
+// or if oracle fails.
+func (m *Manager) suggestGasPrice(ctx context.Context, client *ethclient.Client, config *params.NodeConfig) (*big.Int, error) {
+	if config.FeeConfig != nil && config.FeeConfig.GasPriceOracleURL != "" {
+		gasPrice, err := fetchGasPrice(ctx, config.FeeConfig.GasPriceOracleURL)
+		if err == nil {
+			return gasPrice, nil
+		}
+		log.Warn("gas price oracle failed, gas price is suggested by node", "url", params.RedactURL(config.FeeConfig.GasPriceOracleURL), "err", err)
+	}
+
+	return client.SuggestGasPrice(ctx)
+}
+
+// fetchGasPrice requests gas price from oracle at URL.
+func fetchGasPrice(ctx context.Context, url string) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, gasPriceOracleTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		if urlErr, ok := err.(*neturl.Error); ok {
+			urlErr.URL = params.RedactURL(urlErr.URL)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	var response struct {
+		GasPrice *math.HexOrDecimal256 `json:"gasPrice"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.GasPrice == nil {
+		return nil, ErrInvalidGasPriceOracleResponse
+	}
+
+	return (*big.Int)(response.GasPrice), nil
+}
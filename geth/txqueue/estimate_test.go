@@ -0,0 +1,41 @@
+package txqueue
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchGasPrice(t *testing.T) {
+	var response string
+	oracle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	defer oracle.Close()
+
+	response = `{"gasPrice": "0x4a817c800"}`
+	gasPrice, err := fetchGasPrice(context.Background(), oracle.URL)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20000000000), gasPrice)
+
+	response = `{"gasPrice": "21000000000"}`
+	gasPrice, err = fetchGasPrice(context.Background(), oracle.URL)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(21000000000), gasPrice)
+
+	response = `{"fast": 30}`
+	_, err = fetchGasPrice(context.Background(), oracle.URL)
+	require.Equal(t, ErrInvalidGasPriceOracleResponse, err)
+
+	_, err = fetchGasPrice(context.Background(), oracle.URL+"/missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package txqueue
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventTransactionAwaitingDevice is triggered when transaction is sent to hardware wallet,
+	// and awaits confirmation on the device
+	EventTransactionAwaitingDevice = "transaction.awaiting_device"
+
+	// EventTransactionDeviceResponded is triggered when user confirms or rejects transaction on the device
+	EventTransactionDeviceResponded = "transaction.device_responded"
+)
+
+// errors
+var (
+	ErrHardwareWalletTypedData = errors.New("signing typed data with hardware wallet is not supported")
+)
+
+// DeviceEvent is a signal sent when transaction is sent to hardware wallet, and when device responds.
+type DeviceEvent struct {
+	ID           string `json:"id"`
+	From         string `json:"from"`
+	ErrorMessage string `json:"error_message,omitempty"` // set, if device has not signed transaction
+}
+
+// txSigner signs transaction of queued transaction sender.
+type txSigner interface {
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// keySigner signs transactions with decrypted key of selected account.
+type keySigner struct {
+	account *common.SelectedExtKey
+}
+
+func (s keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), s.account.AccountKey.PrivateKey)
+}
+
+// hardwareSigner delegates signing to hardware wallet, user is notified with signals to confirm it on the device.
+type hardwareSigner struct {
+	wallet   common.HardwareWallet
+	queuedTx *common.QueuedTx
+}
+
+func (s hardwareSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	event := DeviceEvent{
+		ID:   string(s.queuedTx.ID),
+		From: s.queuedTx.Args.From.Hex(),
+	}
+	signal.Send(signal.Envelope{
+		Type:  EventTransactionAwaitingDevice,
+		Event: event,
+	})
+
+	signedTx, err := s.wallet.SignTx(accounts.Account{Address: s.queuedTx.Args.From}, tx, chainID)
+	if err != nil {
+		log.Warn("hardware wallet has not signed transaction", "id", s.queuedTx.ID, "err", err)
+		event.ErrorMessage = err.Error()
+	}
+	signal.Send(signal.Envelope{
+		Type:  EventTransactionDeviceResponded,
+		Event: event,
+	})
+
+	return signedTx, err
+}
+
+// SetHardwareWallet sets hardware wallet, transactions of its accounts are signed with: they are sent
+// to the device instead of being signed with key of selected account, and password is not needed
+// to complete them. Nil wallet disconnects it.
+func (m *Manager) SetHardwareWallet(wallet common.HardwareWallet) {
+	m.hardwareWalletMx.Lock()
+	m.hardwareWallet = wallet
+	m.hardwareWalletMx.Unlock()
+}
+
+// hardwareWalletOf returns hardware wallet of account, or nil if account is not a hardware one.
+func (m *Manager) hardwareWalletOf(address gethcommon.Address) common.HardwareWallet {
+	m.hardwareWalletMx.RLock()
+	defer m.hardwareWalletMx.RUnlock()
+
+	if m.hardwareWallet == nil || !m.hardwareWallet.Contains(accounts.Account{Address: address}) {
+		return nil
+	}
+
+	return m.hardwareWallet
+}
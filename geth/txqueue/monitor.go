@@ -0,0 +1,188 @@
+package txqueue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// EventTransactionMined is triggered when sent transaction is mined successfully, and confirmed
+// with configured number of blocks (see params.TxMonitorConfig)
+const EventTransactionMined = "transaction.mined"
+
+// defaultMonitorInterval is an interval, receipts of sent transactions are polled at
+const defaultMonitorInterval = 5 * time.Second
+
+// Statuses of receipts of mined transactions
+const (
+	ReceiptStatusFailed     = 0
+	ReceiptStatusSuccessful = 1
+)
+
+// errors
+var (
+	ErrTransactionReverted = errors.New("transaction has failed (reverted)")
+	ErrTransactionNotMined = errors.New("transaction has not been mined in time")
+)
+
+// MinedTransactionEvent is a signal sent when sent transaction is mined and confirmed
+type MinedTransactionEvent struct {
+	ID            string       `json:"id"`
+	Hash          string       `json:"hash"`
+	MessageID     string       `json:"message_id"`
+	Origin        string       `json:"origin"`
+	RequestID     string       `json:"request_id"`
+	Status        uint64       `json:"status"`
+	BlockNumber   uint64       `json:"block_number"`
+	GasUsed       *hexutil.Big `json:"gas_used"`
+	Confirmations uint64       `json:"confirmations"`
+}
+
+// startMonitor starts monitoring of transactions sent by manager, which stops once manager is stopped.
+func (m *Manager) startMonitor() {
+	m.monitorMu.Lock()
+	defer m.monitorMu.Unlock()
+
+	m.monitorStop = make(chan struct{})
+}
+
+func (m *Manager) stopMonitor() {
+	m.monitorMu.Lock()
+	defer m.monitorMu.Unlock()
+
+	if m.monitorStop != nil {
+		close(m.monitorStop)
+		m.monitorStop = nil
+	}
+}
+
+// monitorTransaction starts monitoring of completed transaction, sent with hash, if monitoring is enabled.
+func (m *Manager) monitorTransaction(queuedTx *common.QueuedTx, hash gethcommon.Hash, config *params.TxMonitorConfig) {
+	if config == nil || !config.Enabled {
+		return
+	}
+
+	m.monitorMu.Lock()
+	stop := m.monitorStop
+	m.monitorMu.Unlock()
+	if stop == nil {
+		return
+	}
+
+	client := ethclient.NewClient(m.nodeManager.RPCClient())
+	go m.watchTransaction(client, queuedTx, hash, config, stop)
+}
+
+// watchTransaction polls receipt of transaction, until it's confirmed with config.Confirmations blocks.
+// EventTransactionMined is sent then, or EventTransactionFailed, if it has failed, or has not been mined
+// within config.Timeout.
+func (m *Manager) watchTransaction(client *ethclient.Client, queuedTx *common.QueuedTx, hash gethcommon.Hash, config *params.TxMonitorConfig, stop <-chan struct{}) {
+	ticker := time.NewTicker(m.monitorInterval)
+	defer ticker.Stop()
+	timeout := time.After(time.Duration(config.Timeout) * time.Second)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-timeout:
+			log.Warn("transaction has not been mined in time", "id", queuedTx.ID, "hash", hash)
+			m.notifyFailed(queuedTx, hash, ErrTransactionNotMined)
+			return
+		case <-ticker.C:
+		}
+
+		receipt, confirmations, err := transactionConfirmations(client, hash)
+		if err == ethereum.NotFound {
+			continue
+		} else if err != nil {
+			log.Warn("failed to get receipt of transaction", "hash", hash, "err", err)
+			continue
+		}
+		// transaction is mined, so the rest is a matter of confirmations
+		timeout = nil
+		if confirmations < config.Confirmations {
+			continue
+		}
+
+		if receiptStatus(receipt) == ReceiptStatusFailed {
+			log.Warn("transaction has failed", "id", queuedTx.ID, "hash", hash)
+			m.notifyFailed(queuedTx, hash, ErrTransactionReverted)
+			return
+		}
+
+		log.Info("transaction has been mined", "id", queuedTx.ID, "hash", hash, "confirmations", confirmations)
+		signal.Send(signal.Envelope{
+			Type: EventTransactionMined,
+			Event: MinedTransactionEvent{
+				ID:            string(queuedTx.ID),
+				Hash:          hash.Hex(),
+				MessageID:     common.MessageIDFromContext(queuedTx.Context),
+				Origin:        queuedTx.Origin,
+				RequestID:     rpc.RequestIDFromContext(queuedTx.Context),
+				Status:        ReceiptStatusSuccessful,
+				BlockNumber:   receipt.BlockNumber.ToInt().Uint64(),
+				GasUsed:       receipt.GasUsed,
+				Confirmations: confirmations,
+			},
+		})
+		return
+	}
+}
+
+// transactionConfirmations returns receipt of mined transaction, along with number of blocks it's confirmed with
+// (including the one it's mined in). ethereum.NotFound is returned for transactions, which are not mined yet.
+func transactionConfirmations(client *ethclient.Client, hash gethcommon.Hash) (*ethclient.Receipt, uint64, error) {
+	// Calls are limited by timeout, retries and circuit breaker configured for RPC client.
+	ctx := context.Background()
+
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, 0, err
+	}
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mined := receipt.BlockNumber.ToInt().Uint64()
+	if head < mined {
+		return receipt, 0, nil
+	}
+	return receipt, head - mined + 1, nil
+}
+
+// receiptStatus returns status of receipt, transactions mined before Byzantium are assumed successful.
+func receiptStatus(receipt *ethclient.Receipt) uint64 {
+	if receipt.Status == nil {
+		return ReceiptStatusSuccessful
+	}
+	return uint64(*receipt.Status)
+}
+
+// notifyFailed sends EventTransactionFailed signal for transaction, which has been sent with hash.
+func (m *Manager) notifyFailed(queuedTx *common.QueuedTx, hash gethcommon.Hash, err error) {
+	signal.Send(signal.Envelope{
+		Type: EventTransactionFailed,
+		Event: ReturnSendTransactionEvent{
+			ID:           string(queuedTx.ID),
+			Hash:         hash.Hex(),
+			Args:         queuedTx.Args,
+			MessageID:    common.MessageIDFromContext(queuedTx.Context),
+			Origin:       queuedTx.Origin,
+			RequestID:    rpc.RequestIDFromContext(queuedTx.Context),
+			ErrorMessage: err.Error(),
+			ErrorCode:    m.sendTransactionErrorCode(err),
+		},
+	})
+}
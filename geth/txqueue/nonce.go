@@ -0,0 +1,114 @@
+package txqueue
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/log"
+)
+
+// nonceGapTimeout is a time, within which node may be not aware of transactions we have sent yet
+// (e.g. upstream node is load-balanced). Once it passes, pending nonce of node is trusted again,
+// so that nonces of dropped transactions are reused, and transactions sent afterwards are not stuck.
+const nonceGapTimeout = time.Minute
+
+// nonceSource returns nonce of account in the pending state (see ethclient.Client).
+type nonceSource interface {
+	PendingNonceAt(ctx context.Context, account gethcommon.Address) (uint64, error)
+}
+
+// nonceTracker keeps nonces of transactions, sent from accounts, so that transactions sent in quick
+// succession do not get the same nonce from eth_getTransactionCount, before node is aware of previous ones.
+// It's shared by transactions sent to upstream node and to the local one.
+type nonceTracker struct {
+	mu       sync.Mutex
+	accounts map[gethcommon.Address]*accountNonce
+}
+
+// accountNonce is a nonce of account, locked for the time transaction is signed and sent.
+type accountNonce struct {
+	sync.Mutex
+	next   uint64    // nonce of next transaction, as far as we know
+	sentAt time.Time // time last transaction has been sent at
+}
+
+func newNonceTracker() *nonceTracker {
+	return &nonceTracker{
+		accounts: make(map[gethcommon.Address]*accountNonce),
+	}
+}
+
+func (t *nonceTracker) account(address gethcommon.Address) *accountNonce {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	account, ok := t.accounts[address]
+	if !ok {
+		account = &accountNonce{}
+		t.accounts[address] = account
+	}
+
+	return account
+}
+
+// acquire returns nonce of next transaction sent from address, unless it's set explicitly (nonce isn't nil).
+// No other transaction of account gets nonce, until returned release func is called with result of sending.
+func (t *nonceTracker) acquire(ctx context.Context, source nonceSource, address gethcommon.Address, nonce *hexutil.Uint64) (uint64, func(error), error) {
+	account := t.account(address)
+	account.Lock()
+	release := func(nonce uint64) func(error) {
+		return func(err error) {
+			account.sent(nonce, err)
+			account.Unlock()
+		}
+	}
+
+	if nonce != nil {
+		return uint64(*nonce), release(uint64(*nonce)), nil
+	}
+
+	pending, err := source.PendingNonceAt(ctx, address)
+	if err != nil {
+		account.Unlock()
+		return 0, nil, err
+	}
+	next := account.nonce(pending, address)
+
+	return next, release(next), nil
+}
+
+// nonce returns the larger of pending nonce of node and the local one. Local nonce is larger for a while,
+// after transaction has been sent, but if node is still not aware of it after nonceGapTimeout,
+// transaction must have been dropped, and the gap is repaired by sending next transaction with its nonce.
+func (a *accountNonce) nonce(pending uint64, address gethcommon.Address) uint64 {
+	if pending >= a.next {
+		return pending
+	}
+	if time.Since(a.sentAt) < nonceGapTimeout {
+		return a.next
+	}
+
+	log.Warn("nonce gap detected, reusing nonce of dropped transactions", "account", log.Address(address.Hex()), "pending", pending, "next", a.next)
+	a.next = pending
+	return pending
+}
+
+// sent updates local nonce with result of sending transaction with nonce.
+func (a *accountNonce) sent(nonce uint64, err error) {
+	if err == nil {
+		if nonce >= a.next {
+			a.next = nonce + 1
+		}
+		a.sentAt = time.Now()
+		return
+	}
+
+	// local nonce is behind the node, which is trusted next time
+	if strings.Contains(err.Error(), "nonce too low") {
+		a.next = 0
+	}
+}
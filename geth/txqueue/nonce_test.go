@@ -0,0 +1,86 @@
+package txqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNonceSource uint64
+
+func (s *fakeNonceSource) PendingNonceAt(ctx context.Context, account gethcommon.Address) (uint64, error) {
+	return uint64(*s), nil
+}
+
+func TestNonceTracker(t *testing.T) {
+	var (
+		tracker = newNonceTracker()
+		source  = fakeNonceSource(5)
+		address = gethcommon.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+		ctx     = context.Background()
+	)
+
+	acquire := func(nonce *hexutil.Uint64, sendErr error) uint64 {
+		next, release, err := tracker.acquire(ctx, &source, address, nonce)
+		require.NoError(t, err)
+		release(sendErr)
+		return next
+	}
+
+	// node is not aware of sent transactions yet
+	require.Equal(t, uint64(5), acquire(nil, nil))
+	require.Equal(t, uint64(6), acquire(nil, nil))
+	require.Equal(t, uint64(7), acquire(nil, errors.New("insufficient funds for gas * price + value")))
+	require.Equal(t, uint64(7), acquire(nil, nil))
+
+	// explicit nonce is used as is
+	explicit := hexutil.Uint64(10)
+	require.Equal(t, uint64(10), acquire(&explicit, nil))
+	require.Equal(t, uint64(11), acquire(nil, nil))
+
+	// node is ahead (e.g. transactions were sent with another wallet)
+	source = 20
+	require.Equal(t, uint64(20), acquire(nil, nil))
+
+	// node is still not aware of sent transactions after timeout, so they have been dropped
+	source = 18
+	tracker.account(address).sentAt = time.Now().Add(-nonceGapTimeout)
+	require.Equal(t, uint64(18), acquire(nil, nil))
+	require.Equal(t, uint64(19), acquire(nil, nil))
+
+	// node is trusted once nonce is too low
+	require.Equal(t, uint64(20), acquire(nil, errors.New("nonce too low")))
+	require.Equal(t, uint64(18), acquire(nil, nil))
+}
+
+func TestNonceTrackerConcurrentSends(t *testing.T) {
+	var (
+		tracker = newNonceTracker()
+		source  = fakeNonceSource(0)
+		address = gethcommon.HexToAddress("0x5B38Da6a701c568545dCfcB03FcB875f56beddC4")
+	)
+
+	const sends = 10
+	nonces := make(chan uint64, sends)
+	for i := 0; i < sends; i++ {
+		go func() {
+			nonce, release, err := tracker.acquire(context.Background(), &source, address, nil)
+			require.NoError(t, err)
+			nonces <- nonce
+			release(nil)
+		}()
+	}
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < sends; i++ {
+		nonce := <-nonces
+		require.False(t, seen[nonce], "duplicate nonce %d", nonce)
+		seen[nonce] = true
+	}
+	require.Len(t, seen, sends)
+}
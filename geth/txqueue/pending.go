@@ -0,0 +1,108 @@
+package txqueue
+
+import (
+	"sort"
+	"time"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventTransactionExpired is triggered when queued transaction is not completed within TTL of the queue
+	EventTransactionExpired = "transaction.expired"
+
+	// EventTransactionEvicted is triggered when queued transaction is evicted from full queue,
+	// to make room for another one
+	EventTransactionEvicted = "transaction.evicted"
+
+	// EventTransactionDiscarded is triggered when queued transaction is discarded by user
+	EventTransactionDiscarded = "transaction.discarded"
+)
+
+// Reasons of removal of queued transactions, which are neither completed, nor discarded
+const (
+	ExpiredTimeout = "timeout" // not completed within TTL of the queue (see params.TxQueueConfig)
+	ExpiredEvicted = "evicted" // evicted to make room for another transaction, once queue is full
+)
+
+// ExpiredTransactionEvent is a signal sent when queued transaction expires, or is evicted
+type ExpiredTransactionEvent struct {
+	ID        string            `json:"id"`
+	Args      common.SendTxArgs `json:"args"`
+	MessageID string            `json:"message_id"`
+	Origin    string            `json:"origin"`
+	RequestID string            `json:"request_id"`
+	Reason    string            `json:"reason"`
+}
+
+// DiscardedTransactionEvent is a signal sent when queued transaction is discarded
+type DiscardedTransactionEvent struct {
+	ID        string            `json:"id"`
+	Args      common.SendTxArgs `json:"args"`
+	MessageID string            `json:"message_id"`
+	Origin    string            `json:"origin"`
+	RequestID string            `json:"request_id"`
+}
+
+// PendingTransactions returns transactions (and requests to sign typed data), which wait to be completed
+// or discarded, in order they have been queued.
+func (m *Manager) PendingTransactions() []common.PendingTransaction {
+	queued := m.txQueue.All()
+	ttl := m.txQueue.TTL()
+	sort.Slice(queued, func(i, j int) bool { return queued[i].Queued.Before(queued[j].Queued) })
+
+	pending := make([]common.PendingTransaction, 0, len(queued))
+	for _, queuedTx := range queued {
+		pending = append(pending, common.PendingTransaction{
+			ID:        string(queuedTx.ID),
+			Args:      queuedTx.Args,
+			Origin:    queuedTx.Origin,
+			MessageID: common.MessageIDFromContext(queuedTx.Context),
+			TypedData: typedDataJSON(queuedTx),
+			QueuedAt:  unixMilli(queuedTx.Queued),
+			ExpiresAt: unixMilli(queuedTx.Queued.Add(ttl)),
+		})
+	}
+
+	return pending
+}
+
+// notifyExpired sends EventTransactionExpired signal, or EventTransactionEvicted for evicted transaction.
+func notifyExpired(queuedTx *common.QueuedTx, reason string) {
+	eventType := EventTransactionExpired
+	if reason == ExpiredEvicted {
+		eventType = EventTransactionEvicted
+	}
+
+	signal.Send(signal.Envelope{
+		Type: eventType,
+		Event: ExpiredTransactionEvent{
+			ID:        string(queuedTx.ID),
+			Args:      queuedTx.Args,
+			MessageID: common.MessageIDFromContext(queuedTx.Context),
+			Origin:    queuedTx.Origin,
+			RequestID: rpc.RequestIDFromContext(queuedTx.Context),
+			Reason:    reason,
+		},
+	})
+}
+
+// notifyDiscarded sends EventTransactionDiscarded signal.
+func notifyDiscarded(queuedTx *common.QueuedTx) {
+	signal.Send(signal.Envelope{
+		Type: EventTransactionDiscarded,
+		Event: DiscardedTransactionEvent{
+			ID:        string(queuedTx.ID),
+			Args:      queuedTx.Args,
+			MessageID: common.MessageIDFromContext(queuedTx.Context),
+			Origin:    queuedTx.Origin,
+			RequestID: rpc.RequestIDFromContext(queuedTx.Context),
+		},
+	})
+}
+
+func unixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
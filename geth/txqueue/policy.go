@@ -0,0 +1,161 @@
+package txqueue
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// errors
+var (
+	ErrAccountNotAllowed     = errors.New("account is not allowed to sign transactions")
+	ErrTxValueAboveLimit     = errors.New("transaction value and fee are above auto-approval limit")
+	ErrTxNotPlainTransfer    = errors.New("only plain transfers are approved automatically")
+	ErrPasswordFileRequired  = errors.New("password file is required for auto-approval")
+	ErrInvalidAllowedAccount = errors.New("invalid address of allowed account")
+	ErrInvalidMaxValue       = errors.New("invalid auto-approval max value")
+)
+
+// SigningPolicy decides, which queued transactions may be signed, and (if auto-approval is on)
+// approves or rejects them without user interaction. It is configured with params.SigningConfig.
+type SigningPolicy struct {
+	allowed     map[gethcommon.Address]struct{} // nil means any account is allowed
+	autoApprove bool
+	maxValue    *big.Int
+	password    string
+}
+
+// NewSigningPolicy creates signing policy from config, reading password file (if auto-approval is on,
+// password file is required, so that transactions are never signed with an empty password).
+// Nil is returned for nil config, i.e. transactions are neither restricted, nor auto-approved.
+func NewSigningPolicy(config *params.SigningConfig) (*SigningPolicy, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	policy := &SigningPolicy{
+		autoApprove: config.AutoApprove,
+		maxValue:    new(big.Int),
+	}
+
+	if len(config.AllowedAccounts) > 0 {
+		policy.allowed = make(map[gethcommon.Address]struct{})
+		for _, address := range config.AllowedAccounts {
+			if !gethcommon.IsHexAddress(address) {
+				return nil, fmt.Errorf("%v: %s", ErrInvalidAllowedAccount, address)
+			}
+			policy.allowed[gethcommon.HexToAddress(address)] = struct{}{}
+		}
+	}
+
+	if !policy.autoApprove {
+		return policy, nil
+	}
+
+	if config.AutoApproveMaxValue != "" {
+		if _, ok := policy.maxValue.SetString(config.AutoApproveMaxValue, 10); !ok || policy.maxValue.Sign() < 0 {
+			return nil, fmt.Errorf("%v: %s", ErrInvalidMaxValue, config.AutoApproveMaxValue)
+		}
+	}
+
+	if config.PasswordFile == "" {
+		return nil, ErrPasswordFileRequired
+	}
+	password, err := ioutil.ReadFile(config.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	policy.password = strings.TrimRight(string(password), "\r\n")
+
+	return policy, nil
+}
+
+// Allowed checks whether sender of transaction is permitted to sign it.
+func (p *SigningPolicy) Allowed(tx *common.QueuedTx) error {
+	return p.AllowedAccount(tx.Args.From)
+}
+
+// AllowedAccount checks whether account is permitted to sign transactions and messages.
+func (p *SigningPolicy) AllowedAccount(address gethcommon.Address) error {
+	if p == nil || p.allowed == nil {
+		return nil
+	}
+
+	if _, ok := p.allowed[address]; !ok {
+		return ErrAccountNotAllowed
+	}
+
+	return nil
+}
+
+// Approve checks whether transaction is approved automatically: only plain transfers (without data,
+// which could move tokens regardless of value) are approved, if their value and maximum fee (gas * gas price,
+// both expected to be set) are within limit. ErrTxNotPlainTransfer or ErrTxValueAboveLimit is returned
+// for transactions, which are rejected.
+func (p *SigningPolicy) Approve(tx *common.QueuedTx) error {
+	if tx.Args.To == nil || len(tx.Args.Data) > 0 {
+		return ErrTxNotPlainTransfer
+	}
+
+	if tx.Args.Gas == nil || tx.Args.GasPrice == nil {
+		return ErrTxValueAboveLimit
+	}
+	cost := new(big.Int).Mul((*big.Int)(tx.Args.Gas), (*big.Int)(tx.Args.GasPrice))
+	if tx.Args.Value != nil {
+		cost.Add(cost, (*big.Int)(tx.Args.Value))
+	}
+	if cost.Cmp(p.maxValue) > 0 {
+		return ErrTxValueAboveLimit
+	}
+
+	return nil
+}
+
+// AutoApprove returns true, if queued transactions are approved or rejected without user interaction.
+func (p *SigningPolicy) AutoApprove() bool {
+	return p != nil && p.autoApprove
+}
+
+// SetSigningPolicy sets policy, queued transactions are checked against (nil config disables it).
+func (m *Manager) SetSigningPolicy(config *params.SigningConfig) error {
+	policy, err := NewSigningPolicy(config)
+	if err != nil {
+		return err
+	}
+
+	m.policyMx.Lock()
+	m.policy = policy
+	m.policyMx.Unlock()
+
+	return nil
+}
+
+func (m *Manager) signingPolicy() *SigningPolicy {
+	m.policyMx.RLock()
+	defer m.policyMx.RUnlock()
+
+	return m.policy
+}
+
+// completeApproved completes automatically approved transaction. If it can't be signed
+// (e.g. password is wrong), transaction is discarded, as there is no one to retry.
+func (m *Manager) completeApproved(policy *SigningPolicy, tx *common.QueuedTx) {
+	log.Info("transaction approved by signing policy", "id", tx.ID)
+
+	if _, err := m.CompleteTransaction(tx.ID, policy.password); err == keystore.ErrDecrypt {
+		log.Warn("failed to sign approved transaction", "id", tx.ID, "err", err)
+		if err := m.DiscardTransaction(tx.ID); err != nil {
+			log.Warn("failed to discard transaction", "id", tx.ID, "err", err)
+		}
+	} else if err != nil {
+		log.Warn("failed to complete approved transaction", "id", tx.ID, "err", err)
+	}
+}
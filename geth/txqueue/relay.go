@@ -0,0 +1,228 @@
+package txqueue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+)
+
+// relayTimeout limits time of request to gas relay
+const relayTimeout = 30 * time.Second
+
+// errors
+var (
+	ErrRelayValue            = errors.New("relayed calls can not transfer ether")
+	ErrRelayContractCreation = errors.New("contracts can not be created with relayed calls")
+	ErrInvalidRelayResponse  = errors.New("gas relay responded without transaction hash")
+	ErrNotRelayHub           = errors.New("address of relay hub is not a relay hub contract")
+)
+
+// relayed calls, which can not be relayed, are invalid input of eth_sendTransaction
+func init() {
+	for _, err := range []error{
+		ErrRelayValue,
+		ErrRelayContractCreation,
+	} {
+		rpc.RegisterErrorCode(err, rpc.ErrCodeInvalidInput)
+	}
+}
+
+// getNonceSelector is a selector of getNonce(address) function of relay hub
+var getNonceSelector = crypto.Keccak256([]byte("getNonce(address)"))[:4]
+
+// RelayRequest is a signed relayed call, which is posted to gas relay. Relay wraps it into
+// a transaction to relay hub, and pays for gas, while recipient contract compensates relay.
+type RelayRequest struct {
+	From            gethcommon.Address `json:"from"`
+	To              gethcommon.Address `json:"to"`
+	EncodedFunction hexutil.Bytes      `json:"encodedFunction"`
+	RelayFee        *hexutil.Big       `json:"relayFee"`
+	GasPrice        *hexutil.Big       `json:"gasPrice"`
+	GasLimit        *hexutil.Big       `json:"gasLimit"`
+	SenderNonce     *hexutil.Big       `json:"senderNonce"`
+	RelayHubAddress gethcommon.Address `json:"relayHubAddress"`
+	Signature       hexutil.Bytes      `json:"signature"`
+}
+
+// Hash returns hash of relayed call, which is signed by sender: keccak256 of "rlx:" prefix followed by
+// tightly packed sender, recipient, call data, relay fee, gas price, gas limit, nonce and relay hub.
+func (r *RelayRequest) Hash() []byte {
+	return crypto.Keccak256(
+		[]byte("rlx:"),
+		r.From.Bytes(),
+		r.To.Bytes(),
+		r.EncodedFunction,
+		math.PaddedBigBytes(r.RelayFee.ToInt(), 32),
+		math.PaddedBigBytes(r.GasPrice.ToInt(), 32),
+		math.PaddedBigBytes(r.GasLimit.ToInt(), 32),
+		math.PaddedBigBytes(r.SenderNonce.ToInt(), 32),
+		r.RelayHubAddress.Bytes(),
+	)
+}
+
+// completeRelayedTransaction signs transaction as relayed call, and submits it to gas relay
+// (see params.RelayConfig), instead of sending it as raw transaction, so that sender needs no ether.
+// Hash of transaction, sent by relay to relay hub, is returned.
+func (m *Manager) completeRelayedTransaction(queuedTx *common.QueuedTx, password string, passwordVerified bool) (gethcommon.Hash, error) {
+	log.Info("complete transaction using gas relay", "id", queuedTx.ID)
+
+	var emptyHash gethcommon.Hash
+
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return emptyHash, err
+	}
+
+	args := queuedTx.Args
+	if args.To == nil {
+		return emptyHash, ErrRelayContractCreation
+	}
+	if args.Value != nil && args.Value.ToInt().Sign() != 0 {
+		return emptyHash, ErrRelayValue
+	}
+
+	selectedAcct, err := m.senderAccount(args.From)
+	if err != nil {
+		return emptyHash, err
+	}
+	if !passwordVerified {
+		if _, err := m.accountManager.VerifyAccountPassword(
+			config.KeyStoreDir,
+			selectedAcct.Address.String(),
+			password,
+		); err != nil {
+			log.Warn("failed to verify account", "account", log.Address(selectedAcct.Address.String()), "error", err.Error())
+			return emptyHash, err
+		}
+	}
+
+	// Calls are limited by timeout, retries and circuit breaker configured for RPC client.
+	ctx := context.Background()
+	client := ethclient.NewClient(m.nodeManager.RPCClient())
+
+	request, err := m.relayRequest(ctx, client, config, args)
+	if err != nil {
+		return emptyHash, err
+	}
+	signature, err := crypto.Sign(account.MessageHash(request.Hash()), selectedAcct.AccountKey.PrivateKey)
+	if err != nil {
+		return emptyHash, err
+	}
+	signature[64] += 27
+	request.Signature = signature
+
+	return submitRelayRequest(ctx, config.RelayConfig.URL, request)
+}
+
+// relayRequest fills in relayed call of transaction, gas price and limit are suggested and estimated,
+// unless they are set in args, and nonce of sender is requested from relay hub.
+func (m *Manager) relayRequest(ctx context.Context, client *ethclient.Client, config *params.NodeConfig, args common.SendTxArgs) (*RelayRequest, error) {
+	var err error
+
+	gasPrice := (*big.Int)(args.GasPrice)
+	if gasPrice == nil {
+		gasPrice, err = m.suggestGasPrice(ctx, client, config)
+		if err != nil {
+			log.Warn("failed to get gas price", "err", err)
+			return nil, err
+		}
+	}
+
+	gas := (*big.Int)(args.Gas)
+	if gas == nil {
+		gas, err = client.EstimateGas(ctx, ethereum.CallMsg{
+			From:     args.From,
+			To:       args.To,
+			GasPrice: gasPrice,
+			Data:     []byte(args.Data),
+		})
+		if err != nil {
+			log.Warn("failed to estimate gas", "err", err)
+			return nil, err
+		}
+	}
+
+	hub := gethcommon.HexToAddress(config.RelayConfig.HubAddress)
+	result, err := client.CallContract(ctx, ethereum.CallMsg{
+		To:   &hub,
+		Data: append(append([]byte{}, getNonceSelector...), gethcommon.LeftPadBytes(args.From.Bytes(), 32)...),
+	}, nil)
+	if err != nil {
+		log.Warn("failed to get nonce of relay hub", "err", err)
+		return nil, err
+	}
+	// calls of accounts without code succeed with empty result
+	if len(result) != 32 {
+		return nil, ErrNotRelayHub
+	}
+
+	return &RelayRequest{
+		From:            args.From,
+		To:              *args.To,
+		EncodedFunction: args.Data,
+		RelayFee:        (*hexutil.Big)(new(big.Int).SetUint64(config.RelayConfig.Fee)),
+		GasPrice:        (*hexutil.Big)(gasPrice),
+		GasLimit:        (*hexutil.Big)(gas),
+		SenderNonce:     (*hexutil.Big)(new(big.Int).SetBytes(result)),
+		RelayHubAddress: hub,
+	}, nil
+}
+
+// submitRelayRequest posts relayed call to gas relay at URL, and returns hash of transaction sent by relay.
+func submitRelayRequest(ctx context.Context, url string, request *RelayRequest) (gethcommon.Hash, error) {
+	ctx, cancel := context.WithTimeout(ctx, relayTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	var response struct {
+		TxHash *gethcommon.Hash `json:"txHash"`
+		Error  string           `json:"error"`
+	}
+	// relays explain refused calls in body of error responses
+	decodeErr := json.NewDecoder(resp.Body).Decode(&response)
+	if response.Error != "" {
+		return gethcommon.Hash{}, fmt.Errorf("gas relay refused call: %s", response.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return gethcommon.Hash{}, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return gethcommon.Hash{}, decodeErr
+	}
+	if response.TxHash == nil {
+		return gethcommon.Hash{}, ErrInvalidRelayResponse
+	}
+
+	return *response.TxHash, nil
+}
@@ -0,0 +1,180 @@
+package txqueue
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventTransactionReplaced is triggered when pending transaction is replaced (sped up or cancelled)
+	EventTransactionReplaced = "transaction.replaced"
+
+	// EventTransactionReplacementFailed is triggered when pending transaction could not be replaced
+	EventTransactionReplacementFailed = "transaction.replacement_failed"
+)
+
+// Actions, pending transactions are replaced with
+const (
+	ReplaceSpeedUp = "speedup" // the same transaction, with higher gas price
+	ReplaceCancel  = "cancel"  // transfer of zero value to sender, with higher gas price
+)
+
+// minGasPriceBump is a minimal increase of gas price (in percents), replacing transaction is accepted
+// by transaction pool of node with (see core.DefaultTxPoolConfig).
+const minGasPriceBump = 10
+
+// errors
+var (
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrTransactionNotPending = errors.New("transaction is not pending")
+	ErrGasPriceTooLow        = errors.New("gas price must be at least 10% higher than gas price of replaced transaction")
+)
+
+// ReplacedTransactionEvent is a signal sent when pending transaction is replaced, or replacement fails
+type ReplacedTransactionEvent struct {
+	Hash         string `json:"hash"`
+	NewHash      string `json:"new_hash,omitempty"`
+	Action       string `json:"action"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// replaceFunc builds transaction, replacing pending transaction tx, sent from account from.
+type replaceFunc func(ctx context.Context, client *ethclient.Client, tx *types.Transaction, from gethcommon.Address) (*types.Transaction, error)
+
+// SpeedUpTransaction sends pending transaction again, with the same nonce and higher gas price,
+// so that it's mined sooner. Gas price must be at least 10% higher than the original one.
+func (m *Manager) SpeedUpTransaction(hash gethcommon.Hash, gasPrice *big.Int, password string) (gethcommon.Hash, error) {
+	return m.replaceTransaction(hash, password, ReplaceSpeedUp, func(ctx context.Context, client *ethclient.Client, tx *types.Transaction, from gethcommon.Address) (*types.Transaction, error) {
+		if gasPrice == nil || gasPrice.Cmp(minReplacementGasPrice(tx)) < 0 {
+			return nil, ErrGasPriceTooLow
+		}
+		if tx.To() == nil {
+			return types.NewContractCreation(tx.Nonce(), tx.Value(), tx.Gas(), gasPrice, tx.Data()), nil
+		}
+		return types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data()), nil
+	})
+}
+
+// CancelTransaction replaces pending transaction with transfer of zero value to sender, with the same nonce
+// and higher gas price (at least 10% higher than the original one, or suggested gas price, if it's higher).
+func (m *Manager) CancelTransaction(hash gethcommon.Hash, password string) (gethcommon.Hash, error) {
+	return m.replaceTransaction(hash, password, ReplaceCancel, func(ctx context.Context, client *ethclient.Client, tx *types.Transaction, from gethcommon.Address) (*types.Transaction, error) {
+		config, err := m.nodeManager.NodeConfig()
+		if err != nil {
+			return nil, err
+		}
+		gasPrice, err := m.suggestGasPrice(ctx, client, config)
+		if err != nil {
+			return nil, err
+		}
+		if minGasPrice := minReplacementGasPrice(tx); gasPrice.Cmp(minGasPrice) < 0 {
+			gasPrice = minGasPrice
+		}
+		return types.NewTransaction(tx.Nonce(), from, new(big.Int), new(big.Int).SetUint64(params.TxGas), gasPrice, nil), nil
+	})
+}
+
+// replaceTransaction replaces pending transaction, sent from selected account, with transaction built by replace,
+// which is signed and sent the same way as queued transactions are (see completeSignedTransaction).
+// Result of replacement is signalled with EventTransactionReplaced or EventTransactionReplacementFailed.
+func (m *Manager) replaceTransaction(hash gethcommon.Hash, password, action string, replace replaceFunc) (newHash gethcommon.Hash, err error) {
+	log.Info("replace transaction", "hash", hash, "action", action)
+	defer func() {
+		notifyReplaced(hash, newHash, action, err)
+	}()
+
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return newHash, err
+	}
+
+	// Calls are limited by timeout, retries and circuit breaker configured for RPC client.
+	ctx := context.Background()
+	client := ethclient.NewClient(m.nodeManager.RPCClient())
+
+	tx, pending, err := client.TransactionByHash(ctx, hash)
+	if err == ethereum.NotFound {
+		return newHash, ErrTransactionNotFound
+	} else if err != nil {
+		return newHash, err
+	}
+	if !pending {
+		return newHash, ErrTransactionNotPending
+	}
+
+	// signature of transaction, sent to another network, is valid, but replacing it would be a mistake
+	if tx.Protected() && tx.ChainId().Cmp(chainID(config)) != 0 {
+		return newHash, ErrInvalidChainID
+	}
+	from, err := types.Sender(transactionSigner(tx), tx)
+	if err != nil {
+		return newHash, err
+	}
+	selectedAcct, err := m.senderAccount(from)
+	if err == account.ErrAccountNotSelected {
+		return newHash, ErrInvalidCompleteTxSender
+	} else if err != nil {
+		return newHash, err
+	}
+	if _, err = m.accountManager.VerifyAccountPassword(config.KeyStoreDir, from.Hex(), password); err != nil {
+		log.Warn("failed to verify account", "account", log.Address(from.Hex()), "error", err.Error())
+		return newHash, err
+	}
+
+	newTx, err := replace(ctx, client, tx, from)
+	if err != nil {
+		return newHash, err
+	}
+	signedTx, err := signTransaction(keySigner{account: selectedAcct}, LegacyTxType, newTx, chainID(config))
+	if err != nil {
+		return newHash, err
+	}
+
+	return client.SendRawTransactionData(ctx, signedTx)
+}
+
+// minReplacementGasPrice returns the lowest gas price, transaction pool of node accepts replacement of tx with.
+func minReplacementGasPrice(tx *types.Transaction) *big.Int {
+	gasPrice := new(big.Int).Mul(tx.GasPrice(), big.NewInt(100+minGasPriceBump))
+	gasPrice.Add(gasPrice, big.NewInt(99)) // round up
+	return gasPrice.Div(gasPrice, big.NewInt(100))
+}
+
+// transactionSigner returns signer, sender of tx is recovered with.
+func transactionSigner(tx *types.Transaction) types.Signer {
+	if tx.Protected() {
+		return types.NewEIP155Signer(tx.ChainId())
+	}
+	return types.HomesteadSigner{}
+}
+
+// notifyReplaced sends EventTransactionReplaced, or EventTransactionReplacementFailed signal, if err isn't nil.
+func notifyReplaced(hash, newHash gethcommon.Hash, action string, err error) {
+	event := ReplacedTransactionEvent{
+		Hash:   hash.Hex(),
+		Action: action,
+	}
+	eventType := EventTransactionReplaced
+	if err != nil {
+		log.Warn("failed to replace transaction", "hash", hash, "action", action, "err", err)
+		eventType = EventTransactionReplacementFailed
+		event.ErrorMessage = err.Error()
+	} else {
+		event.NewHash = newHash.Hex()
+	}
+
+	signal.Send(signal.Envelope{
+		Type:  eventType,
+		Event: event,
+	})
+}
@@ -0,0 +1,248 @@
+package txqueue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// EventSignRequestQueued is triggered when request to sign a message (personal_sign, eth_sign) is queued
+const EventSignRequestQueued = "sign_request.queued"
+
+// errors
+var (
+	ErrSignRequestNotFound  = errors.New("sign request not found")
+	ErrSignRequestDiscarded = errors.New("sign request has been discarded")
+	ErrSignRequestTimedOut  = errors.New("sign request timed out")
+	ErrInvalidSignParams    = errors.New("address and data are expected")
+)
+
+// errors of personal_sign and eth_sign are reported the same way as errors of transactions
+func init() {
+	for _, err := range []error{ErrSignRequestDiscarded, ErrSignRequestTimedOut} {
+		rpc.RegisterErrorCode(err, rpc.ErrCodeTransactionRejected)
+	}
+}
+
+// SignRequest is a request to sign a message, which waits for user approval.
+type SignRequest struct {
+	ID      common.SignRequestID
+	Method  string // personal_sign or eth_sign
+	Address gethcommon.Address
+	Data    hexutil.Bytes
+	Context context.Context
+	Origin  string // chatID (or dapp origin), message has been requested to be signed by
+
+	done      chan struct{} // closed, once request is approved or discarded
+	signature hexutil.Bytes
+	err       error
+}
+
+// signQueue keeps sign requests, until they are approved, discarded or time out.
+type signQueue struct {
+	mu       sync.Mutex
+	requests map[common.SignRequestID]*SignRequest
+}
+
+func newSignQueue() *signQueue {
+	return &signQueue{requests: make(map[common.SignRequestID]*SignRequest)}
+}
+
+func (q *signQueue) add(request *SignRequest) {
+	q.mu.Lock()
+	q.requests[request.ID] = request
+	q.mu.Unlock()
+}
+
+func (q *signQueue) get(id common.SignRequestID) (*SignRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	request, ok := q.requests[id]
+	if !ok {
+		return nil, ErrSignRequestNotFound
+	}
+	return request, nil
+}
+
+// complete removes request from queue, and sets its result, unless it has been completed already.
+func (q *signQueue) complete(request *SignRequest, signature []byte, err error) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.requests[request.ID]; !ok {
+		return false
+	}
+	delete(q.requests, request.ID)
+
+	request.signature = signature
+	request.err = err
+	close(request.done)
+
+	return true
+}
+
+// SignRequestEvent is a signal sent on a request to sign a message
+type SignRequestEvent struct {
+	ID        string             `json:"id"`
+	Method    string             `json:"method"`
+	Address   gethcommon.Address `json:"address"`
+	Data      hexutil.Bytes      `json:"data"`
+	MessageID string             `json:"message_id"`
+	Origin    string             `json:"origin"`
+	RequestID string             `json:"request_id"`
+}
+
+// SignRPCHandler returns handler for personal_sign (data, address) or eth_sign (address, data) method.
+// Requests are queued, and EventSignRequestQueued signal is sent, so that user could approve
+// (see ApproveSignRequest) or discard (see DiscardSignRequest) them. Handler returns signature,
+// once request is approved.
+func (m *Manager) SignRPCHandler(method string) rpc.Handler {
+	return func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		log.Info("SignRPCHandler called", "method", method)
+
+		address, data, err := signParams(method, args)
+		if err != nil {
+			return nil, err
+		}
+		if m.accountManager.IsWatchOnlyAccount(address.Hex()) {
+			log.Warn("sign request of watch-only account rejected", "method", method)
+			return nil, account.ErrWatchOnlyAccount
+		}
+		if err := m.signingPolicy().AllowedAccount(address); err != nil {
+			log.Warn("sign request rejected by signing policy", "method", method, "err", err)
+			return nil, err
+		}
+
+		request := &SignRequest{
+			ID:      common.SignRequestID(uuid.New()),
+			Method:  method,
+			Address: address,
+			Data:    data,
+			Context: ctx,
+			Origin:  common.OriginFromContext(ctx),
+			done:    make(chan struct{}),
+		}
+		m.signRequests.add(request)
+
+		log.Info("queue a new sign request", "id", request.ID, "method", method, "address", log.Address(address.Hex()),
+			"origin", request.Origin, "requestID", rpc.RequestIDFromContext(ctx))
+		signal.Send(signal.Envelope{
+			Type: EventSignRequestQueued,
+			Event: SignRequestEvent{
+				ID:        string(request.ID),
+				Method:    method,
+				Address:   address,
+				Data:      data,
+				MessageID: common.MessageIDFromContext(ctx),
+				Origin:    request.Origin,
+				RequestID: rpc.RequestIDFromContext(ctx),
+			},
+		})
+
+		select {
+		case <-request.done:
+		case <-ctx.Done():
+			// request of cancelled call is discarded, so that it's not approved by user in vain
+			m.signRequests.complete(request, nil, ctx.Err())
+			<-request.done
+		case <-time.After(DefaultTxSendCompletionTimeout * time.Second):
+			m.signRequests.complete(request, nil, ErrSignRequestTimedOut)
+			<-request.done
+		}
+
+		if request.err != nil {
+			return nil, request.err
+		}
+		return request.signature, nil
+	}
+}
+
+// ApproveSignRequest signs message of a given request with selected account, once password is verified.
+// Request stays in queue, if password is wrong.
+func (m *Manager) ApproveSignRequest(id common.SignRequestID, password string) (hexutil.Bytes, error) {
+	log.Info("approve sign request", "id", id)
+
+	request, err := m.signRequests.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	address := request.Address.Hex()
+	if _, err := m.accountManager.VerifyAccountPassword(config.KeyStoreDir, address, password); err != nil {
+		log.Warn("failed to verify account", "account", log.Address(address), "error", err.Error())
+		if err == keystore.ErrDecrypt {
+			return nil, err
+		}
+		m.signRequests.complete(request, nil, err)
+		return nil, err
+	}
+
+	signature, err := m.accountManager.SignMessage(address, request.Data)
+	if !m.signRequests.complete(request, signature, err) {
+		return nil, ErrSignRequestNotFound
+	}
+
+	return signature, err
+}
+
+// DiscardSignRequest discards a given request to sign a message.
+func (m *Manager) DiscardSignRequest(id common.SignRequestID) error {
+	request, err := m.signRequests.get(id)
+	if err != nil {
+		return err
+	}
+
+	if !m.signRequests.complete(request, nil, ErrSignRequestDiscarded) {
+		return ErrSignRequestNotFound
+	}
+
+	return nil
+}
+
+// signParams returns signer address and data of personal_sign or eth_sign request.
+// Data which is not hex encoded is signed as text.
+func signParams(method string, args []interface{}) (gethcommon.Address, []byte, error) {
+	if len(args) < 2 {
+		return gethcommon.Address{}, nil, ErrInvalidSignParams
+	}
+
+	addressArg, dataArg := args[0], args[1]
+	if method == "personal_sign" {
+		addressArg, dataArg = args[1], args[0]
+	}
+
+	address, ok := addressArg.(string)
+	if !ok || !gethcommon.IsHexAddress(address) {
+		return gethcommon.Address{}, nil, ErrInvalidSignParams
+	}
+	data, ok := dataArg.(string)
+	if !ok {
+		return gethcommon.Address{}, nil, ErrInvalidSignParams
+	}
+
+	if strings.HasPrefix(data, "0x") {
+		if decoded, err := hexutil.Decode(data); err == nil {
+			return gethcommon.HexToAddress(address), decoded, nil
+		}
+	}
+
+	return gethcommon.HexToAddress(address), []byte(data), nil
+}
@@ -0,0 +1,81 @@
+package txqueue
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/status-im/status-go/geth/common"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// txStoreDirName is a name of directory (in node's data dir), where queued transactions are persisted
+const txStoreDirName = "txqueue"
+
+// storedTx is a queued transaction, persisted in txStore. Context of transaction is not persisted,
+// only values, transaction is tagged with.
+type storedTx struct {
+	ID        common.QueuedTxID `json:"id"`
+	Args      common.SendTxArgs `json:"args"`
+	Origin    string            `json:"origin,omitempty"`
+	MessageID string            `json:"message_id,omitempty"`
+	Queued    int64             `json:"queued"` // unix time (in nanoseconds), transactions are restored in this order
+}
+
+// txStore keeps queued transactions, so that they could be restored after restart (or crash) of the app.
+type txStore struct {
+	db *leveldb.DB
+}
+
+// openTxStore opens store of queued transactions at path.
+func openTxStore(path string) (*txStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &txStore{db: db}, nil
+}
+
+func (s *txStore) put(tx *common.QueuedTx) error {
+	data, err := json.Marshal(storedTx{
+		ID:        tx.ID,
+		Args:      tx.Args,
+		Origin:    tx.Origin,
+		MessageID: common.MessageIDFromContext(tx.Context),
+		Queued:    tx.Queued.UnixNano(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.Put([]byte(tx.ID), data, nil)
+}
+
+func (s *txStore) delete(id common.QueuedTxID) error {
+	return s.db.Delete([]byte(id), nil)
+}
+
+// all returns persisted transactions, in order they have been queued.
+func (s *txStore) all() ([]storedTx, error) {
+	var txs []storedTx
+
+	it := s.db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		var tx storedTx
+		if err := json.Unmarshal(it.Value(), &tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Queued < txs[j].Queued })
+
+	return txs, nil
+}
+
+func (s *txStore) close() error {
+	return s.db.Close()
+}
@@ -10,14 +10,16 @@ import (
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
 )
 
 const (
-	// DefaultTxQueueCap defines how many items can be queued.
+	// DefaultTxQueueCap defines how many items can be queued, unless configured (see params.TxQueueConfig).
 	DefaultTxQueueCap = int(35)
 	// DefaultTxSendQueueCap defines how many items can be passed to sendTransaction() w/o blocking.
 	DefaultTxSendQueueCap = int(70)
-	// DefaultTxSendCompletionTimeout defines how many seconds to wait before returning result in sentTransaction().
+	// DefaultTxSendCompletionTimeout defines how many seconds to wait before returning result in sentTransaction(),
+	// unless configured (see params.TxQueueConfig).
 	DefaultTxSendCompletionTimeout = 300
 )
 
@@ -25,6 +27,8 @@ var (
 	ErrQueuedTxIDNotFound       = errors.New("transaction hash not found")
 	ErrQueuedTxTimedOut         = errors.New("transaction sending timed out")
 	ErrQueuedTxDiscarded        = errors.New("transaction has been discarded")
+	ErrQueuedTxEvicted          = errors.New("transaction has been evicted from full queue")
+	ErrQueueFull                = errors.New("transaction queue is full")
 	ErrQueuedTxInProgress       = errors.New("transaction is in progress")
 	ErrQueuedTxAlreadyProcessed = errors.New("transaction has been already processed")
 	ErrInvalidCompleteTxSender  = errors.New("transaction can only be completed by the same account which created it")
@@ -32,11 +36,15 @@ var (
 
 // TxQueue is capped container that holds pending transactions
 type TxQueue struct {
-	transactions  map[common.QueuedTxID]*common.QueuedTx
-	mu            sync.RWMutex // to guard transactions map
-	evictableIDs  chan common.QueuedTxID
-	enqueueTicker chan struct{}
-	incomingPool  chan *common.QueuedTx
+	transactions map[common.QueuedTxID]*common.QueuedTx
+	order        []common.QueuedTxID // IDs of transactions in order they are queued, to evict in FIFO
+	mu           sync.RWMutex        // to guard transactions map, order and limits
+	incomingPool chan *common.QueuedTx
+
+	// limits of queue, see setConfig
+	capacity int
+	ttl      time.Duration
+	eviction string
 
 	// when this channel is closed, all queue channels processing must cease (incoming queue, processing queued items etc)
 	stopped      chan struct{}
@@ -47,20 +55,50 @@ type TxQueue struct {
 
 	// when tx is returned (either successfully or with error) notify subscriber
 	txReturnHandler common.EnqueuedTxReturnHandler
+
+	// queued transactions are persisted there, if set (guarded by mu)
+	store *txStore
 }
 
 // NewTransactionQueue make new transaction queue
 func NewTransactionQueue() *TxQueue {
 	log.Info("initializing transaction queue")
 	return &TxQueue{
-		transactions:  make(map[common.QueuedTxID]*common.QueuedTx),
-		evictableIDs:  make(chan common.QueuedTxID, DefaultTxQueueCap), // will be used to evict in FIFO
-		enqueueTicker: make(chan struct{}),
-		incomingPool:  make(chan *common.QueuedTx, DefaultTxSendQueueCap),
+		transactions: make(map[common.QueuedTxID]*common.QueuedTx),
+		incomingPool: make(chan *common.QueuedTx, DefaultTxSendQueueCap),
+		capacity:     DefaultTxQueueCap,
+		ttl:          DefaultTxSendCompletionTimeout * time.Second,
+		eviction:     params.TxQueueEvictOldest,
+	}
+}
+
+// setConfig sets limits of queue, defaults are used for nil config. Transactions over capacity, if it's
+// lowered, are evicted (or make queue reject new ones) once another transaction is queued.
+func (q *TxQueue) setConfig(config *params.TxQueueConfig) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if config == nil {
+		config = &params.TxQueueConfig{
+			Size:     DefaultTxQueueCap,
+			TTL:      DefaultTxSendCompletionTimeout,
+			Eviction: params.TxQueueEvictOldest,
+		}
 	}
+	q.capacity = config.Size
+	q.ttl = time.Duration(config.TTL) * time.Second
+	q.eviction = config.Eviction
+}
+
+// TTL returns time, within which queued transaction must be completed, otherwise it expires.
+func (q *TxQueue) TTL() time.Duration {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.ttl
 }
 
-// Start starts enqueue and eviction loops
+// Start starts enqueue loop
 func (q *TxQueue) Start() {
 	log.Info("starting transaction queue")
 
@@ -69,13 +107,12 @@ func (q *TxQueue) Start() {
 	}
 
 	q.stopped = make(chan struct{})
-	q.stoppedGroup.Add(2)
+	q.stoppedGroup.Add(1)
 
-	go q.evictionLoop()
 	go q.enqueueLoop()
 }
 
-// Stop stops transaction enqueue and eviction loops
+// Stop stops transaction enqueue loop
 func (q *TxQueue) Stop() {
 	log.Info("stopping transaction queue")
 
@@ -83,36 +120,13 @@ func (q *TxQueue) Stop() {
 		return
 	}
 
-	close(q.stopped) // stops all processing loops (enqueue, eviction etc)
+	close(q.stopped) // stops all processing loops
 	q.stoppedGroup.Wait()
 	q.stopped = nil
 
 	log.Info("finally stopped transaction queue")
 }
 
-// evictionLoop frees up queue to accommodate another transaction item
-func (q *TxQueue) evictionLoop() {
-	defer HaltOnPanic()
-	evict := func() {
-		if len(q.transactions) >= DefaultTxQueueCap { // eviction is required to accommodate another/last item
-			q.Remove(<-q.evictableIDs)
-		}
-	}
-
-	for {
-		select {
-		case <-time.After(250 * time.Millisecond): // do not wait for manual ticks, check queue regularly
-			evict()
-		case <-q.enqueueTicker: // when manually requested
-			evict()
-		case <-q.stopped:
-			log.Info("transaction queue's eviction loop stopped")
-			q.stoppedGroup.Done()
-			return
-		}
-	}
-}
-
 // enqueueLoop process incoming enqueue requests
 func (q *TxQueue) enqueueLoop() {
 	defer HaltOnPanic()
@@ -138,7 +152,7 @@ func (q *TxQueue) Reset() {
 	defer q.mu.Unlock()
 
 	q.transactions = make(map[common.QueuedTxID]*common.QueuedTx)
-	q.evictableIDs = make(chan common.QueuedTxID, DefaultTxQueueCap)
+	q.order = nil
 }
 
 // EnqueueAsync enqueues incoming transaction in async manner, returns as soon as possible
@@ -148,7 +162,8 @@ func (q *TxQueue) EnqueueAsync(tx *common.QueuedTx) error {
 	return nil
 }
 
-// Enqueue enqueues incoming transaction
+// Enqueue enqueues incoming transaction. Once queue is full, either the oldest transactions are evicted
+// to make room for it, or ErrQueueFull is returned, depending on eviction policy of queue.
 func (q *TxQueue) Enqueue(tx *common.QueuedTx) error {
 	log.Info(fmt.Sprintf("enqueue transaction: %s", tx.ID))
 
@@ -157,16 +172,30 @@ func (q *TxQueue) Enqueue(tx *common.QueuedTx) error {
 		return nil
 	}
 
-	log.Info("before enqueueTicker")
-	q.enqueueTicker <- struct{}{} // notify eviction loop that we are trying to insert new item
-	log.Info("before evictableIDs")
-	q.evictableIDs <- tx.ID // this will block when we hit DefaultTxQueueCap
-	log.Info("after evictableIDs")
-
 	q.mu.Lock()
+	evicted, err := q.makeRoom()
+	if err != nil {
+		q.mu.Unlock()
+		log.Warn("transaction rejected by full queue", "id", tx.ID)
+		return err
+	}
+	tx.Queued = time.Now()
 	q.transactions[tx.ID] = tx
+	q.order = append(q.order, tx.ID)
+	q.persist(tx)
 	q.mu.Unlock()
 
+	for _, evictedTx := range evicted {
+		log.Warn("transaction evicted from full queue", "id", evictedTx.ID)
+		notifyExpired(evictedTx, ExpiredEvicted)
+
+		// let requester of transaction know, that it's not waited for anymore
+		select {
+		case evictedTx.Discard <- struct{}{}:
+		default:
+		}
+	}
+
 	// notify handler
 	log.Info("calling txEnqueueHandler")
 	q.txEnqueueHandler(tx)
@@ -174,6 +203,33 @@ func (q *TxQueue) Enqueue(tx *common.QueuedTx) error {
 	return nil
 }
 
+// makeRoom evicts the oldest transactions, which are not in progress, until there is room for another one,
+// unless policy is to reject new transactions. It's called with lock held.
+func (q *TxQueue) makeRoom() (evicted []*common.QueuedTx, err error) {
+	for len(q.transactions) >= q.capacity {
+		if q.eviction == params.TxQueueRejectNew {
+			return evicted, ErrQueueFull
+		}
+
+		var oldest *common.QueuedTx
+		for _, id := range q.order {
+			if tx := q.transactions[id]; !tx.InProgress {
+				oldest = tx
+				break
+			}
+		}
+		if oldest == nil { // every transaction is being completed
+			return evicted, ErrQueueFull
+		}
+
+		q.remove(oldest.ID)
+		oldest.Err = ErrQueuedTxEvicted
+		evicted = append(evicted, oldest)
+	}
+
+	return evicted, nil
+}
+
 // Get returns transaction by transaction identifier
 func (q *TxQueue) Get(id common.QueuedTxID) (*common.QueuedTx, error) {
 	q.mu.RLock()
@@ -186,12 +242,82 @@ func (q *TxQueue) Get(id common.QueuedTxID) (*common.QueuedTx, error) {
 	return nil, ErrQueuedTxIDNotFound
 }
 
+// ByOrigin returns transactions, requested by a given origin
+func (q *TxQueue) ByOrigin(origin string) []*common.QueuedTx {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	var txs []*common.QueuedTx
+	for _, tx := range q.transactions {
+		if tx.Origin == origin {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs
+}
+
+// All returns all queued transactions
+func (q *TxQueue) All() []*common.QueuedTx {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	txs := make([]*common.QueuedTx, 0, len(q.transactions))
+	for _, tx := range q.transactions {
+		txs = append(txs, tx)
+	}
+
+	return txs
+}
+
 // Remove removes transaction by transaction identifier
 func (q *TxQueue) Remove(id common.QueuedTxID) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	q.remove(id)
+}
+
+// remove removes transaction, along with persisted one. It's called with lock held.
+func (q *TxQueue) remove(id common.QueuedTxID) {
 	delete(q.transactions, id)
+	for i, queuedID := range q.order {
+		if queuedID == id {
+			q.order = append(q.order[:i], q.order[i+1:]...)
+			break
+		}
+	}
+	if q.store != nil {
+		if err := q.store.delete(id); err != nil {
+			log.Warn("failed to remove persisted transaction", "id", id, "err", err)
+		}
+	}
+}
+
+// persist stores transaction, if queue is persistent. Requests to sign typed data are not persisted,
+// as signature could not be returned to requester, once app is restarted. Must be called with lock held.
+func (q *TxQueue) persist(tx *common.QueuedTx) {
+	if q.store == nil || tx.TypedData != "" {
+		return
+	}
+
+	if err := q.store.put(tx); err != nil {
+		log.Warn("failed to persist transaction", "id", tx.ID, "err", err)
+	}
+}
+
+// setStore makes queue persistent: transactions queued from now on are kept in store, until they are
+// removed from queue. Previous store (if any) is closed, nil store makes queue in-memory only.
+func (q *TxQueue) setStore(store *txStore) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.store != nil {
+		if err := q.store.close(); err != nil {
+			log.Warn("failed to close store of transactions", "err", err)
+		}
+	}
+	q.store = store
 }
 
 // StartProcessing marks a transaction as in progress. It's thread-safe and
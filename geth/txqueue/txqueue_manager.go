@@ -2,18 +2,25 @@ package txqueue
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
+	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	gethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/les/status"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/account"
 	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/log"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
 	"github.com/status-im/status-go/geth/signal"
 )
 
@@ -35,6 +42,10 @@ const (
 	SendTransactionPasswordErrorCode  = "2"
 	SendTransactionTimeoutErrorCode   = "3"
 	SendTransactionDiscardedErrorCode = "4"
+	SendTransactionRevertedErrorCode  = "5"
+	SendTransactionNotMinedErrorCode  = "6"
+	SendTransactionChainIDErrorCode   = "7"
+	SendTransactionEvictedErrorCode   = "8"
 )
 
 var txReturnCodes = map[error]string{ // deliberately strings, in case more meaningful codes are to be returned
@@ -42,6 +53,27 @@ var txReturnCodes = map[error]string{ // deliberately strings, in case more mean
 	keystore.ErrDecrypt:  SendTransactionPasswordErrorCode,
 	ErrQueuedTxTimedOut:  SendTransactionTimeoutErrorCode,
 	ErrQueuedTxDiscarded: SendTransactionDiscardedErrorCode,
+
+	ErrTransactionReverted: SendTransactionRevertedErrorCode,
+	ErrTransactionNotMined: SendTransactionNotMinedErrorCode,
+	ErrInvalidChainID:      SendTransactionChainIDErrorCode,
+	ErrQueuedTxEvicted:     SendTransactionEvictedErrorCode,
+}
+
+// errors of eth_sendTransaction are reported to JavaScript as rejected transactions (EIP-1474)
+func init() {
+	for _, err := range []error{
+		keystore.ErrDecrypt,
+		ErrQueuedTxTimedOut,
+		ErrQueuedTxDiscarded,
+		ErrQueuedTxEvicted,
+		ErrAccountNotAllowed,
+		ErrTxValueAboveLimit,
+		account.ErrWatchOnlyAccount,
+	} {
+		rpc.RegisterErrorCode(err, rpc.ErrCodeTransactionRejected)
+	}
+	rpc.RegisterErrorCode(ErrQueueFull, rpc.ErrCodeLimitExceeded)
 }
 
 // Manager provides means to manage internal Status Backend (injected into LES)
@@ -49,6 +81,20 @@ type Manager struct {
 	nodeManager    common.NodeManager
 	accountManager common.AccountManager
 	txQueue        *TxQueue
+	signRequests   *signQueue // requests to sign messages (personal_sign, eth_sign)
+	nonces         *nonceTracker
+	policy         *SigningPolicy
+	policyMx       sync.RWMutex
+
+	hardwareWallet   common.HardwareWallet // see SetHardwareWallet
+	hardwareWalletMx sync.RWMutex
+
+	monitorInterval time.Duration // interval, receipts of sent transactions are polled at
+	monitorStop     chan struct{} // closed, once manager is stopped
+	monitorMu       sync.Mutex
+
+	sentHandler   common.SentTxHandler // see SetTransactionSentHandler
+	sentHandlerMx sync.RWMutex
 }
 
 // NewManager returns a new Manager.
@@ -57,6 +103,10 @@ func NewManager(nodeManager common.NodeManager, accountManager common.AccountMan
 		nodeManager:    nodeManager,
 		accountManager: accountManager,
 		txQueue:        NewTransactionQueue(),
+		signRequests:   newSignQueue(),
+		nonces:         newNonceTracker(),
+
+		monitorInterval: defaultMonitorInterval,
 	}
 }
 
@@ -64,12 +114,73 @@ func NewManager(nodeManager common.NodeManager, accountManager common.AccountMan
 func (m *Manager) Start() {
 	log.Info("start Manager")
 	m.txQueue.Start()
+	m.startMonitor()
 }
 
 // Stop stops accepting new transactions into the queue.
 func (m *Manager) Stop() {
 	log.Info("stop Manager")
 	m.txQueue.Stop()
+	m.txQueue.setStore(nil)
+	m.stopMonitor()
+}
+
+// RestoreTransactions makes transaction queue persistent (in data dir of node), and queues transactions,
+// persisted before restart (or crash) of the app, again, so that EventTransactionQueued is sent for each
+// of them. It is to be called once node is started, and transaction queue handler is set.
+func (m *Manager) RestoreTransactions() error {
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
+		return err
+	}
+
+	m.txQueue.setStore(nil) // store is locked, until it's closed
+	store, err := openTxStore(filepath.Join(config.DataDir, txStoreDirName))
+	if err != nil {
+		return err
+	}
+	stored, err := store.all()
+	if err != nil {
+		store.close() // nolint: errcheck
+		return err
+	}
+	m.txQueue.setStore(store)
+
+	for _, storedTx := range stored {
+		if m.txQueue.Has(storedTx.ID) { // node has been restarted, but transaction is still waited for
+			continue
+		}
+
+		ctx := context.WithValue(context.Background(), common.OriginKey, storedTx.Origin)
+		ctx = context.WithValue(ctx, common.MessageIDKey, storedTx.MessageID)
+		tx := &common.QueuedTx{
+			ID:      storedTx.ID,
+			Context: ctx,
+			Args:    storedTx.Args,
+			Origin:  storedTx.Origin,
+			Done:    make(chan struct{}, 1),
+			Discard: make(chan struct{}, 1),
+		}
+
+		log.Info("restore queued transaction", "id", tx.ID)
+		if err := m.QueueTransaction(tx); err != nil {
+			log.Warn("restored transaction rejected", "id", tx.ID, "err", err)
+			m.txQueue.Remove(tx.ID)
+			continue
+		}
+
+		// requester of transaction is gone, so nobody else waits for it to be completed, discarded
+		// or timed out (and removed from the queue then)
+		go m.WaitForTransaction(tx) // nolint: errcheck
+	}
+
+	return nil
+}
+
+// SetQueueConfig sets capacity of transaction queue, TTL of queued transactions and eviction policy
+// (see params.TxQueueConfig), defaults are used for nil config.
+func (m *Manager) SetQueueConfig(config *params.TxQueueConfig) {
+	m.txQueue.setConfig(config)
 }
 
 // TransactionQueue returns a reference to the queue.
@@ -84,6 +195,7 @@ func (m *Manager) CreateTransaction(ctx context.Context, args common.SendTxArgs)
 		Hash:    gethcommon.Hash{},
 		Context: ctx,
 		Args:    args,
+		Origin:  common.OriginFromContext(ctx),
 		Done:    make(chan struct{}, 1),
 		Discard: make(chan struct{}, 1),
 	}
@@ -95,9 +207,47 @@ func (m *Manager) QueueTransaction(tx *common.QueuedTx) error {
 	if tx.Args.To != nil {
 		to = tx.Args.To.Hex()
 	}
-	log.Info("queue a new transaction", "id", tx.ID, "from", tx.Args.From.Hex(), "to", to)
+	log.Info("queue a new transaction", "id", tx.ID, "from", log.Address(tx.Args.From.Hex()), "to", log.Address(to), "origin", tx.Origin,
+		"requestID", rpc.RequestIDFromContext(tx.Context))
+
+	if m.accountManager.IsWatchOnlyAccount(tx.Args.From.Hex()) {
+		log.Warn("transaction of watch-only account rejected", "id", tx.ID)
+		return account.ErrWatchOnlyAccount
+	}
+
+	policy := m.signingPolicy()
+	if err := policy.Allowed(tx); err != nil {
+		log.Warn("transaction rejected by signing policy", "id", tx.ID, "err", err)
+		return err
+	}
+	// typed data (e.g. permits) is not limited by value, so it always waits for user approval
+	autoApprove := policy.AutoApprove() && tx.TypedData == ""
+	if autoApprove {
+		// gas and gas price are fixed before approval, so that fee of signed transaction is within limit
+		if tx.Args.To != nil && len(tx.Args.Data) == 0 && (tx.Args.Gas == nil || tx.Args.GasPrice == nil) {
+			estimate, err := m.EstimateTransaction(tx.Args)
+			if err != nil {
+				log.Warn("failed to estimate auto-approved transaction", "id", tx.ID, "err", err)
+				return err
+			}
+			tx.Args.Gas = estimate.Gas
+			tx.Args.GasPrice = estimate.GasPrice
+		}
+		if err := policy.Approve(tx); err != nil {
+			log.Warn("transaction rejected by signing policy", "id", tx.ID, "err", err)
+			return err
+		}
+	}
 
-	return m.txQueue.Enqueue(tx)
+	if err := m.txQueue.Enqueue(tx); err != nil {
+		return err
+	}
+
+	if autoApprove {
+		go m.completeApproved(policy, tx)
+	}
+
+	return nil
 }
 
 // WaitForTransaction adds a transaction to the queue and blocks
@@ -108,15 +258,22 @@ func (m *Manager) WaitForTransaction(tx *common.QueuedTx) error {
 	// now wait up until transaction is:
 	// - completed (via CompleteQueuedTransaction),
 	// - discarded (via DiscardQueuedTransaction)
-	// - or times out
+	// - or times out (or is evicted from full queue)
 	select {
 	case <-tx.Done:
 		m.NotifyOnQueuedTxReturn(tx, tx.Err)
 		return tx.Err
 	case <-tx.Discard:
-		m.NotifyOnQueuedTxReturn(tx, ErrQueuedTxDiscarded)
-		return ErrQueuedTxDiscarded
-	case <-time.After(DefaultTxSendCompletionTimeout * time.Second):
+		err := ErrQueuedTxDiscarded
+		if tx.Err == ErrQueuedTxEvicted {
+			err = ErrQueuedTxEvicted
+		}
+		m.NotifyOnQueuedTxReturn(tx, err)
+		return err
+	case <-time.After(m.txQueue.TTL()):
+		if m.txQueue.Has(tx.ID) { // unless it has been evicted already
+			notifyExpired(tx, ExpiredTimeout)
+		}
 		m.NotifyOnQueuedTxReturn(tx, ErrQueuedTxTimedOut)
 		return ErrQueuedTxTimedOut
 	}
@@ -131,6 +288,12 @@ func (m *Manager) NotifyOnQueuedTxReturn(queuedTx *common.QueuedTx, err error) {
 // TODO(adam): investigate a possible bug that calling this method multiple times with the same Transaction ID
 // results in sending multiple transactions.
 func (m *Manager) CompleteTransaction(id common.QueuedTxID, password string) (gethcommon.Hash, error) {
+	return m.completeTransaction(id, password, false)
+}
+
+// completeTransaction completes transaction, password of sender of which is not verified again,
+// if passwordVerified is true (see CompleteTransactions).
+func (m *Manager) completeTransaction(id common.QueuedTxID, password string, passwordVerified bool) (gethcommon.Hash, error) {
 	log.Info("complete transaction", "id", id)
 
 	queuedTx, err := m.txQueue.Get(id)
@@ -144,17 +307,18 @@ func (m *Manager) CompleteTransaction(id common.QueuedTxID, password string) (ge
 	}
 	defer m.txQueue.StopProcessing(queuedTx)
 
-	selectedAccount, err := m.accountManager.SelectedAccount()
-	if err != nil {
-		log.Warn("failed to get a selected account", "err", err)
-		return gethcommon.Hash{}, err
-	}
-
-	// make sure that only account which created the tx can complete it
-	if queuedTx.Args.From.Hex() != selectedAccount.Address.Hex() {
+	// make sure that only account which created the tx can complete it,
+	// transactions of hardware wallet accounts are confirmed on the device instead
+	hardwareWallet := m.hardwareWalletOf(queuedTx.Args.From)
+	if hardwareWallet != nil {
+		log.Info("transaction is sent from hardware wallet account", "id", queuedTx.ID)
+	} else if _, err := m.senderAccount(queuedTx.Args.From); err == account.ErrAccountNotSelected {
 		log.Warn("queued transaction does not belong to the selected account", "err", ErrInvalidCompleteTxSender)
 		m.NotifyOnQueuedTxReturn(queuedTx, ErrInvalidCompleteTxSender)
 		return gethcommon.Hash{}, ErrInvalidCompleteTxSender
+	} else if err != nil {
+		log.Warn("failed to get a selected account", "err", err)
+		return gethcommon.Hash{}, err
 	}
 
 	config, err := m.nodeManager.NodeConfig()
@@ -167,8 +331,18 @@ func (m *Manager) CompleteTransaction(id common.QueuedTxID, password string) (ge
 	var hash gethcommon.Hash
 	var txErr error
 
-	if config.UpstreamConfig.Enabled {
-		hash, txErr = m.completeRemoteTransaction(queuedTx, password)
+	if queuedTx.TypedData != "" && hardwareWallet != nil {
+		txErr = ErrHardwareWalletTypedData
+	} else if queuedTx.TypedData != "" {
+		txErr = m.completeTypedData(queuedTx, config.KeyStoreDir, password, passwordVerified)
+	} else if txErr = checkTransaction(queuedTx, config); txErr != nil {
+		log.Warn("transaction refused", "id", queuedTx.ID, "err", txErr)
+	} else if hardwareWallet != nil {
+		hash, txErr = m.completeSignedTransaction(queuedTx, hardwareSigner{wallet: hardwareWallet, queuedTx: queuedTx})
+	} else if config.RelayConfig != nil && config.RelayConfig.Enabled {
+		hash, txErr = m.completeRelayedTransaction(queuedTx, password, passwordVerified)
+	} else if config.UpstreamConfig.Enabled {
+		hash, txErr = m.completeRemoteTransaction(queuedTx, password, passwordVerified)
 	} else {
 		hash, txErr = m.completeLocalTransaction(queuedTx, password)
 	}
@@ -183,6 +357,11 @@ func (m *Manager) CompleteTransaction(id common.QueuedTxID, password string) (ge
 
 	log.Info("finally completed transaction", "id", queuedTx.ID, "hash", hash, "err", txErr)
 
+	if txErr == nil && queuedTx.TypedData == "" {
+		m.monitorTransaction(queuedTx, hash, config.TxMonitorConfig)
+		m.notifySent(queuedTx, hash)
+	}
+
 	queuedTx.Hash = hash
 	queuedTx.Err = txErr
 	queuedTx.Done <- struct{}{}
@@ -190,6 +369,20 @@ func (m *Manager) CompleteTransaction(id common.QueuedTxID, password string) (ge
 	return hash, txErr
 }
 
+// senderAccount returns selected account, transaction is sent from: either the main one,
+// or one selected along with it. account.ErrAccountNotSelected is returned for other accounts.
+func (m *Manager) senderAccount(from gethcommon.Address) (*common.SelectedExtKey, error) {
+	selectedAccount, err := m.accountManager.SelectedAccount()
+	if err != nil {
+		return nil, err
+	}
+	if from.Hex() == selectedAccount.Address.Hex() {
+		return selectedAccount, nil
+	}
+
+	return m.accountManager.SelectedAccountByAddress(from.Hex())
+}
+
 func (m *Manager) completeLocalTransaction(queuedTx *common.QueuedTx, password string) (gethcommon.Hash, error) {
 	log.Info("complete transaction using local node", "id", queuedTx.ID)
 
@@ -198,13 +391,34 @@ func (m *Manager) completeLocalTransaction(queuedTx *common.QueuedTx, password s
 		return gethcommon.Hash{}, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), m.nodeManager.RPCClient().CallTimeout())
 	defer cancel()
 
-	return les.StatusBackend.SendTransaction(ctx, status.SendTxArgs(queuedTx.Args), password)
+	// local node signs legacy transactions only
+	args := queuedTx.Args
+	if txType(args) != LegacyTxType {
+		return gethcommon.Hash{}, ErrUnsupportedTxType
+	}
+	nonce, release, err := m.nonces.acquire(ctx, ethclient.NewClient(m.nodeManager.RPCClient()), args.From, args.Nonce)
+	if err != nil {
+		return gethcommon.Hash{}, err
+	}
+
+	hash, err := les.StatusBackend.SendTransaction(ctx, status.SendTxArgs{
+		From:     args.From,
+		To:       args.To,
+		Gas:      args.Gas,
+		GasPrice: args.GasPrice,
+		Value:    args.Value,
+		Data:     args.Data,
+		Nonce:    (*hexutil.Uint64)(&nonce),
+	}, password)
+	release(err)
+
+	return hash, err
 }
 
-func (m *Manager) completeRemoteTransaction(queuedTx *common.QueuedTx, password string) (gethcommon.Hash, error) {
+func (m *Manager) completeRemoteTransaction(queuedTx *common.QueuedTx, password string, passwordVerified bool) (gethcommon.Hash, error) {
 	log.Info("complete transaction using upstream node", "id", queuedTx.ID)
 
 	var emptyHash gethcommon.Hash
@@ -214,44 +428,50 @@ func (m *Manager) completeRemoteTransaction(queuedTx *common.QueuedTx, password
 		return emptyHash, err
 	}
 
-	selectedAcct, err := m.accountManager.SelectedAccount()
+	selectedAcct, err := m.senderAccount(queuedTx.Args.From)
 	if err != nil {
 		return emptyHash, err
 	}
 
-	if _, err := m.accountManager.VerifyAccountPassword(
-		config.KeyStoreDir,
-		selectedAcct.Address.String(),
-		password,
-	); err != nil {
-		log.Warn("failed to verify account", "account", selectedAcct.Address.String(), "error", err.Error())
-		return emptyHash, err
+	if !passwordVerified {
+		if _, err := m.accountManager.VerifyAccountPassword(
+			config.KeyStoreDir,
+			selectedAcct.Address.String(),
+			password,
+		); err != nil {
+			log.Warn("failed to verify account", "account", log.Address(selectedAcct.Address.String()), "error", err.Error())
+			return emptyHash, err
+		}
 	}
 
-	// We need to request a new transaction nounce from upstream node.
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
+	return m.completeSignedTransaction(queuedTx, keySigner{account: selectedAcct})
+}
 
-	var txCount hexutil.Uint
-	client := m.nodeManager.RPCClient()
-	if err := client.CallContext(ctx, &txCount, "eth_getTransactionCount", queuedTx.Args.From, "pending"); err != nil {
+// completeSignedTransaction fills in missing fields of transaction, signs it with signer,
+// and sends it as raw transaction (to upstream or local node, see rpc.Client).
+func (m *Manager) completeSignedTransaction(queuedTx *common.QueuedTx, signer txSigner) (gethcommon.Hash, error) {
+	var emptyHash gethcommon.Hash
+
+	config, err := m.nodeManager.NodeConfig()
+	if err != nil {
 		return emptyHash, err
 	}
 
+	// Calls are limited by timeout, retries and circuit breaker configured for RPC client.
+	ctx := context.Background()
+	client := ethclient.NewClient(m.nodeManager.RPCClient())
+
 	args := queuedTx.Args
 
-	if args.GasPrice == nil {
-		value, err := m.gasPrice()
+	gasPrice := (*big.Int)(args.GasPrice)
+	if gasPrice == nil {
+		gasPrice, err = m.suggestGasPrice(ctx, client, config)
 		if err != nil {
+			log.Warn("failed to get gas price", "err", err)
 			return emptyHash, err
 		}
-
-		args.GasPrice = value
 	}
 
-	chainID := big.NewInt(int64(config.NetworkID))
-	nonce := uint64(txCount)
-	gasPrice := (*big.Int)(args.GasPrice)
 	data := []byte(args.Data)
 	value := (*big.Int)(args.Value)
 	toAddr := gethcommon.Address{}
@@ -259,9 +479,19 @@ func (m *Manager) completeRemoteTransaction(queuedTx *common.QueuedTx, password
 		toAddr = *args.To
 	}
 
-	gas, err := m.estimateGas(args)
-	if err != nil {
-		return emptyHash, err
+	gas := (*big.Int)(args.Gas)
+	if gas == nil {
+		gas, err = client.EstimateGas(ctx, ethereum.CallMsg{
+			From:     args.From,
+			To:       args.To,
+			GasPrice: gasPrice,
+			Value:    value,
+			Data:     data,
+		})
+		if err != nil {
+			log.Warn("failed to estimate gas", "err", err)
+			return emptyHash, err
+		}
 	}
 
 	log.Info(
@@ -273,95 +503,43 @@ func (m *Manager) completeRemoteTransaction(queuedTx *common.QueuedTx, password
 		"value", value,
 	)
 
-	tx := types.NewTransaction(nonce, toAddr, value, (*big.Int)(gas), gasPrice, data)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), selectedAcct.AccountKey.PrivateKey)
+	// We need to request a new transaction nonce from upstream node, unless transactions
+	// have been sent from the account recently (see nonceTracker).
+	nonce, release, err := m.nonces.acquire(ctx, client, args.From, args.Nonce)
 	if err != nil {
 		return emptyHash, err
 	}
 
-	txBytes, err := rlp.EncodeToBytes(signedTx)
+	tx := types.NewTransaction(nonce, toAddr, value, gas, gasPrice, data)
+	signedTx, err := signTransaction(signer, txType(args), tx, chainID(config))
 	if err != nil {
+		release(err)
 		return emptyHash, err
 	}
 
-	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel2()
-
-	if err := client.CallContext(ctx2, nil, "eth_sendRawTransaction", gethcommon.ToHex(txBytes)); err != nil {
-		return emptyHash, err
-	}
-
-	return signedTx.Hash(), nil
-}
-
-func (m *Manager) estimateGas(args common.SendTxArgs) (*hexutil.Big, error) {
-	if args.Gas != nil {
-		return args.Gas, nil
-	}
-
-	client := m.nodeManager.RPCClient()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-
-	var gasPrice hexutil.Big
-	if args.GasPrice != nil {
-		gasPrice = (hexutil.Big)(*args.GasPrice)
-	}
-
-	var value hexutil.Big
-	if args.Value != nil {
-		value = (hexutil.Big)(*args.Value)
-	}
-
-	params := struct {
-		From     gethcommon.Address  `json:"from"`
-		To       *gethcommon.Address `json:"to"`
-		Gas      hexutil.Big         `json:"gas"`
-		GasPrice hexutil.Big         `json:"gasPrice"`
-		Value    hexutil.Big         `json:"value"`
-		Data     hexutil.Bytes       `json:"data"`
-	}{
-		From:     args.From,
-		To:       args.To,
-		GasPrice: gasPrice,
-		Value:    value,
-		Data:     []byte(args.Data),
-	}
-
-	var estimatedGas hexutil.Big
-	if err := client.CallContext(
-		ctx,
-		&estimatedGas,
-		"eth_estimateGas",
-		params,
-	); err != nil {
-		log.Warn("failed to estimate gas", "err", err)
-		return nil, err
-	}
-
-	return &estimatedGas, nil
-}
-
-func (m *Manager) gasPrice() (*hexutil.Big, error) {
-	client := m.nodeManager.RPCClient()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-
-	var gasPrice hexutil.Big
-	if err := client.CallContext(ctx, &gasPrice, "eth_gasPrice"); err != nil {
-		log.Warn("failed to get gas price", "err", err)
-		return nil, err
-	}
+	hash, err := client.SendRawTransactionData(ctx, signedTx)
+	release(err)
 
-	return &gasPrice, nil
+	return hash, err
 }
 
-// CompleteTransactions instructs backend to complete sending of multiple transactions
+// CompleteTransactions instructs backend to complete sending of multiple transactions (e.g. when user approves
+// all transactions, queued by dapp at once). Password is verified once per sender, rather than for every
+// transaction: if it's wrong, transactions of sender stay in the queue, with keystore.ErrDecrypt as their result.
 func (m *Manager) CompleteTransactions(ids []common.QueuedTxID, password string) map[common.QueuedTxID]common.RawCompleteTransactionResult {
 	results := make(map[common.QueuedTxID]common.RawCompleteTransactionResult)
+	verified := make(map[gethcommon.Address]error) // results of verification of password, by sender
 
 	for _, txID := range ids {
-		txHash, txErr := m.CompleteTransaction(txID, password)
+		passwordVerified, err := m.verifySenderPassword(txID, password, verified)
+		if err != nil {
+			results[txID] = common.RawCompleteTransactionResult{
+				Error: err,
+			}
+			continue
+		}
+
+		txHash, txErr := m.completeTransaction(txID, password, passwordVerified)
 		results[txID] = common.RawCompleteTransactionResult{
 			Hash:  txHash,
 			Error: txErr,
@@ -371,6 +549,42 @@ func (m *Manager) CompleteTransactions(ids []common.QueuedTxID, password string)
 	return results
 }
 
+// verifySenderPassword verifies password of selected account, queued transaction is sent from, unless it has
+// been verified already (results are kept in verified). Only wrong password is returned as an error, the rest
+// (e.g. unknown transaction, or sender, which is not selected) is reported, once transaction is completed.
+func (m *Manager) verifySenderPassword(id common.QueuedTxID, password string, verified map[gethcommon.Address]error) (bool, error) {
+	queuedTx, err := m.txQueue.Get(id)
+	if err != nil || m.hardwareWalletOf(queuedTx.Args.From) != nil {
+		return false, nil
+	}
+	if _, err := m.senderAccount(queuedTx.Args.From); err != nil {
+		return false, nil
+	}
+
+	sender := queuedTx.Args.From
+	verifyErr, ok := verified[sender]
+	if !ok {
+		config, err := m.nodeManager.NodeConfig()
+		if err != nil {
+			return false, nil
+		}
+		_, verifyErr = m.accountManager.VerifyAccountPassword(config.KeyStoreDir, sender.Hex(), password)
+		verified[sender] = verifyErr
+	}
+
+	switch verifyErr {
+	case nil:
+		return true, nil
+	case keystore.ErrDecrypt:
+		// transaction stays in the queue, so that it could be completed with correct password
+		log.Warn("failed to complete transaction", "id", id, "err", verifyErr)
+		m.NotifyOnQueuedTxReturn(queuedTx, verifyErr)
+		return false, verifyErr
+	default:
+		return false, nil
+	}
+}
+
 // DiscardTransaction discards a given transaction from transaction queue
 func (m *Manager) DiscardTransaction(id common.QueuedTxID) error {
 	queuedTx, err := m.txQueue.Get(id)
@@ -384,6 +598,7 @@ func (m *Manager) DiscardTransaction(id common.QueuedTxID) error {
 	// allow SendTransaction to return
 	queuedTx.Err = ErrQueuedTxDiscarded
 	queuedTx.Discard <- struct{}{} // sendTransaction() waits on this, notify so that it can return
+	notifyDiscarded(queuedTx)
 
 	return nil
 }
@@ -404,28 +619,71 @@ func (m *Manager) DiscardTransactions(ids []common.QueuedTxID) map[common.Queued
 	return results
 }
 
+// TransactionsByOrigin returns queued transactions, requested by a given origin (e.g. chatID of jail cell).
+func (m *Manager) TransactionsByOrigin(origin string) []*common.QueuedTx {
+	return m.txQueue.ByOrigin(origin)
+}
+
+// DiscardTransactionsByOrigin discards all queued transactions, requested by a given origin
+// (e.g. when dapp is closed).
+func (m *Manager) DiscardTransactionsByOrigin(origin string) map[common.QueuedTxID]common.RawDiscardTransactionResult {
+	var ids []common.QueuedTxID
+	for _, tx := range m.txQueue.ByOrigin(origin) {
+		ids = append(ids, tx.ID)
+	}
+
+	return m.DiscardTransactions(ids)
+}
+
 // SendTransactionEvent is a signal sent on a send transaction request
 type SendTransactionEvent struct {
-	ID        string            `json:"id"`
-	Args      common.SendTxArgs `json:"args"`
-	MessageID string            `json:"message_id"`
+	ID        string             `json:"id"`
+	Args      common.SendTxArgs  `json:"args"`
+	MessageID string             `json:"message_id"`
+	Origin    string             `json:"origin"`
+	RequestID string             `json:"request_id"`
+	TypedData json.RawMessage    `json:"typed_data,omitempty"` // set for requests to sign typed data
+	Estimate  *common.TxEstimate `json:"estimate,omitempty"`   // set for transactions, unless estimation fails
 }
 
-// TransactionQueueHandler returns handler that processes incoming tx queue requests
+// TransactionQueueHandler returns handler that processes incoming tx queue requests.
+// Gas and fee of transactions are estimated, before they are signalled.
 func (m *Manager) TransactionQueueHandler() func(queuedTx *common.QueuedTx) {
 	return func(queuedTx *common.QueuedTx) {
 		log.Info("calling TransactionQueueHandler")
+
+		var estimate *common.TxEstimate
+		if queuedTx.TypedData == "" {
+			if txEstimate, err := m.EstimateTransaction(queuedTx.Args); err != nil {
+				log.Warn("failed to estimate transaction", "id", queuedTx.ID, "err", err)
+			} else {
+				estimate = &txEstimate
+			}
+		}
+
 		signal.Send(signal.Envelope{
 			Type: EventTransactionQueued,
 			Event: SendTransactionEvent{
 				ID:        string(queuedTx.ID),
 				Args:      queuedTx.Args,
 				MessageID: common.MessageIDFromContext(queuedTx.Context),
+				Origin:    queuedTx.Origin,
+				RequestID: rpc.RequestIDFromContext(queuedTx.Context),
+				TypedData: typedDataJSON(queuedTx),
+				Estimate:  estimate,
 			},
 		})
 	}
 }
 
+// typedDataJSON returns typed data of request as JSON, nil for transactions.
+func typedDataJSON(queuedTx *common.QueuedTx) json.RawMessage {
+	if queuedTx.TypedData == "" {
+		return nil
+	}
+	return json.RawMessage(queuedTx.TypedData)
+}
+
 // SetTransactionQueueHandler sets a handler that will be called
 // when a new transaction is enqueued.
 func (m *Manager) SetTransactionQueueHandler(fn common.EnqueuedTxHandler) {
@@ -435,8 +693,11 @@ func (m *Manager) SetTransactionQueueHandler(fn common.EnqueuedTxHandler) {
 // ReturnSendTransactionEvent is a JSON returned whenever transaction send is returned
 type ReturnSendTransactionEvent struct {
 	ID           string            `json:"id"`
+	Hash         string            `json:"hash,omitempty"` // set for transactions, which have failed after being sent
 	Args         common.SendTxArgs `json:"args"`
 	MessageID    string            `json:"message_id"`
+	Origin       string            `json:"origin"`
+	RequestID    string            `json:"request_id"`
 	ErrorMessage string            `json:"error_message"`
 	ErrorCode    string            `json:"error_code"`
 }
@@ -460,6 +721,8 @@ func (m *Manager) TransactionReturnHandler() func(queuedTx *common.QueuedTx, err
 				ID:           string(queuedTx.ID),
 				Args:         queuedTx.Args,
 				MessageID:    common.MessageIDFromContext(queuedTx.Context),
+				Origin:       queuedTx.Origin,
+				RequestID:    rpc.RequestIDFromContext(queuedTx.Context),
 				ErrorMessage: err.Error(),
 				ErrorCode:    m.sendTransactionErrorCode(err),
 			},
@@ -482,6 +745,24 @@ func (m *Manager) SetTransactionReturnHandler(fn common.EnqueuedTxReturnHandler)
 	m.txQueue.SetTxReturnHandler(fn)
 }
 
+// SetTransactionSentHandler sets a handler, which is called once transaction is sent
+// (e.g. to record it in history of transactions).
+func (m *Manager) SetTransactionSentHandler(fn common.SentTxHandler) {
+	m.sentHandlerMx.Lock()
+	defer m.sentHandlerMx.Unlock()
+
+	m.sentHandler = fn
+}
+
+func (m *Manager) notifySent(queuedTx *common.QueuedTx, hash gethcommon.Hash) {
+	m.sentHandlerMx.RLock()
+	defer m.sentHandlerMx.RUnlock()
+
+	if m.sentHandler != nil {
+		m.sentHandler(queuedTx, hash)
+	}
+}
+
 // SendTransactionRPCHandler is a handler for eth_sendTransaction method.
 // It accepts one param which is a slice with a map of transaction params.
 func (m *Manager) SendTransactionRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
@@ -491,7 +772,15 @@ func (m *Manager) SendTransactionRPCHandler(ctx context.Context, args ...interfa
 	// We should refactor parsing these params to a separate struct.
 	rpcCall := common.RPCCall{Params: args}
 
-	tx := m.CreateTransaction(ctx, rpcCall.ToSendTxArgs())
+	txArgs := rpcCall.ToSendTxArgs()
+	// transactions are sent from account bound to origin (jail cell, dapp) by default
+	if txArgs.From == (gethcommon.Address{}) {
+		if address, ok := m.accountManager.BoundAccount(common.OriginFromContext(ctx)); ok {
+			txArgs.From = address
+		}
+	}
+
+	tx := m.CreateTransaction(ctx, txArgs)
 
 	if err := m.QueueTransaction(tx); err != nil {
 		return nil, err
@@ -2,17 +2,38 @@ package txqueue
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethnode "github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/golang/mock/gomock"
 
+	"github.com/status-im/status-go/geth/account"
 	"github.com/status-im/status-go/geth/common"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/rpc/ethclient"
+	"github.com/status-im/status-go/geth/signal"
 	. "github.com/status-im/status-go/testing"
 )
 
@@ -36,6 +57,7 @@ func (s *TxQueueTestSuite) SetupTest() {
 
 	s.nodeManagerMock = common.NewMockNodeManager(s.nodeManagerMockCtrl)
 	s.accountManagerMock = common.NewMockAccountManager(s.accountManagerMockCtrl)
+	s.accountManagerMock.EXPECT().IsWatchOnlyAccount(gomock.Any()).Return(false).AnyTimes()
 }
 
 func (s *TxQueueTestSuite) TearDownTest() {
@@ -160,6 +182,10 @@ func (s *TxQueueTestSuite) TestAccountMismatch() {
 	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
 		Address: common.FromAddress(TestConfig.Account2.Address),
 	}, nil)
+	// sender is neither the main, nor additionally selected account
+	s.accountManagerMock.EXPECT().SelectedAccountByAddress(common.FromAddress(TestConfig.Account1.Address).Hex()).Return(
+		nil, account.ErrAccountNotSelected,
+	)
 
 	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
 
@@ -241,6 +267,55 @@ func (s *TxQueueTestSuite) TestInvalidPassword() {
 	s.True(txQueueManager.TransactionQueue().Has(tx.ID))
 }
 
+func (s *TxQueueTestSuite) TestCompleteTransactions() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil).AnyTimes()
+
+	config, err := params.NewNodeConfig("/tmp", params.RopstenNetworkID, true)
+	s.NoError(err)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	var ids []common.QueuedTxID
+	for i := 0; i < 3; i++ {
+		tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+			From: common.FromAddress(TestConfig.Account1.Address),
+			To:   common.ToAddress(TestConfig.Account2.Address),
+		})
+		s.NoError(txQueueManager.QueueTransaction(tx))
+		ids = append(ids, tx.ID)
+	}
+
+	// password is verified once, transactions stay queued, if it's wrong
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(config.KeyStoreDir, TestConfig.Account1.Address, "invalid-password").
+		Return(nil, keystore.ErrDecrypt).Times(1)
+	results := txQueueManager.CompleteTransactions(ids, "invalid-password")
+	s.Len(results, len(ids))
+	for _, id := range ids {
+		s.Equal(keystore.ErrDecrypt, results[id].Error)
+		s.True(txQueueManager.TransactionQueue().Has(id))
+	}
+
+	// LES is mocked with a known error, which is treated as success
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(config.KeyStoreDir, TestConfig.Account1.Address, TestConfig.Account1.Password).
+		Return(nil, nil).Times(1)
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent).Times(len(ids))
+	results = txQueueManager.CompleteTransactions(append(ids, "invalid-tx-id"), TestConfig.Account1.Password)
+	s.Len(results, len(ids)+1)
+	for _, id := range ids {
+		s.Equal(errTxAssumedSent, results[id].Error)
+	}
+	s.Equal(ErrQueuedTxIDNotFound, results["invalid-tx-id"].Error)
+}
+
 func (s *TxQueueTestSuite) TestDiscardTransaction() {
 	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
 
@@ -277,3 +352,812 @@ func (s *TxQueueTestSuite) TestDiscardTransaction() {
 	// Transaction should be already removed from the queue.
 	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
 }
+
+func (s *TxQueueTestSuite) TestDiscardTransactionsByOrigin() {
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	args := common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account1.Address),
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	}
+	dappTx := txQueueManager.CreateTransaction(context.WithValue(context.Background(), common.OriginKey, "dapp"), args)
+	otherTx := txQueueManager.CreateTransaction(context.Background(), args)
+	s.Equal("dapp", dappTx.Origin)
+	s.Equal("", otherTx.Origin)
+
+	s.NoError(txQueueManager.QueueTransaction(dappTx))
+	s.NoError(txQueueManager.QueueTransaction(otherTx))
+
+	s.Equal([]*common.QueuedTx{dappTx}, txQueueManager.TransactionsByOrigin("dapp"))
+
+	// only failures are reported
+	s.Empty(txQueueManager.DiscardTransactionsByOrigin("dapp"))
+	s.Equal(ErrQueuedTxDiscarded, txQueueManager.WaitForTransaction(dappTx))
+
+	s.Empty(txQueueManager.TransactionsByOrigin("dapp"))
+	s.True(txQueueManager.TransactionQueue().Has(otherTx.ID))
+}
+
+func (s *TxQueueTestSuite) TestRestoreTransactions() {
+	dir, err := ioutil.TempDir("", "txqueue")
+	s.NoError(err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	config, err := params.NewNodeConfig(dir, params.RopstenNetworkID, true)
+	s.NoError(err)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+
+	// transaction is queued, but app is stopped before it is completed
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager.Start()
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	s.NoError(txQueueManager.RestoreTransactions())
+	ctx := context.WithValue(context.Background(), common.OriginKey, "chat")
+	tx := txQueueManager.CreateTransaction(ctx, common.SendTxArgs{
+		From:  common.FromAddress(TestConfig.Account1.Address),
+		To:    common.ToAddress(TestConfig.Account2.Address),
+		Value: (*hexutil.Big)(big.NewInt(1)),
+		Data:  hexutil.Bytes{0x01},
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+	txQueueManager.Stop()
+
+	// transaction is queued again on next start
+	txQueueManager = NewManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+	restored := make(chan *common.QueuedTx, 1)
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {
+		restored <- queuedTx
+	})
+	s.NoError(txQueueManager.RestoreTransactions())
+	restoredTx := <-restored
+	s.Equal(tx.ID, restoredTx.ID)
+	s.Equal(tx.Args, restoredTx.Args)
+	s.Equal("chat", restoredTx.Origin)
+	s.Equal("chat", common.OriginFromContext(restoredTx.Context))
+
+	// once discarded, it's removed from store
+	s.NoError(txQueueManager.DiscardTransaction(tx.ID))
+	s.NoError(txQueueManager.RestoreTransactions())
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+	s.Len(restored, 0)
+}
+
+func (s *TxQueueTestSuite) TestPendingTransactions() {
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	evicted := make(chan ExpiredTransactionEvent, 1)
+	discarded := make(chan DiscardedTransactionEvent, 1)
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		var envelope struct {
+			Type  string
+			Event json.RawMessage
+		}
+		s.NoError(json.Unmarshal([]byte(event), &envelope))
+		switch envelope.Type {
+		case EventTransactionEvicted:
+			var expiredEvent ExpiredTransactionEvent
+			s.NoError(json.Unmarshal(envelope.Event, &expiredEvent))
+			evicted <- expiredEvent
+		case EventTransactionDiscarded:
+			var discardedEvent DiscardedTransactionEvent
+			s.NoError(json.Unmarshal(envelope.Event, &discardedEvent))
+			discarded <- discardedEvent
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	args := common.SendTxArgs{
+		From:  common.FromAddress(TestConfig.Account1.Address),
+		To:    common.ToAddress(TestConfig.Account2.Address),
+		Value: (*hexutil.Big)(big.NewInt(1)),
+	}
+	dappTx := txQueueManager.CreateTransaction(context.WithValue(context.Background(), common.OriginKey, "dapp"), args)
+	s.NoError(txQueueManager.QueueTransaction(dappTx))
+	otherTx := txQueueManager.CreateTransaction(context.Background(), args)
+	s.NoError(txQueueManager.QueueTransaction(otherTx))
+
+	pending := txQueueManager.PendingTransactions()
+	s.Len(pending, 2)
+	s.Equal(string(dappTx.ID), pending[0].ID)
+	s.Equal(args, pending[0].Args)
+	s.Equal("dapp", pending[0].Origin)
+	s.Equal(string(otherTx.ID), pending[1].ID)
+	s.True(pending[0].QueuedAt <= pending[1].QueuedAt)
+	s.Equal(pending[0].QueuedAt+DefaultTxSendCompletionTimeout*1000, pending[0].ExpiresAt)
+
+	// the oldest transaction is evicted from full queue, and its requester stops waiting
+	txQueueManager.SetQueueConfig(&params.TxQueueConfig{Size: 2, TTL: 60, Eviction: params.TxQueueEvictOldest})
+	dappTxErr := make(chan error, 1)
+	go func() { dappTxErr <- txQueueManager.WaitForTransaction(dappTx) }()
+	newTx := txQueueManager.CreateTransaction(context.Background(), args)
+	s.NoError(txQueueManager.QueueTransaction(newTx))
+	event := <-evicted
+	s.Equal(string(dappTx.ID), event.ID)
+	s.Equal("dapp", event.Origin)
+	s.Equal(ExpiredEvicted, event.Reason)
+	s.Equal(ErrQueuedTxEvicted, <-dappTxErr)
+
+	pending = txQueueManager.PendingTransactions()
+	s.Len(pending, 2)
+	s.Equal(string(otherTx.ID), pending[0].ID)
+	s.Equal(string(newTx.ID), pending[1].ID)
+	s.Equal(pending[1].QueuedAt+60*1000, pending[1].ExpiresAt)
+
+	// new transactions are rejected by full queue
+	txQueueManager.SetQueueConfig(&params.TxQueueConfig{Size: 2, TTL: 60, Eviction: params.TxQueueRejectNew})
+	s.Equal(ErrQueueFull, txQueueManager.QueueTransaction(txQueueManager.CreateTransaction(context.Background(), args)))
+	s.Len(txQueueManager.PendingTransactions(), 2)
+
+	// discarded transaction makes room for another one
+	s.NoError(txQueueManager.DiscardTransaction(otherTx.ID))
+	s.Equal(string(otherTx.ID), (<-discarded).ID)
+	s.NoError(txQueueManager.QueueTransaction(txQueueManager.CreateTransaction(context.Background(), args)))
+	s.Len(txQueueManager.PendingTransactions(), 2)
+}
+
+func (s *TxQueueTestSuite) TestSigningPolicy() {
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(TestConfig.Account1.Address),
+	}, nil)
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true),
+	)
+
+	// as in TestCompleteTransaction, known error is treated as success
+	s.nodeManagerMock.EXPECT().LightEthereumService().Return(nil, errTxAssumedSent)
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	passwordFile := s.passwordFile()
+	defer os.Remove(passwordFile) // nolint: errcheck
+
+	s.Equal(ErrPasswordFileRequired, txQueueManager.SetSigningPolicy(&params.SigningConfig{AutoApprove: true}))
+	s.NoError(txQueueManager.SetSigningPolicy(&params.SigningConfig{
+		AllowedAccounts:     []string{TestConfig.Account1.Address},
+		AutoApprove:         true,
+		AutoApproveMaxValue: "211000",
+		PasswordFile:        passwordFile,
+	}))
+	gas := (*hexutil.Big)(big.NewInt(21000))
+	gasPrice := (*hexutil.Big)(big.NewInt(10))
+
+	// account is not allowed
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: common.FromAddress(TestConfig.Account2.Address),
+		To:   common.ToAddress(TestConfig.Account1.Address),
+	})
+	s.Equal(ErrAccountNotAllowed, txQueueManager.QueueTransaction(tx))
+
+	// value and fee are above limit
+	tx = txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From:     common.FromAddress(TestConfig.Account1.Address),
+		To:       common.ToAddress(TestConfig.Account2.Address),
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Value:    (*hexutil.Big)(big.NewInt(1001)),
+	})
+	s.Equal(ErrTxValueAboveLimit, txQueueManager.QueueTransaction(tx))
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+
+	// contract calls are not approved, even without value (e.g. transfers of tokens)
+	tx = txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From:     common.FromAddress(TestConfig.Account1.Address),
+		To:       common.ToAddress(TestConfig.Account2.Address),
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     hexutil.Bytes{0xa9, 0x05, 0x9c, 0xbb},
+	})
+	s.Equal(ErrTxNotPlainTransfer, txQueueManager.QueueTransaction(tx))
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+
+	// approved transaction is completed without interaction
+	tx = txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From:     common.FromAddress(TestConfig.Account1.Address),
+		To:       common.ToAddress(TestConfig.Account2.Address),
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Value:    (*hexutil.Big)(big.NewInt(1000)),
+	})
+	s.NoError(txQueueManager.QueueTransaction(tx))
+	s.Equal(errTxAssumedSent, txQueueManager.WaitForTransaction(tx))
+
+	// invalid config is rejected
+	s.Error(txQueueManager.SetSigningPolicy(&params.SigningConfig{AllowedAccounts: []string{"0x1"}}))
+	s.Error(txQueueManager.SetSigningPolicy(&params.SigningConfig{AutoApprove: true, AutoApproveMaxValue: "-1", PasswordFile: passwordFile}))
+}
+
+// passwordFile writes password of the first test account into temporary file, returning its path.
+func (s *TxQueueTestSuite) passwordFile() string {
+	file, err := ioutil.TempFile("", "password")
+	s.Require().NoError(err)
+	defer file.Close() // nolint: errcheck
+
+	_, err = file.WriteString(TestConfig.Account1.Password)
+	s.Require().NoError(err)
+	return file.Name()
+}
+
+func (s *TxQueueTestSuite) TestSignTypedData() {
+	from := TestConfig.Account1.Address
+	typedData := `{"types":{"EIP712Domain":[{"name":"name","type":"string"}]},"primaryType":"EIP712Domain","domain":{"name":"test"},"message":{}}`
+	signature := []byte{1, 2, 3}
+
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address: common.FromAddress(from),
+	}, nil)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true),
+	)
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(gomock.Any(), common.FromAddress(from).Hex(), TestConfig.Account1.Password).Return(nil, nil)
+	s.accountManagerMock.EXPECT().SignTypedData(common.FromAddress(from).Hex(), typedData).Return(signature, nil)
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	// typed data is never approved automatically
+	passwordFile := s.passwordFile()
+	defer os.Remove(passwordFile) // nolint: errcheck
+	s.NoError(txQueueManager.SetSigningPolicy(&params.SigningConfig{AutoApprove: true, PasswordFile: passwordFile}))
+
+	queued := make(chan *common.QueuedTx, 1)
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {
+		queued <- queuedTx
+	})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	_, err := txQueueManager.SignTypedDataRPCHandler(context.Background(), from, `{"types":{}}`)
+	s.Error(err)
+
+	go func() {
+		tx := <-queued
+		s.Equal(typedData, tx.TypedData)
+		_, err := txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+		s.NoError(err)
+	}()
+
+	result, err := txQueueManager.SignTypedDataRPCHandler(context.Background(), from, typedData)
+	s.NoError(err)
+	s.Equal(hexutil.Bytes(signature), result)
+}
+
+func (s *TxQueueTestSuite) TestSignRequests() {
+	from := common.FromAddress(TestConfig.Account1.Address)
+	signature := []byte{1, 2, 3}
+
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(
+		params.NewNodeConfig("/tmp", params.RopstenNetworkID, true),
+	).Times(2)
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(gomock.Any(), from.Hex(), "invalid-password").Return(nil, keystore.ErrDecrypt)
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(gomock.Any(), from.Hex(), TestConfig.Account1.Password).Return(nil, nil)
+	s.accountManagerMock.EXPECT().SignMessage(from.Hex(), []byte("hello")).Return(signature, nil)
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	queued := make(chan SignRequestEvent, 2)
+	signal.SetDefaultNodeNotificationHandler(func(event string) {
+		var envelope struct {
+			Type  string
+			Event SignRequestEvent
+		}
+		s.NoError(json.Unmarshal([]byte(event), &envelope))
+		if envelope.Type == EventSignRequestQueued {
+			queued <- envelope.Event
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	personalSign := txQueueManager.SignRPCHandler("personal_sign")
+	_, err := personalSign(context.Background(), "hello")
+	s.Equal(ErrInvalidSignParams, err)
+
+	// approved request returns signature, wrong password keeps it in queue
+	go func() {
+		event := <-queued
+		s.Equal("personal_sign", event.Method)
+		s.Equal(hexutil.Bytes("hello"), event.Data)
+		id := common.SignRequestID(event.ID)
+		_, err := txQueueManager.ApproveSignRequest(id, "invalid-password")
+		s.Equal(keystore.ErrDecrypt, err)
+		_, err = txQueueManager.ApproveSignRequest(id, TestConfig.Account1.Password)
+		s.NoError(err)
+	}()
+	result, err := personalSign(context.Background(), "hello", from.Hex())
+	s.NoError(err)
+	s.Equal(hexutil.Bytes(signature), result)
+
+	// discarded request fails
+	go func() {
+		event := <-queued
+		s.Equal(hexutil.Bytes{0xde, 0xad}, event.Data)
+		s.NoError(txQueueManager.DiscardSignRequest(common.SignRequestID(event.ID)))
+		s.Equal(ErrSignRequestNotFound, txQueueManager.DiscardSignRequest(common.SignRequestID(event.ID)))
+	}()
+	_, err = txQueueManager.SignRPCHandler("eth_sign")(context.Background(), from.Hex(), "0xdead")
+	s.Equal(ErrSignRequestDiscarded, err)
+
+	// request of cancelled call is removed from queue
+	ctx, cancel := context.WithCancel(context.Background())
+	ids := make(chan common.SignRequestID, 1)
+	go func() {
+		event := <-queued
+		ids <- common.SignRequestID(event.ID)
+		cancel()
+	}()
+	_, err = personalSign(ctx, "hello", from.Hex())
+	s.Equal(context.Canceled, err)
+	s.Equal(ErrSignRequestNotFound, txQueueManager.DiscardSignRequest(<-ids))
+}
+
+func (s *TxQueueTestSuite) TestWatchOnlyAccount() {
+	from := common.FromAddress(TestConfig.Account1.Address)
+
+	// account manager of suite treats every account as a regular one
+	ctrl := gomock.NewController(s.T())
+	defer ctrl.Finish()
+	accountManager := common.NewMockAccountManager(ctrl)
+	accountManager.EXPECT().IsWatchOnlyAccount(from.Hex()).Return(true).Times(2)
+
+	txQueueManager := NewManager(s.nodeManagerMock, accountManager)
+
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+		From: from,
+		To:   common.ToAddress(TestConfig.Account2.Address),
+	})
+	s.Equal(account.ErrWatchOnlyAccount, txQueueManager.QueueTransaction(tx))
+	s.False(txQueueManager.TransactionQueue().Has(tx.ID))
+
+	_, err := txQueueManager.SignRPCHandler("eth_sign")(context.Background(), from.Hex(), "0x01")
+	s.Equal(account.ErrWatchOnlyAccount, err)
+}
+
+// testHardwareWallet signs transactions of a single account with its key, as a device would.
+type testHardwareWallet struct {
+	key *ecdsa.PrivateKey
+	err error // returned, as if user rejected transaction on the device
+}
+
+func (w *testHardwareWallet) Contains(account accounts.Account) bool {
+	return account.Address == crypto.PubkeyToAddress(w.key.PublicKey)
+}
+
+func (w *testHardwareWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), w.key)
+}
+
+func (s *TxQueueTestSuite) TestHardwareWallet() {
+	key, err := crypto.GenerateKey()
+	s.NoError(err)
+	wallet := &testHardwareWallet{key: key}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	s.NoError(err)
+	s.NoError(stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	s.NoError(err)
+
+	var sentTx types.Transaction
+	rpcClient.RegisterHandler("eth_getTransactionCount", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return hexutil.Uint64(7), nil
+	})
+	rpcClient.RegisterHandler("eth_sendRawTransaction", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		if err := rlp.DecodeBytes(hexutil.MustDecode(args[0].(string)), &sentTx); err != nil {
+			return nil, err
+		}
+		return sentTx.Hash(), nil
+	})
+
+	config, err := params.NewNodeConfig("/tmp", params.RopstenNetworkID, true)
+	s.NoError(err)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	s.nodeManagerMock.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+	txQueueManager.SetHardwareWallet(wallet)
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	var mu sync.Mutex
+	var events []string
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope signal.Envelope
+		s.NoError(json.Unmarshal([]byte(jsonEvent), &envelope))
+		mu.Lock()
+		events = append(events, envelope.Type)
+		mu.Unlock()
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	gas := hexutil.Big(*big.NewInt(21000))
+	gasPrice := hexutil.Big(*big.NewInt(1))
+	newTx := func() *common.QueuedTx {
+		tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{
+			From:     from,
+			To:       common.ToAddress(TestConfig.Account2.Address),
+			Gas:      &gas,
+			GasPrice: &gasPrice,
+		})
+		s.NoError(txQueueManager.QueueTransaction(tx))
+		return tx
+	}
+
+	// device rejects transaction, no password is needed and selected account is not checked
+	wallet.err = errors.New("rejected on device")
+	tx := newTx()
+	_, err = txQueueManager.CompleteTransaction(tx.ID, "")
+	s.Equal(wallet.err, err)
+
+	wallet.err = nil
+	tx = newTx()
+	hash, err := txQueueManager.CompleteTransaction(tx.ID, "")
+	s.NoError(err)
+	s.Equal(sentTx.Hash(), hash)
+	s.Equal(uint64(7), sentTx.Nonce())
+	sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(int64(params.RopstenNetworkID))), &sentTx)
+	s.NoError(err)
+	s.Equal(from, sender)
+
+	mu.Lock()
+	s.Equal([]string{
+		EventTransactionAwaitingDevice, EventTransactionDeviceResponded,
+		EventTransactionAwaitingDevice, EventTransactionDeviceResponded,
+	}, events)
+	mu.Unlock()
+
+	// transactions are signed with selected account, once wallet is disconnected
+	txQueueManager.SetHardwareWallet(nil)
+	s.Nil(txQueueManager.hardwareWalletOf(from))
+}
+
+func (s *TxQueueTestSuite) TestReplaceTransaction() {
+	key, err := crypto.GenerateKey()
+	s.NoError(err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.ToAddress(TestConfig.Account2.Address)
+	signer := types.NewEIP155Signer(big.NewInt(int64(params.RopstenNetworkID)))
+
+	pendingTx, err := types.SignTx(types.NewTransaction(3, *to, big.NewInt(1), big.NewInt(21000), big.NewInt(100), nil), signer, key)
+	s.NoError(err)
+
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	s.NoError(err)
+	s.NoError(stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	s.NoError(err)
+
+	var (
+		mined    bool
+		gasPrice = hexutil.Big(*big.NewInt(150))
+		sentTx   *types.Transaction
+	)
+	rpcClient.RegisterHandler("eth_getTransactionByHash", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		var tx map[string]interface{}
+		data, err := pendingTx.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &tx); err != nil {
+			return nil, err
+		}
+		if mined {
+			tx["blockNumber"] = "0x10"
+		}
+		return tx, nil
+	})
+	rpcClient.RegisterHandler("eth_gasPrice", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return gasPrice, nil
+	})
+	rpcClient.RegisterHandler("eth_sendRawTransaction", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		sentTx = new(types.Transaction)
+		if err := rlp.DecodeBytes(hexutil.MustDecode(args[0].(string)), sentTx); err != nil {
+			return nil, err
+		}
+		return sentTx.Hash(), nil
+	})
+
+	config, err := params.NewNodeConfig("/tmp", params.RopstenNetworkID, true)
+	s.NoError(err)
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	s.nodeManagerMock.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address:    from,
+		AccountKey: &keystore.Key{PrivateKey: key},
+	}, nil).AnyTimes()
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(config.KeyStoreDir, from.Hex(), "wrong").Return(nil, keystore.ErrDecrypt)
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(config.KeyStoreDir, from.Hex(), TestConfig.Account1.Password).Return(nil, nil).AnyTimes()
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+
+	var mu sync.Mutex
+	var events []ReplacedTransactionEvent
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event ReplacedTransactionEvent
+		}
+		s.NoError(json.Unmarshal([]byte(jsonEvent), &envelope))
+		if envelope.Type == EventTransactionReplaced || envelope.Type == EventTransactionReplacementFailed {
+			mu.Lock()
+			events = append(events, envelope.Event)
+			mu.Unlock()
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	// gas price must be bumped by at least 10%
+	_, err = txQueueManager.SpeedUpTransaction(pendingTx.Hash(), big.NewInt(109), TestConfig.Account1.Password)
+	s.Equal(ErrGasPriceTooLow, err)
+	_, err = txQueueManager.SpeedUpTransaction(pendingTx.Hash(), big.NewInt(110), "wrong")
+	s.Equal(keystore.ErrDecrypt, err)
+
+	hash, err := txQueueManager.SpeedUpTransaction(pendingTx.Hash(), big.NewInt(110), TestConfig.Account1.Password)
+	s.NoError(err)
+	s.Equal(sentTx.Hash(), hash)
+	s.Equal(pendingTx.Nonce(), sentTx.Nonce())
+	s.Equal(big.NewInt(110), sentTx.GasPrice())
+	s.Equal(to, sentTx.To())
+	s.Equal(big.NewInt(1), sentTx.Value())
+	sender, err := types.Sender(signer, sentTx)
+	s.NoError(err)
+	s.Equal(from, sender)
+
+	// suggested gas price is used for cancellation, if it's higher than the minimal one
+	hash, err = txQueueManager.CancelTransaction(pendingTx.Hash(), TestConfig.Account1.Password)
+	s.NoError(err)
+	s.Equal(sentTx.Hash(), hash)
+	s.Equal(pendingTx.Nonce(), sentTx.Nonce())
+	s.Equal(big.NewInt(150), sentTx.GasPrice())
+	s.Equal(&from, sentTx.To())
+	s.Equal(big.NewInt(0), sentTx.Value())
+
+	gasPrice = hexutil.Big(*big.NewInt(50))
+	_, err = txQueueManager.CancelTransaction(pendingTx.Hash(), TestConfig.Account1.Password)
+	s.NoError(err)
+	s.Equal(big.NewInt(110), sentTx.GasPrice())
+
+	mined = true
+	_, err = txQueueManager.CancelTransaction(pendingTx.Hash(), TestConfig.Account1.Password)
+	s.Equal(ErrTransactionNotPending, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	s.Len(events, 6)
+	s.Equal(ReplacedTransactionEvent{Hash: pendingTx.Hash().Hex(), Action: ReplaceSpeedUp, ErrorMessage: ErrGasPriceTooLow.Error()}, events[0])
+	s.Equal(ReplacedTransactionEvent{Hash: pendingTx.Hash().Hex(), NewHash: events[2].NewHash, Action: ReplaceSpeedUp}, events[2])
+	s.NotEmpty(events[2].NewHash)
+	s.Equal(ReplaceCancel, events[3].Action)
+	s.Equal(ErrTransactionNotPending.Error(), events[5].ErrorMessage)
+}
+
+func (s *TxQueueTestSuite) TestMonitorTransaction() {
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	s.NoError(err)
+	s.NoError(stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	s.NoError(err)
+
+	var (
+		mu       sync.Mutex
+		head     = hexutil.Uint64(10)
+		receipts = make(map[gethcommon.Hash]*ethclient.Receipt)
+	)
+	rpcClient.RegisterHandler("eth_getTransactionReceipt", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return receipts[args[0].(gethcommon.Hash)], nil
+	})
+	rpcClient.RegisterHandler("eth_blockNumber", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		head++
+		return head, nil
+	})
+	s.nodeManagerMock.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager.monitorInterval = 10 * time.Millisecond
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+
+	events := make(chan signal.Envelope, 10)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope struct {
+			Type  string
+			Event json.RawMessage
+		}
+		s.NoError(json.Unmarshal([]byte(jsonEvent), &envelope))
+		if envelope.Type == EventTransactionMined || envelope.Type == EventTransactionFailed {
+			events <- signal.Envelope{Type: envelope.Type, Event: envelope.Event}
+		}
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	newReceipt := func(block int64, status hexutil.Uint64) *ethclient.Receipt {
+		return &ethclient.Receipt{
+			BlockNumber: (*hexutil.Big)(big.NewInt(block)),
+			GasUsed:     (*hexutil.Big)(big.NewInt(21000)),
+			Status:      &status,
+		}
+	}
+	config := &params.TxMonitorConfig{Enabled: true, Confirmations: 3, Timeout: 1}
+	tx := txQueueManager.CreateTransaction(context.Background(), common.SendTxArgs{})
+
+	// mined transaction is reported once it's confirmed
+	minedHash := gethcommon.HexToHash("0x01")
+	mu.Lock()
+	receipts[minedHash] = newReceipt(12, ReceiptStatusSuccessful)
+	mu.Unlock()
+	txQueueManager.monitorTransaction(tx, minedHash, config)
+
+	envelope := <-events
+	s.Equal(EventTransactionMined, envelope.Type)
+	var mined MinedTransactionEvent
+	s.NoError(json.Unmarshal(envelope.Event.(json.RawMessage), &mined))
+	s.Equal(minedHash.Hex(), mined.Hash)
+	s.Equal(string(tx.ID), mined.ID)
+	s.Equal(uint64(12), mined.BlockNumber)
+	s.Equal(uint64(3), mined.Confirmations)
+	s.Equal(big.NewInt(21000), mined.GasUsed.ToInt())
+
+	// reverted transaction
+	revertedHash := gethcommon.HexToHash("0x02")
+	mu.Lock()
+	receipts[revertedHash] = newReceipt(12, ReceiptStatusFailed)
+	mu.Unlock()
+	txQueueManager.monitorTransaction(tx, revertedHash, config)
+
+	envelope = <-events
+	s.Equal(EventTransactionFailed, envelope.Type)
+	var failed ReturnSendTransactionEvent
+	s.NoError(json.Unmarshal(envelope.Event.(json.RawMessage), &failed))
+	s.Equal(revertedHash.Hex(), failed.Hash)
+	s.Equal(SendTransactionRevertedErrorCode, failed.ErrorCode)
+
+	// transaction, which is not mined within timeout
+	txQueueManager.monitorTransaction(tx, gethcommon.HexToHash("0x03"), config)
+
+	envelope = <-events
+	s.Equal(EventTransactionFailed, envelope.Type)
+	s.NoError(json.Unmarshal(envelope.Event.(json.RawMessage), &failed))
+	s.Equal(SendTransactionNotMinedErrorCode, failed.ErrorCode)
+
+	// nothing is monitored, if monitoring is disabled
+	txQueueManager.monitorTransaction(tx, minedHash, &params.TxMonitorConfig{})
+	select {
+	case envelope := <-events:
+		s.Fail("unexpected signal", envelope.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (s *TxQueueTestSuite) TestRelayTransaction() {
+	key, err := crypto.GenerateKey()
+	s.NoError(err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.ToAddress(TestConfig.Account2.Address)
+	hub := gethcommon.HexToAddress("0xd216153c06e857cd7f72665e0af1d7d82172f494")
+	relayedHash := gethcommon.HexToHash("0x0102")
+
+	stack, err := gethnode.New(&gethnode.Config{NoUSB: true, P2P: p2p.Config{NoDiscovery: true}})
+	s.NoError(err)
+	s.NoError(stack.Start())
+	defer stack.Stop() // nolint: errcheck
+	rpcClient, err := rpc.NewClient(stack, params.UpstreamRPCConfig{})
+	s.NoError(err)
+
+	rpcClient.RegisterHandler("eth_call", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		call := args[0].(map[string]interface{})
+		s.Equal(hub, *call["to"].(*gethcommon.Address))
+		s.Equal(append(append([]byte{}, getNonceSelector...), gethcommon.LeftPadBytes(from.Bytes(), 32)...), []byte(call["data"].(hexutil.Bytes)))
+		return hexutil.Bytes(gethcommon.LeftPadBytes([]byte{5}, 32)), nil
+	})
+	rpcClient.RegisterHandler("eth_gasPrice", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return hexutil.Big(*big.NewInt(20)), nil
+	})
+	rpcClient.RegisterHandler("eth_estimateGas", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return hexutil.Big(*big.NewInt(50000)), nil
+	})
+
+	var (
+		relayed    RelayRequest
+		relayError string
+	)
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		s.NoError(json.NewDecoder(r.Body).Decode(&relayed))
+		if relayError != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error": %q}`, relayError) // nolint: errcheck
+			return
+		}
+		fmt.Fprintf(w, `{"txHash": %q}`, relayedHash.Hex()) // nolint: errcheck
+	}))
+	defer relay.Close()
+
+	config, err := params.NewNodeConfig("/tmp", params.RopstenNetworkID, true)
+	s.NoError(err)
+	config.TxMonitorConfig.Enabled = false
+	config.RelayConfig = &params.RelayConfig{Enabled: true, URL: relay.URL, HubAddress: hub.Hex(), Fee: 10}
+	s.nodeManagerMock.EXPECT().NodeConfig().Return(config, nil).AnyTimes()
+	s.nodeManagerMock.EXPECT().RPCClient().Return(rpcClient).AnyTimes()
+	s.accountManagerMock.EXPECT().SelectedAccount().Return(&common.SelectedExtKey{
+		Address:    from,
+		AccountKey: &keystore.Key{PrivateKey: key},
+	}, nil).AnyTimes()
+	s.accountManagerMock.EXPECT().VerifyAccountPassword(config.KeyStoreDir, from.Hex(), TestConfig.Account1.Password).Return(nil, nil).AnyTimes()
+
+	txQueueManager := NewManager(s.nodeManagerMock, s.accountManagerMock)
+	txQueueManager.Start()
+	defer txQueueManager.Stop()
+	txQueueManager.SetTransactionQueueHandler(func(queuedTx *common.QueuedTx) {})
+	txQueueManager.SetTransactionReturnHandler(func(queuedTx *common.QueuedTx, err error) {})
+
+	complete := func(args common.SendTxArgs) (gethcommon.Hash, error) {
+		tx := txQueueManager.CreateTransaction(context.Background(), args)
+		s.NoError(txQueueManager.QueueTransaction(tx))
+		return txQueueManager.CompleteTransaction(tx.ID, TestConfig.Account1.Password)
+	}
+
+	data := hexutil.Bytes{0x01, 0x02}
+	hash, err := complete(common.SendTxArgs{From: from, To: to, Data: data})
+	s.NoError(err)
+	s.Equal(relayedHash, hash)
+	s.Equal(from, relayed.From)
+	s.Equal(*to, relayed.To)
+	s.Equal(data, relayed.EncodedFunction)
+	s.Equal(int64(10), relayed.RelayFee.ToInt().Int64())
+	s.Equal(int64(20), relayed.GasPrice.ToInt().Int64())
+	s.Equal(int64(50000), relayed.GasLimit.ToInt().Int64())
+	s.Equal(int64(5), relayed.SenderNonce.ToInt().Int64())
+	s.Equal(hub, relayed.RelayHubAddress)
+
+	// call is signed by sender, the way personal_sign signs messages
+	signature := append([]byte{}, relayed.Signature...)
+	signature[64] -= 27
+	pubKey, err := crypto.SigToPub(account.MessageHash(relayed.Hash()), signature)
+	s.NoError(err)
+	s.Equal(from, crypto.PubkeyToAddress(*pubKey))
+
+	// ether can not be relayed, nor contracts created
+	value := hexutil.Big(*big.NewInt(1))
+	_, err = complete(common.SendTxArgs{From: from, To: to, Value: &value})
+	s.Equal(ErrRelayValue, err)
+	_, err = complete(common.SendTxArgs{From: from, Data: data})
+	s.Equal(ErrRelayContractCreation, err)
+
+	// calls refused by relay
+	relayError = "recipient does not accept relayed calls"
+	_, err = complete(common.SendTxArgs{From: from, To: to, Data: data})
+	s.EqualError(err, "gas relay refused call: "+relayError)
+}
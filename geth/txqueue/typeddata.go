@@ -0,0 +1,90 @@
+package txqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/common"
+	"github.com/status-im/status-go/geth/log"
+)
+
+// errors
+var (
+	ErrInvalidSignTypedDataParams = errors.New("address and typed data are expected")
+)
+
+// CreateTypedDataRequest returns request to sign EIP-712 typed data (JSON encoded) by from account,
+// which is queued and completed (or discarded) the same way as transactions.
+func (m *Manager) CreateTypedDataRequest(ctx context.Context, from gethcommon.Address, typedData string) *common.QueuedTx {
+	tx := m.CreateTransaction(ctx, common.SendTxArgs{From: from})
+	tx.TypedData = typedData
+	return tx
+}
+
+// completeTypedData signs typed data of request with selected account, once password is verified.
+func (m *Manager) completeTypedData(queuedTx *common.QueuedTx, keyStoreDir, password string, passwordVerified bool) error {
+	log.Info("complete typed data signing", "id", queuedTx.ID)
+
+	from := queuedTx.Args.From.Hex()
+	if !passwordVerified {
+		if _, err := m.accountManager.VerifyAccountPassword(keyStoreDir, from, password); err != nil {
+			log.Warn("failed to verify account", "account", log.Address(from), "error", err.Error())
+			return err
+		}
+	}
+
+	signature, err := m.accountManager.SignTypedData(from, queuedTx.TypedData)
+	if err != nil {
+		return err
+	}
+	queuedTx.Signature = signature
+
+	return nil
+}
+
+// SignTypedDataRPCHandler is a handler for eth_signTypedData and eth_signTypedData_v4 methods.
+// It accepts address of signer and typed data, either JSON encoded or as an object,
+// and returns signature, once request is completed.
+func (m *Manager) SignTypedDataRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
+	log.Info("SignTypedDataRPCHandler called")
+
+	if len(args) < 2 {
+		return nil, ErrInvalidSignTypedDataParams
+	}
+	address, ok := args[0].(string)
+	if !ok || !gethcommon.IsHexAddress(address) {
+		return nil, ErrInvalidSignTypedDataParams
+	}
+	typedData, ok := args[1].(string)
+	if !ok {
+		data, err := json.Marshal(args[1])
+		if err != nil {
+			return nil, err
+		}
+		typedData = string(data)
+	}
+
+	// malformed typed data is rejected before it is shown to user
+	typed, err := account.ParseTypedData([]byte(typedData))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := typed.Hash(); err != nil {
+		return nil, err
+	}
+
+	tx := m.CreateTypedDataRequest(ctx, gethcommon.HexToAddress(address), typedData)
+
+	if err := m.QueueTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	if err := m.WaitForTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx.Signature, nil
+}